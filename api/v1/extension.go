@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
 func (a *AgentStatus) FromModel(m models.AgentStatus) {
@@ -17,23 +18,33 @@ func (a *AgentStatus) FromModel(m models.AgentStatus) {
 	if m.Console.Error != nil {
 		err := m.Console.Error.Error()
 		a.Error = &err
+		if errors.IsSourceNotRegisteredError(m.Console.Error) {
+			code := AgentStatusErrorCodeSourceNotRegistered
+			a.ErrorCode = &code
+		}
 	}
 	a.Mode = AgentStatusMode(m.Console.Target)
+	if !m.Console.LastContactAt.IsZero() {
+		lastContactAt := m.Console.LastContactAt
+		a.LastContactAt = &lastContactAt
+	}
 }
 
 // NewVirtualMachineFromSummary converts a models.VirtualMachineSummary to an API VirtualMachine.
 func NewVirtualMachineFromSummary(vm models.VirtualMachineSummary) VirtualMachine {
 	result := VirtualMachine{
-		Id:           vm.ID,
-		Name:         vm.Name,
-		Cluster:      vm.Cluster,
-		Datacenter:   vm.Datacenter,
-		DiskSize:     vm.DiskSize,
-		Memory:       int64(vm.Memory),
-		VCenterState: vm.PowerState,
-		IssueCount:   vm.IssueCount,
-		Migratable:   &vm.IsMigratable,
-		Template:     &vm.IsTemplate,
+		Id:            vm.ID,
+		Name:          vm.Name,
+		Cluster:       vm.Cluster,
+		Datacenter:    vm.Datacenter,
+		DiskSize:      vm.DiskSize,
+		Memory:        int64(vm.Memory),
+		VCenterState:  vm.PowerState,
+		IssueCount:    vm.IssueCount,
+		CriticalCount: vm.CriticalCount,
+		WarningCount:  vm.WarningCount,
+		Migratable:    &vm.IsMigratable,
+		Template:      &vm.IsTemplate,
 	}
 	if len(vm.Tags) > 0 {
 		result.Tags = &vm.Tags
@@ -46,6 +57,12 @@ func NewVirtualMachineFromSummary(vm models.VirtualMachineSummary) VirtualMachin
 	if vm.InspectionConcernCount > 0 {
 		result.InspectionConcernCount = &vm.InspectionConcernCount
 	}
+	if vm.SnapshotCount > 0 {
+		result.SnapshotCount = &vm.SnapshotCount
+	}
+	if len(vm.DiskTypes) > 0 {
+		result.DiskTypes = &vm.DiskTypes
+	}
 
 	result.UtilizationCpuP95 = vm.UtilizationCpuP95
 	result.UtilizationMemP95 = vm.UtilizationMemP95
@@ -80,6 +97,27 @@ func NewCollectorStatus(status models.CollectorStatus) CollectorStatus {
 		c.Error = &e
 	}
 
+	if len(status.Phases) > 0 {
+		phases := make([]CollectorPhase, 0, len(status.Phases))
+		for _, p := range status.Phases {
+			phases = append(phases, CollectorPhase{
+				Phase:      CollectorPhasePhase(p.State),
+				DurationMs: p.Duration.Milliseconds(),
+			})
+		}
+		c.Phases = &phases
+	}
+
+	if len(status.Warnings) > 0 {
+		warnings := status.Warnings
+		c.Warnings = &warnings
+	}
+
+	if !status.CollectedAt.IsZero() {
+		collectedAt := status.CollectedAt
+		c.CollectedAt = &collectedAt
+	}
+
 	return c
 }
 
@@ -92,6 +130,130 @@ func NewCollectorStatusWithError(status models.CollectorStatus, err error) Colle
 	return c
 }
 
+func NewCollectionHistoryFromModel(events []models.CollectionHistoryEvent) CollectionHistory {
+	h := CollectionHistory{Events: make([]CollectionHistoryEvent, 0, len(events))}
+	for _, e := range events {
+		event := CollectionHistoryEvent{
+			Time:  e.Time,
+			State: CollectionHistoryEventState(e.State),
+		}
+		if e.Error != "" {
+			event.Error = &e.Error
+		}
+		h.Events = append(h.Events, event)
+	}
+	return h
+}
+
+func NewInventoryHistoryFromModel(history []time.Time) InventoryHistory {
+	h := InventoryHistory{Snapshots: make([]InventorySnapshot, 0, len(history))}
+	for _, t := range history {
+		h.Snapshots = append(h.Snapshots, InventorySnapshot{TakenAt: t})
+	}
+	return h
+}
+
+func NewConcernCatalogFromModel(catalog []models.ConcernDefinition) ConcernCatalog {
+	c := ConcernCatalog{Concerns: make([]ConcernDefinition, 0, len(catalog))}
+	for _, d := range catalog {
+		c.Concerns = append(c.Concerns, ConcernDefinition{
+			Id:         d.ID,
+			Category:   d.Category,
+			Label:      d.Label,
+			Assessment: d.Assessment,
+		})
+	}
+	return c
+}
+
+func NewVcenterInfoFromModel(info models.VCenterInfo) VcenterInfo {
+	return VcenterInfo{
+		Product:    info.Product,
+		Version:    info.Version,
+		Build:      info.Build,
+		ApiVersion: info.APIVersion,
+	}
+}
+
+func NewVmViewFromModel(view models.VMView) VmView {
+	v := VmView{
+		Name:      view.Name,
+		Sort:      view.Sort,
+		CreatedAt: view.CreatedAt,
+	}
+	if v.Sort == nil {
+		v.Sort = []string{}
+	}
+	if view.Expression != "" {
+		v.ByExpression = &view.Expression
+	}
+	if view.PageSize > 0 {
+		v.PageSize = &view.PageSize
+	}
+	return v
+}
+
+func NewInventoryDiffFromModel(diff models.InventoryDiff) InventoryDiff {
+	changed := make([]VmDelta, 0, len(diff.Changed))
+	for _, d := range diff.Changed {
+		changed = append(changed, VmDelta{
+			Id:       d.ID,
+			Name:     d.Name,
+			Cpus:     newFieldDeltaFromModel(d.CPUs),
+			MemoryMB: newFieldDeltaFromModel(d.MemoryMB),
+			DiskMB:   newFieldDeltaFromModel(d.DiskMB),
+		})
+	}
+
+	return InventoryDiff{
+		PreviousCollectedAt: diff.PreviousCollectedAt,
+		CurrentCollectedAt:  diff.CurrentCollectedAt,
+		Added:               diff.Added,
+		Removed:             diff.Removed,
+		Changed:             changed,
+	}
+}
+
+func NewInventoryMetricsFromModel(m models.InventoryMetrics) InventoryMetrics {
+	return InventoryMetrics{
+		UpdatedAt:            m.UpdatedAt,
+		TotalVms:             m.TotalVMs,
+		PoweredOnVms:         m.PoweredOnVMs,
+		TotalVcpus:           m.TotalVCPUs,
+		TotalRamGB:           m.TotalRAMGB,
+		TotalDiskTB:          m.TotalDiskTB,
+		TotalHosts:           m.TotalHosts,
+		TotalDatacenters:     m.TotalDatacenters,
+		CpuOverCommitment:    m.CPUOverCommitment,
+		MemoryOverCommitment: m.MemoryOverCommitment,
+	}
+}
+
+func NewClusterListFromModel(clusters []models.ClusterMetrics) ClusterList {
+	list := ClusterList{Clusters: make([]ClusterMetrics, 0, len(clusters))}
+	for _, c := range clusters {
+		list.Clusters = append(list.Clusters, ClusterMetrics{
+			Id:                   c.ID,
+			TotalVms:             c.TotalVMs,
+			PoweredOnVms:         c.PoweredOnVMs,
+			TotalVcpus:           c.TotalVCPUs,
+			TotalRamGB:           c.TotalRAMGB,
+			TotalDiskTB:          c.TotalDiskTB,
+			TotalHosts:           c.TotalHosts,
+			CpuOverCommitment:    c.CPUOverCommitment,
+			MemoryOverCommitment: c.MemoryOverCommitment,
+		})
+	}
+	return list
+}
+
+func newFieldDeltaFromModel(d *models.FieldDelta) *FieldDelta {
+	if d == nil {
+		return nil
+	}
+	return &FieldDelta{Previous: d.Previous, Current: d.Current}
+}
+
 func NewVirtualMachineDetailFromModel(vm models.VM) VirtualMachineDetail {
 	details := VirtualMachineDetail{
 		Id:              vm.ID,
@@ -165,17 +327,24 @@ func NewVirtualMachineDetailFromModel(vm models.VM) VirtualMachineDetail {
 		// Convert MiB to bytes (parser returns capacity in MiB)
 		capacityBytes := d.Capacity * 1024 * 1024
 		disk := VMDisk{
-			File:     &d.File,
-			Capacity: &capacityBytes,
-			Shared:   &d.Shared,
-			Rdm:      &d.RDM,
-			Bus:      &d.Bus,
-			Mode:     &d.Mode,
+			File:            &d.File,
+			Capacity:        &capacityBytes,
+			Shared:          &d.Shared,
+			Rdm:             &d.RDM,
+			Bus:             &d.Bus,
+			Mode:            &d.Mode,
+			ThinProvisioned: &d.Thin,
 		}
 		if d.Key != 0 {
 			key := d.Key
 			disk.Key = &key
 		}
+		if d.Datastore != "" {
+			disk.Datastore = &d.Datastore
+		}
+		if d.DiskType != "" {
+			disk.DiskType = &d.DiskType
+		}
 		details.Disks = append(details.Disks, disk)
 	}
 
@@ -205,6 +374,23 @@ func NewVirtualMachineDetailFromModel(vm models.VM) VirtualMachineDetail {
 		details.Issues = &issues
 	}
 
+	if len(vm.Snapshots) > 0 {
+		snapshots := make([]VMSnapshot, 0, len(vm.Snapshots))
+		for _, snap := range vm.Snapshots {
+			s := VMSnapshot{Name: snap.Name}
+			if !snap.CreatedAt.IsZero() {
+				createdAt := snap.CreatedAt
+				s.CreatedAt = &createdAt
+			}
+			snapshots = append(snapshots, s)
+		}
+		details.Snapshots = &snapshots
+	}
+
+	if len(vm.DiskTypes) > 0 {
+		details.DiskTypes = &vm.DiskTypes
+	}
+
 	return details
 }
 
@@ -218,6 +404,8 @@ func NewInspectorStatus(status models.InspectorStatus) *InspectorStatus {
 		c.State = InspectorStatusStateInitiating
 	case models.InspectorStateRunning:
 		c.State = InspectorStatusStateRunning
+	case models.InspectorStatePaused:
+		c.State = InspectorStatusStatePaused
 	case models.InspectorStateCanceled:
 		c.State = InspectorStatusStateCanceled
 	case models.InspectorStateCompleted:
@@ -233,6 +421,12 @@ func NewInspectorStatus(status models.InspectorStatus) *InspectorStatus {
 		c.Error = &e
 	}
 
+	if status.AverageVmDuration > 0 {
+		d := status.AverageVmDuration.Round(time.Millisecond).String()
+		c.AverageVmDuration = &d
+	}
+	c.EstimatedCompletion = status.EstimatedCompletion
+
 	return &c
 }
 
@@ -280,6 +474,18 @@ func NewInspectionStatus(status models.InspectionStatus) VmInspectionStatus {
 	return c
 }
 
+// NewInspectionCancelResultsFromModel converts per-VM cancel outcomes to the API form.
+func NewInspectionCancelResultsFromModel(results []models.InspectionCancelResult) InspectionCancelResults {
+	out := InspectionCancelResults{Results: make([]InspectionCancelResult, 0, len(results))}
+	for _, r := range results {
+		out.Results = append(out.Results, InspectionCancelResult{
+			VmId:    r.VMID,
+			Outcome: InspectionCancelResultOutcome(r.Outcome),
+		})
+	}
+	return out
+}
+
 // NewGroupFromModel converts a models.Group to an API Group.
 func NewGroupFromModel(g models.Group) Group {
 	id := fmt.Sprintf("%d", g.ID)
@@ -387,3 +593,13 @@ func NewVmUtilizationDetailsFromModel(d models.VmUtilizationDetails) VmUtilizati
 		Confidence: d.Confidence,
 	}
 }
+
+func NewStorageUsageFromModel(u models.StorageUsage) StorageUsage {
+	return StorageUsage{
+		Path:          u.Path,
+		TotalBytes:    int64(u.TotalBytes),
+		FreeBytes:     int64(u.FreeBytes),
+		DatabaseBytes: u.DatabaseBytes,
+		VddkBytes:     u.VddkBytes,
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
 func TestExtension(t *testing.T) {
@@ -53,6 +54,26 @@ var _ = Describe("AgentStatus.FromModel", func() {
 
 		Expect(status.Error).NotTo(BeNil())
 		Expect(*status.Error).To(Equal("connection failed"))
+		Expect(status.ErrorCode).To(BeNil())
+	})
+
+	// Given a console status stopped because the source is not registered
+	// When we convert it to API status
+	// Then it should set the SOURCE_NOT_REGISTERED error code
+	It("should set the error code for an unregistered source", func() {
+		model := models.AgentStatus{
+			Console: models.ConsoleStatus{
+				Current: models.ConsoleStatusDisconnected,
+				Target:  models.ConsoleStatusConnected,
+				Error:   srvErrors.NewSourceNotRegisteredError("source-1", srvErrors.NewConsoleClientError(404, "404 Not Found")),
+			},
+		}
+
+		var status v1.AgentStatus
+		status.FromModel(model)
+
+		Expect(status.ErrorCode).To(HaveValue(Equal(v1.AgentStatusErrorCodeSourceNotRegistered)))
+		Expect(*status.Error).To(ContainSubstring("source source-1 is not registered"))
 	})
 })
 
@@ -62,14 +83,16 @@ var _ = Describe("NewVirtualMachineFromSummary", func() {
 	// Then it should map all fields correctly
 	It("should convert VirtualMachineSummary to VirtualMachine", func() {
 		summary := models.VirtualMachineSummary{
-			ID:         "vm-123",
-			Name:       "Test VM",
-			PowerState: "poweredOn",
-			Cluster:    "cluster-1",
-			Datacenter: "DC1",
-			Memory:     4096,
-			DiskSize:   102400,
-			IssueCount: 3,
+			ID:            "vm-123",
+			Name:          "Test VM",
+			PowerState:    "poweredOn",
+			Cluster:       "cluster-1",
+			Datacenter:    "DC1",
+			Memory:        4096,
+			DiskSize:      102400,
+			IssueCount:    3,
+			CriticalCount: 1,
+			WarningCount:  2,
 		}
 
 		vm := v1.NewVirtualMachineFromSummary(summary)
@@ -82,6 +105,8 @@ var _ = Describe("NewVirtualMachineFromSummary", func() {
 		Expect(vm.Memory).To(Equal(int64(4096)))
 		Expect(vm.DiskSize).To(Equal(int64(102400)))
 		Expect(vm.IssueCount).To(Equal(3))
+		Expect(vm.CriticalCount).To(Equal(1))
+		Expect(vm.WarningCount).To(Equal(2))
 	})
 
 	It("should not return inspection when not started", func() {
@@ -138,6 +163,38 @@ var _ = Describe("NewVirtualMachineFromSummary", func() {
 		Expect(vm.InspectionConcernCount).To(BeNil())
 	})
 
+	Context("SnapshotCount", func() {
+		It("should include the snapshot count when the VM has snapshots", func() {
+			vm := v1.NewVirtualMachineFromSummary(models.VirtualMachineSummary{ID: "vm-snap", SnapshotCount: 2})
+
+			Expect(vm.SnapshotCount).NotTo(BeNil())
+			Expect(*vm.SnapshotCount).To(Equal(2))
+		})
+
+		It("should omit the snapshot count when the VM has none", func() {
+			vm := v1.NewVirtualMachineFromSummary(models.VirtualMachineSummary{ID: "vm-no-snap"})
+
+			Expect(vm.SnapshotCount).To(BeNil())
+		})
+	})
+
+	Context("DiskTypes", func() {
+		It("should include the disk type breakdown when present", func() {
+			vm := v1.NewVirtualMachineFromSummary(models.VirtualMachineSummary{
+				ID:        "vm-disks",
+				DiskTypes: map[string]float64{"VMFS": 0.98, "NFS": 1.5},
+			})
+
+			Expect(vm.DiskTypes).To(HaveValue(Equal(map[string]float64{"VMFS": 0.98, "NFS": 1.5})))
+		})
+
+		It("should omit the breakdown when the VM has none", func() {
+			vm := v1.NewVirtualMachineFromSummary(models.VirtualMachineSummary{ID: "vm-no-disks"})
+
+			Expect(vm.DiskTypes).To(BeNil())
+		})
+	})
+
 	Context("Tags", func() {
 		It("should include tags when present", func() {
 			summary := models.VirtualMachineSummary{
@@ -289,6 +346,59 @@ var _ = Describe("NewCollectorStatus", func() {
 			Expect(string(status.Status)).To(Equal("unknown state"))
 		})
 	})
+
+	Context("phases", func() {
+		// Given a status with phase timings
+		// When we convert it to API status
+		// Then each phase should carry its state and duration in milliseconds
+		It("should map phase timings", func() {
+			status := v1.NewCollectorStatus(models.CollectorStatus{
+				State: models.CollectorStateCollected,
+				Phases: []models.CollectorPhase{
+					{State: models.CollectorStateConnecting, Duration: 412 * time.Millisecond},
+					{State: models.CollectorStateCollecting, Duration: 95*time.Second + 310*time.Millisecond},
+				},
+			})
+			Expect(status.Phases).NotTo(BeNil())
+			Expect(*status.Phases).To(Equal([]v1.CollectorPhase{
+				{Phase: v1.CollectorPhasePhaseConnecting, DurationMs: 412},
+				{Phase: v1.CollectorPhasePhaseCollecting, DurationMs: 95310},
+			}))
+		})
+
+		// Given a status without phase timings
+		// When we convert it to API status
+		// Then phases should be omitted
+		It("should omit phases when there are none", func() {
+			status := v1.NewCollectorStatus(models.CollectorStatus{State: models.CollectorStateReady})
+			Expect(status.Phases).To(BeNil())
+		})
+	})
+
+	Context("warnings", func() {
+		// Given a collected status with warnings
+		// When we convert it to API status
+		// Then the warnings should be kept in order
+		It("should map warnings", func() {
+			status := v1.NewCollectorStatus(models.CollectorStatus{
+				State:    models.CollectorStateCollected,
+				Warnings: []string{"VM snapshots were not collected: timeout", "VM vm-1 (vm-1) could not be validated: boom"},
+			})
+			Expect(status.Warnings).NotTo(BeNil())
+			Expect(*status.Warnings).To(Equal([]string{
+				"VM snapshots were not collected: timeout",
+				"VM vm-1 (vm-1) could not be validated: boom",
+			}))
+		})
+
+		// Given a collected status without warnings
+		// When we convert it to API status
+		// Then warnings should be omitted
+		It("should omit warnings when there are none", func() {
+			status := v1.NewCollectorStatus(models.CollectorStatus{State: models.CollectorStateCollected})
+			Expect(status.Warnings).To(BeNil())
+		})
+	})
 })
 
 var _ = Describe("NewCollectorStatusWithError", func() {
@@ -393,6 +503,11 @@ var _ = Describe("NewInspectorStatus", func() {
 			Expect(status.State).To(Equal(v1.InspectorStatusStateRunning))
 		})
 
+		It("should map paused state", func() {
+			status := v1.NewInspectorStatus(models.InspectorStatus{State: models.InspectorStatePaused})
+			Expect(status.State).To(Equal(v1.InspectorStatusStatePaused))
+		})
+
 		It("should map canceled state", func() {
 			status := v1.NewInspectorStatus(models.InspectorStatus{State: models.InspectorStateCanceled})
 			Expect(status.State).To(Equal(v1.InspectorStatusStateCanceled))
@@ -741,6 +856,50 @@ var _ = Describe("NewVirtualMachineDetailFromModel", func() {
 			Expect(details.Disks).To(HaveLen(1))
 			Expect(details.Disks[0].Key).To(BeNil())
 		})
+
+		// Given a VM with a thin and a thick disk
+		// When we convert it to VMDetails
+		// Then it should include provisioning, datastore, and disk type per disk
+		It("should include thin provisioning, datastore, and disk type", func() {
+			vm := models.VM{
+				ID:              "vm-disk-types",
+				Name:            "Disk Types VM",
+				PowerState:      "poweredOn",
+				ConnectionState: "connected",
+				Disks: []models.Disk{
+					{File: "[ds-vmfs] vm/disk1.vmdk", Capacity: 100, Thin: true, Datastore: "ds-vmfs", DiskType: "VMFS"},
+					{File: "[ds-nfs] vm/disk2.vmdk", Capacity: 100, Thin: false, Datastore: "ds-nfs", DiskType: "NFS"},
+				},
+			}
+
+			details := v1.NewVirtualMachineDetailFromModel(vm)
+
+			Expect(details.Disks).To(HaveLen(2))
+			Expect(*details.Disks[0].ThinProvisioned).To(BeTrue())
+			Expect(*details.Disks[0].Datastore).To(Equal("ds-vmfs"))
+			Expect(*details.Disks[0].DiskType).To(Equal("VMFS"))
+			Expect(*details.Disks[1].ThinProvisioned).To(BeFalse())
+			Expect(*details.Disks[1].Datastore).To(Equal("ds-nfs"))
+			Expect(*details.Disks[1].DiskType).To(Equal("NFS"))
+		})
+
+		// Given a VM with a disk whose datastore is unknown
+		// When we convert it to VMDetails
+		// Then it should omit datastore and disk type
+		It("should omit datastore and disk type when empty", func() {
+			vm := models.VM{
+				ID:              "vm-disk-no-ds",
+				Name:            "No Datastore VM",
+				PowerState:      "poweredOn",
+				ConnectionState: "connected",
+				Disks:           []models.Disk{{File: "disk.vmdk", Capacity: 50}},
+			}
+
+			details := v1.NewVirtualMachineDetailFromModel(vm)
+
+			Expect(details.Disks[0].Datastore).To(BeNil())
+			Expect(details.Disks[0].DiskType).To(BeNil())
+		})
 	})
 
 	Context("NICs", func() {
@@ -820,6 +979,53 @@ var _ = Describe("NewVirtualMachineDetailFromModel", func() {
 		})
 	})
 
+	Context("snapshots", func() {
+		// Given a VM with snapshots
+		// When we convert it to VMDetails
+		// Then it should include their names and creation times in order
+		It("should include snapshots when present", func() {
+			created := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+			vm := models.VM{
+				ID: "vm-snap",
+				Snapshots: []models.VirtualMachineSnapshot{
+					{VMID: "vm-snap", Name: "before-upgrade", CreatedAt: created},
+					{VMID: "vm-snap", Name: "no-date"},
+				},
+			}
+
+			details := v1.NewVirtualMachineDetailFromModel(vm)
+
+			Expect(details.Snapshots).NotTo(BeNil())
+			Expect(*details.Snapshots).To(HaveLen(2))
+			Expect((*details.Snapshots)[0].Name).To(Equal("before-upgrade"))
+			Expect((*details.Snapshots)[0].CreatedAt).To(HaveValue(Equal(created)))
+			Expect((*details.Snapshots)[1].CreatedAt).To(BeNil())
+		})
+
+		It("should not include snapshots when the VM has none", func() {
+			details := v1.NewVirtualMachineDetailFromModel(models.VM{ID: "vm-no-snap"})
+
+			Expect(details.Snapshots).To(BeNil())
+		})
+	})
+
+	Context("disk types", func() {
+		It("should include the disk type breakdown when present", func() {
+			details := v1.NewVirtualMachineDetailFromModel(models.VM{
+				ID:        "vm-disks",
+				DiskTypes: map[string]float64{"vsan": 40},
+			})
+
+			Expect(details.DiskTypes).To(HaveValue(Equal(map[string]float64{"vsan": 40})))
+		})
+
+		It("should not include the breakdown when the VM has none", func() {
+			details := v1.NewVirtualMachineDetailFromModel(models.VM{ID: "vm-no-disks"})
+
+			Expect(details.DiskTypes).To(BeNil())
+		})
+	})
+
 	Context("inspection concerns", func() {
 		It("should map inspection concerns when present", func() {
 			vm := models.VM{
@@ -19,15 +19,69 @@ type ServerInterface interface {
 	// Change agent mode
 	// (POST /agent)
 	SetAgentMode(c *gin.Context)
+	// Update console settings at runtime
+	// (PATCH /agent/config)
+	UpdateAgentConfig(c *gin.Context)
+	// Get maintenance mode
+	// (GET /agent/maintenance)
+	GetMaintenance(c *gin.Context)
+	// Enable or disable maintenance mode
+	// (POST /agent/maintenance)
+	SetMaintenance(c *gin.Context)
+	// Force an inventory push to the console
+	// (POST /agent/push-inventory)
+	PushInventory(c *gin.Context)
+	// List the optional features this agent supports
+	// (GET /capabilities)
+	GetCapabilities(c *gin.Context)
+	// List the clusters of the inventory with their totals
+	// (GET /clusters)
+	GetClusters(c *gin.Context, params GetClustersParams)
 	// Stop collection
 	// (DELETE /collector)
 	StopCollector(c *gin.Context)
 	// Get collector status
 	// (GET /collector)
-	GetCollectorStatus(c *gin.Context)
+	GetCollectorStatus(c *gin.Context, params GetCollectorStatusParams)
 	// Start inventory collection
 	// (POST /collector)
 	StartCollector(c *gin.Context)
+	// Get collection history
+	// (GET /collector/history)
+	GetCollectorHistory(c *gin.Context)
+	// Get recent collection logs
+	// (GET /collector/logs)
+	GetCollectorLogs(c *gin.Context, params GetCollectorLogsParams)
+	// Reload OPA policies
+	// (POST /collector/policies/reload)
+	ReloadPolicies(c *gin.Context)
+	// Test vCenter connectivity
+	// (POST /collector/test)
+	TestCollectorConnection(c *gin.Context)
+	// List the concern definitions
+	// (GET /concerns/catalog)
+	GetConcernCatalog(c *gin.Context)
+	// Get effective agent configuration
+	// (GET /config)
+	GetConfig(c *gin.Context)
+	// Compact the agent database
+	// (POST /debug/compact)
+	CompactStorage(c *gin.Context)
+	// Download the agent database file
+	// (GET /debug/db)
+	DownloadDatabase(c *gin.Context)
+	// Change the log level
+	// (POST /debug/loglevel)
+	SetLogLevel(c *gin.Context)
+	// Get data folder disk usage
+	// (GET /debug/storage)
+	GetStorageUsage(c *gin.Context)
+	// Stream status changes as Server-Sent Events
+	// (GET /events)
+	StreamEvents(c *gin.Context)
+	// Download the inventory, VM list and agent config as one archive
+	// (GET /export)
+	ExportInventory(c *gin.Context)
 	// Cancel benchmark
 	// (DELETE /forecaster)
 	StopForecaster(c *gin.Context)
@@ -75,7 +129,7 @@ type ServerInterface interface {
 	UpdateGroup(c *gin.Context, id string)
 	// Stop inspector entirely
 	// (DELETE /inspector)
-	StopInspection(c *gin.Context)
+	StopInspection(c *gin.Context, params StopInspectionParams)
 	// Get inspector status
 	// (GET /inspector)
 	GetInspectorStatus(c *gin.Context, params GetInspectorStatusParams)
@@ -94,6 +148,21 @@ type ServerInterface interface {
 	// Get collected inventory
 	// (GET /inventory)
 	GetInventory(c *gin.Context, params GetInventoryParams)
+	// Compare the last two collections
+	// (GET /inventory/diff)
+	GetInventoryDiff(c *gin.Context)
+	// Download the inventory split per cluster
+	// (GET /inventory/export)
+	ExportInventoryByGroup(c *gin.Context, params ExportInventoryByGroupParams)
+	// List the kept inventories
+	// (GET /inventory/history)
+	GetInventoryHistory(c *gin.Context)
+	// Get the top-level inventory totals
+	// (GET /inventory/metrics)
+	GetInventoryMetrics(c *gin.Context)
+	// Recompute VM concerns
+	// (POST /inventory/revalidate)
+	RevalidateInventory(c *gin.Context)
 	// List all rightsizing reports
 	// (GET /rightsizing)
 	ListRightsizingReports(c *gin.Context)
@@ -109,6 +178,33 @@ type ServerInterface interface {
 	// Get list of VMs with filtering and pagination
 	// (GET /vms)
 	GetVMs(c *gin.Context, params GetVMsParams)
+	// Get details about several vms
+	// (POST /vms/batch)
+	BatchGetVMs(c *gin.Context)
+	// Count VMs per value of a field
+	// (GET /vms/group-by)
+	GetVMsGroupBy(c *gin.Context, params GetVMsGroupByParams)
+	// Cancel the inspection of several VirtualMachines
+	// (POST /vms/inspector/cancel)
+	CancelVmsInspection(c *gin.Context)
+	// Pause the inspection
+	// (POST /vms/inspector/pause)
+	PauseInspection(c *gin.Context)
+	// Move pending VirtualMachines to the front of the inspection queue
+	// (PATCH /vms/inspector/priority)
+	PrioritizeInspection(c *gin.Context)
+	// Resume a paused inspection
+	// (POST /vms/inspector/resume)
+	ResumeInspection(c *gin.Context)
+	// Count VMs per guest operating system
+	// (GET /vms/os-distribution)
+	GetVMsOsDistribution(c *gin.Context, params GetVMsOsDistributionParams)
+	// List saved VM list views
+	// (GET /vms/views)
+	ListVMViews(c *gin.Context)
+	// Save a named filter, sort and page size for GET /vms
+	// (POST /vms/views)
+	CreateVMView(c *gin.Context)
 	// Get details about a vm
 	// (GET /vms/{id})
 	GetVM(c *gin.Context, id string)
@@ -155,6 +251,105 @@ func (siw *ServerInterfaceWrapper) SetAgentMode(c *gin.Context) {
 	siw.Handler.SetAgentMode(c)
 }
 
+// UpdateAgentConfig operation middleware
+func (siw *ServerInterfaceWrapper) UpdateAgentConfig(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateAgentConfig(c)
+}
+
+// GetMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) GetMaintenance(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMaintenance(c)
+}
+
+// SetMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) SetMaintenance(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SetMaintenance(c)
+}
+
+// PushInventory operation middleware
+func (siw *ServerInterfaceWrapper) PushInventory(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PushInventory(c)
+}
+
+// GetCapabilities operation middleware
+func (siw *ServerInterfaceWrapper) GetCapabilities(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCapabilities(c)
+}
+
+// GetClusters operation middleware
+func (siw *ServerInterfaceWrapper) GetClusters(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetClustersParams
+
+	// ------------- Optional query parameter "minCpuOvercommit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minCpuOvercommit", c.Request.URL.Query(), &params.MinCpuOvercommit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter minCpuOvercommit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "minMemoryOvercommit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minMemoryOvercommit", c.Request.URL.Query(), &params.MinMemoryOvercommit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter minMemoryOvercommit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetClusters(c, params)
+}
+
 // StopCollector operation middleware
 func (siw *ServerInterfaceWrapper) StopCollector(c *gin.Context) {
 
@@ -171,6 +366,27 @@ func (siw *ServerInterfaceWrapper) StopCollector(c *gin.Context) {
 // GetCollectorStatus operation middleware
 func (siw *ServerInterfaceWrapper) GetCollectorStatus(c *gin.Context) {
 
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCollectorStatusParams
+
+	// ------------- Optional query parameter "wait" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "wait", c.Request.URL.Query(), &params.Wait)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter wait: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", c.Request.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter since: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -178,7 +394,7 @@ func (siw *ServerInterfaceWrapper) GetCollectorStatus(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetCollectorStatus(c)
+	siw.Handler.GetCollectorStatus(c, params)
 }
 
 // StartCollector operation middleware
@@ -194,6 +410,175 @@ func (siw *ServerInterfaceWrapper) StartCollector(c *gin.Context) {
 	siw.Handler.StartCollector(c)
 }
 
+// GetCollectorHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetCollectorHistory(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCollectorHistory(c)
+}
+
+// GetCollectorLogs operation middleware
+func (siw *ServerInterfaceWrapper) GetCollectorLogs(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCollectorLogsParams
+
+	// ------------- Optional query parameter "lines" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lines", c.Request.URL.Query(), &params.Lines)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lines: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCollectorLogs(c, params)
+}
+
+// ReloadPolicies operation middleware
+func (siw *ServerInterfaceWrapper) ReloadPolicies(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ReloadPolicies(c)
+}
+
+// TestCollectorConnection operation middleware
+func (siw *ServerInterfaceWrapper) TestCollectorConnection(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.TestCollectorConnection(c)
+}
+
+// GetConcernCatalog operation middleware
+func (siw *ServerInterfaceWrapper) GetConcernCatalog(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetConcernCatalog(c)
+}
+
+// GetConfig operation middleware
+func (siw *ServerInterfaceWrapper) GetConfig(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetConfig(c)
+}
+
+// CompactStorage operation middleware
+func (siw *ServerInterfaceWrapper) CompactStorage(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CompactStorage(c)
+}
+
+// DownloadDatabase operation middleware
+func (siw *ServerInterfaceWrapper) DownloadDatabase(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DownloadDatabase(c)
+}
+
+// SetLogLevel operation middleware
+func (siw *ServerInterfaceWrapper) SetLogLevel(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SetLogLevel(c)
+}
+
+// GetStorageUsage operation middleware
+func (siw *ServerInterfaceWrapper) GetStorageUsage(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetStorageUsage(c)
+}
+
+// StreamEvents operation middleware
+func (siw *ServerInterfaceWrapper) StreamEvents(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StreamEvents(c)
+}
+
+// ExportInventory operation middleware
+func (siw *ServerInterfaceWrapper) ExportInventory(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ExportInventory(c)
+}
+
 // StopForecaster operation middleware
 func (siw *ServerInterfaceWrapper) StopForecaster(c *gin.Context) {
 
@@ -536,6 +921,19 @@ func (siw *ServerInterfaceWrapper) UpdateGroup(c *gin.Context) {
 // StopInspection operation middleware
 func (siw *ServerInterfaceWrapper) StopInspection(c *gin.Context) {
 
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StopInspectionParams
+
+	// ------------- Optional query parameter "reset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reset", c.Request.URL.Query(), &params.Reset)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter reset: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -543,7 +941,7 @@ func (siw *ServerInterfaceWrapper) StopInspection(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.StopInspection(c)
+	siw.Handler.StopInspection(c, params)
 }
 
 // GetInspectorStatus operation middleware
@@ -656,6 +1054,30 @@ func (siw *ServerInterfaceWrapper) GetInventory(c *gin.Context) {
 		return
 	}
 
+	// ------------- Optional query parameter "schema" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "schema", c.Request.URL.Query(), &params.Schema)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter schema: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "includeSuppressed" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeSuppressed", c.Request.URL.Query(), &params.IncludeSuppressed)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter includeSuppressed: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "snapshot" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "snapshot", c.Request.URL.Query(), &params.Snapshot)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter snapshot: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -666,6 +1088,91 @@ func (siw *ServerInterfaceWrapper) GetInventory(c *gin.Context) {
 	siw.Handler.GetInventory(c, params)
 }
 
+// GetInventoryDiff operation middleware
+func (siw *ServerInterfaceWrapper) GetInventoryDiff(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetInventoryDiff(c)
+}
+
+// ExportInventoryByGroup operation middleware
+func (siw *ServerInterfaceWrapper) ExportInventoryByGroup(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportInventoryByGroupParams
+
+	// ------------- Required query parameter "groupBy" -------------
+
+	if paramValue := c.Query("groupBy"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument groupBy is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "groupBy", c.Request.URL.Query(), &params.GroupBy)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter groupBy: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ExportInventoryByGroup(c, params)
+}
+
+// GetInventoryHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetInventoryHistory(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetInventoryHistory(c)
+}
+
+// GetInventoryMetrics operation middleware
+func (siw *ServerInterfaceWrapper) GetInventoryMetrics(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetInventoryMetrics(c)
+}
+
+// RevalidateInventory operation middleware
+func (siw *ServerInterfaceWrapper) RevalidateInventory(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RevalidateInventory(c)
+}
+
 // ListRightsizingReports operation middleware
 func (siw *ServerInterfaceWrapper) ListRightsizingReports(c *gin.Context) {
 
@@ -769,6 +1276,94 @@ func (siw *ServerInterfaceWrapper) GetVMs(c *gin.Context) {
 		return
 	}
 
+	// ------------- Optional query parameter "hasCritical" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "hasCritical", c.Request.URL.Query(), &params.HasCritical)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter hasCritical: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "issueLabel" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "issueLabel", c.Request.URL.Query(), &params.IssueLabel)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter issueLabel: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "nicType" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "nicType", c.Request.URL.Query(), &params.NicType)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter nicType: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "readiness" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "readiness", c.Request.URL.Query(), &params.Readiness)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter readiness: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "nicCountMin" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "nicCountMin", c.Request.URL.Query(), &params.NicCountMin)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter nicCountMin: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "nicCountMax" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "nicCountMax", c.Request.URL.Query(), &params.NicCountMax)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter nicCountMax: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "includeSuppressed" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeSuppressed", c.Request.URL.Query(), &params.IncludeSuppressed)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter includeSuppressed: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", c.Request.URL.Query(), &params.Fields)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter fields: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "count" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "count", c.Request.URL.Query(), &params.Count)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter count: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "explain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "explain", c.Request.URL.Query(), &params.Explain)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter explain: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "view" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "view", c.Request.URL.Query(), &params.View)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter view: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -779,6 +1374,196 @@ func (siw *ServerInterfaceWrapper) GetVMs(c *gin.Context) {
 	siw.Handler.GetVMs(c, params)
 }
 
+// BatchGetVMs operation middleware
+func (siw *ServerInterfaceWrapper) BatchGetVMs(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.BatchGetVMs(c)
+}
+
+// GetVMsGroupBy operation middleware
+func (siw *ServerInterfaceWrapper) GetVMsGroupBy(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetVMsGroupByParams
+
+	// ------------- Required query parameter "field" -------------
+
+	if paramValue := c.Query("field"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument field is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "field", c.Request.URL.Query(), &params.Field)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter field: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "byExpression" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "byExpression", c.Request.URL.Query(), &params.ByExpression)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter byExpression: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "hasCritical" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "hasCritical", c.Request.URL.Query(), &params.HasCritical)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter hasCritical: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "includeSuppressed" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeSuppressed", c.Request.URL.Query(), &params.IncludeSuppressed)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter includeSuppressed: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetVMsGroupBy(c, params)
+}
+
+// CancelVmsInspection operation middleware
+func (siw *ServerInterfaceWrapper) CancelVmsInspection(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CancelVmsInspection(c)
+}
+
+// PauseInspection operation middleware
+func (siw *ServerInterfaceWrapper) PauseInspection(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PauseInspection(c)
+}
+
+// PrioritizeInspection operation middleware
+func (siw *ServerInterfaceWrapper) PrioritizeInspection(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PrioritizeInspection(c)
+}
+
+// ResumeInspection operation middleware
+func (siw *ServerInterfaceWrapper) ResumeInspection(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ResumeInspection(c)
+}
+
+// GetVMsOsDistribution operation middleware
+func (siw *ServerInterfaceWrapper) GetVMsOsDistribution(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetVMsOsDistributionParams
+
+	// ------------- Optional query parameter "byExpression" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "byExpression", c.Request.URL.Query(), &params.ByExpression)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter byExpression: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "hasCritical" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "hasCritical", c.Request.URL.Query(), &params.HasCritical)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter hasCritical: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "includeSuppressed" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeSuppressed", c.Request.URL.Query(), &params.IncludeSuppressed)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter includeSuppressed: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetVMsOsDistribution(c, params)
+}
+
+// ListVMViews operation middleware
+func (siw *ServerInterfaceWrapper) ListVMViews(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListVMViews(c)
+}
+
+// CreateVMView operation middleware
+func (siw *ServerInterfaceWrapper) CreateVMView(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateVMView(c)
+}
+
 // GetVM operation middleware
 func (siw *ServerInterfaceWrapper) GetVM(c *gin.Context) {
 
@@ -880,9 +1665,27 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 
 	router.GET(options.BaseURL+"/agent", wrapper.GetAgentStatus)
 	router.POST(options.BaseURL+"/agent", wrapper.SetAgentMode)
+	router.PATCH(options.BaseURL+"/agent/config", wrapper.UpdateAgentConfig)
+	router.GET(options.BaseURL+"/agent/maintenance", wrapper.GetMaintenance)
+	router.POST(options.BaseURL+"/agent/maintenance", wrapper.SetMaintenance)
+	router.POST(options.BaseURL+"/agent/push-inventory", wrapper.PushInventory)
+	router.GET(options.BaseURL+"/capabilities", wrapper.GetCapabilities)
+	router.GET(options.BaseURL+"/clusters", wrapper.GetClusters)
 	router.DELETE(options.BaseURL+"/collector", wrapper.StopCollector)
 	router.GET(options.BaseURL+"/collector", wrapper.GetCollectorStatus)
 	router.POST(options.BaseURL+"/collector", wrapper.StartCollector)
+	router.GET(options.BaseURL+"/collector/history", wrapper.GetCollectorHistory)
+	router.GET(options.BaseURL+"/collector/logs", wrapper.GetCollectorLogs)
+	router.POST(options.BaseURL+"/collector/policies/reload", wrapper.ReloadPolicies)
+	router.POST(options.BaseURL+"/collector/test", wrapper.TestCollectorConnection)
+	router.GET(options.BaseURL+"/concerns/catalog", wrapper.GetConcernCatalog)
+	router.GET(options.BaseURL+"/config", wrapper.GetConfig)
+	router.POST(options.BaseURL+"/debug/compact", wrapper.CompactStorage)
+	router.GET(options.BaseURL+"/debug/db", wrapper.DownloadDatabase)
+	router.POST(options.BaseURL+"/debug/loglevel", wrapper.SetLogLevel)
+	router.GET(options.BaseURL+"/debug/storage", wrapper.GetStorageUsage)
+	router.GET(options.BaseURL+"/events", wrapper.StreamEvents)
+	router.GET(options.BaseURL+"/export", wrapper.ExportInventory)
 	router.DELETE(options.BaseURL+"/forecaster", wrapper.StopForecaster)
 	router.GET(options.BaseURL+"/forecaster", wrapper.GetForecasterStatus)
 	router.POST(options.BaseURL+"/forecaster", wrapper.StartForecaster)
@@ -905,11 +1708,25 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 	router.GET(options.BaseURL+"/inspector/vddk", wrapper.GetInspectorVddkStatus)
 	router.PUT(options.BaseURL+"/inspector/vddk", wrapper.PutInspectorVddk)
 	router.GET(options.BaseURL+"/inventory", wrapper.GetInventory)
+	router.GET(options.BaseURL+"/inventory/diff", wrapper.GetInventoryDiff)
+	router.GET(options.BaseURL+"/inventory/export", wrapper.ExportInventoryByGroup)
+	router.GET(options.BaseURL+"/inventory/history", wrapper.GetInventoryHistory)
+	router.GET(options.BaseURL+"/inventory/metrics", wrapper.GetInventoryMetrics)
+	router.POST(options.BaseURL+"/inventory/revalidate", wrapper.RevalidateInventory)
 	router.GET(options.BaseURL+"/rightsizing", wrapper.ListRightsizingReports)
 	router.POST(options.BaseURL+"/rightsizing", wrapper.TriggerRightsizingCollection)
 	router.GET(options.BaseURL+"/rightsizing/:id", wrapper.GetRightsizingReport)
 	router.GET(options.BaseURL+"/version", wrapper.GetVersion)
 	router.GET(options.BaseURL+"/vms", wrapper.GetVMs)
+	router.POST(options.BaseURL+"/vms/batch", wrapper.BatchGetVMs)
+	router.GET(options.BaseURL+"/vms/group-by", wrapper.GetVMsGroupBy)
+	router.POST(options.BaseURL+"/vms/inspector/cancel", wrapper.CancelVmsInspection)
+	router.POST(options.BaseURL+"/vms/inspector/pause", wrapper.PauseInspection)
+	router.PATCH(options.BaseURL+"/vms/inspector/priority", wrapper.PrioritizeInspection)
+	router.POST(options.BaseURL+"/vms/inspector/resume", wrapper.ResumeInspection)
+	router.GET(options.BaseURL+"/vms/os-distribution", wrapper.GetVMsOsDistribution)
+	router.GET(options.BaseURL+"/vms/views", wrapper.ListVMViews)
+	router.POST(options.BaseURL+"/vms/views", wrapper.CreateVMView)
 	router.GET(options.BaseURL+"/vms/:id", wrapper.GetVM)
 	router.DELETE(options.BaseURL+"/vms/:id/inspection", wrapper.RemoveVMFromInspection)
 	router.GET(options.BaseURL+"/vms/:id/utilization", wrapper.GetVMUtilization)
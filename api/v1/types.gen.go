@@ -21,12 +21,31 @@ const (
 	AgentStatusConsoleConnectionDisconnected AgentStatusConsoleConnection = "disconnected"
 )
 
+// Defines values for AgentStatusErrorCode.
+const (
+	AgentStatusErrorCodeSourceNotRegistered AgentStatusErrorCode = "SOURCE_NOT_REGISTERED"
+)
+
 // Defines values for AgentStatusMode.
 const (
 	AgentStatusModeConnected    AgentStatusMode = "connected"
 	AgentStatusModeDisconnected AgentStatusMode = "disconnected"
 )
 
+// Defines values for CollectionHistoryEventState.
+const (
+	CollectionHistoryEventStateCollected CollectionHistoryEventState = "collected"
+	CollectionHistoryEventStateError     CollectionHistoryEventState = "error"
+)
+
+// Defines values for CollectorPhasePhase.
+const (
+	CollectorPhasePhaseCollected  CollectorPhasePhase = "collected"
+	CollectorPhasePhaseCollecting CollectorPhasePhase = "collecting"
+	CollectorPhasePhaseConnecting CollectorPhasePhase = "connecting"
+	CollectorPhasePhaseParsing    CollectorPhasePhase = "parsing"
+)
+
 // Defines values for CollectorStatusStatus.
 const (
 	CollectorStatusStatusCollected  CollectorStatusStatus = "collected"
@@ -61,16 +80,32 @@ const (
 	ForecasterStatusStateRunning ForecasterStatusState = "running"
 )
 
+// Defines values for InspectionCancelResultOutcome.
+const (
+	InspectionCancelResultOutcomeAlreadyCompleted InspectionCancelResultOutcome = "already-completed"
+	InspectionCancelResultOutcomeCanceled         InspectionCancelResultOutcome = "canceled"
+	InspectionCancelResultOutcomeNotFound         InspectionCancelResultOutcome = "not-found"
+)
+
 // Defines values for InspectorStatusState.
 const (
 	InspectorStatusStateCanceled   InspectorStatusState = "canceled"
 	InspectorStatusStateCompleted  InspectorStatusState = "completed"
 	InspectorStatusStateError      InspectorStatusState = "error"
 	InspectorStatusStateInitiating InspectorStatusState = "Initiating"
+	InspectorStatusStatePaused     InspectorStatusState = "paused"
 	InspectorStatusStateReady      InspectorStatusState = "ready"
 	InspectorStatusStateRunning    InspectorStatusState = "running"
 )
 
+// Defines values for LogLevelLevel.
+const (
+	Debug LogLevelLevel = "debug"
+	Error LogLevelLevel = "error"
+	Info  LogLevelLevel = "info"
+	Warn  LogLevelLevel = "warn"
+)
+
 // Defines values for PairCapabilityCapabilities.
 const (
 	PairCapabilityCapabilitiesCopyOffload PairCapabilityCapabilities = "copy-offload"
@@ -98,6 +133,75 @@ const (
 	VmInspectionStatusStateRunning   VmInspectionStatusState = "running"
 )
 
+// Defines values for GetInventoryParamsSchema.
+const (
+	V1 GetInventoryParamsSchema = "v1"
+	V2 GetInventoryParamsSchema = "v2"
+)
+
+// Defines values for ExportInventoryByGroupParamsGroupBy.
+const (
+	ExportInventoryByGroupParamsGroupByCluster ExportInventoryByGroupParamsGroupBy = "cluster"
+)
+
+// Defines values for GetVMsParamsReadiness.
+const (
+	GetVMsParamsReadinessBlocked    GetVMsParamsReadiness = "blocked"
+	GetVMsParamsReadinessMigratable GetVMsParamsReadiness = "migratable"
+	GetVMsParamsReadinessWarning    GetVMsParamsReadiness = "warning"
+)
+
+// Defines values for GetVMsGroupByParamsField.
+const (
+	GetVMsGroupByParamsFieldCluster      GetVMsGroupByParamsField = "cluster"
+	GetVMsGroupByParamsFieldDatacenter   GetVMsGroupByParamsField = "datacenter"
+	GetVMsGroupByParamsFieldHost         GetVMsGroupByParamsField = "host"
+	GetVMsGroupByParamsFieldOs           GetVMsGroupByParamsField = "os"
+	GetVMsGroupByParamsFieldVCenterState GetVMsGroupByParamsField = "vCenterState"
+)
+
+// AgentConfig defines model for AgentConfig.
+type AgentConfig struct {
+	// CollectionHistoryEnabled Whether collection results are recorded to the local history while disconnected
+	CollectionHistoryEnabled bool `json:"collectionHistoryEnabled"`
+
+	// CollectionSchedule Cron expression or duration for periodic recollection; empty when disabled
+	CollectionSchedule string `json:"collectionSchedule"`
+
+	// DataFolder Folder holding the agent database
+	DataFolder string `json:"dataFolder"`
+
+	// Id Agent ID
+	Id string `json:"id"`
+
+	// LegacyStatusEnabled Whether legacy (v1) status values are reported to the console
+	LegacyStatusEnabled bool `json:"legacyStatusEnabled"`
+
+	// Mode Agent mode the agent was started with
+	Mode string `json:"mode"`
+
+	// OpaPoliciesFolder Folder holding the OPA policies
+	OpaPoliciesFolder string `json:"opaPoliciesFolder"`
+
+	// SourceId Source ID
+	SourceId string `json:"sourceId"`
+
+	// UpdateInterval Interval between console updates (e.g. 5s)
+	UpdateInterval string `json:"updateInterval"`
+
+	// Version Agent version
+	Version string `json:"version"`
+}
+
+// AgentConfigUpdate defines model for AgentConfigUpdate.
+type AgentConfigUpdate struct {
+	// LegacyStatusEnabled Whether legacy (v1) status values are reported to the console. null resets it to --legacy-status-enabled
+	LegacyStatusEnabled *bool `json:"legacyStatusEnabled"`
+
+	// UpdateInterval Interval between console updates (e.g. 5s); must be positive. null resets it to --console-update-interval
+	UpdateInterval *string `json:"updateInterval"`
+}
+
 // AgentModeRequest defines model for AgentModeRequest.
 type AgentModeRequest struct {
 	Mode AgentModeRequestMode `binding:"required,oneof=connected disconnected" json:"mode"`
@@ -114,6 +218,16 @@ type AgentStatus struct {
 	// Error Connection error description
 	Error *string `json:"error,omitempty"`
 
+	// ErrorCode Machine-readable kind of the error, set for errors the operator can
+	// fix. SOURCE_NOT_REGISTERED: the console does not know the agent's
+	// source ID; create the source in the console or fix --source-id.
+	ErrorCode *AgentStatusErrorCode `json:"error_code,omitempty"`
+
+	// LastContactAt When a request to the console last succeeded. Kept while the agent
+	// backs off after errors, so a stalled agent shows how long it has been
+	// out of touch. Absent if the console was never reached since startup.
+	LastContactAt *time.Time `json:"last_contact_at,omitempty"`
+
 	// Mode Target mode for the agent
 	Mode AgentStatusMode `json:"mode"`
 }
@@ -121,6 +235,11 @@ type AgentStatus struct {
 // AgentStatusConsoleConnection Current console connection status
 type AgentStatusConsoleConnection string
 
+// AgentStatusErrorCode Machine-readable kind of the error, set for errors the operator can
+// fix. SOURCE_NOT_REGISTERED: the console does not know the agent's
+// source ID; create the source in the console or fix --source-id.
+type AgentStatusErrorCode string
+
 // AgentStatusMode Target mode for the agent
 type AgentStatusMode string
 
@@ -147,19 +266,143 @@ type BenchmarkRun struct {
 	ThroughputMbps  float64  `json:"throughputMbps"`
 }
 
+// Capabilities defines model for Capabilities.
+type Capabilities struct {
+	// AgentVersion Agent version (e.g. v2.0.0), as in GET /version
+	AgentVersion string `json:"agentVersion"`
+
+	// ApiVersion Version of the agent API (e.g. v1)
+	ApiVersion string `json:"apiVersion"`
+
+	// Features Supported features, sorted by name
+	Features []Feature `json:"features"`
+}
+
+// ClusterList defines model for ClusterList.
+type ClusterList struct {
+	Clusters []ClusterMetrics `json:"clusters"`
+}
+
+// ClusterMetrics Totals of one cluster of the stored inventory
+type ClusterMetrics struct {
+	// CpuOverCommitment Allocated vCPUs per physical core. Omitted when the cluster has no host CPU data
+	CpuOverCommitment *float64 `json:"cpuOverCommitment,omitempty"`
+
+	// Id Cluster ID, as the key of clusters in GET /inventory
+	Id string `json:"id"`
+
+	// MemoryOverCommitment Allocated memory per GB of host memory. Omitted when the cluster has no host memory data
+	MemoryOverCommitment *float64 `json:"memoryOverCommitment,omitempty"`
+	PoweredOnVms         int      `json:"poweredOnVms"`
+
+	// TotalDiskTB Provisioned disk capacity of the cluster's VMs, in TB (1 TB = 1024 GB)
+	TotalDiskTB float64 `json:"totalDiskTB"`
+	TotalHosts  int     `json:"totalHosts"`
+	TotalRamGB  int     `json:"totalRamGB"`
+	TotalVcpus  int     `json:"totalVcpus"`
+	TotalVms    int     `json:"totalVms"`
+}
+
+// CollectionHistory defines model for CollectionHistory.
+type CollectionHistory struct {
+	Events []CollectionHistoryEvent `json:"events"`
+}
+
+// CollectionHistoryEvent defines model for CollectionHistoryEvent.
+type CollectionHistoryEvent struct {
+	// Error Error message when state is error
+	Error *string                     `json:"error,omitempty"`
+	State CollectionHistoryEventState `json:"state"`
+
+	// Time When the collection finished
+	Time time.Time `json:"time"`
+}
+
+// CollectionHistoryEventState defines model for CollectionHistoryEvent.State.
+type CollectionHistoryEventState string
+
+// CollectorLogs defines model for CollectorLogs.
+type CollectorLogs struct {
+	// Lines Log lines, oldest first, in console format
+	Lines []string `json:"lines"`
+}
+
+// CollectorPhase defines model for CollectorPhase.
+type CollectorPhase struct {
+	// DurationMs Time the phase took, in milliseconds
+	DurationMs int64 `json:"durationMs"`
+
+	// Phase The collector state of the phase: connecting verifies the credentials,
+	// collecting lists the vCenter inventory, parsing stores it and collected
+	// queues the inventory update
+	Phase CollectorPhasePhase `json:"phase"`
+}
+
+// CollectorPhasePhase The collector state of the phase: connecting verifies the credentials,
+// collecting lists the vCenter inventory, parsing stores it and collected
+// queues the inventory update
+type CollectorPhasePhase string
+
 // CollectorStartRequest defines model for CollectorStartRequest.
-type CollectorStartRequest = VcenterCredentials
+type CollectorStartRequest struct {
+	// InsecureSkipVerify Skip verification of the vCenter TLS certificate for this collection. Use for lab vCenters with self-signed certificates.
+	InsecureSkipVerify *bool `json:"insecureSkipVerify,omitempty"`
+
+	// MaxAge Freshness window, as a Go duration (e.g. "1h"). Only used by POST /collector.
+	// Without it a stored inventory is never recollected. With it, an inventory
+	// collected more than maxAge ago is recollected with these credentials, and a
+	// fresher one is kept.
+	MaxAge   *string `json:"maxAge,omitempty"`
+	Password string  `binding:"required,min=1" json:"password"`
+
+	// Url vCenter URL
+	Url      string `binding:"required,url" json:"url"`
+	Username string `binding:"required,min=1" json:"username"`
+}
 
 // CollectorStatus defines model for CollectorStatus.
 type CollectorStatus struct {
+	// CollectedAt When the stored inventory was collected. Only set when status is collected.
+	CollectedAt *time.Time `json:"collectedAt,omitempty"`
+
 	// Error Error message when status is error
-	Error  *string               `json:"error,omitempty"`
+	Error *string `json:"error,omitempty"`
+
+	// Phases How long each phase of the last collection took, in the order they ran.
+	// A running collection lists the phases finished so far; a failed phase is
+	// listed with the time it took to fail.
+	Phases *[]CollectorPhase     `json:"phases,omitempty"`
 	Status CollectorStatusStatus `json:"status"`
+
+	// Warnings Non-fatal issues hit by the last successful collection, such as a VM
+	// that could not be validated or VM snapshots that could not be read.
+	// Only set when status is collected.
+	Warnings *[]string `json:"warnings,omitempty"`
 }
 
 // CollectorStatusStatus defines model for CollectorStatus.Status.
 type CollectorStatusStatus string
 
+// ConcernCatalog defines model for ConcernCatalog.
+type ConcernCatalog struct {
+	Concerns []ConcernDefinition `json:"concerns"`
+}
+
+// ConcernDefinition defines model for ConcernDefinition.
+type ConcernDefinition struct {
+	// Assessment Detailed description of the concern, empty when the policy computes it
+	Assessment string `json:"assessment"`
+
+	// Category Severity category (Critical, Warning, Information)
+	Category string `json:"category"`
+
+	// Id Concern identifier, e.g. vmware.snapshot.detected
+	Id string `json:"id"`
+
+	// Label Short label describing the concern
+	Label string `json:"label"`
+}
+
 // CreateGroupRequest defines model for CreateGroupRequest.
 type CreateGroupRequest struct {
 	// Description Optional group description
@@ -175,6 +418,17 @@ type CreateGroupRequest struct {
 	Tags *[]string `binding:"omitempty,dive,tag_format" json:"tags,omitempty"`
 }
 
+// CreateVmViewRequest defines model for CreateVmViewRequest.
+type CreateVmViewRequest struct {
+	// ByExpression Filter expression (will be validated)
+	ByExpression *string `json:"byExpression,omitempty"`
+	Name         string  `binding:"required,min=1,max=100" json:"name"`
+	PageSize     *int    `json:"pageSize,omitempty"`
+
+	// Sort Sort fields with direction, as in GET /vms (e.g. "cluster:asc")
+	Sort *[]string `json:"sort,omitempty"`
+}
+
 // DatastoreDetail defines model for DatastoreDetail.
 type DatastoreDetail struct {
 	// Capabilities Intrinsic offload capabilities of this datastore based on vendor support
@@ -227,6 +481,21 @@ type EstimateRange struct {
 	WorstCase *string `json:"worstCase,omitempty"`
 }
 
+// Feature defines model for Feature.
+type Feature struct {
+	// Description What the feature provides
+	Description string `json:"description"`
+
+	// Name Stable feature name (e.g. events, vms.views)
+	Name string `json:"name"`
+}
+
+// FieldDelta defines model for FieldDelta.
+type FieldDelta struct {
+	Current  int64 `json:"current"`
+	Previous int64 `json:"previous"`
+}
+
 // ForecastStats defines model for ForecastStats.
 type ForecastStats struct {
 	// Ci95LowerMbps Lower bound of 95% confidence interval
@@ -378,13 +647,35 @@ type GuestNetwork struct {
 	PrefixLength *int32 `json:"prefixLength,omitempty"`
 }
 
+// InspectionCancelResult defines model for InspectionCancelResult.
+type InspectionCancelResult struct {
+	// Outcome What canceling did for this VirtualMachine
+	Outcome InspectionCancelResultOutcome `json:"outcome"`
+
+	// VmId VirtualMachine MoRef ID
+	VmId string `json:"vmId"`
+}
+
+// InspectionCancelResultOutcome What canceling did for this VirtualMachine
+type InspectionCancelResultOutcome string
+
+// InspectionCancelResults defines model for InspectionCancelResults.
+type InspectionCancelResults struct {
+	Results []InspectionCancelResult `json:"results"`
+}
+
 // InspectorStatus defines model for InspectorStatus.
 type InspectorStatus struct {
-	Credentials *VcenterCredentials `json:"credentials,omitempty"`
+	// AverageVmDuration Average inspection time of the VMs completed in the current or last run, as a duration string (e.g., "2m30.5s"). Omitted until a VM completes.
+	AverageVmDuration *string             `json:"averageVmDuration,omitempty"`
+	Credentials       *VcenterCredentials `json:"credentials,omitempty"`
 
 	// Error Error message when state is error
 	Error *string `json:"error,omitempty"`
 
+	// EstimatedCompletion Estimated time at which the remaining VMs finish. Only set while running and after at least one VM completed.
+	EstimatedCompletion *time.Time `json:"estimatedCompletion,omitempty"`
+
 	// State Inspector state
 	State InspectorStatusState `json:"state"`
 	Vddk  *VddkProperties      `json:"vddk,omitempty"`
@@ -393,6 +684,65 @@ type InspectorStatus struct {
 // InspectorStatusState Inspector state
 type InspectorStatusState string
 
+// InventoryDiff defines model for InventoryDiff.
+type InventoryDiff struct {
+	// Added IDs of VMs present only in the latest collection
+	Added               []string  `json:"added"`
+	Changed             []VmDelta `json:"changed"`
+	CurrentCollectedAt  time.Time `json:"currentCollectedAt"`
+	PreviousCollectedAt time.Time `json:"previousCollectedAt"`
+
+	// Removed IDs of VMs present only in the previous collection
+	Removed []string `json:"removed"`
+}
+
+// InventoryHistory defines model for InventoryHistory.
+type InventoryHistory struct {
+	// Snapshots Kept inventories, newest (the current one) first
+	Snapshots []InventorySnapshot `json:"snapshots"`
+}
+
+// InventoryMetrics vCenter-wide totals of the stored inventory
+type InventoryMetrics struct {
+	// CpuOverCommitment Allocated vCPUs per physical core. Omitted when the inventory has no host CPU data
+	CpuOverCommitment *float64 `json:"cpuOverCommitment,omitempty"`
+
+	// MemoryOverCommitment Allocated memory per GB of host memory. Omitted when the inventory has no host memory data
+	MemoryOverCommitment *float64 `json:"memoryOverCommitment,omitempty"`
+	PoweredOnVms         int      `json:"poweredOnVms"`
+	TotalDatacenters     int      `json:"totalDatacenters"`
+
+	// TotalDiskTB Provisioned disk capacity of all VMs, in TB (1 TB = 1024 GB)
+	TotalDiskTB float64 `json:"totalDiskTB"`
+	TotalHosts  int     `json:"totalHosts"`
+	TotalRamGB  int     `json:"totalRamGB"`
+	TotalVcpus  int     `json:"totalVcpus"`
+	TotalVms    int     `json:"totalVms"`
+
+	// UpdatedAt When the stored inventory was last built, by a collection or a revalidation
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// InventorySnapshot defines model for InventorySnapshot.
+type InventorySnapshot struct {
+	// TakenAt When the inventory was collected; pass it to GET /inventory as snapshot
+	TakenAt time.Time `json:"takenAt"`
+}
+
+// LogLevel defines model for LogLevel.
+type LogLevel struct {
+	Level LogLevelLevel `binding:"required,oneof=debug info warn error" json:"level"`
+}
+
+// LogLevelLevel defines model for LogLevel.Level.
+type LogLevelLevel string
+
+// Maintenance defines model for Maintenance.
+type Maintenance struct {
+	// Enabled Whether mutating operations are blocked
+	Enabled bool `json:"enabled"`
+}
+
 // PairCapability defines model for PairCapability.
 type PairCapability struct {
 	// Capabilities Feasible offload methods for this source-target pair
@@ -411,6 +761,18 @@ type PairCapabilityRequest struct {
 	Pairs       []DatastorePair     `json:"pairs"`
 }
 
+// PolicyReloadResponse defines model for PolicyReloadResponse.
+type PolicyReloadResponse struct {
+	// Policies Number of policies loaded
+	Policies int `json:"policies"`
+}
+
+// RevalidateResponse defines model for RevalidateResponse.
+type RevalidateResponse struct {
+	// Concerns Number of concerns stored after revalidation
+	Concerns int `json:"concerns"`
+}
+
 // RightsizingCollectRequest defines model for RightsizingCollectRequest.
 type RightsizingCollectRequest struct {
 	// BatchSize Number of VMs per QueryPerf round-trip
@@ -501,6 +863,24 @@ type RightsizingVMReport struct {
 	Warnings []string `json:"warnings"`
 }
 
+// StorageUsage defines model for StorageUsage.
+type StorageUsage struct {
+	// DatabaseBytes Size of the DuckDB file including its write-ahead log
+	DatabaseBytes int64 `json:"databaseBytes"`
+
+	// FreeBytes Space available to the agent on that filesystem
+	FreeBytes int64 `json:"freeBytes"`
+
+	// Path Data folder path
+	Path string `json:"path"`
+
+	// TotalBytes Size of the filesystem holding the data folder
+	TotalBytes int64 `json:"totalBytes"`
+
+	// VddkBytes Size of the extracted VDDK (0 if none was uploaded)
+	VddkBytes int64 `json:"vddkBytes"`
+}
+
 // UpdateGroupRequest defines model for UpdateGroupRequest.
 type UpdateGroupRequest struct {
 	// Description Optional group description
@@ -530,6 +910,12 @@ type VMDisk struct {
 	// Capacity Disk capacity in bytes
 	Capacity *int64 `json:"capacity,omitempty"`
 
+	// Datastore Name of the datastore backing the disk
+	Datastore *string `json:"datastore,omitempty"`
+
+	// DiskType Type of the backing datastore (e.g., VMFS, NFS, vSAN)
+	DiskType *string `json:"diskType,omitempty"`
+
 	// File Path to the VMDK file in the datastore
 	File *string `json:"file,omitempty"`
 
@@ -544,6 +930,9 @@ type VMDisk struct {
 
 	// Shared Whether this disk is shared between multiple VMs
 	Shared *bool `json:"shared,omitempty"`
+
+	// ThinProvisioned Whether the disk is thin provisioned
+	ThinProvisioned *bool `json:"thinProvisioned,omitempty"`
 }
 
 // VMIssue defines model for VMIssue.
@@ -573,6 +962,15 @@ type VMNIC struct {
 	Network *string `json:"network,omitempty"`
 }
 
+// VMSnapshot defines model for VMSnapshot.
+type VMSnapshot struct {
+	// CreatedAt When the snapshot was taken
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+
+	// Name Snapshot name
+	Name string `json:"name"`
+}
+
 // VcenterCredentials defines model for VcenterCredentials.
 type VcenterCredentials struct {
 	Password string `binding:"required,min=1" json:"password"`
@@ -582,6 +980,16 @@ type VcenterCredentials struct {
 	Username string `binding:"required,min=1" json:"username"`
 }
 
+// VcenterInfo defines model for VcenterInfo.
+type VcenterInfo struct {
+	ApiVersion string `json:"apiVersion"`
+	Build      string `json:"build"`
+
+	// Product Full product name reported by vCenter
+	Product string `json:"product"`
+	Version string `json:"version"`
+}
+
 // VddkProperties defines model for VddkProperties.
 type VddkProperties struct {
 	// Bytes provided tarball bytes
@@ -611,12 +1019,18 @@ type VirtualMachine struct {
 	// Cluster Cluster name
 	Cluster string `json:"cluster"`
 
+	// CriticalCount Number of issues with the Critical category
+	CriticalCount int `json:"criticalCount"`
+
 	// Datacenter Datacenter name
 	Datacenter string `json:"datacenter"`
 
 	// DiskSize Total disk size in MB
 	DiskSize int64 `json:"diskSize"`
 
+	// DiskTypes Disk capacity in GB per datastore type (e.g. VMFS, NFS, vsan), as in the inventory disk type summary. Disks on an unknown datastore are left out
+	DiskTypes *map[string]float64 `json:"diskTypes,omitempty"`
+
 	// Id VirtualMachine ID in vCenter
 	Id string `json:"id"`
 
@@ -624,7 +1038,7 @@ type VirtualMachine struct {
 	InspectionConcernCount *int                `json:"inspectionConcernCount,omitempty"`
 	InspectionStatus       *VmInspectionStatus `json:"inspectionStatus,omitempty"`
 
-	// IssueCount Number of issues found for this VirtualMachine
+	// IssueCount Number of issues found for this VirtualMachine (all categories)
 	IssueCount int `json:"issueCount"`
 
 	// Memory Memory size in MB
@@ -636,6 +1050,9 @@ type VirtualMachine struct {
 	// Name VirtualMachine name
 	Name string `json:"name"`
 
+	// SnapshotCount Number of snapshots the VirtualMachine had in vCenter at collection time
+	SnapshotCount *int `json:"snapshotCount,omitempty"`
+
 	// Tags Tags aggregated from matching groups
 	Tags *[]string `json:"tags,omitempty"`
 
@@ -656,6 +1073,9 @@ type VirtualMachine struct {
 
 	// VCenterState vCenter state (e.g., poweredOn, poweredOff, suspended)
 	VCenterState string `json:"vCenterState"`
+
+	// WarningCount Number of issues with the Warning category
+	WarningCount int `json:"warningCount"`
 }
 
 // VirtualMachineDetail defines model for VirtualMachineDetail.
@@ -681,6 +1101,9 @@ type VirtualMachineDetail struct {
 	// Devices List of other virtual devices attached to the VirtualMachine
 	Devices *[]VMDevice `json:"devices,omitempty"`
 
+	// DiskTypes Disk capacity in GB per datastore type (e.g. VMFS, NFS, vsan), as in the inventory disk type summary. Disks on an unknown datastore are left out
+	DiskTypes *map[string]float64 `json:"diskTypes,omitempty"`
+
 	// Disks List of virtual disks attached to the VirtualMachine
 	Disks []VMDisk `json:"disks"`
 
@@ -738,6 +1161,9 @@ type VirtualMachineDetail struct {
 	// PowerState Current power state of the VirtualMachine (poweredOn, poweredOff, or suspended)
 	PowerState string `json:"powerState"`
 
+	// Snapshots Snapshots of the VirtualMachine at collection time, oldest first
+	Snapshots *[]VMSnapshot `json:"snapshots,omitempty"`
+
 	// StorageUsed Total storage space consumed by the VirtualMachine in bytes
 	StorageUsed *int64 `json:"storageUsed,omitempty"`
 
@@ -767,6 +1193,28 @@ type VirtualMachineListResponse struct {
 	Vms   []VirtualMachine `json:"vms"`
 }
 
+// VmBatchRequest defines model for VmBatchRequest.
+type VmBatchRequest struct {
+	// Ids VM ids to look up, at most 100
+	Ids []string `json:"ids"`
+}
+
+// VmBatchResponse defines model for VmBatchResponse.
+type VmBatchResponse struct {
+	// NotFound Requested ids that match no VM
+	NotFound []string               `json:"notFound"`
+	Vms      []VirtualMachineDetail `json:"vms"`
+}
+
+// VmDelta Resource changes of a VM present in both collections. Unchanged fields are omitted.
+type VmDelta struct {
+	Cpus     *FieldDelta `json:"cpus,omitempty"`
+	DiskMB   *FieldDelta `json:"diskMB,omitempty"`
+	Id       string      `json:"id"`
+	MemoryMB *FieldDelta `json:"memoryMB,omitempty"`
+	Name     string      `json:"name"`
+}
+
 // VmInspectionConcern Represents the structure of vm-migration-detective library inspection concerns
 type VmInspectionConcern struct {
 	Category string `json:"category"`
@@ -796,6 +1244,15 @@ type VmInspectionStatus struct {
 // VmInspectionStatusState Current inspection state
 type VmInspectionStatusState string
 
+// VmQueryExplain defines model for VmQueryExplain.
+type VmQueryExplain struct {
+	// Args Values bound to the placeholders, in order
+	Args []interface{} `json:"args"`
+
+	// Sql SQL of the VM list query, with ? placeholders
+	Sql string `json:"sql"`
+}
+
 // VmUtilizationDetails defines model for VmUtilizationDetails.
 type VmUtilizationDetails struct {
 	// Confidence Data confidence — sample_count / expected_sample_count × 100
@@ -819,6 +1276,49 @@ type VmUtilizationDetails struct {
 	VmName    string  `json:"vm_name"`
 }
 
+// VmView defines model for VmView.
+type VmView struct {
+	// ByExpression Filter expression applied when the request has no byExpression
+	ByExpression *string   `json:"byExpression,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Name         string    `json:"name"`
+
+	// PageSize Page size applied when the request has no pageSize
+	PageSize *int `json:"pageSize,omitempty"`
+
+	// Sort Sort applied when the request has no sort
+	Sort []string `json:"sort"`
+}
+
+// VmViewListResponse defines model for VmViewListResponse.
+type VmViewListResponse struct {
+	Views []VmView `json:"views"`
+}
+
+// GetClustersParams defines parameters for GetClusters.
+type GetClustersParams struct {
+	// MinCpuOvercommit Only return clusters with at least this many vCPUs per physical core
+	MinCpuOvercommit *float64 `form:"minCpuOvercommit,omitempty" json:"minCpuOvercommit,omitempty"`
+
+	// MinMemoryOvercommit Only return clusters with at least this much allocated memory per GB of host memory
+	MinMemoryOvercommit *float64 `form:"minMemoryOvercommit,omitempty" json:"minMemoryOvercommit,omitempty"`
+}
+
+// GetCollectorStatusParams defines parameters for GetCollectorStatus.
+type GetCollectorStatusParams struct {
+	// Wait How long to wait for a status change, as a Go duration (e.g. "30s"), at most 60s
+	Wait *string `form:"wait,omitempty" json:"wait,omitempty"`
+
+	// Since Status the caller last saw; only used with wait
+	Since *string `form:"since,omitempty" json:"since,omitempty"`
+}
+
+// GetCollectorLogsParams defines parameters for GetCollectorLogs.
+type GetCollectorLogsParams struct {
+	// Lines Number of most recent lines to return. Values above the number of lines kept are clamped to it.
+	Lines *int `form:"lines,omitempty" json:"lines,omitempty"`
+}
+
 // GetForecasterRunsParams defines parameters for GetForecasterRuns.
 type GetForecasterRunsParams struct {
 	// PairName Filter runs by pair name
@@ -839,22 +1339,28 @@ type ListGroupsParams struct {
 	// Page Page number (1-indexed)
 	Page *int `form:"page,omitempty" json:"page,omitempty"`
 
-	// PageSize Number of groups per page
+	// PageSize Number of groups per page. Values above the configured maximum (default 100) are clamped to it.
 	PageSize *int `form:"pageSize,omitempty" json:"pageSize,omitempty"`
 }
 
 // GetGroupParams defines parameters for GetGroup.
 type GetGroupParams struct {
-	// Sort Sort fields with direction (e.g., "name:asc" or "cluster:desc,name:asc"). Valid fields are name, vCenterState, cluster, diskSize, memory, issues.
+	// Sort Sort fields with direction (e.g., "name:asc" or "cluster:desc,name:asc"). Valid fields are name, vCenterState, cluster, diskSize, memory, issues, criticalIssues, warningIssues.
 	Sort *[]string `form:"sort,omitempty" json:"sort,omitempty"`
 
 	// Page Page number for pagination
 	Page *int `form:"page,omitempty" json:"page,omitempty"`
 
-	// PageSize Number of items per page
+	// PageSize Number of items per page. Values above the configured maximum (default 100) are clamped to it.
 	PageSize *int `form:"pageSize,omitempty" json:"pageSize,omitempty"`
 }
 
+// StopInspectionParams defines parameters for StopInspection.
+type StopInspectionParams struct {
+	// Reset If true, reset a finished inspector to ready instead of stopping a run.
+	Reset *bool `form:"reset,omitempty" json:"reset,omitempty"`
+}
+
 // GetInspectorStatusParams defines parameters for GetInspectorStatus.
 type GetInspectorStatusParams struct {
 	// IncludeVddk If true, include uploaded VDDK metadata (`version`, `md5`) when present. omitted if VDDK was never uploaded.
@@ -882,29 +1388,151 @@ type GetInventoryParams struct {
 
 	// GroupId Filter inventory to VMs matching this group's filter expression
 	GroupId *string `form:"group_id,omitempty" json:"group_id,omitempty"`
+
+	// Schema Inventory schema version. v2 (default) returns the clustered inventory.
+	// v1 returns the legacy flat inventory built from the vCenter-wide data.
+	Schema *GetInventoryParamsSchema `form:"schema,omitempty" json:"schema,omitempty"`
+
+	// IncludeSuppressed If true, keep suppressed concerns in the migration warnings and not-migratable reasons. The VM totals always count suppressed concerns.
+	IncludeSuppressed *bool `form:"includeSuppressed,omitempty" json:"includeSuppressed,omitempty"`
+
+	// Snapshot Return the inventory collected at this time, as listed by GET /inventory/history,
+	// instead of the latest one.
+	Snapshot *time.Time `form:"snapshot,omitempty" json:"snapshot,omitempty"`
+}
+
+// GetInventoryParamsSchema defines parameters for GetInventory.
+type GetInventoryParamsSchema string
+
+// ExportInventoryByGroupParams defines parameters for ExportInventoryByGroup.
+type ExportInventoryByGroupParams struct {
+	// GroupBy How to split the inventory
+	GroupBy ExportInventoryByGroupParamsGroupBy `form:"groupBy" json:"groupBy"`
 }
 
+// ExportInventoryByGroupParamsGroupBy defines parameters for ExportInventoryByGroup.
+type ExportInventoryByGroupParamsGroupBy string
+
 // GetVMsParams defines parameters for GetVMs.
 type GetVMsParams struct {
 	// ByExpression Filter by expression (matches VMs with the provided expression)
 	ByExpression *string `form:"byExpression,omitempty" json:"byExpression,omitempty"`
 
-	// Sort Sort fields with direction (e.g., "name:asc" or "cluster:desc,name:asc"). Valid fields are name, vCenterState, cluster, diskSize, memory, issues.
+	// Sort Sort fields with direction (e.g., "name:asc" or "cluster:desc,name:asc"). Valid fields are name, vCenterState, cluster, diskSize, memory, issues, criticalIssues, warningIssues.
 	Sort *[]string `form:"sort,omitempty" json:"sort,omitempty"`
 
 	// Page Page number for pagination
 	Page *int `form:"page,omitempty" json:"page,omitempty"`
 
-	// PageSize Number of items per page
+	// PageSize Number of items per page. Values above the configured maximum (default 100) are clamped to it.
 	PageSize *int `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+
+	// HasCritical If true, only return VMs with at least one Critical concern. Combines with byExpression.
+	HasCritical *bool `form:"hasCritical,omitempty" json:"hasCritical,omitempty"`
+
+	// IssueLabel Regular expression (RE2 syntax) matched against concern labels. Only VMs with at least one
+	// concern whose label matches are returned. Combines with byExpression and hasCritical.
+	IssueLabel *string `form:"issueLabel,omitempty" json:"issueLabel,omitempty"`
+
+	// NicType Regular expression (RE2 syntax) matched against NIC adapter types (e.g. e1000, vmxnet3).
+	// Only VMs with at least one matching NIC are returned. Combines with the other filters.
+	NicType *string `form:"nicType,omitempty" json:"nicType,omitempty"`
+
+	// Readiness Only return VMs with one of these migration readiness categories, derived from their
+	// concerns: blocked (a Critical concern), warning (a Warning concern but no Critical one)
+	// or migratable (neither). Combines with the other filters.
+	Readiness *[]GetVMsParamsReadiness `form:"readiness,omitempty" json:"readiness,omitempty"`
+
+	// NicCountMin Only return VMs with at least this many NICs, counted by distinct MAC address.
+	NicCountMin *int `form:"nicCountMin,omitempty" json:"nicCountMin,omitempty"`
+
+	// NicCountMax Only return VMs with at most this many NICs, counted by distinct MAC address. Must not be lower than nicCountMin.
+	NicCountMax *int `form:"nicCountMax,omitempty" json:"nicCountMax,omitempty"`
+
+	// IncludeSuppressed If true, suppressed concerns count towards issue counts, severity breakdowns and filters.
+	IncludeSuppressed *bool `form:"includeSuppressed,omitempty" json:"includeSuppressed,omitempty"`
+
+	// Fields Comma-separated list of VM fields to return (e.g. "id,name,memory"). When set, each VM
+	// only contains the requested fields. Valid fields are id, name, cluster, diskSize, memory,
+	// issueCount, vCenterState.
+	Fields *string `form:"fields,omitempty" json:"fields,omitempty"`
+
+	// Count If true, only the number of matching VMs is returned as {"total": N}. The VM rows are
+	// not fetched, so pagination, sort and fields are ignored.
+	Count *bool `form:"count,omitempty" json:"count,omitempty"`
+
+	// Explain Debug aid. If true, the VM list query is not run; the SQL it would run, with the
+	// filter, sort, limit and offset applied, is returned as a VmQueryExplain. Ignored
+	// when count is true. Answers 403 unless the agent runs with
+	// --server-database-download-enabled.
+	Explain *bool `form:"explain,omitempty" json:"explain,omitempty"`
+
+	// View Name of a saved view whose byExpression, sort and pageSize are applied. Parameters
+	// given explicitly in the request take precedence over the view.
+	View *string `form:"view,omitempty" json:"view,omitempty"`
+}
+
+// GetVMsParamsReadiness defines parameters for GetVMs.
+type GetVMsParamsReadiness string
+
+// GetVMsGroupByParams defines parameters for GetVMsGroupBy.
+type GetVMsGroupByParams struct {
+	// Field Field to group the VMs by
+	Field GetVMsGroupByParamsField `form:"field" json:"field"`
+
+	// ByExpression Filter by expression (matches VMs with the provided expression)
+	ByExpression *string `form:"byExpression,omitempty" json:"byExpression,omitempty"`
+
+	// HasCritical If true, only count VMs with at least one Critical concern. Combines with byExpression.
+	HasCritical *bool `form:"hasCritical,omitempty" json:"hasCritical,omitempty"`
+
+	// IncludeSuppressed If true, suppressed concerns count towards filters.
+	IncludeSuppressed *bool `form:"includeSuppressed,omitempty" json:"includeSuppressed,omitempty"`
+}
+
+// GetVMsGroupByParamsField defines parameters for GetVMsGroupBy.
+type GetVMsGroupByParamsField string
+
+// CancelVmsInspectionJSONBody defines parameters for CancelVmsInspection.
+type CancelVmsInspectionJSONBody struct {
+	VmIds []string `json:"vmIds"`
+}
+
+// PrioritizeInspectionJSONBody defines parameters for PrioritizeInspection.
+type PrioritizeInspectionJSONBody struct {
+	VmIds []string `json:"vmIds"`
+}
+
+// GetVMsOsDistributionParams defines parameters for GetVMsOsDistribution.
+type GetVMsOsDistributionParams struct {
+	// ByExpression Filter by expression (matches VMs with the provided expression)
+	ByExpression *string `form:"byExpression,omitempty" json:"byExpression,omitempty"`
+
+	// HasCritical If true, only count VMs with at least one Critical concern. Combines with byExpression.
+	HasCritical *bool `form:"hasCritical,omitempty" json:"hasCritical,omitempty"`
+
+	// IncludeSuppressed If true, suppressed concerns count towards filters.
+	IncludeSuppressed *bool `form:"includeSuppressed,omitempty" json:"includeSuppressed,omitempty"`
 }
 
 // SetAgentModeJSONRequestBody defines body for SetAgentMode for application/json ContentType.
 type SetAgentModeJSONRequestBody = AgentModeRequest
 
+// UpdateAgentConfigJSONRequestBody defines body for UpdateAgentConfig for application/json ContentType.
+type UpdateAgentConfigJSONRequestBody = AgentConfigUpdate
+
+// SetMaintenanceJSONRequestBody defines body for SetMaintenance for application/json ContentType.
+type SetMaintenanceJSONRequestBody = Maintenance
+
 // StartCollectorJSONRequestBody defines body for StartCollector for application/json ContentType.
 type StartCollectorJSONRequestBody = CollectorStartRequest
 
+// TestCollectorConnectionJSONRequestBody defines body for TestCollectorConnection for application/json ContentType.
+type TestCollectorConnectionJSONRequestBody = CollectorStartRequest
+
+// SetLogLevelJSONRequestBody defines body for SetLogLevel for application/json ContentType.
+type SetLogLevelJSONRequestBody = LogLevel
+
 // StartForecasterJSONRequestBody defines body for StartForecaster for application/json ContentType.
 type StartForecasterJSONRequestBody = ForecasterStartRequest
 
@@ -934,3 +1562,15 @@ type PutInspectorVddkMultipartRequestBody PutInspectorVddkMultipartBody
 
 // TriggerRightsizingCollectionJSONRequestBody defines body for TriggerRightsizingCollection for application/json ContentType.
 type TriggerRightsizingCollectionJSONRequestBody = RightsizingCollectRequest
+
+// BatchGetVMsJSONRequestBody defines body for BatchGetVMs for application/json ContentType.
+type BatchGetVMsJSONRequestBody = VmBatchRequest
+
+// CancelVmsInspectionJSONRequestBody defines body for CancelVmsInspection for application/json ContentType.
+type CancelVmsInspectionJSONRequestBody CancelVmsInspectionJSONBody
+
+// PrioritizeInspectionJSONRequestBody defines body for PrioritizeInspection for application/json ContentType.
+type PrioritizeInspectionJSONRequestBody PrioritizeInspectionJSONBody
+
+// CreateVMViewJSONRequestBody defines body for CreateVMView for application/json ContentType.
+type CreateVMViewJSONRequestBody = CreateVmViewRequest
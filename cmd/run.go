@@ -23,10 +23,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/go-extras/cobraflags"
 
-	"github.com/kubev2v/migration-planner/pkg/opa"
+	"github.com/kubev2v/migration-planner/pkg/duckdb_parser"
 
 	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
 	"github.com/kubev2v/assisted-migration-agent/internal/config"
@@ -35,13 +36,21 @@ import (
 	"github.com/kubev2v/assisted-migration-agent/internal/server"
 	"github.com/kubev2v/assisted-migration-agent/internal/services"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
 	"github.com/kubev2v/assisted-migration-agent/pkg/console"
+	"github.com/kubev2v/assisted-migration-agent/pkg/logger"
 )
 
 const (
 	apiV1 string = "/api/v1"
+	// collectorLogLines is the number of collection log lines kept for GET /collector/logs.
+	collectorLogLines = 1000
 )
 
+// collectorLoggers are the loggers written to while collecting and parsing
+// the inventory.
+var collectorLoggers = []string{"inventory", "collector_service", "duckdb_parser"}
+
 func NewRunCommand(cfg *config.Configuration) *cobra.Command {
 	runCmd := &cobra.Command{
 		Use:   "run",
@@ -66,11 +75,24 @@ func NewRunCommand(cfg *config.Configuration) *cobra.Command {
 				"auth", helpers.Flatten(cfg.Auth.DebugMap()),
 			)
 
+			// Keep recent collection logs for GET /collector/logs.
+			collectorLogs := logger.NewRing(collectorLogLines, collectorLoggers...)
+			undoLogger := zap.ReplaceGlobals(zap.L().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+				return zapcore.NewTee(core, collectorLogs.Core())
+			})))
+			defer undoLogger()
+
 			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGHUP, syscall.SIGTERM, syscall.SIGQUIT)
 			wg := sync.WaitGroup{}
 			wg.Add(1)
 
-			st, err := initStore(cfg)
+			policySrv, err := services.NewPolicyService(cfg.Agent.OpaPoliciesFolder)
+			if err != nil {
+				zap.S().Errorw("failed to initialize OPA validator", "error", err)
+				return err
+			}
+
+			st, err := initStore(cfg, policySrv)
 			if err != nil {
 				return err
 			}
@@ -95,7 +117,8 @@ func NewRunCommand(cfg *config.Configuration) *cobra.Command {
 			}
 
 			// init console client
-			consoleClient, err := console.NewConsoleClient(cfg.Console.URL, jwt)
+			consoleClient, err := console.NewConsoleClient(cfg.Console.URL, jwt,
+				console.WithUserAgent(console.UserAgent(cfg.Agent.Version, cfg.Agent.ID)))
 			if err != nil {
 				return fmt.Errorf("failed to create console client: %w", err)
 			}
@@ -124,7 +147,13 @@ func NewRunCommand(cfg *config.Configuration) *cobra.Command {
 				WithVddkService(svcMgr.VddkService()).
 				WithGroupService(svcMgr.GroupService()).
 				WithRightsizingService(svcMgr.RightsizingService()).
-				WithForecasterService(svcMgr.ForecasterService())
+				WithForecasterService(svcMgr.ForecasterService()).
+				WithPolicyService(policySrv).
+				WithMaintenanceService(svcMgr.MaintenanceService()).
+				WithStorageService(svcMgr.StorageService()).
+				WithStatusBroker(svcMgr.StatusBroker()).
+				WithCollectorLogs(collectorLogs).
+				WithLogLevel(logger.Level())
 
 			srv, err := server.NewServer(cfg, map[string]func(router *gin.RouterGroup){
 				apiV1: func(router *gin.RouterGroup) {
@@ -227,12 +256,48 @@ func validateConfiguration(cfg *config.Configuration) error {
 		return errors.New("authentication-jwt-filepath must be set when authentication is enabled")
 	}
 
+	if cfg.Agent.InventoryPushTimeoutMax > 0 && (cfg.Agent.InventoryPushTimeoutMin <= 0 || cfg.Agent.InventoryPushTimeoutMin > cfg.Agent.InventoryPushTimeoutMax) {
+		return fmt.Errorf("invalid inventory-push-timeout-min %s: must be positive and at most inventory-push-timeout-max (%s)", cfg.Agent.InventoryPushTimeoutMin, cfg.Agent.InventoryPushTimeoutMax)
+	}
+
+	if cfg.Agent.CollectionConcurrency < 1 {
+		return fmt.Errorf("invalid collection-concurrency %d: must be at least 1", cfg.Agent.CollectionConcurrency)
+	}
+
+	if cfg.Agent.InventoryRetention < 0 {
+		return fmt.Errorf("invalid inventory-retention %d: must not be negative", cfg.Agent.InventoryRetention)
+	}
+
+	if _, err := services.ParseTiers(cfg.Agent); err != nil {
+		return err
+	}
+
+	if _, err := services.ParseConcernCategories(cfg.Agent); err != nil {
+		return err
+	}
+
+	for id, text := range cfg.Agent.ConcernAssessments {
+		if strings.TrimSpace(id) == "" || strings.TrimSpace(text) == "" {
+			return fmt.Errorf("invalid concern-assessments entry %q=%q: concern id and text must not be empty", id, text)
+		}
+	}
+
+	if _, err := collectorV1.ParseRedactFields(cfg.Agent.PushRedactedFields); err != nil {
+		return fmt.Errorf("invalid push-redacted-fields: %w", err)
+	}
+
+	if cfg.Agent.CollectionSchedule != "" {
+		if _, err := services.ParseCollectionSchedule(cfg.Agent.CollectionSchedule); err != nil {
+			return fmt.Errorf("invalid collection-schedule: %w", err)
+		}
+	}
+
 	return nil
 }
 
-func initStore(cfg *config.Configuration) (*store.Store, error) {
+func initStore(cfg *config.Configuration, validator duckdb_parser.Validator) (*store.Store, error) {
 	// init store
-	dbPath := filepath.Join(cfg.Agent.DataFolder, "agent.duckdb")
+	dbPath := filepath.Join(cfg.Agent.DataFolder, store.DatabaseFile)
 	if cfg.Agent.DataFolder == "" {
 		dbPath = ":memory:"
 		zap.S().Warn("data-folder not set, using in-memory database (data will not persist)")
@@ -243,13 +308,7 @@ func initStore(cfg *config.Configuration) (*store.Store, error) {
 		return nil, err
 	}
 
-	opaValidator, err := opa.NewValidatorFromDir(cfg.Agent.OpaPoliciesFolder)
-	if err != nil {
-		zap.S().Errorw("failed to initialize OPA validator", "error", err)
-		return nil, err
-	}
-
-	return store.NewStore(db, opaValidator), nil
+	return store.NewStore(db, validator), nil
 }
 
 func validateUUID(value, name string) error {
@@ -266,6 +325,9 @@ func registerServerFlags(flagSet *pflag.FlagSet, config *config.Configuration) {
 	flagSet.IntVar(&config.Server.HTTPPort, "server-http-port", config.Server.HTTPPort, "Port on which the HTTP server is listening")
 	flagSet.StringVar(&config.Server.StaticsFolder, "server-statics-folder", config.Server.StaticsFolder, "Path to statics folder")
 	flagSet.StringVar(&config.Server.ServerMode, "server-mode", config.Server.ServerMode, "Server mode: either prod or dev. If prod the statics folder must be set")
+	flagSet.DurationVar(&config.Server.RequestTimeout, "server-request-timeout", config.Server.RequestTimeout, "Maximum time an API request may run before it is answered with 503. Event streams, long-polls, export and VDDK upload are exempt. 0 disables the timeout")
+	flagSet.BoolVar(&config.Server.DatabaseDownloadEnabled, "server-database-download-enabled", config.Server.DatabaseDownloadEnabled, "Serve the agent DuckDB file on GET /api/v1/debug/db for support bundles. The file holds the full inventory")
+	flagSet.StringSliceVar(&config.Server.AllowedOrigins, "server-allowed-origins", config.Server.AllowedOrigins, "Comma-separated origins (e.g. https://ui.example.com, or * for any) whose pages may call the API from the browser. Empty allows only same-origin pages")
 }
 
 func registerAuthenticationFlags(flagSet *pflag.FlagSet, config *config.Configuration) {
@@ -281,6 +343,25 @@ func registerAgentFlags(flagSet *pflag.FlagSet, config *config.Configuration) {
 	flagSet.StringVar(&config.Agent.Version, "version", config.Agent.Version, "Agent version to report to console")
 	flagSet.StringVar(&config.Agent.DataFolder, "data-folder", config.Agent.DataFolder, "Path to the persistent data folder")
 	flagSet.BoolVar(&config.Agent.LegacyStatusEnabled, "legacy-status-enabled", config.Agent.LegacyStatusEnabled, "Use agent's legacy status like waiting-for-credentials")
+	flagSet.BoolVar(&config.Agent.CollectionHistoryEnabled, "collection-history-enabled", config.Agent.CollectionHistoryEnabled, "Record collection results to a local history file while disconnected")
+	flagSet.StringVar(&config.Agent.CollectionSchedule, "collection-schedule", config.Agent.CollectionSchedule, "Cron expression or duration (e.g. 24h) for periodic recollection with the last credentials")
+	flagSet.StringVar(&config.Agent.CredentialsSecretPath, "credentials-secret-filepath", config.Agent.CredentialsSecretPath, "Path of the secret used to encrypt stored vCenter credentials; credentials are kept in memory only if unset")
+	flagSet.StringSliceVar(&config.Agent.SuppressedConcerns, "suppressed-concerns", config.Agent.SuppressedConcerns, "Comma-separated concern IDs to leave out of issue counts and inventory migration issues")
+	flagSet.StringToStringVar(&config.Agent.ConcernAssessments, "concern-assessments", config.Agent.ConcernAssessments, "Comma-separated id=text pairs appending remediation guidance or a link to the assessment of a concern in inventory migration issues and VM issues; quote a text holding commas")
+	flagSet.IntVar(&config.Agent.MaxInventoryBytes, "max-inventory-bytes", config.Agent.MaxInventoryBytes, "Largest inventory, in bytes, pushed to the console; larger ones are skipped with an error status (0 disables the limit)")
+	flagSet.StringSliceVar(&config.Agent.PushRedactedFields, "push-redacted-fields", config.Agent.PushRedactedFields, "Comma-separated inventory fields obfuscated in the inventory pushed to the console, but kept in the local database: cluster-names, network-names, datastore-ids, host-ids, vcenter-id")
+	flagSet.IntVar(&config.Agent.MaxPageSize, "max-page-size", config.Agent.MaxPageSize, "Largest page size served by the VM and group list endpoints; larger requests are clamped to it")
+	flagSet.IntVar(&config.Agent.MaxFilterLength, "max-filter-length", config.Agent.MaxFilterLength, "Longest filter expression, in bytes, accepted by the VM and group endpoints; longer ones are rejected with 413 Request Entity Too Large")
+	flagSet.DurationVar(&config.Agent.VCenterSessionTTL, "vcenter-session-ttl", config.Agent.VCenterSessionTTL, "How long an idle vCenter session is kept for reuse by the next collection or inspection (0 disables reuse)")
+	flagSet.IntVar(&config.Agent.InspectionWorkers, "inspection-workers", config.Agent.InspectionWorkers, "Number of VMs inspected in parallel")
+	flagSet.IntVar(&config.Agent.CollectionConcurrency, "collection-concurrency", config.Agent.CollectionConcurrency, "Number of vCenter object lists (VM snapshots and creation dates) fetched in parallel after the inventory collection; 1 fetches them one after the other")
+	flagSet.DurationVar(&config.Agent.InventoryPushTimeoutMin, "inventory-push-timeout-min", config.Agent.InventoryPushTimeoutMin, "Shortest time allowed for one inventory push to the console; larger inventories get proportionally more, up to inventory-push-timeout-max")
+	flagSet.DurationVar(&config.Agent.InventoryPushTimeoutMax, "inventory-push-timeout-max", config.Agent.InventoryPushTimeoutMax, "Longest time allowed for one inventory push to the console (0 disables the deadline)")
+	flagSet.IntVar(&config.Agent.InventoryRetention, "inventory-retention", config.Agent.InventoryRetention, "Number of previous inventories kept besides the current one, listed by GET /inventory/history")
+	flagSet.StringSliceVar(&config.Agent.DiskSizeTiers, "disk-size-tiers", config.Agent.DiskSizeTiers, "Comma-separated inventory diskSizeTier buckets as label=maxTB, smallest first, the last one a bare label for the rest (e.g. Easy=1,Medium=5,Hard=20,White Glove); empty keeps the default buckets")
+	flagSet.IntSliceVar(&config.Agent.CPUTiers, "cpu-tiers", config.Agent.CPUTiers, "Comma-separated vCPU upper bounds of the inventory distributionByCpuTier buckets (e.g. 2,4,8 gives 0-2, 3-4, 5-8 and 8+); empty keeps the default 4,8,16,32")
+	flagSet.IntSliceVar(&config.Agent.MemoryTiers, "memory-tiers", config.Agent.MemoryTiers, "Comma-separated memory upper bounds, in GB, of the inventory distributionByMemoryTier buckets (e.g. 8,32 gives 0-8, 9-32 and 32+); empty keeps the default 4,16,32,64,128,256")
+	flagSet.StringToStringVar(&config.Agent.ConcernCategories, "concern-categories", config.Agent.ConcernCategories, "Comma-separated category=bucket pairs routing concern categories to critical, warning or ignore in the inventory migration issues and totals (e.g. Info=warning,Notice=ignore); unlisted categories keep the default: Critical and Warning counted, others ignored")
 }
 
 func registerConsoleFlags(flagSet *pflag.FlagSet, config *config.Configuration) {
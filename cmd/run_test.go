@@ -611,5 +611,108 @@ var _ = Describe("Run Command", func() {
 				Expect(err.Error()).To(ContainSubstring("authentication-jwt-filepath must be set"))
 			})
 		})
+
+		Context("concern-assessments", func() {
+			// Given concern assessments, one holding commas in quotes
+			// When we parse the flags and validate the configuration
+			// Then each concern id should map to its whole text
+			It("should parse id=text pairs", func() {
+				// Arrange
+				cmd := NewRunCommand(cfg)
+
+				// Act
+				err := cmd.ParseFlags([]string{"--concern-assessments", `vmware.cbt=See https://wiki.example.com/cbt,"vmware.rdm=Ask storage, then networking"`})
+
+				// Assert
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cfg.Agent.ConcernAssessments).To(Equal(map[string]string{
+					"vmware.cbt": "See https://wiki.example.com/cbt",
+					"vmware.rdm": "Ask storage, then networking",
+				}))
+				Expect(validateConfiguration(cfg)).To(Succeed())
+			})
+
+			// Given a concern assessment with an empty text
+			// When we validate the configuration
+			// Then it should fail naming the flag
+			It("should fail with an empty text", func() {
+				// Arrange
+				cfg.Agent.ConcernAssessments = map[string]string{"vmware.cbt": " "}
+
+				// Act
+				err := validateConfiguration(cfg)
+
+				// Assert
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid concern-assessments"))
+			})
+		})
+
+		Context("collection-schedule validation", func() {
+			// Given a cron expression and a duration as the collection schedule
+			// When we validate the configuration
+			// Then validation should pass for both
+			It("should accept a cron expression or a duration", func() {
+				for _, schedule := range []string{"0 2 * * *", "24h"} {
+					// Arrange
+					cfg.Agent.CollectionSchedule = schedule
+
+					// Act
+					err := validateConfiguration(cfg)
+
+					// Assert
+					Expect(err).ToNot(HaveOccurred(), schedule)
+				}
+			})
+
+			// Given a collection schedule that is neither cron nor a duration
+			// When we validate the configuration
+			// Then it should fail with appropriate error
+			It("should fail with an invalid schedule", func() {
+				// Arrange
+				cfg.Agent.CollectionSchedule = "every day"
+
+				// Act
+				err := validateConfiguration(cfg)
+
+				// Assert
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid collection-schedule"))
+			})
+		})
+
+		Context("inventory-retention validation", func() {
+			// Given a negative inventory retention
+			// When we validate the configuration
+			// Then it should fail with appropriate error
+			It("should fail with a negative retention", func() {
+				// Arrange
+				cfg.Agent.InventoryRetention = -1
+
+				// Act
+				err := validateConfiguration(cfg)
+
+				// Assert
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid inventory-retention"))
+			})
+		})
+
+		Context("collection-concurrency validation", func() {
+			// Given a collection concurrency of zero
+			// When we validate the configuration
+			// Then it should fail with appropriate error
+			It("should fail without any concurrency", func() {
+				// Arrange
+				cfg.Agent.CollectionConcurrency = 0
+
+				// Act
+				err := validateConfiguration(cfg)
+
+				// Assert
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid collection-concurrency"))
+			})
+		})
 	})
 })
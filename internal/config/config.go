@@ -1,6 +1,9 @@
 package config
 
-import "time"
+import (
+	"reflect"
+	"time"
+)
 
 type ServerModeType string
 
@@ -22,22 +25,44 @@ type Configuration struct {
 }
 
 type Server struct {
-	ServerMode    string `debugmap:"visible" default:"dev"`
-	HTTPPort      int    `debugmap:"visible" default:"8000"`
-	StaticsFolder string `debugmap:"visible"`
+	ServerMode              string        `debugmap:"visible" default:"dev"`
+	HTTPPort                int           `debugmap:"visible" default:"8000"`
+	StaticsFolder           string        `debugmap:"visible"`
+	RequestTimeout          time.Duration `debugmap:"visible" default:"60s"`
+	DatabaseDownloadEnabled bool          `debugmap:"visible" default:"false"`
+	AllowedOrigins          []string      `debugmap:"visible"`
 }
 
 type Agent struct {
-	Mode                string        `debugmap:"visible" default:"disconnected"`
-	ID                  string        `debugmap:"visible"`
-	SourceID            string        `debugmap:"visible"`
-	Version             string        `debugmap:"visible" default:"v0.0.0"`
-	GitCommit           string        `debugmap:"visible" default:"unknown"`
-	UIGitCommit         string        `debugmap:"visible" default:"unknown"`
-	DataFolder          string        `debugmap:"visible"`
-	OpaPoliciesFolder   string        `debugmap:"visible"`
-	UpdateInterval      time.Duration `debugmap:"visible" default:"5s"`
-	LegacyStatusEnabled bool          `debugmap:"visible" default:"true"`
+	Mode                     string            `debugmap:"visible" default:"disconnected"`
+	ID                       string            `debugmap:"visible"`
+	SourceID                 string            `debugmap:"visible"`
+	Version                  string            `debugmap:"visible" default:"v0.0.0"`
+	GitCommit                string            `debugmap:"visible" default:"unknown"`
+	UIGitCommit              string            `debugmap:"visible" default:"unknown"`
+	DataFolder               string            `debugmap:"visible"`
+	OpaPoliciesFolder        string            `debugmap:"visible"`
+	UpdateInterval           time.Duration     `debugmap:"visible" default:"5s"`
+	LegacyStatusEnabled      bool              `debugmap:"visible" default:"true"`
+	CollectionHistoryEnabled bool              `debugmap:"visible" default:"false"`
+	CollectionSchedule       string            `debugmap:"visible"`
+	CredentialsSecretPath    string            `debugmap:"visible"`
+	SuppressedConcerns       []string          `debugmap:"visible"`
+	ConcernAssessments       map[string]string `debugmap:"visible"`
+	MaxInventoryBytes        int               `debugmap:"visible" default:"0"`
+	PushRedactedFields       []string          `debugmap:"visible"`
+	MaxPageSize              int               `debugmap:"visible" default:"100"`
+	MaxFilterLength          int               `debugmap:"visible" default:"4096"`
+	VCenterSessionTTL        time.Duration     `debugmap:"visible" default:"5m"`
+	InspectionWorkers        int               `debugmap:"visible" default:"5"`
+	CollectionConcurrency    int               `debugmap:"visible" default:"2"`
+	InventoryPushTimeoutMin  time.Duration     `debugmap:"visible" default:"30s"`
+	InventoryPushTimeoutMax  time.Duration     `debugmap:"visible" default:"10m"`
+	InventoryRetention       int               `debugmap:"visible" default:"1"`
+	DiskSizeTiers            []string          `debugmap:"visible"`
+	CPUTiers                 []int             `debugmap:"visible"`
+	MemoryTiers              []int             `debugmap:"visible"`
+	ConcernCategories        map[string]string `debugmap:"visible"`
 }
 
 type Console struct {
@@ -48,3 +73,23 @@ type Authentication struct {
 	Enabled     bool   `debugmap:"visible" default:"true"`
 	JWTFilePath string `debugmap:"visible"`
 }
+
+// Redacted returns a copy of the agent configuration that is safe to expose
+// over the API. Any field not tagged `debugmap:"visible"` is reset to its zero
+// value, so secrets added later stay out of the response by default.
+func (a Agent) Redacted() Agent {
+	redact(reflect.ValueOf(&a).Elem())
+	return a
+}
+
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("debugmap") == "visible" {
+			continue
+		}
+		if f := v.Field(i); f.CanSet() {
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Redaction", func() {
+	// Given a struct mixing visible, sensitive and untagged fields
+	// When it is redacted
+	// Then only the visible fields should keep their values
+	It("should clear fields not tagged as visible", func() {
+		// Arrange
+		s := struct {
+			Name   string `debugmap:"visible"`
+			Token  string `debugmap:"sensitive"`
+			Secret string `debugmap:"hidden"`
+			Plain  string
+		}{Name: "agent", Token: "t0k3n", Secret: "s3cr3t", Plain: "plain"}
+
+		// Act
+		redact(reflect.ValueOf(&s).Elem())
+
+		// Assert
+		Expect(s.Name).To(Equal("agent"))
+		Expect(s.Token).To(BeEmpty())
+		Expect(s.Secret).To(BeEmpty())
+		Expect(s.Plain).To(BeEmpty())
+	})
+
+	// Given a fully populated agent configuration
+	// When it is redacted
+	// Then every visible field should be kept
+	It("should keep the visible agent fields", func() {
+		// Arrange
+		a := Agent{
+			Mode:                "connected",
+			ID:                  "agent-id",
+			SourceID:            "source-id",
+			Version:             "v2.0.0",
+			GitCommit:           "abc1234",
+			UIGitCommit:         "def5678",
+			DataFolder:          "/data",
+			OpaPoliciesFolder:   "/policies",
+			UpdateInterval:      5 * time.Second,
+			LegacyStatusEnabled: true,
+		}
+
+		// Act
+		r := a.Redacted()
+
+		// Assert
+		Expect(r).To(Equal(a))
+	})
+})
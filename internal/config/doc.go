@@ -21,6 +21,11 @@
 //	│ ServerMode       │ "dev"   │ Server mode: "prod" or "dev"           │
 //	│ HTTPPort         │ 8000    │ HTTP server listen port                │
 //	│ StaticsFolder    │ ""      │ Path to static files for UI            │
+//	│ RequestTimeout   │ 60s     │ Per-request API timeout, 0 disables it │
+//	│ DatabaseDownload │ false   │ Serve the DuckDB file on GET /debug/db │
+//	│ Enabled          │         │ for support bundles                    │
+//	│ AllowedOrigins   │ []      │ Origins allowed to call the API        │
+//	│                  │         │ cross-origin (CORS), "*" for any       │
 //	└──────────────────┴─────────┴────────────────────────────────────────┘
 //
 // Server modes:
@@ -29,18 +34,62 @@
 //
 // # Agent Configuration
 //
-//	┌─────────────────────┬────────────────┬──────────────────────────────────────┐
-//	│ Field               │ Default        │ Description                          │
-//	├─────────────────────┼────────────────┼──────────────────────────────────────┤
-//	│ Mode                │ "disconnected" │ Initial agent mode                   │
-//	│ ID                  │ ""             │ Agent UUID (required)                │
-//	│ SourceID            │ ""             │ Source UUID (required)               │
-//	│ Version             │ "v0.0.0"       │ Agent version string                 │
-//	│ DataFolder          │ ""             │ Path to data storage (DuckDB)        │
-//	│ OpaPoliciesFolder   │ ""             │ Path to OPA policy files             │
-//	│ UpdateInterval      │ 5s             │ Console update frequency             │
-//	│ LegacyStatusEnabled │ true           │ Use v1 agent status values           │
-//	└─────────────────────┴────────────────┴──────────────────────────────────────┘
+//	┌──────────────────────────┬────────────────┬──────────────────────────────────────┐
+//	│ Field                    │ Default        │ Description                          │
+//	├──────────────────────────┼────────────────┼──────────────────────────────────────┤
+//	│ Mode                     │ "disconnected" │ Initial agent mode                   │
+//	│ ID                       │ ""             │ Agent UUID (required)                │
+//	│ SourceID                 │ ""             │ Source UUID (required)               │
+//	│ Version                  │ "v0.0.0"       │ Agent version string                 │
+//	│ DataFolder               │ ""             │ Path to data storage (DuckDB)        │
+//	│ OpaPoliciesFolder        │ ""             │ Path to OPA policy files             │
+//	│ UpdateInterval           │ 5s             │ Console update frequency             │
+//	│ LegacyStatusEnabled      │ true           │ Use v1 agent status values           │
+//	│ CollectionHistoryEnabled │ false          │ Spool collection results to disk     │
+//	│                          │                │ while disconnected                   │
+//	│ CollectionSchedule       │ ""             │ Cron expression or duration for      │
+//	│                          │                │ periodic recollection (off if empty) │
+//	│ CredentialsSecretPath    │ ""             │ File with the secret used to encrypt │
+//	│                          │                │ stored vCenter credentials           │
+//	│ SuppressedConcerns       │ []             │ Concern IDs left out of issue counts │
+//	│                          │                │ and inventory migration issues       │
+//	│ ConcernAssessments       │ {}             │ Text appended to the assessment of   │
+//	│                          │                │ a concern, by concern ID             │
+//	│ MaxInventoryBytes        │ 0              │ Largest inventory pushed to the      │
+//	│                          │                │ console, in bytes (0: no limit)      │
+//	│ PushRedactedFields       │ []             │ Inventory field groups hashed before │
+//	│                          │                │ the console push (e.g. cluster-names)│
+//	│ MaxPageSize              │ 100            │ Largest page size served by list     │
+//	│                          │                │ endpoints; larger requests clamped   │
+//	│ MaxFilterLength          │ 4096           │ Longest filter expression accepted,  │
+//	│                          │                │ in bytes; longer ones get 413        │
+//	│ VCenterSessionTTL        │ 5m             │ How long an idle vCenter session is  │
+//	│                          │                │ kept for reuse (0: no reuse)         │
+//	│ InspectionWorkers        │ 5              │ VMs inspected in parallel            │
+//	│ CollectionConcurrency    │ 2              │ vCenter object lists (VM snapshots  │
+//	│                          │                │ and creation dates) in parallel      │
+//	│ InventoryPushTimeoutMin  │ 30s            │ Shortest deadline of one inventory   │
+//	│                          │                │ push to the console                  │
+//	│ InventoryPushTimeoutMax  │ 10m            │ Longest deadline of one inventory    │
+//	│                          │                │ push; between the two the deadline   │
+//	│                          │                │ grows with the inventory size        │
+//	│                          │                │ (0: no deadline)                     │
+//	│ InventoryRetention       │ 1              │ Previous inventories kept besides    │
+//	│                          │                │ the current one (GET /inventory/     │
+//	│                          │                │ history)                             │
+//	│ DiskSizeTiers            │ (empty)        │ diskSizeTier buckets as label=maxTB, │
+//	│                          │                │ last one unbounded (empty: the       │
+//	│                          │                │ parser's 0-100GiB ... 20+TiB)        │
+//	│ CPUTiers                 │ (empty)        │ distributionByCpuTier upper bounds   │
+//	│                          │                │ (empty: 4,8,16,32)                   │
+//	│ MemoryTiers              │ (empty)        │ distributionByMemoryTier upper       │
+//	│                          │                │ bounds in GB (empty: 4,16,32,64,128, │
+//	│                          │                │ 256)                                 │
+//	│ ConcernCategories        │ {}             │ Concern category to inventory bucket │
+//	│                          │                │ (critical, warning or ignore), e.g.  │
+//	│                          │                │ Info=warning (unlisted: Critical and │
+//	│                          │                │ Warning counted, others ignored)     │
+//	└──────────────────────────┴────────────────┴──────────────────────────────────────┘
 //
 // Agent modes:
 //   - connected: Agent sends updates to console.redhat.com
@@ -108,4 +157,9 @@
 //
 // This produces a map suitable for structured logging without exposing
 // sensitive values (if any were marked with `debugmap:"hidden"`).
+//
+// The same tags drive Agent.Redacted(), which returns a copy of the agent
+// configuration with every field not tagged `debugmap:"visible"` cleared.
+// It backs the GET /config endpoint, so new secret fields must not be
+// tagged visible.
 package config
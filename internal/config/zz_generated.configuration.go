@@ -138,6 +138,9 @@ func (s *Server) ToOption() ServerOption {
 		to.ServerMode = s.ServerMode
 		to.HTTPPort = s.HTTPPort
 		to.StaticsFolder = s.StaticsFolder
+		to.RequestTimeout = s.RequestTimeout
+		to.DatabaseDownloadEnabled = s.DatabaseDownloadEnabled
+		to.AllowedOrigins = s.AllowedOrigins
 	}
 }
 
@@ -147,6 +150,9 @@ func (s *Server) DebugMap() map[string]any {
 	debugMap["ServerMode"] = helpers.DebugValue(s.ServerMode, false)
 	debugMap["HTTPPort"] = helpers.DebugValue(s.HTTPPort, false)
 	debugMap["StaticsFolder"] = helpers.DebugValue(s.StaticsFolder, false)
+	debugMap["RequestTimeout"] = helpers.DebugValue(s.RequestTimeout, false)
+	debugMap["DatabaseDownloadEnabled"] = helpers.DebugValue(s.DatabaseDownloadEnabled, false)
+	debugMap["AllowedOrigins"] = helpers.DebugValue(s.AllowedOrigins, false)
 	return debugMap
 }
 
@@ -187,6 +193,34 @@ func WithStaticsFolder(staticsFolder string) ServerOption {
 	}
 }
 
+// WithRequestTimeout returns an option that can set RequestTimeout on a Server
+func WithRequestTimeout(requestTimeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.RequestTimeout = requestTimeout
+	}
+}
+
+// WithDatabaseDownloadEnabled returns an option that can set DatabaseDownloadEnabled on a Server
+func WithDatabaseDownloadEnabled(databaseDownloadEnabled bool) ServerOption {
+	return func(s *Server) {
+		s.DatabaseDownloadEnabled = databaseDownloadEnabled
+	}
+}
+
+// WithAllowedOrigins returns an option that can append AllowedOriginss to Server.AllowedOrigins
+func WithAllowedOrigins(allowedOrigins string) ServerOption {
+	return func(s *Server) {
+		s.AllowedOrigins = append(s.AllowedOrigins, allowedOrigins)
+	}
+}
+
+// SetAllowedOrigins returns an option that can set AllowedOrigins on a Server
+func SetAllowedOrigins(allowedOrigins []string) ServerOption {
+	return func(s *Server) {
+		s.AllowedOrigins = allowedOrigins
+	}
+}
+
 type AgentOption func(a *Agent)
 
 // NewAgentWithOptions creates a new Agent with the passed in options set
@@ -221,6 +255,25 @@ func (a *Agent) ToOption() AgentOption {
 		to.OpaPoliciesFolder = a.OpaPoliciesFolder
 		to.UpdateInterval = a.UpdateInterval
 		to.LegacyStatusEnabled = a.LegacyStatusEnabled
+		to.CollectionHistoryEnabled = a.CollectionHistoryEnabled
+		to.CollectionSchedule = a.CollectionSchedule
+		to.CredentialsSecretPath = a.CredentialsSecretPath
+		to.SuppressedConcerns = a.SuppressedConcerns
+		to.ConcernAssessments = a.ConcernAssessments
+		to.MaxInventoryBytes = a.MaxInventoryBytes
+		to.PushRedactedFields = a.PushRedactedFields
+		to.MaxPageSize = a.MaxPageSize
+		to.MaxFilterLength = a.MaxFilterLength
+		to.VCenterSessionTTL = a.VCenterSessionTTL
+		to.InspectionWorkers = a.InspectionWorkers
+		to.CollectionConcurrency = a.CollectionConcurrency
+		to.InventoryPushTimeoutMin = a.InventoryPushTimeoutMin
+		to.InventoryPushTimeoutMax = a.InventoryPushTimeoutMax
+		to.InventoryRetention = a.InventoryRetention
+		to.DiskSizeTiers = a.DiskSizeTiers
+		to.CPUTiers = a.CPUTiers
+		to.MemoryTiers = a.MemoryTiers
+		to.ConcernCategories = a.ConcernCategories
 	}
 }
 
@@ -237,6 +290,25 @@ func (a *Agent) DebugMap() map[string]any {
 	debugMap["OpaPoliciesFolder"] = helpers.DebugValue(a.OpaPoliciesFolder, false)
 	debugMap["UpdateInterval"] = helpers.DebugValue(a.UpdateInterval, false)
 	debugMap["LegacyStatusEnabled"] = helpers.DebugValue(a.LegacyStatusEnabled, false)
+	debugMap["CollectionHistoryEnabled"] = helpers.DebugValue(a.CollectionHistoryEnabled, false)
+	debugMap["CollectionSchedule"] = helpers.DebugValue(a.CollectionSchedule, false)
+	debugMap["CredentialsSecretPath"] = helpers.DebugValue(a.CredentialsSecretPath, false)
+	debugMap["SuppressedConcerns"] = helpers.DebugValue(a.SuppressedConcerns, false)
+	debugMap["ConcernAssessments"] = helpers.DebugValue(a.ConcernAssessments, false)
+	debugMap["MaxInventoryBytes"] = helpers.DebugValue(a.MaxInventoryBytes, false)
+	debugMap["PushRedactedFields"] = helpers.DebugValue(a.PushRedactedFields, false)
+	debugMap["MaxPageSize"] = helpers.DebugValue(a.MaxPageSize, false)
+	debugMap["MaxFilterLength"] = helpers.DebugValue(a.MaxFilterLength, false)
+	debugMap["VCenterSessionTTL"] = helpers.DebugValue(a.VCenterSessionTTL, false)
+	debugMap["InspectionWorkers"] = helpers.DebugValue(a.InspectionWorkers, false)
+	debugMap["CollectionConcurrency"] = helpers.DebugValue(a.CollectionConcurrency, false)
+	debugMap["InventoryPushTimeoutMin"] = helpers.DebugValue(a.InventoryPushTimeoutMin, false)
+	debugMap["InventoryPushTimeoutMax"] = helpers.DebugValue(a.InventoryPushTimeoutMax, false)
+	debugMap["InventoryRetention"] = helpers.DebugValue(a.InventoryRetention, false)
+	debugMap["DiskSizeTiers"] = helpers.DebugValue(a.DiskSizeTiers, false)
+	debugMap["CPUTiers"] = helpers.DebugValue(a.CPUTiers, false)
+	debugMap["MemoryTiers"] = helpers.DebugValue(a.MemoryTiers, false)
+	debugMap["ConcernCategories"] = helpers.DebugValue(a.ConcernCategories, false)
 	return debugMap
 }
 
@@ -326,6 +398,188 @@ func WithLegacyStatusEnabled(legacyStatusEnabled bool) AgentOption {
 	}
 }
 
+// WithCollectionHistoryEnabled returns an option that can set CollectionHistoryEnabled on a Agent
+func WithCollectionHistoryEnabled(collectionHistoryEnabled bool) AgentOption {
+	return func(a *Agent) {
+		a.CollectionHistoryEnabled = collectionHistoryEnabled
+	}
+}
+
+// WithCollectionSchedule returns an option that can set CollectionSchedule on a Agent
+func WithCollectionSchedule(collectionSchedule string) AgentOption {
+	return func(a *Agent) {
+		a.CollectionSchedule = collectionSchedule
+	}
+}
+
+// WithCredentialsSecretPath returns an option that can set CredentialsSecretPath on a Agent
+func WithCredentialsSecretPath(credentialsSecretPath string) AgentOption {
+	return func(a *Agent) {
+		a.CredentialsSecretPath = credentialsSecretPath
+	}
+}
+
+// WithSuppressedConcerns returns an option that can append SuppressedConcernss to Agent.SuppressedConcerns
+func WithSuppressedConcerns(suppressedConcerns string) AgentOption {
+	return func(a *Agent) {
+		a.SuppressedConcerns = append(a.SuppressedConcerns, suppressedConcerns)
+	}
+}
+
+// SetSuppressedConcerns returns an option that can set SuppressedConcerns on a Agent
+func SetSuppressedConcerns(suppressedConcerns []string) AgentOption {
+	return func(a *Agent) {
+		a.SuppressedConcerns = suppressedConcerns
+	}
+}
+
+// WithConcernAssessments returns an option that can append ConcernAssessmentss to Agent.ConcernAssessments
+func WithConcernAssessments(key string, value string) AgentOption {
+	return func(a *Agent) {
+		a.ConcernAssessments[key] = value
+	}
+}
+
+// SetConcernAssessments returns an option that can set ConcernAssessments on a Agent
+func SetConcernAssessments(concernAssessments map[string]string) AgentOption {
+	return func(a *Agent) {
+		a.ConcernAssessments = concernAssessments
+	}
+}
+
+// WithMaxInventoryBytes returns an option that can set MaxInventoryBytes on a Agent
+func WithMaxInventoryBytes(maxInventoryBytes int) AgentOption {
+	return func(a *Agent) {
+		a.MaxInventoryBytes = maxInventoryBytes
+	}
+}
+
+// WithPushRedactedFields returns an option that can append PushRedactedFieldss to Agent.PushRedactedFields
+func WithPushRedactedFields(pushRedactedFields string) AgentOption {
+	return func(a *Agent) {
+		a.PushRedactedFields = append(a.PushRedactedFields, pushRedactedFields)
+	}
+}
+
+// SetPushRedactedFields returns an option that can set PushRedactedFields on a Agent
+func SetPushRedactedFields(pushRedactedFields []string) AgentOption {
+	return func(a *Agent) {
+		a.PushRedactedFields = pushRedactedFields
+	}
+}
+
+// WithMaxPageSize returns an option that can set MaxPageSize on a Agent
+func WithMaxPageSize(maxPageSize int) AgentOption {
+	return func(a *Agent) {
+		a.MaxPageSize = maxPageSize
+	}
+}
+
+// WithMaxFilterLength returns an option that can set MaxFilterLength on a Agent
+func WithMaxFilterLength(maxFilterLength int) AgentOption {
+	return func(a *Agent) {
+		a.MaxFilterLength = maxFilterLength
+	}
+}
+
+// WithVCenterSessionTTL returns an option that can set VCenterSessionTTL on a Agent
+func WithVCenterSessionTTL(vCenterSessionTTL time.Duration) AgentOption {
+	return func(a *Agent) {
+		a.VCenterSessionTTL = vCenterSessionTTL
+	}
+}
+
+// WithInspectionWorkers returns an option that can set InspectionWorkers on a Agent
+func WithInspectionWorkers(inspectionWorkers int) AgentOption {
+	return func(a *Agent) {
+		a.InspectionWorkers = inspectionWorkers
+	}
+}
+
+// WithCollectionConcurrency returns an option that can set CollectionConcurrency on a Agent
+func WithCollectionConcurrency(collectionConcurrency int) AgentOption {
+	return func(a *Agent) {
+		a.CollectionConcurrency = collectionConcurrency
+	}
+}
+
+// WithInventoryPushTimeoutMin returns an option that can set InventoryPushTimeoutMin on a Agent
+func WithInventoryPushTimeoutMin(inventoryPushTimeoutMin time.Duration) AgentOption {
+	return func(a *Agent) {
+		a.InventoryPushTimeoutMin = inventoryPushTimeoutMin
+	}
+}
+
+// WithInventoryPushTimeoutMax returns an option that can set InventoryPushTimeoutMax on a Agent
+func WithInventoryPushTimeoutMax(inventoryPushTimeoutMax time.Duration) AgentOption {
+	return func(a *Agent) {
+		a.InventoryPushTimeoutMax = inventoryPushTimeoutMax
+	}
+}
+
+// WithInventoryRetention returns an option that can set InventoryRetention on a Agent
+func WithInventoryRetention(inventoryRetention int) AgentOption {
+	return func(a *Agent) {
+		a.InventoryRetention = inventoryRetention
+	}
+}
+
+// WithDiskSizeTiers returns an option that can append DiskSizeTierss to Agent.DiskSizeTiers
+func WithDiskSizeTiers(diskSizeTiers string) AgentOption {
+	return func(a *Agent) {
+		a.DiskSizeTiers = append(a.DiskSizeTiers, diskSizeTiers)
+	}
+}
+
+// SetDiskSizeTiers returns an option that can set DiskSizeTiers on a Agent
+func SetDiskSizeTiers(diskSizeTiers []string) AgentOption {
+	return func(a *Agent) {
+		a.DiskSizeTiers = diskSizeTiers
+	}
+}
+
+// WithCPUTiers returns an option that can append CPUTierss to Agent.CPUTiers
+func WithCPUTiers(cPUTiers int) AgentOption {
+	return func(a *Agent) {
+		a.CPUTiers = append(a.CPUTiers, cPUTiers)
+	}
+}
+
+// SetCPUTiers returns an option that can set CPUTiers on a Agent
+func SetCPUTiers(cPUTiers []int) AgentOption {
+	return func(a *Agent) {
+		a.CPUTiers = cPUTiers
+	}
+}
+
+// WithMemoryTiers returns an option that can append MemoryTierss to Agent.MemoryTiers
+func WithMemoryTiers(memoryTiers int) AgentOption {
+	return func(a *Agent) {
+		a.MemoryTiers = append(a.MemoryTiers, memoryTiers)
+	}
+}
+
+// SetMemoryTiers returns an option that can set MemoryTiers on a Agent
+func SetMemoryTiers(memoryTiers []int) AgentOption {
+	return func(a *Agent) {
+		a.MemoryTiers = memoryTiers
+	}
+}
+
+// WithConcernCategories returns an option that can append ConcernCategoriess to Agent.ConcernCategories
+func WithConcernCategories(key string, value string) AgentOption {
+	return func(a *Agent) {
+		a.ConcernCategories[key] = value
+	}
+}
+
+// SetConcernCategories returns an option that can set ConcernCategories on a Agent
+func SetConcernCategories(concernCategories map[string]string) AgentOption {
+	return func(a *Agent) {
+		a.ConcernCategories = concernCategories
+	}
+}
+
 type ConsoleOption func(c *Console)
 
 // NewConsoleWithOptions creates a new Console with the passed in options set
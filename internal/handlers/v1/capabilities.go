@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+)
+
+// apiVersion is the version of the API served under /api/v1.
+const apiVersion = "v1"
+
+// capabilities maps each feature name reported by GET /capabilities to its
+// description. Handler files add their features with registerCapability.
+var capabilities = map[string]string{}
+
+// registerCapability adds a feature to GET /capabilities. Call it from a
+// package-level var in the file implementing the feature, so the feature is
+// listed exactly when it is built in:
+//
+//	var _ = registerCapability("vms.views", "Saved GET /vms queries")
+//
+// Feature names are part of the API: never rename one. Registering the same
+// name twice panics at startup.
+func registerCapability(name, description string) bool {
+	if _, found := capabilities[name]; found {
+		panic(fmt.Sprintf("capability %q registered twice", name))
+	}
+	capabilities[name] = description
+	return true
+}
+
+// GetCapabilities returns the API version and the registered features
+// (GET /capabilities)
+func (h *Handler) GetCapabilities(c *gin.Context) {
+	features := make([]v1.Feature, 0, len(capabilities))
+	for _, name := range slices.Sorted(maps.Keys(capabilities)) {
+		features = append(features, v1.Feature{Name: name, Description: capabilities[name]})
+	}
+
+	c.JSON(http.StatusOK, v1.Capabilities{
+		ApiVersion:   apiVersion,
+		AgentVersion: h.cfg.Agent.Version,
+		Features:     features,
+	})
+}
@@ -0,0 +1,80 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
+)
+
+var _ = Describe("Capabilities Handler", func() {
+	var router *gin.Engine
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		cfg := config.Configuration{}
+		cfg.Agent.Version = "v2.1.0"
+
+		handler := handlers.NewHandler(cfg)
+		router = gin.New()
+		router.GET("/capabilities", handler.GetCapabilities)
+	})
+
+	// Given an agent built with the registered features
+	// When we request its capabilities
+	// Then the document should be valid JSON listing the API and agent
+	// versions and the known features, sorted by name
+	It("should list the registered features", func() {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/capabilities", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(json.Valid(w.Body.Bytes())).To(BeTrue())
+
+		var caps v1.Capabilities
+		Expect(json.Unmarshal(w.Body.Bytes(), &caps)).To(Succeed())
+		Expect(caps.ApiVersion).To(Equal("v1"))
+		Expect(caps.AgentVersion).To(Equal("v2.1.0"))
+
+		names := make([]string, 0, len(caps.Features))
+		for _, f := range caps.Features {
+			Expect(f.Description).NotTo(BeEmpty(), f.Name)
+			names = append(names, f.Name)
+		}
+		Expect(names).To(ContainElements("events", "export", "vms.views", "vms.filter.nic", "inventory.history", "inspector.pause"))
+		Expect(slices.IsSorted(names)).To(BeTrue())
+		Expect(slices.Compact(slices.Clone(names))).To(HaveLen(len(names)))
+	})
+
+	// Given two requests
+	// When we compare the documents
+	// Then they should be identical, so clients can cache them
+	It("should return a stable document", func() {
+		// Arrange
+		get := func() string {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/capabilities", nil))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			return w.Body.String()
+		}
+
+		// Act
+		first, second := get(), get()
+
+		// Assert
+		Expect(second).To(Equal(first))
+	})
+})
@@ -1,7 +1,10 @@
 package v1
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -10,34 +13,79 @@ import (
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
-// GetCollectorStatus returns the collector status
+var (
+	_ = registerCapability("collector.history", "Past collection runs (GET /collector/history)")
+	_ = registerCapability("collector.logs", "Log lines of the current or last collection (GET /collector/logs)")
+	_ = registerCapability("collector.test-connection", "Check vCenter credentials without collecting (POST /collector/test)")
+	_ = registerCapability("collector.warnings", "Non-fatal warnings of the last collection in GET /collector")
+	_ = registerCapability("concerns.catalog", "Every concern the loaded policies can raise (GET /concerns/catalog)")
+)
+
+const (
+	// maxCollectorWait caps how long GET /collector?wait= may block.
+	maxCollectorWait = 60 * time.Second
+	// defaultCollectorLogLines is the number of lines GET /collector/logs returns by default.
+	defaultCollectorLogLines = 200
+)
+
+// GetCollectorStatus returns the collector status. With wait it long-polls
+// until the status differs from since or the wait elapses.
 // (GET /collector)
-func (h *Handler) GetCollectorStatus(c *gin.Context) {
-	status := h.collectorSrv.GetStatus()
+func (h *Handler) GetCollectorStatus(c *gin.Context, params v1.GetCollectorStatusParams) {
+	if params.Wait == nil {
+		c.JSON(http.StatusOK, v1.NewCollectorStatus(h.collectorSrv.GetStatus()))
+		return
+	}
+
+	wait, err := time.ParseDuration(*params.Wait)
+	if err != nil || wait < 0 {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(fmt.Sprintf("wait must be a non-negative duration such as 30s, got %q", *params.Wait)))
+		return
+	}
+	wait = min(wait, maxCollectorWait)
+
+	var since models.CollectorStateType
+	if params.Since != nil {
+		since = models.CollectorStateType(*params.Since)
+	} else {
+		since = h.collectorSrv.GetStatus().State
+	}
+
+	status := h.collectorSrv.WaitStatus(c.Request.Context(), since, wait)
 	c.JSON(http.StatusOK, v1.NewCollectorStatus(status))
 }
 
-// StartCollector starts inventory collection
+// StartCollector starts inventory collection, or with maxAge recollects an
+// inventory older than maxAge
 // (POST /collector)
 func (h *Handler) StartCollector(c *gin.Context) {
-	var req v1.CollectorStartRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+	if h.rejectInMaintenance(c) {
 		return
 	}
 
-	creds := models.Credentials{
-		URL:      req.Url,
-		Username: req.Username,
-		Password: req.Password,
+	req, err := bindCollectorRequest(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
 	}
 
-	if err := h.collectorSrv.Start(c.Request.Context(), creds); err != nil {
+	creds := collectorCredentials(req)
+	if req.MaxAge == nil {
+		err = h.collectorSrv.Start(c.Request.Context(), creds)
+	} else {
+		maxAge, parseErr := time.ParseDuration(*req.MaxAge)
+		if parseErr != nil || maxAge <= 0 {
+			respondError(c, http.StatusBadRequest, srvErrors.NewFieldValidationError(map[string]string{"maxAge": "must be a positive duration such as 1h"}))
+			return
+		}
+		err = h.collectorSrv.Refresh(c.Request.Context(), creds, maxAge)
+	}
+	if err != nil {
 		if srvErrors.IsOperationInProgressError(err) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			respondError(c, http.StatusConflict, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -45,6 +93,37 @@ func (h *Handler) StartCollector(c *gin.Context) {
 	c.JSON(http.StatusAccepted, v1.NewCollectorStatus(status))
 }
 
+// TestCollectorConnection checks that vCenter is reachable and accepts the
+// credentials, without starting a collection
+// (POST /collector/test)
+func (h *Handler) TestCollectorConnection(c *gin.Context) {
+	req, err := bindCollectorRequest(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	info, err := h.collectorSrv.TestConnection(c.Request.Context(), collectorCredentials(req))
+	if err != nil {
+		if srvErrors.IsValidationError(err) || srvErrors.IsCredentialsError(err) {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		if srvErrors.IsPermissionError(err) {
+			respondError(c, http.StatusForbidden, err)
+			return
+		}
+		if srvErrors.IsVCenterError(err) {
+			respondError(c, http.StatusBadGateway, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewVcenterInfoFromModel(*info))
+}
+
 // StopCollector stops the collection but keeps credentials for retry
 // (DELETE /collector)
 func (h *Handler) StopCollector(c *gin.Context) {
@@ -53,3 +132,79 @@ func (h *Handler) StopCollector(c *gin.Context) {
 	status := h.collectorSrv.GetStatus()
 	c.JSON(http.StatusOK, v1.NewCollectorStatus(status))
 }
+
+// GetCollectorHistory returns the collection outcomes recorded while disconnected
+// (GET /collector/history)
+func (h *Handler) GetCollectorHistory(c *gin.Context) {
+	events, err := h.collectorSrv.History(c.Request.Context())
+	if err != nil {
+		if srvErrors.IsResourceNotFoundError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewCollectionHistoryFromModel(events))
+}
+
+// GetCollectorLogs returns the most recent collection log lines, oldest first
+// (GET /collector/logs)
+func (h *Handler) GetCollectorLogs(c *gin.Context, params v1.GetCollectorLogsParams) {
+	lines := defaultCollectorLogLines
+	if params.Lines != nil {
+		if *params.Lines < 1 {
+			respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(fmt.Sprintf("lines must be at least 1, got %d", *params.Lines)))
+			return
+		}
+		lines = *params.Lines
+	}
+
+	logs := []string{}
+	if h.collectorLogs != nil {
+		logs = append(logs, h.collectorLogs.Lines(lines)...)
+	}
+	c.JSON(http.StatusOK, v1.CollectorLogs{Lines: logs})
+}
+
+// ReloadPolicies reloads the OPA policies from the policies folder
+// (POST /collector/policies/reload)
+func (h *Handler) ReloadPolicies(c *gin.Context) {
+	count, err := h.policySrv.Reload()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.PolicyReloadResponse{Policies: count})
+}
+
+// GetConcernCatalog lists the concerns the loaded OPA policies can raise
+// (GET /concerns/catalog)
+func (h *Handler) GetConcernCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, v1.NewConcernCatalogFromModel(h.policySrv.Catalog()))
+}
+
+// bindCollectorRequest binds the POST /collector and POST /collector/test body.
+// Invalid fields are reported together in a ValidationError keyed by JSON name;
+// url must also be an http or https URL with a host.
+func bindCollectorRequest(c *gin.Context) (v1.CollectorStartRequest, error) {
+	var req v1.CollectorStartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return req, fieldValidationError(err)
+	}
+	if u, err := url.Parse(req.Url); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return req, srvErrors.NewFieldValidationError(map[string]string{"url": "must be an http or https URL with a host"})
+	}
+	return req, nil
+}
+
+func collectorCredentials(req v1.CollectorStartRequest) models.Credentials {
+	return models.Credentials{
+		URL:      req.Url,
+		Username: req.Username,
+		Password: req.Password,
+		Insecure: req.InsecureSkipVerify != nil && *req.InsecureSkipVerify,
+	}
+}
@@ -2,25 +2,37 @@ package v1_test
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
 
 	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
 	"github.com/kubev2v/assisted-migration-agent/internal/config"
 	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/pkg/logger"
+	"github.com/kubev2v/assisted-migration-agent/pkg/work"
+	"github.com/kubev2v/assisted-migration-agent/test"
 )
 
 var _ = Describe("Collector Handlers", func() {
 	var (
 		mockCollector *MockCollectorService
+		mockPolicy    *MockPolicyService
 		handler       *handlers.Handler
 		router        *gin.Engine
 	)
@@ -30,11 +42,33 @@ var _ = Describe("Collector Handlers", func() {
 		mockCollector = &MockCollectorService{
 			StatusResult: models.CollectorStatus{State: models.CollectorStateReady},
 		}
-		handler = handlers.NewHandler(config.Configuration{}).WithCollectorService(mockCollector)
+		mockPolicy = &MockPolicyService{ReloadResult: 3}
+		handler = handlers.NewHandler(config.Configuration{}).
+			WithCollectorService(mockCollector).
+			WithPolicyService(mockPolicy)
 		router = gin.New()
-		router.GET("/collector", handler.GetCollectorStatus)
+		router.GET("/collector", func(c *gin.Context) {
+			var params v1.GetCollectorStatusParams
+			if err := c.ShouldBindQuery(&params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			handler.GetCollectorStatus(c, params)
+		})
 		router.POST("/collector", handler.StartCollector)
 		router.DELETE("/collector", handler.StopCollector)
+		router.GET("/collector/history", handler.GetCollectorHistory)
+		router.POST("/collector/test", handler.TestCollectorConnection)
+		router.POST("/collector/policies/reload", handler.ReloadPolicies)
+		router.GET("/concerns/catalog", handler.GetConcernCatalog)
+		router.GET("/collector/logs", func(c *gin.Context) {
+			var params v1.GetCollectorLogsParams
+			if err := c.ShouldBindQuery(&params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			handler.GetCollectorLogs(c, params)
+		})
 	})
 
 	Describe("GetCollectorStatus", func() {
@@ -101,6 +135,69 @@ var _ = Describe("Collector Handlers", func() {
 			Expect(response.Error).NotTo(BeNil())
 			Expect(*response.Error).To(Equal("connection failed"))
 		})
+
+		// Given a request with wait and since
+		// When we request the collector status
+		// Then it should long-poll the service from since and return its status
+		It("should wait for a change from since", func() {
+			// Arrange
+			mockCollector.WaitResult = &models.CollectorStatus{State: models.CollectorStateCollecting}
+			req := httptest.NewRequest(http.MethodGet, "/collector?wait=30s&since=connecting", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response v1.CollectorStatus
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Status).To(Equal(v1.CollectorStatusStatusCollecting))
+			Expect(mockCollector.WaitSince).To(Equal(models.CollectorStateConnecting))
+			Expect(mockCollector.WaitTimeout).To(Equal(30 * time.Second))
+		})
+
+		It("should wait from the current status when since is omitted", func() {
+			req := httptest.NewRequest(http.MethodGet, "/collector?wait=5s", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockCollector.WaitSince).To(Equal(models.CollectorStateReady))
+		})
+
+		It("should cap the wait at 60s", func() {
+			req := httptest.NewRequest(http.MethodGet, "/collector?wait=10m&since=ready", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockCollector.WaitTimeout).To(Equal(60 * time.Second))
+		})
+
+		It("should not wait without the wait parameter", func() {
+			req := httptest.NewRequest(http.MethodGet, "/collector?since=ready", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockCollector.WaitCallCount).To(BeZero())
+		})
+
+		It("should return 400 for an invalid wait", func() {
+			for _, wait := range []string{"soon", "-1s"} {
+				req := httptest.NewRequest(http.MethodGet, "/collector?wait="+wait, nil)
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest), wait)
+			}
+			Expect(mockCollector.WaitCallCount).To(BeZero())
+		})
 	})
 
 	Describe("StartCollector", func() {
@@ -146,7 +243,9 @@ var _ = Describe("Collector Handlers", func() {
 			var response map[string]any
 			err := json.Unmarshal(w.Body.Bytes(), &response)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(response["error"]).To(ContainSubstring("Url"))
+			Expect(response["error"]).To(Equal("url is required"))
+			Expect(response["code"]).To(Equal("VALIDATION"))
+			Expect(response["fields"]).To(Equal(map[string]any{"url": "is required"}))
 		})
 
 		// Given a request missing the username field
@@ -170,7 +269,8 @@ var _ = Describe("Collector Handlers", func() {
 			Expect(w.Code).To(Equal(http.StatusBadRequest))
 			var response map[string]any
 			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
-			Expect(response["error"]).To(ContainSubstring("Username is required"))
+			Expect(response["code"]).To(Equal("VALIDATION"))
+			Expect(response["fields"]).To(Equal(map[string]any{"username": "is required"}))
 		})
 
 		// Given a request missing the password field
@@ -194,7 +294,8 @@ var _ = Describe("Collector Handlers", func() {
 			Expect(w.Code).To(Equal(http.StatusBadRequest))
 			var response map[string]any
 			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
-			Expect(response["error"]).To(ContainSubstring("Password is required"))
+			Expect(response["code"]).To(Equal("VALIDATION"))
+			Expect(response["fields"]).To(Equal(map[string]any{"password": "is required"}))
 		})
 
 		// Given a request with an invalid URL format
@@ -220,7 +321,101 @@ var _ = Describe("Collector Handlers", func() {
 			var response map[string]any
 			err := json.Unmarshal(w.Body.Bytes(), &response)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(response["error"]).To(ContainSubstring("Url must be a valid URL"))
+			Expect(response["code"]).To(Equal("VALIDATION"))
+			Expect(response["fields"]).To(Equal(map[string]any{"url": "must be a valid URL"}))
+		})
+
+		// Given a request missing every field
+		// When we try to start the collector
+		// Then each field should get its own message
+		It("should report every missing field at once", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/collector", bytes.NewReader([]byte(`{}`)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			var response map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["error"]).To(Equal("password is required; url is required; username is required"))
+			Expect(response["code"]).To(Equal("VALIDATION"))
+			Expect(response["fields"]).To(Equal(map[string]any{
+				"url":      "is required",
+				"username": "is required",
+				"password": "is required",
+			}))
+		})
+
+		// Given URLs without an http(s) scheme or a host
+		// When we try to start the collector
+		// Then url should be reported invalid and no collection started
+		It("should reject a URL without an http scheme or a host", func() {
+			for _, u := range []string{"ftp://vcenter.example.com", "https://", "mailto:admin@example.com"} {
+				// Arrange
+				bodyBytes, _ := json.Marshal(v1.CollectorStartRequest{Url: u, Username: "admin", Password: "secret"})
+				req := httptest.NewRequest(http.MethodPost, "/collector", bytes.NewReader(bodyBytes))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				Expect(w.Code).To(Equal(http.StatusBadRequest), u)
+				var response map[string]any
+				Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+				Expect(response["code"]).To(Equal("VALIDATION"))
+				Expect(response["fields"]).To(HaveKey("url"), u)
+			}
+			Expect(mockCollector.StartCallCount).To(BeZero())
+		})
+
+		// Given a request missing a field and a client asking for problem details
+		// When we try to start the collector
+		// Then the problem body should carry the code and fields
+		It("should include the field errors in a problem response", func() {
+			// Arrange
+			bodyBytes, _ := json.Marshal(v1.CollectorStartRequest{Url: "https://vcenter.example.com", Username: "admin"})
+			req := httptest.NewRequest(http.MethodPost, "/collector", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", "application/problem+json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(w.Header().Get("Content-Type")).To(HavePrefix("application/problem+json"))
+			var response map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["type"]).To(Equal("urn:assisted-migration-agent:error:validation"))
+			Expect(response["code"]).To(Equal("VALIDATION"))
+			Expect(response["fields"]).To(Equal(map[string]any{"password": "is required"}))
+		})
+
+		// Given a body that is not JSON
+		// When we try to start the collector
+		// Then it should return 400 without field errors
+		It("should return a plain validation error for a malformed body", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/collector", bytes.NewReader([]byte(`{`)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			var response map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["error"]).To(Equal("invalid request body"))
+			Expect(response).NotTo(HaveKey("fields"))
 		})
 
 		// Given a request with valid credentials
@@ -297,6 +492,73 @@ var _ = Describe("Collector Handlers", func() {
 			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
 			Expect(response["error"]).To(Equal("unexpected error"))
 		})
+
+		// Given a request with a freshness window and a collected inventory
+		// When we start the collector
+		// Then it should refresh with that window and return when the
+		// inventory was collected
+		It("should refresh the inventory when maxAge is set", func() {
+			// Arrange
+			collectedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+			mockCollector.StatusResult = models.CollectorStatus{State: models.CollectorStateCollected, CollectedAt: collectedAt}
+			maxAge := "90m"
+			body := v1.CollectorStartRequest{
+				Url:      "https://vcenter.example.com",
+				Username: "admin",
+				Password: "secret",
+				MaxAge:   &maxAge,
+			}
+			bodyBytes, _ := json.Marshal(body)
+			req := httptest.NewRequest(http.MethodPost, "/collector", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusAccepted))
+			Expect(mockCollector.StartCallCount).To(BeZero())
+			Expect(mockCollector.RefreshCallCount).To(Equal(1))
+			Expect(mockCollector.RefreshMaxAge).To(Equal(90 * time.Minute))
+			var response v1.CollectorStatus
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Status).To(Equal(v1.CollectorStatusStatusCollected))
+			Expect(response.CollectedAt).NotTo(BeNil())
+			Expect(*response.CollectedAt).To(BeTemporally("==", collectedAt))
+		})
+
+		// Given a request with a malformed or non-positive freshness window
+		// When we start the collector
+		// Then it should return 400 naming the maxAge field
+		DescribeTable("should return 400 for an invalid maxAge",
+			func(maxAge string) {
+				// Arrange
+				body := v1.CollectorStartRequest{
+					Url:      "https://vcenter.example.com",
+					Username: "admin",
+					Password: "secret",
+					MaxAge:   &maxAge,
+				}
+				bodyBytes, _ := json.Marshal(body)
+				req := httptest.NewRequest(http.MethodPost, "/collector", bytes.NewReader(bodyBytes))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(mockCollector.RefreshCallCount).To(BeZero())
+				var response map[string]any
+				Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+				Expect(response["fields"]).To(HaveKey("maxAge"))
+			},
+			Entry("malformed", "an hour"),
+			Entry("zero", "0s"),
+			Entry("negative", "-1h"),
+		)
 	})
 
 	Describe("StopCollector", func() {
@@ -319,4 +581,498 @@ var _ = Describe("Collector Handlers", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Describe("TestCollectorConnection", func() {
+		post := func(body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/collector/test", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+		validBody := `{"url": "https://vcenter.example.com", "username": "admin", "password": "secret"}`
+
+		// Given a reachable vCenter that accepts the credentials
+		// When we test the connection
+		// Then it should return 200 with the vCenter information and not start a collection
+		It("should return vCenter information", func() {
+			// Arrange
+			mockCollector.TestResult = &models.VCenterInfo{
+				Product:    "VMware vCenter Server 8.0.2 build-22385739",
+				Version:    "8.0.2",
+				Build:      "22385739",
+				APIVersion: "8.0.2.0",
+			}
+
+			// Act
+			w := post(validBody)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response v1.VcenterInfo
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Version).To(Equal("8.0.2"))
+			Expect(response.ApiVersion).To(Equal("8.0.2.0"))
+			Expect(mockCollector.TestCreds.Username).To(Equal("admin"))
+			Expect(mockCollector.TestCreds.Insecure).To(BeFalse())
+			Expect(mockCollector.StartCallCount).To(BeZero())
+			Expect(w.Body.String()).NotTo(ContainSubstring("secret"))
+		})
+
+		// Given vCenter rejects the credentials
+		// When we test the connection
+		// Then it should return 400
+		It("should return 400 for rejected credentials", func() {
+			// Arrange
+			mockCollector.TestError = srvErrors.NewCredentialsError()
+
+			// Act
+			w := post(validBody)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(w.Body.String()).To(ContainSubstring("invalid credentials"))
+		})
+
+		// Given vCenter cannot be reached
+		// When we test the connection
+		// Then it should return 502
+		It("should return 502 when vCenter is unreachable", func() {
+			// Arrange
+			mockCollector.TestError = srvErrors.NewVCenterError(errors.New("dial tcp: connection refused"))
+
+			// Act
+			w := post(validBody)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadGateway))
+			Expect(w.Body.String()).To(ContainSubstring("connection refused"))
+		})
+
+		// Given vCenter presents a certificate that is not trusted
+		// When we test the connection
+		// Then it should return 502 with a certificate message
+		It("should return 502 when the vCenter certificate is not trusted", func() {
+			// Arrange
+			mockCollector.TestError = srvErrors.NewVCenterError(errors.New("tls: failed to verify certificate: x509: certificate signed by unknown authority"))
+
+			// Act
+			w := post(validBody)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadGateway))
+			Expect(w.Body.String()).To(ContainSubstring("vCenter certificate not trusted"))
+		})
+
+		// Given vCenter accepts the login but denies the account a privilege
+		// When we test the connection
+		// Then it should return 403 naming the privilege
+		It("should return 403 when the account lacks a privilege", func() {
+			// Arrange
+			mockCollector.TestError = srvErrors.NewVCenterError(errors.New("NoPermission: missing System.Read"))
+
+			// Act
+			w := post(validBody)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(w.Body.String()).To(ContainSubstring("vCenter permission denied"))
+		})
+
+		// Given a request that opts into insecure TLS
+		// When we test the connection
+		// Then the service should receive the insecure flag
+		It("should pass the insecure flag to the service", func() {
+			// Arrange
+			mockCollector.TestResult = &models.VCenterInfo{Version: "8.0.2"}
+
+			// Act
+			w := post(`{"url": "https://vcenter.example.com", "username": "admin", "password": "secret", "insecureSkipVerify": true}`)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockCollector.TestCreds.Insecure).To(BeTrue())
+		})
+
+		// Given a request without a password
+		// When we test the connection
+		// Then it should return 400 without calling the service
+		It("should return 400 for an invalid request", func() {
+			// Act
+			w := post(`{"url": "https://vcenter.example.com", "username": "admin"}`)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(mockCollector.TestCreds).To(Equal(models.Credentials{}))
+		})
+	})
+
+	Describe("GetCollectorHistory", func() {
+		// Given a history with a failed and a successful collection
+		// When we request the collection history
+		// Then it should return both events in order
+		It("should return recorded events", func() {
+			// Arrange
+			finished := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+			mockCollector.HistoryResult = []models.CollectionHistoryEvent{
+				{Time: finished, State: models.CollectorStateError, Error: "login failed"},
+				{Time: finished.Add(time.Hour), State: models.CollectorStateCollected},
+			}
+			req := httptest.NewRequest(http.MethodGet, "/collector/history", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response v1.CollectionHistory
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Events).To(HaveLen(2))
+			Expect(response.Events[0].State).To(Equal(v1.CollectionHistoryEventStateError))
+			Expect(*response.Events[0].Error).To(Equal("login failed"))
+			Expect(response.Events[0].Time).To(BeTemporally("==", finished))
+			Expect(response.Events[1].State).To(Equal(v1.CollectionHistoryEventStateCollected))
+			Expect(response.Events[1].Error).To(BeNil())
+		})
+
+		// Given the collection history is not enabled
+		// When we request the collection history
+		// Then it should return 404
+		It("should return 404 when history is not enabled", func() {
+			// Arrange
+			mockCollector.HistoryError = srvErrors.NewResourceNotFoundError("collection history", "")
+			req := httptest.NewRequest(http.MethodGet, "/collector/history", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("GetCollectorLogs", func() {
+		// Given a handler without a log source
+		// When we request the collector logs
+		// Then it should return an empty list
+		It("should return no lines without a log source", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/collector/logs", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(MatchJSON(`{"lines": []}`))
+		})
+
+		// Given a non-positive lines parameter
+		// When we request the collector logs
+		// Then it should return 400
+		It("should reject lines below 1", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/collector/logs?lines=0", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("ReloadPolicies", func() {
+		// Given a valid policies folder
+		// When we reload the policies
+		// Then it should return 200 OK with the number of policies loaded
+		It("should return the number of policies loaded", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/collector/policies/reload", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockPolicy.ReloadCallCount).To(Equal(1))
+			var response v1.PolicyReloadResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Policies).To(Equal(3))
+		})
+
+		// Given an invalid policies folder
+		// When we reload the policies
+		// Then it should return 500 with the error message
+		It("should return 500 when the policies cannot be loaded", func() {
+			// Arrange
+			mockPolicy.ReloadError = errors.New("failed to read policies: no such directory")
+			req := httptest.NewRequest(http.MethodPost, "/collector/policies/reload", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			var response map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["error"]).To(Equal("failed to read policies: no such directory"))
+		})
+	})
+
+	Describe("GetConcernCatalog", func() {
+		// Given policies defining two concerns
+		// When we request the concern catalog
+		// Then it should return both definitions with 200 OK
+		It("should return the concern definitions", func() {
+			// Arrange
+			mockPolicy.CatalogResult = []models.ConcernDefinition{
+				{ID: "vmware.disk.capacity.invalid", Category: "Critical", Label: "Disk '%v' has an invalid capacity of %v bytes"},
+				{ID: "vmware.snapshot.detected", Category: "Information", Label: "VM snapshot detected", Assessment: "Online snapshots are not supported."},
+			}
+			req := httptest.NewRequest(http.MethodGet, "/concerns/catalog", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response v1.ConcernCatalog
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Concerns).To(Equal([]v1.ConcernDefinition{
+				{Id: "vmware.disk.capacity.invalid", Category: "Critical", Label: "Disk '%v' has an invalid capacity of %v bytes", Assessment: ""},
+				{Id: "vmware.snapshot.detected", Category: "Information", Label: "VM snapshot detected", Assessment: "Online snapshots are not supported."},
+			}))
+		})
+
+		// Given policies defining no concerns
+		// When we request the concern catalog
+		// Then it should return an empty list rather than null
+		It("should return an empty list", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/concerns/catalog", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(MatchJSON(`{"concerns": []}`))
+		})
+	})
+})
+
+var _ = Describe("Collector Handlers Integration", func() {
+	const password = "Sup3r-S3cret!"
+
+	var (
+		ctx     context.Context
+		db      *sql.DB
+		st      *store.Store
+		handler *handlers.Handler
+		router  *gin.Engine
+	)
+
+	// builder returns a single-unit collection that fails with workErr, or
+	// saves an empty inventory when workErr is nil.
+	builder := func(workErr error) func(models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+		return func(_ models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+			return work.NewSliceWorkBuilder([]work.WorkUnit[models.CollectorStatus, models.CollectorResult]{
+				{
+					Status: func() models.CollectorStatus {
+						return models.CollectorStatus{State: models.CollectorStateConnecting}
+					},
+					Work: func(ctx context.Context, r models.CollectorResult) (models.CollectorResult, error) {
+						if workErr != nil {
+							return r, workErr
+						}
+						return r, st.Inventory().Save(ctx, []byte(`{"vms":[]}`))
+					},
+				},
+			})
+		}
+	}
+
+	start := func(buildFn func(models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult]) {
+		collector := services.NewCollectorService(services.NewInventoryService(st), buildFn).
+			WithCredentialsStore(st.Credentials().WithSecret("agent-secret"))
+		DeferCleanup(collector.Stop)
+		handler = handlers.NewHandler(config.Configuration{}).WithCollectorService(collector)
+		router = gin.New()
+		router.GET("/collector", func(c *gin.Context) {
+			var params v1.GetCollectorStatusParams
+			if err := c.ShouldBindQuery(&params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			handler.GetCollectorStatus(c, params)
+		})
+		router.POST("/collector", handler.StartCollector)
+
+		body, err := json.Marshal(v1.CollectorStartRequest{
+			Url:      "https://vcenter.example.com",
+			Username: "administrator@vsphere.local",
+			Password: password,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(http.MethodPost, "/collector", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusAccepted))
+		Expect(w.Body.String()).NotTo(ContainSubstring(password))
+	}
+
+	getStatus := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/collector", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		return w.Body.String()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		gin.SetMode(gin.TestMode)
+
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = migrations.Run(ctx, db)
+		Expect(err).NotTo(HaveOccurred())
+
+		st = store.NewStore(db, test.NewMockValidator())
+	})
+
+	AfterEach(func() {
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	// Given credentials stored by a successful collection
+	// When we request the collector status
+	// Then the password should not appear in the response
+	It("should not return the password once collected", func() {
+		// Arrange
+		start(builder(nil))
+
+		// Act
+		Eventually(getStatus).Should(ContainSubstring(string(v1.CollectorStatusStatusCollected)))
+		body := getStatus()
+
+		// Assert
+		Expect(body).NotTo(ContainSubstring(password))
+		stored, err := st.Credentials().Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Password).To(Equal(password))
+	})
+
+	// Given credentials stored by a failed collection
+	// When we request the collector status
+	// Then the error is returned but the password is not
+	It("should not return the password on error", func() {
+		// Arrange
+		start(builder(errors.New("cannot complete login due to an incorrect user name or password")))
+
+		// Act
+		Eventually(getStatus).Should(ContainSubstring(string(v1.CollectorStatusStatusError)))
+		body := getStatus()
+
+		// Assert
+		Expect(body).To(ContainSubstring("incorrect user name or password"))
+		Expect(body).NotTo(ContainSubstring(password))
+	})
+})
+
+var _ = Describe("Collector Logs Integration", func() {
+	var (
+		db     *sql.DB
+		router *gin.Engine
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(migrations.Run(context.Background(), db)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	getLines := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/collector/logs"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var response v1.CollectorLogs
+		Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+		return response.Lines
+	}
+
+	// Given a ring of 3 lines fed by the inventory logger
+	// When a collection logs 5 inventory lines and one from another logger
+	// Then the last 3 inventory lines should be returned, oldest first
+	It("should return the last collection log lines, bounded by the ring size", func() {
+		// Arrange
+		ring := logger.NewRing(3, "inventory")
+		log := zap.New(ring.Core())
+		st := store.NewStore(db, test.NewMockValidator())
+		collector := services.NewCollectorService(services.NewInventoryService(st), func(_ models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+			return work.NewSliceWorkBuilder([]work.WorkUnit[models.CollectorStatus, models.CollectorResult]{
+				{
+					Status: func() models.CollectorStatus {
+						return models.CollectorStatus{State: models.CollectorStateCollecting}
+					},
+					Work: func(ctx context.Context, r models.CollectorResult) (models.CollectorResult, error) {
+						for i := range 5 {
+							log.Named("inventory").Sugar().Infow("parsed sheet", "sheet", i)
+						}
+						log.Named("console_service").Info("not a collection line")
+						return r, st.Inventory().Save(ctx, []byte(`{"vms":[]}`))
+					},
+				},
+			})
+		})
+		DeferCleanup(collector.Stop)
+		handler := handlers.NewHandler(config.Configuration{}).
+			WithCollectorService(collector).
+			WithCollectorLogs(ring)
+		router = gin.New()
+		router.GET("/collector/logs", func(c *gin.Context) {
+			var params v1.GetCollectorLogsParams
+			Expect(c.ShouldBindQuery(&params)).To(Succeed())
+			handler.GetCollectorLogs(c, params)
+		})
+
+		// Act
+		Expect(collector.Start(context.Background(), models.Credentials{URL: "https://vcenter.example.com", Username: "admin", Password: "secret"})).To(Succeed())
+		Eventually(func() models.CollectorStateType {
+			return collector.GetStatus().State
+		}, time.Second).Should(Equal(models.CollectorStateCollected))
+
+		// Assert
+		lines := getLines("")
+		Expect(lines).To(HaveLen(3))
+		for i, line := range lines {
+			Expect(line).To(ContainSubstring("inventory"))
+			Expect(line).To(ContainSubstring("parsed sheet"))
+			Expect(line).To(ContainSubstring(fmt.Sprintf(`"sheet": %d`, i+2)))
+		}
+		Expect(getLines("?lines=1")).To(Equal(lines[2:]))
+	})
 })
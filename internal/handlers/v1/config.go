@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+var _ = registerCapability("agent.config", "Change the console update interval and legacy status flag at runtime (PATCH /agent/config)")
+
+// GetConfig returns the effective agent configuration without secrets
+// (GET /config)
+func (h *Handler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, h.agentConfig())
+}
+
+// UpdateAgentConfig changes the console settings of the running agent and
+// returns the effective configuration. A field sent as null resets the setting
+// to the flag the agent was started with
+// (PATCH /agent/config)
+func (h *Handler) UpdateAgentConfig(c *gin.Context) {
+	var (
+		req    v1.AgentConfigUpdate
+		fields map[string]json.RawMessage
+	)
+	body, err := c.GetRawData()
+	if err == nil {
+		err = json.Unmarshal(body, &fields)
+	}
+	if err == nil {
+		err = json.Unmarshal(body, &req)
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, errors.NewValidationError(validationErrorMessage(err)))
+		return
+	}
+
+	isNull := func(field string) bool {
+		raw, ok := fields[field]
+		return ok && bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+	}
+
+	change := models.ConsoleSettingsChange{
+		ResetUpdateInterval:      isNull("updateInterval"),
+		ResetLegacyStatusEnabled: isNull("legacyStatusEnabled"),
+	}
+	if req.UpdateInterval != nil {
+		interval, err := time.ParseDuration(*req.UpdateInterval)
+		if err != nil || interval <= 0 {
+			respondError(c, http.StatusBadRequest, errors.NewFieldValidationError(map[string]string{"updateInterval": "must be a positive duration such as 5s"}))
+			return
+		}
+		change.UpdateInterval = &interval
+	}
+	change.LegacyStatusEnabled = req.LegacyStatusEnabled
+
+	if _, err := h.consoleSrv.UpdateSettings(c.Request.Context(), change); err != nil {
+		if errors.IsValidationError(err) {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.agentConfig())
+}
+
+// agentConfig maps the redacted agent configuration to its API form, with the
+// console settings currently in effect. It is also written to the export
+// archive.
+func (h *Handler) agentConfig() v1.AgentConfig {
+	agent := h.cfg.Agent.Redacted()
+	if h.consoleSrv != nil {
+		settings := h.consoleSrv.Settings()
+		if settings.UpdateInterval != nil {
+			agent.UpdateInterval = *settings.UpdateInterval
+		}
+		if settings.LegacyStatusEnabled != nil {
+			agent.LegacyStatusEnabled = *settings.LegacyStatusEnabled
+		}
+	}
+	return v1.AgentConfig{
+		Mode:                     agent.Mode,
+		Id:                       agent.ID,
+		SourceId:                 agent.SourceID,
+		Version:                  agent.Version,
+		DataFolder:               agent.DataFolder,
+		OpaPoliciesFolder:        agent.OpaPoliciesFolder,
+		UpdateInterval:           agent.UpdateInterval.String(),
+		LegacyStatusEnabled:      agent.LegacyStatusEnabled,
+		CollectionHistoryEnabled: agent.CollectionHistoryEnabled,
+		CollectionSchedule:       agent.CollectionSchedule,
+	}
+}
@@ -0,0 +1,200 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+var _ = Describe("Config Handler", func() {
+	// Given an agent configured with auth and console settings
+	// When we request the configuration
+	// Then it should return the agent settings and nothing else
+	It("should return the effective agent configuration", func() {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		handler := handlers.NewHandler(config.Configuration{
+			Agent: config.Agent{
+				Mode:                     "connected",
+				ID:                       "agent-id",
+				SourceID:                 "source-id",
+				Version:                  "v2.0.0",
+				DataFolder:               "/var/lib/agent",
+				OpaPoliciesFolder:        "/etc/policies",
+				UpdateInterval:           5 * time.Second,
+				LegacyStatusEnabled:      true,
+				CollectionHistoryEnabled: true,
+				CollectionSchedule:       "0 2 * * *",
+			},
+			Auth:    config.Authentication{Enabled: true, JWTFilePath: "/secrets/jwt"},
+			Console: config.Console{URL: "https://console.example.com"},
+		})
+		router := gin.New()
+		router.GET("/config", handler.GetConfig)
+
+		req := httptest.NewRequest(http.MethodGet, "/config", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp map[string]any
+		Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp).To(Equal(map[string]any{
+			"mode":                     "connected",
+			"id":                       "agent-id",
+			"sourceId":                 "source-id",
+			"version":                  "v2.0.0",
+			"dataFolder":               "/var/lib/agent",
+			"opaPoliciesFolder":        "/etc/policies",
+			"updateInterval":           "5s",
+			"legacyStatusEnabled":      true,
+			"collectionHistoryEnabled": true,
+			"collectionSchedule":       "0 2 * * *",
+		}))
+		Expect(w.Body.String()).NotTo(ContainSubstring("/secrets/jwt"))
+	})
+
+	Describe("UpdateAgentConfig", func() {
+		var (
+			router      *gin.Engine
+			mockConsole *MockConsoleService
+		)
+
+		BeforeEach(func() {
+			gin.SetMode(gin.TestMode)
+			interval := 5 * time.Second
+			legacy := false
+			mockConsole = &MockConsoleService{
+				SettingsResult: models.ConsoleSettings{UpdateInterval: &interval, LegacyStatusEnabled: &legacy},
+			}
+			handler := handlers.NewHandler(config.Configuration{
+				Agent: config.Agent{UpdateInterval: interval},
+			}).WithConsoleService(mockConsole)
+			router = gin.New()
+			router.PATCH("/agent/config", handler.UpdateAgentConfig)
+			router.GET("/config", handler.GetConfig)
+		})
+
+		patch := func(body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPatch, "/agent/config", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		// Given a running console with a 5s update interval
+		// When we patch the interval
+		// Then the service should get only the interval and GET /config should report it
+		It("should apply a new update interval", func() {
+			// Act
+			w := patch(`{"updateInterval":"30s"}`)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var resp map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp["updateInterval"]).To(Equal("30s"))
+			Expect(resp["legacyStatusEnabled"]).To(BeFalse())
+			Expect(mockConsole.LastUpdate).NotTo(BeNil())
+			Expect(*mockConsole.LastUpdate.UpdateInterval).To(Equal(30 * time.Second))
+			Expect(mockConsole.LastUpdate.LegacyStatusEnabled).To(BeNil())
+
+			req := httptest.NewRequest(http.MethodGet, "/config", nil)
+			get := httptest.NewRecorder()
+			router.ServeHTTP(get, req)
+			Expect(get.Body.String()).To(ContainSubstring(`"updateInterval":"30s"`))
+		})
+
+		// Given a running console
+		// When we patch only the legacy status flag
+		// Then the interval should be left unchanged
+		It("should apply the legacy status flag alone", func() {
+			// Act
+			w := patch(`{"legacyStatusEnabled":true}`)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var resp map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp["legacyStatusEnabled"]).To(BeTrue())
+			Expect(resp["updateInterval"]).To(Equal("5s"))
+			Expect(mockConsole.LastUpdate.UpdateInterval).To(BeNil())
+		})
+
+		// Given a running console
+		// When we patch both settings to null
+		// Then the service should be asked to reset both and set neither
+		It("should reset settings sent as null", func() {
+			// Act
+			w := patch(`{"updateInterval":null,"legacyStatusEnabled":null}`)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockConsole.LastUpdate).NotTo(BeNil())
+			Expect(mockConsole.LastUpdate.ResetUpdateInterval).To(BeTrue())
+			Expect(mockConsole.LastUpdate.ResetLegacyStatusEnabled).To(BeTrue())
+			Expect(mockConsole.LastUpdate.UpdateInterval).To(BeNil())
+			Expect(mockConsole.LastUpdate.LegacyStatusEnabled).To(BeNil())
+		})
+
+		// Given a running console
+		// When we patch only the interval
+		// Then the omitted legacy status flag should not be reset
+		It("should not reset omitted settings", func() {
+			// Act
+			w := patch(`{"updateInterval":"30s"}`)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockConsole.LastUpdate.ResetUpdateInterval).To(BeFalse())
+			Expect(mockConsole.LastUpdate.ResetLegacyStatusEnabled).To(BeFalse())
+		})
+
+		// Given a running console
+		// When we patch a zero, negative or malformed interval
+		// Then it should return 400 and not call the service
+		DescribeTable("should reject an invalid update interval",
+			func(body string) {
+				// Act
+				w := patch(body)
+
+				// Assert
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(w.Body.String()).To(ContainSubstring("updateInterval"))
+				Expect(mockConsole.LastUpdate).To(BeNil())
+			},
+			Entry("zero", `{"updateInterval":"0s"}`),
+			Entry("negative", `{"updateInterval":"-5s"}`),
+			Entry("malformed", `{"updateInterval":"soon"}`),
+		)
+
+		// Given a console service failing to persist the settings
+		// When we patch the interval
+		// Then it should return 500
+		It("should return 500 when the settings cannot be saved", func() {
+			// Arrange
+			mockConsole.UpdateError = errors.New("db closed")
+
+			// Act
+			w := patch(`{"updateInterval":"30s"}`)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
@@ -25,7 +25,7 @@ func (h *Handler) GetAgentStatus(c *gin.Context) {
 func (h *Handler) SetAgentMode(c *gin.Context) {
 	var req v1.AgentModeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		respondError(c, http.StatusBadRequest, errors.NewValidationError(validationErrorMessage(err)))
 		return
 	}
 
@@ -38,11 +38,11 @@ func (h *Handler) SetAgentMode(c *gin.Context) {
 	}
 
 	if err := h.consoleSrv.SetMode(c.Request.Context(), mode); err != nil {
-		if errors.IsModeConflictError(err) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		if errors.IsModeConflictError(err) || errors.IsModeChangeInProgressError(err) {
+			respondError(c, http.StatusConflict, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -52,3 +52,25 @@ func (h *Handler) SetAgentMode(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// PushInventory re-sends the stored inventory to the console
+// (POST /agent/push-inventory)
+func (h *Handler) PushInventory(c *gin.Context) {
+	if h.rejectInMaintenance(c) {
+		return
+	}
+
+	if err := h.consoleSrv.PushInventory(c.Request.Context()); err != nil {
+		switch {
+		case errors.IsAgentNotConnectedError(err):
+			respondError(c, http.StatusConflict, err)
+		case errors.IsResourceNotFoundError(err):
+			respondError(c, http.StatusNotFound, err)
+		default:
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
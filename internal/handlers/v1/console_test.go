@@ -6,6 +6,7 @@ import (
 	stderrors "errors"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
@@ -37,6 +38,7 @@ var _ = Describe("Console Handlers", func() {
 		router = gin.New()
 		router.GET("/agent", handler.GetAgentStatus)
 		router.POST("/agent", handler.SetAgentMode)
+		router.POST("/agent/push-inventory", handler.PushInventory)
 	})
 
 	Describe("GetAgentStatus", func() {
@@ -59,16 +61,19 @@ var _ = Describe("Console Handlers", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(response.ConsoleConnection).To(Equal(v1.AgentStatusConsoleConnectionDisconnected))
 			Expect(response.Mode).To(Equal(v1.AgentStatusModeDisconnected))
+			Expect(response.LastContactAt).To(BeNil())
 		})
 
 		// Given a console service in connected mode
 		// When we request the agent status
-		// Then it should return connected status
+		// Then it should return connected status and the last contact time
 		It("should return connected status", func() {
 			// Arrange
+			lastContact := time.Date(2026, 5, 4, 10, 30, 0, 0, time.UTC)
 			mockConsole.StatusResult = models.ConsoleStatus{
-				Current: models.ConsoleStatusConnected,
-				Target:  models.ConsoleStatusConnected,
+				Current:       models.ConsoleStatusConnected,
+				Target:        models.ConsoleStatusConnected,
+				LastContactAt: lastContact,
 			}
 
 			req := httptest.NewRequest(http.MethodGet, "/agent", nil)
@@ -85,6 +90,8 @@ var _ = Describe("Console Handlers", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(response.ConsoleConnection).To(Equal(v1.AgentStatusConsoleConnectionConnected))
 			Expect(response.Mode).To(Equal(v1.AgentStatusModeConnected))
+			Expect(response.LastContactAt).NotTo(BeNil())
+			Expect(*response.LastContactAt).To(BeTemporally("==", lastContact))
 		})
 
 		// Given a console service with an error
@@ -226,6 +233,30 @@ var _ = Describe("Console Handlers", func() {
 			Expect(response["error"]).To(Equal("mode change conflict: console stopped"))
 		})
 
+		// Given a console service already applying another mode change
+		// When we try to set the agent mode
+		// Then it should return 409 Conflict
+		It("should return 409 when a mode change is already in progress", func() {
+			// Arrange
+			mockConsole.SetModeError = errors.NewModeChangeInProgressError()
+
+			body := v1.AgentModeRequest{Mode: v1.AgentModeRequestModeConnected}
+			bodyBytes, _ := json.Marshal(body)
+
+			req := httptest.NewRequest(http.MethodPost, "/agent", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusConflict))
+			var response map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["error"]).To(Equal("mode change already in progress"))
+		})
+
 		// Given a console service that returns an internal error
 		// When we try to set the agent mode
 		// Then it should return 500 Internal Server Error
@@ -250,4 +281,54 @@ var _ = Describe("Console Handlers", func() {
 			Expect(response["error"]).To(Equal("database error"))
 		})
 	})
+
+	Describe("PushInventory", func() {
+		// Given a connected console service
+		// When we request an inventory push
+		// Then it should return 202 Accepted
+		It("should return 202 when the push is triggered", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPost, "/agent/push-inventory", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusAccepted))
+			Expect(mockConsole.PushCallCount).To(Equal(1))
+		})
+
+		// Given a disconnected console service
+		// When we request an inventory push
+		// Then it should return 409 Conflict
+		It("should return 409 when the agent is not connected", func() {
+			// Arrange
+			mockConsole.PushError = errors.NewAgentNotConnectedError()
+			req := httptest.NewRequest(http.MethodPost, "/agent/push-inventory", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+
+		// Given no inventory has been collected yet
+		// When we request an inventory push
+		// Then it should return 404 Not Found
+		It("should return 404 when there is no inventory", func() {
+			// Arrange
+			mockConsole.PushError = errors.NewInventoryNotFoundError()
+			req := httptest.NewRequest(http.MethodPost, "/agent/push-inventory", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
 })
@@ -47,43 +47,68 @@
 //
 // Agent Endpoints (console.go):
 //
-//	┌────────┬──────────┬─────────────────────────────────────────────┐
-//	│ Method │ Endpoint │ Description                                 │
-//	├────────┼──────────┼─────────────────────────────────────────────┤
-//	│ GET    │ /agent   │ Get agent status (connection state, mode)   │
-//	│ POST   │ /agent   │ Set agent mode (connected/disconnected)     │
-//	└────────┴──────────┴─────────────────────────────────────────────┘
+//	┌────────┬───────────────────────┬─────────────────────────────────────────────┐
+//	│ Method │ Endpoint              │ Description                                 │
+//	├────────┼───────────────────────┼─────────────────────────────────────────────┤
+//	│ GET    │ /agent                │ Get agent status (connection state, mode)   │
+//	│ POST   │ /agent                │ Set agent mode (connected/disconnected)     │
+//	│ POST   │ /agent/push-inventory │ Re-send stored inventory to the console     │
+//	│ GET    │ /agent/maintenance    │ Get maintenance mode flag                   │
+//	│ POST   │ /agent/maintenance    │ Enable/disable maintenance (read-only) mode │
+//	│ PATCH  │ /agent/config         │ Change console interval and legacy status   │
+//	└────────┴───────────────────────┴─────────────────────────────────────────────┘
 //
 // Collector Endpoints (collector.go):
 //
-//	┌────────┬─────────────┬──────────────────────────────────────────┐
-//	│ Method │ Endpoint    │ Description                              │
-//	├────────┼─────────────┼──────────────────────────────────────────┤
-//	│ GET    │ /collector  │ Get collector status                     │
-//	│ POST   │ /collector  │ Start inventory collection               │
-//	│ DELETE │ /collector  │ Stop ongoing collection                  │
-//	└────────┴─────────────┴──────────────────────────────────────────┘
+//	┌────────┬───────────────────┬──────────────────────────────────────────┐
+//	│ Method │ Endpoint          │ Description                              │
+//	├────────┼───────────────────┼──────────────────────────────────────────┤
+//	│ GET    │ /collector        │ Get collector status                     │
+//	│ POST   │ /collector        │ Start inventory collection               │
+//	│ DELETE │ /collector        │ Stop ongoing collection                  │
+//	│ POST   │ /collector/test   │ Test vCenter login without collecting    │
+//	│ GET    │ /collector/logs   │ Recent collection log lines              │
+//	│ GET    │ /concerns/catalog │ Concerns the loaded policies can raise   │
+//	└────────┴───────────────────┴──────────────────────────────────────────┘
 //
 // Inventory Endpoints (inventory.go):
 //
-//	┌────────┬─────────────┬──────────────────────────────────────────┐
-//	│ Method │ Endpoint    │ Description                              │
-//	├────────┼─────────────┼──────────────────────────────────────────┤
-//	│ GET    │ /inventory  │ Get collected inventory as JSON          │
-//	└────────┴─────────────┴──────────────────────────────────────────┘
+//	┌────────┬────────────────────┬──────────────────────────────────────────┐
+//	│ Method │ Endpoint           │ Description                              │
+//	├────────┼────────────────────┼──────────────────────────────────────────┤
+//	│ GET    │ /inventory         │ Get collected inventory as JSON          │
+//	│ GET    │ /inventory/diff    │ Compare the last two collections         │
+//	│ GET    │ /inventory/metrics │ Top-level inventory totals               │
+//	│ GET    │ /clusters          │ Per-cluster totals, by overcommitment    │
+//	│ GET    │ /export            │ Inventory, VM CSV and config as tar.gz   │
+//	│ GET    │ /inventory/export  │ Inventory split per cluster as tar.gz    │
+//	└────────┴────────────────────┴──────────────────────────────────────────┘
+//
+// Events Endpoints (events.go):
+//
+//	┌────────┬─────────────────┬──────────────────────────────────────────┐
+//	│ Method │ Endpoint        │ Description                              │
+//	├────────┼─────────────────┼──────────────────────────────────────────┤
+//	│ GET    │ /events         │ Stream status changes as SSE             │
+//	└────────┴─────────────────┴──────────────────────────────────────────┘
 //
 // VM Endpoints (vms.go):
 //
-//	┌────────┬──────────────────┬───────────────────────────────────────┐
-//	│ Method │ Endpoint         │ Description                           │
-//	├────────┼──────────────────┼───────────────────────────────────────┤
-//	│ GET    │ /vms             │ List VMs with filtering/pagination    │
-//	│ GET    │ /vms/{id}        │ Get VM details                        │
-//	│ GET    │ /vms/inspector   │ Get inspector status (not implemented)│
-//	│ POST   │ /vms/inspector   │ Start inspection (not implemented)    │
-//	│ PATCH  │ /vms/inspector   │ Add VMs to inspection (not impl.)     │
-//	│ DELETE │ /vms/inspector   │ Remove VMs from inspection (not impl.)│
-//	└────────┴──────────────────┴───────────────────────────────────────┘
+//	┌────────┬──────────────────────┬───────────────────────────────────────┐
+//	│ Method │ Endpoint             │ Description                           │
+//	├────────┼──────────────────────┼───────────────────────────────────────┤
+//	│ GET    │ /vms                 │ List VMs with filtering/pagination    │
+//	│ GET    │ /vms/os-distribution │ VM count per guest OS                 │
+//	│ GET    │ /vms/group-by        │ VM count per value of a field         │
+//	│ GET    │ /vms/views           │ List saved VM list views              │
+//	│ POST   │ /vms/views           │ Save a named filter/sort/pageSize     │
+//	│ POST   │ /vms/batch           │ Get details for several VMs           │
+//	│ GET    │ /vms/{id}            │ Get VM details                        │
+//	│ GET    │ /vms/inspector       │ Get inspector status (not implemented)│
+//	│ POST   │ /vms/inspector       │ Start inspection (not implemented)    │
+//	│ PATCH  │ /vms/inspector       │ Add VMs to inspection (not impl.)     │
+//	│ DELETE │ /vms/inspector       │ Remove VMs from inspection (not impl.)│
+//	└────────┴──────────────────────┴───────────────────────────────────────┘
 //
 // Group Endpoints (group.go):
 //
@@ -105,6 +130,17 @@
 //	│ PUT    │ /inspector/vddk  │ Upload VDDK tarball (max 64MB)        │
 //	└────────┴──────────────────┴───────────────────────────────────────┘
 //
+// Debug Endpoints (storage.go, loglevel.go):
+//
+//	┌────────┬──────────────────┬───────────────────────────────────────┐
+//	│ Method │ Endpoint         │ Description                           │
+//	├────────┼──────────────────┼───────────────────────────────────────┤
+//	│ GET    │ /debug/storage   │ Data folder disk usage                │
+//	│ POST   │ /debug/compact   │ Compact the agent database            │
+//	│ GET    │ /debug/db        │ Download the DuckDB file              │
+//	│ POST   │ /debug/loglevel  │ Change the log level until restart    │
+//	└────────┴──────────────────┴───────────────────────────────────────┘
+//
 // # Agent Handler
 //
 // GET /agent - Returns current agent status:
@@ -125,7 +161,29 @@
 //
 // Errors:
 //   - 400 Bad Request: Invalid mode value
-//   - 409 Conflict: Mode change blocked after fatal console error
+//   - 409 Conflict: Mode change blocked after fatal console error, or another
+//     mode change is still being applied
+//
+// POST /agent/push-inventory - Queues the stored inventory for the console and
+// sends it right away, even if unchanged. Returns 202 Accepted.
+//
+// Errors:
+//   - 404 Not Found: No inventory collected yet
+//   - 409 Conflict: Agent is not in connected mode
+//   - 503 Service Unavailable: Agent is in maintenance mode
+//
+// GET/POST /agent/maintenance (maintenance.go) - Reads or sets the maintenance
+// flag ({"enabled": true}). While enabled, StartCollector, StartInspection and
+// PushInventory return 503 with MaintenanceModeError; reads keep working.
+//
+// PATCH /agent/config (config.go) - Changes the console settings of the running
+// agent ({"updateInterval": "30s", "legacyStatusEnabled": false}; both optional)
+// through ConsoleService.UpdateSettings and returns the effective configuration,
+// as GET /config does. A field sent as null resets the setting to its flag. GET /config reports the settings in effect, not the
+// flags the agent started with.
+//
+// Errors:
+//   - 400 Bad Request: updateInterval is not a positive duration
 //
 // # Collector Handler
 //
@@ -136,6 +194,10 @@
 //	    "error": null           // optional error message
 //	}
 //
+// With ?wait=30s the request long-polls: it returns as soon as the status
+// differs from ?since (the status at request time when omitted) or after the
+// wait, capped at 60s. An unparsable or negative wait returns 400.
+//
 // POST /collector - Starts inventory collection:
 //
 // Request:
@@ -143,27 +205,95 @@
 //	{
 //	    "url": "https://vcenter.example.com",
 //	    "username": "admin@vsphere.local",
-//	    "password": "secret"
+//	    "password": "secret",
+//	    "insecureSkipVerify": false, // optional, skip TLS certificate verification
+//	    "maxAge": "1h"               // optional, recollect only an older inventory
 //	}
 //
 // Validation:
-//   - All fields required
-//   - URL must have valid scheme and host
+//   - url, username and password required
+//   - URL must be http or https and have a host
+//   - maxAge, when set, must be a positive Go duration
+//
+// Without maxAge the request calls CollectorService.Start, which does nothing
+// once an inventory exists. With maxAge it calls Refresh, which recollects
+// only when the inventory is older than maxAge. Either way the response
+// carries collectedAt, so clients can tell whether the inventory was kept.
 //
 // Response: 202 Accepted with collector status
 //
 // Errors:
-//   - 400 Bad Request: Missing fields or invalid URL format
+//   - 400 Bad Request: Missing fields or invalid URL format, with code
+//     "VALIDATION" and a per-field "fields" map (see bindCollectorRequest)
 //   - 409 Conflict: Collection already in progress
 //
 // DELETE /collector - Stops ongoing collection, returns to ready state.
 //
+// POST /collector/test - Logs in to vCenter with the same request body as
+// POST /collector and returns its product, version, build and API version.
+// No collection is started and the credentials are not stored.
+//
+// Errors:
+//   - 400 Bad Request: Invalid request, or vCenter rejected the login (CredentialsError)
+//   - 403 Forbidden: vCenter denied the account a privilege (PermissionError)
+//   - 502 Bad Gateway: vCenter unreachable, certificate not trusted or failed (VCenterError)
+//
+// GET /collector/logs?lines=N - Returns the last N (default 200) lines logged
+// while collecting, oldest first. Lines come from the CollectorLogs source set
+// with WithCollectorLogs (a logger.Ring fed by the inventory, collector_service
+// and duckdb_parser loggers); without one the list is empty. lines below 1
+// returns 400.
+//
+// GET /concerns/catalog - Lists every concern the loaded OPA policies can
+// raise, sorted by ID, from PolicyService.Catalog. It follows
+// POST /collector/policies/reload.
+//
 // # Inventory Handler
 //
 // GET /inventory - Returns raw inventory JSON.
 //
 // Errors:
 //   - 404 Not Found: Inventory not yet collected
+//   - 409 Conflict: No inventory and the collector is in the error state
+//     (CollectionFailedError wrapping the collection error)
+//
+// GET /inventory/diff - Returns VMs added and removed between the last two
+// collections and the CPU, memory and disk changes of VMs present in both.
+//
+// Errors:
+//   - 404 Not Found: Fewer than two collections have completed
+//
+// GET /inventory/metrics - Returns the vCenter-wide totals of the stored
+// inventory (VMs, powered-on VMs, vCPUs, RAM, disk, hosts, datacenters and
+// the overcommitment ratios) without the rest of the inventory.
+//
+// Errors:
+//   - 404 Not Found: Inventory not yet collected
+//
+// GET /clusters?minCpuOvercommit=&minMemoryOvercommit= - Returns the same
+// totals per cluster, in cluster ID order, keeping the clusters that reach
+// every minimum given. Clusters without host data have no ratios and never
+// match a minimum.
+//
+// Errors:
+//   - 400 Bad Request: Negative minimum (per-field "fields" map)
+//   - 404 Not Found: Inventory not yet collected
+//
+// GET /export (export.go) - Streams a tar.gz with inventory.json, vms.csv and
+// the redacted config.json. All members are built before the status is sent,
+// so a failure still yields a JSON error rather than a truncated archive.
+//
+// Errors:
+//   - 404 Not Found: Inventory not yet collected
+//
+// GET /inventory/export?groupBy=cluster (export.go) - Streams a tar.gz with
+// one <clusterId>.json per cluster of the inventory, holding that cluster's
+// InventoryData, then vcenter.json with the vCenter-wide aggregate. Members
+// are built before the status is sent, as for GET /export.
+//
+// Errors:
+//   - 400 Bad Request: groupBy missing or not "cluster"
+//   - 404 Not Found: Inventory not yet collected
 //
 // # VM Handler
 //
@@ -178,6 +308,14 @@
 //	│ sort           │ []string │ Sort fields (format: "field:direction") │
 //	│ page           │ int      │ Page number (default: 1)                │
 //	│ pageSize       │ int      │ Items per page (default: 20, max: 100)  │
+//	│ hasCritical    │ bool     │ Only VMs with a Critical concern        │
+//	│ issueLabel     │ string   │ Regex over concern labels (RE2)         │
+//	│ nicType        │ string   │ Regex over NIC adapter types (RE2)      │
+//	│ nicCountMin    │ int      │ Minimum number of NICs (distinct MACs)  │
+//	│ nicCountMax    │ int      │ Maximum number of NICs (distinct MACs)  │
+//	│ readiness      │ []string │ migratable, warning and/or blocked      │
+//	│ fields         │ string   │ Comma-separated fields to return per VM │
+//	│ view           │ string   │ Saved view to apply (see /vms/views)    │
 //	└────────────────┴──────────┴─────────────────────────────────────────┘
 //
 // The byExpression parameter accepts a filter DSL expression that can reference
@@ -185,12 +323,26 @@
 // vnetwork, vdatastore, vm_inspection_status). See pkg/filter for the grammar
 // and docs/filter-by-expression.md for field mappings and examples.
 //
+// An expression longer than Agent.MaxFilterLength (4096 bytes when unset) is
+// answered 413 before it reaches the parser (filterTooLong). The same check
+// guards /vms/os-distribution, /vms/group-by, saved views and group filters.
+//
 // Valid Sort Fields:
 //   - name, vCenterState, cluster, diskSize, memory, issues
 //
 // Sort Direction:
 //   - asc (ascending) or desc (descending)
 //
+// Valid Projection Fields (fields parameter):
+//   - id, name, cluster, diskSize, memory, issueCount, vCenterState
+//
+// The projection is applied after VMService.List: each VM is emitted with only
+// the requested keys.
+//
+// Saved views: with view=<name> the handler loads the view and fills
+// byExpression, sort and pageSize from it when the request leaves them unset,
+// so explicit parameters always win. An unknown view returns 404.
+//
 // Example: /vms?byExpression=memory+%3E%3D+8GB&sort=name:asc&page=1&pageSize=50
 //
 // Response:
@@ -213,6 +365,22 @@
 //	    ]
 //	}
 //
+// The same pagination is set in the X-Total-Count and RFC 5988 Link headers
+// (setPaginationHeaders). Link holds first, prev, next and last URLs built from
+// the request URL with only page replaced; prev and next are omitted on the
+// first and last page.
+//
+// pageSize is clamped to Agent.MaxPageSize (100 when unset); X-Page-Size
+// reports the page size applied, so clients can tell when it was clamped.
+//
+// With "Accept: application/x-ndjson" (and neither count nor explain) the
+// handler streams every matching VM as JSON Lines instead (streamVMs): one
+// VM object per line, projected when fields is set, flushed after each batch
+// of VMService.Stream. Pagination is ignored and no pagination headers are
+// set. A failure before the first batch returns the usual error response;
+// later ones end the stream and are logged. The server exempts these
+// requests from the request timeout.
+//
 // Tags on each VM are derived from all groups whose filter matches
 // that VM. Tags are pre-computed at group create/update time and
 // stored in the group_matches table.
@@ -222,12 +390,23 @@
 //   - Invalid sort format (must be "field:direction")
 //   - Invalid sort field
 //   - Invalid sort direction
+//   - Unknown or empty fields projection
+//
+// explain=true answers 403 Forbidden unless Server.DatabaseDownloadEnabled is
+// set, the same switch as GET /debug/db.
 //
 // GET /vms/{id} - Returns detailed VM information.
 //
 // Errors:
 //   - 404 Not Found: VM not found
 //
+// POST /vms/batch - Returns detailed information for a list of VM ids in one
+// call. VMs come back in request order; ids matching no VM are listed in
+// notFound rather than failing the request.
+//
+// Errors:
+//   - 400 Bad Request: missing or empty ids, or more than 100 ids
+//
 // # Group Handler
 //
 // GET /groups - Lists groups with optional name filtering and pagination.
@@ -332,6 +511,43 @@
 // DELETE /groups/{id} - Deletes a group and its pre-computed matches.
 // Idempotent (returns 204 even if the group does not exist).
 //
+// # Debug Handler
+//
+// GET /debug/db - Checkpoints the database and streams its file as
+// application/octet-stream. Disabled unless Server.DatabaseDownloadEnabled is
+// set (--server-database-download-enabled), since the file holds the whole
+// inventory.
+//
+// Errors:
+//   - 403 Forbidden: Database download is disabled
+//   - 409 Conflict: In-memory database (DataFolderNotSetError), nothing to download
+//
+// POST /debug/loglevel - Sets the level of the agent logger from a
+// {"level": "debug|info|warn|error"} body and returns the level in effect.
+// The level comes from WithLogLevel (logger.Level() in cmd/run.go, the
+// AtomicLevel shared by the loggers built by logger.Init), so the change
+// applies to the running process and is lost on restart.
+//
+// Errors:
+//   - 400 Bad Request: Missing or unknown level
+//   - 404 Not Found: No LogLevel was set with WithLogLevel
+//
+// # Events Handler
+//
+// GET /events - Streams status changes as Server-Sent Events until the client
+// disconnects. It first sends the current collector, inspector and agent
+// status, then one event per change from the StatusBroker. The event name is
+// the source (collector, inspector, agent) and the data is the body of the
+// matching GET endpoint. An idle stream gets a comment line every 15s.
+//
+// # Capabilities Handler
+//
+// GET /capabilities - Returns the API version, the agent version and the
+// features registered with registerCapability, sorted by name. A handler file
+// registers its features from a package-level var, so a feature is listed
+// exactly when its handler is built in. Feature names are part of the API and
+// must not be renamed.
+//
 // # VDDK Handler
 //
 // PUT /inspector/vddk - Untar and override a VDDK tarball to the agent's data directory.
@@ -363,14 +579,34 @@
 //   - tag_format:   validates each tag matches ^[a-zA-Z0-9_.]+$
 //
 // Validation errors are formatted by validationErrorMessage (validation.go) into
-// human-readable messages before being returned as 400 Bad Request.
+// human-readable messages before being returned as 400 Bad Request. The
+// collector endpoints use fieldValidationError instead, which keeps one
+// message per JSON field; respondError then adds "code": "VALIDATION" and a
+// "fields" map to either body format.
 //
 // # Error Handling
 //
-// Handlers use consistent error response format:
+// Handlers write errors through respondError (errors.go), which uses a
+// consistent error response format:
 //
 //	{ "error": "error message" }
 //
+// A client sending "Accept: application/problem+json" gets an RFC 7807 body
+// instead, with the type derived from the pkg/errors type ("about:blank" when
+// the error has none):
+//
+//	{
+//	    "type": "urn:assisted-migration-agent:error:resource-not-found",
+//	    "title": "Not Found",
+//	    "status": 404,
+//	    "detail": "group '42' not found"
+//	}
+//
+// When the server's timeout middleware has expired the request context,
+// respondError answers 503 with the RequestTimeoutError instead of the
+// handler's status, since the handler's error is then only a side effect of
+// the cancellation.
+//
 // HTTP Status Code Mapping:
 //
 //	┌─────────────────────────────┬────────┬──────────────────────────────┐
@@ -380,7 +616,10 @@
 //	│ ResourceNotFoundError       │ 404     │ Resource doesn't exist      │
 //	│ CollectionInProgressError   │ 409    │ Collection already running   │
 //	│ ModeConflictError           │ 409    │ Mode change after fatal err  │
+//	│ ModeChangeInProgressError   │ 409    │ Concurrent mode change       │
+//	│ AgentNotConnectedError      │ 409    │ Push while disconnected      │
 //	│ MaxBytesError               │ 413    │ Upload exceeds size limit    │
+//	│ MaintenanceModeError        │ 503    │ Mutation in maintenance mode │
 //	│ Internal error              │ 500    │ Unexpected service errors    │
 //	│ Not implemented             │ 501    │ Inspector endpoints          │
 //	└─────────────────────────────┴────────┴──────────────────────────────┘
@@ -395,6 +634,7 @@
 //   - v1.NewVirtualMachineDetailFromModel(models.VM) → v1.VirtualMachineDetail
 //   - v1.AgentStatus.FromModel(models.AgentStatus)
 //   - v1.NewGroupFromModel(models.Group) → v1.Group
+//   - v1.NewConcernCatalogFromModel([]models.ConcernDefinition) → v1.ConcernCatalog
 //
 // # Framework
 //
@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+const (
+	problemJSONContentType = "application/problem+json"
+	problemTypePrefix      = "urn:assisted-migration-agent:error:"
+)
+
+// problem is an RFC 7807 error body.
+type problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+
+	MissingPrivileges []string          `json:"missingPrivileges,omitempty"`
+	Code              string            `json:"code,omitempty"`
+	Fields            map[string]string `json:"fields,omitempty"`
+}
+
+// validationCode marks a body carrying per-field validation errors.
+const validationCode = "VALIDATION"
+
+// respondError writes err with the given status. Clients that ask for
+// application/problem+json get an RFC 7807 body; everyone else gets the
+// {"error": "..."} body. Both carry missingPrivileges for an
+// InsufficientPrivilegesError, and code "VALIDATION" with the per-field
+// messages for a ValidationError with fields. A request that ran past the
+// server's timeout is answered 503 with the RequestTimeoutError, whatever the
+// handler's own status and error were.
+func respondError(c *gin.Context, status int, err error) {
+	if cause := context.Cause(c.Request.Context()); srvErrors.IsRequestTimeoutError(cause) {
+		status, err = http.StatusServiceUnavailable, cause
+	}
+
+	var missing []string
+	if privErr := srvErrors.GetInsufficientPrivilegesError(err); privErr != nil {
+		missing = privErr.Missing
+	}
+	var code string
+	var fields map[string]string
+	if ve := srvErrors.GetValidationError(err); ve != nil && len(ve.Fields) > 0 {
+		code, fields = validationCode, ve.Fields
+	}
+
+	if c.NegotiateFormat(binding.MIMEJSON, problemJSONContentType) != problemJSONContentType {
+		body := gin.H{"error": err.Error()}
+		if missing != nil {
+			body["missingPrivileges"] = missing
+		}
+		if fields != nil {
+			body["code"] = code
+			body["fields"] = fields
+		}
+		c.JSON(status, body)
+		return
+	}
+
+	// Set before rendering, gin only fills in the content type when missing.
+	c.Header("Content-Type", problemJSONContentType)
+	c.JSON(status, problem{
+		Type:              problemType(err),
+		Title:             http.StatusText(status),
+		Status:            status,
+		Detail:            err.Error(),
+		MissingPrivileges: missing,
+		Code:              code,
+		Fields:            fields,
+	})
+}
+
+// MethodNotAllowed answers a request whose path exists but not for its method
+// with 405 in the error response shape. Gin sets the Allow header, listing the
+// path's methods, before calling it. It is installed with the engine's NoMethod.
+func MethodNotAllowed(c *gin.Context) {
+	err := fmt.Errorf("method %s is not allowed on %s", c.Request.Method, c.Request.URL.Path)
+	if allow := c.Writer.Header().Get("Allow"); allow != "" {
+		err = fmt.Errorf("%w; allowed: %s", err, allow)
+	}
+	respondError(c, http.StatusMethodNotAllowed, err)
+}
+
+// problemType names the pkg/errors type behind err. Errors without a type get
+// "about:blank", meaning the status code alone describes the problem.
+func problemType(err error) string {
+	kinds := []struct {
+		is   func(error) bool
+		name string
+	}{
+		{srvErrors.IsResourceNotFoundError, "resource-not-found"},
+		{srvErrors.IsDuplicateResourceError, "duplicate-resource"},
+		{srvErrors.IsValidationError, "validation"},
+		{srvErrors.IsOperationInProgressError, "operation-in-progress"},
+		{srvErrors.IsInvalidStateError, "invalid-state"},
+		{srvErrors.IsModeConflictError, "mode-conflict"},
+		{srvErrors.IsModeChangeInProgressError, "mode-change-in-progress"},
+		{srvErrors.IsAgentNotConnectedError, "agent-not-connected"},
+		{srvErrors.IsMaintenanceModeError, "maintenance-mode"},
+		{srvErrors.IsRequestTimeoutError, "request-timeout"},
+		{srvErrors.IsVCenterCertificateError, "vcenter-certificate"},
+		{srvErrors.IsPermissionError, "vcenter-permission"},
+		{srvErrors.IsVCenterError, "vcenter"},
+		{srvErrors.IsCredentialsError, "credentials"},
+		{srvErrors.IsCredentialsNotSetError, "credentials-not-set"},
+		{srvErrors.IsInsufficientPrivilegesError, "insufficient-privileges"},
+		{srvErrors.IsInspectorNotRunningError, "inspector-not-running"},
+		{srvErrors.IsInspectionLimitReachedError, "inspection-limit-reached"},
+		{srvErrors.IsForecasterNotRunningError, "forecaster-not-running"},
+		{srvErrors.IsForecasterLimitReachedError, "forecaster-limit-reached"},
+		{srvErrors.IsCollectionFailedError, "collection-failed"},
+		{srvErrors.IsSourceNotRegisteredError, "source-not-registered"},
+		{srvErrors.IsConsoleClientError, "console-client"},
+		{srvErrors.IsConsoleUnreachableError, "console-unreachable"},
+	}
+	for _, k := range kinds {
+		if k.is(err) {
+			return problemTypePrefix + k.name
+		}
+	}
+	return "about:blank"
+}
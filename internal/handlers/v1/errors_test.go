@@ -0,0 +1,265 @@
+package v1_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+var _ = Describe("Error responses", func() {
+	var (
+		mockInventory *MockInventoryService
+		router        *gin.Engine
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		mockInventory = &MockInventoryService{
+			DiffError: srvErrors.NewResourceNotFoundError("inventory snapshot", ""),
+		}
+		handler := handlers.NewHandler(config.Configuration{}).
+			WithInventoryService(mockInventory).
+			WithCollectorService(&MockCollectorService{
+				StartError: srvErrors.NewCollectionInProgressError(),
+			})
+		router = gin.New()
+		router.GET("/inventory/diff", handler.GetInventoryDiff)
+		router.POST("/collector", handler.StartCollector)
+	})
+
+	getDiff := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/inventory/diff", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	startCollector := func(accept string) *httptest.ResponseRecorder {
+		body := `{"url": "https://vcenter.example.com", "username": "admin", "password": "secret"}`
+		req := httptest.NewRequest(http.MethodPost, "/collector", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	decode := func(w *httptest.ResponseRecorder) map[string]any {
+		var response map[string]any
+		Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+		return response
+	}
+
+	Context("default format", func() {
+		// Given a client that does not send Accept
+		// When the handler returns 404
+		// Then the body should keep the {"error"} shape
+		It("should return the error shape for a 404", func() {
+			// Act
+			w := getDiff("")
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+			Expect(decode(w)).To(Equal(map[string]any{"error": "inventory snapshot not found"}))
+		})
+
+		// Given a client accepting any media type
+		// When the handler returns 409
+		// Then the body should keep the {"error"} shape
+		It("should return the error shape for a 409", func() {
+			// Act
+			w := startCollector("*/*")
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusConflict))
+			Expect(w.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+			Expect(decode(w)).To(Equal(map[string]any{"error": "collection already in progress"}))
+		})
+	})
+
+	Context("problem+json format", func() {
+		// Given a client sending Accept: application/problem+json
+		// When the handler returns 404
+		// Then the body should be a problem derived from ResourceNotFoundError
+		It("should return a problem for a 404", func() {
+			// Act
+			w := getDiff("application/problem+json")
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/problem+json"))
+			Expect(decode(w)).To(Equal(map[string]any{
+				"type":   "urn:assisted-migration-agent:error:resource-not-found",
+				"title":  "Not Found",
+				"status": float64(http.StatusNotFound),
+				"detail": "inventory snapshot not found",
+			}))
+		})
+
+		// Given a client preferring problem+json over plain JSON
+		// When the handler returns 409
+		// Then the body should be a problem derived from OperationInProgressError
+		It("should return a problem for a 409", func() {
+			// Act
+			w := startCollector("application/problem+json, application/json;q=0.5")
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusConflict))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/problem+json"))
+			Expect(decode(w)).To(Equal(map[string]any{
+				"type":   "urn:assisted-migration-agent:error:operation-in-progress",
+				"title":  "Conflict",
+				"status": float64(http.StatusConflict),
+				"detail": "collection already in progress",
+			}))
+		})
+
+		// Given an error without a pkg/errors type
+		// When it is returned as problem+json
+		// Then the type should be about:blank
+		It("should use about:blank for untyped errors", func() {
+			// Arrange
+			mockInventory.DiffError = errors.New("database error")
+
+			// Act
+			w := getDiff("application/problem+json")
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			response := decode(w)
+			Expect(response["type"]).To(Equal("about:blank"))
+			Expect(response["title"]).To(Equal("Internal Server Error"))
+			Expect(response["detail"]).To(Equal("database error"))
+		})
+	})
+
+	Context("timed out request", func() {
+		// Given a request whose context ran past the server timeout
+		// When the handler fails with its own error
+		// Then the response should be 503 with the timeout error instead
+		It("should answer 503 with the request timeout error", func() {
+			// Arrange
+			mockInventory.DiffError = context.DeadlineExceeded
+			ctx, cancel := context.WithDeadlineCause(context.Background(), time.Now().Add(-time.Second), srvErrors.NewRequestTimeoutError(30*time.Second))
+			defer cancel()
+			req := httptest.NewRequest(http.MethodGet, "/inventory/diff", nil).WithContext(ctx)
+			req.Header.Set("Accept", "application/problem+json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(decode(w)).To(Equal(map[string]any{
+				"type":   "urn:assisted-migration-agent:error:request-timeout",
+				"title":  "Service Unavailable",
+				"status": float64(http.StatusServiceUnavailable),
+				"detail": "request timed out after 30s",
+			}))
+		})
+
+		// Given a request canceled by the client rather than timed out
+		// When the handler fails
+		// Then the handler's own status should be kept
+		It("should keep the handler status for a canceled request", func() {
+			// Arrange
+			mockInventory.DiffError = context.Canceled
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			req := httptest.NewRequest(http.MethodGet, "/inventory/diff", nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
+
+var _ = Describe("MethodNotAllowed", func() {
+	var router *gin.Engine
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.HandleMethodNotAllowed = true
+		router.NoMethod(handlers.MethodNotAllowed)
+		v1.RegisterHandlers(router.Group("/api/v1"), handlers.NewHandler(config.Configuration{}))
+	})
+
+	send := func(method, path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	allowed := func(w *httptest.ResponseRecorder) []string {
+		return strings.Split(w.Header().Get("Allow"), ", ")
+	}
+
+	// Given the collector routes (GET, POST, DELETE)
+	// When /collector is called with PUT
+	// Then it should answer 405 with the collector methods in Allow and the error shape
+	It("should answer 405 for a wrong method on /collector", func() {
+		// Act
+		w := send(http.MethodPut, "/api/v1/collector", "")
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(allowed(w)).To(ConsistOf(http.MethodGet, http.MethodPost, http.MethodDelete))
+		var body map[string]any
+		Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+		Expect(body["error"]).To(HavePrefix("method PUT is not allowed on /api/v1/collector"))
+	})
+
+	// Given the VM list route (GET only)
+	// When /vms is called with DELETE, asking for problem+json
+	// Then it should answer a 405 problem with GET in Allow
+	It("should answer a 405 problem for a wrong method on /vms", func() {
+		// Act
+		w := send(http.MethodDelete, "/api/v1/vms", "application/problem+json")
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(allowed(w)).To(ConsistOf(http.MethodGet))
+		Expect(w.Header().Get("Content-Type")).To(HavePrefix("application/problem+json"))
+		var body map[string]any
+		Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+		Expect(body["status"]).To(BeEquivalentTo(http.StatusMethodNotAllowed))
+		Expect(body["title"]).To(Equal("Method Not Allowed"))
+		Expect(body["detail"]).To(Equal("method DELETE is not allowed on /api/v1/vms; allowed: GET"))
+	})
+
+	It("should keep 404 for unknown paths", func() {
+		w := send(http.MethodGet, "/api/v1/nonexistent", "")
+
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+})
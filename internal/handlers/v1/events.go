@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+var _ = registerCapability("events", "Server-Sent Events stream of collector, inspector and agent status changes (GET /events)")
+
+// eventsKeepAlive is how often an idle event stream gets a comment line, so
+// proxies do not close it.
+const eventsKeepAlive = 15 * time.Second
+
+// StreamEvents streams collector, inspector and agent status changes as
+// Server-Sent Events until the client disconnects
+// (GET /events)
+func (h *Handler) StreamEvents(c *gin.Context) {
+	events, unsubscribe := h.statusBroker.Subscribe()
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	// Start with the current status of each source, so clients need no
+	// extra GET to know where they stand.
+	if h.collectorSrv != nil {
+		writeStatusEvent(c, models.StatusEvent{Source: models.StatusEventCollector, Status: h.collectorSrv.GetStatus()})
+	}
+	if h.inspectorSrv != nil {
+		writeStatusEvent(c, models.StatusEvent{Source: models.StatusEventInspector, Status: h.inspectorSrv.GetStatus()})
+	}
+	if h.consoleSrv != nil {
+		writeStatusEvent(c, models.StatusEvent{Source: models.StatusEventAgent, Status: h.consoleSrv.Status()})
+	}
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			writeStatusEvent(c, event)
+		case <-keepAlive.C:
+			if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeStatusEvent writes event as an SSE frame named after its source, with
+// the same JSON body as the source's GET endpoint.
+func writeStatusEvent(c *gin.Context, event models.StatusEvent) {
+	var data any
+	switch status := event.Status.(type) {
+	case models.CollectorStatus:
+		data = v1.NewCollectorStatus(status)
+	case models.InspectorStatus:
+		data = v1.NewInspectorStatus(status)
+	case models.ConsoleStatus:
+		var agent v1.AgentStatus
+		agent.FromModel(models.AgentStatus{Console: status})
+		data = agent
+	default:
+		return
+	}
+	c.SSEvent(string(event.Source), data)
+}
@@ -0,0 +1,170 @@
+package v1_test
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	"github.com/kubev2v/assisted-migration-agent/pkg/work"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+// sseFrame is one event read from a Server-Sent Events stream.
+type sseFrame struct {
+	event string
+	data  string
+}
+
+var _ = Describe("Events Handler", func() {
+	var (
+		ctx       context.Context
+		db        *sql.DB
+		collector *services.CollectorService
+		server    *httptest.Server
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		gin.SetMode(gin.TestMode)
+
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(migrations.Run(ctx, db)).To(Succeed())
+		st := store.NewStore(db, test.NewMockValidator())
+
+		// A collection that stays in collecting until stopped.
+		broker := services.NewStatusBroker()
+		collector = services.NewCollectorService(services.NewInventoryService(st), func(_ models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+			return work.NewSliceWorkBuilder([]work.WorkUnit[models.CollectorStatus, models.CollectorResult]{
+				{
+					Status: func() models.CollectorStatus {
+						return models.CollectorStatus{State: models.CollectorStateCollecting}
+					},
+					Work: func(ctx context.Context, r models.CollectorResult) (models.CollectorResult, error) {
+						<-ctx.Done()
+						return r, ctx.Err()
+					},
+				},
+			})
+		}).WithStatusBroker(broker)
+
+		handler := handlers.NewHandler(config.Configuration{}).
+			WithCollectorService(collector).
+			WithStatusBroker(broker)
+		router := gin.New()
+		router.GET("/events", handler.StreamEvents)
+		server = httptest.NewServer(router)
+	})
+
+	AfterEach(func() {
+		collector.Stop()
+		// Streams never end on their own; drop them so Close does not wait.
+		server.CloseClientConnections()
+		server.Close()
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	// connect opens the stream and returns its frames.
+	connect := func() <-chan sseFrame {
+		resp, err := http.Get(server.URL + "/events")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/event-stream"))
+
+		frames := make(chan sseFrame, 16)
+		go func() {
+			defer close(frames)
+			defer func() { _ = resp.Body.Close() }()
+			var frame sseFrame
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				line := scanner.Text()
+				switch {
+				case strings.HasPrefix(line, "event:"):
+					frame.event = strings.TrimPrefix(line, "event:")
+				case strings.HasPrefix(line, "data:"):
+					frame.data = strings.TrimPrefix(line, "data:")
+				case line == "" && frame.event != "":
+					frames <- frame
+					frame = sseFrame{}
+				}
+			}
+		}()
+		return frames
+	}
+
+	collectorState := func(frame sseFrame) v1.CollectorStatusStatus {
+		var status v1.CollectorStatus
+		Expect(json.Unmarshal([]byte(frame.data), &status)).To(Succeed())
+		return status.Status
+	}
+
+	// nextCollectorState returns the state of the next collector frame, or an
+	// empty state when none is pending.
+	nextCollectorState := func(frames <-chan sseFrame) func() v1.CollectorStatusStatus {
+		return func() v1.CollectorStatusStatus {
+			select {
+			case frame := <-frames:
+				if frame.event != "collector" {
+					return ""
+				}
+				return collectorState(frame)
+			default:
+				return ""
+			}
+		}
+	}
+
+	// Given a connected client
+	// When it connects
+	// Then it should receive the current collector status first
+	It("should send the current status on connect", func() {
+		// Act
+		frames := connect()
+
+		// Assert
+		var frame sseFrame
+		Eventually(frames).Should(Receive(&frame))
+		Expect(frame.event).To(Equal("collector"))
+		Expect(collectorState(frame)).To(Equal(v1.CollectorStatusStatusReady))
+	})
+
+	// Given a connected client
+	// When a collection starts and is stopped
+	// Then the client should receive the collector state changes
+	It("should stream collector state changes", func() {
+		// Arrange
+		frames := connect()
+		Eventually(frames).Should(Receive())
+
+		// Act
+		Expect(collector.Start(ctx, models.Credentials{URL: "https://vcenter.example.com", Username: "admin", Password: "secret"})).To(Succeed())
+
+		// Assert
+		Eventually(nextCollectorState(frames)).Should(Equal(v1.CollectorStatusStatusCollecting))
+
+		// Act
+		collector.Stop()
+
+		// Assert
+		Eventually(nextCollectorState(frames)).Should(Equal(v1.CollectorStatusStatusReady))
+	})
+})
@@ -0,0 +1,220 @@
+package v1
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	"go.uber.org/zap"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+var (
+	_ = registerCapability("export", "tar.gz download of the inventory, a CSV VM list and the agent config (GET /export)")
+	_ = registerCapability("inventory.export", "tar.gz download of the inventory with one JSON file per cluster (GET /inventory/export?groupBy=cluster)")
+)
+
+const (
+	exportFilename          = "assisted-migration-agent-export.tar.gz"
+	clusterExportFilename   = "assisted-migration-agent-clusters.tar.gz"
+	vcenterExportMemberName = "vcenter.json"
+)
+
+// exportMember is one file of an export archive.
+type exportMember struct {
+	name string
+	data []byte
+}
+
+var vmsCSVHeader = []string{
+	"id", "name", "powerState", "cluster", "datacenter", "memoryMB", "diskSizeMB",
+	"issueCount", "criticalCount", "warningCount", "migratable", "template",
+}
+
+// ExportInventory streams a tar.gz with the inventory, the VM list and the
+// redacted agent configuration
+// (GET /export)
+func (h *Handler) ExportInventory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	inv, err := h.inventorySrv.GetInventory(ctx)
+	if err != nil {
+		if srvErrors.IsResourceNotFoundError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	vms, _, err := h.vmSrv.List(ctx, services.VMListParams{})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to list VMs: %v", err))
+		return
+	}
+
+	config, err := json.MarshalIndent(h.agentConfig(), "", "  ")
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	vmsCSV, err := writeVMsCSV(vms)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Everything that can fail is built above; from here on the status is sent
+	// and errors can only be logged.
+	writeExportArchive(c, exportFilename, []exportMember{
+		{"inventory.json", inv.Data},
+		{"vms.csv", vmsCSV},
+		{"config.json", config},
+	})
+}
+
+// ExportInventoryByGroup streams a tar.gz with the inventory split per group:
+// one <clusterId>.json per cluster holding its InventoryData, and
+// vcenter.json with the vCenter-wide aggregate
+// (GET /inventory/export)
+func (h *Handler) ExportInventoryByGroup(c *gin.Context, params v1.ExportInventoryByGroupParams) {
+	if params.GroupBy != v1.ExportInventoryByGroupParamsGroupByCluster {
+		respondError(c, http.StatusBadRequest, srvErrors.NewFieldValidationError(map[string]string{
+			"groupBy": "must be cluster",
+		}))
+		return
+	}
+
+	inv, err := h.inventorySrv.GetInventory(c.Request.Context())
+	if err != nil {
+		if srvErrors.IsResourceNotFoundError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var inventory v1alpha1.Inventory
+	if err := json.Unmarshal(inv.Data, &inventory); err != nil {
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to parse inventory: %v", err))
+		return
+	}
+
+	members, err := clusterExportMembers(inventory)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeExportArchive(c, clusterExportFilename, members)
+}
+
+// clusterExportMembers renders each cluster of inv as <clusterId>.json, in
+// cluster ID order, followed by vcenter.json when inv has the vCenter-wide
+// aggregate.
+func clusterExportMembers(inv v1alpha1.Inventory) ([]exportMember, error) {
+	members := make([]exportMember, 0, len(inv.Clusters)+1)
+	for _, id := range slices.Sorted(maps.Keys(inv.Clusters)) {
+		data, err := json.MarshalIndent(inv.Clusters[id], "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cluster %s: %w", id, err)
+		}
+		members = append(members, exportMember{id + ".json", data})
+	}
+
+	if inv.Vcenter != nil {
+		data, err := json.MarshalIndent(inv.Vcenter, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode vCenter inventory: %w", err)
+		}
+		members = append(members, exportMember{vcenterExportMemberName, data})
+	}
+
+	return members, nil
+}
+
+// writeExportArchive sends members as a tar.gz attachment named filename. The
+// status is sent first, so write errors can only be logged.
+func writeExportArchive(c *gin.Context, filename string, members []exportMember) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Content-Type", "application/gzip")
+	c.Status(http.StatusOK)
+
+	gz := gzip.NewWriter(c.Writer)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, m := range members {
+		if err := writeTarMember(tw, m.name, m.data, now); err != nil {
+			zap.S().Named("export_handler").Errorw("failed to write export archive", "member", m.name, "error", err)
+			return
+		}
+	}
+	if err := tw.Close(); err != nil {
+		zap.S().Named("export_handler").Errorw("failed to close export archive", "error", err)
+		return
+	}
+	if err := gz.Close(); err != nil {
+		zap.S().Named("export_handler").Errorw("failed to close export archive", "error", err)
+	}
+}
+
+func writeTarMember(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, bytes.NewReader(data))
+	return err
+}
+
+// writeVMsCSV renders one row per VM with the columns of vmsCSVHeader.
+func writeVMsCSV(vms []models.VirtualMachineSummary) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(vmsCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, vm := range vms {
+		if err := w.Write([]string{
+			vm.ID,
+			vm.Name,
+			vm.PowerState,
+			vm.Cluster,
+			vm.Datacenter,
+			strconv.Itoa(int(vm.Memory)),
+			strconv.FormatInt(vm.DiskSize, 10),
+			strconv.Itoa(vm.IssueCount),
+			strconv.Itoa(vm.CriticalCount),
+			strconv.Itoa(vm.WarningCount),
+			strconv.FormatBool(vm.IsMigratable),
+			strconv.FormatBool(vm.IsTemplate),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
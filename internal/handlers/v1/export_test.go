@@ -0,0 +1,252 @@
+package v1_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+// readExport returns the members of a tar.gz archive keyed by name.
+func readExport(body []byte) map[string][]byte {
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	Expect(err).NotTo(HaveOccurred())
+	tr := tar.NewReader(gz)
+
+	members := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		Expect(err).NotTo(HaveOccurred())
+		data, err := io.ReadAll(tr)
+		Expect(err).NotTo(HaveOccurred())
+		members[hdr.Name] = data
+	}
+	return members
+}
+
+var _ = Describe("Export Handler", func() {
+	var (
+		ctx    context.Context
+		db     *sql.DB
+		st     *store.Store
+		router *gin.Engine
+		cfg    config.Configuration
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		gin.SetMode(gin.TestMode)
+
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+
+		st = store.NewStore(db, test.NewMockValidator())
+		Expect(st.Migrate(ctx)).To(Succeed())
+		Expect(test.InsertVMs(ctx, db)).To(Succeed())
+
+		cfg = config.Configuration{Agent: config.Agent{
+			ID:                    "agent-1",
+			Version:               "v1.2.3",
+			CredentialsSecretPath: "/run/secrets/credentials",
+		}}
+		handler := handlers.NewHandler(cfg).
+			WithInventoryService(services.NewInventoryService(st)).
+			WithVMService(services.NewVMService(st))
+		router = gin.New()
+		router.GET("/export", handler.ExportInventory)
+		router.GET("/inventory/export", func(c *gin.Context) {
+			var params v1.ExportInventoryByGroupParams
+			if err := c.ShouldBindQuery(&params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			handler.ExportInventoryByGroup(c, params)
+		})
+	})
+
+	AfterEach(func() {
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	// Given a collected inventory
+	// When we download the export
+	// Then the archive should contain the inventory, the VM list and the config
+	It("should stream an archive with inventory, VMs and config", func() {
+		// Arrange
+		inventory := []byte(`{"vcenter_id":"vc-1","clusters":{},"vcenter":{}}`)
+		Expect(st.Inventory().Save(ctx, inventory)).To(Succeed())
+
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get("Content-Type")).To(Equal("application/gzip"))
+		Expect(w.Header().Get("Content-Disposition")).To(ContainSubstring("attachment"))
+
+		members := readExport(w.Body.Bytes())
+		Expect(members).To(HaveLen(3))
+		Expect(members).To(HaveKey("inventory.json"))
+		Expect(members).To(HaveKey("vms.csv"))
+		Expect(members).To(HaveKey("config.json"))
+
+		var inv map[string]any
+		Expect(json.Unmarshal(members["inventory.json"], &inv)).To(Succeed())
+		Expect(inv["vcenter_id"]).To(Equal("vc-1"))
+
+		rows, err := csv.NewReader(bytes.NewReader(members["vms.csv"])).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rows).To(HaveLen(len(test.VMs) + 1))
+		Expect(rows[0][0]).To(Equal("id"))
+		Expect(rows[1][:2]).To(Equal([]string{"vm-001", "web-server-1"}))
+
+		var agentCfg v1.AgentConfig
+		Expect(json.Unmarshal(members["config.json"], &agentCfg)).To(Succeed())
+		Expect(agentCfg.Id).To(Equal("agent-1"))
+		Expect(agentCfg.Version).To(Equal("v1.2.3"))
+		Expect(string(members["config.json"])).NotTo(ContainSubstring("/run/secrets"))
+	})
+
+	// Given no inventory has been collected
+	// When we download the export
+	// Then it should return 404 without an archive
+	It("should return 404 when no inventory exists", func() {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+		Expect(w.Header().Get("Content-Type")).To(ContainSubstring("application/json"))
+	})
+
+	// Given the inventory cannot be read
+	// When we download the export
+	// Then it should return 500
+	It("should return 500 when the inventory cannot be read", func() {
+		// Arrange
+		mock := &MockInventoryService{InventoryError: errors.New("database error")}
+		handler := handlers.NewHandler(cfg).WithInventoryService(mock)
+		router = gin.New()
+		router.GET("/export", handler.ExportInventory)
+
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusInternalServerError))
+	})
+
+	Context("ExportInventoryByGroup", func() {
+		// Given an inventory with two clusters and a vCenter aggregate
+		// When we download it grouped by cluster
+		// Then the archive should hold one valid JSON member per cluster and
+		// one for the vCenter
+		It("should stream one member per cluster plus the vCenter", func() {
+			// Arrange
+			inventory := []byte(`{
+				"vcenter_id": "vc-1",
+				"clusters": {
+					"domain-c1": {"vms": {"total": 4}},
+					"domain-c2": {"vms": {"total": 6}}
+				},
+				"vcenter": {"vms": {"total": 10}}
+			}`)
+			Expect(st.Inventory().Save(ctx, inventory)).To(Succeed())
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/export?groupBy=cluster", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/gzip"))
+			Expect(w.Header().Get("Content-Disposition")).To(ContainSubstring("attachment"))
+
+			members := readExport(w.Body.Bytes())
+			Expect(members).To(HaveLen(3))
+			totals := map[string]any{}
+			for name, data := range members {
+				var member map[string]any
+				Expect(json.Unmarshal(data, &member)).To(Succeed(), name)
+				Expect(member).To(HaveKey("vms"), name)
+				totals[name] = member["vms"].(map[string]any)["total"]
+			}
+			Expect(totals).To(Equal(map[string]any{
+				"domain-c1.json": float64(4),
+				"domain-c2.json": float64(6),
+				"vcenter.json":   float64(10),
+			}))
+		})
+
+		// Given a groupBy value other than cluster
+		// When we download the export
+		// Then it should return 400 naming the groupBy field
+		It("should return 400 for an unknown groupBy", func() {
+			// Arrange
+			Expect(st.Inventory().Save(ctx, []byte(`{"vcenter_id":"vc-1","clusters":{}}`))).To(Succeed())
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/export?groupBy=datacenter", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			var body map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body["fields"]).To(HaveKey("groupBy"))
+		})
+
+		// Given no inventory has been collected
+		// When we download it grouped by cluster
+		// Then it should return 404 without an archive
+		It("should return 404 when no inventory exists", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/inventory/export?groupBy=cluster", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
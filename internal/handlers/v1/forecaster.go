@@ -2,6 +2,7 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -12,6 +13,8 @@ import (
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
+var _ = registerCapability("forecaster", "Datastore copy throughput forecasts (/forecaster)")
+
 // ForecasterService defines the interface for forecaster operations.
 type ForecasterService interface {
 	Start(ctx context.Context, req models.ForecastRequest) error
@@ -32,7 +35,7 @@ type ForecasterService interface {
 func (h *Handler) StartForecaster(c *gin.Context) {
 	var req v1api.ForecasterStartRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
 		return
 	}
 
@@ -70,22 +73,26 @@ func (h *Handler) StartForecaster(c *gin.Context) {
 
 	if err := h.forecasterSrv.Start(c.Request.Context(), forecastReq); err != nil {
 		if srvErrors.IsOperationInProgressError(err) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			respondError(c, http.StatusConflict, err)
 			return
 		}
 		if srvErrors.IsCredentialsNotSetError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "credentials required: provide credentials inline"})
+			respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("credentials required: provide credentials inline"))
 			return
 		}
 		if srvErrors.IsForecasterLimitReachedError(err) || srvErrors.IsValidationError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		if srvErrors.IsPermissionError(err) {
+			respondError(c, http.StatusForbidden, err)
 			return
 		}
 		if srvErrors.IsVCenterError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to start forecaster: %v", err)})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to start forecaster: %v", err))
 		return
 	}
 
@@ -137,10 +144,10 @@ func (h *Handler) GetForecasterStatus(c *gin.Context) {
 func (h *Handler) StopForecaster(c *gin.Context) {
 	if err := h.forecasterSrv.Stop(); err != nil {
 		if srvErrors.IsForecasterNotRunningError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -152,7 +159,7 @@ func (h *Handler) StopForecaster(c *gin.Context) {
 func (h *Handler) PutForecasterCredentials(c *gin.Context) {
 	var req v1api.VcenterCredentials
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
 		return
 	}
 
@@ -163,18 +170,15 @@ func (h *Handler) PutForecasterCredentials(c *gin.Context) {
 	}
 
 	if err := h.forecasterSrv.VerifyCredentials(c.Request.Context(), creds); err != nil {
-		if privErr := srvErrors.GetInsufficientPrivilegesError(err); privErr != nil {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error":             err.Error(),
-				"missingPrivileges": privErr.Missing,
-			})
+		if srvErrors.IsInsufficientPrivilegesError(err) || srvErrors.IsPermissionError(err) {
+			respondError(c, http.StatusForbidden, err)
 			return
 		}
 		if srvErrors.IsVCenterError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -191,7 +195,7 @@ func (h *Handler) GetForecasterRuns(c *gin.Context, params v1api.GetForecasterRu
 
 	runs, err := h.forecasterSrv.ListRuns(c.Request.Context(), pairName)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -228,10 +232,10 @@ func (h *Handler) GetForecasterRuns(c *gin.Context, params v1api.GetForecasterRu
 func (h *Handler) DeleteForecasterRun(c *gin.Context, id int64) {
 	if err := h.forecasterSrv.DeleteRun(c.Request.Context(), id); err != nil {
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -245,7 +249,7 @@ func (h *Handler) GetForecasterStats(c *gin.Context, params v1api.GetForecasterS
 
 	stats, err := h.forecasterSrv.GetStats(c.Request.Context(), pairName)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -286,7 +290,7 @@ func (h *Handler) GetForecasterDatastores(c *gin.Context) {
 
 	datastores, err := h.forecasterSrv.ListDatastores(c.Request.Context(), models.Credentials{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -328,7 +332,7 @@ func (h *Handler) GetForecasterDatastores(c *gin.Context) {
 func (h *Handler) PostForecasterPairCapabilities(c *gin.Context) {
 	var req v1api.PairCapabilityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
 		return
 	}
 
@@ -347,10 +351,10 @@ func (h *Handler) PostForecasterPairCapabilities(c *gin.Context) {
 	caps, err := h.forecasterSrv.PairCapabilities(c.Request.Context(), models.Credentials{}, models.PairCapabilityRequest{Pairs: pairs})
 	if err != nil {
 		if srvErrors.IsValidationError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -378,14 +382,14 @@ func (h *Handler) StopForecasterPair(c *gin.Context, name string) {
 
 	if err := h.forecasterSrv.StopPair(pairName); err != nil {
 		if srvErrors.IsForecasterNotRunningError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "no benchmark is running"})
+			respondError(c, http.StatusNotFound, errors.New("no benchmark is running"))
 			return
 		}
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("pair %q not found or already finished", pairName)})
+			respondError(c, http.StatusNotFound, fmt.Errorf("pair %q not found or already finished", pairName))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -15,6 +15,8 @@ import (
 	"github.com/kubev2v/assisted-migration-agent/pkg/filter"
 )
 
+var _ = registerCapability("groups", "Named groups of VMs selected by a filter expression (/groups)")
+
 // ListGroups returns groups with optional name filtering and pagination
 // (GET /groups)
 func (h *Handler) ListGroups(c *gin.Context, params v1.ListGroupsParams) {
@@ -25,7 +27,7 @@ func (h *Handler) ListGroups(c *gin.Context, params v1.ListGroupsParams) {
 
 	pageSize := defaultPageSize
 	if params.PageSize != nil && *params.PageSize > 0 {
-		pageSize = min(*params.PageSize, maxPageSize)
+		pageSize = min(*params.PageSize, h.maxPageSize())
 	}
 
 	svcParams := services.GroupListParams{
@@ -41,7 +43,7 @@ func (h *Handler) ListGroups(c *gin.Context, params v1.ListGroupsParams) {
 
 	groups, total, err := h.groupSrv.List(c.Request.Context(), svcParams)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -68,18 +70,21 @@ func (h *Handler) ListGroups(c *gin.Context, params v1.ListGroupsParams) {
 func (h *Handler) CreateGroup(c *gin.Context) {
 	var req v1.CreateGroupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
 		return
 	}
 
 	req.Name = strings.TrimSpace(req.Name)
 	if req.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be blank"})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("name must not be blank"))
 		return
 	}
 
+	if h.filterTooLong(c, req.Filter) {
+		return
+	}
 	if _, err := filter.ParseWithDefaultMap([]byte(req.Filter)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("filter is invalid: %v", err)})
+		respondError(c, http.StatusBadRequest, fmt.Errorf("filter is invalid: %v", err))
 		return
 	}
 
@@ -97,10 +102,10 @@ func (h *Handler) CreateGroup(c *gin.Context) {
 	created, err := h.groupSrv.Create(c.Request.Context(), group)
 	if err != nil {
 		if srvErrors.IsDuplicateResourceError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -112,17 +117,17 @@ func (h *Handler) CreateGroup(c *gin.Context) {
 func (h *Handler) GetGroup(c *gin.Context, id string, params v1.GetGroupParams) {
 	groupID, err := strconv.Atoi(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("invalid group id"))
 		return
 	}
 
 	group, err := h.groupSrv.Get(c.Request.Context(), groupID)
 	if err != nil {
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -133,7 +138,7 @@ func (h *Handler) GetGroup(c *gin.Context, id string, params v1.GetGroupParams)
 
 	pageSize := defaultPageSize
 	if params.PageSize != nil && *params.PageSize > 0 {
-		pageSize = min(*params.PageSize, maxPageSize)
+		pageSize = min(*params.PageSize, h.maxPageSize())
 	}
 
 	svcParams := services.GroupGetParams{
@@ -145,16 +150,16 @@ func (h *Handler) GetGroup(c *gin.Context, id string, params v1.GetGroupParams)
 		for _, s := range *params.Sort {
 			parts := strings.SplitN(s, ":", 2)
 			if len(parts) != 2 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort format, expected 'field:direction' (e.g., 'name:asc')"})
+				respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("invalid sort format, expected 'field:direction' (e.g., 'name:asc')"))
 				return
 			}
 			field, direction := parts[0], parts[1]
 			if !validSortFields[field] {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort field: " + field})
+				respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("invalid sort field: "+field))
 				return
 			}
 			if direction != "asc" && direction != "desc" {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort direction: " + direction + ", must be 'asc' or 'desc'"})
+				respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("invalid sort direction: "+direction+", must be 'asc' or 'desc'"))
 				return
 			}
 			svcParams.Sort = append(svcParams.Sort, services.SortField{Field: field, Desc: direction == "desc"})
@@ -163,7 +168,7 @@ func (h *Handler) GetGroup(c *gin.Context, id string, params v1.GetGroupParams)
 
 	vms, total, err := h.groupSrv.ListVirtualMachines(c.Request.Context(), groupID, svcParams)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -191,13 +196,13 @@ func (h *Handler) GetGroup(c *gin.Context, id string, params v1.GetGroupParams)
 func (h *Handler) UpdateGroup(c *gin.Context, id string) {
 	groupID, err := strconv.Atoi(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("invalid group id"))
 		return
 	}
 
 	var req v1.UpdateGroupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
 		return
 	}
 
@@ -205,14 +210,17 @@ func (h *Handler) UpdateGroup(c *gin.Context, id string) {
 		trimmed := strings.TrimSpace(*req.Name)
 		req.Name = &trimmed
 		if *req.Name == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be blank"})
+			respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("name must not be blank"))
 			return
 		}
 	}
 
 	if req.Filter != nil {
+		if h.filterTooLong(c, *req.Filter) {
+			return
+		}
 		if _, err := filter.ParseWithDefaultMap([]byte(*req.Filter)); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("filter is invalid: %v", err)})
+			respondError(c, http.StatusBadRequest, fmt.Errorf("filter is invalid: %v", err))
 			return
 		}
 	}
@@ -220,10 +228,10 @@ func (h *Handler) UpdateGroup(c *gin.Context, id string) {
 	existing, err := h.groupSrv.Get(c.Request.Context(), groupID)
 	if err != nil {
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -243,10 +251,10 @@ func (h *Handler) UpdateGroup(c *gin.Context, id string) {
 	updated, err := h.groupSrv.Update(c.Request.Context(), groupID, *existing)
 	if err != nil {
 		if srvErrors.IsDuplicateResourceError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -258,13 +266,13 @@ func (h *Handler) UpdateGroup(c *gin.Context, id string) {
 func (h *Handler) DeleteGroup(c *gin.Context, id string) {
 	groupID, err := strconv.Atoi(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("invalid group id"))
 		return
 	}
 
 	if err := h.groupSrv.Delete(c.Request.Context(), groupID); err != nil {
 		if !srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusInternalServerError, err)
 			return
 		}
 	}
@@ -209,6 +209,25 @@ var _ = Describe("Group Handlers", func() {
 			Expect(resp["error"]).To(ContainSubstring("Name is required"))
 		})
 
+		// Given a filter longer than the default max filter length
+		// When we create a group
+		// Then it should return 413 without calling the service
+		It("should return 413 when the filter is too long", func() {
+			// Arrange
+			filter := "name = '" + strings.Repeat("a", 4096) + "'"
+			body := `{"name":"mygroup","filter":"` + filter + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/groups", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusRequestEntityTooLarge))
+			Expect(mockGroup.LastCreateGroup.Name).To(BeEmpty())
+		})
+
 		It("should return 400 when name exceeds 100 characters", func() {
 			longName := strings.Repeat("a", 101)
 			body := `{"name":"` + longName + `","filter":"name = 'test'"}`
@@ -3,6 +3,10 @@ package v1
 import (
 	"context"
 	"io"
+	"os"
+	"time"
+
+	"go.uber.org/zap/zapcore"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/config"
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
@@ -12,25 +16,47 @@ import (
 // CollectorService defines the interface for collector operations.
 type CollectorService interface {
 	GetStatus() models.CollectorStatus
+	WaitStatus(ctx context.Context, since models.CollectorStateType, timeout time.Duration) models.CollectorStatus
 	Start(ctx context.Context, creds models.Credentials) error
+	Refresh(ctx context.Context, creds models.Credentials, maxAge time.Duration) error
 	Stop()
+	History(ctx context.Context) ([]models.CollectionHistoryEvent, error)
+	TestConnection(ctx context.Context, creds models.Credentials) (*models.VCenterInfo, error)
+	Revalidate(ctx context.Context) (int, error)
 }
 
 // InventoryService defines the interface for inventory operations.
 type InventoryService interface {
 	GetInventory(ctx context.Context) (*models.Inventory, error)
+	GetInventoryAt(ctx context.Context, takenAt time.Time) (*models.Inventory, error)
+	History(ctx context.Context) ([]time.Time, error)
+	Diff(ctx context.Context) (*models.InventoryDiff, error)
+	Metrics(ctx context.Context) (*models.InventoryMetrics, error)
+	Clusters(ctx context.Context, params services.ClusterListParams) ([]models.ClusterMetrics, error)
 }
 
 // ConsoleService defines the interface for console/agent operations.
 type ConsoleService interface {
 	Status() models.ConsoleStatus
 	SetMode(ctx context.Context, mode models.AgentMode) error
+	PushInventory(ctx context.Context) error
+	Settings() models.ConsoleSettings
+	UpdateSettings(ctx context.Context, change models.ConsoleSettingsChange) (models.ConsoleSettings, error)
 }
 
 // VMService defines the interface for VM operations.
 type VMService interface {
 	List(ctx context.Context, params services.VMListParams) ([]models.VirtualMachineSummary, int, error)
+	Count(ctx context.Context, params services.VMListParams) (int, error)
+	Stream(ctx context.Context, params services.VMListParams, fn func([]models.VirtualMachineSummary) error) error
+	Explain(params services.VMListParams) (string, []any, error)
 	Get(ctx context.Context, id string) (*models.VM, error)
+	GetMany(ctx context.Context, ids []string) ([]models.VM, []string, error)
+	OsDistribution(ctx context.Context, params services.VMListParams) (map[string]int, error)
+	GroupBy(ctx context.Context, field string, params services.VMListParams) (map[string]int, error)
+	CreateView(ctx context.Context, view models.VMView) (*models.VMView, error)
+	ListViews(ctx context.Context) ([]models.VMView, error)
+	GetView(ctx context.Context, name string) (*models.VMView, error)
 }
 
 // InspectorService defines the interface for deep inspector operations.
@@ -41,7 +67,12 @@ type InspectorService interface {
 	GetVmStatus(id string) models.InspectionStatus
 	IsBusy() bool
 	Cancel(id string) error
+	CancelVmsInspection(ctx context.Context, ids ...string) ([]models.InspectionCancelResult, error)
 	Stop() error
+	Reset(ctx context.Context) error
+	Prioritize(ctx context.Context, vmIDs []string) error
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
 }
 
 // VddkService defines the interface for vddk operations. Vddk is required for running InspectorService properly.
@@ -68,6 +99,42 @@ type RightsizingService interface {
 	GetVMUtilization(ctx context.Context, vmID string) (*models.VmUtilizationDetails, error)
 }
 
+// MaintenanceService defines the interface for the maintenance mode flag.
+type MaintenanceService interface {
+	Enabled() bool
+	SetEnabled(ctx context.Context, enabled bool) error
+}
+
+// StorageService defines the interface for data folder disk usage and
+// database compaction.
+type StorageService interface {
+	Usage(ctx context.Context) (*models.StorageUsage, error)
+	Compact(ctx context.Context) error
+	OpenDatabase(ctx context.Context) (*os.File, error)
+}
+
+// StatusBroker defines the interface for subscribing to service status changes.
+type StatusBroker interface {
+	Subscribe() (<-chan models.StatusEvent, func())
+}
+
+// CollectorLogs defines the interface for reading recent collection log lines.
+type CollectorLogs interface {
+	Lines(n int) []string
+}
+
+// LogLevel defines the interface for reading and changing the log level at runtime.
+type LogLevel interface {
+	Level() zapcore.Level
+	SetLevel(zapcore.Level)
+}
+
+// PolicyService defines the interface for OPA policy operations.
+type PolicyService interface {
+	Reload() (int, error)
+	Catalog() []models.ConcernDefinition
+}
+
 type Handler struct {
 	cfg            config.Configuration
 	consoleSrv     ConsoleService
@@ -79,6 +146,12 @@ type Handler struct {
 	groupSrv       GroupService
 	rightsizingSrv RightsizingService
 	forecasterSrv  ForecasterService
+	policySrv      PolicyService
+	maintenanceSrv MaintenanceService
+	storageSrv     StorageService
+	statusBroker   StatusBroker
+	collectorLogs  CollectorLogs
+	logLevel       LogLevel
 }
 
 func NewHandler(cfg config.Configuration) *Handler {
@@ -129,3 +202,33 @@ func (h *Handler) WithForecasterService(srv ForecasterService) *Handler {
 	h.forecasterSrv = srv
 	return h
 }
+
+func (h *Handler) WithPolicyService(srv PolicyService) *Handler {
+	h.policySrv = srv
+	return h
+}
+
+func (h *Handler) WithMaintenanceService(srv MaintenanceService) *Handler {
+	h.maintenanceSrv = srv
+	return h
+}
+
+func (h *Handler) WithStorageService(srv StorageService) *Handler {
+	h.storageSrv = srv
+	return h
+}
+
+func (h *Handler) WithStatusBroker(broker StatusBroker) *Handler {
+	h.statusBroker = broker
+	return h
+}
+
+func (h *Handler) WithCollectorLogs(logs CollectorLogs) *Handler {
+	h.collectorLogs = logs
+	return h
+}
+
+func (h *Handler) WithLogLevel(level LogLevel) *Handler {
+	h.logLevel = level
+	return h
+}
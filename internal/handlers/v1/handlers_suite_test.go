@@ -3,7 +3,10 @@ package v1_test
 import (
 	"context"
 	"io"
+	"os"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
@@ -13,6 +16,7 @@ import (
 	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
 func TestHandlers(t *testing.T) {
@@ -28,41 +32,124 @@ var _ = BeforeSuite(func() {
 
 // MockCollectorService is a mock implementation of CollectorService.
 type MockCollectorService struct {
-	StatusResult   models.CollectorStatus
-	StartError     error
-	StartCallCount int
-	StopCallCount  int
+	StatusResult        models.CollectorStatus
+	WaitResult          *models.CollectorStatus
+	WaitSince           models.CollectorStateType
+	WaitTimeout         time.Duration
+	WaitCallCount       int
+	StartError          error
+	StartCallCount      int
+	RefreshMaxAge       time.Duration
+	RefreshCallCount    int
+	StopCallCount       int
+	HistoryResult       []models.CollectionHistoryEvent
+	HistoryError        error
+	TestResult          *models.VCenterInfo
+	TestError           error
+	TestCreds           models.Credentials
+	RevalidateResult    int
+	RevalidateError     error
+	RevalidateCallCount int
 }
 
 func (m *MockCollectorService) GetStatus() models.CollectorStatus {
 	return m.StatusResult
 }
 
+func (m *MockCollectorService) WaitStatus(ctx context.Context, since models.CollectorStateType, timeout time.Duration) models.CollectorStatus {
+	m.WaitCallCount++
+	m.WaitSince = since
+	m.WaitTimeout = timeout
+	if m.WaitResult != nil {
+		return *m.WaitResult
+	}
+	return m.StatusResult
+}
+
 func (m *MockCollectorService) Start(ctx context.Context, creds models.Credentials) error {
 	m.StartCallCount++
 	return m.StartError
 }
 
+func (m *MockCollectorService) Refresh(ctx context.Context, creds models.Credentials, maxAge time.Duration) error {
+	m.RefreshCallCount++
+	m.RefreshMaxAge = maxAge
+	return m.StartError
+}
+
 func (m *MockCollectorService) Stop() {
 	m.StopCallCount++
 }
 
+func (m *MockCollectorService) History(ctx context.Context) ([]models.CollectionHistoryEvent, error) {
+	return m.HistoryResult, m.HistoryError
+}
+
+func (m *MockCollectorService) TestConnection(ctx context.Context, creds models.Credentials) (*models.VCenterInfo, error) {
+	m.TestCreds = creds
+	return m.TestResult, m.TestError
+}
+
+func (m *MockCollectorService) Revalidate(ctx context.Context) (int, error) {
+	m.RevalidateCallCount++
+	return m.RevalidateResult, m.RevalidateError
+}
+
 // MockInventoryService is a mock implementation of InventoryService.
 type MockInventoryService struct {
 	InventoryResult *models.Inventory
 	InventoryError  error
+	SnapshotResult  *models.Inventory
+	SnapshotError   error
+	SnapshotAt      time.Time
+	HistoryResult   []time.Time
+	HistoryError    error
+	DiffResult      *models.InventoryDiff
+	DiffError       error
+	MetricsResult   *models.InventoryMetrics
+	MetricsError    error
+	ClustersResult  []models.ClusterMetrics
+	ClustersError   error
+	ClustersParams  services.ClusterListParams
 }
 
 func (m *MockInventoryService) GetInventory(ctx context.Context) (*models.Inventory, error) {
 	return m.InventoryResult, m.InventoryError
 }
 
+func (m *MockInventoryService) GetInventoryAt(ctx context.Context, takenAt time.Time) (*models.Inventory, error) {
+	m.SnapshotAt = takenAt
+	return m.SnapshotResult, m.SnapshotError
+}
+
+func (m *MockInventoryService) History(ctx context.Context) ([]time.Time, error) {
+	return m.HistoryResult, m.HistoryError
+}
+
+func (m *MockInventoryService) Diff(ctx context.Context) (*models.InventoryDiff, error) {
+	return m.DiffResult, m.DiffError
+}
+
+func (m *MockInventoryService) Metrics(ctx context.Context) (*models.InventoryMetrics, error) {
+	return m.MetricsResult, m.MetricsError
+}
+
+func (m *MockInventoryService) Clusters(ctx context.Context, params services.ClusterListParams) ([]models.ClusterMetrics, error) {
+	m.ClustersParams = params
+	return m.ClustersResult, m.ClustersError
+}
+
 // MockConsoleService is a mock implementation of ConsoleService.
 type MockConsoleService struct {
 	StatusResult     models.ConsoleStatus
 	SetModeError     error
 	SetModeCallCount int
 	LastModeSet      models.AgentMode
+	PushError        error
+	PushCallCount    int
+	SettingsResult   models.ConsoleSettings
+	UpdateError      error
+	LastUpdate       *models.ConsoleSettingsChange
 }
 
 func (m *MockConsoleService) Status() models.ConsoleStatus {
@@ -75,6 +162,29 @@ func (m *MockConsoleService) SetMode(ctx context.Context, mode models.AgentMode)
 	return m.SetModeError
 }
 
+func (m *MockConsoleService) PushInventory(ctx context.Context) error {
+	m.PushCallCount++
+	return m.PushError
+}
+
+func (m *MockConsoleService) Settings() models.ConsoleSettings {
+	return m.SettingsResult
+}
+
+func (m *MockConsoleService) UpdateSettings(ctx context.Context, change models.ConsoleSettingsChange) (models.ConsoleSettings, error) {
+	m.LastUpdate = &change
+	if m.UpdateError != nil {
+		return models.ConsoleSettings{}, m.UpdateError
+	}
+	if change.UpdateInterval != nil {
+		m.SettingsResult.UpdateInterval = change.UpdateInterval
+	}
+	if change.LegacyStatusEnabled != nil {
+		m.SettingsResult.LegacyStatusEnabled = change.LegacyStatusEnabled
+	}
+	return m.SettingsResult, nil
+}
+
 // MockVMService is a mock implementation of VMService.
 type MockVMService struct {
 	ListResult     []models.VirtualMachineSummary
@@ -82,7 +192,23 @@ type MockVMService struct {
 	ListError      error
 	GetResult      *models.VM
 	GetError       error
+	ManyResult     []models.VM
+	ManyNotFound   []string
+	ManyError      error
+	ManyCallCount  int
 	LastListParams services.VMListParams
+	CountError     error
+	CountCallCount int
+	Views          map[string]models.VMView
+	ViewsError     error
+	OsResult       map[string]int
+	OsError        error
+	GroupResult    map[string]int
+	GroupError     error
+	LastGroupField string
+	ExplainSQL     string
+	ExplainArgs    []any
+	ExplainError   error
 }
 
 func (m *MockVMService) List(ctx context.Context, params services.VMListParams) ([]models.VirtualMachineSummary, int, error) {
@@ -90,10 +216,87 @@ func (m *MockVMService) List(ctx context.Context, params services.VMListParams)
 	return m.ListResult, m.ListTotal, m.ListError
 }
 
+func (m *MockVMService) Count(ctx context.Context, params services.VMListParams) (int, error) {
+	m.LastListParams = params
+	m.CountCallCount++
+	return m.ListTotal, m.CountError
+}
+
+// Stream passes ListResult to fn as a single batch, unless ListError is set.
+func (m *MockVMService) Stream(ctx context.Context, params services.VMListParams, fn func([]models.VirtualMachineSummary) error) error {
+	m.LastListParams = params
+	if m.ListError != nil {
+		return m.ListError
+	}
+	if len(m.ListResult) == 0 {
+		return nil
+	}
+	return fn(m.ListResult)
+}
+
+func (m *MockVMService) Explain(params services.VMListParams) (string, []any, error) {
+	m.LastListParams = params
+	return m.ExplainSQL, m.ExplainArgs, m.ExplainError
+}
+
 func (m *MockVMService) Get(ctx context.Context, id string) (*models.VM, error) {
 	return m.GetResult, m.GetError
 }
 
+func (m *MockVMService) GetMany(ctx context.Context, ids []string) ([]models.VM, []string, error) {
+	m.ManyCallCount++
+	return m.ManyResult, m.ManyNotFound, m.ManyError
+}
+
+func (m *MockVMService) OsDistribution(ctx context.Context, params services.VMListParams) (map[string]int, error) {
+	m.LastListParams = params
+	return m.OsResult, m.OsError
+}
+
+func (m *MockVMService) GroupBy(ctx context.Context, field string, params services.VMListParams) (map[string]int, error) {
+	m.LastGroupField = field
+	m.LastListParams = params
+	return m.GroupResult, m.GroupError
+}
+
+func (m *MockVMService) CreateView(ctx context.Context, view models.VMView) (*models.VMView, error) {
+	if m.ViewsError != nil {
+		return nil, m.ViewsError
+	}
+	if _, ok := m.Views[view.Name]; ok {
+		return nil, srvErrors.NewDuplicateResourceError("view", "name", view.Name)
+	}
+	if m.Views == nil {
+		m.Views = map[string]models.VMView{}
+	}
+	view.CreatedAt = time.Now()
+	m.Views[view.Name] = view
+	return &view, nil
+}
+
+func (m *MockVMService) ListViews(ctx context.Context) ([]models.VMView, error) {
+	if m.ViewsError != nil {
+		return nil, m.ViewsError
+	}
+	views := make([]models.VMView, 0, len(m.Views))
+	for _, v := range m.Views {
+		views = append(views, v)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	return views, nil
+}
+
+func (m *MockVMService) GetView(ctx context.Context, name string) (*models.VMView, error) {
+	if m.ViewsError != nil {
+		return nil, m.ViewsError
+	}
+	v, ok := m.Views[name]
+	if !ok {
+		return nil, srvErrors.NewResourceNotFoundError("view", name)
+	}
+	return &v, nil
+}
+
 // MockInspectorService is a mock implementation of InspectorService.
 type MockInspectorService struct {
 	StartError                   error
@@ -106,8 +309,19 @@ type MockInspectorService struct {
 	GetStatusCallCount           int
 	GetVmStatusCallCount         int
 	CancelVmsInspectionCallCount int
+	CancelVmsResult              []models.InspectionCancelResult
+	CancelVmsError               error
+	CanceledVMs                  []string
 	StopCallCount                int
+	ResetError                   error
+	ResetCallCount               int
 	IsBusyResult                 bool
+	PrioritizeError              error
+	PrioritizedVMs               []string
+	PauseError                   error
+	PauseCallCount               int
+	ResumeError                  error
+	ResumeCallCount              int
 }
 
 func (m *MockInspectorService) IsBusy() bool {
@@ -139,11 +353,36 @@ func (m *MockInspectorService) Cancel(id string) error {
 	return m.CancelError
 }
 
+func (m *MockInspectorService) CancelVmsInspection(ctx context.Context, ids ...string) ([]models.InspectionCancelResult, error) {
+	m.CanceledVMs = ids
+	return m.CancelVmsResult, m.CancelVmsError
+}
+
 func (m *MockInspectorService) Stop() error {
 	m.StopCallCount++
 	return m.StopError
 }
 
+func (m *MockInspectorService) Reset(ctx context.Context) error {
+	m.ResetCallCount++
+	return m.ResetError
+}
+
+func (m *MockInspectorService) Prioritize(ctx context.Context, vmIDs []string) error {
+	m.PrioritizedVMs = vmIDs
+	return m.PrioritizeError
+}
+
+func (m *MockInspectorService) Pause(ctx context.Context) error {
+	m.PauseCallCount++
+	return m.PauseError
+}
+
+func (m *MockInspectorService) Resume(ctx context.Context) error {
+	m.ResumeCallCount++
+	return m.ResumeError
+}
+
 // MockVddkService is a mock implementation of VddkService.
 type MockVddkService struct {
 	UploadResult *models.VddkStatus
@@ -255,3 +494,64 @@ func (m *MockRightsizingService) GetVMUtilization(ctx context.Context, vmID stri
 	m.LastUtilizationVMID = vmID
 	return m.GetUtilizationResult, m.GetUtilizationError
 }
+
+// MockPolicyService is a mock implementation of PolicyService.
+type MockPolicyService struct {
+	ReloadResult    int
+	ReloadError     error
+	ReloadCallCount int
+	CatalogResult   []models.ConcernDefinition
+}
+
+func (m *MockPolicyService) Reload() (int, error) {
+	m.ReloadCallCount++
+	return m.ReloadResult, m.ReloadError
+}
+
+func (m *MockPolicyService) Catalog() []models.ConcernDefinition {
+	return m.CatalogResult
+}
+
+// MockMaintenanceService is a mock implementation of MaintenanceService.
+type MockMaintenanceService struct {
+	EnabledResult bool
+	SetError      error
+}
+
+func (m *MockMaintenanceService) Enabled() bool {
+	return m.EnabledResult
+}
+
+func (m *MockMaintenanceService) SetEnabled(ctx context.Context, enabled bool) error {
+	if m.SetError != nil {
+		return m.SetError
+	}
+	m.EnabledResult = enabled
+	return nil
+}
+
+// MockStorageService is a mock implementation of StorageService.
+type MockStorageService struct {
+	UsageResult      *models.StorageUsage
+	UsageError       error
+	CompactError     error
+	CompactCallCount int
+	DatabasePath     string
+	DatabaseError    error
+}
+
+func (m *MockStorageService) Usage(ctx context.Context) (*models.StorageUsage, error) {
+	return m.UsageResult, m.UsageError
+}
+
+func (m *MockStorageService) Compact(ctx context.Context) error {
+	m.CompactCallCount++
+	return m.CompactError
+}
+
+func (m *MockStorageService) OpenDatabase(ctx context.Context) (*os.File, error) {
+	if m.DatabaseError != nil {
+		return nil, m.DatabaseError
+	}
+	return os.Open(m.DatabasePath)
+}
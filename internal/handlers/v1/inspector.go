@@ -12,6 +12,12 @@ import (
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
+var (
+	_ = registerCapability("inspector.pause", "Pause and resume the inspection queue (POST /vms/inspector/pause, /vms/inspector/resume)")
+	_ = registerCapability("inspector.cancel", "Cancel the inspection of several VMs at once (POST /vms/inspector/cancel)")
+	_ = registerCapability("inspector.priority", "Move VMs to the front of the inspection queue (POST /vms/inspector/priority)")
+)
+
 const (
 	MaxVDDKSize = 64 << 20 // 64Mb
 )
@@ -19,36 +25,40 @@ const (
 // StartInspection starts inspection for VMs
 // (POST /inspector)
 func (h *Handler) StartInspection(c *gin.Context) {
+	if h.rejectInMaintenance(c) {
+		return
+	}
+
 	var req v1.StartInspectionJSONRequestBody
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
 		return
 	}
 
 	if len(req.VmIds) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "vmIds is required"})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("vmIds is required"))
 		return
 	}
 
 	if _, err := h.vddkSrv.Status(c.Request.Context()); err != nil {
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "A VDDK must be uploaded before starting an inspection"})
+			respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("A VDDK must be uploaded before starting an inspection"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	if err := h.inspectorSrv.Start(c.Request.Context(), req.VmIds); err != nil {
 		if srvErrors.IsOperationInProgressError(err) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			respondError(c, http.StatusConflict, err)
 			return
 		}
 		if srvErrors.IsInspectionLimitReachedError(err) || srvErrors.IsCredentialsNotSetError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to start inspector: %v", err)})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to start inspector: %v", err))
 		return
 	}
 
@@ -70,7 +80,7 @@ func (h *Handler) GetInspectorStatus(c *gin.Context, params v1.GetInspectorStatu
 		s, err := h.vddkSrv.Status(c.Request.Context())
 		if err != nil {
 			if !srvErrors.IsResourceNotFoundError(err) {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				respondError(c, http.StatusInternalServerError, err)
 				return
 			}
 		} else {
@@ -81,27 +91,129 @@ func (h *Handler) GetInspectorStatus(c *gin.Context, params v1.GetInspectorStatu
 	c.JSON(http.StatusOK, apiStatus)
 }
 
-// StopInspection stops inspector entirely
+// StopInspection stops inspector entirely. With reset=true it instead returns
+// a finished inspector to ready and clears the inspection statuses.
 // (DELETE /inspector)
-func (h *Handler) StopInspection(c *gin.Context) {
+func (h *Handler) StopInspection(c *gin.Context, params v1.StopInspectionParams) {
+	if params.Reset != nil && *params.Reset {
+		if err := h.inspectorSrv.Reset(c.Request.Context()); err != nil {
+			if srvErrors.IsOperationInProgressError(err) {
+				respondError(c, http.StatusConflict, err)
+				return
+			}
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to reset inspector: %v", err))
+			return
+		}
+		c.JSON(http.StatusOK, v1.NewInspectorStatus(h.inspectorSrv.GetStatus()))
+		return
+	}
+
 	if err := h.inspectorSrv.Stop(); err != nil {
 		if srvErrors.IsInspectorNotRunningError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusAccepted, v1.NewInspectorStatus(h.inspectorSrv.GetStatus()))
 }
 
+// PrioritizeInspection moves pending VMs to the front of the inspection queue
+// (PATCH /vms/inspector/priority)
+func (h *Handler) PrioritizeInspection(c *gin.Context) {
+	var req v1.PrioritizeInspectionJSONRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
+		return
+	}
+
+	if len(req.VmIds) == 0 {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("vmIds is required"))
+		return
+	}
+
+	if err := h.inspectorSrv.Prioritize(c.Request.Context(), req.VmIds); err != nil {
+		if srvErrors.IsInspectorNotRunningError(err) || srvErrors.IsValidationError(err) {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to prioritize inspection: %v", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CancelVmsInspection cancels the inspection of several VMs and reports an
+// outcome per VM
+// (POST /vms/inspector/cancel)
+func (h *Handler) CancelVmsInspection(c *gin.Context) {
+	var req v1.CancelVmsInspectionJSONRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
+		return
+	}
+
+	if len(req.VmIds) == 0 {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("vmIds is required"))
+		return
+	}
+
+	results, err := h.inspectorSrv.CancelVmsInspection(c.Request.Context(), req.VmIds...)
+	if err != nil {
+		if srvErrors.IsInspectorNotRunningError(err) || srvErrors.IsValidationError(err) {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to cancel inspection: %v", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewInspectionCancelResultsFromModel(results))
+}
+
+// PauseInspection stops new VM inspections from starting
+// (POST /vms/inspector/pause)
+func (h *Handler) PauseInspection(c *gin.Context) {
+	if err := h.inspectorSrv.Pause(c.Request.Context()); err != nil {
+		if srvErrors.IsInspectorNotRunningError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewInspectorStatus(h.inspectorSrv.GetStatus()))
+}
+
+// ResumeInspection lets a paused inspection start new VMs again
+// (POST /vms/inspector/resume)
+func (h *Handler) ResumeInspection(c *gin.Context) {
+	if h.rejectInMaintenance(c) {
+		return
+	}
+
+	if err := h.inspectorSrv.Resume(c.Request.Context()); err != nil {
+		if srvErrors.IsInspectorNotRunningError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewInspectorStatus(h.inspectorSrv.GetStatus()))
+}
+
 // PutInspectorCredentials sets or replaces vCenter credentials used by the inspector.
 // (PUT /inspector/credentials)
 func (h *Handler) PutInspectorCredentials(c *gin.Context) {
 	var req v1.VcenterCredentials
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
 		return
 	}
 
@@ -112,11 +224,15 @@ func (h *Handler) PutInspectorCredentials(c *gin.Context) {
 	}
 
 	if err := h.inspectorSrv.Credentials(c.Request.Context(), creds); err != nil {
+		if srvErrors.IsPermissionError(err) {
+			respondError(c, http.StatusForbidden, err)
+			return
+		}
 		if srvErrors.IsVCenterError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -126,7 +242,7 @@ func (h *Handler) PutInspectorCredentials(c *gin.Context) {
 // PutInspectorVddk (PUT /inspector/vddk)
 func (h *Handler) PutInspectorVddk(c *gin.Context) {
 	if h.inspectorSrv != nil && h.inspectorSrv.IsBusy() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "VDDK upload is not allowed while inspector is running"})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("VDDK upload is not allowed while inspector is running"))
 		return
 	}
 
@@ -135,16 +251,16 @@ func (h *Handler) PutInspectorVddk(c *gin.Context) {
 	if err != nil {
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			respondError(c, http.StatusRequestEntityTooLarge, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	r, err := file.Open()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	defer func() {
@@ -154,10 +270,10 @@ func (h *Handler) PutInspectorVddk(c *gin.Context) {
 	s, err := h.vddkSrv.Upload(c.Request.Context(), file.Filename, r)
 	if err != nil {
 		if srvErrors.IsOperationInProgressError(err) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			respondError(c, http.StatusConflict, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -173,10 +289,10 @@ func (h *Handler) GetInspectorVddkStatus(c *gin.Context) {
 	s, err := h.vddkSrv.Status(c.Request.Context())
 	if err != nil {
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
@@ -46,7 +47,11 @@ var _ = Describe("Inspector Handler", func() {
 		router.GET("/inspector", apiWrapper.GetInspectorStatus)
 		router.POST("/inspector", handler.StartInspection)
 		router.PUT("/inspector/credentials", handler.PutInspectorCredentials)
-		router.DELETE("/inspector", handler.StopInspection)
+		router.DELETE("/inspector", apiWrapper.StopInspection)
+		router.PATCH("/vms/inspector/priority", handler.PrioritizeInspection)
+		router.POST("/vms/inspector/cancel", handler.CancelVmsInspection)
+		router.POST("/vms/inspector/pause", handler.PauseInspection)
+		router.POST("/vms/inspector/resume", handler.ResumeInspection)
 	})
 
 	Context("GetInspectorStatus", func() {
@@ -67,6 +72,43 @@ var _ = Describe("Inspector Handler", func() {
 			Expect(response.State).To(Equal(v1.InspectorStatusStateReady))
 		})
 
+		It("should include the progress estimate", func() {
+			eta := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			mockInspector.GetStatusResult = models.InspectorStatus{
+				State:               models.InspectorStateRunning,
+				AverageVmDuration:   90 * time.Second,
+				EstimatedCompletion: &eta,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/inspector", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response v1.InspectorStatus
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.AverageVmDuration).NotTo(BeNil())
+			Expect(*response.AverageVmDuration).To(Equal("1m30s"))
+			Expect(response.EstimatedCompletion).NotTo(BeNil())
+			Expect(response.EstimatedCompletion.Equal(eta)).To(BeTrue())
+		})
+
+		It("should omit the progress estimate before any VM completes", func() {
+			mockInspector.GetStatusResult = models.InspectorStatus{State: models.InspectorStateRunning}
+
+			req := httptest.NewRequest(http.MethodGet, "/inspector", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var body map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body).NotTo(HaveKey("averageVmDuration"))
+			Expect(body).NotTo(HaveKey("estimatedCompletion"))
+		})
+
 		It("should include credentials when includeCredentials=true", func() {
 			mockInspector.GetStatusResult = models.InspectorStatus{
 				State: models.InspectorStateReady,
@@ -247,6 +289,257 @@ var _ = Describe("Inspector Handler", func() {
 			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
 			Expect(body["error"]).To(Equal("inspector not running"))
 		})
+
+		// Given a finished inspector
+		// When DELETE /inspector is called with reset=true
+		// Then it should reset instead of stopping and return the ready status
+		It("should reset the inspector when reset is true", func() {
+			// Arrange
+			mockInspector.GetStatusResult = models.InspectorStatus{State: models.InspectorStateReady}
+			req := httptest.NewRequest(http.MethodDelete, "/inspector?reset=true", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockInspector.ResetCallCount).To(Equal(1))
+			Expect(mockInspector.StopCallCount).To(BeZero())
+			var body v1.InspectorStatus
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.State).To(Equal(v1.InspectorStatusStateReady))
+		})
+
+		// Given an inspection in progress
+		// When DELETE /inspector is called with reset=true
+		// Then it should return 409
+		It("should return 409 when resetting during an inspection", func() {
+			// Arrange
+			mockInspector.ResetError = srvErrors.NewInspectionInProgressError()
+			req := httptest.NewRequest(http.MethodDelete, "/inspector?reset=true", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusConflict))
+			Expect(mockInspector.StopCallCount).To(BeZero())
+		})
+
+		// Given the statuses cannot be deleted
+		// When DELETE /inspector is called with reset=true
+		// Then it should return 500
+		It("should return 500 when the reset fails", func() {
+			// Arrange
+			mockInspector.ResetError = errors.New("db error")
+			req := httptest.NewRequest(http.MethodDelete, "/inspector?reset=true", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("PrioritizeInspection", func() {
+		It("should pass the VM ids to the service in order", func() {
+			req := httptest.NewRequest(http.MethodPatch, "/vms/inspector/priority", bytes.NewBufferString(`{"vmIds":["vm-3","vm-1"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			Expect(mockInspector.PrioritizedVMs).To(Equal([]string{"vm-3", "vm-1"}))
+		})
+
+		It("should return 400 when vmIds is empty", func() {
+			req := httptest.NewRequest(http.MethodPatch, "/vms/inspector/priority", bytes.NewBufferString(`{"vmIds":[]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(mockInspector.PrioritizedVMs).To(BeNil())
+		})
+
+		It("should return 400 when a VM is not pending", func() {
+			mockInspector.PrioritizeError = srvErrors.NewValidationError("vm vm-1 is not pending inspection")
+
+			req := httptest.NewRequest(http.MethodPatch, "/vms/inspector/priority", bytes.NewBufferString(`{"vmIds":["vm-1"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			var body map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body["error"]).To(Equal("vm vm-1 is not pending inspection"))
+		})
+
+		It("should return 400 when the inspector is not running", func() {
+			mockInspector.PrioritizeError = srvErrors.NewInspectorNotRunningError()
+
+			req := httptest.NewRequest(http.MethodPatch, "/vms/inspector/priority", bytes.NewBufferString(`{"vmIds":["vm-1"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 500 on unexpected failure", func() {
+			mockInspector.PrioritizeError = errors.New("boom")
+
+			req := httptest.NewRequest(http.MethodPatch, "/vms/inspector/priority", bytes.NewBufferString(`{"vmIds":["vm-1"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("CancelVmsInspection", func() {
+		// Given a request mixing a running, a finished and an unknown VM
+		// When the VMs are canceled
+		// Then every VM should get its own outcome, in request order
+		It("should return an outcome per VM", func() {
+			// Arrange
+			mockInspector.CancelVmsResult = []models.InspectionCancelResult{
+				{VMID: "vm-2", Outcome: models.InspectionCancelOutcomeCanceled},
+				{VMID: "vm-1", Outcome: models.InspectionCancelOutcomeAlreadyCompleted},
+				{VMID: "vm-9", Outcome: models.InspectionCancelOutcomeNotFound},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/vms/inspector/cancel", bytes.NewBufferString(`{"vmIds":["vm-2","vm-1","vm-9"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockInspector.CanceledVMs).To(Equal([]string{"vm-2", "vm-1", "vm-9"}))
+			var body v1.InspectionCancelResults
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Results).To(Equal([]v1.InspectionCancelResult{
+				{VmId: "vm-2", Outcome: v1.InspectionCancelResultOutcomeCanceled},
+				{VmId: "vm-1", Outcome: v1.InspectionCancelResultOutcomeAlreadyCompleted},
+				{VmId: "vm-9", Outcome: v1.InspectionCancelResultOutcomeNotFound},
+			}))
+		})
+
+		It("should return 400 when vmIds is empty", func() {
+			req := httptest.NewRequest(http.MethodPost, "/vms/inspector/cancel", bytes.NewBufferString(`{"vmIds":[]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(mockInspector.CanceledVMs).To(BeNil())
+		})
+
+		It("should return 400 when the inspector is not running", func() {
+			mockInspector.CancelVmsError = srvErrors.NewInspectorNotRunningError()
+
+			req := httptest.NewRequest(http.MethodPost, "/vms/inspector/cancel", bytes.NewBufferString(`{"vmIds":["vm-1"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 500 on unexpected failure", func() {
+			mockInspector.CancelVmsError = errors.New("boom")
+
+			req := httptest.NewRequest(http.MethodPost, "/vms/inspector/cancel", bytes.NewBufferString(`{"vmIds":["vm-1"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("PauseInspection", func() {
+		It("should pause and return the inspector status", func() {
+			mockInspector.GetStatusResult = models.InspectorStatus{State: models.InspectorStatePaused}
+
+			req := httptest.NewRequest(http.MethodPost, "/vms/inspector/pause", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockInspector.PauseCallCount).To(Equal(1))
+			var body v1.InspectorStatus
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.State).To(Equal(v1.InspectorStatusStatePaused))
+		})
+
+		It("should return 404 when the inspector is not running", func() {
+			mockInspector.PauseError = srvErrors.NewInspectorNotRunningError()
+
+			req := httptest.NewRequest(http.MethodPost, "/vms/inspector/pause", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("ResumeInspection", func() {
+		It("should resume and return the inspector status", func() {
+			mockInspector.GetStatusResult = models.InspectorStatus{State: models.InspectorStateRunning}
+
+			req := httptest.NewRequest(http.MethodPost, "/vms/inspector/resume", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockInspector.ResumeCallCount).To(Equal(1))
+			var body v1.InspectorStatus
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.State).To(Equal(v1.InspectorStatusStateRunning))
+		})
+
+		It("should return 404 when the inspector is not running", func() {
+			mockInspector.ResumeError = srvErrors.NewInspectorNotRunningError()
+
+			req := httptest.NewRequest(http.MethodPost, "/vms/inspector/resume", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return 503 in maintenance mode", func() {
+			handler.WithMaintenanceService(&MockMaintenanceService{EnabledResult: true})
+
+			req := httptest.NewRequest(http.MethodPost, "/vms/inspector/resume", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(mockInspector.ResumeCallCount).To(BeZero())
+		})
 	})
 })
 
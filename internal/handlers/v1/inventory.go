@@ -13,34 +13,97 @@ import (
 
 	"github.com/kubev2v/migration-planner/api/v1alpha1"
 
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
+var (
+	_ = registerCapability("clusters", "Per-cluster totals with overcommitment filters (GET /clusters)")
+	_ = registerCapability("inventory.diff", "Changes between the last two collections (GET /inventory/diff)")
+	_ = registerCapability("inventory.metrics", "Top-level inventory totals without the full inventory (GET /inventory/metrics)")
+	_ = registerCapability("inventory.history", "Previous inventories kept after each collection (GET /inventory/history, GET /inventory?snapshot=)")
+	_ = registerCapability("inventory.revalidate", "Re-run the validation policies on the stored inventory (POST /inventory/revalidate)")
+)
+
 // GetInventory returns the collected inventory
 // (GET /inventory)
 func (h *Handler) GetInventory(c *gin.Context, params v1.GetInventoryParams) {
+	if params.Schema != nil && *params.Schema != v1.V1 && *params.Schema != v1.V2 {
+		respondError(c, http.StatusBadRequest, fmt.Errorf("invalid schema version: %s, must be 'v1' or 'v2'", *params.Schema))
+		return
+	}
+
+	if params.Snapshot != nil {
+		inv, err := h.inventorySrv.GetInventoryAt(c.Request.Context(), *params.Snapshot)
+		if err != nil {
+			if srvErrors.IsResourceNotFoundError(err) {
+				respondError(c, http.StatusNotFound, err)
+				return
+			}
+			zap.S().Named("inventory_handler").Errorw("failed to get inventory snapshot", "snapshot", *params.Snapshot, "error", err)
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		h.respondInventory(c, inv, params)
+		return
+	}
+
 	inv, err := h.inventorySrv.GetInventory(c.Request.Context())
 	if err != nil {
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			// Tell a failed collection apart from one that never ran.
+			if h.collectorSrv != nil {
+				if status := h.collectorSrv.GetStatus(); status.State == models.CollectorStateError && status.Error != nil {
+					respondError(c, http.StatusConflict, srvErrors.NewCollectionFailedError(status.Error))
+					return
+				}
+			}
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
 		zap.S().Named("inventory_handler").Errorw("failed to get inventory", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	h.respondInventory(c, inv, params)
+}
+
+// respondInventory writes a stored inventory in the schema and with the
+// options asked for by the GET /inventory parameters.
+func (h *Handler) respondInventory(c *gin.Context, inv *models.Inventory, params v1.GetInventoryParams) {
 	var inventory v1alpha1.Inventory
 	if err := json.Unmarshal(inv.Data, &inventory); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Errorf("error unmarshalling inventory: %w", err)})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("error unmarshalling inventory: %w", err))
 		return
 	}
 
+	if params.IncludeSuppressed == nil || !*params.IncludeSuppressed {
+		inventory = collectorV1.SuppressIssues(inventory, h.cfg.Agent.SuppressedConcerns)
+	}
+	inventory = collectorV1.EnrichIssues(inventory, h.cfg.Agent.ConcernAssessments)
+
 	withAgentId := false
 	if params.WithAgentId != nil {
 		withAgentId = *params.WithAgentId
 	}
 
+	// Legacy flat inventory
+	if params.Schema != nil && *params.Schema == v1.V1 {
+		legacy := collectorV1.ToLegacyInventory(inventory)
+		if !withAgentId {
+			c.JSON(http.StatusOK, legacy)
+			return
+		}
+		c.JSON(http.StatusOK, &collectorV1.UpdateInventory{
+			Inventory: legacy,
+			AgentId:   uuid.MustParse(h.cfg.Agent.ID),
+		})
+		return
+	}
+
 	// Return inventory without agent ID
 	if !withAgentId {
 		c.JSON(http.StatusOK, inventory)
@@ -54,3 +117,108 @@ func (h *Handler) GetInventory(c *gin.Context, params v1.GetInventoryParams) {
 	}
 	c.JSON(http.StatusOK, payload)
 }
+
+// GetInventoryHistory lists when each kept inventory was collected
+// (GET /inventory/history)
+func (h *Handler) GetInventoryHistory(c *gin.Context) {
+	history, err := h.inventorySrv.History(c.Request.Context())
+	if err != nil {
+		zap.S().Named("inventory_handler").Errorw("failed to list inventory history", "error", err)
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewInventoryHistoryFromModel(history))
+}
+
+// GetInventoryDiff returns the changes between the last two collections
+// (GET /inventory/diff)
+func (h *Handler) GetInventoryDiff(c *gin.Context) {
+	diff, err := h.inventorySrv.Diff(c.Request.Context())
+	if err != nil {
+		if srvErrors.IsResourceNotFoundError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		zap.S().Named("inventory_handler").Errorw("failed to diff inventory", "error", err)
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewInventoryDiffFromModel(*diff))
+}
+
+// GetInventoryMetrics returns the top-level totals of the stored inventory
+// (GET /inventory/metrics)
+func (h *Handler) GetInventoryMetrics(c *gin.Context) {
+	metrics, err := h.inventorySrv.Metrics(c.Request.Context())
+	if err != nil {
+		if srvErrors.IsResourceNotFoundError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		zap.S().Named("inventory_handler").Errorw("failed to get inventory metrics", "error", err)
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewInventoryMetricsFromModel(*metrics))
+}
+
+// GetClusters returns the totals of each cluster of the stored inventory,
+// optionally only the overcommitted ones
+// (GET /clusters)
+func (h *Handler) GetClusters(c *gin.Context, params v1.GetClustersParams) {
+	fields := map[string]string{}
+	if params.MinCpuOvercommit != nil && *params.MinCpuOvercommit < 0 {
+		fields["minCpuOvercommit"] = "must not be negative"
+	}
+	if params.MinMemoryOvercommit != nil && *params.MinMemoryOvercommit < 0 {
+		fields["minMemoryOvercommit"] = "must not be negative"
+	}
+	if len(fields) > 0 {
+		respondError(c, http.StatusBadRequest, srvErrors.NewFieldValidationError(fields))
+		return
+	}
+
+	clusters, err := h.inventorySrv.Clusters(c.Request.Context(), services.ClusterListParams{
+		MinCPUOverCommitment:    params.MinCpuOvercommit,
+		MinMemoryOverCommitment: params.MinMemoryOvercommit,
+	})
+	if err != nil {
+		if srvErrors.IsResourceNotFoundError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		zap.S().Named("inventory_handler").Errorw("failed to list clusters", "error", err)
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewClusterListFromModel(clusters))
+}
+
+// RevalidateInventory recomputes the VM concerns and rebuilds the inventory
+// without recollecting
+// (POST /inventory/revalidate)
+func (h *Handler) RevalidateInventory(c *gin.Context) {
+	if h.rejectInMaintenance(c) {
+		return
+	}
+
+	count, err := h.collectorSrv.Revalidate(c.Request.Context())
+	if err != nil {
+		switch {
+		case srvErrors.IsResourceNotFoundError(err):
+			respondError(c, http.StatusNotFound, err)
+		case srvErrors.IsOperationInProgressError(err):
+			respondError(c, http.StatusConflict, err)
+		default:
+			zap.S().Named("inventory_handler").Errorw("failed to revalidate inventory", "error", err)
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.RevalidateResponse{Concerns: count})
+}
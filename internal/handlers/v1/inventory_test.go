@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
 
@@ -25,6 +26,7 @@ import (
 var _ = Describe("Inventory Handlers", func() {
 	var (
 		mockInventory *MockInventoryService
+		mockCollector *MockCollectorService
 		handler       *handlers.Handler
 		router        *gin.Engine
 	)
@@ -32,17 +34,23 @@ var _ = Describe("Inventory Handlers", func() {
 	BeforeEach(func() {
 		gin.SetMode(gin.TestMode)
 		mockInventory = &MockInventoryService{}
+		mockCollector = &MockCollectorService{StatusResult: models.CollectorStatus{State: models.CollectorStateReady}}
 		handler = handlers.NewHandler(config.Configuration{
 			Agent: config.Agent{
 				ID: uuid.Nil.String(),
 			},
-		}).WithInventoryService(mockInventory)
+		}).WithInventoryService(mockInventory).WithCollectorService(mockCollector)
 		router = gin.New()
 		wrapper := v1.ServerInterfaceWrapper{
 			Handler:      handler,
 			ErrorHandler: func(c *gin.Context, err error, statusCode int) { c.JSON(statusCode, gin.H{"msg": err.Error()}) },
 		}
 		router.GET("/inventory", wrapper.GetInventory)
+		router.GET("/inventory/diff", wrapper.GetInventoryDiff)
+		router.GET("/inventory/metrics", wrapper.GetInventoryMetrics)
+		router.GET("/clusters", wrapper.GetClusters)
+		router.GET("/inventory/history", wrapper.GetInventoryHistory)
+		router.POST("/inventory/revalidate", wrapper.RevalidateInventory)
 	})
 
 	Context("GetInventory", func() {
@@ -97,6 +105,172 @@ var _ = Describe("Inventory Handlers", func() {
 			Expect(result.Inventory.VcenterId).To(Equal(vcenterID))
 		})
 
+		// Given inventory data exists in the store
+		// When we request the inventory with schema=v1
+		// Then it should return the flat legacy inventory
+		It("should return legacy inventory when schema=v1", func() {
+			// Arrange
+			inventoryData := []byte(`{"clusters": {"c1": {"infra": {"totalHosts": 1}, "vms": {"total": 1}}}, "vcenter": {"infra": {"totalHosts": 2}, "vms": {"total": 5}}, "vcenter_id": "vc-1"}`)
+			mockInventory.InventoryResult = &models.Inventory{Data: inventoryData}
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory?schema=v1", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var raw map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &raw)).To(Succeed())
+			Expect(raw).NotTo(HaveKey("clusters"))
+
+			var result v1alpha1.InventoryData
+			Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+			Expect(result.Vcenter).NotTo(BeNil())
+			Expect(result.Vcenter.Id).To(Equal("vc-1"))
+			Expect(result.Infra.TotalHosts).To(Equal(2))
+			Expect(result.Vms.Total).To(Equal(5))
+		})
+
+		// Given inventory data exists in the store
+		// When we request the inventory with schema=v1 and withAgentId=true
+		// Then it should wrap the legacy inventory with the agent ID
+		It("should return legacy inventory with agent id when schema=v1", func() {
+			// Arrange
+			inventoryData := []byte(`{"clusters": {}, "vcenter": {"vms": {"total": 5}}, "vcenter_id": "vc-1"}`)
+			mockInventory.InventoryResult = &models.Inventory{Data: inventoryData}
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory?schema=v1&withAgentId=true", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var result struct {
+				AgentId   string                 `json:"agentId"`
+				Inventory v1alpha1.InventoryData `json:"inventory"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+			Expect(result.AgentId).To(Equal(uuid.Nil.String()))
+			Expect(result.Inventory.Vcenter.Id).To(Equal("vc-1"))
+			Expect(result.Inventory.Vms.Total).To(Equal(5))
+		})
+
+		// Given inventory data exists in the store
+		// When we request the inventory with schema=v2
+		// Then it should return the clustered inventory
+		It("should return clustered inventory when schema=v2", func() {
+			// Arrange
+			inventoryData := []byte(`{"clusters": {"c1": {}}, "vcenter": {}, "vcenter_id": "vc-1"}`)
+			mockInventory.InventoryResult = &models.Inventory{Data: inventoryData}
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory?schema=v2", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var result v1alpha1.Inventory
+			Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+			Expect(result.Clusters).To(HaveKey("c1"))
+		})
+
+		// Given an agent configured to suppress a concern found in the inventory
+		// When we request the inventory with and without includeSuppressed
+		// Then the suppressed issue should only be returned when asked for
+		It("should leave suppressed concerns out of the migration issues", func() {
+			// Arrange
+			handler = handlers.NewHandler(config.Configuration{
+				Agent: config.Agent{
+					ID:                 uuid.Nil.String(),
+					SuppressedConcerns: []string{"known.warning"},
+				},
+			}).WithInventoryService(mockInventory)
+			wrapper := v1.ServerInterfaceWrapper{Handler: handler}
+			router = gin.New()
+			router.GET("/inventory", wrapper.GetInventory)
+
+			vms := `{"migrationWarnings": [{"id": "known.warning", "label": "Accepted", "assessment": "", "count": 3}, {"id": "other.warning", "label": "Other", "assessment": "", "count": 1}]}`
+			inventoryData := []byte(`{"clusters": {"c1": {"vms": ` + vms + `}}, "vcenter": {"vms": ` + vms + `}, "vcenter_id": "vc-1"}`)
+			mockInventory.InventoryResult = &models.Inventory{Data: inventoryData}
+
+			get := func(url string) v1alpha1.Inventory {
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+				Expect(w.Code).To(Equal(http.StatusOK))
+				var result v1alpha1.Inventory
+				Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+				return result
+			}
+
+			// Act
+			suppressed := get("/inventory")
+			raw := get("/inventory?includeSuppressed=true")
+
+			// Assert
+			Expect(suppressed.Vcenter.Vms.MigrationWarnings).To(HaveLen(1))
+			Expect(*suppressed.Vcenter.Vms.MigrationWarnings[0].Id).To(Equal("other.warning"))
+			Expect(suppressed.Clusters["c1"].Vms.MigrationWarnings).To(HaveLen(1))
+			Expect(raw.Vcenter.Vms.MigrationWarnings).To(HaveLen(2))
+			Expect(raw.Clusters["c1"].Vms.MigrationWarnings).To(HaveLen(2))
+		})
+
+		// Given an agent configured with extra assessment text for a concern
+		// When we request the inventory
+		// Then the matching issue assessments should carry the text and the
+		// counts should be unchanged
+		It("should append the configured assessment text to the migration issues", func() {
+			// Arrange
+			handler = handlers.NewHandler(config.Configuration{
+				Agent: config.Agent{
+					ID:                 uuid.Nil.String(),
+					ConcernAssessments: map[string]string{"known.warning": "See https://wiki.example.com/known"},
+				},
+			}).WithInventoryService(mockInventory)
+			wrapper := v1.ServerInterfaceWrapper{Handler: handler}
+			router = gin.New()
+			router.GET("/inventory", wrapper.GetInventory)
+
+			vms := `{"total": 4, "migrationWarnings": [{"id": "known.warning", "label": "Accepted", "assessment": "Check it.", "count": 3}, {"id": "other.warning", "label": "Other", "assessment": "Other.", "count": 1}]}`
+			mockInventory.InventoryResult = &models.Inventory{Data: []byte(`{"clusters": {"c1": {"vms": ` + vms + `}}, "vcenter": {"vms": ` + vms + `}, "vcenter_id": "vc-1"}`)}
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/inventory", nil))
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var result v1alpha1.Inventory
+			Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+			for _, data := range []v1alpha1.InventoryData{*result.Vcenter, result.Clusters["c1"]} {
+				Expect(data.Vms.Total).To(Equal(4))
+				Expect(data.Vms.MigrationWarnings).To(HaveLen(2))
+				Expect(data.Vms.MigrationWarnings[0].Assessment).To(Equal("Check it.\n\nSee https://wiki.example.com/known"))
+				Expect(data.Vms.MigrationWarnings[0].Count).To(Equal(3))
+				Expect(data.Vms.MigrationWarnings[1].Assessment).To(Equal("Other."))
+			}
+		})
+
+		// Given an unknown schema version
+		// When we request the inventory
+		// Then it should return 400 Bad Request
+		It("should return 400 for an unknown schema version", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/inventory?schema=v3", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
 		// Given no inventory has been collected yet
 		// When we request the inventory
 		// Then it should return 404 Not Found
@@ -119,6 +293,53 @@ var _ = Describe("Inventory Handlers", func() {
 			Expect(response["error"]).To(ContainSubstring("inventory not found"))
 		})
 
+		// Given no inventory and a collector whose last collection failed
+		// When we request the inventory
+		// Then it should return 409 with the collection error
+		It("should return 409 with the collection error when collection failed", func() {
+			// Arrange
+			mockInventory.InventoryError = srvErrors.NewInventoryNotFoundError()
+			mockCollector.StatusResult = models.CollectorStatus{
+				State: models.CollectorStateError,
+				Error: errors.New("vcenter login failed"),
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusConflict))
+
+			var response map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["error"]).To(ContainSubstring("collection failed"))
+			Expect(response["error"]).To(ContainSubstring("vcenter login failed"))
+		})
+
+		// Given an inventory from an earlier collection and a later failed collection
+		// When we request the inventory
+		// Then it should still return the stored inventory
+		It("should return the stored inventory even if the last collection failed", func() {
+			// Arrange
+			mockInventory.InventoryResult = &models.Inventory{Data: []byte(`{"clusters": {}, "vcenter": {}, "vcenter_id": "vc-1"}`)}
+			mockCollector.StatusResult = models.CollectorStatus{
+				State: models.CollectorStateError,
+				Error: errors.New("vcenter login failed"),
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
 		It("should return 500 when inventory data is not valid JSON", func() {
 			mockInventory.InventoryResult = &models.Inventory{Data: []byte("not valid json")}
 
@@ -151,5 +372,420 @@ var _ = Describe("Inventory Handlers", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(response["error"]).To(ContainSubstring("database error"))
 		})
+
+		// Given an older inventory kept in the history
+		// When we request the inventory with its snapshot timestamp
+		// Then that inventory should be returned instead of the latest one
+		It("should return the inventory of the requested snapshot", func() {
+			// Arrange
+			mockInventory.InventoryResult = &models.Inventory{Data: []byte(`{"clusters": {}, "vcenter": {}, "vcenter_id": "latest"}`)}
+			mockInventory.SnapshotResult = &models.Inventory{Data: []byte(`{"clusters": {}, "vcenter": {}, "vcenter_id": "older"}`)}
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory?snapshot=2026-03-01T10:00:00.123456Z", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var result v1alpha1.Inventory
+			Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+			Expect(result.VcenterId).To(Equal("older"))
+			Expect(mockInventory.SnapshotAt.Equal(time.Date(2026, 3, 1, 10, 0, 0, 123456000, time.UTC))).To(BeTrue())
+		})
+
+		// Given no kept inventory collected at the requested time
+		// When we request the inventory with that snapshot timestamp
+		// Then it should return 404
+		It("should return 404 for an unknown snapshot", func() {
+			// Arrange
+			mockInventory.SnapshotError = srvErrors.NewResourceNotFoundError("inventory snapshot", "2026-03-01T10:00:00Z")
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory?snapshot=2026-03-01T10:00:00Z", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		// Given a snapshot value that is not a timestamp
+		// When we request the inventory with it
+		// Then it should return 400
+		It("should return 400 for an invalid snapshot timestamp", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/inventory?snapshot=yesterday", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("GetInventoryHistory", func() {
+		// Given two kept inventories
+		// When we request the history
+		// Then their timestamps should be listed newest first
+		It("should list the kept inventories", func() {
+			// Arrange
+			latest := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+			previous := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+			mockInventory.HistoryResult = []time.Time{latest, previous}
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/history", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var result v1.InventoryHistory
+			Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+			Expect(result.Snapshots).To(HaveLen(2))
+			Expect(result.Snapshots[0].TakenAt.Equal(latest)).To(BeTrue())
+			Expect(result.Snapshots[1].TakenAt.Equal(previous)).To(BeTrue())
+		})
+
+		// Given the history cannot be read
+		// When we request the history
+		// Then it should return 500
+		It("should return 500 when the history cannot be read", func() {
+			// Arrange
+			mockInventory.HistoryError = errors.New("database error")
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/history", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("GetInventoryDiff", func() {
+		// Given two collections with changes between them
+		// When we request the diff
+		// Then it should return the added, removed and changed VMs
+		It("should return the diff", func() {
+			// Arrange
+			mockInventory.DiffResult = &models.InventoryDiff{
+				PreviousCollectedAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+				CurrentCollectedAt:  time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+				Added:               []string{"vm-5"},
+				Removed:             []string{"vm-3"},
+				Changed: []models.VMDelta{
+					{ID: "vm-2", Name: "db", CPUs: &models.FieldDelta{Previous: 4, Current: 8}},
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/diff", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var response v1.InventoryDiff
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Added).To(Equal([]string{"vm-5"}))
+			Expect(response.Removed).To(Equal([]string{"vm-3"}))
+			Expect(response.Changed).To(HaveLen(1))
+			Expect(response.Changed[0].Id).To(Equal("vm-2"))
+			Expect(*response.Changed[0].Cpus).To(Equal(v1.FieldDelta{Previous: 4, Current: 8}))
+			Expect(response.Changed[0].MemoryMB).To(BeNil())
+			Expect(response.Changed[0].DiskMB).To(BeNil())
+			Expect(w.Body.String()).NotTo(ContainSubstring("memoryMB"))
+		})
+
+		// Given fewer than two collections have completed
+		// When we request the diff
+		// Then it should return 404 Not Found
+		It("should return 404 when there is nothing to compare", func() {
+			// Arrange
+			mockInventory.DiffError = srvErrors.NewResourceNotFoundError("inventory snapshot", "")
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/diff", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		// Given an internal error occurs when computing the diff
+		// When we request the diff
+		// Then it should return 500 Internal Server Error
+		It("should return 500 for other errors", func() {
+			// Arrange
+			mockInventory.DiffError = errors.New("database error")
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/diff", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("GetClusters", func() {
+		// Given clusters in the stored inventory
+		// When we request them with minimum overcommitment filters
+		// Then the filters should reach the service and the clusters be
+		// returned without the ratios they do not have
+		It("should pass the filters and return the clusters", func() {
+			// Arrange
+			cpu := 4.5
+			mockInventory.ClustersResult = []models.ClusterMetrics{
+				{ID: "domain-c1", TotalVMs: 40, TotalHosts: 4, CPUOverCommitment: &cpu},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/clusters?minCpuOvercommit=4&minMemoryOvercommit=0.5", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(*mockInventory.ClustersParams.MinCPUOverCommitment).To(Equal(4.0))
+			Expect(*mockInventory.ClustersParams.MinMemoryOverCommitment).To(Equal(0.5))
+
+			var response v1.ClusterList
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Clusters).To(HaveLen(1))
+			Expect(response.Clusters[0].Id).To(Equal("domain-c1"))
+			Expect(response.Clusters[0].TotalVms).To(Equal(40))
+			Expect(*response.Clusters[0].CpuOverCommitment).To(Equal(4.5))
+			Expect(w.Body.String()).NotTo(ContainSubstring("memoryOverCommitment"))
+		})
+
+		// Given no cluster matches
+		// When we request the clusters
+		// Then an empty list should be returned, not null
+		It("should return an empty list when no cluster matches", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/clusters?minCpuOvercommit=10", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(MatchJSON(`{"clusters": []}`))
+		})
+
+		// Given a negative minimum
+		// When we request the clusters
+		// Then it should return 400 naming the field
+		It("should return 400 for a negative minimum", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/clusters?minMemoryOvercommit=-1", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			var body map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body["fields"]).To(HaveKey("minMemoryOvercommit"))
+		})
+
+		// Given nothing has been collected
+		// When we request the clusters
+		// Then it should return 404 Not Found
+		It("should return 404 when inventory not found", func() {
+			// Arrange
+			mockInventory.ClustersError = srvErrors.NewInventoryNotFoundError()
+
+			req := httptest.NewRequest(http.MethodGet, "/clusters", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("GetInventoryMetrics", func() {
+		// Given a stored inventory
+		// When we request its metrics
+		// Then the totals should be returned, without the ratios the
+		// inventory does not have
+		It("should return the inventory totals", func() {
+			// Arrange
+			cpu := 2.5
+			mockInventory.MetricsResult = &models.InventoryMetrics{
+				UpdatedAt:         time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+				TotalVMs:          120,
+				PoweredOnVMs:      97,
+				TotalVCPUs:        480,
+				TotalRAMGB:        1920,
+				TotalDiskTB:       18.5,
+				TotalHosts:        8,
+				TotalDatacenters:  2,
+				CPUOverCommitment: &cpu,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/metrics", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var response v1.InventoryMetrics
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.TotalVms).To(Equal(120))
+			Expect(response.PoweredOnVms).To(Equal(97))
+			Expect(response.TotalVcpus).To(Equal(480))
+			Expect(response.TotalRamGB).To(Equal(1920))
+			Expect(response.TotalDiskTB).To(Equal(18.5))
+			Expect(response.TotalHosts).To(Equal(8))
+			Expect(response.TotalDatacenters).To(Equal(2))
+			Expect(*response.CpuOverCommitment).To(Equal(2.5))
+			Expect(w.Body.String()).NotTo(ContainSubstring("memoryOverCommitment"))
+		})
+
+		// Given nothing has been collected
+		// When we request the metrics
+		// Then it should return 404 Not Found
+		It("should return 404 when inventory not found", func() {
+			// Arrange
+			mockInventory.MetricsError = srvErrors.NewInventoryNotFoundError()
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/metrics", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		// Given the stored inventory cannot be read
+		// When we request the metrics
+		// Then it should return 500 Internal Server Error
+		It("should return 500 for other errors", func() {
+			// Arrange
+			mockInventory.MetricsError = errors.New("database error")
+
+			req := httptest.NewRequest(http.MethodGet, "/inventory/metrics", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("RevalidateInventory", func() {
+		revalidate := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/inventory/revalidate", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		// Given a collected inventory
+		// When we revalidate it
+		// Then it should return the number of concerns stored
+		It("should return the number of concerns", func() {
+			// Arrange
+			mockCollector.RevalidateResult = 7
+
+			// Act
+			w := revalidate()
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response v1.RevalidateResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Concerns).To(Equal(7))
+			Expect(mockCollector.RevalidateCallCount).To(Equal(1))
+		})
+
+		// Given no stored inventory
+		// When we revalidate
+		// Then it should return 404
+		It("should return 404 when nothing was collected", func() {
+			// Arrange
+			mockCollector.RevalidateError = srvErrors.NewResourceNotFoundError("inventory", "")
+
+			// Act
+			w := revalidate()
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		// Given a collection in progress
+		// When we revalidate
+		// Then it should return 409
+		It("should return 409 while a collection is running", func() {
+			// Arrange
+			mockCollector.RevalidateError = srvErrors.NewCollectionInProgressError()
+
+			// Act
+			w := revalidate()
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+
+		// Given an internal error while revalidating
+		// When we revalidate
+		// Then it should return 500
+		It("should return 500 for other errors", func() {
+			// Arrange
+			mockCollector.RevalidateError = errors.New("database error")
+
+			// Act
+			w := revalidate()
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+
+		// Given the agent in maintenance mode
+		// When we revalidate
+		// Then it should return 503 without revalidating
+		It("should return 503 in maintenance mode", func() {
+			// Arrange
+			handler.WithMaintenanceService(&MockMaintenanceService{EnabledResult: true})
+
+			// Act
+			w := revalidate()
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(mockCollector.RevalidateCallCount).To(BeZero())
+		})
 	})
 })
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+var _ = registerCapability("debug.loglevel", "Change the log level at runtime (POST /debug/loglevel)")
+
+// SetLogLevel changes the level of the agent logger until the next restart
+// (POST /debug/loglevel)
+func (h *Handler) SetLogLevel(c *gin.Context) {
+	var req v1.LogLevel
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
+		return
+	}
+
+	if h.logLevel == nil {
+		respondError(c, http.StatusNotFound, srvErrors.NewResourceNotFoundError("log level", ""))
+		return
+	}
+
+	level, err := zapcore.ParseLevel(string(req.Level))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(err.Error()))
+		return
+	}
+
+	previous := h.logLevel.Level()
+	h.logLevel.SetLevel(level)
+	zap.S().Named("debug_handler").Infow("log level changed", "from", previous.String(), "to", level.String())
+
+	c.JSON(http.StatusOK, v1.LogLevel{Level: v1.LogLevelLevel(h.logLevel.Level().String())})
+}
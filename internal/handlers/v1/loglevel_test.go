@@ -0,0 +1,110 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
+)
+
+var _ = Describe("LogLevel Handler", func() {
+	var (
+		level  zap.AtomicLevel
+		logs   *observer.ObservedLogs
+		log    *zap.Logger
+		router *gin.Engine
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+		var core zapcore.Core
+		core, logs = observer.New(level)
+		log = zap.New(core)
+
+		handler := handlers.NewHandler(config.Configuration{}).
+			WithLogLevel(level)
+		router = gin.New()
+		router.POST("/debug/loglevel", handler.SetLogLevel)
+	})
+
+	set := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/debug/loglevel", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Given a logger at info level
+	// When we lower the level to debug and then raise it to error
+	// Then debug entries should be emitted only after the first change
+	// and info entries suppressed after the second
+	It("should change which entries are emitted", func() {
+		// Arrange
+		log.Debug("before")
+
+		// Act
+		w := set(`{"level":"debug"}`)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp v1.LogLevel
+		Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Level).To(Equal(v1.Debug))
+
+		log.Debug("after debug")
+		Expect(logs.FilterMessage("before").Len()).To(Equal(0))
+		Expect(logs.FilterMessage("after debug").Len()).To(Equal(1))
+
+		// Act
+		w = set(`{"level":"error"}`)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(level.Level()).To(Equal(zapcore.ErrorLevel))
+
+		log.Info("after error")
+		log.Error("failure")
+		Expect(logs.FilterMessage("after error").Len()).To(Equal(0))
+		Expect(logs.FilterMessage("failure").Len()).To(Equal(1))
+	})
+
+	// Given a request with an unknown level
+	// When we post it
+	// Then it should return 400 and keep the current level
+	It("should return 400 for an unknown level", func() {
+		// Act
+		w := set(`{"level":"verbose"}`)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(level.Level()).To(Equal(zapcore.InfoLevel))
+	})
+
+	// Given a handler without a runtime log level
+	// When we change the level
+	// Then it should return 404
+	It("should return 404 when no log level is configured", func() {
+		// Arrange
+		router = gin.New()
+		router.POST("/debug/loglevel", handlers.NewHandler(config.Configuration{}).SetLogLevel)
+
+		// Act
+		w := set(`{"level":"debug"}`)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+})
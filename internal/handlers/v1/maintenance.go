@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+var _ = registerCapability("maintenance", "Maintenance mode rejecting mutating requests (GET, POST /agent/maintenance)")
+
+// GetMaintenance returns whether maintenance mode is enabled
+// (GET /agent/maintenance)
+func (h *Handler) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, v1.Maintenance{Enabled: h.maintenanceSrv.Enabled()})
+}
+
+// SetMaintenance enables or disables maintenance mode
+// (POST /agent/maintenance)
+func (h *Handler) SetMaintenance(c *gin.Context) {
+	var req v1.Maintenance
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, errors.NewValidationError(validationErrorMessage(err)))
+		return
+	}
+
+	if err := h.maintenanceSrv.SetEnabled(c.Request.Context(), req.Enabled); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.Maintenance{Enabled: h.maintenanceSrv.Enabled()})
+}
+
+// rejectInMaintenance writes a 503 response and returns true when the agent is
+// in maintenance mode. Handlers of mutating operations call it first.
+func (h *Handler) rejectInMaintenance(c *gin.Context) bool {
+	if h.maintenanceSrv == nil || !h.maintenanceSrv.Enabled() {
+		return false
+	}
+	respondError(c, http.StatusServiceUnavailable, errors.NewMaintenanceModeError())
+	return true
+}
@@ -0,0 +1,178 @@
+package v1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+var _ = Describe("Maintenance Handlers", func() {
+	var (
+		mockMaintenance *MockMaintenanceService
+		mockCollector   *MockCollectorService
+		mockInspector   *MockInspectorService
+		mockConsole     *MockConsoleService
+		router          *gin.Engine
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		mockMaintenance = &MockMaintenanceService{}
+		mockCollector = &MockCollectorService{
+			StatusResult: models.CollectorStatus{State: models.CollectorStateCollected},
+		}
+		mockInspector = &MockInspectorService{}
+		mockConsole = &MockConsoleService{}
+
+		handler := handlers.NewHandler(config.Configuration{}).
+			WithMaintenanceService(mockMaintenance).
+			WithCollectorService(mockCollector).
+			WithInspectorService(mockInspector).
+			WithVddkService(&MockVddkService{StatusResult: &models.VddkStatus{}}).
+			WithConsoleService(mockConsole)
+		router = gin.New()
+		router.GET("/agent", handler.GetAgentStatus)
+		router.GET("/agent/maintenance", handler.GetMaintenance)
+		router.POST("/agent/maintenance", handler.SetMaintenance)
+		router.POST("/agent/push-inventory", handler.PushInventory)
+		router.GET("/collector", func(c *gin.Context) {
+			var params v1.GetCollectorStatusParams
+			if err := c.ShouldBindQuery(&params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			handler.GetCollectorStatus(c, params)
+		})
+		router.POST("/collector", handler.StartCollector)
+		router.POST("/inspector", handler.StartInspection)
+	})
+
+	postJSON := func(path string, body any) *httptest.ResponseRecorder {
+		bodyBytes, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Describe("SetMaintenance", func() {
+		// Given maintenance mode is off
+		// When we enable it
+		// Then it should return the new state
+		It("should enable maintenance mode", func() {
+			// Act
+			w := postJSON("/agent/maintenance", v1.Maintenance{Enabled: true})
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var resp v1.Maintenance
+			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.Enabled).To(BeTrue())
+			Expect(mockMaintenance.EnabledResult).To(BeTrue())
+		})
+
+		// Given the flag cannot be persisted
+		// When we enable maintenance mode
+		// Then it should return 500
+		It("should return 500 when the flag cannot be saved", func() {
+			// Arrange
+			mockMaintenance.SetError = stderrors.New("database error")
+
+			// Act
+			w := postJSON("/agent/maintenance", v1.Maintenance{Enabled: true})
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("when maintenance mode is enabled", func() {
+		BeforeEach(func() {
+			mockMaintenance.EnabledResult = true
+		})
+
+		// Given maintenance mode is enabled
+		// When we start a collection
+		// Then it should return 503 and not start the collector
+		It("should block starting a collection", func() {
+			// Act
+			w := postJSON("/collector", v1.CollectorStartRequest{
+				Url:      "https://vcenter.example.com",
+				Username: "admin",
+				Password: "secret",
+			})
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(mockCollector.StartCallCount).To(Equal(0))
+		})
+
+		// Given maintenance mode is enabled
+		// When we start an inspection
+		// Then it should return 503 and not start the inspector
+		It("should block starting an inspection", func() {
+			// Act
+			w := postJSON("/inspector", v1.StartInspectionJSONRequestBody{VmIds: []string{"vm-1"}})
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(mockInspector.StartCallCount).To(Equal(0))
+		})
+
+		// Given maintenance mode is enabled
+		// When we force an inventory push
+		// Then it should return 503 and not push
+		It("should block inventory pushes", func() {
+			// Act
+			w := postJSON("/agent/push-inventory", nil)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(mockConsole.PushCallCount).To(Equal(0))
+		})
+
+		// Given maintenance mode is enabled
+		// When we call read endpoints
+		// Then they should keep working
+		It("should allow reads", func() {
+			for _, path := range []string{"/agent", "/collector", "/agent/maintenance"} {
+				// Arrange
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				Expect(w.Code).To(Equal(http.StatusOK), path)
+			}
+		})
+
+		// Given maintenance mode is enabled
+		// When we disable it
+		// Then mutations should be accepted again
+		It("should allow mutations once disabled", func() {
+			// Arrange
+			Expect(postJSON("/agent/maintenance", v1.Maintenance{Enabled: false}).Code).To(Equal(http.StatusOK))
+
+			// Act
+			w := postJSON("/agent/push-inventory", nil)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusAccepted))
+			Expect(mockConsole.PushCallCount).To(Equal(1))
+		})
+	})
+})
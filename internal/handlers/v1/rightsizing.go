@@ -11,13 +11,15 @@ import (
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
+var _ = registerCapability("rightsizing", "VM utilization reports for right-sizing (/rightsizing)")
+
 // ListRightsizingReports returns all stored rightsizing reports.
 // (GET /rightsizing)
 func (h *Handler) ListRightsizingReports(c *gin.Context) {
 	reports, err := h.rightsizingSrv.ListReports(c.Request.Context())
 	if err != nil {
 		zap.S().Named("rightsizing_handler").Errorw("failed to list reports", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -38,11 +40,11 @@ func (h *Handler) GetRightsizingReport(c *gin.Context, id string) {
 	report, err := h.rightsizingSrv.GetReport(c.Request.Context(), id)
 	if err != nil {
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
 		zap.S().Named("rightsizing_handler").Errorw("failed to get report", "id", id, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -54,7 +56,7 @@ func (h *Handler) GetRightsizingReport(c *gin.Context, id string) {
 func (h *Handler) TriggerRightsizingCollection(c *gin.Context) {
 	var req v1.RightsizingCollectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
 		return
 	}
 
@@ -81,7 +83,7 @@ func (h *Handler) TriggerRightsizingCollection(c *gin.Context) {
 	report, err := h.rightsizingSrv.TriggerCollection(c.Request.Context(), params)
 	if err != nil {
 		zap.S().Named("rightsizing_handler").Errorw("failed to trigger collection", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -94,11 +96,11 @@ func (h *Handler) GetVMUtilization(c *gin.Context, id string) {
 	details, err := h.rightsizingSrv.GetVMUtilization(c.Request.Context(), id)
 	if err != nil {
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
 		zap.S().Named("rightsizing_handler").Errorw("failed to get VM utilization", "id", id, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, v1.NewVmUtilizationDetailsFromModel(*details))
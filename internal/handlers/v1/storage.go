@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+var _ = registerCapability("debug.storage", "Database size, compaction and download (/debug/storage, /debug/compact, /debug/db)")
+
+// GetStorageUsage returns disk usage of the agent data folder
+// (GET /debug/storage)
+func (h *Handler) GetStorageUsage(c *gin.Context) {
+	usage, err := h.storageSrv.Usage(c.Request.Context())
+	if err != nil {
+		if srvErrors.IsResourceNotFoundError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewStorageUsageFromModel(*usage))
+}
+
+// CompactStorage compacts the agent database
+// (POST /debug/compact)
+func (h *Handler) CompactStorage(c *gin.Context) {
+	if err := h.storageSrv.Compact(c.Request.Context()); err != nil {
+		if srvErrors.IsResourceNotFoundError(err) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DownloadDatabase streams the checkpointed DuckDB file
+// (GET /debug/db)
+func (h *Handler) DownloadDatabase(c *gin.Context) {
+	if !h.cfg.Server.DatabaseDownloadEnabled {
+		respondError(c, http.StatusForbidden, errors.New("database download is disabled, start the agent with --server-database-download-enabled"))
+		return
+	}
+
+	f, err := h.storageSrv.OpenDatabase(c.Request.Context())
+	if err != nil {
+		if srvErrors.IsResourceNotFoundError(err) {
+			respondError(c, http.StatusConflict, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, info.Size(), "application/octet-stream", f, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, info.Name()),
+	})
+}
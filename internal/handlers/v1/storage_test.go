@@ -0,0 +1,235 @@
+package v1_test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+var _ = Describe("Storage Handler", func() {
+	var (
+		mockStorage *MockStorageService
+		router      *gin.Engine
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		mockStorage = &MockStorageService{}
+
+		handler := handlers.NewHandler(config.Configuration{}).
+			WithStorageService(mockStorage)
+		router = gin.New()
+		router.GET("/debug/storage", handler.GetStorageUsage)
+		router.POST("/debug/compact", handler.CompactStorage)
+	})
+
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/debug/storage", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Given a data folder with a database and a VDDK
+	// When we request storage usage
+	// Then it should return the measured sizes
+	It("should return storage usage", func() {
+		// Arrange
+		mockStorage.UsageResult = &models.StorageUsage{
+			Path:          "/var/lib/agent",
+			TotalBytes:    10000,
+			FreeBytes:     4000,
+			DatabaseBytes: 1536,
+			VddkBytes:     2048,
+		}
+
+		// Act
+		w := get()
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp v1.StorageUsage
+		Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Path).To(Equal("/var/lib/agent"))
+		Expect(resp.TotalBytes).To(Equal(int64(10000)))
+		Expect(resp.FreeBytes).To(Equal(int64(4000)))
+		Expect(resp.DatabaseBytes).To(Equal(int64(1536)))
+		Expect(resp.VddkBytes).To(Equal(int64(2048)))
+	})
+
+	// Given an agent without a data folder
+	// When we request storage usage
+	// Then it should return 404
+	It("should return 404 when no data folder is configured", func() {
+		// Arrange
+		mockStorage.UsageError = srvErrors.NewDataFolderNotSetError()
+
+		// Act
+		w := get()
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+
+	// Given the filesystem cannot be measured
+	// When we request storage usage
+	// Then it should return 500
+	It("should return 500 on unexpected errors", func() {
+		// Arrange
+		mockStorage.UsageError = stderrors.New("statfs failed")
+
+		// Act
+		w := get()
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusInternalServerError))
+	})
+
+	Describe("CompactStorage", func() {
+		compact := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/debug/compact", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		// Given a file-backed database
+		// When we request compaction
+		// Then it should compact and return 204
+		It("should return 204 after compacting", func() {
+			// Act
+			w := compact()
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			Expect(mockStorage.CompactCallCount).To(Equal(1))
+		})
+
+		// Given an agent without a data folder
+		// When we request compaction
+		// Then it should return 404
+		It("should return 404 when no data folder is configured", func() {
+			// Arrange
+			mockStorage.CompactError = srvErrors.NewDataFolderNotSetError()
+
+			// Act
+			w := compact()
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		// Given the database fails to checkpoint
+		// When we request compaction
+		// Then it should return 500
+		It("should return 500 on unexpected errors", func() {
+			// Arrange
+			mockStorage.CompactError = stderrors.New("checkpoint failed")
+
+			// Act
+			w := compact()
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("DownloadDatabase", func() {
+		download := func(enabled bool) *httptest.ResponseRecorder {
+			cfg := config.Configuration{Server: config.Server{DatabaseDownloadEnabled: enabled}}
+			handler := handlers.NewHandler(cfg).WithStorageService(mockStorage)
+			r := gin.New()
+			r.GET("/debug/db", handler.DownloadDatabase)
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/db", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		// Given an agent started without --server-database-download-enabled
+		// When we request the database file
+		// Then it should return 403 without touching the store
+		It("should return 403 when the download is disabled", func() {
+			// Arrange
+			mockStorage.DatabaseError = stderrors.New("must not be called")
+
+			// Act
+			w := download(false)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		// Given an agent with an in-memory database
+		// When we request the database file
+		// Then it should return 409
+		It("should return 409 when there is no data folder", func() {
+			// Arrange
+			mockStorage.DatabaseError = srvErrors.NewDataFolderNotSetError()
+
+			// Act
+			w := download(true)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+
+		// Given a file-backed store holding an inventory not yet checkpointed
+		// When we download the database file
+		// Then the downloaded bytes should open as a DuckDB database holding that inventory
+		It("should stream a database file that reopens with the same data", func() {
+			// Arrange
+			dataFolder := GinkgoT().TempDir()
+			db, err := store.NewDB(nil, filepath.Join(dataFolder, store.DatabaseFile))
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(db.Close)
+			Expect(migrations.Run(context.Background(), db)).To(Succeed())
+			st := store.NewStore(db, test.NewMockValidator())
+			Expect(st.Inventory().Save(context.Background(), []byte(`{"vms":[]}`))).To(Succeed())
+
+			cfg := config.Configuration{Server: config.Server{DatabaseDownloadEnabled: true}}
+			handler := handlers.NewHandler(cfg).
+				WithStorageService(services.NewStorageService(dataFolder, st))
+			r := gin.New()
+			r.GET("/debug/db", handler.DownloadDatabase)
+
+			// Act
+			req := httptest.NewRequest(http.MethodGet, "/debug/db", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/octet-stream"))
+			Expect(w.Header().Get("Content-Disposition")).To(ContainSubstring(store.DatabaseFile))
+
+			copyPath := filepath.Join(GinkgoT().TempDir(), "support.duckdb")
+			Expect(os.WriteFile(copyPath, w.Body.Bytes(), 0o600)).To(Succeed())
+			copyDB, err := store.NewDB(nil, copyPath)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(copyDB.Close)
+			inv, err := store.NewStore(copyDB, test.NewMockValidator()).Inventory().Get(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(inv.Data)).To(Equal(`{"vms":[]}`))
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 	"github.com/go-playground/validator/v10"
 
 	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
 // tagFormatRegex validates that tags contain only alphanumeric characters, underscores, and dots.
@@ -29,6 +30,30 @@ func validationErrorMessage(err error) string {
 	return "invalid request body"
 }
 
+// fieldValidationError translates validator.ValidationErrors into a
+// ValidationError keyed by JSON field name. Other errors (e.g. malformed JSON)
+// get a ValidationError without fields, as from validationErrorMessage.
+func fieldValidationError(err error) *srvErrors.ValidationError {
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return srvErrors.NewValidationError(validationErrorMessage(err))
+	}
+	fields := make(map[string]string, len(ve))
+	for _, fe := range ve {
+		fields[jsonFieldName(fe.Field())] = strings.TrimPrefix(formatFieldError(fe), fe.Field()+" ")
+	}
+	return srvErrors.NewFieldValidationError(fields)
+}
+
+// jsonFieldName maps a generated struct field name (e.g. "Url") to its JSON
+// name ("url"). The generated API types only differ in the first letter.
+func jsonFieldName(field string) string {
+	if field == "" {
+		return field
+	}
+	return strings.ToLower(field[:1]) + field[1:]
+}
+
 func formatFieldError(fe validator.FieldError) string {
 	field := fe.Field()
 	switch fe.Tag() {
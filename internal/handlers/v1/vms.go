@@ -1,37 +1,90 @@
 package v1
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/kubev2v/assisted-migration-agent/pkg/filter"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap"
 
 	v1 "github.com/kubev2v/assisted-migration-agent/api/v1"
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/services"
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
+var (
+	_ = registerCapability("vms.batch", "Details of several VMs in one request (POST /vms/batch)")
+	_ = registerCapability("vms.count", "GET /vms?count=true returns only the number of matching VMs")
+	_ = registerCapability("vms.explain", "GET /vms?explain=true returns the SQL of the list query")
+	_ = registerCapability("vms.filter.issue-label", "GET /vms issueLabel filter, a regular expression over concern labels")
+	_ = registerCapability("vms.filter.nic", "GET /vms nicType, nicCountMin and nicCountMax filters")
+	_ = registerCapability("vms.filter.readiness", "GET /vms readiness filter and the readiness filter identifier (migratable, warning, blocked)")
+	_ = registerCapability("vms.group-by", "VM count per cluster, datacenter, host, OS or power state (GET /vms/group-by)")
+	_ = registerCapability("vms.os-distribution", "VM count per guest operating system (GET /vms/os-distribution)")
+	_ = registerCapability("vms.ndjson", "GET /vms streams every matching VM as JSON Lines with Accept: application/x-ndjson")
+	_ = registerCapability("vms.projection", "GET /vms fields parameter returning only the requested VM fields")
+	_ = registerCapability("vms.views", "Saved GET /vms queries (GET, POST /vms/views and GET /vms?view=)")
+)
+
 var validSortFields = map[string]bool{
+	"name":           true,
+	"vCenterState":   true,
+	"cluster":        true,
+	"diskSize":       true,
+	"memory":         true,
+	"issues":         true,
+	"criticalIssues": true,
+	"warningIssues":  true,
+}
+
+// validProjectionFields lists the VM fields that can be requested with the
+// fields query parameter of GET /vms.
+var validProjectionFields = map[string]bool{
+	"id":           true,
 	"name":         true,
-	"vCenterState": true,
 	"cluster":      true,
 	"diskSize":     true,
 	"memory":       true,
-	"issues":       true,
+	"issueCount":   true,
+	"vCenterState": true,
 }
 
 const (
-	defaultPageSize      = 20
-	maxPageSize          = 100
-	maxDescriptionLength = 500
+	defaultPageSize        = 20
+	defaultMaxPageSize     = 100  // when Agent.MaxPageSize is unset
+	defaultMaxFilterLength = 4096 // when Agent.MaxFilterLength is unset
+	maxDescriptionLength   = 500
+	maxBatchVMIds          = 100
+	ndjsonContentType      = "application/x-ndjson"
 )
 
 // GetVMs returns the list of VMs with filtering and pagination
 // (GET /vms)
 func (h *Handler) GetVMs(c *gin.Context, params v1.GetVMsParams) {
+	// Apply saved view; explicit params win
+	if params.View != nil {
+		view, err := h.vmSrv.GetView(c.Request.Context(), *params.View)
+		if err != nil {
+			if srvErrors.IsResourceNotFoundError(err) {
+				respondError(c, http.StatusNotFound, err)
+				return
+			}
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		applyView(&params, *view)
+	}
+
 	// Parse pagination
 	page := 1
 	if params.Page != nil && *params.Page > 0 {
@@ -39,7 +92,7 @@ func (h *Handler) GetVMs(c *gin.Context, params v1.GetVMsParams) {
 	}
 	pageSize := defaultPageSize
 	if params.PageSize != nil && *params.PageSize > 0 {
-		pageSize = min(*params.PageSize, maxPageSize)
+		pageSize = min(*params.PageSize, h.maxPageSize())
 	}
 
 	// Build service params
@@ -50,37 +103,114 @@ func (h *Handler) GetVMs(c *gin.Context, params v1.GetVMsParams) {
 
 	if params.ByExpression != nil {
 		// validate expression
+		if h.filterTooLong(c, *params.ByExpression) {
+			return
+		}
 		if _, err := filter.ParseWithDefaultMap([]byte(*params.ByExpression)); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("expression filter is invalid: %v", err)})
+			respondError(c, http.StatusBadRequest, fmt.Errorf("expression filter is invalid: %v", err))
 			return
 		}
 		svcParams.Expression = *params.ByExpression
 	}
 
-	// Parse and validate sort params
-	if params.Sort != nil {
-		for _, s := range *params.Sort {
-			parts := strings.SplitN(s, ":", 2)
-			if len(parts) != 2 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort format, expected 'field:direction' (e.g., 'name:asc')"})
-				return
-			}
-			field, direction := parts[0], parts[1]
-			if !validSortFields[field] {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort field: " + field})
-				return
-			}
-			if direction != "asc" && direction != "desc" {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort direction: " + direction + ", must be 'asc' or 'desc'"})
+	if params.HasCritical != nil {
+		svcParams.HasCritical = *params.HasCritical
+	}
+
+	if params.IssueLabel != nil {
+		if _, err := regexp.Compile(*params.IssueLabel); err != nil {
+			respondError(c, http.StatusBadRequest, fmt.Errorf("issueLabel is not a valid regular expression: %v", err))
+			return
+		}
+		svcParams.IssueLabel = *params.IssueLabel
+	}
+
+	if params.NicType != nil {
+		if _, err := regexp.Compile(*params.NicType); err != nil {
+			respondError(c, http.StatusBadRequest, fmt.Errorf("nicType is not a valid regular expression: %v", err))
+			return
+		}
+		svcParams.NICType = *params.NicType
+	}
+
+	if err := validateNICCount(params.NicCountMin, params.NicCountMax); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	svcParams.NICCountMin = params.NicCountMin
+	svcParams.NICCountMax = params.NicCountMax
+
+	if params.Readiness != nil {
+		for _, r := range *params.Readiness {
+			readiness, err := models.ParseReadiness(string(r))
+			if err != nil {
+				respondError(c, http.StatusBadRequest, err)
 				return
 			}
-			svcParams.Sort = append(svcParams.Sort, services.SortField{Field: field, Desc: direction == "desc"})
+			svcParams.Readiness = append(svcParams.Readiness, readiness)
+		}
+	}
+
+	if params.IncludeSuppressed != nil {
+		svcParams.IncludeSuppressed = *params.IncludeSuppressed
+	}
+
+	if params.Count != nil && *params.Count {
+		total, err := h.vmSrv.Count(c.Request.Context(), svcParams)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to count VMs: %v", err))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"total": total})
+		return
+	}
+
+	var fields []string
+	if params.Fields != nil {
+		var err error
+		if fields, err = parseProjectionFields(*params.Fields); err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// Parse and validate sort params
+	if params.Sort != nil {
+		sort, err := parseSortParams(*params.Sort)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
 		}
+		svcParams.Sort = sort
+	}
+
+	if params.Explain != nil && *params.Explain {
+		// The SQL exposes the schema and the bound filter values, so it shares
+		// the debug switch of GET /debug/db.
+		if !h.cfg.Server.DatabaseDownloadEnabled {
+			respondError(c, http.StatusForbidden, errors.New("explain is disabled, start the agent with --server-database-download-enabled"))
+			return
+		}
+		query, args, err := h.vmSrv.Explain(svcParams)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to build VM query: %v", err))
+			return
+		}
+		if args == nil {
+			args = []any{}
+		}
+		c.JSON(http.StatusOK, v1.VmQueryExplain{Sql: query, Args: args})
+		return
+	}
+
+	if c.NegotiateFormat(binding.MIMEJSON, ndjsonContentType) == ndjsonContentType {
+		h.streamVMs(c, svcParams, fields)
+		return
 	}
 
 	vms, total, err := h.vmSrv.List(c.Request.Context(), svcParams)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to list VMs: %v", err)})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to list VMs: %v", err))
 		return
 	}
 
@@ -90,6 +220,8 @@ func (h *Handler) GetVMs(c *gin.Context, params v1.GetVMsParams) {
 		pageCount = 1
 	}
 
+	setPaginationHeaders(c, page, pageSize, pageCount, total)
+
 	// Get inspection status and Map to API response
 	apiVMs := make([]v1.VirtualMachine, 0, len(vms))
 	for _, vm := range vms {
@@ -97,6 +229,21 @@ func (h *Handler) GetVMs(c *gin.Context, params v1.GetVMsParams) {
 		apiVMs = append(apiVMs, v1.NewVirtualMachineFromSummary(vm))
 	}
 
+	if len(fields) > 0 {
+		projected, err := projectVMs(apiVMs, fields)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"page":      page,
+			"pageCount": pageCount,
+			"total":     total,
+			"vms":       projected,
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, v1.VirtualMachineListResponse{
 		Page:      page,
 		PageCount: pageCount,
@@ -105,33 +252,404 @@ func (h *Handler) GetVMs(c *gin.Context, params v1.GetVMsParams) {
 	})
 }
 
+// streamVMs writes every VM matching params as JSON Lines, one VM object per
+// line, flushing after each batch read from the service. Pagination is
+// ignored. An error before the first batch gets the usual error response;
+// after it the status is sent and errors can only be logged.
+func (h *Handler) streamVMs(c *gin.Context, params services.VMListParams, fields []string) {
+	enc := json.NewEncoder(c.Writer)
+	started := false
+
+	err := h.vmSrv.Stream(c.Request.Context(), params, func(vms []models.VirtualMachineSummary) error {
+		if !started {
+			c.Header("Content-Type", ndjsonContentType)
+			c.Status(http.StatusOK)
+			started = true
+		}
+		for _, vm := range vms {
+			vm.InspectionStatus = h.inspectorSrv.GetVmStatus(vm.ID)
+			var line any = v1.NewVirtualMachineFromSummary(vm)
+			if len(fields) > 0 {
+				projected, err := projectVMs([]v1.VirtualMachine{line.(v1.VirtualMachine)}, fields)
+				if err != nil {
+					return err
+				}
+				line = projected[0]
+			}
+			if err := enc.Encode(line); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		if !started {
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to list VMs: %v", err))
+			return
+		}
+		zap.S().Named("vms_handler").Errorw("failed to stream VMs", "error", err)
+		return
+	}
+
+	if !started {
+		c.Header("Content-Type", ndjsonContentType)
+		c.Status(http.StatusOK)
+	}
+}
+
+// setPaginationHeaders mirrors the body pagination in X-Total-Count and an
+// RFC 5988 Link header. Each link is the request URL with only page replaced;
+// prev and next are left out on the first and last page. X-Page-Size is the
+// page size applied, which is lower than the requested one when it was clamped.
+func setPaginationHeaders(c *gin.Context, page, pageSize, pageCount, total int) {
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.Header("X-Page-Size", strconv.Itoa(pageSize))
+
+	pageURL := func(p int) string {
+		query := c.Request.URL.Query()
+		query.Set("page", strconv.Itoa(p))
+		u := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+		return u.String()
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(1))}
+	if page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(min(page-1, pageCount))))
+	}
+	if page < pageCount {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(page+1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(pageCount)))
+	c.Header("Link", strings.Join(links, ", "))
+}
+
+// maxPageSize is the largest page size served. Larger requests are clamped to it.
+func (h *Handler) maxPageSize() int {
+	if h.cfg.Agent.MaxPageSize > 0 {
+		return h.cfg.Agent.MaxPageSize
+	}
+	return defaultMaxPageSize
+}
+
+// maxFilterLength is the longest filter expression accepted, in bytes.
+func (h *Handler) maxFilterLength() int {
+	if h.cfg.Agent.MaxFilterLength > 0 {
+		return h.cfg.Agent.MaxFilterLength
+	}
+	return defaultMaxFilterLength
+}
+
+// filterTooLong answers 413 when expr is longer than maxFilterLength, so an
+// oversized expression never reaches the filter parser.
+func (h *Handler) filterTooLong(c *gin.Context, expr string) bool {
+	limit := h.maxFilterLength()
+	if len(expr) <= limit {
+		return false
+	}
+	respondError(c, http.StatusRequestEntityTooLarge, srvErrors.NewValidationError(
+		fmt.Sprintf("filter expression is %d bytes, longer than the %d byte limit", len(expr), limit)))
+	return true
+}
+
 // GetVM returns details for a specific VM
 // (GET /vms/{id})
 func (h *Handler) GetVM(c *gin.Context, id string) {
 	vm, err := h.vmSrv.Get(c.Request.Context(), id)
 	if err != nil {
 		if srvErrors.IsResourceNotFoundError(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, v1.NewVirtualMachineDetailFromModel(*vm))
 }
 
+// BatchGetVMs returns details for several VMs, listing unknown ids separately
+// (POST /vms/batch)
+func (h *Handler) BatchGetVMs(c *gin.Context) {
+	var req v1.VmBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
+		return
+	}
+
+	if len(req.Ids) == 0 {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("ids must not be empty"))
+		return
+	}
+
+	if len(req.Ids) > maxBatchVMIds {
+		respondError(c, http.StatusBadRequest, srvErrors.NewFieldValidationError(map[string]string{"ids": fmt.Sprintf("must not hold more than %d ids", maxBatchVMIds)}))
+		return
+	}
+
+	vms, notFound, err := h.vmSrv.GetMany(c.Request.Context(), req.Ids)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := v1.VmBatchResponse{
+		Vms:      make([]v1.VirtualMachineDetail, 0, len(vms)),
+		NotFound: notFound,
+	}
+	for _, vm := range vms {
+		resp.Vms = append(resp.Vms, v1.NewVirtualMachineDetailFromModel(vm))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetVMsOsDistribution returns the number of VMs per guest OS
+// (GET /vms/os-distribution)
+func (h *Handler) GetVMsOsDistribution(c *gin.Context, params v1.GetVMsOsDistributionParams) {
+	var svcParams services.VMListParams
+
+	if params.ByExpression != nil {
+		if h.filterTooLong(c, *params.ByExpression) {
+			return
+		}
+		if _, err := filter.ParseWithDefaultMap([]byte(*params.ByExpression)); err != nil {
+			respondError(c, http.StatusBadRequest, fmt.Errorf("expression filter is invalid: %v", err))
+			return
+		}
+		svcParams.Expression = *params.ByExpression
+	}
+
+	if params.HasCritical != nil {
+		svcParams.HasCritical = *params.HasCritical
+	}
+
+	if params.IncludeSuppressed != nil {
+		svcParams.IncludeSuppressed = *params.IncludeSuppressed
+	}
+
+	distribution, err := h.vmSrv.OsDistribution(c.Request.Context(), svcParams)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to compute OS distribution: %v", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, distribution)
+}
+
+// GetVMsGroupBy returns the number of VMs per value of a field
+// (GET /vms/group-by)
+func (h *Handler) GetVMsGroupBy(c *gin.Context, params v1.GetVMsGroupByParams) {
+	var svcParams services.VMListParams
+
+	if params.ByExpression != nil {
+		if h.filterTooLong(c, *params.ByExpression) {
+			return
+		}
+		if _, err := filter.ParseWithDefaultMap([]byte(*params.ByExpression)); err != nil {
+			respondError(c, http.StatusBadRequest, fmt.Errorf("expression filter is invalid: %v", err))
+			return
+		}
+		svcParams.Expression = *params.ByExpression
+	}
+
+	if params.HasCritical != nil {
+		svcParams.HasCritical = *params.HasCritical
+	}
+
+	if params.IncludeSuppressed != nil {
+		svcParams.IncludeSuppressed = *params.IncludeSuppressed
+	}
+
+	counts, err := h.vmSrv.GroupBy(c.Request.Context(), string(params.Field), svcParams)
+	if err != nil {
+		if srvErrors.IsValidationError(err) {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to group VMs: %v", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// ListVMViews returns the saved GET /vms views
+// (GET /vms/views)
+func (h *Handler) ListVMViews(c *gin.Context) {
+	views, err := h.vmSrv.ListViews(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	apiViews := make([]v1.VmView, 0, len(views))
+	for _, view := range views {
+		apiViews = append(apiViews, v1.NewVmViewFromModel(view))
+	}
+
+	c.JSON(http.StatusOK, v1.VmViewListResponse{Views: apiViews})
+}
+
+// CreateVMView saves a named filter, sort and page size for GET /vms
+// (POST /vms/views)
+func (h *Handler) CreateVMView(c *gin.Context) {
+	var req v1.CreateVmViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError(validationErrorMessage(err)))
+		return
+	}
+
+	view := models.VMView{Name: strings.TrimSpace(req.Name)}
+	if view.Name == "" {
+		respondError(c, http.StatusBadRequest, srvErrors.NewValidationError("name must not be blank"))
+		return
+	}
+
+	if req.ByExpression != nil {
+		if h.filterTooLong(c, *req.ByExpression) {
+			return
+		}
+		if _, err := filter.ParseWithDefaultMap([]byte(*req.ByExpression)); err != nil {
+			respondError(c, http.StatusBadRequest, fmt.Errorf("expression filter is invalid: %v", err))
+			return
+		}
+		view.Expression = *req.ByExpression
+	}
+
+	if req.Sort != nil {
+		if _, err := parseSortParams(*req.Sort); err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		view.Sort = *req.Sort
+	}
+
+	if req.PageSize != nil {
+		if *req.PageSize < 1 || *req.PageSize > h.maxPageSize() {
+			respondError(c, http.StatusBadRequest, fmt.Errorf("pageSize must be between 1 and %d", h.maxPageSize()))
+			return
+		}
+		view.PageSize = *req.PageSize
+	}
+
+	created, err := h.vmSrv.CreateView(c.Request.Context(), view)
+	if err != nil {
+		if srvErrors.IsDuplicateResourceError(err) {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.NewVmViewFromModel(*created))
+}
+
 // RemoveVMFromInspection removes VM from inspection queue
 // (DELETE /vms/{id}/inspection)
 func (h *Handler) RemoveVMFromInspection(c *gin.Context, id string) {
 	if err := h.inspectorSrv.Cancel(id); err != nil {
 		if srvErrors.IsInspectorNotRunningError(err) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, v1.NewInspectionStatus(h.inspectorSrv.GetVmStatus(id)))
 }
+
+// applyView fills the GET /vms parameters the request left unset from a saved
+// view.
+func applyView(params *v1.GetVMsParams, view models.VMView) {
+	if params.ByExpression == nil && view.Expression != "" {
+		params.ByExpression = &view.Expression
+	}
+	if params.Sort == nil && len(view.Sort) > 0 {
+		params.Sort = &view.Sort
+	}
+	if params.PageSize == nil && view.PageSize > 0 {
+		params.PageSize = &view.PageSize
+	}
+}
+
+// parseSortParams parses "field:direction" sort entries and checks each field
+// against validSortFields.
+func parseSortParams(raw []string) ([]services.SortField, error) {
+	var sort []services.SortField
+	for _, s := range raw {
+		parts := strings.SplitN(s, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid sort format, expected 'field:direction' (e.g., 'name:asc')")
+		}
+		field, direction := parts[0], parts[1]
+		if !validSortFields[field] {
+			return nil, fmt.Errorf("invalid sort field: %s", field)
+		}
+		if direction != "asc" && direction != "desc" {
+			return nil, fmt.Errorf("invalid sort direction: %s, must be 'asc' or 'desc'", direction)
+		}
+		sort = append(sort, services.SortField{Field: field, Desc: direction == "desc"})
+	}
+	return sort, nil
+}
+
+// validateNICCount checks the nicCountMin and nicCountMax bounds: neither may
+// be negative and the range may not be empty.
+func validateNICCount(minCount, maxCount *int) error {
+	if minCount != nil && *minCount < 0 {
+		return fmt.Errorf("nicCountMin must not be negative")
+	}
+	if maxCount != nil && *maxCount < 0 {
+		return fmt.Errorf("nicCountMax must not be negative")
+	}
+	if minCount != nil && maxCount != nil && *minCount > *maxCount {
+		return fmt.Errorf("nicCountMin (%d) must not be greater than nicCountMax (%d)", *minCount, *maxCount)
+	}
+	return nil
+}
+
+// parseProjectionFields splits the comma-separated fields parameter and checks
+// every entry against validProjectionFields.
+func parseProjectionFields(raw string) ([]string, error) {
+	var fields []string
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !validProjectionFields[f] {
+			return nil, fmt.Errorf("invalid field: %s", f)
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields must list at least one field")
+	}
+	return fields, nil
+}
+
+// projectVMs keeps only the requested JSON fields of each VM.
+func projectVMs(vms []v1.VirtualMachine, fields []string) ([]map[string]any, error) {
+	projected := make([]map[string]any, 0, len(vms))
+	for _, vm := range vms {
+		data, err := json.Marshal(vm)
+		if err != nil {
+			return nil, err
+		}
+		var full map[string]any
+		if err := json.Unmarshal(data, &full); err != nil {
+			return nil, err
+		}
+		out := make(map[string]any, len(fields))
+		for _, f := range fields {
+			if v, ok := full[f]; ok {
+				out[f] = v
+			}
+		}
+		projected = append(projected, out)
+	}
+	return projected, nil
+}
@@ -5,8 +5,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
@@ -44,6 +48,25 @@ var _ = Describe("VMs Handlers", func() {
 			}
 			handler.GetVMs(c, params)
 		})
+		router.GET("/vms/os-distribution", func(c *gin.Context) {
+			var params v1.GetVMsOsDistributionParams
+			if err := c.ShouldBindQuery(&params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			handler.GetVMsOsDistribution(c, params)
+		})
+		router.GET("/vms/group-by", func(c *gin.Context) {
+			var params v1.GetVMsGroupByParams
+			if err := c.ShouldBindQuery(&params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			handler.GetVMsGroupBy(c, params)
+		})
+		router.GET("/vms/views", handler.ListVMViews)
+		router.POST("/vms/views", handler.CreateVMView)
+		router.POST("/vms/batch", handler.BatchGetVMs)
 		router.GET("/vms/:id", func(c *gin.Context) {
 			handler.GetVM(c, c.Param("id"))
 		})
@@ -145,6 +168,126 @@ var _ = Describe("VMs Handlers", func() {
 			// Assert
 			Expect(w.Code).To(Equal(http.StatusOK))
 			Expect(mockVM.LastListParams.Limit).To(Equal(uint64(100)))
+			Expect(w.Header().Get("X-Page-Size")).To(Equal("100"))
+		})
+
+		// Given the default maximum page size
+		// When we request exactly the maximum
+		// Then it should be served unclamped
+		It("should accept a page size equal to the default max", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?pageSize=100", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.Limit).To(Equal(uint64(100)))
+			Expect(w.Header().Get("X-Page-Size")).To(Equal("100"))
+		})
+
+		// Given a raised maximum page size
+		// When we request pages at and above it
+		// Then the maximum should be served and larger sizes clamped to it
+		It("should clamp to a configured max page size", func() {
+			// Arrange
+			cfg := config.Configuration{Agent: config.Agent{MaxPageSize: 500}}
+			handler := handlers.NewHandler(cfg).WithVMService(mockVM).WithInspectorService(mockInspector)
+			router := gin.New()
+			router.GET("/vms", func(c *gin.Context) {
+				var params v1.GetVMsParams
+				Expect(c.ShouldBindQuery(&params)).To(Succeed())
+				handler.GetVMs(c, params)
+			})
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+
+			// Act
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/vms?pageSize=500", nil))
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.Limit).To(Equal(uint64(500)))
+			Expect(w.Header().Get("X-Page-Size")).To(Equal("500"))
+
+			// Act
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/vms?pageSize=501", nil))
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.Limit).To(Equal(uint64(500)))
+			Expect(w.Header().Get("X-Page-Size")).To(Equal("500"))
+		})
+
+		// Given a middle page of the VM list
+		// When we request it
+		// Then X-Total-Count and all four Link relations should be set, keeping the query
+		It("should set pagination headers on a middle page", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+			mockVM.ListTotal = 50
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?page=3&pageSize=10&sort=name:asc", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("X-Total-Count")).To(Equal("50"))
+			Expect(w.Header().Get("Link")).To(Equal(
+				`</vms?page=1&pageSize=10&sort=name%3Aasc>; rel="first", ` +
+					`</vms?page=2&pageSize=10&sort=name%3Aasc>; rel="prev", ` +
+					`</vms?page=4&pageSize=10&sort=name%3Aasc>; rel="next", ` +
+					`</vms?page=5&pageSize=10&sort=name%3Aasc>; rel="last"`))
+		})
+
+		// Given the last page of the VM list
+		// When we request it
+		// Then the Link header should not contain a next relation
+		It("should omit next on the last page", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+			mockVM.ListTotal = 50
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?page=5&pageSize=10", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("X-Total-Count")).To(Equal("50"))
+			link := w.Header().Get("Link")
+			Expect(link).NotTo(ContainSubstring(`rel="next"`))
+			Expect(link).To(ContainSubstring(`</vms?page=4&pageSize=10>; rel="prev"`))
+			Expect(link).To(ContainSubstring(`</vms?page=5&pageSize=10>; rel="last"`))
+		})
+
+		// Given a single page of results
+		// When we request the VM list
+		// Then the Link header should only contain first and last
+		It("should only link first and last for a single page", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+			mockVM.ListTotal = 0
+
+			req := httptest.NewRequest(http.MethodGet, "/vms", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Header().Get("X-Total-Count")).To(Equal("0"))
+			Expect(w.Header().Get("Link")).To(Equal(`</vms?page=1>; rel="first", </vms?page=1>; rel="last"`))
 		})
 
 		// Given an invalid sort format
@@ -250,16 +393,630 @@ var _ = Describe("VMs Handlers", func() {
 			Expect(body["error"]).To(Equal("failed to list VMs: database error"))
 		})
 
-		It("should return 400 when byExpression is invalid", func() {
-			req := httptest.NewRequest(http.MethodGet, "/vms?byExpression=!!!invalid", nil)
+		It("should return 400 when byExpression is invalid", func() {
+			req := httptest.NewRequest(http.MethodGet, "/vms?byExpression=!!!invalid", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			var body map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body["error"]).To(HavePrefix("expression filter is invalid:"))
+		})
+
+		// Given a configured max filter length
+		// When byExpression is exactly at the limit, then one byte over it
+		// Then the first should be served and the second rejected with 413
+		It("should reject a byExpression longer than the max filter length", func() {
+			// Arrange
+			cfg := config.Configuration{Agent: config.Agent{MaxFilterLength: 32}}
+			handler := handlers.NewHandler(cfg).WithVMService(mockVM).WithInspectorService(mockInspector)
+			router := gin.New()
+			router.GET("/vms", func(c *gin.Context) {
+				var params v1.GetVMsParams
+				Expect(c.ShouldBindQuery(&params)).To(Succeed())
+				handler.GetVMs(c, params)
+			})
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+			expression := func(length int) string {
+				return "name = '" + strings.Repeat("a", length-9) + "'"
+			}
+
+			// Act
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/vms?byExpression="+url.QueryEscape(expression(32)), nil))
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.Expression).To(HaveLen(32))
+
+			// Act
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/vms?byExpression="+url.QueryEscape(expression(33)), nil))
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusRequestEntityTooLarge))
+			var body map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body["error"]).To(Equal("filter expression is 33 bytes, longer than the 32 byte limit"))
+		})
+
+		// Given the hasCritical query parameter
+		// When we request the VM list
+		// Then it should be passed to the service
+		It("should pass hasCritical to the service", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?hasCritical=true", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.HasCritical).To(BeTrue())
+		})
+
+		// Given the issueLabel query parameter
+		// When we request the VM list
+		// Then it should be passed to the service
+		It("should pass issueLabel to the service", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?issueLabel="+url.QueryEscape("(?i)^rdm"), nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.IssueLabel).To(Equal("(?i)^rdm"))
+		})
+
+		// Given an issueLabel that is not a valid regular expression
+		// When we request the VM list
+		// Then it should return 400 without calling the service
+		It("should return 400 for an invalid issueLabel", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/vms?issueLabel="+url.QueryEscape("disk("), nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			var body map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body["error"]).To(HavePrefix("issueLabel is not a valid regular expression:"))
+			Expect(mockVM.LastListParams.IssueLabel).To(BeEmpty())
+		})
+
+		// Given the nicType, nicCountMin and nicCountMax query parameters
+		// When we request the VM list
+		// Then they should be passed to the service
+		It("should pass the NIC filters to the service", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?nicType="+url.QueryEscape("(?i)^e1000")+"&nicCountMin=1&nicCountMax=2", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.NICType).To(Equal("(?i)^e1000"))
+			Expect(mockVM.LastListParams.NICCountMin).To(HaveValue(Equal(1)))
+			Expect(mockVM.LastListParams.NICCountMax).To(HaveValue(Equal(2)))
+		})
+
+		// Given invalid NIC filters
+		// When we request the VM list
+		// Then it should return 400 without calling the service
+		It("should return 400 for invalid NIC filters", func() {
+			for query, prefix := range map[string]string{
+				"nicType=" + url.QueryEscape("e1000("): "nicType is not a valid regular expression:",
+				"nicCountMin=-1":                       "nicCountMin must not be negative",
+				"nicCountMax=-1":                       "nicCountMax must not be negative",
+				"nicCountMin=3&nicCountMax=2":          "nicCountMin (3) must not be greater than nicCountMax (2)",
+			} {
+				// Arrange
+				req := httptest.NewRequest(http.MethodGet, "/vms?"+query, nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				Expect(w.Code).To(Equal(http.StatusBadRequest), query)
+				var body map[string]any
+				Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+				Expect(body["error"]).To(HavePrefix(prefix), query)
+			}
+			Expect(mockVM.LastListParams.NICType).To(BeEmpty())
+		})
+
+		// Given the readiness query parameter, repeated
+		// When we request the VM list
+		// Then every value should be passed to the service
+		It("should pass the readiness filter to the service", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?readiness=warning&readiness=blocked", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.Readiness).To(Equal([]models.Readiness{models.ReadinessWarning, models.ReadinessBlocked}))
+		})
+
+		// Given an unknown readiness
+		// When we request the VM list
+		// Then it should return 400 without calling the service
+		It("should return 400 for an unknown readiness", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/vms?readiness=ready", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(w.Body.String()).To(ContainSubstring("invalid readiness: ready"))
+			Expect(mockVM.LastListParams.Readiness).To(BeNil())
+		})
+
+		// Given a service failing before any VM is read
+		// When we request the VM list as application/x-ndjson
+		// Then it should return a 500 error response
+		It("should return 500 when the stream fails before any VM", func() {
+			// Arrange
+			mockVM.ListError = errors.New("database error")
+
+			req := httptest.NewRequest(http.MethodGet, "/vms", nil)
+			req.Header.Set("Accept", "application/x-ndjson")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			Expect(w.Body.String()).To(ContainSubstring("failed to list VMs: database error"))
+		})
+
+		// Given count=true
+		// When we request the VM list
+		// Then only the total should be returned and no rows fetched
+		It("should return only the total in count mode", func() {
+			// Arrange
+			mockVM.ListTotal = 42
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?count=true&byExpression=cluster%20%3D%20'prod'&hasCritical=true", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var body map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body).To(Equal(map[string]any{"total": float64(42)}))
+			Expect(body).NotTo(HaveKey("vms"))
+			Expect(mockVM.CountCallCount).To(Equal(1))
+			Expect(mockVM.LastListParams.Expression).To(Equal("cluster = 'prod'"))
+			Expect(mockVM.LastListParams.HasCritical).To(BeTrue())
+		})
+
+		// Given the count query fails
+		// When we request the VM list in count mode
+		// Then it should return 500
+		It("should return 500 when counting fails", func() {
+			// Arrange
+			mockVM.CountError = errors.New("db error")
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?count=true", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+
+		// Given VMs exist in the store
+		// When we request the VM list with a fields projection
+		// Then each VM should contain only the requested fields
+		It("should return only the requested fields", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{
+				{ID: "vm-1", Name: "VM 1", Cluster: "cluster-1", DiskSize: 1024, Memory: 2048, PowerState: "poweredOn", IssueCount: 3},
+			}
+			mockVM.ListTotal = 1
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?fields=id,name,issueCount", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var body struct {
+				Page  int              `json:"page"`
+				Total int              `json:"total"`
+				Vms   []map[string]any `json:"vms"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Page).To(Equal(1))
+			Expect(body.Total).To(Equal(1))
+			Expect(body.Vms).To(HaveLen(1))
+			Expect(body.Vms[0]).To(Equal(map[string]any{
+				"id":         "vm-1",
+				"name":       "VM 1",
+				"issueCount": float64(3),
+			}))
+		})
+
+		// Given a fields projection with surrounding spaces
+		// When we request the VM list
+		// Then the fields should be trimmed and applied
+		It("should trim spaces in the fields parameter", func() {
+			// Arrange
+			mockVM.ListResult = []models.VirtualMachineSummary{
+				{ID: "vm-1", Name: "VM 1", Cluster: "cluster-1", PowerState: "poweredOn"},
+			}
+			mockVM.ListTotal = 1
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?fields=cluster,%20vCenterState", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var body struct {
+				Vms []map[string]any `json:"vms"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Vms[0]).To(HaveLen(2))
+			Expect(body.Vms[0]).To(HaveKeyWithValue("cluster", "cluster-1"))
+			Expect(body.Vms[0]).To(HaveKey("vCenterState"))
+		})
+
+		// Given a fields projection with an unknown field
+		// When we request the VM list
+		// Then it should return 400 without querying the service
+		It("should return 400 for an unknown field", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/vms?fields=id,tags", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			var body map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+			Expect(body["error"]).To(Equal("invalid field: tags"))
+		})
+
+		// Given an empty fields parameter
+		// When we request the VM list
+		// Then it should return 400
+		It("should return 400 for an empty fields parameter", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/vms?fields=,", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		// Given explain=true with pagination
+		// When we request the VM list
+		// Then it should return the query from the service with the same paging
+		It("should return the query instead of the VMs when explain is set", func() {
+			// Arrange
+			cfg := config.Configuration{Server: config.Server{DatabaseDownloadEnabled: true}}
+			handler = handlers.NewHandler(cfg).WithVMService(mockVM).WithInspectorService(mockInspector)
+			mockVM.ExplainSQL = "SELECT 1 LIMIT 10 OFFSET 10"
+			mockVM.ExplainArgs = []any{"x"}
+			req := httptest.NewRequest(http.MethodGet, "/vms?explain=true&page=2&pageSize=10", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response v1.VmQueryExplain
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Sql).To(Equal("SELECT 1 LIMIT 10 OFFSET 10"))
+			Expect(response.Args).To(Equal([]any{"x"}))
+			Expect(mockVM.LastListParams.Limit).To(Equal(uint64(10)))
+			Expect(mockVM.LastListParams.Offset).To(Equal(uint64(10)))
+			Expect(w.Header().Get("X-Total-Count")).To(BeEmpty())
+		})
+
+		// Given an agent started without --server-database-download-enabled
+		// When we request the VM list with explain=true
+		// Then it should return 403 without building the query
+		It("should return 403 for explain when the debug switch is off", func() {
+			// Arrange
+			mockVM.ExplainSQL = "SELECT 1"
+			req := httptest.NewRequest(http.MethodGet, "/vms?explain=true", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(w.Body.String()).To(ContainSubstring("--server-database-download-enabled"))
+			Expect(w.Body.String()).NotTo(ContainSubstring("SELECT 1"))
+		})
+	})
+
+	Context("GetVMsOsDistribution", func() {
+		// Given filter parameters
+		// When we request the OS distribution
+		// Then they should be passed to the service
+		It("should pass the filters to the service", func() {
+			// Arrange
+			mockVM.OsResult = map[string]int{"CentOS 8": 2}
+
+			req := httptest.NewRequest(http.MethodGet, "/vms/os-distribution?hasCritical=true&byExpression="+url.QueryEscape("cluster = 'staging'"), nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.Expression).To(Equal("cluster = 'staging'"))
+			Expect(mockVM.LastListParams.HasCritical).To(BeTrue())
+			Expect(w.Body.String()).To(MatchJSON(`{"CentOS 8": 2}`))
+		})
+
+		// Given the service fails
+		// When we request the OS distribution
+		// Then it should return 500
+		It("should return 500 on service error", func() {
+			// Arrange
+			mockVM.OsError = errors.New("database error")
+
+			req := httptest.NewRequest(http.MethodGet, "/vms/os-distribution", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("GetVMsGroupBy", func() {
+		// Given a field and filter parameters
+		// When we request the VM counts grouped by the field
+		// Then they should be passed to the service
+		It("should pass the field and filters to the service", func() {
+			// Arrange
+			mockVM.GroupResult = map[string]int{"staging": 3}
+
+			req := httptest.NewRequest(http.MethodGet, "/vms/group-by?field=cluster&hasCritical=true&byExpression="+url.QueryEscape("cluster = 'staging'"), nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastGroupField).To(Equal("cluster"))
+			Expect(mockVM.LastListParams.Expression).To(Equal("cluster = 'staging'"))
+			Expect(mockVM.LastListParams.HasCritical).To(BeTrue())
+			Expect(w.Body.String()).To(MatchJSON(`{"staging": 3}`))
+		})
+
+		// Given the service fails
+		// When we request the VM counts grouped by a field
+		// Then it should return 500
+		It("should return 500 on service error", func() {
+			// Arrange
+			mockVM.GroupError = errors.New("database error")
+
+			req := httptest.NewRequest(http.MethodGet, "/vms/group-by?field=cluster", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("Saved views", func() {
+		createView := func(body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/vms/views", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		// Given a valid view definition
+		// When we save it
+		// Then it should be created and returned
+		It("should create a view", func() {
+			// Act
+			w := createView(`{"name":"big-prod","byExpression":"cluster = 'production'","sort":["memory:desc"],"pageSize":50}`)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusCreated))
+
+			var response v1.VmView
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Name).To(Equal("big-prod"))
+			Expect(*response.ByExpression).To(Equal("cluster = 'production'"))
+			Expect(response.Sort).To(Equal([]string{"memory:desc"}))
+			Expect(*response.PageSize).To(Equal(50))
+			Expect(mockVM.Views).To(HaveKey("big-prod"))
+		})
+
+		// Given saved views
+		// When we list them
+		// Then they should be returned ordered by name
+		It("should list views", func() {
+			// Arrange
+			Expect(createView(`{"name":"b-view","sort":["name:asc"]}`).Code).To(Equal(http.StatusCreated))
+			Expect(createView(`{"name":"a-view","pageSize":10}`).Code).To(Equal(http.StatusCreated))
+
+			req := httptest.NewRequest(http.MethodGet, "/vms/views", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var response v1.VmViewListResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Views).To(HaveLen(2))
+			Expect(response.Views[0].Name).To(Equal("a-view"))
+			Expect(response.Views[0].ByExpression).To(BeNil())
+			Expect(response.Views[1].Name).To(Equal("b-view"))
+		})
+
+		// Given invalid view definitions
+		// When we try to save them
+		// Then each should be rejected with 400
+		It("should reject invalid views", func() {
+			for _, body := range []string{
+				`{"name":""}`,
+				`{"name":"   "}`,
+				`{"name":"v","byExpression":"cluster = "}`,
+				`{"name":"v","sort":["unknown:asc"]}`,
+				`{"name":"v","sort":["name"]}`,
+				`{"name":"v","pageSize":0}`,
+				`{"name":"v","pageSize":1000}`,
+			} {
+				// Act
+				w := createView(body)
+
+				// Assert
+				Expect(w.Code).To(Equal(http.StatusBadRequest), body)
+			}
+			Expect(mockVM.Views).To(BeEmpty())
+		})
+
+		// Given a view with the same name exists
+		// When we save another with that name
+		// Then it should return 400
+		It("should reject a duplicate name", func() {
+			// Arrange
+			Expect(createView(`{"name":"dup"}`).Code).To(Equal(http.StatusCreated))
+
+			// Act
+			w := createView(`{"name":"dup"}`)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		// Given a saved view
+		// When we list VMs with ?view=
+		// Then its expression, sort and page size should be applied
+		It("should apply a view to GET /vms", func() {
+			// Arrange
+			Expect(createView(`{"name":"prod","byExpression":"cluster = 'production'","sort":["memory:desc"],"pageSize":5}`).Code).To(Equal(http.StatusCreated))
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?view=prod", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.Expression).To(Equal("cluster = 'production'"))
+			Expect(mockVM.LastListParams.Sort).To(Equal([]services.SortField{{Field: "memory", Desc: true}}))
+			Expect(mockVM.LastListParams.Limit).To(Equal(uint64(5)))
+		})
+
+		// Given a saved view
+		// When we list VMs with ?view= and explicit parameters
+		// Then the explicit parameters should win and the rest come from the view
+		It("should let explicit parameters override the view", func() {
+			// Arrange
+			Expect(createView(`{"name":"prod","byExpression":"cluster = 'production'","sort":["memory:desc"],"pageSize":5}`).Code).To(Equal(http.StatusCreated))
+			mockVM.ListResult = []models.VirtualMachineSummary{}
+
+			req := httptest.NewRequest(http.MethodGet, "/vms?view=prod&sort=name:asc&pageSize=7", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockVM.LastListParams.Expression).To(Equal("cluster = 'production'"))
+			Expect(mockVM.LastListParams.Sort).To(Equal([]services.SortField{{Field: "name", Desc: false}}))
+			Expect(mockVM.LastListParams.Limit).To(Equal(uint64(7)))
+		})
+
+		// Given no view with the requested name
+		// When we list VMs with ?view=
+		// Then it should return 404
+		It("should return 404 for an unknown view", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/vms?view=missing", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		// Given the view store fails
+		// When we list views
+		// Then it should return 500
+		It("should return 500 when listing fails", func() {
+			// Arrange
+			mockVM.ViewsError = errors.New("database error")
+
+			req := httptest.NewRequest(http.MethodGet, "/vms/views", nil)
 			w := httptest.NewRecorder()
 
+			// Act
 			router.ServeHTTP(w, req)
 
-			Expect(w.Code).To(Equal(http.StatusBadRequest))
-			var body map[string]any
-			Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
-			Expect(body["error"]).To(HavePrefix("expression filter is invalid:"))
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
 		})
 	})
 
@@ -333,6 +1090,89 @@ var _ = Describe("VMs Handlers", func() {
 		})
 	})
 
+	Context("BatchGetVMs", func() {
+		// Given VMs returned by the service
+		// When we request them in a batch
+		// Then it should return their details and the unknown ids
+		It("should return VM details and not found ids", func() {
+			// Arrange
+			mockVM.ManyResult = []models.VM{
+				{ID: "vm-1", Name: "first", CpuCount: 2},
+				{ID: "vm-2", Name: "second", CpuCount: 4},
+			}
+			mockVM.ManyNotFound = []string{"vm-x"}
+
+			req := httptest.NewRequest(http.MethodPost, "/vms/batch", strings.NewReader(`{"ids": ["vm-1", "vm-x", "vm-2"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var response v1.VmBatchResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Vms).To(HaveLen(2))
+			Expect(response.Vms[0].Id).To(Equal("vm-1"))
+			Expect(response.Vms[1].CpuCount).To(Equal(int32(4)))
+			Expect(response.NotFound).To(Equal([]string{"vm-x"}))
+		})
+
+		// Given a request without ids
+		// When we request a batch
+		// Then it should return 400 without calling the service
+		It("should return 400 for empty ids", func() {
+			for _, body := range []string{`{"ids": []}`, `{}`} {
+				req := httptest.NewRequest(http.MethodPost, "/vms/batch", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest), body)
+			}
+			Expect(mockVM.ManyCallCount).To(BeZero())
+		})
+
+		// Given a request with more ids than the batch limit
+		// When we request a batch
+		// Then it should return 400 without calling the service
+		It("should return 400 for too many ids", func() {
+			// Arrange
+			ids := make([]string, 101)
+			for i := range ids {
+				ids[i] = fmt.Sprintf("vm-%d", i)
+			}
+			body, err := json.Marshal(v1.VmBatchRequest{Ids: ids})
+			Expect(err).NotTo(HaveOccurred())
+			req := httptest.NewRequest(http.MethodPost, "/vms/batch", strings.NewReader(string(body)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(w.Body.String()).To(ContainSubstring("ids"))
+			Expect(mockVM.ManyCallCount).To(BeZero())
+		})
+
+		It("should return 500 on service error", func() {
+			mockVM.ManyError = errors.New("database connection lost")
+
+			req := httptest.NewRequest(http.MethodPost, "/vms/batch", strings.NewReader(`{"ids": ["vm-1"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
 	Context("VM inspection endpoints (/vms/{id}/inspection)", func() {
 		// Given a VM that has been cancelled
 		// When we remove it from inspection
@@ -466,6 +1306,23 @@ var _ = Describe("VMs Handlers Integration", func() {
 			}
 			handler.GetVMs(c, params)
 		})
+		router.GET("/vms/os-distribution", func(c *gin.Context) {
+			var params v1.GetVMsOsDistributionParams
+			if err := c.ShouldBindQuery(&params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			handler.GetVMsOsDistribution(c, params)
+		})
+		router.GET("/vms/group-by", func(c *gin.Context) {
+			var params v1.GetVMsGroupByParams
+			if err := c.ShouldBindQuery(&params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			handler.GetVMsGroupBy(c, params)
+		})
+		router.POST("/vms/batch", handler.BatchGetVMs)
 		router.GET("/vms/:id", func(c *gin.Context) {
 			handler.GetVM(c, c.Param("id"))
 		})
@@ -649,6 +1506,24 @@ var _ = Describe("VMs Handlers Integration", func() {
 			Expect(response.Vms[0].IssueCount).To(Equal(3)) // vm-007 has 3 issues
 		})
 
+		It("should sort by critical issues then name", func() {
+			req := httptest.NewRequest(http.MethodGet, "/vms?sort=criticalIssues:desc&sort=name:asc&pageSize=50", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var response v1.VirtualMachineListResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Vms).To(HaveLen(10))
+			Expect(response.Vms[0].Name).To(Equal("cache-server-1")) // vm-007 is the only VM with a critical issue
+			Expect(response.Vms[0].CriticalCount).To(Equal(1))
+			for i := 2; i < len(response.Vms); i++ {
+				Expect(response.Vms[i-1].Name <= response.Vms[i].Name).To(BeTrue())
+			}
+		})
+
 		It("should combine byExpression filter with pagination", func() {
 			req := httptest.NewRequest(http.MethodGet, "/vms?byExpression=cluster+%3D+%27production%27&page=1&pageSize=2", nil)
 			w := httptest.NewRecorder()
@@ -729,6 +1604,478 @@ var _ = Describe("VMs Handlers Integration", func() {
 			Expect(issueMap["vm-007"]).To(Equal(3))
 			Expect(issueMap["vm-001"]).To(Equal(0))
 		})
+
+		Context("issueLabel", func() {
+			listIDs := func(query string) []string {
+				req := httptest.NewRequest(http.MethodGet, "/vms?pageSize=50&sort=name:asc&"+query, nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var response v1.VirtualMachineListResponse
+				Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+				Expect(response.Total).To(Equal(len(response.Vms)))
+				ids := make([]string, 0, len(response.Vms))
+				for _, vm := range response.Vms {
+					ids = append(ids, vm.Id)
+				}
+				return ids
+			}
+
+			// Given VMs with concerns of different labels
+			// When we filter by a label pattern
+			// Then only VMs with a matching concern should be returned
+			It("should return VMs with a concern label matching the pattern", func() {
+				ids := listIDs("issueLabel=" + url.QueryEscape("usage|backup"))
+
+				Expect(ids).To(Equal([]string{"vm-003", "vm-004"}))
+			})
+
+			// Given a case-insensitive pattern
+			// When we filter by it
+			// Then labels should match regardless of case
+			It("should honor regex flags", func() {
+				ids := listIDs("issueLabel=" + url.QueryEscape("(?i)VMWARE tools|^rdm"))
+
+				Expect(ids).To(ConsistOf("vm-003", "vm-007"))
+			})
+
+			// Given a pattern no concern label matches
+			// When we filter by it
+			// Then no VMs should be returned
+			It("should return no VMs when no label matches", func() {
+				Expect(listIDs("issueLabel=" + url.QueryEscape("^nothing$"))).To(BeEmpty())
+			})
+
+			// Given issueLabel together with byExpression and hasCritical
+			// When we list VMs
+			// Then all filters should apply
+			It("should combine with the other filters", func() {
+				Expect(listIDs("issueLabel=" + url.QueryEscape("usage|backup|state") + "&byExpression=" + url.QueryEscape("cluster = 'production'"))).
+					To(Equal([]string{"vm-003", "vm-004"}))
+				Expect(listIDs("issueLabel=" + url.QueryEscape("usage|backup|state") + "&hasCritical=true")).
+					To(Equal([]string{"vm-007"}))
+			})
+		})
+
+		Context("NIC filters", func() {
+			listIDs := func(query string) []string {
+				req := httptest.NewRequest(http.MethodGet, "/vms?pageSize=50&sort=name:asc&"+query, nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var response v1.VirtualMachineListResponse
+				Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+				Expect(response.Total).To(Equal(len(response.Vms)))
+				ids := make([]string, 0, len(response.Vms))
+				for _, vm := range response.Vms {
+					ids = append(ids, vm.Id)
+				}
+				return ids
+			}
+
+			// Given VMs with e1000, e1000e and vmxnet3 NICs
+			// When we filter by an e1000 pattern
+			// Then only VMs with a matching NIC should be returned
+			It("should return VMs with a NIC type matching the pattern", func() {
+				Expect(listIDs("nicType=" + url.QueryEscape("^e1000$"))).To(Equal([]string{"vm-002"}))
+				Expect(listIDs("nicType=" + url.QueryEscape("(?i)^e1000"))).To(Equal([]string{"vm-007", "vm-003", "vm-002"}))
+			})
+
+			// Given VMs with zero, one and two NICs
+			// When we filter by NIC count
+			// Then only VMs within the bounds should be returned
+			It("should filter by NIC count", func() {
+				Expect(listIDs("nicCountMin=2")).To(Equal([]string{"vm-003"}))
+				Expect(listIDs("nicCountMin=1&nicCountMax=1")).To(Equal([]string{"vm-007", "vm-001", "vm-002"}))
+				Expect(listIDs("nicCountMax=0")).To(ConsistOf("vm-004", "vm-005", "vm-006", "vm-008", "vm-009", "vm-010"))
+			})
+
+			// Given nicType together with a NIC count and byExpression
+			// When we list VMs
+			// Then all filters should apply
+			It("should combine with the other filters", func() {
+				Expect(listIDs("nicType=" + url.QueryEscape("(?i)e1000") + "&nicCountMax=1")).To(Equal([]string{"vm-007", "vm-002"}))
+				Expect(listIDs("nicType=" + url.QueryEscape("(?i)e1000") + "&byExpression=" + url.QueryEscape("cluster = 'production'"))).
+					To(Equal([]string{"vm-003", "vm-002"}))
+			})
+		})
+
+		Context("readiness filter", func() {
+			listIDs := func(query string) []string {
+				req := httptest.NewRequest(http.MethodGet, "/vms?pageSize=50&"+query, nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var response v1.VirtualMachineListResponse
+				Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+				Expect(response.Total).To(Equal(len(response.Vms)))
+				ids := make([]string, 0, len(response.Vms))
+				for _, vm := range response.Vms {
+					ids = append(ids, vm.Id)
+				}
+				return ids
+			}
+
+			// Given vm-007 with a Critical concern, vm-003 and vm-004 with only
+			// Warning ones, and the other VMs without either
+			// When we filter on each readiness, by parameter and by expression
+			// Then each VM should be in exactly one bucket
+			It("should put each VM in one readiness bucket", func() {
+				buckets := map[string][]string{
+					"blocked":    {"vm-007"},
+					"warning":    {"vm-003", "vm-004"},
+					"migratable": {"vm-001", "vm-002", "vm-005", "vm-006", "vm-008", "vm-009", "vm-010"},
+				}
+				for readiness, ids := range buckets {
+					Expect(listIDs("readiness="+readiness)).To(ConsistOf(ids), readiness)
+					Expect(listIDs("byExpression="+url.QueryEscape("readiness = '"+readiness+"'"))).To(ConsistOf(ids), readiness)
+				}
+			})
+
+			// Given several readiness values and another filter
+			// When we list VMs
+			// Then VMs in any of the buckets should match the other filter
+			It("should combine with the other filters", func() {
+				Expect(listIDs("readiness=warning&readiness=blocked")).To(ConsistOf("vm-003", "vm-004", "vm-007"))
+				Expect(listIDs("readiness=migratable&byExpression=" + url.QueryEscape("cluster = 'production'"))).
+					To(ConsistOf("vm-001", "vm-002"))
+			})
+		})
+	})
+
+	Context("GetVMs as JSON Lines with real data", func() {
+		// Given a filter and a page size smaller than the matching VMs
+		// When we request the VM list as application/x-ndjson
+		// Then each line should parse on its own as a VM, and there should
+		// be one line per matching VM, regardless of the page size
+		It("should stream one VM per line", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/vms?pageSize=2&sort=name:asc&byExpression="+url.QueryEscape("cluster = 'production'"), nil)
+			req.Header.Set("Accept", "application/x-ndjson")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/x-ndjson"))
+			Expect(w.Header().Get("X-Total-Count")).To(BeEmpty())
+
+			lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+			names := make([]string, 0, len(lines))
+			for _, line := range lines {
+				var vm v1.VirtualMachine
+				Expect(json.Unmarshal([]byte(line), &vm)).To(Succeed(), line)
+				Expect(vm.Cluster).To(Equal("production"))
+				names = append(names, vm.Name)
+			}
+
+			total, err := vmSrv.Count(ctx, services.VMListParams{Expression: "cluster = 'production'"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lines).To(HaveLen(total))
+			Expect(slices.IsSorted(names)).To(BeTrue())
+		})
+
+		// Given the fields parameter
+		// When we request the VM list as application/x-ndjson
+		// Then each line should hold only the requested fields
+		It("should project each line", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/vms?fields=id,cluster", nil)
+			req.Header.Set("Accept", "application/x-ndjson")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+			Expect(lines).To(HaveLen(10))
+			for _, line := range lines {
+				var vm map[string]any
+				Expect(json.Unmarshal([]byte(line), &vm)).To(Succeed(), line)
+				Expect(vm).To(HaveLen(2))
+				Expect(vm).To(HaveKey("id"))
+				Expect(vm).To(HaveKey("cluster"))
+			}
+		})
+
+		// Given a filter no VM matches
+		// When we request the VM list as application/x-ndjson
+		// Then the body should be empty
+		It("should return an empty body when no VM matches", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/vms?byExpression="+url.QueryEscape("cluster = 'none'"), nil)
+			req.Header.Set("Accept", "application/x-ndjson")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/x-ndjson"))
+			Expect(w.Body.String()).To(BeEmpty())
+		})
+	})
+
+	Context("GetVMs explain with real data", func() {
+		// Given a filter, a sort and the second page
+		// When we request the VM list with explain=true
+		// Then the SQL should carry the filter, sort and pagination and select the same VMs
+		It("should return the SQL of the list query", func() {
+			// Arrange
+			handler = handlers.NewHandler(config.Configuration{Server: config.Server{DatabaseDownloadEnabled: true}}).
+				WithVMService(vmSrv).
+				WithInspectorService(mockInspector)
+			req := httptest.NewRequest(http.MethodGet, "/vms?explain=true&byExpression=memory%20%3E%3D%208GB&sort=name:asc&page=2&pageSize=2", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response v1.VmQueryExplain
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Sql).To(ContainSubstring("WHERE"))
+			Expect(response.Sql).To(ContainSubstring(`v."Memory" >= ?`))
+			Expect(response.Sql).To(ContainSubstring("ORDER BY"))
+			Expect(response.Sql).To(ContainSubstring("LIMIT 2"))
+			Expect(response.Sql).To(ContainSubstring("OFFSET 2"))
+			Expect(response.Args).To(ContainElement(float64(8192)))
+
+			// The returned query selects the page the list returns.
+			rows, err := db.QueryContext(ctx, response.Sql, response.Args...)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = rows.Close() }()
+			var explained []string
+			for rows.Next() {
+				cols, err := rows.Columns()
+				Expect(err).NotTo(HaveOccurred())
+				values := make([]any, len(cols))
+				ptrs := make([]any, len(cols))
+				for i := range values {
+					ptrs[i] = &values[i]
+				}
+				Expect(rows.Scan(ptrs...)).To(Succeed())
+				explained = append(explained, values[0].(string))
+			}
+			Expect(rows.Err()).NotTo(HaveOccurred())
+
+			req = httptest.NewRequest(http.MethodGet, "/vms?byExpression=memory%20%3E%3D%208GB&sort=name:asc&page=2&pageSize=2", nil)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			var list v1.VirtualMachineListResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &list)).To(Succeed())
+			ids := make([]string, 0, len(list.Vms))
+			for _, vm := range list.Vms {
+				ids = append(ids, vm.Id)
+			}
+			Expect(explained).NotTo(BeEmpty())
+			Expect(explained).To(Equal(ids))
+		})
+	})
+
+	Context("GetVMsOsDistribution with real data", func() {
+		getDistribution := func(query string) (int, map[string]int) {
+			req := httptest.NewRequest(http.MethodGet, "/vms/os-distribution"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var distribution map[string]int
+			if w.Code == http.StatusOK {
+				Expect(json.Unmarshal(w.Body.Bytes(), &distribution)).To(Succeed())
+			}
+			return w.Code, distribution
+		}
+
+		// Given the fixture VMs with one of them reconfigured as Windows
+		// When we request the OS distribution
+		// Then each configured OS should be counted
+		It("should count VMs per configured OS", func() {
+			// Arrange
+			_, err := db.ExecContext(ctx, `UPDATE vinfo SET "OS according to the configuration file" = 'Microsoft Windows Server 2019 (64-bit)' WHERE "VM ID" = 'vm-010'`)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			code, distribution := getDistribution("")
+
+			// Assert
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(distribution).To(Equal(map[string]int{
+				"Red Hat Enterprise Linux 8":             2,
+				"Red Hat Enterprise Linux 9":             2,
+				"CentOS 8":                               2,
+				"Ubuntu 22.04":                           1,
+				"Fedora 38":                              2,
+				"Microsoft Windows Server 2019 (64-bit)": 1,
+			}))
+		})
+
+		// Given VMs in several clusters
+		// When we request the OS distribution filtered by cluster
+		// Then only the matching VMs should be counted
+		It("should honor byExpression", func() {
+			// Act
+			code, distribution := getDistribution("?byExpression=" + url.QueryEscape("cluster = 'production'"))
+
+			// Assert
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(distribution).To(Equal(map[string]int{
+				"Red Hat Enterprise Linux 8": 2,
+				"Red Hat Enterprise Linux 9": 2,
+			}))
+		})
+
+		// Given only vm-007 has a Critical concern
+		// When we request the OS distribution with hasCritical
+		// Then only its OS should be counted
+		It("should honor hasCritical", func() {
+			// Act
+			code, distribution := getDistribution("?hasCritical=true")
+
+			// Assert
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(distribution).To(Equal(map[string]int{"Ubuntu 22.04": 1}))
+		})
+
+		// Given a VM with no guest OS reported
+		// When we request the OS distribution
+		// Then it should be counted as Unknown OS
+		It("should count VMs without a guest OS as Unknown OS", func() {
+			// Arrange
+			_, err := db.ExecContext(ctx, `UPDATE vinfo SET "OS according to the configuration file" = '' WHERE "VM ID" = 'vm-010'`)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			code, distribution := getDistribution("")
+
+			// Assert
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(distribution).To(HaveKeyWithValue("Unknown OS", 1))
+		})
+
+		// Given an invalid filter expression
+		// When we request the OS distribution
+		// Then it should return 400
+		It("should return 400 for an invalid expression", func() {
+			// Act
+			code, _ := getDistribution("?byExpression=" + url.QueryEscape("cluster = "))
+
+			// Assert
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("GetVMsGroupBy with real data", func() {
+		groupBy := func(query string) (int, map[string]int) {
+			req := httptest.NewRequest(http.MethodGet, "/vms/group-by"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var counts map[string]int
+			if w.Code == http.StatusOK {
+				Expect(json.Unmarshal(w.Body.Bytes(), &counts)).To(Succeed())
+			}
+			return w.Code, counts
+		}
+
+		// Given the fixture VMs in three clusters
+		// When we group them by cluster
+		// Then each cluster should be counted
+		It("should count VMs per cluster", func() {
+			// Act
+			code, counts := groupBy("?field=cluster")
+
+			// Assert
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(counts).To(Equal(map[string]int{
+				"production":  4,
+				"staging":     3,
+				"development": 3,
+			}))
+		})
+
+		// Given the fixture VMs powered on, off or suspended
+		// When we group them by power state
+		// Then each power state should be counted
+		It("should count VMs per power state", func() {
+			// Act
+			code, counts := groupBy("?field=vCenterState")
+
+			// Assert
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(counts).To(Equal(map[string]int{
+				"poweredOn":  7,
+				"poweredOff": 2,
+				"suspended":  1,
+			}))
+		})
+
+		// Given VMs in several clusters
+		// When we group them by power state, filtered by cluster
+		// Then only the matching VMs should be counted
+		It("should honor byExpression", func() {
+			// Act
+			code, counts := groupBy("?field=vCenterState&byExpression=" + url.QueryEscape("cluster = 'production'"))
+
+			// Assert
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(counts).To(Equal(map[string]int{
+				"poweredOn":  3,
+				"poweredOff": 1,
+			}))
+		})
+
+		// Given a VM without a cluster
+		// When we group the VMs by cluster
+		// Then it should be counted under the empty string
+		It("should count VMs without a value under the empty string", func() {
+			// Arrange
+			_, err := db.ExecContext(ctx, `UPDATE vinfo SET "Cluster" = NULL WHERE "VM ID" = 'vm-010'`)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			code, counts := groupBy("?field=cluster")
+
+			// Assert
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(counts).To(HaveKeyWithValue("", 1))
+			Expect(counts).To(HaveKeyWithValue("development", 2))
+		})
+
+		// Given a field outside the groupable ones
+		// When we group the VMs by it
+		// Then it should return 400 naming the accepted fields
+		It("should return 400 for an unknown field", func() {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, `/vms/group-by?field=`+url.QueryEscape(`"VM ID"`), nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(w.Body.String()).To(ContainSubstring("cluster, datacenter, host, os, vCenterState"))
+		})
+
+		// Given no field
+		// When we request the grouped counts
+		// Then it should return 400
+		It("should return 400 without a field", func() {
+			// Act
+			code, _ := groupBy("")
+
+			// Assert
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
 	})
 
 	Context("GetVM with real data", func() {
@@ -903,4 +2250,52 @@ var _ = Describe("VMs Handlers Integration", func() {
 			}
 		})
 	})
+	Context("BatchGetVMs with real data", func() {
+		batch := func(body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/vms/batch", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		It("should return all requested VMs in request order", func() {
+			w := batch(`{"ids": ["vm-007", "vm-003"]}`)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var response v1.VmBatchResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.NotFound).To(BeEmpty())
+			Expect(response.Vms).To(HaveLen(2))
+			Expect(response.Vms[0].Id).To(Equal("vm-007"))
+			Expect(*response.Vms[0].Issues).To(HaveLen(3))
+			Expect(response.Vms[1].Id).To(Equal("vm-003"))
+			Expect(response.Vms[1].Name).To(Equal("db-server-1"))
+			Expect(response.Vms[1].Disks).To(HaveLen(2))
+			Expect(response.Vms[1].Nics).To(HaveLen(2))
+		})
+
+		It("should report unknown ids in notFound", func() {
+			w := batch(`{"ids": ["vm-001", "vm-missing", "vm-001", "vm-gone"]}`)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var response v1.VmBatchResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Vms).To(HaveLen(1))
+			Expect(response.Vms[0].Id).To(Equal("vm-001"))
+			Expect(response.NotFound).To(Equal([]string{"vm-missing", "vm-gone"}))
+		})
+
+		It("should return 400 for empty ids", func() {
+			w := batch(`{"ids": []}`)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+			var response map[string]any
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["error"]).To(Equal("ids must not be empty"))
+		})
+	})
 })
@@ -1,6 +1,9 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type AgentMode string
 
@@ -31,6 +34,9 @@ type ConsoleStatus struct {
 	Current ConsoleStatusType
 	Target  ConsoleStatusType
 	Error   error
+	// LastContactAt is when a request to the console last succeeded; zero if
+	// none has since startup.
+	LastContactAt time.Time
 }
 
 type AgentStatus struct {
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // CollectorStateType represents the current state of the collector.
 type CollectorStateType string
 
@@ -43,10 +45,38 @@ func (c CollectorStateType) ToV1() CollectorStateType {
 type CollectorStatus struct {
 	State CollectorStateType
 	Error error
+	// Phases holds the timings of the last collection, set by
+	// CollectorService.GetStatus only.
+	Phases []CollectorPhase
+	// Warnings lists the non-fatal issues of the last successful collection
+	// (e.g. a VM that could not be validated), set when State is collected.
+	Warnings []string
+	// CollectedAt is when the stored inventory was collected, set when State
+	// is collected.
+	CollectedAt time.Time
+}
+
+// CollectorPhase is how long one phase (work unit) of a collection took. The
+// phase is named after the collector state it runs in.
+type CollectorPhase struct {
+	State    CollectorStateType
+	Duration time.Duration
 }
 
 // CollectorResult is the shared result struct threaded through collector work units.
 type CollectorResult struct {
-	SQLitePath string
-	Inventory  []byte
+	SQLitePath  string
+	Snapshots   []VirtualMachineSnapshot
+	CreateDates map[string]time.Time
+	Inventory   []byte
+	// Warnings accumulates the non-fatal issues hit by the units so far.
+	Warnings []string
+}
+
+// CollectionHistoryEvent is a terminal collection outcome recorded to the
+// local history spool while the agent is disconnected.
+type CollectionHistoryEvent struct {
+	Time  time.Time
+	State CollectorStateType
+	Error string
 }
@@ -1,6 +1,27 @@
 package models
 
+import "time"
+
 // Configuration represents agent configuration stored in the database.
 type Configuration struct {
-	AgentMode AgentMode
+	AgentMode   AgentMode
+	Maintenance bool // when set, mutating operations are rejected
+	Console     ConsoleSettings
+}
+
+// ConsoleSettings are the console settings that can be changed at runtime.
+// A nil field is not set: it keeps the value the agent was started with, or,
+// in a change, is left as it is.
+type ConsoleSettings struct {
+	UpdateInterval      *time.Duration
+	LegacyStatusEnabled *bool
+}
+
+// ConsoleSettingsChange is a change to the console settings. The fields set in
+// ConsoleSettings are stored; a Reset field clears the stored value, so the
+// setting goes back to the flag the agent was started with.
+type ConsoleSettingsChange struct {
+	ConsoleSettings
+	ResetUpdateInterval      bool
+	ResetLegacyStatusEnabled bool
 }
@@ -33,6 +33,24 @@ func (i InspectionState) Value() string {
 	return string(i)
 }
 
+// InspectionCancelOutcome is what canceling one VM's inspection did.
+type InspectionCancelOutcome string
+
+const (
+	// InspectionCancelOutcomeCanceled - the VM was pending or running and is now canceled
+	InspectionCancelOutcomeCanceled InspectionCancelOutcome = "canceled"
+	// InspectionCancelOutcomeAlreadyCompleted - the VM's inspection had already ended
+	InspectionCancelOutcomeAlreadyCompleted InspectionCancelOutcome = "already-completed"
+	// InspectionCancelOutcomeNotFound - the VM is not part of the current inspection
+	InspectionCancelOutcomeNotFound InspectionCancelOutcome = "not-found"
+)
+
+// InspectionCancelResult is the outcome of canceling one VM's inspection.
+type InspectionCancelResult struct {
+	VMID    string
+	Outcome InspectionCancelOutcome
+}
+
 // InspectionStatus holds the current Inspection state for a vm.
 type InspectionStatus struct {
 	State InspectionState
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // InspectorState represents the current state of the Inspector.
 type InspectorState string
 
@@ -10,6 +12,8 @@ const (
 	InspectorStateInitiating InspectorState = "Initiating"
 	// InspectorStateRunning - running inspections on VMs
 	InspectorStateRunning InspectorState = "running"
+	// InspectorStatePaused - running, but no new VM inspection starts
+	InspectorStatePaused InspectorState = "paused"
 	// InspectorStateCanceled - inspection canceled
 	InspectorStateCanceled InspectorState = "canceled"
 	// InspectorStateCompleted - Inspection complete
@@ -23,4 +27,10 @@ type InspectorStatus struct {
 	State       InspectorState
 	Credentials *Credentials
 	Error       error
+	// AverageVmDuration is the mean inspection time of the VMs completed in the
+	// current (or last) run. Zero until the first VM completes.
+	AverageVmDuration time.Duration
+	// EstimatedCompletion is when the remaining VMs are expected to finish.
+	// Nil unless the inspector is running and at least one VM has completed.
+	EstimatedCompletion *time.Time
 }
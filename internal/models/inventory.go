@@ -25,3 +25,96 @@ type Inventory struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// VMSnapshot holds the per-VM resources compared between two collections.
+type VMSnapshot struct {
+	ID       string
+	Name     string
+	CPUs     int32
+	MemoryMB int32
+	DiskMB   int64 // sum of disk capacities
+}
+
+// InventorySnapshot is the per-VM state of one successful collection.
+type InventorySnapshot struct {
+	TakenAt time.Time
+	VMs     []VMSnapshot
+}
+
+// FieldDelta is the previous and current value of a changed field.
+type FieldDelta struct {
+	Previous int64
+	Current  int64
+}
+
+// VMDelta lists the resource fields that changed for a VM present in both
+// collections. Unchanged fields are nil.
+type VMDelta struct {
+	ID       string
+	Name     string
+	CPUs     *FieldDelta
+	MemoryMB *FieldDelta
+	DiskMB   *FieldDelta
+}
+
+// InventoryDiff is what changed between the previous and the current
+// collection. VM IDs are sorted.
+type InventoryDiff struct {
+	PreviousCollectedAt time.Time
+	CurrentCollectedAt  time.Time
+	Added               []string
+	Removed             []string
+	Changed             []VMDelta
+}
+
+// VMSize is what the inventory's tier distributions bucket a VM by.
+type VMSize struct {
+	CPUs     int
+	MemoryMB int
+	DiskTB   float64 // sum of disk capacities
+}
+
+// VMConcerns are the concerns of one VM, as the inventory's migration issues
+// count them.
+type VMConcerns struct {
+	VMID     string
+	Concerns []VMConcern
+}
+
+// VMConcern is one concern raised for a VM.
+type VMConcern struct {
+	ID         string
+	Label      string
+	Category   string
+	Assessment string
+}
+
+// InventoryMetrics are the vCenter-wide totals of a stored inventory. The
+// overcommitment ratios are nil when the inventory has no host data.
+type InventoryMetrics struct {
+	UpdatedAt            time.Time
+	TotalVMs             int
+	PoweredOnVMs         int
+	TotalVCPUs           int
+	TotalRAMGB           int
+	TotalDiskTB          float64
+	TotalHosts           int
+	TotalDatacenters     int
+	CPUOverCommitment    *float64
+	MemoryOverCommitment *float64
+}
+
+// ClusterMetrics are the totals of one cluster of a stored inventory, keyed
+// by the cluster ID the inventory uses. The overcommitment ratios are nil
+// when the cluster has no host data.
+type ClusterMetrics struct {
+	ID                   string
+	TotalVMs             int
+	PoweredOnVMs         int
+	TotalVCPUs           int
+	TotalRAMGB           int
+	TotalDiskTB          float64
+	TotalHosts           int
+	CPUOverCommitment    *float64
+	MemoryOverCommitment *float64
+}
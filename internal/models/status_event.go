@@ -0,0 +1,17 @@
+package models
+
+// StatusEventSource names the service a StatusEvent comes from.
+type StatusEventSource string
+
+const (
+	StatusEventCollector StatusEventSource = "collector"
+	StatusEventInspector StatusEventSource = "inspector"
+	StatusEventAgent     StatusEventSource = "agent"
+)
+
+// StatusEvent is a state change of a long-running service. Status holds the
+// new status of the source: CollectorStatus, InspectorStatus or ConsoleStatus.
+type StatusEvent struct {
+	Source StatusEventSource
+	Status any
+}
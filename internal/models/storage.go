@@ -0,0 +1,15 @@
+package models
+
+// StorageUsage describes disk usage of the agent data folder.
+type StorageUsage struct {
+	// Path is the data folder.
+	Path string
+	// TotalBytes and FreeBytes describe the filesystem holding the data folder.
+	// FreeBytes is the space available to the agent, excluding blocks reserved for root.
+	TotalBytes uint64
+	FreeBytes  uint64
+	// DatabaseBytes is the size of the DuckDB file and its write-ahead log.
+	DatabaseBytes int64
+	// VddkBytes is the size of the extracted VDDK, zero if none was uploaded.
+	VddkBytes int64
+}
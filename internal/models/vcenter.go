@@ -5,4 +5,14 @@ type Credentials struct {
 	URL      string
 	Username string
 	Password string
+	// Insecure skips TLS certificate verification of the vCenter.
+	Insecure bool
+}
+
+// VCenterInfo describes the vCenter reached by a connectivity test.
+type VCenterInfo struct {
+	Product    string
+	Version    string
+	Build      string
+	APIVersion string
 }
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// VMView is a saved GET /vms query: a filter expression, sort and page size
+// stored under a name and applied with ?view=<name>. Zero values mean the
+// view does not set that parameter.
+type VMView struct {
+	Name       string
+	Expression string
+	Sort       []string
+	PageSize   int
+	CreatedAt  time.Time
+}
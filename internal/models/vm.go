@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"time"
+)
+
 // VirtualMachineSummary represents a lightweight VM record for list views.
 type VirtualMachineSummary struct {
 	ID                     string
@@ -9,11 +14,15 @@ type VirtualMachineSummary struct {
 	Datacenter             string
 	Memory                 int32 // MB
 	DiskSize               int64 // MB (stored as MiB in DB, treated as MB)
-	IssueCount             int
+	IssueCount             int   // total number of concerns, regardless of category
+	CriticalCount          int
+	WarningCount           int
 	IsMigratable           bool
 	IsTemplate             bool
 	InspectionStatus       InspectionStatus
 	InspectionConcernCount int
+	SnapshotCount          int
+	DiskTypes              map[string]float64 // GB per datastore type
 	Tags                   []string
 	UtilizationCpuP95      *float64 // CPU utilization at p95 (%); nil when no utilization data
 	UtilizationMemP95      *float64 // Memory utilization at p95 (%); nil when no utilization data
@@ -21,6 +30,26 @@ type VirtualMachineSummary struct {
 	UtilizationConfidence  *float64 // Data confidence (%); nil when no utilization data
 }
 
+// Readiness is a VM's migration readiness, derived from the category of its
+// concerns.
+type Readiness string
+
+const (
+	ReadinessMigratable Readiness = "migratable" // no Critical or Warning concern
+	ReadinessWarning    Readiness = "warning"    // Warning concerns, but no Critical one
+	ReadinessBlocked    Readiness = "blocked"    // at least one Critical concern
+)
+
+// ParseReadiness returns the Readiness named s.
+func ParseReadiness(s string) (Readiness, error) {
+	switch r := Readiness(s); r {
+	case ReadinessMigratable, ReadinessWarning, ReadinessBlocked:
+		return r, nil
+	default:
+		return "", fmt.Errorf("invalid readiness: %s", s)
+	}
+}
+
 type VM struct {
 	ID              string
 	Name            string
@@ -59,13 +88,22 @@ type VM struct {
 	Devices       []Device
 	GuestNetworks []GuestNetwork
 
-	Issues []Issue
+	Issues    []Issue
+	Snapshots []VirtualMachineSnapshot
+	DiskTypes map[string]float64 // GB per datastore type
 
 	InspectionState    string
 	InspectionError    string
 	InspectionConcerns []VmInspectionConcern
 }
 
+// VirtualMachineSnapshot is one snapshot of a VM's snapshot tree in vCenter.
+type VirtualMachineSnapshot struct {
+	VMID      string
+	Name      string
+	CreatedAt time.Time
+}
+
 type Issue struct {
 	ID          string
 	Label       string
@@ -73,14 +111,27 @@ type Issue struct {
 	Category    string
 }
 
+// ConcernDefinition is a concern the loaded OPA policies can raise. Label and
+// Assessment keep the placeholders of the policy's sprintf format where the
+// policy builds them from the VM.
+type ConcernDefinition struct {
+	ID         string
+	Category   string
+	Label      string
+	Assessment string
+}
+
 type Disk struct {
-	Key      int32
-	File     string
-	Capacity int64
-	Shared   bool
-	RDM      bool
-	Bus      string
-	Mode     string
+	Key       int32
+	File      string
+	Capacity  int64
+	Shared    bool
+	RDM       bool
+	Bus       string
+	Mode      string
+	Thin      bool
+	Datastore string // name of the backing datastore
+	DiskType  string // type of the backing datastore (e.g. VMFS, NFS, vSAN)
 }
 
 type NIC struct {
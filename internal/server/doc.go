@@ -20,8 +20,10 @@
 //	├───────────────────────────────────────────────────────────────┤
 //	│                       Middleware Stack                        │
 //	│  ┌─────────────────────────────────────────────────────────┐  │
+//	│  │  CORS (engine-wide, allowed origins and preflight)      │  │
 //	│  │  Logger (request/response logging)                      │  │
 //	│  │  Recovery (panic recovery with zap logging)             │  │
+//	│  │  Timeout (per-request deadline, 503 when exceeded)      │  │
 //	│  └─────────────────────────────────────────────────────────┘  │
 //	├───────────────────────────────────────────────────────────────┤
 //	│                       Router (/api/v1)                        │
@@ -44,6 +46,11 @@
 //   - SPA fallback: non-API routes serve index.html
 //   - API 404s return JSON error response
 //
+// In both modes a known path called with a method it does not serve answers
+// 405 Method Not Allowed, with an Allow header listing the path's methods and
+// the API error body (v1 handlers' MethodNotAllowed, installed as the engine's
+// NoMethod handler), instead of gin's plain 404.
+//
 // # Server Lifecycle
 //
 // Creation:
@@ -69,7 +76,15 @@
 //
 // # Middleware
 //
-// The server applies two middleware to all API routes:
+// The engine runs the CORS middleware before routing, so it also sees
+// preflight requests, which match no route:
+//
+// CORS Middleware (middlewares.CORS):
+//   - Does nothing unless Server.AllowedOrigins is set (--server-allowed-origins)
+//   - Echoes an allowed Origin in Access-Control-Allow-Origin; "*" allows any
+//   - Answers preflight OPTIONS requests with 204, or 403 for other origins
+//
+// The server then applies three middleware to all API routes:
 //
 // Logger Middleware (middlewares.Logger):
 //   - Logs request start: method, path, query, IP, user-agent, timestamp
@@ -82,6 +97,15 @@
 //   - Logs panic details with stack trace
 //   - Returns 500 Internal Server Error
 //
+// Timeout Middleware (middlewares.Timeout):
+//   - Bounds the request context by Server.RequestTimeout (0 disables it)
+//   - The context's cause is a RequestTimeoutError once the deadline passes
+//   - Returns 503 Service Unavailable if the handler wrote nothing by then;
+//     handlers answering through respondError map the timeout to 503 too
+//   - Streaming routes (events, collector long-poll, export, VDDK upload,
+//     GET /vms with Accept: application/x-ndjson) are exempt, see
+//     streamingRoutes
+//
 // # Static File Serving (Production Only)
 //
 // In production mode, the server serves:
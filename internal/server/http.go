@@ -18,6 +18,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	v1Handlers "github.com/kubev2v/assisted-migration-agent/internal/handlers/v1"
 	"github.com/kubev2v/assisted-migration-agent/internal/server/middlewares"
 	"github.com/kubev2v/assisted-migration-agent/pkg/certificates"
 )
@@ -27,6 +28,20 @@ const (
 	DevServer        string = "dev"
 )
 
+// streamingRoutes run for as long as the client keeps them open or move large
+// bodies, so they are exempt from the per-request timeout. Paths are relative
+// to the API version group; a trailing media type limits the exemption to
+// requests that negotiate it.
+var streamingRoutes = []string{
+	"GET /events",
+	"GET /vms application/x-ndjson",
+	"GET /collector", // long-polls with wait
+	"GET /export",
+	"GET /inventory/export",
+	"PUT /inspector/vddk",
+	"GET /debug/db",
+}
+
 type Server struct {
 	srv *http.Server
 }
@@ -38,6 +53,13 @@ func NewServer(cfg *config.Configuration, registerHandlerFn map[string]func(rout
 	}
 	engine := gin.New()
 	engine.MaxMultipartMemory = 64 << 20 // max 64Mb
+	// Installed on the engine so preflight requests, which match no route,
+	// still get an answer.
+	engine.Use(middlewares.CORS(cfg.Server.AllowedOrigins))
+	// A known path with the wrong method gets 405 and an Allow header
+	// instead of gin's plain 404.
+	engine.HandleMethodNotAllowed = true
+	engine.NoMethod(v1Handlers.MethodNotAllowed)
 
 	srv := &http.Server{
 		Addr:    fmt.Sprintf("0.0.0.0:%d", cfg.Server.HTTPPort),
@@ -77,9 +99,17 @@ func NewServer(cfg *config.Configuration, registerHandlerFn map[string]func(rout
 	for apiVersion, handlersFn := range registerHandlerFn {
 		router := engine.Group(apiVersion)
 
+		exempt := make([]string, 0, len(streamingRoutes))
+		for _, route := range streamingRoutes {
+			method, rest, _ := strings.Cut(route, " ")
+			p, mediaType, _ := strings.Cut(rest, " ")
+			exempt = append(exempt, strings.TrimSpace(method+" "+path.Join(apiVersion, p)+" "+mediaType))
+		}
+
 		router.Use(
 			middlewares.Logger(),
 			ginzap.RecoveryWithZap(zap.S().Desugar(), true),
+			middlewares.Timeout(cfg.Server.RequestTimeout, exempt...),
 		)
 
 		handlersFn(router)
@@ -87,6 +87,122 @@ var _ = Describe("HTTP Server", func() {
 			Expect(resp.StatusCode).To(Equal(200))
 			_ = resp.Body.Close()
 		})
+
+		// Given a dev server with a GET-only route
+		// When the route is called with POST
+		// Then it should answer 405 with GET in the Allow header
+		It("answers 405 for a wrong method on a known route", func() {
+			var err error
+			srv, err = server.NewServer(cfg, registerHandlerFn)
+			Expect(err).ToNot(HaveOccurred())
+
+			go func() {
+				_ = srv.Start(context.TODO())
+			}()
+			time.Sleep(100 * time.Millisecond)
+
+			resp, err := http.Post(fmt.Sprintf("http://localhost:%d/api/v1/health", cfg.Server.HTTPPort), "application/json", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+			Expect(resp.Header.Get("Allow")).To(Equal(http.MethodGet))
+			_ = resp.Body.Close()
+		})
+
+		It("times out slow API requests but not the event stream", func() {
+			cfg.Server.RequestTimeout = 50 * time.Millisecond
+			slow := func(c *gin.Context) {
+				select {
+				case <-c.Request.Context().Done():
+				case <-time.After(200 * time.Millisecond):
+					c.Status(http.StatusOK)
+				}
+			}
+			registerHandlerFn["/api/v1"] = func(router *gin.RouterGroup) {
+				router.GET("/vms", slow)
+				router.GET("/events", slow)
+			}
+
+			var err error
+			srv, err = server.NewServer(cfg, registerHandlerFn)
+			Expect(err).ToNot(HaveOccurred())
+
+			go func() {
+				_ = srv.Start(context.TODO())
+			}()
+			time.Sleep(100 * time.Millisecond)
+
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d/api/v1/vms", cfg.Server.HTTPPort))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			_ = resp.Body.Close()
+
+			resp, err = http.Get(fmt.Sprintf("http://localhost:%d/api/v1/events", cfg.Server.HTTPPort))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			_ = resp.Body.Close()
+		})
+
+		// Given a short request timeout and a VM list slower than it
+		// When the list is requested as NDJSON and as JSON
+		// Then only the JSON request should be cut off with 503
+		It("does not time out the NDJSON VM stream", func() {
+			cfg.Server.RequestTimeout = 50 * time.Millisecond
+			registerHandlerFn["/api/v1"] = func(router *gin.RouterGroup) {
+				router.GET("/vms", func(c *gin.Context) {
+					select {
+					case <-c.Request.Context().Done():
+					case <-time.After(200 * time.Millisecond):
+						c.Status(http.StatusOK)
+					}
+				})
+			}
+
+			var err error
+			srv, err = server.NewServer(cfg, registerHandlerFn)
+			Expect(err).ToNot(HaveOccurred())
+
+			go func() {
+				_ = srv.Start(context.TODO())
+			}()
+			time.Sleep(100 * time.Millisecond)
+
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:%d/api/v1/vms", cfg.Server.HTTPPort), nil)
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Accept", "application/x-ndjson")
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			_ = resp.Body.Close()
+
+			resp, err = http.Get(fmt.Sprintf("http://localhost:%d/api/v1/vms", cfg.Server.HTTPPort))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			_ = resp.Body.Close()
+		})
+
+		It("answers CORS preflight requests for allowed origins", func() {
+			cfg.Server.AllowedOrigins = []string{"https://ui.example.com"}
+
+			var err error
+			srv, err = server.NewServer(cfg, registerHandlerFn)
+			Expect(err).ToNot(HaveOccurred())
+
+			go func() {
+				_ = srv.Start(context.TODO())
+			}()
+			time.Sleep(100 * time.Millisecond)
+
+			req, err := http.NewRequest(http.MethodOptions, fmt.Sprintf("http://localhost:%d/api/v1/health", cfg.Server.HTTPPort), nil)
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Origin", "https://ui.example.com")
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+			Expect(resp.Header.Get("Access-Control-Allow-Origin")).To(Equal("https://ui.example.com"))
+			_ = resp.Body.Close()
+		})
 	})
 
 	Context("production server mode", func() {
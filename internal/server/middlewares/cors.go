@@ -0,0 +1,63 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	corsAllowMethods  = "GET, POST, PUT, PATCH, DELETE"
+	corsAllowHeaders  = "Accept, Content-Type"
+	corsExposeHeaders = "Content-Disposition, Link, X-Page-Size, X-Total-Count"
+	corsMaxAge        = "600"
+)
+
+// CORS returns a gin middleware that lets pages served from the listed origins
+// (e.g. "https://ui.example.com", or "*" for any) call the API. A request from
+// an allowed origin gets its origin echoed back in Access-Control-Allow-Origin;
+// a preflight OPTIONS request from it is answered 204 with the allowed methods
+// and headers, without reaching the router. A preflight from any other origin
+// is answered 403, and other requests from it run without CORS headers, which
+// the browser then blocks. With no origins the middleware does nothing and
+// only same-origin pages can read the responses.
+func CORS(allowedOrigins []string) gin.HandlerFunc {
+	anyOrigin := slices.Contains(allowedOrigins, "*")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if len(allowedOrigins) == 0 || origin == "" {
+			c.Next()
+			return
+		}
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		allowed := anyOrigin || slices.ContainsFunc(allowedOrigins, func(o string) bool {
+			return strings.EqualFold(strings.TrimSuffix(o, "/"), origin)
+		})
+
+		c.Writer.Header().Add("Vary", "Origin")
+		if !allowed {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Origin", origin)
+		if !preflight {
+			c.Header("Access-Control-Expose-Headers", corsExposeHeaders)
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Methods", corsAllowMethods)
+		c.Header("Access-Control-Allow-Headers", corsAllowHeaders)
+		c.Header("Access-Control-Max-Age", corsMaxAge)
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,145 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/server/middlewares"
+)
+
+var _ = Describe("CORS", func() {
+	var (
+		router *gin.Engine
+		served int
+	)
+
+	setup := func(origins ...string) {
+		router = gin.New()
+		router.Use(middlewares.CORS(origins))
+		router.GET("/api/v1/vms", func(c *gin.Context) {
+			served++
+			c.JSON(http.StatusOK, gin.H{"vms": []string{}})
+		})
+	}
+
+	serve := func(method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/vms", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	preflight := map[string]string{"Access-Control-Request-Method": http.MethodPatch}
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		served = 0
+	})
+
+	// Given an allowed origin
+	// When a page from it calls the API
+	// Then the response should allow that origin and expose the paging headers
+	It("should allow a listed origin", func() {
+		// Arrange
+		setup("https://ui.example.com")
+
+		// Act
+		w := serve(http.MethodGet, "https://ui.example.com", nil)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(served).To(Equal(1))
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://ui.example.com"))
+		Expect(w.Header().Get("Access-Control-Expose-Headers")).To(ContainSubstring("X-Total-Count"))
+		Expect(w.Header().Values("Vary")).To(ContainElement("Origin"))
+	})
+
+	// Given a list that does not include the caller's origin
+	// When a page from it calls the API
+	// Then the request should run without CORS headers, so the browser blocks it
+	It("should not allow an unlisted origin", func() {
+		// Arrange
+		setup("https://ui.example.com")
+
+		// Act
+		w := serve(http.MethodGet, "https://evil.example.com", nil)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+	})
+
+	// Given an allowed origin
+	// When the browser sends a preflight request
+	// Then it should be answered 204 with the allowed methods and headers,
+	// without reaching the handler
+	It("should answer preflight requests from a listed origin", func() {
+		// Arrange
+		setup("https://ui.example.com")
+
+		// Act
+		w := serve(http.MethodOptions, "https://ui.example.com", preflight)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		Expect(served).To(BeZero())
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://ui.example.com"))
+		Expect(w.Header().Get("Access-Control-Allow-Methods")).To(Equal("GET, POST, PUT, PATCH, DELETE"))
+		Expect(w.Header().Get("Access-Control-Allow-Headers")).To(ContainSubstring("Content-Type"))
+		Expect(w.Header().Get("Access-Control-Max-Age")).NotTo(BeEmpty())
+	})
+
+	// Given a list that does not include the caller's origin
+	// When the browser sends a preflight request
+	// Then it should be rejected with 403
+	It("should reject preflight requests from an unlisted origin", func() {
+		// Arrange
+		setup("https://ui.example.com")
+
+		// Act
+		w := serve(http.MethodOptions, "https://evil.example.com", preflight)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusForbidden))
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+	})
+
+	// Given the wildcard origin
+	// When a page from any origin calls the API
+	// Then its origin should be allowed
+	It("should allow any origin with *", func() {
+		// Arrange
+		setup("*")
+
+		// Act
+		w := serve(http.MethodGet, "http://localhost:3000", nil)
+
+		// Assert
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(Equal("http://localhost:3000"))
+	})
+
+	// Given no allowed origins
+	// When a cross-origin page sends a preflight request
+	// Then the middleware should leave it to the router
+	It("should do nothing without allowed origins", func() {
+		// Arrange
+		setup()
+
+		// Act
+		w := serve(http.MethodOptions, "https://ui.example.com", preflight)
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+		Expect(w.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+	})
+})
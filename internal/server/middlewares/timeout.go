@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+// Timeout returns a gin middleware that bounds each request by timeout. The request
+// context gets a deadline whose cause is a RequestTimeoutError, so anything reading
+// from it (store queries, vCenter calls) stops once the deadline passes. When the
+// handler returns without writing a response after the deadline, the middleware
+// answers 503 itself.
+//
+// Routes listed in exempt, as "METHOD /full/path" (e.g. "GET /api/v1/events"), are
+// left without a deadline; they are meant for streams and long-polls. An entry may
+// add a media type, as in "GET /api/v1/vms application/x-ndjson", to exempt the
+// route only when the client negotiates that type over JSON. A non-positive
+// timeout disables the middleware.
+func Timeout(timeout time.Duration, exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 || isExempt(c, exempt) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeoutCause(c.Request.Context(), timeout, srvErrors.NewRequestTimeoutError(timeout))
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if cause := context.Cause(ctx); srvErrors.IsRequestTimeoutError(cause) && !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": cause.Error()})
+		}
+	}
+}
+
+// isExempt reports whether the request matches one of the exempt entries.
+func isExempt(c *gin.Context, exempt []string) bool {
+	for _, entry := range exempt {
+		fields := strings.Fields(entry)
+		if len(fields) < 2 || fields[0] != c.Request.Method || fields[1] != c.FullPath() {
+			continue
+		}
+		if len(fields) == 2 || c.NegotiateFormat(binding.MIMEJSON, fields[2]) == fields[2] {
+			return true
+		}
+	}
+	return false
+}
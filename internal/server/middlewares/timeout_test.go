@@ -0,0 +1,182 @@
+package middlewares_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/server/middlewares"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+var _ = Describe("Timeout", func() {
+	var router *gin.Engine
+
+	// slow blocks until the request context is done and reports nothing,
+	// like a handler stuck on a query that honors cancellation.
+	slow := func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	}
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+	})
+
+	// Given a handler slower than the timeout
+	// When it returns without writing a response
+	// Then the middleware should answer 503 with the timeout error
+	It("should answer 503 when a slow handler runs past the timeout", func() {
+		// Arrange
+		router.Use(middlewares.Timeout(50 * time.Millisecond))
+		router.GET("/api/v1/vms", slow)
+
+		// Act
+		start := time.Now()
+		w := serve(http.MethodGet, "/api/v1/vms")
+
+		// Assert
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+		var body map[string]any
+		Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+		Expect(body).To(Equal(map[string]any{"error": "request timed out after 50ms"}))
+	})
+
+	// Given a slow handler
+	// When its context deadline passes
+	// Then the cause should be a RequestTimeoutError
+	It("should set a RequestTimeoutError as the context cause", func() {
+		// Arrange
+		var cause error
+		router.Use(middlewares.Timeout(20 * time.Millisecond))
+		router.GET("/api/v1/vms", func(c *gin.Context) {
+			<-c.Request.Context().Done()
+			cause = context.Cause(c.Request.Context())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed"})
+		})
+
+		// Act
+		w := serve(http.MethodGet, "/api/v1/vms")
+
+		// Assert
+		Expect(srvErrors.IsRequestTimeoutError(cause)).To(BeTrue())
+		Expect(w.Code).To(Equal(http.StatusInternalServerError))
+	})
+
+	// Given a handler faster than the timeout
+	// When it responds
+	// Then its response should pass through untouched
+	It("should not affect a fast handler", func() {
+		// Arrange
+		router.Use(middlewares.Timeout(time.Second))
+		router.GET("/api/v1/vms", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"vms": []string{}})
+		})
+
+		// Act
+		w := serve(http.MethodGet, "/api/v1/vms")
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(Equal(`{"vms":[]}`))
+	})
+
+	// Given an exempt streaming route
+	// When it is served
+	// Then its context should carry no deadline
+	It("should leave exempt routes without a deadline", func() {
+		// Arrange
+		var hasDeadline bool
+		router.Use(middlewares.Timeout(time.Millisecond, "GET /api/v1/events"))
+		router.GET("/api/v1/events", func(c *gin.Context) {
+			_, hasDeadline = c.Request.Context().Deadline()
+			time.Sleep(10 * time.Millisecond)
+			c.Status(http.StatusOK)
+		})
+
+		// Act
+		w := serve(http.MethodGet, "/api/v1/events")
+
+		// Assert
+		Expect(hasDeadline).To(BeFalse())
+		Expect(w.Code).To(Equal(http.StatusOK))
+	})
+
+	// Given a route exempt only for NDJSON
+	// When it is requested with and without Accept: application/x-ndjson
+	// Then only the NDJSON request should run without a deadline
+	It("should match exemptions limited to a media type on the Accept header", func() {
+		// Arrange
+		var hasDeadline bool
+		router.Use(middlewares.Timeout(time.Second, "GET /api/v1/vms application/x-ndjson"))
+		router.GET("/api/v1/vms", func(c *gin.Context) {
+			_, hasDeadline = c.Request.Context().Deadline()
+			c.Status(http.StatusOK)
+		})
+		get := func(accept string) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/vms", nil)
+			if accept != "" {
+				req.Header.Set("Accept", accept)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		// Act & Assert
+		get("application/x-ndjson")
+		Expect(hasDeadline).To(BeFalse())
+
+		get("")
+		Expect(hasDeadline).To(BeTrue())
+
+		get("application/json")
+		Expect(hasDeadline).To(BeTrue())
+	})
+
+	// Given an exemption for one method
+	// When another method hits the same path
+	// Then the timeout should still apply
+	It("should match exemptions by method and path", func() {
+		// Arrange
+		router.Use(middlewares.Timeout(20*time.Millisecond, "GET /api/v1/inspector/vddk"))
+		router.PUT("/api/v1/inspector/vddk", slow)
+
+		// Act
+		w := serve(http.MethodPut, "/api/v1/inspector/vddk")
+
+		// Assert
+		Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	// Given a zero timeout
+	// When a request is served
+	// Then no deadline should be set
+	It("should be disabled by a zero timeout", func() {
+		// Arrange
+		var hasDeadline bool
+		router.Use(middlewares.Timeout(0))
+		router.GET("/api/v1/vms", func(c *gin.Context) {
+			_, hasDeadline = c.Request.Context().Deadline()
+			c.Status(http.StatusNoContent)
+		})
+
+		// Act
+		w := serve(http.MethodGet, "/api/v1/vms")
+
+		// Assert
+		Expect(hasDeadline).To(BeFalse())
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+	})
+})
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/pkg/work"
+)
+
+// CollectionHistoryFile is the name of the history spool inside the data folder.
+const CollectionHistoryFile = "collection-history.jsonl"
+
+// historyRecord is the on-disk shape of one spooled event.
+type historyRecord struct {
+	Time  time.Time `json:"time"`
+	State string    `json:"state"`
+	Error string    `json:"error,omitempty"`
+}
+
+// CollectionHistory is an append-only JSONL spool of terminal collection
+// outcomes. Events are only recorded while the agent is disconnected, since a
+// connected agent already reports them to the console. The file lives in the
+// data folder, so it survives restarts and mode switches.
+type CollectionHistory struct {
+	mu    sync.Mutex
+	path  string
+	store *store.Store
+}
+
+func NewCollectionHistory(dataFolder string, st *store.Store) *CollectionHistory {
+	return &CollectionHistory{
+		path:  filepath.Join(dataFolder, CollectionHistoryFile),
+		store: st,
+	}
+}
+
+// Record appends event to the spool if the agent is disconnected.
+func (h *CollectionHistory) Record(ctx context.Context, event models.CollectionHistoryEvent) error {
+	cfg, err := h.store.Configuration().Get(ctx)
+	if err != nil && !srvErrors.IsResourceNotFoundError(err) {
+		return err
+	}
+	if cfg != nil && cfg.AgentMode == models.AgentModeConnected {
+		return nil
+	}
+
+	line, err := json.Marshal(historyRecord{
+		Time:  event.Time.UTC(),
+		State: string(event.State),
+		Error: event.Error,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening collection history: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing collection history: %w", err)
+	}
+	return nil
+}
+
+// List returns the spooled events, oldest first. A missing spool yields an
+// empty list.
+func (h *CollectionHistory) List(ctx context.Context) ([]models.CollectionHistoryEvent, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := []models.CollectionHistoryEvent{}
+
+	f, err := os.Open(h.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return events, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening collection history: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r historyRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// A torn last line from a crash mid-write must not hide the rest.
+			zap.S().Named("collector_service").Warnw("skipping malformed collection history line", "error", err)
+			continue
+		}
+		events = append(events, models.CollectionHistoryEvent{
+			Time:  r.Time,
+			State: models.CollectorStateType(r.State),
+			Error: r.Error,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading collection history: %w", err)
+	}
+
+	return events, nil
+}
+
+// historyWorkBuilder wraps a collection builder and records its terminal
+// outcome: "collected" once the builder is exhausted, or "error" when a unit
+// fails. Cancellation by Stop is not a terminal outcome and is not recorded.
+type historyWorkBuilder struct {
+	inner   work.WorkBuilder[models.CollectorStatus, models.CollectorResult]
+	history *CollectionHistory
+	done    bool
+}
+
+func (b *historyWorkBuilder) Next() (collectorWorkUnit, bool) {
+	unit, ok := b.inner.Next()
+	if !ok {
+		if !b.done {
+			b.done = true
+			b.record(models.CollectionHistoryEvent{State: models.CollectorStateCollected})
+		}
+		return unit, false
+	}
+
+	fn := unit.Work
+	unit.Work = func(ctx context.Context, result models.CollectorResult) (models.CollectorResult, error) {
+		result, err := fn(ctx, result)
+		if err != nil && ctx.Err() == nil {
+			b.done = true
+			b.record(models.CollectionHistoryEvent{State: models.CollectorStateError, Error: err.Error()})
+		}
+		return result, err
+	}
+	return unit, true
+}
+
+func (b *historyWorkBuilder) record(event models.CollectionHistoryEvent) {
+	event.Time = time.Now()
+	if err := b.history.Record(context.Background(), event); err != nil {
+		zap.S().Named("collector_service").Warnw("failed to record collection history", "error", err)
+	}
+}
@@ -0,0 +1,176 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+var _ = Describe("CollectionHistory", func() {
+	var (
+		ctx        context.Context
+		db         *sql.DB
+		st         *store.Store
+		eventSrv   *services.EventService
+		invSrv     *services.InventoryService
+		dataFolder string
+		history    *services.CollectionHistory
+		srv        *services.CollectorService
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		dataFolder = GinkgoT().TempDir()
+
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = migrations.Run(ctx, db)
+		Expect(err).NotTo(HaveOccurred())
+
+		st = store.NewStore(db, test.NewMockValidator())
+		invSrv = services.NewInventoryService(st)
+		eventSrv = services.NewEventService(st)
+		history = services.NewCollectionHistory(dataFolder, st)
+		srv = services.NewCollectorService(invSrv, mockCollectorBuilder(st, eventSrv, errors.New("login failed"), nil, nil)).
+			WithHistory(history)
+	})
+
+	AfterEach(func() {
+		if srv != nil {
+			srv.Stop()
+		}
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	historyLen := func() int {
+		events, err := srv.History(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		return len(events)
+	}
+
+	// Given a disconnected agent with history enabled
+	// When a collection fails and the next one succeeds
+	// Then both outcomes should be appended in order
+	It("should append an event for each collection", func() {
+		// Arrange
+		before := time.Now()
+
+		// Act
+		Expect(srv.Start(ctx, models.Credentials{})).To(Succeed())
+		Eventually(historyLen, 2*time.Second, 10*time.Millisecond).Should(Equal(1))
+
+		srv.WithWorkBuilder(mockCollectorBuilder(st, eventSrv, nil, nil, nil))
+		Expect(srv.Start(ctx, models.Credentials{})).To(Succeed())
+		Eventually(historyLen, 2*time.Second, 10*time.Millisecond).Should(Equal(2))
+
+		// Assert
+		events, err := srv.History(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events[0].State).To(Equal(models.CollectorStateError))
+		Expect(events[0].Error).To(ContainSubstring("login failed"))
+		Expect(events[0].Time).To(BeTemporally(">=", before.Truncate(time.Second)))
+		Expect(events[1].State).To(Equal(models.CollectorStateCollected))
+		Expect(events[1].Error).To(BeEmpty())
+		Expect(events[1].Time).NotTo(BeTemporally("<", events[0].Time))
+	})
+
+	// Given a history file written by a previous agent process
+	// When a new history is opened on the same data folder
+	// Then it should return the previously recorded events
+	It("should survive a restart", func() {
+		// Arrange
+		Expect(srv.Start(ctx, models.Credentials{})).To(Succeed())
+		Eventually(historyLen, 2*time.Second, 10*time.Millisecond).Should(Equal(1))
+
+		// Act
+		reopened := services.NewCollectionHistory(dataFolder, st)
+		events, err := reopened.List(ctx)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].State).To(Equal(models.CollectorStateError))
+		Expect(filepath.Join(dataFolder, services.CollectionHistoryFile)).To(BeAnExistingFile())
+	})
+
+	// Given a connected agent
+	// When a collection finishes
+	// Then nothing should be recorded
+	It("should not record while connected", func() {
+		// Arrange
+		Expect(st.Configuration().Save(ctx, &models.Configuration{AgentMode: models.AgentModeConnected})).To(Succeed())
+		srv.WithWorkBuilder(mockCollectorBuilder(st, eventSrv, nil, nil, nil))
+
+		// Act
+		Expect(srv.Start(ctx, models.Credentials{})).To(Succeed())
+		Eventually(func() models.CollectorStateType {
+			return srv.GetStatus().State
+		}, 2*time.Second).Should(Equal(models.CollectorStateCollected))
+
+		// Assert
+		Consistently(historyLen, 200*time.Millisecond).Should(BeZero())
+	})
+
+	// Given a collection that is stopped by the user
+	// When the pipeline is cancelled
+	// Then the cancellation should not be recorded
+	It("should not record stopped collections", func() {
+		// Arrange
+		srv.WithWorkBuilder(blockingCollectorBuilder(make(chan struct{})))
+		Expect(srv.Start(ctx, models.Credentials{})).To(Succeed())
+
+		// Act
+		srv.Stop()
+
+		// Assert
+		Consistently(historyLen, 200*time.Millisecond).Should(BeZero())
+	})
+
+	// Given a spool with a torn trailing line
+	// When the history is listed
+	// Then the valid events should still be returned
+	It("should skip malformed lines", func() {
+		// Arrange
+		content := `{"time":"2026-03-01T10:00:00Z","state":"collected"}` + "\n" + `{"time":"2026-03-01T1`
+		Expect(os.WriteFile(filepath.Join(dataFolder, services.CollectionHistoryFile), []byte(content), 0o644)).To(Succeed())
+
+		// Act
+		events, err := history.List(ctx)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].State).To(Equal(models.CollectorStateCollected))
+	})
+
+	// Given a collector without history
+	// When the history is requested
+	// Then it should return a not found error
+	It("should return not found when history is disabled", func() {
+		// Arrange
+		plain := services.NewCollectorService(invSrv, mockCollectorBuilder(st, eventSrv, nil, nil, nil))
+
+		// Act
+		_, err := plain.History(ctx)
+
+		// Assert
+		Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+	})
+})
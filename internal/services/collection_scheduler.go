@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+// CollectionSchedule returns the next time a recollection is due.
+type CollectionSchedule interface {
+	Next(time.Time) time.Time
+}
+
+// intervalSchedule fires every interval. cron.Every rounds to whole seconds,
+// which is too coarse for short test intervals.
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// ParseCollectionSchedule parses a Go duration (e.g. "24h") or a standard
+// five-field cron expression (e.g. "0 2 * * *", "@daily").
+func ParseCollectionSchedule(expr string) (CollectionSchedule, error) {
+	if d, err := time.ParseDuration(expr); err == nil {
+		if d <= 0 {
+			return nil, fmt.Errorf("duration must be positive: %s", expr)
+		}
+		return intervalSchedule(d), nil
+	}
+
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither a duration nor a cron expression: %w", expr, err)
+	}
+	return schedule, nil
+}
+
+// CollectionScheduler periodically triggers a recollection with the last
+// credentials supplied to the collector. A tick is skipped when no
+// credentials were stored yet, a collection is already running, maintenance
+// mode is on or an inspection is running.
+type CollectionScheduler struct {
+	collector   *CollectorService
+	schedule    CollectionSchedule
+	maintenance *MaintenanceService
+	inspector   *InspectorService
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewCollectionScheduler(collector *CollectorService, schedule CollectionSchedule) *CollectionScheduler {
+	return &CollectionScheduler{
+		collector: collector,
+		schedule:  schedule,
+	}
+}
+
+// WithMaintenance skips scheduled recollections while maintenance mode is on.
+func (s *CollectionScheduler) WithMaintenance(maintenance *MaintenanceService) *CollectionScheduler {
+	s.maintenance = maintenance
+	return s
+}
+
+// WithInspector skips scheduled recollections while an inspection is running,
+// since a recollection clears the inspection results.
+func (s *CollectionScheduler) WithInspector(inspector *InspectorService) *CollectionScheduler {
+	s.inspector = inspector
+	return s
+}
+
+// Start runs the schedule in the background. Calling Start on a running
+// scheduler is a no-op.
+func (s *CollectionScheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(ctx, s.done)
+}
+
+// Stop halts the schedule and waits for the loop to exit. A collection that
+// is already running is left to the collector.
+func (s *CollectionScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	<-s.done
+	s.cancel = nil
+	s.done = nil
+}
+
+func (s *CollectionScheduler) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	for {
+		now := time.Now()
+		timer := time.NewTimer(s.schedule.Next(now).Sub(now))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+func (s *CollectionScheduler) tick(ctx context.Context) {
+	log := zap.S().Named("collection_scheduler")
+
+	if s.maintenance != nil && s.maintenance.Enabled() {
+		log.Debug("skipping scheduled collection: maintenance mode is on")
+		return
+	}
+	if s.inspector != nil && s.inspector.IsBusy() {
+		log.Debug("skipping scheduled collection: inspection is running")
+		return
+	}
+
+	err := s.collector.Recollect(ctx)
+	switch {
+	case err == nil:
+		log.Info("scheduled collection started")
+	case srvErrors.IsOperationInProgressError(err):
+		log.Debug("skipping scheduled collection: collection in progress")
+	case srvErrors.IsCredentialsNotSetError(err):
+		log.Debug("skipping scheduled collection: no credentials stored")
+	default:
+		log.Errorw("failed to start scheduled collection", "error", err)
+	}
+}
@@ -0,0 +1,186 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	"github.com/kubev2v/assisted-migration-agent/pkg/work"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+var _ = Describe("CollectionScheduler", func() {
+	var (
+		ctx       context.Context
+		db        *sql.DB
+		st        *store.Store
+		eventSrv  *services.EventService
+		collector *services.CollectorService
+		scheduler *services.CollectionScheduler
+		builds    atomic.Int32
+	)
+
+	// counting wraps a builder func and counts how many collections it built.
+	counting := func(fn func(models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult]) func(models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+		return func(creds models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+			builds.Add(1)
+			return fn(creds)
+		}
+	}
+
+	newScheduler := func(expr string) *services.CollectionScheduler {
+		schedule, err := services.ParseCollectionSchedule(expr)
+		Expect(err).NotTo(HaveOccurred())
+		return services.NewCollectionScheduler(collector, schedule)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		builds.Store(0)
+
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = migrations.Run(ctx, db)
+		Expect(err).NotTo(HaveOccurred())
+
+		st = store.NewStore(db, test.NewMockValidator())
+		eventSrv = services.NewEventService(st)
+		collector = services.NewCollectorService(services.NewInventoryService(st), counting(mockCollectorBuilder(st, eventSrv, nil, nil, nil)))
+	})
+
+	AfterEach(func() {
+		if scheduler != nil {
+			scheduler.Stop()
+			scheduler = nil
+		}
+		collector.Stop()
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	// Given stored credentials and a short schedule
+	// When the scheduler runs for a while
+	// Then the collector should recollect several times
+	It("should recollect periodically with the last credentials", func() {
+		// Arrange
+		Expect(collector.Start(ctx, models.Credentials{URL: "https://vcenter.example.com"})).To(Succeed())
+		Eventually(func() models.CollectorStateType {
+			return collector.GetStatus().State
+		}, 2*time.Second, 10*time.Millisecond).Should(Equal(models.CollectorStateCollected))
+		scheduler = newScheduler("50ms")
+
+		// Act
+		scheduler.Start()
+
+		// Assert
+		Eventually(builds.Load, 2*time.Second, 10*time.Millisecond).Should(BeNumerically(">=", 4))
+	})
+
+	// Given a collection that never finishes
+	// When the schedule fires repeatedly
+	// Then no second collection should be started
+	It("should not overlap a running collection", func() {
+		// Arrange
+		collector.WithWorkBuilder(counting(blockingCollectorBuilder(make(chan struct{}))))
+		Expect(collector.Start(ctx, models.Credentials{})).To(Succeed())
+		scheduler = newScheduler("20ms")
+
+		// Act
+		scheduler.Start()
+
+		// Assert
+		Consistently(builds.Load, 300*time.Millisecond, 10*time.Millisecond).Should(Equal(int32(1)))
+	})
+
+	// Given no credentials were ever supplied
+	// When the schedule fires
+	// Then nothing should be collected
+	It("should skip when no credentials are stored", func() {
+		// Arrange
+		scheduler = newScheduler("20ms")
+
+		// Act
+		scheduler.Start()
+
+		// Assert
+		Consistently(builds.Load, 200*time.Millisecond, 10*time.Millisecond).Should(BeZero())
+	})
+
+	// Given stored credentials and maintenance mode on
+	// When the schedule fires
+	// Then no recollection should be started
+	It("should skip while maintenance mode is on", func() {
+		// Arrange
+		Expect(collector.Start(ctx, models.Credentials{})).To(Succeed())
+		Eventually(func() models.CollectorStateType {
+			return collector.GetStatus().State
+		}, 2*time.Second, 10*time.Millisecond).Should(Equal(models.CollectorStateCollected))
+
+		maintenance, err := services.NewMaintenanceService(st)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maintenance.SetEnabled(ctx, true)).To(Succeed())
+		scheduler = newScheduler("20ms").WithMaintenance(maintenance)
+
+		// Act
+		scheduler.Start()
+
+		// Assert
+		Consistently(builds.Load, 200*time.Millisecond, 10*time.Millisecond).Should(Equal(int32(1)))
+	})
+
+	Describe("ParseCollectionSchedule", func() {
+		// Given a duration
+		// When we parse it
+		// Then the next run should be one interval later
+		It("should parse a duration", func() {
+			// Arrange
+			now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+			// Act
+			schedule, err := services.ParseCollectionSchedule("90m")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.Next(now)).To(Equal(now.Add(90 * time.Minute)))
+		})
+
+		// Given a standard cron expression
+		// When we parse it
+		// Then the next run should follow the expression
+		It("should parse a cron expression", func() {
+			// Arrange
+			now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+			// Act
+			schedule, err := services.ParseCollectionSchedule("0 2 * * *")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.Next(now)).To(Equal(time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)))
+		})
+
+		// Given invalid or non-positive schedules
+		// When we parse them
+		// Then each should be rejected
+		It("should reject invalid schedules", func() {
+			for _, expr := range []string{"", "every day", "0s", "-5m", "61 * * * *"} {
+				// Act
+				_, err := services.ParseCollectionSchedule(expr)
+
+				// Assert
+				Expect(err).To(HaveOccurred(), expr)
+			}
+		})
+	})
+})
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
+
+	"go.uber.org/zap"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/pkg/vmware"
 	"github.com/kubev2v/assisted-migration-agent/pkg/work"
 )
 
@@ -15,30 +20,80 @@ type (
 	collectorWorkBuilderFunc func(creds models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult]
 )
 
+// errCollectionInterrupted is the collector error after a restart cut a
+// collection short and no stored credentials allowed restarting it.
+var errCollectionInterrupted = errors.New("collection was interrupted by an agent restart; start a new collection")
+
 type CollectorService struct {
 	mu           sync.Mutex
 	workSrv      *work.Service[models.CollectorStatus, models.CollectorResult]
 	inventorySrv *InventoryService
 	buildFn      collectorWorkBuilderFunc
+	history      *CollectionHistory
+	timings      *phaseTimings
+	lastCreds    *models.Credentials
+	credentials  *store.CredentialsStore
+	states       *store.CollectorStateStore
+	// interrupted is set by Resume when a collection cut short by a restart
+	// could not be restarted, and cleared when a new one starts.
+	interrupted error
+	// changed is closed and replaced whenever the collector state may have
+	// changed, waking every WaitStatus caller.
+	changed chan struct{}
+
+	broker      *StatusBroker
+	publishMu   sync.Mutex
+	lastPublish *models.CollectorStatus
 }
 
 func NewCollectorService(inventorySrv *InventoryService, buildFn collectorWorkBuilderFunc) *CollectorService {
 	return &CollectorService{
 		inventorySrv: inventorySrv,
 		buildFn:      buildFn,
+		changed:      make(chan struct{}),
 	}
 }
 
+// GetStatus returns the collector status along with the phase timings of the
+// last collection started since the agent came up and, once collected, the
+// warnings of the last successful collection.
 func (c *CollectorService) GetStatus() models.CollectorStatus {
+	c.mu.Lock()
+	srv, timings, interrupted := c.workSrv, c.timings, c.interrupted
+	c.mu.Unlock()
+
+	status := c.status(srv, interrupted)
+	if timings != nil {
+		status.Phases = timings.list()
+	}
+	return status
+}
+
+// status derives the collector status from the collection srv (nil before the
+// first one), the stored inventory and the error left by Resume, if any.
+func (c *CollectorService) status(srv *work.Service[models.CollectorStatus, models.CollectorResult], interrupted error) models.CollectorStatus {
+	// A running recollection takes precedence over the stored inventory so
+	// its progress is visible.
+	if srv != nil && srv.IsRunning() {
+		return srv.State().State
+	}
+
 	inv, err := c.inventorySrv.GetInventory(context.Background())
 	if err == nil && inv != nil {
-		return models.CollectorStatus{State: models.CollectorStateCollected}
+		status := models.CollectorStatus{State: models.CollectorStateCollected}
+		warnings, err := c.inventorySrv.GetWarnings(context.Background())
+		if err != nil {
+			zap.S().Named("collector_service").Warnw("failed to read collection warnings", "error", err)
+		}
+		status.Warnings = warnings
+		collectedAt, err := c.inventorySrv.CollectedAt(context.Background())
+		if err != nil {
+			zap.S().Named("collector_service").Warnw("failed to read collection time", "error", err)
+		}
+		status.CollectedAt = collectedAt
+		return status
 	}
 
-	c.mu.Lock()
-	srv := c.workSrv
-	c.mu.Unlock()
-
 	if srv != nil {
 		state := srv.State()
 		if state.Err == nil {
@@ -49,42 +104,395 @@ func (c *CollectorService) GetStatus() models.CollectorStatus {
 		}
 	}
 
+	if interrupted != nil {
+		return models.CollectorStatus{State: models.CollectorStateError, Error: interrupted}
+	}
+
 	return models.CollectorStatus{State: models.CollectorStateReady}
 }
 
+// WaitStatus blocks until the collector state differs from since, timeout
+// elapses or ctx is done, then returns the current status.
+func (c *CollectorService) WaitStatus(ctx context.Context, since models.CollectorStateType, timeout time.Duration) models.CollectorStatus {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		// Take the channel before reading the state so a change in between
+		// still wakes us.
+		c.mu.Lock()
+		changed := c.changed
+		c.mu.Unlock()
+
+		status := c.GetStatus()
+		if status.State != since {
+			return status
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return c.GetStatus()
+		case <-ctx.Done():
+			return c.GetStatus()
+		}
+	}
+}
+
 func (c *CollectorService) Start(ctx context.Context, creds models.Credentials) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.workSrv != nil && c.workSrv.IsRunning() {
-		return srvErrors.NewCollectionInProgressError()
-	}
+	c.rememberCredentialsLocked(ctx, creds)
 
+	// Once inventory exists Start is a no-op, even while a recollection runs.
 	inv, err := c.inventorySrv.GetInventory(ctx)
 	if err == nil && inv != nil {
 		return nil
 	}
 
-	srv := work.NewService(models.CollectorStatus{State: models.CollectorStateConnecting}, c.buildFn(creds))
+	if c.workSrv != nil && c.workSrv.IsRunning() {
+		return srvErrors.NewCollectionInProgressError()
+	}
+
+	return c.startLocked(creds)
+}
+
+// Refresh is Start for an inventory that goes stale: a stored inventory
+// collected less than maxAge ago is kept, and an older one is replaced by a
+// new collection with creds. Returns a CollectionInProgressError if a
+// collection is running.
+func (c *CollectorService) Refresh(ctx context.Context, creds models.Credentials, maxAge time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rememberCredentialsLocked(ctx, creds)
+
+	// Like Start, a fresh inventory is kept even while a recollection runs.
+	collectedAt, err := c.inventorySrv.CollectedAt(ctx)
+	switch {
+	case err == nil:
+		if time.Since(collectedAt) < maxAge {
+			zap.S().Named("collector_service").Infow("inventory is fresh, skipping collection", "collected_at", collectedAt, "max_age", maxAge)
+			return nil
+		}
+	case srvErrors.IsResourceNotFoundError(err):
+	default:
+		return err
+	}
+
+	if c.workSrv != nil && c.workSrv.IsRunning() {
+		return srvErrors.NewCollectionInProgressError()
+	}
+
+	return c.startLocked(creds)
+}
+
+// rememberCredentialsLocked keeps creds for Recollect, in the credentials
+// store when one is set.
+func (c *CollectorService) rememberCredentialsLocked(ctx context.Context, creds models.Credentials) {
+	c.lastCreds = &creds
+	if c.credentials != nil {
+		if err := c.credentials.Save(ctx, creds); err != nil {
+			zap.S().Named("collector_service").Warnw("failed to store credentials for recollection", "error", err)
+		}
+	}
+}
+
+// Recollect runs a new collection with the last credentials passed to Start,
+// replacing the stored inventory. After a restart the credentials are loaded
+// from the credentials store, if one is set. Returns a
+// CollectionInProgressError if a collection is running and a
+// CredentialsNotSetError if no credentials are known.
+func (c *CollectorService) Recollect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.workSrv != nil && c.workSrv.IsRunning() {
+		return srvErrors.NewCollectionInProgressError()
+	}
+
+	if c.lastCreds == nil && c.credentials != nil {
+		creds, err := c.credentials.Get(ctx)
+		switch {
+		case err == nil:
+			c.lastCreds = creds
+		case srvErrors.IsResourceNotFoundError(err):
+		default:
+			return err
+		}
+	}
+
+	if c.lastCreds == nil {
+		return srvErrors.NewCredentialsNotSetError()
+	}
+
+	return c.startLocked(*c.lastCreds)
+}
+
+// Revalidate recomputes the concerns of the collected VMs and rebuilds the
+// inventory without connecting to vCenter. It returns the number of concerns
+// stored, a CollectionInProgressError if a collection is running or ran while
+// the VMs were validated, and a ResourceNotFoundError if nothing was collected
+// yet.
+//
+// Only reading the VMs and saving the result hold mu; the validation runs
+// without it, so status reads and WaitStatus are not held up by the policies.
+func (c *CollectorService) Revalidate(ctx context.Context) (int, error) {
+	rv, err := c.readForRevalidation(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	concerns := rv.validate(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.workSrv != nil && c.workSrv.IsRunning() {
+		return 0, srvErrors.NewCollectionInProgressError()
+	}
+	// A collection that started and finished meanwhile replaced the VMs.
+	collectedAt, err := c.inventorySrv.CollectedAt(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !collectedAt.Equal(rv.collectedAt) {
+		return 0, srvErrors.NewCollectionInProgressError()
+	}
+
+	return c.inventorySrv.saveRevalidation(ctx, concerns)
+}
+
+func (c *CollectorService) readForRevalidation(ctx context.Context) (*revalidation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.workSrv != nil && c.workSrv.IsRunning() {
+		return nil, srvErrors.NewCollectionInProgressError()
+	}
+	return c.inventorySrv.readForRevalidation(ctx)
+}
+
+// TestConnection logs in to vCenter with creds and returns its product
+// information. It neither starts a collection nor stores the credentials.
+func (c *CollectorService) TestConnection(ctx context.Context, creds models.Credentials) (*models.VCenterInfo, error) {
+	return vmware.TestConnection(ctx, &creds)
+}
+
+func (c *CollectorService) startLocked(creds models.Credentials) error {
+	if err := checkCollectorTransition(c.status(c.workSrv, c.interrupted).State, models.CollectorStateConnecting); err != nil {
+		return err
+	}
+
+	timings := &phaseTimings{}
+	builder := c.buildFn(creds)
+	builder = &timingWorkBuilder{inner: builder, timings: timings}
+	builder = &transitionWorkBuilder{inner: builder, last: models.CollectorStateConnecting}
+	if c.states != nil {
+		builder = &stateWorkBuilder{inner: builder, states: c.states}
+	}
+	if c.history != nil {
+		builder = &historyWorkBuilder{inner: builder, history: c.history}
+	}
+	builder = &notifyWorkBuilder{inner: builder, notify: c.notify}
+
+	srv := work.NewService(models.CollectorStatus{State: models.CollectorStateConnecting}, builder)
 	if err := srv.Start(); err != nil {
 		return err
 	}
 
 	c.workSrv = srv
+	c.timings = timings
+	c.interrupted = nil
+	c.notifyLocked()
+
+	go func() {
+		srv.Wait()
+		c.notify()
+	}()
+
 	return nil
 }
 
+// notify wakes the WaitStatus callers.
+func (c *CollectorService) notify() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notifyLocked()
+}
+
+func (c *CollectorService) notifyLocked() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+
+	if c.broker != nil {
+		// GetStatus takes c.mu, so publish from outside the lock.
+		go c.publishStatus()
+	}
+}
+
+// publishStatus publishes the current status to the broker unless it is the
+// one published last. Holding publishMu while reading the status keeps
+// concurrent calls from publishing an older status after a newer one.
+func (c *CollectorService) publishStatus() {
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+
+	status := c.GetStatus()
+	if last := c.lastPublish; last != nil && last.State == status.State && errors.Is(last.Error, status.Error) {
+		return
+	}
+	c.lastPublish = &status
+
+	c.broker.Publish(models.StatusEvent{Source: models.StatusEventCollector, Status: status})
+}
+
+// Stop cancels the running collection, if any. Unlike a shutdown, a stopped
+// collection is not resumed on the next start.
 func (c *CollectorService) Stop() {
 	c.mu.Lock()
 	srv := c.workSrv
 	c.mu.Unlock()
 
+	if srv == nil {
+		return
+	}
+
+	running := srv.IsRunning()
+	srv.Stop()
+	if running && c.states != nil {
+		if err := c.states.Clear(context.Background()); err != nil {
+			zap.S().Named("collector_service").Warnw("failed to clear collector state", "error", err)
+		}
+	}
+}
+
+// shutdown cancels the running collection but keeps its persisted state, so
+// Resume picks it up on the next start.
+func (c *CollectorService) shutdown() {
+	c.mu.Lock()
+	srv := c.workSrv
+	c.mu.Unlock()
+
 	if srv != nil {
 		srv.Stop()
 	}
 }
 
+// Resume reconciles a collection cut short by a restart, as recorded by the
+// collector state store. It restarts the collection with the stored
+// credentials when there are some; otherwise the collector reports the error
+// state with errCollectionInterrupted until a new collection starts. Returns
+// whether a collection was restarted. Call it once at startup, before
+// anything else starts a collection.
+func (c *CollectorService) Resume(ctx context.Context) (bool, error) {
+	if c.states == nil {
+		return false, nil
+	}
+
+	state, err := c.states.Get(ctx)
+	if srvErrors.IsResourceNotFoundError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	log := zap.S().Named("collector_service")
+
+	// Already reconciled by an earlier start that could not restart it.
+	if state == models.CollectorStateError {
+		c.interrupted = errCollectionInterrupted
+		return false, nil
+	}
+
+	if c.credentials != nil {
+		creds, err := c.credentials.Get(ctx)
+		switch {
+		case err == nil:
+			log.Infow("restarting collection interrupted by a restart", "state", state)
+			c.lastCreds = creds
+			if err := c.startLocked(*creds); err != nil {
+				return false, err
+			}
+			return true, nil
+		case !srvErrors.IsResourceNotFoundError(err):
+			log.Warnw("failed to read stored credentials", "error", err)
+		}
+	}
+
+	log.Warnw("collection interrupted by a restart cannot be restarted without stored credentials", "state", state)
+	if err := c.states.Save(ctx, models.CollectorStateError); err != nil {
+		return false, err
+	}
+	c.interrupted = errCollectionInterrupted
+	c.notifyLocked()
+	return false, nil
+}
+
 func (c *CollectorService) WithWorkBuilder(fn collectorWorkBuilderFunc) *CollectorService {
 	c.buildFn = fn
 	return c
 }
+
+// WithHistory enables recording terminal collection outcomes to history while
+// the agent is disconnected.
+func (c *CollectorService) WithHistory(history *CollectionHistory) *CollectorService {
+	c.history = history
+	return c
+}
+
+// WithStatusBroker publishes every collector state change to broker.
+func (c *CollectorService) WithStatusBroker(broker *StatusBroker) *CollectorService {
+	c.broker = broker
+	return c
+}
+
+// WithCredentialsStore persists the credentials passed to Start, encrypted,
+// so Recollect can use them after a restart.
+func (c *CollectorService) WithCredentialsStore(credentials *store.CredentialsStore) *CollectorService {
+	c.credentials = credentials
+	return c
+}
+
+// WithStateStore persists the state of the collection in flight, so Resume
+// can tell after a restart that one was cut short.
+func (c *CollectorService) WithStateStore(states *store.CollectorStateStore) *CollectorService {
+	c.states = states
+	return c
+}
+
+// History returns the recorded collection outcomes, oldest first. Returns a
+// ResourceNotFoundError when the history is not enabled.
+func (c *CollectorService) History(ctx context.Context) ([]models.CollectionHistoryEvent, error) {
+	if c.history == nil {
+		return nil, srvErrors.NewResourceNotFoundError("collection history", "")
+	}
+	return c.history.List(ctx)
+}
+
+// notifyWorkBuilder wraps a collection builder and calls notify as each unit
+// starts. The pipeline publishes the unit status before running it, so
+// waiters woken here read the new state.
+type notifyWorkBuilder struct {
+	inner  work.WorkBuilder[models.CollectorStatus, models.CollectorResult]
+	notify func()
+}
+
+func (b *notifyWorkBuilder) Next() (collectorWorkUnit, bool) {
+	unit, ok := b.inner.Next()
+	if !ok {
+		return unit, false
+	}
+
+	fn := unit.Work
+	unit.Work = func(ctx context.Context, result models.CollectorResult) (models.CollectorResult, error) {
+		b.notify()
+		return fn(ctx, result)
+	}
+	return unit, true
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/pkg/work"
+)
+
+// collectorTransitions lists, for each collector state, the states it may move
+// to. A collection walks connecting -> collecting -> parsing -> collected; any
+// running state may fail (error) or be stopped (ready). A collected or failed
+// collector starts again from connecting, and clearing the inventory brings a
+// collected one back to ready.
+var collectorTransitions = map[models.CollectorStateType][]models.CollectorStateType{
+	models.CollectorStateReady: {
+		models.CollectorStateConnecting,
+	},
+	models.CollectorStateConnecting: {
+		models.CollectorStateCollecting,
+		models.CollectorStateError,
+		models.CollectorStateReady,
+	},
+	models.CollectorStateCollecting: {
+		models.CollectorStateParsing,
+		models.CollectorStateError,
+		models.CollectorStateReady,
+	},
+	models.CollectorStateParsing: {
+		models.CollectorStateCollected,
+		models.CollectorStateError,
+		models.CollectorStateReady,
+	},
+	models.CollectorStateCollected: {
+		models.CollectorStateConnecting,
+		models.CollectorStateReady,
+	},
+	models.CollectorStateError: {
+		models.CollectorStateConnecting,
+		models.CollectorStateReady,
+	},
+}
+
+// checkCollectorTransition returns an InvalidStateError, and logs the
+// transition, when the table does not allow moving from one state to the
+// other. Staying in the same state is not a transition and always passes.
+func checkCollectorTransition(from, to models.CollectorStateType) error {
+	if from == to || slices.Contains(collectorTransitions[from], to) {
+		return nil
+	}
+	zap.S().Named("collector_service").Errorw("invalid collector state transition", "from", from, "to", to)
+	return srvErrors.NewInvalidStateError()
+}
+
+// transitionWorkBuilder wraps a collection builder and checks each unit's
+// state against the one before it. A unit entering a state the table does not
+// allow fails with an InvalidStateError instead of running, which ends the
+// collection in the error state.
+type transitionWorkBuilder struct {
+	inner work.WorkBuilder[models.CollectorStatus, models.CollectorResult]
+	last  models.CollectorStateType
+}
+
+func (b *transitionWorkBuilder) Next() (collectorWorkUnit, bool) {
+	unit, ok := b.inner.Next()
+	if !ok {
+		return unit, false
+	}
+
+	from, to := b.last, unit.Status().State
+	b.last = to
+
+	fn := unit.Work
+	unit.Work = func(ctx context.Context, result models.CollectorResult) (models.CollectorResult, error) {
+		if err := checkCollectorTransition(from, to); err != nil {
+			return result, err
+		}
+		return fn(ctx, result)
+	}
+	return unit, true
+}
+
+// stateWorkBuilder wraps a collection builder and persists the state of each
+// unit as it starts, so Resume can tell a collection was cut short by a
+// restart. The state is cleared once the collection ends, collected or
+// failed. A cancelled unit leaves it: Stop clears it, while a shutdown keeps
+// it for the next start.
+type stateWorkBuilder struct {
+	inner  work.WorkBuilder[models.CollectorStatus, models.CollectorResult]
+	states *store.CollectorStateStore
+	done   bool
+}
+
+func (b *stateWorkBuilder) Next() (collectorWorkUnit, bool) {
+	unit, ok := b.inner.Next()
+	if !ok {
+		if !b.done {
+			b.done = true
+			b.clear()
+		}
+		return unit, false
+	}
+
+	state := unit.Status().State
+	fn := unit.Work
+	unit.Work = func(ctx context.Context, result models.CollectorResult) (models.CollectorResult, error) {
+		if err := b.states.Save(context.Background(), state); err != nil {
+			zap.S().Named("collector_service").Warnw("failed to persist collector state", "state", state, "error", err)
+		}
+		result, err := fn(ctx, result)
+		if err != nil && ctx.Err() == nil {
+			b.done = true
+			b.clear()
+		}
+		return result, err
+	}
+	return unit, true
+}
+
+func (b *stateWorkBuilder) clear() {
+	if err := b.states.Clear(context.Background()); err != nil {
+		zap.S().Named("collector_service").Warnw("failed to clear collector state", "error", err)
+	}
+}
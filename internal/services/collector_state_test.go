@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/pkg/work"
+)
+
+var _ = Describe("collector state transitions", func() {
+	type transition struct {
+		from models.CollectorStateType
+		to   models.CollectorStateType
+	}
+
+	Context("legal transitions", func() {
+		legal := []transition{
+			{models.CollectorStateReady, models.CollectorStateConnecting},
+			{models.CollectorStateConnecting, models.CollectorStateCollecting},
+			{models.CollectorStateConnecting, models.CollectorStateError},
+			{models.CollectorStateConnecting, models.CollectorStateReady},
+			{models.CollectorStateCollecting, models.CollectorStateParsing},
+			{models.CollectorStateCollecting, models.CollectorStateError},
+			{models.CollectorStateCollecting, models.CollectorStateReady},
+			{models.CollectorStateParsing, models.CollectorStateCollected},
+			{models.CollectorStateParsing, models.CollectorStateError},
+			{models.CollectorStateParsing, models.CollectorStateReady},
+			{models.CollectorStateCollected, models.CollectorStateConnecting},
+			{models.CollectorStateCollected, models.CollectorStateReady},
+			{models.CollectorStateError, models.CollectorStateConnecting},
+			{models.CollectorStateError, models.CollectorStateReady},
+			{models.CollectorStateCollecting, models.CollectorStateCollecting},
+		}
+
+		for _, t := range legal {
+			t := t
+			It("should allow "+string(t.from)+" -> "+string(t.to), func() {
+				Expect(checkCollectorTransition(t.from, t.to)).To(Succeed())
+			})
+		}
+	})
+
+	Context("illegal transitions", func() {
+		illegal := []transition{
+			{models.CollectorStateReady, models.CollectorStateCollecting},
+			{models.CollectorStateReady, models.CollectorStateParsing},
+			{models.CollectorStateReady, models.CollectorStateCollected},
+			{models.CollectorStateReady, models.CollectorStateError},
+			{models.CollectorStateConnecting, models.CollectorStateParsing},
+			{models.CollectorStateConnecting, models.CollectorStateCollected},
+			{models.CollectorStateCollecting, models.CollectorStateConnecting},
+			{models.CollectorStateCollecting, models.CollectorStateCollected},
+			{models.CollectorStateParsing, models.CollectorStateConnecting},
+			{models.CollectorStateParsing, models.CollectorStateCollecting},
+			{models.CollectorStateCollected, models.CollectorStateCollecting},
+			{models.CollectorStateCollected, models.CollectorStateParsing},
+			{models.CollectorStateCollected, models.CollectorStateError},
+			{models.CollectorStateError, models.CollectorStateCollecting},
+			{models.CollectorStateError, models.CollectorStateParsing},
+			{models.CollectorStateError, models.CollectorStateCollected},
+		}
+
+		for _, t := range illegal {
+			t := t
+			It("should reject "+string(t.from)+" -> "+string(t.to), func() {
+				err := checkCollectorTransition(t.from, t.to)
+				Expect(srvErrors.IsInvalidStateError(err)).To(BeTrue())
+			})
+		}
+	})
+
+	Describe("transitionWorkBuilder", func() {
+		unit := func(state models.CollectorStateType, ran *[]models.CollectorStateType) collectorWorkUnit {
+			return collectorWorkUnit{
+				Status: func() models.CollectorStatus { return models.CollectorStatus{State: state} },
+				Work: func(_ context.Context, r models.CollectorResult) (models.CollectorResult, error) {
+					*ran = append(*ran, state)
+					return r, nil
+				},
+			}
+		}
+
+		run := func(builder work.WorkBuilder[models.CollectorStatus, models.CollectorResult]) error {
+			for {
+				u, ok := builder.Next()
+				if !ok {
+					return nil
+				}
+				if _, err := u.Work(context.Background(), models.CollectorResult{}); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Given the collection pipeline in its usual order
+		// When every unit runs
+		// Then none of them should be rejected
+		It("should run a pipeline that follows the table", func() {
+			// Arrange
+			var ran []models.CollectorStateType
+			builder := &transitionWorkBuilder{
+				inner: work.NewSliceWorkBuilder([]collectorWorkUnit{
+					unit(models.CollectorStateConnecting, &ran),
+					unit(models.CollectorStateCollecting, &ran),
+					unit(models.CollectorStateParsing, &ran),
+					unit(models.CollectorStateCollected, &ran),
+				}),
+				last: models.CollectorStateConnecting,
+			}
+
+			// Act
+			err := run(builder)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ran).To(Equal([]models.CollectorStateType{
+				models.CollectorStateConnecting,
+				models.CollectorStateCollecting,
+				models.CollectorStateParsing,
+				models.CollectorStateCollected,
+			}))
+		})
+
+		// Given a pipeline that skips the collecting step
+		// When it moves from connecting to parsing
+		// Then the parsing unit should fail with an InvalidStateError without running
+		It("should fail a unit entering an illegal state", func() {
+			// Arrange
+			var ran []models.CollectorStateType
+			builder := &transitionWorkBuilder{
+				inner: work.NewSliceWorkBuilder([]collectorWorkUnit{
+					unit(models.CollectorStateConnecting, &ran),
+					unit(models.CollectorStateParsing, &ran),
+				}),
+				last: models.CollectorStateConnecting,
+			}
+
+			// Act
+			err := run(builder)
+
+			// Assert
+			var invalid *srvErrors.InvalidStateError
+			Expect(errors.As(err, &invalid)).To(BeTrue())
+			Expect(ran).To(Equal([]models.CollectorStateType{models.CollectorStateConnecting}))
+		})
+	})
+})
@@ -2,16 +2,22 @@ package services_test
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"errors"
+	"net/url"
+	"time"
 
+	duckdb_models "github.com/kubev2v/migration-planner/pkg/duckdb_parser/models"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/simulator"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/services"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
 	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 	"github.com/kubev2v/assisted-migration-agent/pkg/work"
 	"github.com/kubev2v/assisted-migration-agent/test"
 )
@@ -68,6 +74,25 @@ func mockCollectorBuilder(st *store.Store, eventSrv *services.EventService, conn
 	}
 }
 
+// blockingValidator reports its first call on entered and then holds every
+// call until release is closed.
+type blockingValidator struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (v *blockingValidator) Validate(ctx context.Context, _ duckdb_models.VM) ([]duckdb_models.Concern, error) {
+	select {
+	case v.entered <- struct{}{}:
+	default:
+	}
+	select {
+	case <-v.release:
+	case <-ctx.Done():
+	}
+	return nil, nil
+}
+
 func blockingCollectorBuilder(gate chan struct{}) func(models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
 	return func(_ models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
 		return work.NewSliceWorkBuilder([]work.WorkUnit[models.CollectorStatus, models.CollectorResult]{
@@ -147,6 +172,22 @@ var _ = Describe("CollectorService", func() {
 			// Assert
 			Expect(status.State).To(Equal(models.CollectorStateReady))
 		})
+
+		// Given a stored inventory whose collection raised warnings
+		// When GetStatus is called
+		// Then it should return collected along with those warnings
+		It("should return the warnings of the last collection", func() {
+			// Arrange
+			Expect(st.Inventory().Save(ctx, []byte(`{"vms":[]}`))).To(Succeed())
+			Expect(st.Inventory().SaveWarnings(ctx, []string{"VM snapshots were not collected: timeout"})).To(Succeed())
+
+			// Act
+			status := srv.GetStatus()
+
+			// Assert
+			Expect(status.State).To(Equal(models.CollectorStateCollected))
+			Expect(status.Warnings).To(Equal([]string{"VM snapshots were not collected: timeout"}))
+		})
 	})
 
 	Context("Stop", func() {
@@ -190,6 +231,51 @@ var _ = Describe("CollectorService", func() {
 			Expect(inv).ToNot(BeNil())
 		})
 
+		// Given a collection whose parsing step fails to validate a VM
+		// When Start is called and the collection completes
+		// Then the state should be collected and the failure reported as a warning
+		It("should reach collected with the warnings of the collection", func() {
+			// Arrange
+			warning := "VM web-01 (vm-1) could not be validated: policy engine unavailable"
+			srv = services.NewCollectorService(invSrv, func(_ models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+				unit := func(state models.CollectorStateType, fn func(context.Context, models.CollectorResult) (models.CollectorResult, error)) work.WorkUnit[models.CollectorStatus, models.CollectorResult] {
+					return work.WorkUnit[models.CollectorStatus, models.CollectorResult]{
+						Status: func() models.CollectorStatus { return models.CollectorStatus{State: state} },
+						Work:   fn,
+					}
+				}
+				pass := func(_ context.Context, r models.CollectorResult) (models.CollectorResult, error) { return r, nil }
+				return work.NewSliceWorkBuilder([]work.WorkUnit[models.CollectorStatus, models.CollectorResult]{
+					unit(models.CollectorStateConnecting, pass),
+					unit(models.CollectorStateCollecting, pass),
+					unit(models.CollectorStateParsing, func(ctx context.Context, r models.CollectorResult) (models.CollectorResult, error) {
+						r.Warnings = append(r.Warnings, warning)
+						r.Inventory = []byte(`{"vms":[]}`)
+						if err := st.Inventory().Save(ctx, r.Inventory); err != nil {
+							return r, err
+						}
+						return r, st.Inventory().SaveWarnings(ctx, r.Warnings)
+					}),
+					unit(models.CollectorStateCollected, pass),
+				})
+			})
+			creds := models.Credentials{
+				URL:      "https://vcenter.example.com",
+				Username: "admin",
+				Password: "secret",
+			}
+
+			// Act
+			err := srv.Start(ctx, creds)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func() models.CollectorStateType {
+				return srv.GetStatus().State
+			}).Should(Equal(models.CollectorStateCollected))
+			Expect(srv.GetStatus().Warnings).To(Equal([]string{warning}))
+		})
+
 		// Given a collector service with mock work units that succeed
 		// When Start is called and collection completes
 		// Then an inventory update event should be written to the outbox
@@ -348,6 +434,482 @@ var _ = Describe("CollectorService", func() {
 		})
 	})
 
+	Context("Phase timings", func() {
+		creds := models.Credentials{
+			URL:      "https://vcenter.example.com",
+			Username: "admin",
+			Password: "secret",
+		}
+
+		phaseStates := func(phases []models.CollectorPhase) []models.CollectorStateType {
+			states := make([]models.CollectorStateType, 0, len(phases))
+			for _, p := range phases {
+				states = append(states, p.State)
+			}
+			return states
+		}
+
+		// Given a collector service with mock work units that succeed
+		// When a collection runs to completion
+		// Then every phase should be timed, in order, with a non-negative duration
+		It("should record the duration of each phase", func() {
+			// Act
+			Expect(srv.Start(ctx, creds)).To(Succeed())
+
+			// Assert
+			Eventually(func() []models.CollectorPhase {
+				return srv.GetStatus().Phases
+			}).Should(HaveLen(4))
+
+			phases := srv.GetStatus().Phases
+			Expect(phaseStates(phases)).To(Equal([]models.CollectorStateType{
+				models.CollectorStateConnecting,
+				models.CollectorStateCollecting,
+				models.CollectorStateParsing,
+				models.CollectorStateCollected,
+			}))
+			for _, p := range phases {
+				Expect(p.Duration).To(BeNumerically(">=", 0))
+			}
+		})
+
+		// Given a collector service where the collect step fails
+		// When the collection ends in error
+		// Then the phases up to the failing one should be timed
+		It("should record the failing phase", func() {
+			// Arrange
+			srv = services.NewCollectorService(invSrv,
+				mockCollectorBuilder(st, eventSrv, nil, errors.New("collection failed"), nil))
+
+			// Act
+			Expect(srv.Start(ctx, creds)).To(Succeed())
+
+			// Assert
+			Eventually(func() models.CollectorStateType {
+				return srv.GetStatus().State
+			}).Should(Equal(models.CollectorStateError))
+			Expect(phaseStates(srv.GetStatus().Phases)).To(Equal([]models.CollectorStateType{
+				models.CollectorStateConnecting,
+				models.CollectorStateCollecting,
+			}))
+		})
+
+		// Given a collector service that never collected
+		// When we get its status
+		// Then it should carry no phases
+		It("should have no phases before the first collection", func() {
+			// Act
+			status := srv.GetStatus()
+
+			// Assert
+			Expect(status.Phases).To(BeEmpty())
+		})
+	})
+
+	Context("Recollect", func() {
+		// Given a collector service that was never started
+		// When Recollect is called
+		// Then it should return a credentials-not-set error
+		It("should fail without stored credentials", func() {
+			// Act
+			err := srv.Recollect(ctx)
+
+			// Assert
+			Expect(srvErrors.IsCredentialsNotSetError(err)).To(BeTrue())
+		})
+
+		// Given a collector service that has already collected
+		// When Recollect is called
+		// Then it should run a new collection despite the stored inventory
+		It("should collect again when inventory exists", func() {
+			// Arrange
+			Expect(srv.Start(ctx, models.Credentials{URL: "https://vcenter.example.com"})).To(Succeed())
+			Eventually(func() []models.Event {
+				events, _ := eventSrv.Events(ctx)
+				return events
+			}).Should(HaveLen(1))
+
+			// Act
+			Eventually(func() error {
+				return srv.Recollect(ctx)
+			}).Should(Succeed())
+
+			// Assert
+			Eventually(func() []models.Event {
+				events, _ := eventSrv.Events(ctx)
+				return events
+			}).Should(HaveLen(2))
+		})
+	})
+
+	Context("Refresh", func() {
+		var creds models.Credentials
+
+		inventoryEvents := func() []models.Event {
+			events, _ := eventSrv.Events(ctx)
+			return events
+		}
+
+		BeforeEach(func() {
+			creds = models.Credentials{URL: "https://vcenter.example.com"}
+		})
+
+		// Given a collector service that was never started
+		// When Refresh is called
+		// Then it should collect like Start
+		It("should collect when nothing was collected", func() {
+			// Act
+			err := srv.Refresh(ctx, creds, time.Hour)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(inventoryEvents).Should(HaveLen(1))
+		})
+
+		// Given an inventory collected just now
+		// When Refresh is called with a one hour window
+		// Then it should keep the inventory and report when it was collected
+		It("should be a no-op within the freshness window", func() {
+			// Arrange
+			Expect(srv.Start(ctx, creds)).To(Succeed())
+			Eventually(inventoryEvents).Should(HaveLen(1))
+			Eventually(func() models.CollectorStateType {
+				return srv.GetStatus().State
+			}).Should(Equal(models.CollectorStateCollected))
+
+			// Act
+			err := srv.Refresh(ctx, creds, time.Hour)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Consistently(inventoryEvents, 200*time.Millisecond).Should(HaveLen(1))
+			status := srv.GetStatus()
+			Expect(status.State).To(Equal(models.CollectorStateCollected))
+			Expect(status.CollectedAt).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+
+		// Given an inventory collected two hours ago
+		// When Refresh is called with a one hour window
+		// Then it should collect again
+		It("should recollect outside the freshness window", func() {
+			// Arrange
+			Expect(srv.Start(ctx, creds)).To(Succeed())
+			Eventually(inventoryEvents).Should(HaveLen(1))
+			Eventually(func() models.CollectorStateType {
+				return srv.GetStatus().State
+			}).Should(Equal(models.CollectorStateCollected))
+			takenAt := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Microsecond)
+			Expect(st.Inventory().AppendHistory(ctx, []byte(`{"vms":[]}`), takenAt, 0)).To(Succeed())
+			Expect(srv.GetStatus().CollectedAt).To(BeTemporally("==", takenAt))
+
+			// Act
+			err := srv.Refresh(ctx, creds, time.Hour)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(inventoryEvents).Should(HaveLen(2))
+		})
+	})
+
+	Context("Revalidate", func() {
+		// Given a collection that is still running
+		// When Revalidate is called
+		// Then it should return a collection-in-progress error
+		It("should fail while a collection is running", func() {
+			// Arrange
+			gate := make(chan struct{})
+			defer close(gate)
+
+			srv = services.NewCollectorService(invSrv, blockingCollectorBuilder(gate))
+			Expect(srv.Start(ctx, models.Credentials{URL: "https://vcenter.example.com"})).To(Succeed())
+
+			// Act
+			_, err := srv.Revalidate(ctx)
+
+			// Assert
+			Expect(srvErrors.IsOperationInProgressError(err)).To(BeTrue())
+		})
+
+		// Given a collector service that was never started
+		// When Revalidate is called
+		// Then it should return a not-found error
+		It("should fail when nothing was collected", func() {
+			// Act
+			_, err := srv.Revalidate(ctx)
+
+			// Assert
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+
+		// Given a revalidation whose policies are still running
+		// When a recollection is requested meanwhile
+		// Then the recollection should start without waiting for the policies,
+		// and the revalidation should then refuse to overwrite its VMs
+		It("should validate without holding the collector lock", func() {
+			// Arrange
+			validator := &blockingValidator{entered: make(chan struct{}, 1), release: make(chan struct{})}
+			// Released before AfterEach stops the service, even on failure.
+			defer func() {
+				select {
+				case <-validator.release:
+				default:
+					close(validator.release)
+				}
+			}()
+			st = store.NewStore(db, validator)
+			invSrv = services.NewInventoryService(st)
+			srv = services.NewCollectorService(invSrv, mockCollectorBuilder(st, eventSrv, nil, nil, nil))
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			Expect(st.Inventory().Save(ctx, []byte(`{"vms":[]}`))).To(Succeed())
+
+			revalidated := make(chan error, 1)
+			go func() {
+				_, err := srv.Revalidate(ctx)
+				revalidated <- err
+			}()
+			Eventually(validator.entered).Should(Receive())
+
+			// Act
+			refreshed := make(chan error, 1)
+			go func() {
+				refreshed <- srv.Refresh(ctx, models.Credentials{URL: "https://vcenter.example.com"}, time.Nanosecond)
+			}()
+
+			// Assert
+			Eventually(refreshed).Should(Receive(BeNil()))
+			Eventually(func() models.CollectorStateType {
+				return srv.GetStatus().State
+			}).Should(Equal(models.CollectorStateCollected))
+
+			close(validator.release)
+			var err error
+			Eventually(revalidated).Should(Receive(&err))
+			Expect(srvErrors.IsOperationInProgressError(err)).To(BeTrue())
+		})
+	})
+
+	Context("WithCredentialsStore", func() {
+		// Given a collector that stored its credentials encrypted
+		// When a new collector on the same store recollects (simulating a restart)
+		// Then it should reuse the stored credentials
+		It("should recollect with credentials stored before a restart", func() {
+			// Arrange
+			creds := models.Credentials{
+				URL:      "https://vcenter.example.com",
+				Username: "admin",
+				Password: "secret",
+			}
+			srv.WithCredentialsStore(st.Credentials().WithSecret("agent-secret"))
+			Expect(srv.Start(ctx, creds)).To(Succeed())
+			Eventually(func() []models.Event {
+				events, _ := eventSrv.Events(ctx)
+				return events
+			}).Should(HaveLen(1))
+
+			var used models.Credentials
+			restarted := services.NewCollectorService(invSrv, func(c models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+				used = c
+				return mockCollectorBuilder(st, eventSrv, nil, nil, nil)(c)
+			}).WithCredentialsStore(st.Credentials())
+			DeferCleanup(restarted.Stop)
+
+			// Act
+			err := restarted.Recollect(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(used).To(Equal(creds))
+		})
+	})
+
+	Context("Resume", func() {
+		// Given no collection was in flight when the agent stopped
+		// When the collector resumes
+		// Then nothing should be restarted and the collector should be ready
+		It("should do nothing without an interrupted collection", func() {
+			// Arrange
+			srv.WithStateStore(st.CollectorState())
+
+			// Act
+			restarted, err := srv.Resume(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restarted).To(BeFalse())
+			Expect(srv.GetStatus().State).To(Equal(models.CollectorStateReady))
+		})
+
+		// Given a collection left in collecting by a restart and stored credentials
+		// When the collector resumes
+		// Then it should restart the collection with those credentials and
+		// clear the persisted state once collected
+		It("should restart an interrupted collection with the stored credentials", func() {
+			// Arrange
+			creds := models.Credentials{URL: "https://vcenter.example.com", Username: "admin", Password: "secret"}
+			credentials := st.Credentials().WithSecret("agent-secret")
+			Expect(credentials.Save(ctx, creds)).To(Succeed())
+			Expect(st.CollectorState().Save(ctx, models.CollectorStateCollecting)).To(Succeed())
+
+			var used models.Credentials
+			srv = services.NewCollectorService(invSrv, func(c models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+				used = c
+				return mockCollectorBuilder(st, eventSrv, nil, nil, nil)(c)
+			}).WithCredentialsStore(credentials).WithStateStore(st.CollectorState())
+
+			// Act
+			restarted, err := srv.Resume(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restarted).To(BeTrue())
+			Eventually(func() models.CollectorStateType {
+				return srv.GetStatus().State
+			}).Should(Equal(models.CollectorStateCollected))
+			Expect(used).To(Equal(creds))
+			Eventually(func() bool {
+				_, err := st.CollectorState().Get(ctx)
+				return srvErrors.IsResourceNotFoundError(err)
+			}).Should(BeTrue())
+		})
+
+		// Given a collection left in collecting by a restart and no stored credentials
+		// When the collector resumes, and again after a second restart
+		// Then it should report the error state with an explanation until a new
+		// collection starts
+		It("should move an interrupted collection to error without credentials", func() {
+			// Arrange
+			Expect(st.CollectorState().Save(ctx, models.CollectorStateCollecting)).To(Succeed())
+			srv.WithStateStore(st.CollectorState())
+
+			// Act
+			restarted, err := srv.Resume(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restarted).To(BeFalse())
+			status := srv.GetStatus()
+			Expect(status.State).To(Equal(models.CollectorStateError))
+			Expect(status.Error).To(MatchError(ContainSubstring("interrupted by an agent restart")))
+
+			state, err := st.CollectorState().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(models.CollectorStateError))
+
+			again := services.NewCollectorService(invSrv, mockCollectorBuilder(st, eventSrv, nil, nil, nil)).
+				WithStateStore(st.CollectorState())
+			DeferCleanup(again.Stop)
+			_, err = again.Resume(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again.GetStatus().State).To(Equal(models.CollectorStateError))
+
+			Expect(again.Start(ctx, models.Credentials{URL: "https://vcenter.example.com"})).To(Succeed())
+			Eventually(func() models.CollectorStateType {
+				return again.GetStatus().State
+			}).Should(Equal(models.CollectorStateCollected))
+		})
+
+		// Given a running collection
+		// When it is stopped
+		// Then its persisted state should be cleared, so it is not resumed
+		It("should not resume a stopped collection", func() {
+			// Arrange
+			gate := make(chan struct{})
+			defer close(gate)
+			srv = services.NewCollectorService(invSrv, blockingCollectorBuilder(gate)).
+				WithStateStore(st.CollectorState())
+			Expect(srv.Start(ctx, models.Credentials{URL: "https://vcenter.example.com"})).To(Succeed())
+			Eventually(func() error {
+				_, err := st.CollectorState().Get(ctx)
+				return err
+			}).Should(Succeed())
+
+			// Act
+			srv.Stop()
+
+			// Assert
+			_, err := st.CollectorState().Get(ctx)
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+	})
+
+	Context("TestConnection", func() {
+		var vcURL string
+
+		BeforeEach(func() {
+			model := simulator.VPX()
+			Expect(model.Create()).To(Succeed())
+			model.Service.Listen = &url.URL{User: url.UserPassword("user", "pass")}
+			// Serve HTTPS with vcsim's self-signed certificate.
+			model.Service.TLS = new(tls.Config)
+			server := model.Service.NewServer()
+			DeferCleanup(model.Remove)
+			DeferCleanup(server.Close)
+			vcURL = server.URL.Scheme + "://" + server.URL.Host + "/sdk"
+		})
+
+		// Given a vcsim vCenter and valid credentials
+		// When we test the connection
+		// Then it should return the product information without starting a collection
+		It("should return vCenter information for valid credentials", func() {
+			// Act
+			info, err := srv.TestConnection(ctx, models.Credentials{URL: vcURL, Username: "user", Password: "pass", Insecure: true})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Product).To(ContainSubstring("vCenter"))
+			Expect(info.Version).NotTo(BeEmpty())
+			Expect(info.APIVersion).NotTo(BeEmpty())
+			Expect(srv.GetStatus().State).To(Equal(models.CollectorStateReady))
+		})
+
+		// Given a vcsim vCenter and a wrong password
+		// When we test the connection
+		// Then it should return a credentials error
+		It("should return a credentials error for bad credentials", func() {
+			// Act
+			_, err := srv.TestConnection(ctx, models.Credentials{URL: vcURL, Username: "user", Password: "wrong", Insecure: true})
+
+			// Assert
+			Expect(srvErrors.IsCredentialsError(err)).To(BeTrue())
+			Expect(srvErrors.IsVCenterError(err)).To(BeFalse())
+		})
+
+		// Given a vcsim vCenter with a self-signed certificate
+		// When we test the connection without opting into insecure TLS
+		// Then it should return a certificate error
+		It("should return a certificate error for an untrusted certificate", func() {
+			// Act
+			_, err := srv.TestConnection(ctx, models.Credentials{URL: vcURL, Username: "user", Password: "pass"})
+
+			// Assert
+			Expect(srvErrors.IsVCenterCertificateError(err)).To(BeTrue())
+			Expect(err.Error()).To(HavePrefix("vCenter certificate not trusted"))
+		})
+
+		// Given a URL where no vCenter listens
+		// When we test the connection
+		// Then it should return a vCenter error, not a credentials error
+		It("should return a vCenter error when vCenter is unreachable", func() {
+			// Act
+			_, err := srv.TestConnection(ctx, models.Credentials{URL: "https://127.0.0.1:1/sdk", Username: "user", Password: "pass", Insecure: true})
+
+			// Assert
+			Expect(srvErrors.IsVCenterError(err)).To(BeTrue())
+			Expect(srvErrors.IsCredentialsError(err)).To(BeFalse())
+		})
+
+		// Given a malformed URL
+		// When we test the connection
+		// Then it should return a validation error
+		It("should return a validation error for a malformed URL", func() {
+			// Act
+			_, err := srv.TestConnection(ctx, models.Credentials{URL: "not a url", Username: "user", Password: "pass", Insecure: true})
+
+			// Assert
+			Expect(srvErrors.IsValidationError(err)).To(BeTrue())
+		})
+	})
+
 	Context("NewCollectorService with existing inventory", func() {
 		// Given a store that already has inventory data
 		// When a new CollectorService is created
@@ -365,6 +927,109 @@ var _ = Describe("CollectorService", func() {
 		})
 	})
 
+	Context("WaitStatus", func() {
+		creds := models.Credentials{
+			URL:      "https://vcenter.example.com",
+			Username: "admin",
+			Password: "secret",
+		}
+
+		// Given a collection blocked while connecting
+		// When a caller waits for a change from connecting and the next unit starts
+		// Then the caller should wake up with the collecting state
+		It("should wake a waiting caller on a state change", func() {
+			// Arrange
+			gate := make(chan struct{})
+			srv = services.NewCollectorService(invSrv, func(_ models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
+				return work.NewSliceWorkBuilder([]work.WorkUnit[models.CollectorStatus, models.CollectorResult]{
+					{
+						Status: func() models.CollectorStatus {
+							return models.CollectorStatus{State: models.CollectorStateConnecting}
+						},
+						Work: func(ctx context.Context, r models.CollectorResult) (models.CollectorResult, error) {
+							<-gate
+							return r, nil
+						},
+					},
+					{
+						Status: func() models.CollectorStatus {
+							return models.CollectorStatus{State: models.CollectorStateCollecting}
+						},
+						Work: func(ctx context.Context, r models.CollectorResult) (models.CollectorResult, error) {
+							<-ctx.Done()
+							return r, ctx.Err()
+						},
+					},
+				})
+			})
+			Expect(srv.Start(ctx, creds)).To(Succeed())
+			Expect(srv.GetStatus().State).To(Equal(models.CollectorStateConnecting))
+
+			result := make(chan models.CollectorStatus, 1)
+			go func() {
+				result <- srv.WaitStatus(ctx, models.CollectorStateConnecting, time.Minute)
+			}()
+			Consistently(result, 100*time.Millisecond).ShouldNot(Receive())
+
+			// Act
+			close(gate)
+
+			// Assert
+			var status models.CollectorStatus
+			Eventually(result, 5*time.Second).Should(Receive(&status))
+			Expect(status.State).To(Equal(models.CollectorStateCollecting))
+		})
+
+		// Given a running collection
+		// When a caller waits for a change and the collection is stopped
+		// Then the caller should wake up with the ready state
+		It("should wake a waiting caller when the collection stops", func() {
+			// Arrange
+			srv = services.NewCollectorService(invSrv, blockingCollectorBuilder(make(chan struct{})))
+			Expect(srv.Start(ctx, creds)).To(Succeed())
+
+			result := make(chan models.CollectorStatus, 1)
+			go func() {
+				result <- srv.WaitStatus(ctx, models.CollectorStateConnecting, time.Minute)
+			}()
+			Consistently(result, 100*time.Millisecond).ShouldNot(Receive())
+
+			// Act
+			srv.Stop()
+
+			// Assert
+			var status models.CollectorStatus
+			Eventually(result, 5*time.Second).Should(Receive(&status))
+			Expect(status.State).To(Equal(models.CollectorStateReady))
+		})
+
+		// Given an idle collector
+		// When a caller waits for a change from ready
+		// Then it should return the unchanged state after the timeout
+		It("should return the unchanged state on timeout", func() {
+			// Act
+			start := time.Now()
+			status := srv.WaitStatus(ctx, models.CollectorStateReady, 200*time.Millisecond)
+
+			// Assert
+			Expect(status.State).To(Equal(models.CollectorStateReady))
+			Expect(time.Since(start)).To(BeNumerically(">=", 200*time.Millisecond))
+		})
+
+		// Given a caller that last saw a different state
+		// When it waits
+		// Then it should return the current state immediately
+		It("should return immediately when the state already differs", func() {
+			// Act
+			start := time.Now()
+			status := srv.WaitStatus(ctx, models.CollectorStateCollecting, time.Minute)
+
+			// Assert
+			Expect(status.State).To(Equal(models.CollectorStateReady))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+	})
+
 	Context("Stop cancellation", func() {
 		// Given a collector service with a blocking work unit that is running
 		// When Stop is called
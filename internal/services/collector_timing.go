@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/pkg/work"
+)
+
+// phaseTimings holds the phase durations of one collection. The work units
+// append to it while GetStatus reads it, hence the mutex.
+type phaseTimings struct {
+	mu     sync.Mutex
+	phases []models.CollectorPhase
+}
+
+func (t *phaseTimings) add(phase models.CollectorPhase) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.phases = append(t.phases, phase)
+}
+
+// list returns a copy of the recorded phases, oldest first.
+func (t *phaseTimings) list() []models.CollectorPhase {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.phases)
+}
+
+// timingWorkBuilder wraps a collection builder and records how long each unit
+// took, named after the state it runs in. A failing unit is recorded too, so
+// the time spent before the failure shows up.
+type timingWorkBuilder struct {
+	inner   work.WorkBuilder[models.CollectorStatus, models.CollectorResult]
+	timings *phaseTimings
+}
+
+func (b *timingWorkBuilder) Next() (collectorWorkUnit, bool) {
+	unit, ok := b.inner.Next()
+	if !ok {
+		return unit, false
+	}
+
+	state := unit.Status().State
+	fn := unit.Work
+	unit.Work = func(ctx context.Context, result models.CollectorResult) (models.CollectorResult, error) {
+		start := time.Now()
+		result, err := fn(ctx, result)
+		b.timings.add(models.CollectorPhase{State: state, Duration: time.Since(start)})
+		return result, err
+	}
+	return unit, true
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/pkg/work"
+)
+
+var _ = Describe("timingWorkBuilder", func() {
+	unit := func(state models.CollectorStateType, d time.Duration, err error) collectorWorkUnit {
+		return collectorWorkUnit{
+			Status: func() models.CollectorStatus { return models.CollectorStatus{State: state} },
+			Work: func(_ context.Context, r models.CollectorResult) (models.CollectorResult, error) {
+				time.Sleep(d)
+				return r, err
+			},
+		}
+	}
+
+	run := func(builder *timingWorkBuilder) error {
+		for {
+			u, ok := builder.Next()
+			if !ok {
+				return nil
+			}
+			if _, err := u.Work(context.Background(), models.CollectorResult{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Given a pipeline whose collecting unit takes a while
+	// When every unit runs
+	// Then each unit should be timed under its state, at least as long as it ran
+	It("should time each unit", func() {
+		// Arrange
+		timings := &phaseTimings{}
+		builder := &timingWorkBuilder{
+			inner: work.NewSliceWorkBuilder([]collectorWorkUnit{
+				unit(models.CollectorStateConnecting, 0, nil),
+				unit(models.CollectorStateCollecting, 20*time.Millisecond, nil),
+			}),
+			timings: timings,
+		}
+
+		// Act
+		err := run(builder)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		phases := timings.list()
+		Expect(phases).To(HaveLen(2))
+		Expect(phases[0].State).To(Equal(models.CollectorStateConnecting))
+		Expect(phases[0].Duration).To(BeNumerically(">=", 0))
+		Expect(phases[1].State).To(Equal(models.CollectorStateCollecting))
+		Expect(phases[1].Duration).To(BeNumerically(">=", 20*time.Millisecond))
+	})
+
+	// Given a pipeline whose second unit fails
+	// When the units run
+	// Then the failing unit should be timed and the rest never run
+	It("should time a failing unit", func() {
+		// Arrange
+		timings := &phaseTimings{}
+		builder := &timingWorkBuilder{
+			inner: work.NewSliceWorkBuilder([]collectorWorkUnit{
+				unit(models.CollectorStateConnecting, 0, nil),
+				unit(models.CollectorStateCollecting, 0, errors.New("collection failed")),
+				unit(models.CollectorStateParsing, 0, nil),
+			}),
+			timings: timings,
+		}
+
+		// Act
+		err := run(builder)
+
+		// Assert
+		Expect(err).To(MatchError("collection failed"))
+		phases := timings.list()
+		Expect(phases).To(HaveLen(2))
+		Expect(phases[1].State).To(Equal(models.CollectorStateCollecting))
+	})
+})
@@ -2,21 +2,30 @@ package services
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	"github.com/kubev2v/migration-planner/pkg/duckdb_parser"
+	duckdb_models "github.com/kubev2v/migration-planner/pkg/duckdb_parser/models"
 	"github.com/kubev2v/migration-planner/pkg/inventory/converters"
 
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
 	collector "github.com/kubev2v/assisted-migration-agent/pkg/collector"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/pkg/scheduler"
+	"github.com/kubev2v/assisted-migration-agent/pkg/vmware"
 	"github.com/kubev2v/assisted-migration-agent/pkg/work"
 )
 
@@ -25,6 +34,11 @@ type collectorWorkFactory struct {
 	eventSrv       *EventService
 	dataDir        string
 	opaPoliciesDir string
+	sessions       *vmware.SessionCache
+	retention      int
+	tiers          collectorV1.Tiers
+	categories     collectorV1.ConcernCategories
+	concurrency    int
 }
 
 func newCollectorWorkFactory(st *store.Store, eventSrv *EventService, dataDir, opaPoliciesDir string) *collectorWorkFactory {
@@ -33,9 +47,48 @@ func newCollectorWorkFactory(st *store.Store, eventSrv *EventService, dataDir, o
 		eventSrv:       eventSrv,
 		dataDir:        dataDir,
 		opaPoliciesDir: opaPoliciesDir,
+		retention:      1,
+		concurrency:    1,
 	}
 }
 
+// withSessionCache makes the credential check log in through sessions, leaving
+// the session cached for an inspection that follows.
+func (f *collectorWorkFactory) withSessionCache(sessions *vmware.SessionCache) *collectorWorkFactory {
+	f.sessions = sessions
+	return f
+}
+
+// withInventoryRetention sets how many previous inventories are kept in the
+// history besides the current one.
+func (f *collectorWorkFactory) withInventoryRetention(previous int) *collectorWorkFactory {
+	f.retention = previous
+	return f
+}
+
+// withCollectionConcurrency sets how many of the vCenter object lists the
+// forklift collector does not keep are fetched at the same time.
+func (f *collectorWorkFactory) withCollectionConcurrency(n int) *collectorWorkFactory {
+	if n > 0 {
+		f.concurrency = n
+	}
+	return f
+}
+
+// withTiers replaces the parser's disk size, CPU and memory tier buckets
+// that tiers sets.
+func (f *collectorWorkFactory) withTiers(tiers collectorV1.Tiers) *collectorWorkFactory {
+	f.tiers = tiers
+	return f
+}
+
+// withConcernCategories counts the concern categories that categories maps
+// in the inventory's migration issues and totals as it says.
+func (f *collectorWorkFactory) withConcernCategories(categories collectorV1.ConcernCategories) *collectorWorkFactory {
+	f.categories = categories
+	return f
+}
+
 func (f *collectorWorkFactory) Build(creds models.Credentials) work.WorkBuilder[models.CollectorStatus, models.CollectorResult] {
 	return work.NewSliceWorkBuilder([]collectorWorkUnit{
 		{
@@ -57,6 +110,11 @@ func (f *collectorWorkFactory) Build(creds models.Credentials) work.WorkBuilder[
 					return r, err
 				}
 				r.SQLitePath = sqlitePath
+				r, errs := f.listVCenterObjects(ctx, creds, r, f.vcenterListings())
+				// Each list only adds detail, so a failure is a warning.
+				for _, e := range errs {
+					r.Warnings = append(r.Warnings, e.Error())
+				}
 				return r, nil
 			},
 		},
@@ -64,14 +122,7 @@ func (f *collectorWorkFactory) Build(creds models.Credentials) work.WorkBuilder[
 			Status: func() models.CollectorStatus {
 				return models.CollectorStatus{State: models.CollectorStateParsing}
 			},
-			Work: func(ctx context.Context, r models.CollectorResult) (models.CollectorResult, error) {
-				inv, err := f.process(ctx, r.SQLitePath)
-				if err != nil {
-					return r, err
-				}
-				r.Inventory = inv
-				return r, nil
-			},
+			Work: f.process,
 		},
 		{
 			Status: func() models.CollectorStatus {
@@ -88,15 +139,17 @@ func (f *collectorWorkFactory) Build(creds models.Credentials) work.WorkBuilder[
 }
 
 func (f *collectorWorkFactory) verifyCredentials(ctx context.Context, cred models.Credentials) error {
-	dbPath := path.Join(f.dataDir, fmt.Sprintf("%s.db", uuid.New()))
-	vc := collector.NewVSphereCollector(dbPath)
-	defer vc.Close()
+	if cred.Insecure {
+		zap.S().Named("collector_service").Warnw("vCenter TLS certificate verification is disabled for this collection", "url", cred.URL)
+	}
 
 	zap.S().Named("collector_service").Info("verifying vCenter credentials")
-	if err := vc.VerifyCredentials(ctx, &cred); err != nil {
+	_, release, err := f.sessions.Acquire(ctx, cred)
+	if err != nil {
 		zap.S().Named("collector_service").Errorw("credential verification failed", "error", err)
-		return err
+		return srvErrors.NewVCenterError(err)
 	}
+	release()
 	zap.S().Named("collector_service").Info("vCenter credentials verified")
 	return nil
 }
@@ -116,53 +169,166 @@ func (f *collectorWorkFactory) collect(ctx context.Context, creds models.Credent
 	return dbPath, nil
 }
 
-func (f *collectorWorkFactory) process(ctx context.Context, sqlitePath string) ([]byte, error) {
-	zap.S().Named("collector_service").Info("parsing collected data into duckdb")
+// vcenterListing fetches one kind of vCenter object the forklift collector
+// does not keep. list returns a function storing the objects in the result, so
+// listings can run concurrently and still be stored in a fixed order.
+type vcenterListing struct {
+	what string // what is listed, for the warning when it fails
+	list func(ctx context.Context, creds models.Credentials) (func(*models.CollectorResult), error)
+}
 
-	if _, err := os.Stat(sqlitePath); err != nil {
-		zap.S().Named("collector_service").Errorw("sqlite file not accessible", "path", sqlitePath, "error", err)
-		return nil, err
+func (f *collectorWorkFactory) vcenterListings() []vcenterListing {
+	return []vcenterListing{
+		{
+			what: "VM snapshots and creation dates",
+			list: func(ctx context.Context, creds models.Credentials) (func(*models.CollectorResult), error) {
+				details, err := f.collectVMDetails(ctx, creds)
+				if err != nil {
+					return nil, err
+				}
+				return func(r *models.CollectorResult) {
+					r.Snapshots = details.Snapshots
+					r.CreateDates = details.CreateDates
+				}, nil
+			},
+		},
 	}
-	zap.S().Named("collector_service").Debugw("sqlite file ready", "path", sqlitePath)
+}
 
-	result, err := f.store.Parser().IngestSqlite(ctx, sqlitePath)
+// listVCenterObjects runs listings through a scheduler, f.concurrency at a
+// time, and stores their objects in r in the order of listings, whichever
+// finishes first. A failed listing does not stop the others: its objects are
+// left out and its error, naming what was not collected, is returned with the
+// errors of the other failed listings.
+func (f *collectorWorkFactory) listVCenterObjects(ctx context.Context, creds models.Credentials, r models.CollectorResult, listings []vcenterListing) (models.CollectorResult, []error) {
+	sched, err := scheduler.NewScheduler[func(*models.CollectorResult)](f.concurrency, 0)
 	if err != nil {
-		zap.S().Named("collector_service").Errorw("failed to ingest sqlite data", "error", err)
-		return nil, err
+		return r, []error{err}
 	}
+	defer sched.Close()
 
-	if err := f.store.Checkpoint(); err != nil {
-		zap.S().Named("collector_service").Warnw("checkpoint after ingest failed", "error", err)
+	futures := make([]*scheduler.Future[scheduler.Result[func(*models.CollectorResult)]], 0, len(listings))
+	for _, l := range listings {
+		futures = append(futures, sched.AddWork(func(context.Context) (func(*models.CollectorResult), error) {
+			return l.list(ctx, creds)
+		}))
 	}
 
-	if result.HasErrors() {
-		zap.S().Named("collector_service").Errorw("schema validation errors", "errors", result.Errors)
-		return nil, fmt.Errorf("schema validation failed: %v", result.Errors)
+	var errs []error
+	for i, future := range futures {
+		result := <-future.C()
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s were not collected: %w", listings[i].what, result.Err))
+			continue
+		}
+		result.Data(&r)
 	}
+	return r, errs
+}
 
-	if len(result.Warnings) > 0 {
-		zap.S().Named("collector_service").Warnw("schema validation warnings", "warnings", result.Warnings)
+// collectVMDetails reads the VM snapshot trees and creation dates, which the
+// forklift collector does not keep, in one walk of the vCenter VMs. They only
+// add detail to the inventory, so a failure is logged, returned for the caller
+// to record as a warning, and the collection goes on without them.
+func (f *collectorWorkFactory) collectVMDetails(ctx context.Context, creds models.Credentials) (*vmware.VMDetails, error) {
+	client, release, err := f.sessions.Acquire(ctx, creds)
+	if err != nil {
+		zap.S().Named("collector_service").Warnw("failed to connect for VM snapshots and creation dates, continuing without them", "error", err)
+		return nil, err
 	}
+	defer release()
 
-	zap.S().Named("collector_service").Info("data successfully parsed into duckdb")
+	details, err := vmware.ListVMDetails(ctx, client.Client)
+	if err != nil {
+		zap.S().Named("collector_service").Warnw("failed to list VM snapshots and creation dates, continuing without them", "error", err)
+		return nil, err
+	}
+	zap.S().Named("collector_service").Infow("VM snapshots and creation dates collected", "snapshots", len(details.Snapshots), "creation_dates", len(details.CreateDates))
 
-	if err := os.Remove(sqlitePath); err != nil {
-		zap.S().Named("collector_service").Warnw("failed to remove sqlite file", "path", sqlitePath, "error", err)
+	return details, nil
+}
+
+// process ingests the collected sqlite file, builds the inventory and saves
+// it along with the warnings raised so far and while parsing.
+func (f *collectorWorkFactory) process(ctx context.Context, r models.CollectorResult) (models.CollectorResult, error) {
+	zap.S().Named("collector_service").Info("parsing collected data into duckdb")
+
+	if _, err := os.Stat(r.SQLitePath); err != nil {
+		zap.S().Named("collector_service").Errorw("sqlite file not accessible", "path", r.SQLitePath, "error", err)
+		return r, err
 	}
+	zap.S().Named("collector_service").Debugw("sqlite file ready", "path", r.SQLitePath)
+
+	// The new collection is ingested into a staging database and replaces the
+	// current inventory only once it was parsed, validated and completed with
+	// snapshots and creation dates, so a failed ingest keeps the previous one.
+	err := f.store.ReplaceInventory(ctx, func(ctx context.Context, staged *store.Store) error {
+		// The parser only logs the VMs it fails to validate; record them so the
+		// collection reports them as warnings.
+		parser := staged.Parser()
+		var validator *recordingValidator
+		if v := staged.Validator(); v != nil {
+			validator = &recordingValidator{inner: v}
+			parser = duckdb_parser.New(staged.DB(), validator)
+		}
 
-	inv, err := f.store.Parser().BuildInventory(ctx)
+		result, err := parser.IngestSqlite(ctx, r.SQLitePath)
+		if err != nil {
+			zap.S().Named("collector_service").Errorw("failed to ingest sqlite data", "error", err)
+			return err
+		}
+		if validator != nil {
+			r.Warnings = append(r.Warnings, validator.warnings...)
+		}
+
+		switch {
+		case hasNoVMs(result):
+			zap.S().Named("collector_service").Info("vCenter has no VMs, building an empty inventory")
+		case result.HasErrors():
+			zap.S().Named("collector_service").Errorw("schema validation errors", "errors", result.Errors)
+			return fmt.Errorf("schema validation failed: %v", result.Errors)
+		}
+
+		if len(result.Warnings) > 0 {
+			zap.S().Named("collector_service").Warnw("schema validation warnings", "warnings", result.Warnings)
+			for _, w := range result.Warnings {
+				r.Warnings = append(r.Warnings, w.Message)
+			}
+		}
+
+		if err := staged.VM().InsertSnapshots(ctx, r.Snapshots); err != nil {
+			zap.S().Named("collector_service").Errorw("failed to store VM snapshots", "error", err)
+			return err
+		}
+
+		if err := staged.VM().InsertCreateDates(ctx, r.CreateDates); err != nil {
+			zap.S().Named("collector_service").Errorw("failed to store VM creation dates", "error", err)
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error building inventory: %w", err)
+		zap.S().Named("collector_service").Errorw("failed to replace the inventory", "error", err)
+		return r, err
+	}
+
+	zap.S().Named("collector_service").Info("data successfully parsed into duckdb")
+
+	if err := os.Remove(r.SQLitePath); err != nil {
+		zap.S().Named("collector_service").Warnw("failed to remove sqlite file", "path", r.SQLitePath, "error", err)
 	}
 
-	inventory, err := json.Marshal(converters.ToAPI(inv))
+	inventory, err := buildInventory(ctx, f.store, f.tiers, f.categories)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal the inventory: %w", err)
+		return r, err
 	}
 
-	if err := f.store.Inventory().Save(ctx, inventory); err != nil {
-		return nil, err
+	if err := f.saveInventory(ctx, inventory, r.Warnings); err != nil {
+		zap.S().Named("collector_service").Errorw("failed to save inventory", "error", err)
+		return r, err
 	}
+	r.Inventory = inventory
 
 	zap.S().Named("inventory").Info("successfully created inventory with clusters")
 
@@ -170,9 +336,185 @@ func (f *collectorWorkFactory) process(ctx context.Context, sqlitePath string) (
 		zap.S().Named("collector_service").Warnw("failed to create folder groups", "error", err)
 	}
 
+	return r, nil
+}
+
+// buildInventory builds the inventory sent to the console from the parsed
+// tables.
+func buildInventory(ctx context.Context, st *store.Store, tiers collectorV1.Tiers, categories collectorV1.ConcernCategories) ([]byte, error) {
+	inv, err := st.Parser().BuildInventory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error building inventory: %w", err)
+	}
+
+	apiInv := *converters.ToAPI(inv)
+	if !tiers.IsZero() {
+		apiInv, err = applyTiers(ctx, st, apiInv, tiers)
+		if err != nil {
+			return nil, fmt.Errorf("error computing tier distributions: %w", err)
+		}
+	}
+	if len(categories) > 0 {
+		apiInv, err = applyConcernCategories(ctx, st, apiInv, categories)
+		if err != nil {
+			return nil, fmt.Errorf("error counting concern categories: %w", err)
+		}
+	}
+
+	inventory, err := collectorV1.MarshalInventory(apiInv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the inventory: %w", err)
+	}
 	return inventory, nil
 }
 
+// ParseTiers reads the tier buckets set in the agent configuration. Unset
+// ones keep the parser's buckets.
+func ParseTiers(agent config.Agent) (collectorV1.Tiers, error) {
+	var tiers collectorV1.Tiers
+	var err error
+	if tiers.DiskSize, err = collectorV1.ParseDiskSizeTiers(agent.DiskSizeTiers); err != nil {
+		return tiers, fmt.Errorf("invalid disk-size-tiers: %w", err)
+	}
+	if tiers.CPU, err = collectorV1.ParseTierBounds(agent.CPUTiers); err != nil {
+		return tiers, fmt.Errorf("invalid cpu-tiers: %w", err)
+	}
+	if tiers.MemoryGB, err = collectorV1.ParseTierBounds(agent.MemoryTiers); err != nil {
+		return tiers, fmt.Errorf("invalid memory-tiers: %w", err)
+	}
+	return tiers, nil
+}
+
+// ParseConcernCategories reads the concern category mapping set in the agent
+// configuration. Unset, the parser's Critical and Warning categories are
+// used.
+func ParseConcernCategories(agent config.Agent) (collectorV1.ConcernCategories, error) {
+	categories, err := collectorV1.ParseConcernCategories(agent.ConcernCategories)
+	if err != nil {
+		return nil, fmt.Errorf("invalid concern-categories: %w", err)
+	}
+	return categories, nil
+}
+
+// applyTiers recomputes the tier distributions of inv that tiers sets.
+func applyTiers(ctx context.Context, st *store.Store, inv v1alpha1.Inventory, tiers collectorV1.Tiers) (v1alpha1.Inventory, error) {
+	byName, err := st.VM().SizesByCluster(ctx)
+	if err != nil {
+		return inv, err
+	}
+
+	clusterID := clusterIDs(ctx, st, inv.VcenterId)
+	var all []models.VMSize
+	byID := make(map[string][]models.VMSize, len(byName))
+	for name, sizes := range byName {
+		all = append(all, sizes...)
+		if name == "" {
+			continue
+		}
+		byID[clusterID(name)] = sizes
+	}
+
+	return collectorV1.ApplyTiers(inv, all, byID, tiers), nil
+}
+
+// applyConcernCategories recounts the migration issues and totals of inv with
+// the concern categories mapped by categories.
+func applyConcernCategories(ctx context.Context, st *store.Store, inv v1alpha1.Inventory, categories collectorV1.ConcernCategories) (v1alpha1.Inventory, error) {
+	byName, err := st.VM().ConcernsByCluster(ctx)
+	if err != nil {
+		return inv, err
+	}
+
+	clusterID := clusterIDs(ctx, st, inv.VcenterId)
+	var all []models.VMConcerns
+	byID := make(map[string][]models.VMConcerns, len(byName))
+	for name, vms := range byName {
+		all = append(all, vms...)
+		if name == "" {
+			continue
+		}
+		byID[clusterID(name)] = vms
+	}
+
+	return collectorV1.ApplyConcernCategories(inv, all, byID, categories), nil
+}
+
+// clusterIDs returns a function giving the ID the inventory keys a cluster
+// name by, resolved the way the parser does: the vCluster Object ID, or else
+// an ID derived from the vCenter, datacenter and cluster names.
+func clusterIDs(ctx context.Context, st *store.Store, vcenterID string) func(name string) string {
+	objectIDs, err := st.Parser().ClusterObjectIDs(ctx)
+	if err != nil {
+		objectIDs = map[string]string{}
+	}
+	datacenters, err := st.Parser().ClusterDatacenters(ctx)
+	if err != nil {
+		datacenters = map[string]string{}
+	}
+
+	return func(name string) string {
+		if id, ok := objectIDs[name]; ok {
+			return id
+		}
+		hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s", vcenterID, datacenters[name], name)))
+		return fmt.Sprintf("cluster-%x", hash[:8])
+	}
+}
+
+// hasNoVMs reports whether the only schema error is NO_VMS. The parser flags
+// an empty vinfo table as invalid, but a vCenter without VMs is a valid, empty
+// inventory.
+func hasNoVMs(result duckdb_parser.ValidationResult) bool {
+	if len(result.Errors) == 0 {
+		return false
+	}
+	for _, issue := range result.Errors {
+		if issue.Code != duckdb_parser.CodeNoVMs {
+			return false
+		}
+	}
+	return true
+}
+
+// recordingValidator wraps the store's validator and keeps a warning for each
+// VM it fails to validate. The parser calls it from one goroutine.
+type recordingValidator struct {
+	inner    duckdb_parser.Validator
+	warnings []string
+}
+
+func (v *recordingValidator) Validate(ctx context.Context, vm duckdb_models.VM) ([]duckdb_models.Concern, error) {
+	concerns, err := v.inner.Validate(ctx, vm)
+	if err != nil {
+		v.warnings = append(v.warnings, fmt.Sprintf("VM %s (%s) could not be validated: %v", vm.Name, vm.ID, err))
+	}
+	return concerns, err
+}
+
+// saveInventory stores the marshalled inventory and the collection warnings,
+// records the inventory in the history (GET /inventory/history) and rotates
+// the per-VM snapshot (kept for GET /inventory/diff) in one transaction, so a
+// failure leaves the previous inventory, warnings, history and snapshots
+// untouched. The parsed tables are replaced before this, in their own
+// transaction (see Store.ReplaceInventory).
+func (f *collectorWorkFactory) saveInventory(ctx context.Context, data []byte, warnings []string) error {
+	return f.store.WithTx(ctx, func(txCtx context.Context) error {
+		if err := f.store.Inventory().Save(txCtx, data); err != nil {
+			return fmt.Errorf("saving inventory: %w", err)
+		}
+		if err := f.store.Inventory().SaveWarnings(txCtx, warnings); err != nil {
+			return err
+		}
+		// DuckDB keeps microseconds; truncating lets the timestamp listed by
+		// the history fetch the same inventory back.
+		takenAt := time.Now().UTC().Truncate(time.Microsecond)
+		if err := f.store.Inventory().AppendHistory(txCtx, data, takenAt, f.retention); err != nil {
+			return err
+		}
+		return f.store.Inventory().RotateSnapshot(txCtx)
+	})
+}
+
 func (f *collectorWorkFactory) createFolderGroups(ctx context.Context) error {
 	folders, err := f.store.VM().GetFolders(ctx)
 	if err != nil {
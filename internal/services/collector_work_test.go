@@ -0,0 +1,538 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	duckdb_models "github.com/kubev2v/migration-planner/pkg/duckdb_parser/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/mo"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/pkg/vmware"
+	"github.com/kubev2v/assisted-migration-agent/pkg/work"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+var _ = Describe("collectorWorkFactory", func() {
+	Describe("verifyCredentials", func() {
+		var (
+			factory *collectorWorkFactory
+			creds   models.Credentials
+		)
+
+		BeforeEach(func() {
+			model := simulator.VPX()
+			Expect(model.Create()).To(Succeed())
+			model.Service.Listen = &url.URL{User: url.UserPassword("user", "pass")}
+			model.Service.TLS = new(tls.Config)
+			server := model.Service.NewServer()
+			DeferCleanup(model.Remove)
+			DeferCleanup(server.Close)
+
+			factory = newCollectorWorkFactory(nil, nil, GinkgoT().TempDir(), "")
+			creds = models.Credentials{
+				URL:      server.URL.Scheme + "://" + server.URL.Host + "/sdk",
+				Username: "user",
+				Password: "pass",
+			}
+		})
+
+		// Given a vCenter with a self-signed certificate
+		// When the collection connects without insecureSkipVerify
+		// Then it should fail with a certificate error
+		It("fails on a self-signed certificate by default", func() {
+			err := factory.verifyCredentials(context.Background(), creds)
+
+			Expect(srvErrors.IsVCenterCertificateError(err)).To(BeTrue())
+		})
+
+		// Given a vCenter with a self-signed certificate
+		// When the collection connects with insecureSkipVerify
+		// Then it should log in
+		It("connects to a self-signed certificate when insecureSkipVerify is set", func() {
+			creds.Insecure = true
+
+			Expect(factory.verifyCredentials(context.Background(), creds)).To(Succeed())
+		})
+
+		// Given a collection and an inspection sharing a session cache
+		// When the inspection sets the same credentials right after the collection
+		// Then it should reuse the collection's vCenter session
+		It("shares its vCenter session with a following inspection", func() {
+			// Arrange
+			creds.Insecure = true
+			sessions := vmware.NewSessionCache(time.Minute)
+			DeferCleanup(sessions.Close)
+			factory.withSessionCache(sessions)
+			inspector := NewInspectorService(nil, 10, "").WithSessionCache(sessions)
+
+			observer, err := vmware.NewVsphereClient(context.Background(), creds.URL, creds.Username, creds.Password, true)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(observer.Logout, context.Background())
+			self, err := observer.SessionManager.UserSession(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			activeSessions := func() []string {
+				var sm mo.SessionManager
+				err := property.DefaultCollector(observer.Client).RetrieveOne(context.Background(),
+					*observer.ServiceContent.SessionManager, []string{"sessionList"}, &sm)
+				Expect(err).NotTo(HaveOccurred())
+				var keys []string
+				for _, us := range sm.SessionList {
+					if us.Key != self.Key {
+						keys = append(keys, us.Key)
+					}
+				}
+				return keys
+			}
+
+			// Act
+			Expect(factory.verifyCredentials(context.Background(), creds)).To(Succeed())
+			collected := activeSessions()
+			Expect(inspector.Credentials(context.Background(), creds)).To(Succeed())
+
+			// Assert
+			Expect(collected).To(HaveLen(1))
+			Consistently(activeSessions, 200*time.Millisecond).Should(Equal(collected))
+		})
+	})
+
+	Describe("saveInventory", func() {
+		var (
+			ctx     context.Context
+			db      *sql.DB
+			st      *store.Store
+			factory *collectorWorkFactory
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+
+			var err error
+			db, err = store.NewDB(nil, ":memory:")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(db.Close)
+			Expect(migrations.Run(ctx, db)).To(Succeed())
+
+			st = store.NewStore(db, test.NewMockValidator())
+			factory = newCollectorWorkFactory(st, nil, GinkgoT().TempDir(), "")
+
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			Expect(factory.saveInventory(ctx, []byte(`{"collection":1}`), nil)).To(Succeed())
+		})
+
+		// Given a saved collection
+		// When a second collection is saved
+		// Then the inventory should be replaced and the first snapshot and inventory kept as previous
+		It("saves the inventory and rotates the snapshot", func() {
+			// Act
+			Expect(factory.saveInventory(ctx, []byte(`{"collection":2}`), []string{"warning"})).To(Succeed())
+
+			// Assert
+			inv, err := st.Inventory().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(inv.Data)).To(Equal(`{"collection":2}`))
+			_, err = st.Inventory().PreviousSnapshot(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			warnings, err := st.Inventory().Warnings(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(Equal([]string{"warning"}))
+
+			history, err := st.Inventory().History(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(2))
+			previous, err := st.Inventory().GetAt(ctx, history[1])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(previous.Data)).To(Equal(`{"collection":1}`))
+		})
+
+		// Given a saved collection and a snapshot rotation that fails after the inventory was written
+		// When a second collection is saved
+		// Then nothing of the second save should be kept
+		It("rolls back every write when a step fails", func() {
+			// Arrange
+			before, err := st.Inventory().CurrentSnapshot(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.ExecContext(ctx, "ALTER TABLE inventory_snapshots RENAME TO inventory_snapshots_off")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			err = factory.saveInventory(ctx, []byte(`{"collection":2}`), []string{"warning"})
+
+			// Assert
+			Expect(err).To(HaveOccurred())
+			_, err = db.ExecContext(ctx, "ALTER TABLE inventory_snapshots_off RENAME TO inventory_snapshots")
+			Expect(err).NotTo(HaveOccurred())
+
+			inv, err := st.Inventory().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(inv.Data)).To(Equal(`{"collection":1}`))
+
+			after, err := st.Inventory().CurrentSnapshot(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(after).To(Equal(before))
+			_, err = st.Inventory().PreviousSnapshot(ctx)
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+			warnings, err := st.Inventory().Warnings(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+			history, err := st.Inventory().History(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(1))
+		})
+	})
+
+	Describe("collectVMDetails", func() {
+		var (
+			factory *collectorWorkFactory
+			creds   models.Credentials
+		)
+
+		BeforeEach(func() {
+			model := simulator.VPX()
+			Expect(model.Create()).To(Succeed())
+			model.Service.Listen = &url.URL{User: url.UserPassword("user", "pass")}
+			server := model.Service.NewServer()
+			DeferCleanup(model.Remove)
+			DeferCleanup(server.Close)
+
+			factory = newCollectorWorkFactory(nil, nil, GinkgoT().TempDir(), "")
+			creds = models.Credentials{
+				URL:      server.URL.Scheme + "://" + server.URL.Host + "/sdk",
+				Username: "user",
+				Password: "pass",
+				Insecure: true,
+			}
+		})
+
+		// Given a vCenter VM with a snapshot
+		// When the collection reads the VM details
+		// Then it should return that snapshot and the VM creation date
+		It("returns the snapshots and creation dates of the vCenter VMs", func() {
+			// Arrange
+			ctx := context.Background()
+			client, err := vmware.NewVsphereClient(ctx, creds.URL, creds.Username, creds.Password, true)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(client.Logout, context.Background())
+			vm, err := find.NewFinder(client.Client).VirtualMachine(ctx, "DC0_H0_VM0")
+			Expect(err).NotTo(HaveOccurred())
+			task, err := vm.CreateSnapshot(ctx, "before-upgrade", "", false, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(task.Wait(ctx)).To(Succeed())
+
+			// Act
+			details, err := factory.collectVMDetails(ctx, creds)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(details.Snapshots).To(HaveLen(1))
+			Expect(details.Snapshots[0].VMID).To(Equal(vm.Reference().Value))
+			Expect(details.Snapshots[0].Name).To(Equal("before-upgrade"))
+			Expect(details.CreateDates).To(HaveKey(vm.Reference().Value))
+			Expect(details.CreateDates[vm.Reference().Value].IsZero()).To(BeFalse())
+		})
+
+		// Given a vCenter rejecting the credentials
+		// When the collection reads the VM details
+		// Then it should return none and the error to report as a warning
+		It("returns no details when vCenter cannot be reached", func() {
+			// Arrange
+			creds.Password = "wrong"
+
+			// Act
+			details, err := factory.collectVMDetails(context.Background(), creds)
+
+			// Assert
+			Expect(err).To(HaveOccurred())
+			Expect(details).To(BeNil())
+		})
+	})
+
+	Describe("listVCenterObjects", func() {
+		var (
+			factory *collectorWorkFactory
+			creds   models.Credentials
+		)
+
+		BeforeEach(func() {
+			model := simulator.VPX()
+			Expect(model.Create()).To(Succeed())
+			model.Service.Listen = &url.URL{User: url.UserPassword("user", "pass")}
+			server := model.Service.NewServer()
+			DeferCleanup(model.Remove)
+			DeferCleanup(server.Close)
+
+			factory = newCollectorWorkFactory(nil, nil, GinkgoT().TempDir(), "").withCollectionConcurrency(2)
+			creds = models.Credentials{
+				URL:      server.URL.Scheme + "://" + server.URL.Host + "/sdk",
+				Username: "user",
+				Password: "pass",
+				Insecure: true,
+			}
+		})
+
+		// Given a reachable vCenter
+		// When the collection lists the objects the forklift collector skips
+		// Then every object type should be populated
+		It("populates every object type", func() {
+			// Arrange
+			ctx := context.Background()
+			client, err := vmware.NewVsphereClient(ctx, creds.URL, creds.Username, creds.Password, true)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(client.Logout, context.Background())
+			vm, err := find.NewFinder(client.Client).VirtualMachine(ctx, "DC0_H0_VM0")
+			Expect(err).NotTo(HaveOccurred())
+			task, err := vm.CreateSnapshot(ctx, "before-upgrade", "", false, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(task.Wait(ctx)).To(Succeed())
+
+			// Act
+			r, errs := factory.listVCenterObjects(ctx, creds, models.CollectorResult{SQLitePath: "kept"}, factory.vcenterListings())
+
+			// Assert
+			Expect(errs).To(BeEmpty())
+			Expect(r.SQLitePath).To(Equal("kept"))
+			Expect(r.Snapshots).To(HaveLen(1))
+			Expect(r.CreateDates).To(HaveKey(vm.Reference().Value))
+		})
+
+		// Given listings that block until both have started
+		// When the collection lists them with a concurrency of 2
+		// Then they should run at the same time
+		It("runs the listings concurrently", func() {
+			// Arrange
+			started := make(chan struct{}, 2)
+			listing := func(what string) vcenterListing {
+				return vcenterListing{what: what, list: func(ctx context.Context, _ models.Credentials) (func(*models.CollectorResult), error) {
+					started <- struct{}{}
+					Eventually(started).Should(HaveLen(2))
+					return func(r *models.CollectorResult) { r.Warnings = append(r.Warnings, what) }, nil
+				}}
+			}
+
+			// Act
+			r, errs := factory.listVCenterObjects(context.Background(), creds, models.CollectorResult{}, []vcenterListing{listing("first"), listing("second")})
+
+			// Assert
+			Expect(errs).To(BeEmpty())
+			Expect(r.Warnings).To(Equal([]string{"first", "second"}))
+		})
+
+		// Given a failing listing between two that succeed, the first one slower
+		// When the collection lists them
+		// Then the others should still be stored, in order, and the error
+		// should name what was not collected
+		It("reports the errors of any listing", func() {
+			// Arrange
+			ok := func(what string, delay time.Duration) vcenterListing {
+				return vcenterListing{what: what, list: func(context.Context, models.Credentials) (func(*models.CollectorResult), error) {
+					time.Sleep(delay)
+					return func(r *models.CollectorResult) { r.Warnings = append(r.Warnings, what) }, nil
+				}}
+			}
+			failing := vcenterListing{what: "VM tags", list: func(context.Context, models.Credentials) (func(*models.CollectorResult), error) {
+				return nil, errors.New("permission denied")
+			}}
+
+			// Act
+			r, errs := factory.listVCenterObjects(context.Background(), creds, models.CollectorResult{},
+				[]vcenterListing{ok("first", 50*time.Millisecond), failing, ok("last", 0)})
+
+			// Assert
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(MatchError("VM tags were not collected: permission denied"))
+			Expect(r.Warnings).To(Equal([]string{"first", "last"}))
+		})
+
+		// Given a vCenter rejecting the credentials
+		// When the collection lists the objects
+		// Then the error should name what was not collected
+		It("returns the error of the failed listing", func() {
+			// Arrange
+			creds.Password = "wrong"
+
+			// Act
+			r, errs := factory.listVCenterObjects(context.Background(), creds, models.CollectorResult{}, factory.vcenterListings())
+
+			// Assert
+			Expect(errs).To(ConsistOf(
+				MatchError(ContainSubstring("VM snapshots and creation dates were not collected")),
+			))
+			Expect(r.Snapshots).To(BeNil())
+			Expect(r.CreateDates).To(BeNil())
+		})
+	})
+
+	Describe("empty vCenter", func() {
+		var (
+			ctx     context.Context
+			st      *store.Store
+			factory *collectorWorkFactory
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+
+			db, err := store.NewDB(nil, ":memory:")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(db.Close)
+
+			st = store.NewStore(db, test.NewMockValidator())
+			Expect(st.Migrate(ctx)).To(Succeed())
+			factory = newCollectorWorkFactory(st, NewEventService(st), GinkgoT().TempDir(), "")
+		})
+
+		// newServer starts a vcsim vCenter, lets arrange change its VMs and
+		// returns the credentials for it.
+		newServer := func(machines int, arrange func(context.Context, *find.Finder)) models.Credentials {
+			model := simulator.VPX()
+			model.Machine = machines
+			Expect(model.Create()).To(Succeed())
+			model.Service.Listen = &url.URL{User: url.UserPassword("user", "pass")}
+			server := model.Service.NewServer()
+			DeferCleanup(model.Remove)
+			DeferCleanup(server.Close)
+
+			creds := models.Credentials{
+				URL:      server.URL.Scheme + "://" + server.URL.Host + "/sdk",
+				Username: "user",
+				Password: "pass",
+				Insecure: true,
+			}
+			if arrange != nil {
+				client, err := vmware.NewVsphereClient(ctx, creds.URL, creds.Username, creds.Password, true)
+				Expect(err).NotTo(HaveOccurred())
+				DeferCleanup(client.Logout, context.Background())
+				arrange(ctx, find.NewFinder(client.Client))
+			}
+			return creds
+		}
+
+		collect := func(creds models.Credentials) work.Status[models.CollectorStatus, models.CollectorResult] {
+			srv := work.NewService(models.CollectorStatus{State: models.CollectorStateConnecting}, factory.Build(creds))
+			Expect(srv.Start()).To(Succeed())
+			DeferCleanup(srv.Stop)
+			srv.Wait()
+			return srv.State()
+		}
+
+		expectEmptyInventory := func(status work.Status[models.CollectorStatus, models.CollectorResult]) {
+			Expect(status.Err).NotTo(HaveOccurred())
+			Expect(status.State.State).To(Equal(models.CollectorStateCollected))
+
+			var inv struct {
+				Vcenter struct {
+					Vms struct {
+						Total int `json:"total"`
+						RamGB struct {
+							Total int `json:"total"`
+						} `json:"ramGB"`
+					} `json:"vms"`
+				} `json:"vcenter"`
+			}
+			Expect(json.Unmarshal(status.Result.Inventory, &inv)).To(Succeed())
+			Expect(inv.Vcenter.Vms.Total).To(BeZero())
+			Expect(inv.Vcenter.Vms.RamGB.Total).To(BeZero())
+
+			saved, err := st.Inventory().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved.Data).To(MatchJSON(status.Result.Inventory))
+
+			vms, err := st.VM().List(ctx, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms).To(BeEmpty())
+			count, err := st.VM().Count(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		}
+
+		// Given a vCenter without VMs
+		// When it is collected
+		// Then the collection should complete with an empty inventory
+		It("collects a vCenter without VMs", func() {
+			// Arrange
+			creds := newServer(0, nil)
+
+			// Act
+			status := collect(creds)
+
+			// Assert
+			expectEmptyInventory(status)
+		})
+
+		// Given a vCenter whose VMs are all templates
+		// When it is collected
+		// Then the collection should complete with an empty inventory
+		It("collects a vCenter with only templates", func() {
+			// Arrange
+			creds := newServer(2, func(ctx context.Context, finder *find.Finder) {
+				vms, err := finder.VirtualMachineList(ctx, "*")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(vms).NotTo(BeEmpty())
+				for _, vm := range vms {
+					task, err := vm.PowerOff(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(task.Wait(ctx)).To(Succeed())
+					Expect(vm.MarkAsTemplate(ctx)).To(Succeed())
+				}
+			})
+
+			// Act
+			status := collect(creds)
+
+			// Assert
+			expectEmptyInventory(status)
+		})
+	})
+
+	Describe("recordingValidator", func() {
+		// Given a validator failing on a VM
+		// When the parser validates the VM through the recorder
+		// Then the error should still reach the parser and a warning naming the VM be kept
+		It("records a warning for each VM it fails to validate", func() {
+			// Arrange
+			v := &recordingValidator{inner: &test.MockValidator{Err: errors.New("policy engine unavailable")}}
+
+			// Act
+			_, err := v.Validate(context.Background(), duckdb_models.VM{ID: "vm-1", Name: "web-01"})
+			_, err2 := v.Validate(context.Background(), duckdb_models.VM{ID: "vm-2", Name: "db-01"})
+
+			// Assert
+			Expect(err).To(HaveOccurred())
+			Expect(err2).To(HaveOccurred())
+			Expect(v.warnings).To(Equal([]string{
+				"VM web-01 (vm-1) could not be validated: policy engine unavailable",
+				"VM db-01 (vm-2) could not be validated: policy engine unavailable",
+			}))
+		})
+
+		// Given a validator that succeeds
+		// When a VM is validated through the recorder
+		// Then its concerns should be returned and no warning kept
+		It("passes concerns through without warnings", func() {
+			// Arrange
+			concerns := []duckdb_models.Concern{{Id: "c1", Label: "label", Category: "Warning"}}
+			v := &recordingValidator{inner: &test.MockValidator{Concerns: concerns}}
+
+			// Act
+			got, err := v.Validate(context.Background(), duckdb_models.VM{ID: "vm-1"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(concerns))
+			Expect(v.warnings).To(BeEmpty())
+		})
+	})
+})
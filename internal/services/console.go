@@ -2,17 +2,22 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/google/uuid"
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/config"
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
 	"github.com/kubev2v/assisted-migration-agent/pkg/console"
 	"github.com/kubev2v/assisted-migration-agent/pkg/errors"
 	"github.com/kubev2v/assisted-migration-agent/pkg/scheduler"
@@ -21,7 +26,13 @@ import (
 
 const (
 	maxBackoffInterval        = 60 * time.Second
+	maxRetryAfter             = 10 * time.Minute // cap on a console Retry-After
 	initialState       string = "pending"
+
+	// minPushThroughput is the slowest link, in bytes per second, an inventory
+	// push is expected to cross. The push deadline allows the inventory to be
+	// sent at this rate (512 kbit/s).
+	minPushThroughput = 64 * 1024
 )
 
 type Collector interface {
@@ -33,19 +44,34 @@ type (
 )
 
 type Console struct {
-	updateInterval      time.Duration
+	updateInterval      atomic.Int64 // time.Duration, changed by UpdateSettings
 	agentID             uuid.UUID
 	sourceID            uuid.UUID
 	version             string
 	state               *consoleState
-	mu                  sync.Mutex // protects mode changes to prevent double run()
+	mu                  sync.Mutex  // protects mode changes to prevent double run()
+	modeChanging        atomic.Bool // set while SetMode is applying a change
 	client              *console.Client
 	requestBuilder      *console.RequestBuilder
 	close               chan any
+	trigger             chan struct{} // wakes the run loop before the next tick
+	reconfigured        chan struct{} // restarts the run loop's wait with a new interval
 	collector           Collector
 	eventSrv            *EventService
 	store               *store.Store
-	legacyStatusEnabled bool
+	legacyStatusEnabled atomic.Bool
+	flagSettings        models.ConsoleSettings // the configured values, restored by a reset
+	maxInventoryBytes   int                    // 0: no limit
+	redactFields        []collectorV1.RedactField
+	redactKey           []byte
+	pushTimeoutMin      time.Duration
+	pushTimeoutMax      time.Duration
+	// tooLarge is the last inventory skipped for its size, until one is pushed.
+	tooLarge atomic.Pointer[errors.InventoryTooLargeError]
+	// lastPushed is the InventoryHash of the last inventory delivered.
+	// PushInventory replaces it with a fresh empty string so the next
+	// inventory is sent even if unchanged.
+	lastPushed atomic.Pointer[string]
 }
 
 func NewConsoleService(cfg config.Agent, client *console.Client, collector Collector, st *store.Store, eventSrv *EventService) (*Console, error) {
@@ -64,12 +90,30 @@ func NewConsoleService(cfg config.Agent, client *console.Client, collector Colle
 		defaultStatus.Target = models.ConsoleStatusType(config.AgentMode)
 	}
 
+	redactFields, err := collectorV1.ParseRedactFields(cfg.PushRedactedFields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid push-redacted-fields: %w", err)
+	}
+
 	c := newConsoleService(cfg, client, collector, st, eventSrv, defaultStatus)
+	c.redactFields = redactFields
+	if config != nil {
+		c.logOverrides(config.Console)
+		c.applySettings(models.ConsoleSettingsChange{ConsoleSettings: config.Console})
+	}
 
 	if err := c.store.Configuration().Save(context.Background(), &models.Configuration{AgentMode: models.AgentMode(defaultStatus.Target)}); err != nil {
 		return nil, err
 	}
 
+	if len(redactFields) > 0 {
+		key, err := c.store.Configuration().RedactionKey(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the redaction key: %w", err)
+		}
+		c.redactKey = []byte(key)
+	}
+
 	if defaultStatus.Target == models.ConsoleStatusConnected {
 		c.close = make(chan any, 1)
 		go c.run(c.close)
@@ -83,22 +127,29 @@ func NewConsoleService(cfg config.Agent, client *console.Client, collector Colle
 func newConsoleService(cfg config.Agent, client *console.Client, collector Collector, store *store.Store, eventSrv *EventService, defaultStatus models.ConsoleStatus) *Console {
 	agentID := uuid.MustParse(cfg.ID)
 	sourceID := uuid.MustParse(cfg.SourceID)
-	return &Console{
-		updateInterval: cfg.UpdateInterval,
-		agentID:        agentID,
-		sourceID:       sourceID,
-		version:        cfg.Version,
+	c := &Console{
+		agentID:  agentID,
+		sourceID: sourceID,
+		version:  cfg.Version,
 		state: &consoleState{
 			current: defaultStatus.Current,
 			target:  defaultStatus.Target,
 		},
-		client:              client,
-		requestBuilder:      console.NewRequestBuilder(client, sourceID, agentID),
-		store:               store,
-		collector:           collector,
-		eventSrv:            eventSrv,
-		legacyStatusEnabled: cfg.LegacyStatusEnabled,
+		client:            client,
+		trigger:           make(chan struct{}, 1),
+		reconfigured:      make(chan struct{}, 1),
+		requestBuilder:    console.NewRequestBuilder(client, sourceID, agentID),
+		store:             store,
+		collector:         collector,
+		eventSrv:          eventSrv,
+		maxInventoryBytes: cfg.MaxInventoryBytes,
+		pushTimeoutMin:    cfg.InventoryPushTimeoutMin,
+		pushTimeoutMax:    cfg.InventoryPushTimeoutMax,
 	}
+	c.updateInterval.Store(int64(cfg.UpdateInterval))
+	c.legacyStatusEnabled.Store(cfg.LegacyStatusEnabled)
+	c.flagSettings = models.ConsoleSettings{UpdateInterval: &cfg.UpdateInterval, LegacyStatusEnabled: &cfg.LegacyStatusEnabled}
+	return c
 }
 
 func (c *Console) GetMode(ctx context.Context) (models.AgentMode, error) {
@@ -109,7 +160,15 @@ func (c *Console) GetMode(ctx context.Context) (models.AgentMode, error) {
 	return config.AgentMode, nil
 }
 
+// SetMode persists the requested mode and starts or stops the run loop.
+// Only one mode change is applied at a time: a call made while another is
+// in flight returns ModeChangeInProgressError instead of waiting.
 func (c *Console) SetMode(ctx context.Context, mode models.AgentMode) error {
+	if !c.modeChanging.CompareAndSwap(false, true) {
+		return errors.NewModeChangeInProgressError()
+	}
+	defer c.modeChanging.Store(false)
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -155,10 +214,116 @@ func (c *Console) SetMode(ctx context.Context, mode models.AgentMode) error {
 	return nil
 }
 
+// PushInventory queues the currently stored inventory for delivery and wakes
+// the run loop so it is sent without waiting for the next tick. The inventory
+// is sent even if it is identical to the last one delivered.
+func (c *Console) PushInventory(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.close == nil || c.state.IsFatalStopped() {
+		return errors.NewAgentNotConnectedError()
+	}
+
+	inv, err := c.store.Inventory().Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	forced := ""
+	c.lastPushed.Store(&forced)
+	if err := c.eventSrv.AddInventoryUpdateEvent(ctx, inv.Data); err != nil {
+		return err
+	}
+
+	select {
+	case c.trigger <- struct{}{}:
+	default:
+	}
+
+	zap.S().Named("console_service").Info("inventory push requested")
+	return nil
+}
+
 func (c *Console) Status() models.ConsoleStatus {
 	return c.state.Status()
 }
 
+// Settings returns the console settings in effect.
+func (c *Console) Settings() models.ConsoleSettings {
+	interval := time.Duration(c.updateInterval.Load())
+	legacy := c.legacyStatusEnabled.Load()
+	return models.ConsoleSettings{UpdateInterval: &interval, LegacyStatusEnabled: &legacy}
+}
+
+// UpdateSettings persists the settings set in change and applies them to the
+// running service, returning the settings now in effect. Unset fields are left
+// as they are; reset ones are cleared and go back to the configured value. A
+// new update interval replaces the wait in progress, so it takes effect
+// without waiting out the old one. A non-positive interval is rejected with a
+// ValidationError and nothing is changed.
+func (c *Console) UpdateSettings(ctx context.Context, change models.ConsoleSettingsChange) (models.ConsoleSettings, error) {
+	if change.UpdateInterval != nil && *change.UpdateInterval <= 0 {
+		return models.ConsoleSettings{}, errors.NewFieldValidationError(map[string]string{
+			"updateInterval": "must be positive",
+		})
+	}
+
+	if err := c.store.Configuration().SaveConsoleSettings(ctx, change); err != nil {
+		return models.ConsoleSettings{}, err
+	}
+	c.applySettings(change)
+
+	settings := c.Settings()
+	zap.S().Named("console_service").Infow("console settings changed", "update_interval", *settings.UpdateInterval, "legacy_status_enabled", *settings.LegacyStatusEnabled)
+	return settings, nil
+}
+
+// applySettings applies the settings set in change, restores the configured
+// value of the reset ones and wakes the run loop to wait again with a new
+// interval.
+func (c *Console) applySettings(change models.ConsoleSettingsChange) {
+	if change.ResetLegacyStatusEnabled {
+		change.LegacyStatusEnabled = c.flagSettings.LegacyStatusEnabled
+	}
+	if change.ResetUpdateInterval {
+		change.UpdateInterval = c.flagSettings.UpdateInterval
+	}
+	if change.LegacyStatusEnabled != nil {
+		c.legacyStatusEnabled.Store(*change.LegacyStatusEnabled)
+	}
+	if change.UpdateInterval != nil {
+		c.updateInterval.Store(int64(*change.UpdateInterval))
+		select {
+		case c.reconfigured <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// logOverrides logs every stored setting that differs from the configured
+// value it replaces, so a flag that seems to have no effect can be traced to
+// an earlier PATCH /agent/config.
+func (c *Console) logOverrides(stored models.ConsoleSettings) {
+	logger := zap.S().Named("console_service")
+	if stored.UpdateInterval != nil && *stored.UpdateInterval != *c.flagSettings.UpdateInterval {
+		logger.Infow("stored update interval overrides the configured one, send null to reset it", "stored", *stored.UpdateInterval, "configured", *c.flagSettings.UpdateInterval)
+	}
+	if stored.LegacyStatusEnabled != nil && *stored.LegacyStatusEnabled != *c.flagSettings.LegacyStatusEnabled {
+		logger.Infow("stored legacy status flag overrides the configured one, send null to reset it", "stored", *stored.LegacyStatusEnabled, "configured", *c.flagSettings.LegacyStatusEnabled)
+	}
+}
+
+// WithStatusBroker publishes every console status change to broker.
+func (c *Console) WithStatusBroker(broker *StatusBroker) *Console {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	c.state.onChange = func() {
+		broker.Publish(models.StatusEvent{Source: models.StatusEventAgent, Status: c.state.Status()})
+	}
+	return c
+}
+
 // run is the main loop that delivers status updates and outbox events to the console.
 //
 // On each tick it creates a fresh pipeline by draining the outbox. The pipeline
@@ -168,12 +333,19 @@ func (c *Console) Status() models.ConsoleStatus {
 //
 // Loop structure:
 //
-//  1. Wait for the current interval or close signal.
+//  1. Wait for the current interval, a push trigger (PushInventory) or close signal.
+//     A settings change (UpdateSettings) restarts the wait with the new interval.
 //  2. If the pipeline is still running, skip this tick.
 //  3. Once the pipeline finishes, process the result:
 //     - Fatal error (4xx from console): stop the loop permanently.
-//     - Transient error: double the interval (up to maxBackoffInterval).
-//     - Success: reset the interval to updateInterval.
+//     - Transient error (including ConsoleUnreachableError on DNS failure):
+//     double the interval (up to maxBackoffInterval).
+//     - ConsoleBackpressureError (429/503): back off as for a transient error,
+//     and first wait out the console's Retry-After (up to maxRetryAfter),
+//     so no request goes out before it, push triggers included.
+//     - Success: go back to waiting updateInterval. If an inventory was
+//     skipped for exceeding maxInventoryBytes, report InventoryTooLargeError
+//     as the status error until an inventory is pushed.
 //  4. Create a new pipeline from the current outbox state and start it.
 //
 // This means transient error retries respect the backoff interval — the next
@@ -218,11 +390,20 @@ func (c *Console) run(closeCh chan any) {
 		closeCh <- struct{}{}
 	}()
 
-	interval := c.updateInterval
+	// backoff is the wait while retrying after an error, zero otherwise.
+	var backoff time.Duration
 
 	for {
+		interval := backoff
+		if interval == 0 {
+			interval = time.Duration(c.updateInterval.Load())
+		}
+
 		select {
 		case <-time.After(interval):
+		case <-c.trigger:
+		case <-c.reconfigured:
+			continue
 		case <-closeCh:
 			return
 		}
@@ -239,11 +420,29 @@ func (c *Console) run(closeCh chan any) {
 				c.state.SetFatalStopped()
 				return
 			}
-			zap.S().Named("console_service").Errorw("failed to dispatch to console", "error", state.Err)
-			interval = min(interval*2, maxBackoffInterval)
+			backoff = min(interval*2, maxBackoffInterval)
+			if errors.IsConsoleBackpressureError(state.Err) {
+				retryAfter := min(errors.ConsoleRetryAfter(state.Err), maxRetryAfter)
+				zap.S().Named("console_service").Warnw("console asked to slow down, will retry", "error", state.Err, "retry_in", retryAfter)
+				// Honor Retry-After before the next request.
+				select {
+				case <-time.After(retryAfter):
+				case <-closeCh:
+					return
+				}
+			} else if errors.IsConsoleUnreachableError(state.Err) {
+				zap.S().Named("console_service").Warnw("console host unreachable, will retry", "error", state.Err, "retry_in", backoff)
+			} else {
+				zap.S().Named("console_service").Errorw("failed to dispatch to console", "error", state.Err)
+			}
 		} else {
-			c.state.ClearError()
-			interval = c.updateInterval
+			// A skipped inventory is not retried, so keep reporting it.
+			if tooLarge := c.tooLarge.Load(); tooLarge != nil {
+				c.state.SetError(tooLarge)
+			} else {
+				c.state.ClearError()
+			}
+			backoff = 0
 		}
 
 		pipeline, errPipeline = c.createPipeline(sched)
@@ -260,6 +459,103 @@ func (c *Console) run(closeCh chan any) {
 	}
 }
 
+// pushInventory sends an inventory update event. The console replaces the
+// source's whole inventory on every push, so it is always sent in one piece.
+// An inventory over maxInventoryBytes is skipped and recorded in tooLarge
+// rather than sent, since the console would reject it on every attempt. An
+// inventory with the same InventoryHash as the last one delivered is not sent
+// again. The fields set with Agent.PushRedactedFields are redacted in what is
+// sent only; the stored inventory and the event keep them.
+func (c *Console) pushInventory(ctx context.Context, e models.Event) error {
+	last := c.lastPushed.Load()
+	hash, err := collectorV1.InventoryHash(e.Data)
+	if err != nil {
+		return fmt.Errorf("failed to hash inventory: %w", err)
+	}
+	if last != nil && *last == hash {
+		zap.S().Named("console_service").Debugw("skipping unchanged inventory", "id", e.ID)
+		return nil
+	}
+
+	if e.Data, err = c.redact(e.Data); err != nil {
+		return err
+	}
+	tooLarge, err := c.pushInventoryData(ctx, e, e.Data)
+	if err != nil {
+		return err
+	}
+	c.tooLarge.Store(tooLarge)
+	// Lose to a concurrent PushInventory, whose event must still be sent.
+	c.lastPushed.CompareAndSwap(last, &hash)
+	return nil
+}
+
+// redact returns the inventory data with redactFields obfuscated under the
+// agent's redaction key, or data itself when there are none.
+func (c *Console) redact(data []byte) ([]byte, error) {
+	if len(c.redactFields) == 0 {
+		return data, nil
+	}
+
+	var inventory v1alpha1.Inventory
+	if err := json.Unmarshal(data, &inventory); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal inventory: %w", err)
+	}
+	return collectorV1.MarshalInventory(collectorV1.Redact(inventory, c.redactFields, c.redactKey))
+}
+
+// pushInventoryData sends data as the payload of inventory event e, or
+// returns the InventoryTooLargeError it was skipped with.
+func (c *Console) pushInventoryData(ctx context.Context, e models.Event, data []byte) (*errors.InventoryTooLargeError, error) {
+	if c.maxInventoryBytes > 0 && len(data) > c.maxInventoryBytes {
+		tooLarge := errors.NewInventoryTooLargeError(len(data), c.maxInventoryBytes)
+		zap.S().Named("console_service").Errorw("skipping inventory push", "id", e.ID, "error", tooLarge)
+		return tooLarge, nil
+	}
+
+	e.Data = data
+	fn, err := c.requestBuilder.Build(e)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := c.pushTimeout(len(data))
+	if timeout <= 0 {
+		return nil, c.sourceError(c.contacted(fn(ctx)))
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := c.contacted(fn(ctx)); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("inventory push of %d bytes timed out after %s: %w", len(data), timeout, err)
+		}
+		return nil, c.sourceError(err)
+	}
+	return nil, nil
+}
+
+// sourceError turns a 404 or 410 from the source endpoint into a
+// SourceNotRegisteredError: the console does not know the configured source.
+func (c *Console) sourceError(err error) error {
+	switch errors.ConsoleClientStatusCode(err) {
+	case http.StatusNotFound, http.StatusGone:
+		return errors.NewSourceNotRegisteredError(c.sourceID.String(), err)
+	default:
+		return err
+	}
+}
+
+// pushTimeout returns the deadline for pushing size bytes: the time to send
+// them at minPushThroughput, kept between pushTimeoutMin and pushTimeoutMax.
+// Zero means no deadline, which is the case when pushTimeoutMax is not set.
+func (c *Console) pushTimeout(size int) time.Duration {
+	if c.pushTimeoutMax <= 0 {
+		return 0
+	}
+	timeout := time.Duration(size) * time.Second / minPushThroughput
+	return min(max(timeout, c.pushTimeoutMin), c.pushTimeoutMax)
+}
+
 func (c *Console) Stop() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -284,14 +580,14 @@ func (c *Console) createPipeline(s *scheduler.Scheduler[any]) (*work.Pipeline[st
 			Work: func(ctx context.Context, r any) (any, error) {
 				collectorStatus := c.collector.GetStatus()
 				status := string(collectorStatus.State)
-				if c.legacyStatusEnabled {
+				if c.legacyStatusEnabled.Load() {
 					status = string(collectorStatus.State.ToV1())
 				}
 				statusInfo := status
 				if collectorStatus.State == models.CollectorStateError {
 					statusInfo = collectorStatus.Error.Error()
 				}
-				return nil, c.client.UpdateAgentStatus(ctx, c.agentID, c.sourceID, c.version, status, statusInfo)
+				return nil, c.contacted(c.client.UpdateAgentStatus(ctx, c.agentID, c.sourceID, c.version, status, statusInfo))
 			},
 		}}
 
@@ -309,6 +605,9 @@ func (c *Console) createPipeline(s *scheduler.Scheduler[any]) (*work.Pipeline[st
 		units = append(units, consoleWorkUnit{
 			Status: func() string { return "event" },
 			Work: func(ctx context.Context, r any) (any, error) {
+				if e.Kind == models.InventoryUpdateEvent {
+					return nil, c.pushInventory(ctx, e)
+				}
 				fn, err := c.requestBuilder.Build(e)
 				if err != nil {
 					if errors.IsUnknownEventKindError(err) {
@@ -317,7 +616,7 @@ func (c *Console) createPipeline(s *scheduler.Scheduler[any]) (*work.Pipeline[st
 					}
 					return nil, err
 				}
-				return nil, fn(ctx)
+				return nil, c.contacted(fn(ctx))
 			},
 		})
 		lastID = e.ID
@@ -333,6 +632,15 @@ func (c *Console) createPipeline(s *scheduler.Scheduler[any]) (*work.Pipeline[st
 	return work.NewPipeline(initialState, s, work.NewSliceWorkBuilder(units)), nil
 }
 
+// contacted records a successful console request when err is nil, and
+// returns err.
+func (c *Console) contacted(err error) error {
+	if err == nil {
+		c.state.SetLastContact(time.Now())
+	}
+	return err
+}
+
 // consoleState holds the console status with its own mutex for thread-safe access.
 // This separation prevents deadlocks between state updates (from run loop) and
 // mode changes (from SetMode).
@@ -341,41 +649,75 @@ type consoleState struct {
 	current      models.ConsoleStatusType
 	target       models.ConsoleStatusType
 	err          error
+	lastContact  time.Time
 	fatalStopped bool
+	// onChange, when set, is called outside the lock after the status changes.
+	onChange func()
 }
 
 func (s *consoleState) Status() models.ConsoleStatus {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	return models.ConsoleStatus{
-		Current: s.current,
-		Target:  s.target,
-		Error:   s.err,
+		Current:       s.current,
+		Target:        s.target,
+		Error:         s.err,
+		LastContactAt: s.lastContact,
 	}
 }
 
-func (s *consoleState) SetCurrent(c models.ConsoleStatusType) {
+// update applies fn under the lock and calls onChange if fn reports a change.
+func (s *consoleState) update(fn func() bool) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.current = c
+	changed := fn()
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange()
+	}
+}
+
+func (s *consoleState) SetCurrent(c models.ConsoleStatusType) {
+	s.update(func() bool {
+		changed := s.current != c
+		s.current = c
+		return changed
+	})
 }
 
 func (s *consoleState) SetTarget(t models.ConsoleStatusType) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.target = t
+	s.update(func() bool {
+		changed := s.target != t
+		s.target = t
+		return changed
+	})
 }
 
+// SetError records err. Repeating the same message, as the run loop does
+// while retrying, is not a change.
 func (s *consoleState) SetError(err error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.err = err
+	s.update(func() bool {
+		changed := (s.err == nil) != (err == nil) || (err != nil && s.err.Error() != err.Error())
+		s.err = err
+		return changed
+	})
 }
 
 func (s *consoleState) ClearError() {
+	s.update(func() bool {
+		changed := s.err != nil
+		s.err = nil
+		return changed
+	})
+}
+
+// SetLastContact records t as the time of the last successful request. It
+// advances on every tick, so it is not a change worth publishing.
+func (s *consoleState) SetLastContact(t time.Time) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.err = nil
+	s.lastContact = t
 }
 
 func (s *consoleState) GetError() error {
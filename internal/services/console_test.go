@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -21,6 +23,7 @@ import (
 	"github.com/kubev2v/assisted-migration-agent/internal/services"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
 	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
 	"github.com/kubev2v/assisted-migration-agent/pkg/console"
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 	"github.com/kubev2v/assisted-migration-agent/test"
@@ -484,6 +487,42 @@ var _ = Describe("Console Service", func() {
 			Consistently(statusReceived, 300*time.Millisecond).ShouldNot(Receive())
 		})
 
+		// Given a connected console that accepts the agent status but answers 404
+		// for the source
+		// When a queued inventory is pushed
+		// Then it should stop with a SourceNotRegisteredError naming the source
+		It("should report an unregistered source when the source endpoint returns 404", func() {
+			// Arrange
+			var inventoryRequests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "sources") {
+					inventoryRequests.Add(1)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			inventory := []byte(`{"vcenter_id":"vc-1","clusters":{}}`)
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), inventory)).To(Succeed())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(Succeed())
+
+			// Assert
+			Eventually(func() error { return consoleSrv.Status().Error }, time.Second).Should(Satisfy(srvErrors.IsSourceNotRegisteredError))
+			Expect(consoleSrv.Status().Error.Error()).To(ContainSubstring(sourceID))
+			Consistently(inventoryRequests.Load, 300*time.Millisecond).Should(BeNumerically("==", 1))
+		})
+
 		// Given a console service in connected mode receiving 401 Unauthorized responses
 		// When the server responds with 401 Unauthorized
 		// Then it should stop sending all requests
@@ -543,6 +582,147 @@ var _ = Describe("Console Service", func() {
 			// Assert
 			Eventually(requestReceived, 500*time.Millisecond).Should(Receive())
 		})
+
+		// Given a console that answers the first status update with 429 and Retry-After: 2
+		// When the console service keeps sending updates
+		// Then the next request should wait at least two seconds and the service keep running
+		It("should wait for Retry-After before the next request", func() {
+			// Arrange
+			var requests atomic.Int32
+			requestTimes := make(chan time.Time, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestTimes <- time.Now()
+				if requests.Add(1) == 1 {
+					w.Header().Set("Retry-After", "2")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+
+			// Assert
+			var first, second time.Time
+			Eventually(requestTimes, time.Second).Should(Receive(&first))
+			Eventually(requestTimes, 4*time.Second).Should(Receive(&second))
+			Expect(second.Sub(first)).To(BeNumerically(">=", 2*time.Second))
+			Expect(consoleSrv.Status().Current).To(Equal(models.ConsoleStatusConnected))
+		})
+
+		// Given a console answering 503 with an HTTP-date Retry-After
+		// When the client sends a status update
+		// Then it should return a transient backpressure error carrying the wait
+		It("should report 503 with an HTTP-date Retry-After as backpressure", func() {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", time.Now().Add(30*time.Second).UTC().Format(http.TimeFormat))
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			err = client.UpdateAgentStatus(context.Background(), uuid.New(), uuid.New(), "v1", "up-to-date", "")
+
+			// Assert
+			Expect(srvErrors.IsConsoleBackpressureError(err)).To(BeTrue())
+			Expect(srvErrors.IsConsoleClientError(err)).To(BeFalse())
+			Expect(srvErrors.ConsoleRetryAfter(err)).To(BeNumerically("~", 30*time.Second, 2*time.Second))
+		})
+
+		// Given a console host whose name resolution fails and later recovers
+		// When the console service keeps retrying
+		// Then the failure should be reported as transient and the service should reconnect
+		It("should reconnect after a transient DNS failure", func() {
+			// Arrange
+			requestReceived := make(chan bool, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestReceived <- true
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			var resolvable atomic.Bool
+			var lookups atomic.Int32
+			dialer := &net.Dialer{}
+			dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+				lookups.Add(1)
+				if !resolvable.Load() {
+					return nil, &net.OpError{Op: "dial", Net: network, Err: &net.DNSError{Err: "no such host", Name: "console.test", IsNotFound: true}}
+				}
+				return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+			}
+
+			client, err := console.NewConsoleClient("http://console.test", "", console.WithDialContext(dial))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = client.UpdateAgentStatus(context.Background(), uuid.New(), uuid.New(), "v1", "up-to-date", "")
+			Expect(srvErrors.IsConsoleUnreachableError(err)).To(BeTrue())
+			Expect(srvErrors.IsConsoleClientError(err)).To(BeFalse())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+
+			Eventually(func() bool {
+				return srvErrors.IsConsoleUnreachableError(consoleSrv.Status().Error)
+			}, 500*time.Millisecond, 10*time.Millisecond).Should(BeTrue())
+			failedLookups := lookups.Load()
+
+			// Act
+			resolvable.Store(true)
+
+			// Assert
+			Eventually(requestReceived, 1500*time.Millisecond).Should(Receive())
+			Expect(lookups.Load()).To(BeNumerically(">", failedLookups))
+			Eventually(func() error {
+				return consoleSrv.Status().Error
+			}, 1500*time.Millisecond).Should(BeNil())
+			Expect(consoleSrv.Status().Current).To(Equal(models.ConsoleStatusConnected))
+		})
+
+		// Given a console client configured with the agent's user agent
+		// When the connected service pushes its status
+		// Then the request should carry the agent version and ID
+		It("should identify the agent in the User-Agent header", func() {
+			// Arrange
+			userAgents := make(chan string, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "agents") {
+					userAgents <- r.Header.Get("User-Agent")
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			cfg.Version = "v1.2.3"
+			client, err := console.NewConsoleClient(server.URL, "",
+				console.WithUserAgent(console.UserAgent(cfg.Version, cfg.ID)))
+			Expect(err).NotTo(HaveOccurred())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+
+			// Assert
+			var userAgent string
+			Eventually(userAgents, 500*time.Millisecond).Should(Receive(&userAgent))
+			Expect(userAgent).To(Equal("assisted-migration-agent/v1.2.3 (agent-id=" + agentID + ")"))
+		})
 	})
 
 	Context("Outbox events", func() {
@@ -579,6 +759,49 @@ var _ = Describe("Console Service", func() {
 			}, 500*time.Millisecond).Should(Equal(0))
 		})
 
+		// Given a console that stops answering status updates once it receives an inventory
+		// When the pipeline pushes the inventory
+		// Then the last contact time should be that of the inventory push
+		It("should record the inventory push as the last contact", func() {
+			// Arrange
+			var mu sync.Mutex
+			var pushedAt time.Time
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				if strings.Contains(r.URL.Path, "sources") {
+					pushedAt = time.Now()
+				} else if !pushedAt.IsZero() {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{"vcenter_id": "vc-1"}`))).To(Succeed())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+
+			// Assert
+			Eventually(func() int {
+				events, _ := eventSrv.Events(context.Background())
+				return len(events)
+			}, 500*time.Millisecond).Should(Equal(0))
+
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(consoleSrv.Status().LastContactAt).To(BeTemporally(">=", pushedAt))
+		})
+
 		// Given multiple outbox events exist
 		// When the pipeline runs
 		// Then all events should be sent and deleted
@@ -599,9 +822,9 @@ var _ = Describe("Console Service", func() {
 			client, err := console.NewConsoleClient(server.URL, "")
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{}`))).To(Succeed())
-			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{}`))).To(Succeed())
-			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{}`))).To(Succeed())
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{"vcenter_id": "vc-1"}`))).To(Succeed())
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{"vcenter_id": "vc-2"}`))).To(Succeed())
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{"vcenter_id": "vc-3"}`))).To(Succeed())
 
 			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
 			Expect(err).NotTo(HaveOccurred())
@@ -622,6 +845,168 @@ var _ = Describe("Console Service", func() {
 			}, 500*time.Millisecond).Should(Equal(0))
 		})
 
+		// Given two events with the same inventory, encoded with different key
+		// order and spacing
+		// When the pipeline runs
+		// Then the inventory should be pushed once and both events deleted
+		It("should not re-push an unchanged inventory", func() {
+			// Arrange
+			var inventoryCount int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "sources") {
+					atomic.AddInt64(&inventoryCount, 1)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{"vcenter_id":"vc-1","clusters":{"a":{},"b":{}}}`))).To(Succeed())
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{ "clusters": {"b": {}, "a": {}}, "vcenter_id": "vc-1" }`))).To(Succeed())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+
+			// Assert
+			Eventually(func() int {
+				events, _ := eventSrv.Events(context.Background())
+				return len(events)
+			}, 500*time.Millisecond).Should(Equal(0))
+			Consistently(func() int64 {
+				return atomic.LoadInt64(&inventoryCount)
+			}, 200*time.Millisecond).Should(Equal(int64(1)))
+		})
+
+		// Given an inventory larger than the configured maximum in the outbox
+		// When the pipeline runs
+		// Then the push should be skipped, the event dropped and the error reported
+		It("should skip an oversized inventory and report it", func() {
+			// Arrange
+			inventoryReceived := make(chan bool, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "sources") {
+					inventoryReceived <- true
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.MaxInventoryBytes = 16
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{"vcenter_id": "oversized"}`))).To(Succeed())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+
+			// Assert
+			Eventually(func() bool {
+				return srvErrors.IsInventoryTooLargeError(consoleSrv.Status().Error)
+			}, 500*time.Millisecond, 10*time.Millisecond).Should(BeTrue())
+			Expect(consoleSrv.Status().Error.Error()).To(ContainSubstring("exceeds the 16 byte limit"))
+			Eventually(func() int {
+				events, _ := eventSrv.Events(context.Background())
+				return len(events)
+			}, 500*time.Millisecond).Should(Equal(0))
+			Consistently(inventoryReceived, 200*time.Millisecond).ShouldNot(Receive())
+			Expect(srvErrors.IsInventoryTooLargeError(consoleSrv.Status().Error)).To(BeTrue())
+
+			// Act
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{}`))).To(Succeed())
+
+			// Assert
+			Eventually(inventoryReceived, 500*time.Millisecond).Should(Receive())
+			Eventually(func() error {
+				return consoleSrv.Status().Error
+			}, 500*time.Millisecond).Should(BeNil())
+		})
+
+		// Given a console that takes 300ms to accept an inventory and a 100ms minimum push deadline
+		// When a 128KiB inventory is pushed
+		// Then the deadline should grow with its size (2s) and the push should succeed
+		It("should give a large inventory a deadline proportional to its size", func() {
+			// Arrange
+			inventoryReceived := make(chan bool, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "sources") {
+					time.Sleep(300 * time.Millisecond)
+					inventoryReceived <- true
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.InventoryPushTimeoutMin = 100 * time.Millisecond
+			cfg.InventoryPushTimeoutMax = 5 * time.Second
+			large := `{"vcenter_id": "large", "padding": "` + strings.Repeat("x", 128*1024) + `"}`
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(large))).To(Succeed())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+
+			// Assert
+			Eventually(inventoryReceived, 2*time.Second).Should(Receive())
+			Eventually(func() int {
+				events, _ := eventSrv.Events(context.Background())
+				return len(events)
+			}, time.Second).Should(Equal(0))
+			Expect(consoleSrv.Status().Error).To(BeNil())
+		})
+
+		// Given the same slow console and a small inventory
+		// When it is pushed
+		// Then the push should time out at the 100ms minimum and the event be kept
+		It("should time out a small inventory at the minimum push deadline", func() {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "sources") {
+					time.Sleep(300 * time.Millisecond)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.InventoryPushTimeoutMin = 100 * time.Millisecond
+			cfg.InventoryPushTimeoutMax = 5 * time.Second
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{"vcenter_id": "small"}`))).To(Succeed())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+
+			// Assert
+			Eventually(func() error {
+				return consoleSrv.Status().Error
+			}, time.Second, 10*time.Millisecond).Should(MatchError(ContainSubstring("timed out after 100ms")))
+			events, err := eventSrv.Events(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+		})
+
 		// Given an empty outbox
 		// When the pipeline runs
 		// Then no inventory requests should be sent
@@ -699,6 +1084,63 @@ var _ = Describe("Console Service", func() {
 			}, 1*time.Second).Should(Equal(0))
 		})
 
+		// Given an inventory push failing once, then a changed inventory
+		// When the pipeline retries the push and sends the next inventory
+		// Then the retry should carry the same idempotency key and the changed
+		// inventory a new one
+		It("should reuse the idempotency key across retries of the same inventory", func() {
+			// Arrange
+			var (
+				mu   sync.Mutex
+				keys []string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "sources") {
+					mu.Lock()
+					keys = append(keys, r.Header.Get(console.IdempotencyKeyHeader))
+					first := len(keys) == 1
+					mu.Unlock()
+					if first {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			first := []byte(`{"vcenter_id":"vc-1"}`)
+			changed := []byte(`{"vcenter_id":"vc-2"}`)
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), first)).To(Succeed())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			sentKeys := func() []string {
+				mu.Lock()
+				defer mu.Unlock()
+				return slices.Clone(keys)
+			}
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+			Eventually(sentKeys, 1*time.Second).Should(HaveLen(2))
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), changed)).To(Succeed())
+
+			// Assert
+			Eventually(sentKeys, 1*time.Second).Should(HaveLen(3))
+			firstKey, err := console.IdempotencyKey(uuid.MustParse(agentID), first)
+			Expect(err).NotTo(HaveOccurred())
+			changedKey, err := console.IdempotencyKey(uuid.MustParse(agentID), changed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sentKeys()).To(Equal([]string{firstKey, firstKey, changedKey}))
+			Expect(changedKey).NotTo(Equal(firstKey))
+		})
+
 		// Given outbox events and the inventory request fails with a fatal error
 		// When the pipeline stops
 		// Then events should NOT be deleted (clear unit never runs)
@@ -774,7 +1216,7 @@ var _ = Describe("Console Service", func() {
 
 			// Wait for the first event to be sent, then insert a new one
 			Eventually(firstInventorySent, 500*time.Millisecond).Should(Receive())
-			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{}`))).To(Succeed())
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), []byte(`{"vcenter_id": "vc-2"}`))).To(Succeed())
 
 			// Assert — both events should be delivered
 			Eventually(func() int64 {
@@ -1047,6 +1489,53 @@ var _ = Describe("Console Service", func() {
 			// Assert
 			Expect(statusCount).To(BeNumerically("<", 6))
 		})
+
+		// Given a console that answers, then starts failing
+		// When the service keeps sending status updates
+		// Then the last contact time should advance while requests succeed,
+		// and be kept, not reset, while the service backs off
+		It("should keep the last contact time during backoff", func() {
+			// Arrange
+			var failing atomic.Bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "agents") && failing.Load() {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(consoleSrv.Status().LastContactAt).To(BeZero())
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(BeNil())
+			defer consoleSrv.Stop()
+
+			// Assert
+			Eventually(func() time.Time {
+				return consoleSrv.Status().LastContactAt
+			}, time.Second).ShouldNot(BeZero())
+			first := consoleSrv.Status().LastContactAt
+			Eventually(func() time.Time {
+				return consoleSrv.Status().LastContactAt
+			}, time.Second).Should(BeTemporally(">", first))
+
+			failing.Store(true)
+			Eventually(func() error {
+				return consoleSrv.Status().Error
+			}, time.Second).ShouldNot(BeNil())
+			lastContact := consoleSrv.Status().LastContactAt
+			Expect(lastContact).To(BeTemporally(">", first))
+			Consistently(func() time.Time {
+				return consoleSrv.Status().LastContactAt
+			}, 300*time.Millisecond).Should(Equal(lastContact))
+		})
 	})
 
 	Context("SetMode no-op and fatal", func() {
@@ -1111,6 +1600,232 @@ var _ = Describe("Console Service", func() {
 		})
 	})
 
+	Context("Concurrent SetMode", func() {
+		// Given a console service in disconnected mode
+		// When two mode changes are fired in parallel
+		// Then at most one should be rejected as in progress and the final state should be consistent
+		It("should serialize parallel mode changes", func() {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			modes := []models.AgentMode{models.AgentModeConnected, models.AgentModeDisconnected}
+			errs := make([]error, len(modes))
+			start := make(chan struct{})
+			var wg sync.WaitGroup
+
+			// Act
+			for i, mode := range modes {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					<-start
+					errs[i] = consoleSrv.SetMode(context.Background(), mode)
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			// Assert
+			rejected := 0
+			for _, err := range errs {
+				if err != nil {
+					Expect(srvErrors.IsModeChangeInProgressError(err)).To(BeTrue())
+					rejected++
+				}
+			}
+			Expect(rejected).To(BeNumerically("<=", 1))
+
+			mode, err := consoleSrv.GetMode(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(consoleSrv.Status().Target)).To(Equal(string(mode)))
+			Eventually(func() string {
+				return string(consoleSrv.Status().Current)
+			}, time.Second).Should(Equal(string(mode)))
+		})
+
+		// Given a console service whose mode was changed under contention
+		// When a new mode change is requested after the previous one finished
+		// Then it should be applied normally
+		It("should accept a mode change once the previous one completed", func() {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(Succeed())
+
+			// Act
+			err = consoleSrv.SetMode(context.Background(), models.AgentModeDisconnected)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(consoleSrv.Status().Target).To(Equal(models.ConsoleStatusDisconnected))
+		})
+	})
+
+	Context("Field redaction", func() {
+		// Given an inventory with cluster and network names, stored locally and queued
+		// When it is pushed with cluster and network names redacted
+		// Then the pushed payload should carry only their redacted forms while the
+		// stored inventory keeps the real names
+		It("should redact the configured fields in the pushed inventory only", func() {
+			// Arrange
+			pushed := make(chan string, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "sources") {
+					var body map[string]any
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					raw, _ := json.Marshal(body["inventory"])
+					pushed <- string(raw)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			inventory := []byte(`{"vcenter_id":"vc-1","clusters":{"production":{"infra":{"networks":[{"name":"vlan-finance","type":"standard"}]}}}}`)
+			Expect(st.Inventory().Save(context.Background(), inventory)).To(Succeed())
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), inventory)).To(Succeed())
+
+			cfg.PushRedactedFields = []string{"cluster-names", "network-names"}
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Act
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(Succeed())
+
+			// Assert
+			var body string
+			Eventually(pushed, time.Second).Should(Receive(&body))
+			Expect(body).NotTo(ContainSubstring("production"))
+			Expect(body).NotTo(ContainSubstring("vlan-finance"))
+			key, err := st.Configuration().RedactionKey(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(ContainSubstring(collectorV1.RedactValue([]byte(key), "production")))
+			Expect(body).To(ContainSubstring(collectorV1.RedactValue([]byte(key), "vlan-finance")))
+			Expect(body).To(ContainSubstring("vc-1"))
+
+			stored, err := st.Inventory().Get(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(stored.Data)).To(ContainSubstring("production"))
+			Expect(string(stored.Data)).To(ContainSubstring("vlan-finance"))
+		})
+
+		// Given an unknown field in the redaction list
+		// When the console service is created
+		// Then it should fail naming the setting
+		It("should reject an unknown field", func() {
+			// Arrange
+			client, err := console.NewConsoleClient("http://localhost:1", "")
+			Expect(err).NotTo(HaveOccurred())
+			cfg.PushRedactedFields = []string{"vm-names"}
+
+			// Act
+			_, err = services.NewConsoleService(cfg, client, collector, st, eventSrv)
+
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("invalid push-redacted-fields")))
+		})
+	})
+
+	Context("PushInventory", func() {
+		// Given a connected console that already delivered the stored inventory
+		// When PushInventory is called without the inventory changing
+		// Then the same inventory should be sent again
+		It("should push the inventory even when it has not changed", func() {
+			// Arrange
+			var mu sync.Mutex
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "sources") {
+					var body map[string]any
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					raw, _ := json.Marshal(body["inventory"])
+					mu.Lock()
+					bodies = append(bodies, string(raw))
+					mu.Unlock()
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			inventory := []byte(`{"vcenter_id": "vc-1"}`)
+			Expect(st.Inventory().Save(context.Background(), inventory)).To(Succeed())
+			Expect(eventSrv.AddInventoryUpdateEvent(context.Background(), inventory)).To(Succeed())
+
+			cfg.UpdateInterval = 200 * time.Millisecond
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(Succeed())
+			sent := func() []string {
+				mu.Lock()
+				defer mu.Unlock()
+				return append([]string(nil), bodies...)
+			}
+			Eventually(sent, time.Second).Should(HaveLen(1))
+			Eventually(func() int {
+				events, _ := eventSrv.Events(context.Background())
+				return len(events)
+			}, time.Second).Should(Equal(0))
+
+			// Act
+			err = consoleSrv.PushInventory(context.Background())
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(sent, time.Second).Should(HaveLen(2))
+			Expect(sent()[1]).To(Equal(sent()[0]))
+		})
+
+		// Given a console service in disconnected mode
+		// When PushInventory is called
+		// Then it should return AgentNotConnectedError and queue nothing
+		It("should return AgentNotConnectedError when disconnected", func() {
+			// Arrange
+			client, err := console.NewConsoleClient("http://localhost:1", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(st.Inventory().Save(context.Background(), []byte(`{}`))).To(Succeed())
+
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			err = consoleSrv.PushInventory(context.Background())
+
+			// Assert
+			Expect(srvErrors.IsAgentNotConnectedError(err)).To(BeTrue())
+			events, err := eventSrv.Events(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+	})
+
 	Context("GetMode", func() {
 		// Given a console service with disconnected mode saved in store
 		// When we call GetMode
@@ -1319,4 +2034,126 @@ var _ = Describe("Console Service", func() {
 			Expect(receivedStatusInfo).To(Equal("collected"))
 		})
 	})
+
+	Context("UpdateSettings", func() {
+		// Given a connected console that waits an hour between status updates
+		// When the update interval is changed to 50ms
+		// Then the loop should stop waiting out the hour and send updates at the
+		// new interval, and the interval should be persisted
+		It("should apply a new update interval to the running loop", func() {
+			// Arrange
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := console.NewConsoleClient(server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.UpdateInterval = time.Hour
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			Expect(consoleSrv.SetMode(context.Background(), models.AgentModeConnected)).To(Succeed())
+			Consistently(requests.Load, 200*time.Millisecond).Should(BeZero())
+
+			// Act
+			interval := 50 * time.Millisecond
+			settings, err := consoleSrv.UpdateSettings(context.Background(), models.ConsoleSettingsChange{ConsoleSettings: models.ConsoleSettings{UpdateInterval: &interval}})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.UpdateInterval).To(HaveValue(Equal(interval)))
+			Eventually(requests.Load, time.Second).Should(BeNumerically(">=", 3))
+
+			stored, err := st.Configuration().Get(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Console.UpdateInterval).To(HaveValue(Equal(interval)))
+		})
+
+		// Given console settings persisted by an earlier update
+		// When the console service is created
+		// Then the persisted settings should override the configured ones
+		It("should start with the persisted settings", func() {
+			// Arrange
+			interval := 30 * time.Second
+			legacy := true
+			Expect(st.Configuration().SaveConsoleSettings(context.Background(), models.ConsoleSettingsChange{
+				ConsoleSettings: models.ConsoleSettings{UpdateInterval: &interval, LegacyStatusEnabled: &legacy},
+			})).To(Succeed())
+
+			client, err := console.NewConsoleClient("http://console.test", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Assert
+			settings := consoleSrv.Settings()
+			Expect(settings.UpdateInterval).To(HaveValue(Equal(interval)))
+			Expect(settings.LegacyStatusEnabled).To(HaveValue(BeTrue()))
+		})
+
+		// Given console settings persisted by an earlier update
+		// When both settings are reset
+		// Then the configured values should be back in effect and the stored ones cleared
+		It("should restore the configured settings on reset", func() {
+			// Arrange
+			interval := 30 * time.Second
+			legacy := !cfg.LegacyStatusEnabled
+			Expect(st.Configuration().SaveConsoleSettings(context.Background(), models.ConsoleSettingsChange{
+				ConsoleSettings: models.ConsoleSettings{UpdateInterval: &interval, LegacyStatusEnabled: &legacy},
+			})).To(Succeed())
+
+			client, err := console.NewConsoleClient("http://console.test", "")
+			Expect(err).NotTo(HaveOccurred())
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+			Expect(consoleSrv.Settings().UpdateInterval).To(HaveValue(Equal(interval)))
+
+			// Act
+			settings, err := consoleSrv.UpdateSettings(context.Background(), models.ConsoleSettingsChange{
+				ResetUpdateInterval:      true,
+				ResetLegacyStatusEnabled: true,
+			})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.UpdateInterval).To(HaveValue(Equal(cfg.UpdateInterval)))
+			Expect(settings.LegacyStatusEnabled).To(HaveValue(Equal(cfg.LegacyStatusEnabled)))
+			stored, err := st.Configuration().Get(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Console.UpdateInterval).To(BeNil())
+			Expect(stored.Console.LegacyStatusEnabled).To(BeNil())
+		})
+
+		// Given a console service
+		// When a zero update interval is requested
+		// Then it should return a validation error and keep the current settings
+		It("should reject a non-positive update interval", func() {
+			// Arrange
+			client, err := console.NewConsoleClient("http://console.test", "")
+			Expect(err).NotTo(HaveOccurred())
+			consoleSrv, err := services.NewConsoleService(cfg, client, collector, st, eventSrv)
+			Expect(err).NotTo(HaveOccurred())
+			defer consoleSrv.Stop()
+
+			// Act
+			interval := time.Duration(0)
+			_, err = consoleSrv.UpdateSettings(context.Background(), models.ConsoleSettingsChange{ConsoleSettings: models.ConsoleSettings{UpdateInterval: &interval}})
+
+			// Assert
+			Expect(srvErrors.IsValidationError(err)).To(BeTrue())
+			Expect(consoleSrv.Settings().UpdateInterval).To(HaveValue(Equal(cfg.UpdateInterval)))
+			stored, err := st.Configuration().Get(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Console.UpdateInterval).To(BeNil())
+		})
+	})
 })
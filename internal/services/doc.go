@@ -19,9 +19,12 @@
 //	    ▼
 //	Services Layer
 //	    ├── CollectorService ──► InventoryService, work.Service[CollectorStatus, CollectorResult]
-//	    ├── InspectorService ──► inspectionService (Scheduler[InspectionResult], one work.Pipeline per VM; in-memory only, no Store)
+//	    ├── CollectionScheduler ► CollectorService, MaintenanceService, InspectorService
+//	    ├── InspectorService ──► inspectionService (Scheduler[InspectionResult], one work.Pipeline per VM), Store (queue order only)
 //	    ├── Console ──────────► Store, work.Pipeline (creates Scheduler[any] per run loop), Console Client, Collector
 //	    ├── InventoryService ─► Store
+//	    ├── MaintenanceService ► Store
+//	    ├── StorageService ───► Store, data folder
 //	    ├── VMService ────────► Store
 //	    └── GroupService ─────► Store
 //
@@ -124,13 +127,96 @@
 //   - Collected: Collection completed successfully (terminal state, no way back)
 //   - Error: An error occurred during operation (can restart from here)
 //
+// The allowed transitions are listed in collectorTransitions (collector_state.go).
+// Starting a collection checks the move to Connecting, and transitionWorkBuilder
+// checks each pipeline unit's state against the previous one; an illegal move is
+// logged and fails with InvalidStateError, so a reordered pipeline ends in Error
+// instead of skipping a step. Collected moves to Connecting on Recollect and to
+// Ready when the inventory is cleared.
+//
+// timingWorkBuilder (collector_timing.go) times each pipeline unit, failing ones
+// included. GetStatus returns the timings of the last collection as Phases, in
+// memory only, so they are lost on restart.
+//
 // Key behaviors:
 //   - Only one collection can be in progress at a time (returns CollectionInProgressError otherwise)
 //   - Once inventory is collected, the Collected state is terminal - subsequent Start calls are no-ops
+//   - Refresh(maxAge) remembers the credentials like Start, but recollects an existing
+//     inventory once it is older than maxAge (InventoryService.CollectedAt, the newest
+//     history entry) and does nothing while it is fresher. GetStatus reports the same
+//     time as CollectedAt
+//   - Start remembers the credentials; Recollect reruns the collection with them even when
+//     inventory exists (CredentialsNotSetError if Start was never called)
+//   - With WithCredentialsStore, Start also persists the credentials encrypted
+//     (store.CredentialsStore) and Recollect loads them after a restart; the
+//     password is never part of CollectorStatus or any other API response
+//   - TestConnection logs in to vCenter and returns its product information without starting
+//     a collection or storing the credentials; a rejected login is a CredentialsError, an
+//     unreachable vCenter a VCenterError
+//   - The vCenter certificate is verified unless Credentials.Insecure is set; a failed
+//     verification is a VCenterError with CertificateNotTrusted, reported as
+//     "vCenter certificate not trusted". The flag is stored with the credentials
+//   - The Connecting step logs in through the vmware.SessionCache shared with the
+//     InspectorService (ServiceManager, --vcenter-session-ttl), so an inspection started
+//     right after the collection reuses the session instead of logging in again. The
+//     forklift collector itself opens its own connection
+//   - saveInventory also records the inventory in the history (GET /inventory/history),
+//     pruned to the current one and Agent.InventoryRetention previous ones
+//     (withInventoryRetention, 1 by default). InventoryService.GetInventoryAt fetches
+//     a kept one by its timestamp, truncated to microseconds as DuckDB stores it
+//   - buildInventory replaces the parser's diskSizeTier, distributionByCpuTier and
+//     distributionByMemoryTier buckets with Agent.DiskSizeTiers, CPUTiers and
+//     MemoryTiers when set (ParseTiers, withTiers, and InventoryService.WithTiers for
+//     revalidation). Per-cluster sizes are keyed by the cluster ID the parser assigns
+//   - buildInventory also recounts migrationWarnings, notMigratableReasons,
+//     totalMigratable and totalMigratableWithWarnings with Agent.ConcernCategories
+//     when set (ParseConcernCategories, withConcernCategories, and
+//     InventoryService.WithConcernCategories), so concern categories other than
+//     Critical and Warning can be counted or dropped. Resource breakdowns keep the
+//     parser's split
+//   - After a successful ingest the per-VM snapshot is rotated, keeping the current
+//     and previous collection for GET /inventory/diff. Saving the inventory and the
+//     rotation run in one transaction (saveInventory): if either fails, the previous
+//     inventory and snapshots are kept and the collection ends in error
+//   - After the forklift collection the Collecting step reads every VM's snapshot tree
+//     and config.createDate in one container view walk (vmware.ListVMDetails). The
+//     forklift model reduces the tree to the current snapshot; ingestion stores the
+//     snapshots in vsnapshots, adding the vmware.snapshot.detected concern where OPA did
+//     not raise it, and the creation dates in vcreation for the created filter field.
+//     Failing to read them only adds one warning
+//   - Such vCenter lists are fetched CollectionConcurrency at a time on a Scheduler; each
+//     failed list adds its own warning. The forklift collection itself is a single call
+//     and stays sequential
+//   - Non-fatal issues accumulate in CollectorResult.Warnings: unread snapshots or
+//     creation dates, schema validation warnings, and VMs the validator failed on
+//     (recordingValidator wraps it, since the parser only logs them; such a VM is kept
+//     without concerns). saveInventory stores them with the inventory, and GetStatus
+//     returns them with the Collected state until the next successful collection
+//   - A vCenter without VMs (or with only templates, which ingestion drops) is collected as
+//     an empty inventory: the parser's NO_VMS schema error is not treated as a failure
+//   - Ingestion runs against a staging database (Store.ReplaceInventory) that replaces the
+//     previous inventory only once parsing, schema validation, snapshots and creation dates
+//     succeeded, so a failed recollection keeps the previous inventory. A successful one swaps
+//     it in one transaction, keeps the inspection results of VMs still collected and drops
+//     the others and the group matches; groups themselves are kept
 //   - Collection can be cancelled mid-execution via Stop, returning to Ready state
+//   - With WithStateStore, stateWorkBuilder persists each unit's state as it starts
+//     (store.CollectorStateStore) and clears it when the collection is collected, fails
+//     or is stopped. A shutdown (ServiceManager.Stop) cancels the collection but keeps the
+//     state, so Resume, called once by ServiceManager.Initialize, finds it on the next
+//     start: it restarts the collection with the stored credentials, or else persists
+//     Error and reports it with errCollectionInterrupted until a new collection starts
 //   - Each Start creates a new work.Service; the coordinator checks preconditions before creating it
-//   - GetStatus checks the database for inventory first (authoritative for Collected),
-//     then falls back to the work.Service state, then Ready
+//   - GetStatus reports a running work.Service first (so recollection progress is visible),
+//     then checks the database for inventory (authoritative for Collected),
+//     then falls back to the last work.Service state, then the error left by Resume,
+//     then Ready
+//   - WaitStatus long-polls GetStatus: it blocks until the state differs from the one
+//     the caller last saw or the timeout elapses. Waiters are woken when a collection
+//     starts, as each work unit starts, and when the work.Service finishes or is stopped
+//   - With WithHistory, terminal outcomes (Collected or Error, not Stop) are
+//     appended to collection-history.jsonl in the data folder while the agent
+//     is disconnected; History returns them
 //
 // Usage:
 //
@@ -141,8 +227,26 @@
 //	// At runtime:
 //	err := collector.Start(ctx, credentials)
 //	status := collector.GetStatus()
+//	status = collector.WaitStatus(ctx, status.State, 30*time.Second)
+//	err = collector.Recollect(ctx)
 //	collector.Stop()
 //
+// # CollectionScheduler
+//
+// CollectionScheduler calls CollectorService.Recollect on a schedule parsed by
+// ParseCollectionSchedule from the collection-schedule flag: either a duration
+// ("24h") or a standard cron expression ("0 2 * * *"). It is only created when
+// the flag is set. A tick is skipped, not queued, when no credentials are
+// stored, a collection is running, maintenance mode is on or an inspection is
+// running.
+//
+//	schedule, err := services.ParseCollectionSchedule(cfg.Agent.CollectionSchedule)
+//	scheduler := services.NewCollectionScheduler(collector, schedule).
+//		WithMaintenance(maintenance).
+//		WithInspector(inspector)
+//	scheduler.Start()
+//	defer scheduler.Stop()
+//
 // # InspectorService
 //
 // InspectorService drives VM inspection against vCenter: privilege validation, snapshot lifecycle,
@@ -185,6 +289,7 @@
 //   - Ready: Idle, accepts Start
 //   - Initiating: Connecting to vSphere and starting initial pipelines (Start holds lock for this phase)
 //   - Running: Background run loop polls until no pipeline is busy, then exits to Completed, or handles Stop
+//   - Paused: Running, but VMs that have not started are held back until Resume; Stop and Cancel still apply
 //   - Error: Init failed (vSphere connect or inspectionSvc.start); error is stored on InspectorStatus
 //   - Completed: Normal terminal state when all VM pipelines finish without Stop
 //   - Canceled: Terminal state after Stop() once pipelines have stopped (no intermediate "canceling" state in GetStatus)
@@ -194,10 +299,32 @@
 // Key behaviors:
 //   - Only one inspection run at a time (InspectionInProgressError if already busy)
 //   - Start failure during init sets Error, populates GetStatus().Error, tears down client/pipelines, and returns the error
-//   - Start connects to vCenter, then starts a pipeline per VM ID
+//   - Start connects to vCenter, then starts a pipeline per VM ID. Credentials and Start acquire
+//     the session from the shared vmware.SessionCache (when set), reusing one kept by a recent
+//     collection or inspection for the same URL and username
 //   - Stop tears down pipelines and signals the run loop, which ends in Canceled. Cancel stops a single VM's pipeline
+//   - CancelVmsInspection stops several VMs' pipelines at once and reports an outcome per distinct
+//     id: canceled, already-completed (pipeline already ended) or not-found (not in this run). The
+//     other VMs keep running
+//   - Reset returns a finished inspector to Ready without a new Start: it deletes the persisted
+//     vm_inspection_status rows and forgets the last run's pipelines and timings. Inspection
+//     results are kept. InspectionInProgressError while busy
+//   - The scheduler runs five VMs in parallel; WithWorkers (config Agent.InspectionWorkers) changes it
 //   - GetStatus reads InspectorState (separate mutex); GetVmStatus reads the corresponding work.Pipeline state
-//   - run uses a ticker to detect when all per-VM pipelines have finished, then logs out the vSphere client
+//   - run uses a ticker to detect when all per-VM pipelines have finished, then releases the vSphere session
+//     back to the cache, which logs it out once idle for the TTL
+//   - GetStatus reports AverageVmDuration (first work unit start → last unit done, successful VMs only)
+//     and, while running, EstimatedCompletion = now + average × ceil(remaining VMs / scheduler workers)
+//   - ListVmStatuses reads every VM's status from its work.Pipeline, like GetVmStatus, optionally
+//     narrowed to VMs whose error contains a substring
+//   - Prioritize makes pending VMs start next, in the order given, through the same inspectionGate
+//     that implements Pause: while VMs are prioritized, a VM whose first unit gets a worker is
+//     held back unless it is the head of the list, and starting or canceling the head lets the
+//     next one go. All VMs must be in the run and not started, or nothing changes
+//   - Pause holds back the VMs that have not started; VMs being inspected finish. Each VM's
+//     builder is wrapped in gatedWorkBuilder: when its first unit gets a scheduler worker while
+//     paused, it returns without running and the pipeline waits for Resume before queueing it
+//     again, so held back VMs stay pending and take no worker. Resume releases them
 //
 // Usage:
 //
@@ -235,21 +362,71 @@
 //   - Connected → Disconnected: Saves mode to database, stops the run loop
 //   - Same mode: No-op (returns immediately)
 //   - After fatal error (4xx): Mode changes are blocked with ModeConflictError
+//   - While another change is being applied: Rejected with ModeChangeInProgressError
 //
 // The mode is persisted to the database so it survives agent restarts.
 //
+// Forced inventory push:
+//
+// PushInventory(ctx) queues the stored inventory in the outbox and wakes the
+// run loop, so the inventory is re-sent even when it has not changed. It
+// returns AgentNotConnectedError when the run loop is not active.
+//
+// Runtime settings:
+//
+// UpdateSettings(ctx, change) changes the update interval and the legacy status
+// flag (PATCH /agent/config). Set fields are persisted with
+// ConfigurationStore.SaveConsoleSettings and override the flags on the next
+// start, where NewConsoleService logs each override. Reset fields clear the
+// stored value and restore the configured one. A new interval wakes the run loop through the reconfigured channel,
+// so the wait in progress is restarted with it. A non-positive interval is
+// rejected with ValidationError.
+//
 // The service implements:
 //   - Periodic status and inventory dispatching via a reusable work.Pipeline
-//   - SHA256 hash-based deduplication to avoid sending unchanged inventory
+//   - SHA256 hash-based deduplication to avoid sending unchanged inventory.
+//     The hash (collectorV1.InventoryHash) is taken over the canonical JSON
+//     form, so key order and formatting do not count as changes; the
+//     collector also stores the inventory canonically (MarshalInventory).
+//     PushInventory bypasses the check for the inventory it queues.
+//   - Idempotency key: every inventory push carries an Idempotency-Key header,
+//     the SHA256 of the agent ID and the inventory hash (console.IdempotencyKey).
+//     A retry of a push that timed out after the console applied it sends the
+//     same key, so the console can drop the duplicate.
 //   - Two-phase run loop: process result → wait (with backoff) → restart pipeline.
 //     Retries fire after the backoff interval, not before it.
-//   - Exponential backoff (up to 60s) for transient errors (5xx, network issues)
-//   - Immediate termination on fatal errors (4xx client errors)
+//   - Exponential backoff (up to 60s) for transient errors (5xx, network issues).
+//     DNS failures surface as ConsoleUnreachableError; the client drops idle
+//     connections so the host is resolved again on the next attempt.
+//   - Backpressure: 429 and 503 surface as ConsoleBackpressureError. They are
+//     transient, and the next request waits at least the console's Retry-After
+//     (seconds or HTTP date, capped at 10 minutes).
+//   - Immediate termination on fatal errors (4xx client errors other than 429)
+//   - Inventory size guard: an inventory larger than Agent.MaxInventoryBytes is
+//     not sent (the console would answer 413 on every attempt). Its event is
+//     dropped and InventoryTooLargeError stays the status error until an
+//     inventory is pushed.
+//   - Field redaction (Agent.PushRedactedFields): the listed field groups
+//     (cluster-names, network-names, datastore-ids, host-ids, vcenter-id) are
+//     replaced with collectorV1.RedactValue in the inventory sent, before the
+//     size check. The stored inventory, the outbox event and the local
+//     API keep the real values. Values are HMAC'd with a per-agent key
+//     generated once in the configuration table (ConfigurationStore.RedactionKey),
+//     so equal values redact alike across pushes and restarts, clusters and
+//     networks stay distinct, and guessed names cannot be matched. The unchanged-inventory check hashes the
+//     unredacted data. The aggregated inventory carries no VM names or IPs.
+//   - Push deadline: each inventory request gets the time to send it at 64 KiB/s
+//     (minPushThroughput), kept between Agent.InventoryPushTimeoutMin and
+//     Agent.InventoryPushTimeoutMax, so a large inventory on a slow link is not
+//     cut off while a stuck request still fails. A timed-out push is a
+//     transient error and is retried with backoff.
 //   - Legacy status mode compatibility for older console versions
 //
 // Data sent to console:
 //
-// On each dispatch cycle, two API calls are made:
+// On each dispatch cycle, two API calls are made. Every request carries
+// User-Agent "assisted-migration-agent/<version> (agent-id=<id>)" (see
+// console.UserAgent), so the console can attribute requests in its logs.
 //
 // 1. Agent Status (PUT /api/v1/agents/{id}/status):
 //
@@ -289,8 +466,14 @@
 //
 // Error handling:
 //   - Transient errors: Logged, stored in status.Error, loop continues with backoff
-//   - Fatal errors (4xx): Sets fatalStopped flag, exits run loop permanently
+//   - Fatal errors (4xx except 429): Sets fatalStopped flag, exits run loop permanently
+//   - A 404 or 410 on an inventory push means the console does not know the
+//     source; it is stored as SourceNotRegisteredError (fatal, as above) so
+//     GET /agent tells the operator to create the source or fix --source-id
 //   - Mode changes blocked after fatal stop to prevent retry loops
+//   - Each successful status or event request sets status.LastContactAt; errors
+//     and backoff leave it as it is, so GET /agent shows how long a stalled agent
+//     has been out of touch. It is not published to the status stream
 //
 // Shutdown protocol:
 //
@@ -307,15 +490,72 @@
 //	err = console.SetMode(ctx, models.AgentModeConnected)
 //	status := console.Status()
 //
+// # StatusBroker
+//
+// StatusBroker fans status changes out to subscribers such as the GET /events
+// stream. CollectorService, InspectorService and Console publish to it once
+// wired with WithStatusBroker; the ServiceManager creates one and wires all
+// three. Publish never blocks: a subscriber whose buffer is full misses the
+// event, and the next one carries the full status again.
+//
+//	events, unsubscribe := broker.Subscribe()
+//	defer unsubscribe()
+//	for event := range events { ... }
+//
+// # MaintenanceService
+//
+// MaintenanceService holds the maintenance (read-only) flag. The flag is loaded
+// from the configuration table on creation and persisted by SetEnabled, so it
+// survives restarts. Handlers check Enabled() before collection, inspection and
+// forced inventory pushes and reject them with MaintenanceModeError (503).
+//
+//	maintenance, err := services.NewMaintenanceService(store)
+//	err = maintenance.SetEnabled(ctx, true)
+//	if maintenance.Enabled() { ... }
+//
+// # StorageService
+//
+// StorageService measures the data folder: the DuckDB file and its WAL, the
+// uploaded VDDK, and the free space of the underlying filesystem. Compact
+// delegates to Store.Compact to reclaim space freed by deletes. It holds no
+// state. Without a data folder (in-memory database) both Usage and Compact
+// return a ResourceNotFoundError.
+//
+//	storage := services.NewStorageService(cfg.Agent.DataFolder, store)
+//	usage, err := storage.Usage(ctx)
+//	err = storage.Compact(ctx)
+//
 // # InventoryService
 //
-// InventoryService provides read-only access to collected inventory data.
+// InventoryService provides access to collected inventory data.
 // This is a lightweight stateless service that acts as a facade over the store layer.
 //
 // Usage:
 //
 //	inventoryService := services.NewInventoryService(store)
 //	inventory, err := inventoryService.GetInventory(ctx)
+//	diff, err := inventoryService.Diff(ctx)
+//	concerns, err := inventoryService.Revalidate(ctx)
+//
+// Diff compares the per-VM snapshots of the last two successful collections
+// (see InventoryStore.RotateSnapshot) using collector/v1.DiffInventories.
+// It returns ResourceNotFoundError until two collections have completed.
+//
+// Revalidate (POST /inventory/revalidate, through CollectorService.Revalidate,
+// which refuses while a collection runs) re-runs the store's validator on every
+// stored VM and replaces the concerns in one transaction
+// (VMStore.ReplaceConcerns), then rebuilds and saves the inventory and refreshes
+// group matches. The snapshots are not rotated. With WithEventService the
+// rebuilt inventory is queued in the outbox: the console has no partial update,
+// so the push is the same full inventory a collection sends.
+// CollectorService.Revalidate holds its lock only to read the VMs and to save
+// the result; the policies run without it. If a collection started or
+// finished in between, the result is dropped with CollectionInProgressError.
+//
+// Clusters (GET /clusters) reads the per-cluster infra data of the stored
+// inventory through collector/v1.ClusterMetrics. ClusterListParams keeps the
+// clusters reaching a minimum CPU or memory overcommitment; a cluster without
+// host data has no ratio and is dropped by either minimum.
 //
 // # VMService
 //
@@ -329,7 +569,8 @@
 //
 // Sorting:
 //   - Multiple sort fields with direction control (ascending/descending)
-//   - Default sort applied when no explicit sort specified
+//   - VMs are ordered by ID when no sort is given, and by ID after the given
+//     fields otherwise, so pages are stable and never overlap
 //   - Valid fields: name, vCenterState, cluster, diskSize, memory, issues
 //
 // Usage:
@@ -345,6 +586,35 @@
 //	}
 //	vms, total, err := vmService.List(ctx, params)
 //
+// WithSuppressedConcerns sets concern IDs (Agent.SuppressedConcerns) that List,
+// Count, OsDistribution and GroupBy ignore, through store.VMStore.WithoutConcerns:
+// they count neither towards issue counts nor migratability, and filters do not
+// see them. VMListParams.IncludeSuppressed turns this off for one call. Get and
+// GetMany return every concern.
+//
+// WithConcernAssessments sets text, by concern ID (Agent.ConcernAssessments),
+// that Get and GetMany append to the description of the matching VM issues
+// with collectorV1.EnrichAssessment. GET /inventory applies the same map to the
+// inventory migration issues with collectorV1.EnrichIssues.
+//
+// Stream passes every VM matching the filters to a callback, in batches of
+// 500 (WithStreamBatchSize) read with LIMIT/OFFSET, ignoring pagination. The
+// VM ID is appended as the last sort key so batches neither overlap nor skip
+// VMs with equal sort values. GET /vms uses it for JSON Lines responses.
+//
+// OsDistribution counts the VMs matching byExpression/hasCritical per guest
+// OS name (VMware Tools OS, else the configured one, else "Unknown OS").
+//
+// GroupBy counts them per value of one of store.GroupFields (cluster,
+// datacenter, host, os, vCenterState); any other field is a ValidationError,
+// so callers never put a client-chosen column into the query.
+//
+// Saved views:
+//   - CreateView, ListViews and GetView store named GET /vms queries
+//     (expression, sort, page size) in the vm_views table via store.ViewStore
+//   - The handler applies a view for ?view=<name>; parameters set explicitly in
+//     the request take precedence over the view
+//
 // # GroupService
 //
 // GroupService manages CRUD operations for groups. A group is a named filter
@@ -3,7 +3,12 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
 
@@ -12,6 +17,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 	"github.com/kubev2v/assisted-migration-agent/pkg/scheduler"
 	"github.com/kubev2v/assisted-migration-agent/pkg/work"
 
@@ -39,13 +45,21 @@ type inspectionService struct {
 	mu        sync.Mutex
 	detector  *vmdetect.Detector
 	store     *store.Store
+	timings   inspectionTimings
+	workers   int
+	gate      inspectionGate
+	// cancels holds, per VM, a channel closed when the VM is canceled, so a
+	// VM held back by a pause gives up waiting.
+	cancels map[string]chan struct{}
 }
 
 // newInspectionService returns an idle coordinator with no scheduler until Start.
 func newInspectionService(s *store.Store) *inspectionService {
 	return &inspectionService{
 		pipelines: make(map[string]*inspectionPipeline),
+		cancels:   make(map[string]chan struct{}),
 		store:     s,
+		workers:   defaultInspectionSchedulerNormalWorkers,
 	}
 }
 
@@ -56,7 +70,7 @@ func (i *inspectionService) Start(operator *vmware.VMManager, detector *vmdetect
 
 	i.operator = operator
 
-	sched, err := scheduler.NewScheduler[models.InspectionResult](defaultInspectionSchedulerNormalWorkers, defaultInspectionSchedulerReservedWorkers)
+	sched, err := scheduler.NewScheduler[models.InspectionResult](i.workers, defaultInspectionSchedulerReservedWorkers)
 	if err != nil {
 		return err
 	}
@@ -67,13 +81,24 @@ func (i *inspectionService) Start(operator *vmware.VMManager, detector *vmdetect
 	}
 
 	i.pipelines = make(map[string]*inspectionPipeline)
+	i.cancels = make(map[string]chan struct{})
+	i.timings.reset()
+	i.gate.reset()
 
 	i.detector = detector
 
 	zap.S().Named("inspection_service").Infow("starting VM inspection pipelines", "vmCount", len(vmIDs), "vmIds", vmIDs)
 
 	for _, id := range vmIDs {
-		pipeline := work.NewPipeline(models.InspectionStatus{State: models.InspectionStatePending}, i.scheduler, i.buildFn(id))
+		cancel := make(chan struct{})
+		builder := &gatedWorkBuilder{
+			id:     id,
+			inner:  &timedWorkBuilder{inner: i.buildFn(id), record: i.timings.add},
+			gate:   &i.gate,
+			cancel: cancel,
+		}
+		i.cancels[id] = cancel
+		pipeline := work.NewPipeline(models.InspectionStatus{State: models.InspectionStatePending}, i.scheduler, builder)
 		_ = pipeline.Start()
 		i.pipelines[id] = pipeline
 	}
@@ -84,6 +109,9 @@ func (i *inspectionService) Start(operator *vmware.VMManager, detector *vmdetect
 // Stop stops every pipeline under lock, then closes the scheduler.
 func (i *inspectionService) Stop() {
 	i.mu.Lock()
+	for id := range i.cancels {
+		i.cancelLocked(id)
+	}
 	for _, pipeline := range i.pipelines {
 		p := pipeline
 		if p != nil {
@@ -99,22 +127,107 @@ func (i *inspectionService) Stop() {
 	}
 }
 
+// Reset forgets the pipelines and timings of the last run. The caller makes
+// sure no run is in progress.
+func (i *inspectionService) Reset() {
+	i.mu.Lock()
+	i.pipelines = make(map[string]*inspectionPipeline)
+	i.cancels = make(map[string]chan struct{})
+	i.mu.Unlock()
+
+	i.timings.reset()
+}
+
+// Pause holds back the VMs whose inspection has not started; VMs already
+// being inspected run to the end. It reports false if already paused.
+func (i *inspectionService) Pause() bool {
+	return i.gate.pause()
+}
+
+// Resume lets the held back VMs start. It reports false if not paused.
+func (i *inspectionService) Resume() bool {
+	return i.gate.resume()
+}
+
 // WithWorkUnitsBuilder sets the function that produces work units per VM.
 func (i *inspectionService) WithWorkUnitsBuilder(builder inspectionWorkBuilder) *inspectionService {
 	i.buildFn = builder
 	return i
 }
 
+// Prioritize makes the given VMs start next, in the order given. Every VM must
+// be part of the run and not started yet; otherwise nothing is reordered and a
+// ValidationError is returned.
+func (i *inspectionService) Prioritize(ids []string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := i.pipelines[id]; !ok {
+			return srvErrors.NewValidationError(fmt.Sprintf("vm %s is not queued for inspection", id))
+		}
+	}
+
+	return i.gate.prioritize(ids)
+}
+
 // CancelVmInspection stops the pipeline for id, if present.
 func (i *inspectionService) CancelVmInspection(id string) {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
+	i.cancelLocked(id)
 	if p, ok := i.pipelines[id]; ok {
 		p.Stop()
 	}
 }
 
+// CancelVmsInspection stops the pipelines of ids whose VM is still pending or
+// running and reports, per id, what happened. A VM that already reports
+// completed is left alone even if its pipeline has a last no-op unit queued.
+// A repeated id is reported once.
+func (i *inspectionService) CancelVmsInspection(ids []string) []models.InspectionCancelResult {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	results := make([]models.InspectionCancelResult, 0, len(ids))
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		p, ok := i.pipelines[id]
+		if !ok {
+			results = append(results, models.InspectionCancelResult{VMID: id, Outcome: models.InspectionCancelOutcomeNotFound})
+			continue
+		}
+
+		outcome := models.InspectionCancelOutcomeAlreadyCompleted
+		switch pipelineStatus(p).State {
+		case models.InspectionStatePending, models.InspectionStateRunning:
+			i.cancelLocked(id)
+			p.Stop()
+			outcome = models.InspectionCancelOutcomeCanceled
+		}
+
+		results = append(results, models.InspectionCancelResult{VMID: id, Outcome: outcome})
+	}
+
+	return results
+}
+
+// cancelLocked releases the VM from the gate, if it is held back by it, and
+// lets the VMs prioritized after it start. The caller holds i.mu.
+func (i *inspectionService) cancelLocked(id string) {
+	i.gate.release(id)
+	if cancel, ok := i.cancels[id]; ok {
+		close(cancel)
+		delete(i.cancels, id)
+	}
+}
+
 // IsBusy reports whether any registered pipeline is still running.
 func (i *inspectionService) IsBusy() bool {
 	i.mu.Lock()
@@ -129,6 +242,21 @@ func (i *inspectionService) IsBusy() bool {
 	return false
 }
 
+// Progress returns the average duration of the VMs completed so far and the
+// number of VMs whose pipeline is still running.
+func (i *inspectionService) Progress() (time.Duration, int) {
+	i.mu.Lock()
+	remaining := 0
+	for _, p := range i.pipelines {
+		if p.IsRunning() {
+			remaining++
+		}
+	}
+	i.mu.Unlock()
+
+	return i.timings.average(), remaining
+}
+
 // GetVmStatus returns pull-based status from the VM’s pipeline (completed, running, error, canceled).
 func (i *inspectionService) GetVmStatus(id string) models.InspectionStatus {
 	i.mu.Lock()
@@ -139,6 +267,29 @@ func (i *inspectionService) GetVmStatus(id string) models.InspectionStatus {
 		return models.InspectionStatus{State: models.InspectionStateNotStarted}
 	}
 
+	return pipelineStatus(pipeline)
+}
+
+// ListVmStatuses returns the status of every VM in the run, keyed by VM ID. A
+// non-empty errorContains keeps only VMs whose error message contains it.
+func (i *inspectionService) ListVmStatuses(errorContains string) map[string]models.InspectionStatus {
+	i.mu.Lock()
+	pipelines := maps.Clone(i.pipelines)
+	i.mu.Unlock()
+
+	statuses := make(map[string]models.InspectionStatus, len(pipelines))
+	for id, pipeline := range pipelines {
+		status := pipelineStatus(pipeline)
+		if errorContains != "" && (status.Error == nil || !strings.Contains(status.Error.Error(), errorContains)) {
+			continue
+		}
+		statuses[id] = status
+	}
+	return statuses
+}
+
+// pipelineStatus maps a pipeline's state to the VM's inspection status.
+func pipelineStatus(pipeline *inspectionPipeline) models.InspectionStatus {
 	state := pipeline.State()
 	if state.Err != nil {
 		if errors.Is(state.Err, work.ErrStopped) {
@@ -308,3 +459,262 @@ func (i *inspectionService) removeSnapshot(ctx context.Context, vmId, snapId str
 
 	return nil
 }
+
+// inspectionTimings collects the durations of successfully inspected VMs for a run.
+type inspectionTimings struct {
+	mu        sync.Mutex
+	total     time.Duration
+	completed int
+}
+
+func (t *inspectionTimings) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total = 0
+	t.completed = 0
+}
+
+func (t *inspectionTimings) add(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total += d
+	t.completed++
+}
+
+func (t *inspectionTimings) average() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.completed == 0 {
+		return 0
+	}
+	return t.total / time.Duration(t.completed)
+}
+
+// timedWorkBuilder wraps a VM's work builder and reports how long the VM took,
+// from the moment its first work unit starts running on the scheduler until
+// the builder is exhausted. Time spent queued behind other VMs is not counted.
+// Failed or stopped pipelines never exhaust the builder and are not reported.
+type timedWorkBuilder struct {
+	inner   work.WorkBuilder[models.InspectionStatus, models.InspectionResult]
+	record  func(time.Duration)
+	started time.Time
+	first   bool
+	done    bool
+}
+
+func (b *timedWorkBuilder) Next() (inspectionWorkUnit, bool) {
+	unit, ok := b.inner.Next()
+	if !ok {
+		if !b.done && !b.started.IsZero() {
+			b.done = true
+			b.record(time.Since(b.started))
+		}
+		return unit, false
+	}
+
+	if !b.first {
+		b.first = true
+		fn := unit.Work
+		unit.Work = func(ctx context.Context, result models.InspectionResult) (models.InspectionResult, error) {
+			b.started = time.Now()
+			return fn(ctx, result)
+		}
+	}
+
+	return unit, true
+}
+
+// inspectionGate decides when a VM may start. It holds back every VM while
+// the inspector is paused, and any VM other than the first one while some VMs
+// are prioritized, so those start in the requested order.
+type inspectionGate struct {
+	mu     sync.Mutex
+	paused bool
+	// first lists the prioritized VMs that have not started yet, in the order
+	// they should start.
+	first []string
+	// done holds the VMs that started or were canceled.
+	done map[string]struct{}
+	// changed is closed and replaced whenever a held back VM may be able to
+	// start.
+	changed chan struct{}
+}
+
+// reset clears the gate for a new run.
+func (g *inspectionGate) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.paused = false
+	g.first = nil
+	g.done = make(map[string]struct{})
+	g.notifyLocked()
+}
+
+func (g *inspectionGate) pause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.paused {
+		return false
+	}
+	g.paused = true
+	return true
+}
+
+func (g *inspectionGate) resume() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.paused {
+		return false
+	}
+	g.paused = false
+	g.notifyLocked()
+	return true
+}
+
+// prioritize makes ids start before any other VM not started yet, in the
+// order given. VMs prioritized earlier and not started yet follow them. It
+// fails without changing anything if any id already started.
+func (g *inspectionGate) prioritize(ids []string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := g.done[id]; ok {
+			return srvErrors.NewValidationError(fmt.Sprintf("vm %s is not pending inspection", id))
+		}
+	}
+
+	first := make([]string, 0, len(ids)+len(g.first))
+	for _, id := range append(slices.Clone(ids), g.first...) {
+		if !slices.Contains(first, id) {
+			first = append(first, id)
+		}
+	}
+	g.first = first
+	g.notifyLocked()
+	return nil
+}
+
+// start reports whether id may start now and, if so, records it as started.
+func (g *inspectionGate) start(id string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.mayStartLocked(id) {
+		return false
+	}
+	g.releaseLocked(id)
+	return true
+}
+
+// release records id as done without starting it, so it no longer holds back
+// the VMs prioritized after it.
+func (g *inspectionGate) release(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.releaseLocked(id)
+}
+
+// wait blocks until id may start. It returns false if cancel is closed first.
+func (g *inspectionGate) wait(id string, cancel <-chan struct{}) bool {
+	for {
+		g.mu.Lock()
+		ok := g.mayStartLocked(id)
+		changed := g.changed
+		g.mu.Unlock()
+
+		if ok {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-cancel:
+			return false
+		}
+	}
+}
+
+func (g *inspectionGate) mayStartLocked(id string) bool {
+	return !g.paused && (len(g.first) == 0 || g.first[0] == id)
+}
+
+func (g *inspectionGate) releaseLocked(id string) {
+	if g.done == nil {
+		g.done = make(map[string]struct{})
+	}
+	g.done[id] = struct{}{}
+	if i := slices.Index(g.first, id); i >= 0 {
+		g.first = slices.Delete(g.first, i, i+1)
+		g.notifyLocked()
+	}
+}
+
+func (g *inspectionGate) notifyLocked() {
+	if g.changed != nil {
+		close(g.changed)
+	}
+	g.changed = make(chan struct{})
+}
+
+// gatedWorkBuilder wraps a VM's work builder so the VM does not start while
+// the gate holds it back. Every VM is queued on the scheduler at Start, so the
+// gate is checked when the VM's first unit gets a worker: if held back, the
+// unit returns without running and Next waits on the gate in the pipeline's
+// goroutine before queueing it again. A held back VM therefore takes no
+// worker, and the VMs already started still get theirs. The VM reports
+// pending until its first unit ran; one canceled while held back gets a unit
+// that fails with work.ErrStopped, so it reports canceled.
+type gatedWorkBuilder struct {
+	id      string
+	inner   work.WorkBuilder[models.InspectionStatus, models.InspectionResult]
+	gate    *inspectionGate
+	cancel  <-chan struct{}
+	first   *inspectionWorkUnit
+	started bool
+	held    bool
+}
+
+func (b *gatedWorkBuilder) Next() (inspectionWorkUnit, bool) {
+	if b.started {
+		return b.inner.Next()
+	}
+
+	if b.first == nil {
+		unit, ok := b.inner.Next()
+		if !ok {
+			b.started = true
+			b.gate.release(b.id)
+			return unit, false
+		}
+		b.first = &unit
+	}
+
+	pending := func() models.InspectionStatus {
+		return models.InspectionStatus{State: models.InspectionStatePending}
+	}
+
+	if b.held {
+		b.held = false
+		if !b.gate.wait(b.id, b.cancel) {
+			return inspectionWorkUnit{
+				Status: pending,
+				Work: func(_ context.Context, result models.InspectionResult) (models.InspectionResult, error) {
+					return result, work.ErrStopped
+				},
+			}, true
+		}
+	}
+
+	first := *b.first
+	return inspectionWorkUnit{
+		Status: pending,
+		Work: func(ctx context.Context, result models.InspectionResult) (models.InspectionResult, error) {
+			if !b.gate.start(b.id) {
+				b.held = true
+				return result, nil
+			}
+			b.started = true
+			return first.Work(ctx, result)
+		},
+	}, true
+}
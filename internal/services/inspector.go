@@ -28,11 +28,14 @@ type InspectorService struct {
 	mu              sync.Mutex
 	cred            *models.Credentials
 	vsphereClient   *govmomi.Client
+	releaseClient   func()
+	sessions        *vmware.SessionCache
 	inspectionSvc   *inspectionService
 	state           InspectorState
 	stop            chan struct{}
 	inspectionLimit int
 	vddkLibDir      string
+	store           *store.Store
 }
 
 // NewInspectorService returns an idle inspector using the default inspection work units
@@ -46,12 +49,25 @@ func NewInspectorService(s *store.Store, inspectionLimit int, dateDir string) *I
 		inspectionSvc:   newInspectionService(s),
 		inspectionLimit: inspectionLimit,
 		vddkLibDir:      filepath.Join(dateDir, vddkFolder, vddkLibPath),
+		store:           s,
 	}
 }
 
-// GetStatus returns the current inspector status.
+// GetStatus returns the current inspector status. While running, it includes an
+// estimate of when the remaining VMs will finish, based on the average duration
+// of the VMs completed so far and the number of scheduler workers.
 func (i *InspectorService) GetStatus() models.InspectorStatus {
 	s := i.state.Status()
+
+	avg, remaining := i.inspectionSvc.Progress()
+	s.AverageVmDuration = avg
+	if avg > 0 && remaining > 0 && s.State == models.InspectorStateRunning {
+		workers := i.inspectionSvc.workers
+		batches := (remaining + workers - 1) / workers
+		eta := time.Now().Add(avg * time.Duration(batches))
+		s.EstimatedCompletion = &eta
+	}
+
 	if i.cred != nil {
 		c := &models.Credentials{
 			URL:      i.cred.URL,
@@ -87,7 +103,7 @@ func (i *InspectorService) Start(ctx context.Context, vmIDs []string) error {
 	i.state.Set(models.InspectorStateInitiating)
 	zap.S().Infow("starting inspector", "vmCount", len(vmIDs))
 
-	vClient, err := vmware.NewVsphereClient(ctx, i.cred.URL, i.cred.Username, i.cred.Password, true)
+	vClient, release, err := i.sessions.Acquire(ctx, *i.cred)
 	if err != nil {
 		zap.S().Named("inspector_service").Errorw("failed to connect to vSphere", "error", err)
 		i.state.SetError(err)
@@ -97,6 +113,7 @@ func (i *InspectorService) Start(ctx context.Context, vmIDs []string) error {
 	zap.S().Named("inspector_service").Info("vSphere connection established")
 
 	i.vsphereClient = vClient
+	i.releaseClient = release
 	i.stop = make(chan struct{}, 1)
 
 	detector, err := vmdetect.NewDetector(vmdetect.DetectorConfig{
@@ -109,26 +126,33 @@ func (i *InspectorService) Start(ctx context.Context, vmIDs []string) error {
 		Logger:     logrus.StandardLogger(),
 	})
 	if err != nil {
+		i.closeVsphereClient()
 		return err
 	}
 
 	vmwareOperator := vmware.NewVMManager(i.vsphereClient, i.cred.Username)
 	if err := i.inspectionSvc.Start(vmwareOperator, detector, vmIDs); err != nil {
 		i.inspectionSvc.Stop()
-		_ = i.closeVsphereClient(ctx)
+		i.closeVsphereClient()
 		i.state.SetError(err)
 		return err
 	}
 
+	// Set before run starts so a Pause right after Start is not overwritten.
+	i.state.Set(models.InspectorStateRunning)
 	go i.run(context.Background())
 
 	return nil
 }
 
 func (i *InspectorService) Credentials(ctx context.Context, credentials models.Credentials) error {
-	if err := vmware.VerifyCredentials(ctx, &credentials, "inspector"); err != nil {
+	// The inspector always connects without verifying the vCenter certificate.
+	credentials.Insecure = true
+	_, release, err := i.sessions.Acquire(ctx, credentials)
+	if err != nil {
 		return srvErrors.NewVCenterError(err)
 	}
+	release()
 
 	i.cred = &credentials
 	return nil
@@ -155,6 +179,65 @@ func (i *InspectorService) Stop() error {
 	return nil
 }
 
+// Pause stops new VM inspections from starting, e.g. during a vCenter
+// maintenance window. VMs already being inspected finish; the others stay
+// pending in the queue until Resume. Pausing a paused inspector does nothing.
+// Returns InspectorNotRunningError if no run is in progress.
+func (i *InspectorService) Pause(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.IsBusy() {
+		return srvErrors.NewInspectorNotRunningError()
+	}
+
+	if i.inspectionSvc.Pause() {
+		zap.S().Named("inspector_service").Info("inspection paused")
+		i.state.Set(models.InspectorStatePaused)
+	}
+	return nil
+}
+
+// Resume lets the pending VMs of a paused run start again. Resuming a running
+// inspector does nothing. Returns InspectorNotRunningError if no run is in
+// progress.
+func (i *InspectorService) Resume(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.IsBusy() {
+		return srvErrors.NewInspectorNotRunningError()
+	}
+
+	if i.inspectionSvc.Resume() {
+		zap.S().Named("inspector_service").Info("inspection resumed")
+		i.state.Set(models.InspectorStateRunning)
+	}
+	return nil
+}
+
+// Reset returns a finished inspector (completed, canceled or error) to ready.
+// It deletes the persisted inspection statuses and forgets the last run's
+// pipelines and timings; inspection results are kept. Returns
+// InspectionInProgressError while a run is in progress.
+func (i *InspectorService) Reset(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.IsBusy() {
+		return srvErrors.NewInspectionInProgressError()
+	}
+
+	if err := i.store.Inspection().DeleteAll(ctx); err != nil {
+		return err
+	}
+
+	i.inspectionSvc.Reset()
+	i.state.Set(models.InspectorStateReady)
+
+	return nil
+}
+
 // Cancel stops the pipeline for a single VM ID. Returns InspectorNotRunningError if the service is idle.
 func (i *InspectorService) Cancel(id string) error {
 	i.mu.Lock()
@@ -169,6 +252,77 @@ func (i *InspectorService) Cancel(id string) error {
 	return nil
 }
 
+// CancelVmsInspection cancels the inspection of every VM in ids that is still
+// pending or running, leaving the rest of the run alone. It returns one result
+// per distinct id, in request order: canceled, already-completed when the VM's
+// inspection had ended, or not-found when the VM is not part of the run.
+func (i *InspectorService) CancelVmsInspection(ctx context.Context, ids ...string) ([]models.InspectionCancelResult, error) {
+	if len(ids) == 0 {
+		return nil, srvErrors.NewValidationError("at least one vm id is required")
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.IsBusy() {
+		return nil, srvErrors.NewInspectorNotRunningError()
+	}
+
+	return i.inspectionSvc.CancelVmsInspection(ids), nil
+}
+
+// ListVmStatuses returns the inspection status of every VM in the current or
+// last run, keyed by VM ID, as read from the VM pipelines. A non-empty
+// errorContains keeps only VMs whose error message contains it.
+func (i *InspectorService) ListVmStatuses(errorContains string) map[string]models.InspectionStatus {
+	return i.inspectionSvc.ListVmStatuses(errorContains)
+}
+
+// Prioritize moves the given VMs to the front of the inspection queue, in the
+// order given, ahead of any VM prioritized earlier. Every VM must be pending;
+// otherwise nothing is reordered and a ValidationError is returned. Returns
+// InspectorNotRunningError if no run is in progress.
+func (i *InspectorService) Prioritize(ctx context.Context, vmIDs []string) error {
+	if len(vmIDs) == 0 {
+		return srvErrors.NewValidationError("at least one vm id is required")
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.IsBusy() {
+		return srvErrors.NewInspectorNotRunningError()
+	}
+
+	return i.inspectionSvc.Prioritize(vmIDs)
+}
+
+// WithStatusBroker publishes every inspector state change to broker.
+func (i *InspectorService) WithStatusBroker(broker *StatusBroker) *InspectorService {
+	i.state.mu.Lock()
+	defer i.state.mu.Unlock()
+	i.state.onChange = func() {
+		broker.Publish(models.StatusEvent{Source: models.StatusEventInspector, Status: i.GetStatus()})
+	}
+	return i
+}
+
+// WithSessionCache makes the inspector log in to vCenter through sessions,
+// reusing a session left by a recent collection or inspection.
+func (i *InspectorService) WithSessionCache(sessions *vmware.SessionCache) *InspectorService {
+	i.sessions = sessions
+	return i
+}
+
+// WithWorkers sets how many VMs are inspected in parallel. Non-positive values
+// keep the default of five. It takes effect on the next Start.
+func (i *InspectorService) WithWorkers(workers int) *InspectorService {
+	if workers > 0 {
+		i.inspectionSvc.workers = workers
+	}
+	return i
+}
+
 // WithInspectionBuilder replaces the default per-VM work unit list.
 func (i *InspectorService) WithInspectionBuilder(builder inspectionWorkBuilder) *InspectorService {
 	i.inspectionSvc.WithWorkUnitsBuilder(builder)
@@ -185,14 +339,13 @@ func (i *InspectorService) IsBusy() bool {
 	}
 }
 
-// run marks Running, polls until no inspection pipeline is busy, then logs out and sets Completed or Canceled.
+// run polls until no inspection pipeline is busy, then logs out and sets Completed or Canceled.
 func (i *InspectorService) run(ctx context.Context) {
-	i.state.Set(models.InspectorStateRunning)
 	ticker := time.NewTicker(5 * time.Second)
 	cancel := false
 
 	defer func() {
-		_ = i.closeVsphereClient(ctx)
+		i.closeVsphereClient()
 		ticker.Stop()
 		i.mu.Lock()
 		i.stop = nil
@@ -217,15 +370,14 @@ func (i *InspectorService) run(ctx context.Context) {
 	}
 }
 
-func (i *InspectorService) closeVsphereClient(ctx context.Context) error {
-	logoutCtx, cancel := context.WithTimeout(ctx, time.Second*10)
-	defer cancel()
-
-	if i.vsphereClient != nil {
-		return i.vsphereClient.Logout(logoutCtx)
+// closeVsphereClient hands the run's vSphere session back to the session
+// cache, which logs it out once it is no longer reused.
+func (i *InspectorService) closeVsphereClient() {
+	if i.releaseClient != nil {
+		i.releaseClient()
+		i.releaseClient = nil
 	}
-
-	return nil
+	i.vsphereClient = nil
 }
 
 // InspectorState holds the Inspector status with its own mutex for thread-safe access.
@@ -233,6 +385,8 @@ type InspectorState struct {
 	mu    sync.Mutex
 	state models.InspectorState
 	err   error
+	// onChange, when set, is called after every Set and SetError, outside the lock.
+	onChange func()
 }
 
 func (s *InspectorState) Status() models.InspectorStatus {
@@ -246,14 +400,24 @@ func (s *InspectorState) Status() models.InspectorStatus {
 
 func (s *InspectorState) Set(st models.InspectorState) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	s.state = st
 	s.err = nil
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
 }
 
 func (s *InspectorState) SetError(err error) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	s.state = models.InspectorStateError
 	s.err = err
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
 }
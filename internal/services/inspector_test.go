@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"slices"
 	"sync"
 	"time"
 
@@ -34,6 +35,9 @@ type mockInspectionBuilder struct {
 	delay     time.Duration
 	vmErrors  map[string]error
 	inspected []string
+	started   []string
+	running   int
+	peak      int
 	mu        sync.Mutex
 	st        *store.Store
 	concerns  map[string][]models.VmInspectionConcern
@@ -65,6 +69,20 @@ func (m *mockInspectionBuilder) getInspectedVMs() []string {
 	return append([]string(nil), m.inspected...)
 }
 
+// getStartedVMs returns the VMs whose inspection started, in start order.
+func (m *mockInspectionBuilder) getStartedVMs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.started...)
+}
+
+// getPeakConcurrency returns the largest number of VMs that were inspected at the same time.
+func (m *mockInspectionBuilder) getPeakConcurrency() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.peak
+}
+
 func (m *mockInspectionBuilder) builder() func(id string) work.WorkBuilder[models.InspectionStatus, models.InspectionResult] {
 	return func(id string) work.WorkBuilder[models.InspectionStatus, models.InspectionResult] {
 		return work.NewSliceWorkBuilder([]work.WorkUnit[models.InspectionStatus, models.InspectionResult]{
@@ -73,6 +91,16 @@ func (m *mockInspectionBuilder) builder() func(id string) work.WorkBuilder[model
 					return models.InspectionStatus{State: models.InspectionStateRunning}
 				},
 				Work: func(ctx context.Context, result models.InspectionResult) (models.InspectionResult, error) {
+					m.mu.Lock()
+					m.started = append(m.started, id)
+					m.running++
+					m.peak = max(m.peak, m.running)
+					m.mu.Unlock()
+					defer func() {
+						m.mu.Lock()
+						m.running--
+						m.mu.Unlock()
+					}()
 					if m.delay > 0 {
 						select {
 						case <-time.After(m.delay):
@@ -206,6 +234,140 @@ var _ = Describe("InspectorService", func() {
 		})
 	})
 
+	Describe("Prioritize", func() {
+		// Given an inspector that was never started
+		// When we prioritize a VM
+		// Then it should return InspectorNotRunningError
+		It("should fail when the inspector is not running", func() {
+			err := srv.Prioritize(ctx, []string{"vm-1"})
+			Expect(srvErrors.IsInspectorNotRunningError(err)).To(BeTrue())
+		})
+
+		// Given a single worker inspecting four slow VMs, one of them started
+		// When we prioritize the last two pending VMs, the later one first
+		// Then they should start next, in the requested order, before the rest
+		It("should start prioritized VMs first, in the order given", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(200 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithWorkers(1).WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			vms := []string{"vm-1", "vm-2", "vm-3", "vm-4"}
+			Expect(srv.Start(ctx, vms)).To(Succeed())
+			Eventually(builder.getStartedVMs).Should(HaveLen(1))
+			first := builder.getStartedVMs()[0]
+			pending := slices.DeleteFunc(slices.Clone(vms), func(id string) bool { return id == first })
+
+			// Act
+			err := srv.Prioritize(ctx, []string{pending[2], pending[1]})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+			Expect(builder.getStartedVMs()).To(Equal([]string{first, pending[2], pending[1], pending[0]}))
+		})
+
+		// Given a single worker inspecting slow VMs, one of them started
+		// When we prioritize a pending VM together with the started one
+		// Then it should return a ValidationError and reorder nothing
+		It("should reject VMs that already started", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(200 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithWorkers(1).WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			Expect(srv.Start(ctx, []string{"vm-1", "vm-2"})).To(Succeed())
+			Eventually(builder.getStartedVMs).Should(HaveLen(1))
+			first := builder.getStartedVMs()[0]
+
+			// Act
+			err := srv.Prioritize(ctx, []string{"vm-1", "vm-2", first})
+
+			// Assert
+			Expect(srvErrors.IsValidationError(err)).To(BeTrue())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+		})
+
+		// Given a running inspector
+		// When we prioritize a VM that is not part of the run
+		// Then it should return a ValidationError
+		It("should reject unknown VMs", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(200 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			Expect(srv.Start(ctx, []string{"vm-1"})).To(Succeed())
+
+			// Act
+			err := srv.Prioritize(ctx, []string{"vm-unknown"})
+
+			// Assert
+			Expect(srvErrors.IsValidationError(err)).To(BeTrue())
+		})
+
+		// Given a single worker with one VM started and the next VMs prioritized
+		// When the first prioritized VM is canceled
+		// Then the second one should start next and the run should complete
+		It("should not wait for a canceled prioritized VM", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(200 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithWorkers(1).WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			vms := []string{"vm-1", "vm-2", "vm-3"}
+			Expect(srv.Start(ctx, vms)).To(Succeed())
+			Eventually(builder.getStartedVMs).Should(HaveLen(1))
+			first := builder.getStartedVMs()[0]
+			pending := slices.DeleteFunc(slices.Clone(vms), func(id string) bool { return id == first })
+			Expect(srv.Prioritize(ctx, []string{pending[0], pending[1]})).To(Succeed())
+
+			// Act
+			Expect(srv.Cancel(pending[0])).To(Succeed())
+
+			// Assert
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+			Expect(builder.getStartedVMs()).To(Equal([]string{first, pending[1]}))
+		})
+	})
+
+	Describe("ListVmStatuses", func() {
+		BeforeEach(func() {
+			lockErr := errors.New("snapshot failed: disk is locked")
+			builder := newMockInspectionBuilder().withVmError("vm-1", lockErr).withVmError("vm-3", lockErr)
+			srv = services.NewInspectorService(st, 10, "").WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			Expect(srv.Start(ctx, []string{"vm-1", "vm-2", "vm-3"})).To(Succeed())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+		})
+
+		// Given two VMs that failed with the same error
+		// When we list statuses by that error substring
+		// Then only those VMs are returned
+		It("should return only VMs whose error contains the substring", func() {
+			statuses := srv.ListVmStatuses("disk is locked")
+
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses).To(HaveKey("vm-1"))
+			Expect(statuses).To(HaveKey("vm-3"))
+			Expect(statuses["vm-1"].State).To(Equal(models.InspectionStateError))
+		})
+
+		// Given a finished run
+		// When we list statuses without a substring
+		// Then every VM is returned
+		It("should return all VMs without a substring", func() {
+			statuses := srv.ListVmStatuses("")
+
+			Expect(statuses).To(HaveLen(3))
+			Expect(statuses["vm-2"].State).To(Equal(models.InspectionStateCompleted))
+		})
+	})
+
 	Describe("Cancel", func() {
 
 		Context("when inspector is not started", func() {
@@ -284,6 +446,64 @@ var _ = Describe("InspectorService", func() {
 		})
 	})
 
+	Describe("CancelVmsInspection", func() {
+		// Given an inspector that was never started
+		// When several VMs are canceled
+		// Then it should return InspectorNotRunningError
+		It("should fail when the inspector is not running", func() {
+			// Act
+			results, err := srv.CancelVmsInspection(ctx, "vm-1", "vm-2")
+
+			// Assert
+			Expect(srvErrors.IsInspectorNotRunningError(err)).To(BeTrue())
+			Expect(results).To(BeNil())
+		})
+
+		// Given a single worker run where one VM has finished and the others are still queued
+		// When a finished, a queued and an unknown VM are canceled in one call
+		// Then each should get its own outcome and the remaining VM should still be inspected
+		It("should report mixed outcomes and let the rest of the run continue", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(300 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithWorkers(1).WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			vmIDs := []string{"vm-1", "vm-2", "vm-3"}
+			Expect(srv.Start(ctx, vmIDs)).To(Succeed())
+
+			Eventually(func() []string {
+				return builder.getInspectedVMs()
+			}, 10*time.Second).ShouldNot(BeEmpty())
+			done := builder.getInspectedVMs()[0]
+			Eventually(func() models.InspectionState {
+				return srv.GetVmStatus(done).State
+			}, 5*time.Second).Should(Equal(models.InspectionStateCompleted))
+
+			var queued []string
+			for _, id := range vmIDs {
+				if id != done {
+					queued = append(queued, id)
+				}
+			}
+
+			// Act
+			results, err := srv.CancelVmsInspection(ctx, queued[0], done, "vm-99", queued[0])
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(Equal([]models.InspectionCancelResult{
+				{VMID: queued[0], Outcome: models.InspectionCancelOutcomeCanceled},
+				{VMID: done, Outcome: models.InspectionCancelOutcomeAlreadyCompleted},
+				{VMID: "vm-99", Outcome: models.InspectionCancelOutcomeNotFound},
+			}))
+			Expect(srv.GetVmStatus(queued[0]).State).To(Equal(models.InspectionStateCanceled))
+			Expect(srv.GetVmStatus(done).State).To(Equal(models.InspectionStateCompleted))
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+			Expect(srv.GetVmStatus(queued[1]).State).To(Equal(models.InspectionStateCompleted))
+		})
+	})
+
 	Describe("Start", func() {
 		It("should complete inspection successfully for single VM", func() {
 			builder := newMockInspectionBuilder().withStore(st).withVmConcerns("vm-1", []models.VmInspectionConcern{
@@ -468,6 +688,172 @@ var _ = Describe("InspectorService", func() {
 		})
 	})
 
+	Describe("Pause", func() {
+		// Given an inspector that was never started
+		// When Pause or Resume is called
+		// Then both should return InspectorNotRunningError
+		It("should fail when the inspector is not running", func() {
+			// Act
+			pauseErr := srv.Pause(ctx)
+			resumeErr := srv.Resume(ctx)
+
+			// Assert
+			Expect(srvErrors.IsInspectorNotRunningError(pauseErr)).To(BeTrue())
+			Expect(srvErrors.IsInspectorNotRunningError(resumeErr)).To(BeTrue())
+		})
+
+		// Given a single worker inspecting three slow VMs
+		// When the inspector is paused right after Start and later resumed
+		// Then no VM should start while paused, the waiting VMs should stay
+		// pending, and the run should complete once resumed
+		It("should hold back new VMs until resumed", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(300 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithWorkers(1).WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			Expect(srv.Start(ctx, []string{"vm-1", "vm-2", "vm-3"})).To(Succeed())
+
+			// Act
+			Expect(srv.Pause(ctx)).To(Succeed())
+
+			// Assert
+			Expect(srv.GetStatus().State).To(Equal(models.InspectorStatePaused))
+			Consistently(func() int {
+				return len(builder.getInspectedVMs())
+			}, time.Second, 50*time.Millisecond).Should(BeNumerically("<=", 1))
+			pending := 0
+			for _, id := range []string{"vm-1", "vm-2", "vm-3"} {
+				if srv.GetVmStatus(id).State == models.InspectionStatePending {
+					pending++
+				}
+			}
+			Expect(pending).To(BeNumerically(">=", 2))
+			Expect(srv.GetStatus().State).To(Equal(models.InspectorStatePaused))
+
+			// Act
+			Expect(srv.Resume(ctx)).To(Succeed())
+
+			// Assert
+			Expect(srv.GetStatus().State).To(Equal(models.InspectorStateRunning))
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+			Expect(builder.getInspectedVMs()).To(ConsistOf("vm-1", "vm-2", "vm-3"))
+		})
+
+		// Given a paused inspector with VMs held back
+		// When one VM is canceled and the inspector is then stopped
+		// Then the VM should be canceled and the run should end canceled
+		It("should cancel and stop held back VMs", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(300 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithWorkers(1).WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			Expect(srv.Start(ctx, []string{"vm-1", "vm-2", "vm-3"})).To(Succeed())
+			Expect(srv.Pause(ctx)).To(Succeed())
+
+			// Act
+			Expect(srv.Cancel("vm-3")).To(Succeed())
+			Expect(srv.Stop()).To(Succeed())
+
+			// Assert
+			Expect(srv.GetVmStatus("vm-3").State).To(Equal(models.InspectionStateCanceled))
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCanceled))
+		})
+	})
+
+	Describe("Reset", func() {
+		// Given a completed inspection run with persisted VM statuses and results
+		// When the inspector is reset
+		// Then it should be ready, the statuses deleted and the results kept
+		It("should return a completed inspector to ready", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withStore(st).withVmConcerns("vm-1", []models.VmInspectionConcern{
+				{Category: "disk", Label: "L1", Msg: "m1"},
+			})
+			srv = services.NewInspectorService(st, 10, "").WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			Expect(st.Inspection().Add(ctx, []string{"vm-1", "vm-2"}, models.InspectionStateCompleted)).To(Succeed())
+			Expect(srv.Start(ctx, []string{"vm-1", "vm-2"})).To(Succeed())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+
+			// Act
+			err := srv.Reset(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			status := srv.GetStatus()
+			Expect(status.State).To(Equal(models.InspectorStateReady))
+			Expect(status.AverageVmDuration).To(BeZero())
+			Expect(srv.GetVmStatus("vm-1").State).To(Equal(models.InspectionStateNotStarted))
+
+			Expect(srv.ListVmStatuses("")).To(BeEmpty())
+			statuses, err := st.Inspection().List(ctx, store.NewInspectionQueryFilter())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(BeEmpty())
+
+			results, err := st.Inspection().ListResults(ctx, "vm-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+		})
+
+		// Given a canceled inspection run
+		// When the inspector is reset
+		// Then it should be ready and accept a new run
+		It("should return a canceled inspector to ready", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(time.Second)
+			srv = services.NewInspectorService(st, 10, "").WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			Expect(srv.Start(ctx, []string{"vm-1"})).To(Succeed())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}).Should(Equal(models.InspectorStateRunning))
+			Expect(srv.Stop()).To(Succeed())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCanceled))
+
+			// Act
+			err := srv.Reset(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(srv.GetStatus().State).To(Equal(models.InspectorStateReady))
+			Expect(srv.GetVmStatus("vm-1").State).To(Equal(models.InspectionStateNotStarted))
+		})
+
+		// Given an inspection run in progress
+		// When the inspector is reset
+		// Then it should fail and leave the run and the statuses alone
+		It("should refuse to reset while a run is in progress", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(time.Second)
+			srv = services.NewInspectorService(st, 10, "").WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+			Expect(st.Inspection().Add(ctx, []string{"vm-1"}, models.InspectionStatePending)).To(Succeed())
+			Expect(srv.Start(ctx, []string{"vm-1"})).To(Succeed())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}).Should(Equal(models.InspectorStateRunning))
+
+			// Act
+			err := srv.Reset(ctx)
+
+			// Assert
+			Expect(srvErrors.IsOperationInProgressError(err)).To(BeTrue())
+			Expect(srv.GetStatus().State).To(Equal(models.InspectorStateRunning))
+			Expect(srv.ListVmStatuses("")).To(HaveKey("vm-1"))
+			statuses, err := st.Inspection().List(ctx, store.NewInspectionQueryFilter())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveKey("vm-1"))
+		})
+	})
+
 	Describe("Inspection limit", func() {
 		It("should return InspectionLimitReachedError when Start receives more VM IDs than the limit", func() {
 			builder := newMockInspectionBuilder()
@@ -518,6 +904,103 @@ var _ = Describe("InspectorService", func() {
 		})
 	})
 
+	Describe("Workers", func() {
+		BeforeEach(func() {
+			insertVM("vm-4", "test-vm-4")
+			insertVM("vm-5", "test-vm-5")
+			insertVM("vm-6", "test-vm-6")
+		})
+
+		// Given an inspector configured with two workers
+		// When six slow VMs are inspected
+		// Then at most two VMs should be inspected at the same time
+		It("should inspect as many VMs in parallel as configured", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(100 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithWorkers(2).WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+
+			// Act
+			Expect(srv.Start(ctx, []string{"vm-1", "vm-2", "vm-3", "vm-4", "vm-5", "vm-6"})).To(Succeed())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+
+			// Assert
+			Expect(builder.getInspectedVMs()).To(HaveLen(6))
+			Expect(builder.getPeakConcurrency()).To(Equal(2))
+		})
+
+		// Given an inspector configured with a non-positive worker count
+		// When six slow VMs are inspected
+		// Then the default of five workers should be used
+		It("should keep the default for a non-positive worker count", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(100 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithWorkers(0).WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+
+			// Act
+			Expect(srv.Start(ctx, []string{"vm-1", "vm-2", "vm-3", "vm-4", "vm-5", "vm-6"})).To(Succeed())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+
+			// Assert
+			Expect(builder.getPeakConcurrency()).To(Equal(5))
+		})
+	})
+
+	Describe("Progress estimate", func() {
+		// Given eight VMs that each take 300ms to inspect on five workers
+		// When the first batch of VMs completes
+		// Then GetStatus should report the average duration and an estimated completion
+		It("should estimate completion from completed VMs", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(300 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+
+			// Act
+			err := srv.Start(ctx, []string{"vm-1", "vm-2", "vm-3", "vm-4", "vm-5", "vm-6", "vm-7", "vm-8"})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(srv.GetStatus().AverageVmDuration).To(BeZero())
+
+			var status models.InspectorStatus
+			Eventually(func() *time.Time {
+				status = srv.GetStatus()
+				return status.EstimatedCompletion
+			}, 5*time.Second, 50*time.Millisecond).ShouldNot(BeNil())
+			Expect(status.State).To(Equal(models.InspectorStateRunning))
+			Expect(status.AverageVmDuration).To(BeNumerically(">=", 300*time.Millisecond))
+			Expect(status.AverageVmDuration).To(BeNumerically("<", time.Second))
+			Expect(*status.EstimatedCompletion).To(BeTemporally("~", time.Now().Add(status.AverageVmDuration), time.Second))
+		})
+
+		// Given an inspection run that has finished
+		// When we read the status
+		// Then the average duration is kept and there is no estimated completion
+		It("should drop the estimate once the run completes", func() {
+			// Arrange
+			builder := newMockInspectionBuilder().withWorkDelay(50 * time.Millisecond)
+			srv = services.NewInspectorService(st, 10, "").WithInspectionBuilder(builder.builder())
+			Expect(srv.Credentials(ctx, *getVCenterCredentials())).To(Succeed())
+
+			// Act
+			Expect(srv.Start(ctx, []string{"vm-1", "vm-2"})).To(Succeed())
+			Eventually(func() models.InspectorState {
+				return srv.GetStatus().State
+			}, 10*time.Second).Should(Equal(models.InspectorStateCompleted))
+
+			// Assert
+			status := srv.GetStatus()
+			Expect(status.AverageVmDuration).To(BeNumerically(">=", 50*time.Millisecond))
+			Expect(status.EstimatedCompletion).To(BeNil())
+		})
+	})
+
 	Describe("store persistence (mock inspection)", func() {
 
 		It("should use only the latest inspection run for VM list concern count when the same VM is inspected twice", func() {
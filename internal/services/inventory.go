@@ -2,13 +2,26 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	"github.com/kubev2v/migration-planner/pkg/duckdb_parser"
+	duckdb_models "github.com/kubev2v/migration-planner/pkg/duckdb_parser/models"
+	"go.uber.org/zap"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
 )
 
 type InventoryService struct {
-	store *store.Store
+	store      *store.Store
+	eventSrv   *EventService
+	tiers      collectorV1.Tiers
+	categories collectorV1.ConcernCategories
 }
 
 func NewInventoryService(st *store.Store) *InventoryService {
@@ -19,7 +32,242 @@ func NewInventoryService(st *store.Store) *InventoryService {
 	return srv
 }
 
+// WithEventService makes Revalidate queue the rebuilt inventory for the
+// console.
+func (c *InventoryService) WithEventService(eventSrv *EventService) *InventoryService {
+	c.eventSrv = eventSrv
+	return c
+}
+
+// WithTiers makes Revalidate bucket the rebuilt inventory's tier
+// distributions with tiers, as collections do.
+func (c *InventoryService) WithTiers(tiers collectorV1.Tiers) *InventoryService {
+	c.tiers = tiers
+	return c
+}
+
+// WithConcernCategories makes Revalidate count the rebuilt inventory's
+// migration issues with the concern categories mapped by categories, as
+// collections do.
+func (c *InventoryService) WithConcernCategories(categories collectorV1.ConcernCategories) *InventoryService {
+	c.categories = categories
+	return c
+}
+
 // GetInventory retrieves the stored inventory.
 func (c *InventoryService) GetInventory(ctx context.Context) (*models.Inventory, error) {
 	return c.store.Inventory().Get(ctx)
 }
+
+// History returns when each kept inventory was collected, newest first.
+func (c *InventoryService) History(ctx context.Context) ([]time.Time, error) {
+	return c.store.Inventory().History(ctx)
+}
+
+// CollectedAt returns when the stored inventory was collected, or a
+// ResourceNotFoundError if nothing was collected yet. Revalidating the
+// inventory does not change it.
+func (c *InventoryService) CollectedAt(ctx context.Context) (time.Time, error) {
+	inv, err := c.store.Inventory().Get(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	history, err := c.store.Inventory().History(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// The history always keeps the newest collection, unless the inventory
+	// was stored before the history existed.
+	if len(history) == 0 {
+		return inv.UpdatedAt, nil
+	}
+	return history[0], nil
+}
+
+// GetInventoryAt returns the inventory collected at takenAt, or a
+// ResourceNotFoundError if the history no longer keeps it.
+func (c *InventoryService) GetInventoryAt(ctx context.Context, takenAt time.Time) (*models.Inventory, error) {
+	return c.store.Inventory().GetAt(ctx, takenAt.UTC())
+}
+
+// GetWarnings returns the non-fatal issues raised by the last successful
+// collection.
+func (c *InventoryService) GetWarnings(ctx context.Context) ([]string, error) {
+	return c.store.Inventory().Warnings(ctx)
+}
+
+// Metrics returns the vCenter-wide totals of the stored inventory, or a
+// ResourceNotFoundError when nothing was collected yet.
+func (c *InventoryService) Metrics(ctx context.Context) (*models.InventoryMetrics, error) {
+	inv, err := c.store.Inventory().Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var inventory v1alpha1.Inventory
+	if err := json.Unmarshal(inv.Data, &inventory); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal inventory: %w", err)
+	}
+
+	metrics := collectorV1.Metrics(inventory)
+	metrics.UpdatedAt = inv.UpdatedAt
+	return &metrics, nil
+}
+
+// ClusterListParams filters the clusters returned by Clusters. A minimum
+// overcommitment, when set, also drops the clusters without that ratio.
+type ClusterListParams struct {
+	MinCPUOverCommitment    *float64
+	MinMemoryOverCommitment *float64
+}
+
+// Clusters returns the totals of each cluster of the stored inventory that
+// matches params, in cluster ID order, or a ResourceNotFoundError when
+// nothing was collected yet.
+func (c *InventoryService) Clusters(ctx context.Context, params ClusterListParams) ([]models.ClusterMetrics, error) {
+	inv, err := c.store.Inventory().Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var inventory v1alpha1.Inventory
+	if err := json.Unmarshal(inv.Data, &inventory); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal inventory: %w", err)
+	}
+
+	clusters := collectorV1.ClusterMetrics(inventory)
+	return slices.DeleteFunc(clusters, func(cluster models.ClusterMetrics) bool {
+		return !atLeast(cluster.CPUOverCommitment, params.MinCPUOverCommitment) ||
+			!atLeast(cluster.MemoryOverCommitment, params.MinMemoryOverCommitment)
+	}), nil
+}
+
+// atLeast reports whether value reaches threshold. Without a threshold every
+// value matches; a missing value matches no threshold.
+func atLeast(value, threshold *float64) bool {
+	if threshold == nil {
+		return true
+	}
+	return value != nil && *value >= *threshold
+}
+
+// Diff compares the per-VM snapshots of the last two successful collections.
+// Returns a ResourceNotFoundError until two collections have completed.
+func (c *InventoryService) Diff(ctx context.Context) (*models.InventoryDiff, error) {
+	previous, err := c.store.Inventory().PreviousSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := c.store.Inventory().CurrentSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := collectorV1.DiffInventories(*previous, *current)
+	return &diff, nil
+}
+
+// Revalidate recomputes the VM concerns with the current policies and rebuilds
+// the inventory from the stored VMs, without recollecting. Only the concerns
+// change, so the per-VM snapshots Diff compares are kept. The console has no
+// endpoint for concerns alone: the rebuilt inventory replaces the stored one
+// and is queued for a push like after a collection. It returns the number of
+// concerns stored, or a ResourceNotFoundError when nothing was collected yet.
+func (c *InventoryService) Revalidate(ctx context.Context) (int, error) {
+	rv, err := c.readForRevalidation(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return c.saveRevalidation(ctx, rv.validate(ctx))
+}
+
+// revalidation is what Revalidate reads from the store before validating, so
+// the validation itself can run without holding the store or the collector.
+type revalidation struct {
+	collectedAt time.Time
+	validator   duckdb_parser.Validator
+	vms         []duckdb_models.VM
+}
+
+// readForRevalidation reads the collected VMs, the validator and when the
+// inventory was collected, or returns a ResourceNotFoundError when nothing was
+// collected yet.
+func (c *InventoryService) readForRevalidation(ctx context.Context) (*revalidation, error) {
+	collectedAt, err := c.CollectedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := &revalidation{collectedAt: collectedAt, validator: c.store.Validator()}
+	if rv.validator == nil {
+		return rv, nil
+	}
+
+	rv.vms, err = c.store.Parser().VMs(ctx, duckdb_parser.Filters{}, duckdb_parser.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("getting VMs for validation: %w", err)
+	}
+	return rv, nil
+}
+
+// validate runs the validator on every VM, the way ingestion does. A VM whose
+// validation fails is left without concerns, and without a validator no VM
+// has any.
+func (rv *revalidation) validate(ctx context.Context) map[string][]duckdb_models.Concern {
+	if rv.validator == nil {
+		return nil
+	}
+
+	concerns := make(map[string][]duckdb_models.Concern, len(rv.vms))
+	for _, vm := range rv.vms {
+		vc, err := rv.validator.Validate(ctx, vm)
+		if err != nil {
+			zap.S().Named("inventory_service").Warnw("validation failed for VM", "vm_id", vm.ID, "error", err)
+			continue
+		}
+		concerns[vm.ID] = vc
+	}
+	return concerns
+}
+
+// saveRevalidation replaces the VM concerns with concerns, then rebuilds and
+// saves the inventory, refreshes the group matches and queues the push.
+func (c *InventoryService) saveRevalidation(ctx context.Context, concerns map[string][]duckdb_models.Concern) (int, error) {
+	var count int
+	err := c.store.WithTx(ctx, func(txCtx context.Context) error {
+		var err error
+		count, err = c.store.VM().ReplaceConcerns(txCtx, concerns)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// The parser reads outside the transaction, so the inventory is built
+	// once the new concerns are committed.
+	inventory, err := buildInventory(ctx, c.store, c.tiers, c.categories)
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.store.WithTx(ctx, func(txCtx context.Context) error {
+		if err := c.store.Inventory().Save(txCtx, inventory); err != nil {
+			return fmt.Errorf("saving inventory: %w", err)
+		}
+		// Groups can filter on concerns.
+		if err := c.store.Group().RefreshMatches(txCtx); err != nil {
+			return err
+		}
+		if c.eventSrv == nil {
+			return nil
+		}
+		return c.eventSrv.AddInventoryUpdateEvent(txCtx, inventory)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	zap.S().Named("inventory_service").Infow("inventory revalidated", "concerns", count)
+	return count, nil
+}
@@ -3,12 +3,17 @@ package services_test
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	duckdb_models "github.com/kubev2v/migration-planner/pkg/duckdb_parser/models"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 
+	"github.com/kubev2v/assisted-migration-agent/internal/config"
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/services"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
 	"github.com/kubev2v/assisted-migration-agent/test"
@@ -16,10 +21,11 @@ import (
 
 var _ = Describe("InventoryService", func() {
 	var (
-		ctx context.Context
-		db  *sql.DB
-		st  *store.Store
-		srv *services.InventoryService
+		ctx       context.Context
+		db        *sql.DB
+		st        *store.Store
+		validator *test.MockValidator
+		srv       *services.InventoryService
 	)
 
 	BeforeEach(func() {
@@ -29,7 +35,8 @@ var _ = Describe("InventoryService", func() {
 		db, err = store.NewDB(nil, ":memory:")
 		Expect(err).NotTo(HaveOccurred())
 
-		st = store.NewStore(db, test.NewMockValidator())
+		validator = test.NewMockValidator()
+		st = store.NewStore(db, validator)
 		Expect(st.Migrate(ctx)).To(Succeed())
 
 		srv = services.NewInventoryService(st)
@@ -93,4 +100,393 @@ var _ = Describe("InventoryService", func() {
 			Expect(string(inv.Data)).To(Equal(`{"vcenter_id":"vc-456"}`))
 		})
 	})
+
+	Context("Diff", func() {
+		rotate := func() {
+			Expect(st.WithTx(ctx, func(txCtx context.Context) error {
+				return st.Inventory().RotateSnapshot(txCtx)
+			})).To(Succeed())
+		}
+
+		// Given only one collection has completed
+		// When we request the diff
+		// Then it should return a not-found error
+		It("should return not found after a single collection", func() {
+			// Arrange
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			rotate()
+
+			// Act
+			_, err := srv.Diff(ctx)
+
+			// Assert
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+
+		// Given two collections where one VM gained memory
+		// When we request the diff
+		// Then only that VM should be reported as changed
+		It("should diff the last two collections", func() {
+			// Arrange
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			rotate()
+			_, err := db.ExecContext(ctx, `UPDATE vinfo SET "Memory" = 8192 WHERE "VM ID" = 'vm-002'`)
+			Expect(err).NotTo(HaveOccurred())
+			rotate()
+
+			// Act
+			diff, err := srv.Diff(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.Added).To(BeEmpty())
+			Expect(diff.Removed).To(BeEmpty())
+			Expect(diff.Changed).To(HaveLen(1))
+			Expect(diff.Changed[0].ID).To(Equal("vm-002"))
+			Expect(*diff.Changed[0].MemoryMB).To(Equal(models.FieldDelta{Previous: 4096, Current: 8192}))
+			Expect(diff.Changed[0].CPUs).To(BeNil())
+		})
+	})
+
+	Context("Metrics", func() {
+		// Given nothing has been collected
+		// When we request the metrics
+		// Then it should return a not-found error
+		It("should return not found when no inventory exists", func() {
+			// Act
+			_, err := srv.Metrics(ctx)
+
+			// Assert
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+
+		// Given a stored inventory with vCenter-wide totals
+		// When we request the metrics
+		// Then the scalars should be taken from the vCenter data, not the clusters
+		It("should extract the vCenter totals from the stored inventory", func() {
+			// Arrange
+			data := []byte(`{
+				"vcenter_id": "vc-1",
+				"clusters": {"c1": {"infra": {"totalHosts": 1}, "vms": {"total": 1}}},
+				"vcenter": {
+					"infra": {
+						"totalHosts": 8,
+						"totalDatacenters": 2,
+						"cpuOverCommitment": 2.5,
+						"memoryOverCommitment": 0.75,
+						"datastores": [], "networks": [], "hostPowerStates": {}
+					},
+					"vms": {
+						"total": 120,
+						"totalMigratable": 100,
+						"powerStates": {"poweredOn": 97, "poweredOff": 23},
+						"cpuCores": {"total": 480},
+						"ramGB": {"total": 1920},
+						"diskGB": {"total": 18944},
+						"diskCount": {"total": 200},
+						"migrationWarnings": [], "notMigratableReasons": []
+					}
+				}
+			}`)
+			Expect(st.Inventory().Save(ctx, data)).To(Succeed())
+
+			// Act
+			metrics, err := srv.Metrics(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metrics.UpdatedAt).NotTo(BeZero())
+			Expect(metrics.TotalVMs).To(Equal(120))
+			Expect(metrics.PoweredOnVMs).To(Equal(97))
+			Expect(metrics.TotalVCPUs).To(Equal(480))
+			Expect(metrics.TotalRAMGB).To(Equal(1920))
+			Expect(metrics.TotalDiskTB).To(Equal(18.5))
+			Expect(metrics.TotalHosts).To(Equal(8))
+			Expect(metrics.TotalDatacenters).To(Equal(2))
+			Expect(*metrics.CPUOverCommitment).To(Equal(2.5))
+			Expect(*metrics.MemoryOverCommitment).To(Equal(0.75))
+		})
+
+		// Given a stored inventory without host data
+		// When we request the metrics
+		// Then the overcommitment ratios should be left out
+		It("should leave out the ratios the inventory does not have", func() {
+			// Arrange
+			Expect(st.Inventory().Save(ctx, []byte(`{"vcenter_id":"vc-1","clusters":{},"vcenter":{"vms":{"total":3}}}`))).To(Succeed())
+
+			// Act
+			metrics, err := srv.Metrics(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metrics.TotalVMs).To(Equal(3))
+			Expect(metrics.PoweredOnVMs).To(BeZero())
+			Expect(metrics.CPUOverCommitment).To(BeNil())
+			Expect(metrics.MemoryOverCommitment).To(BeNil())
+		})
+	})
+
+	Context("Clusters", func() {
+		// saveClusters stores an inventory with three clusters: one CPU
+		// oversubscribed, one memory oversubscribed and one without host data.
+		saveClusters := func() {
+			data := []byte(`{
+				"vcenter_id": "vc-1",
+				"clusters": {
+					"domain-c1": {"infra": {"totalHosts": 4, "cpuOverCommitment": 4.5, "memoryOverCommitment": 0.8}, "vms": {"total": 40}},
+					"domain-c2": {"infra": {"totalHosts": 2, "cpuOverCommitment": 1.5, "memoryOverCommitment": 1.3}, "vms": {"total": 12}},
+					"domain-c3": {"infra": {"totalHosts": 0}, "vms": {"total": 3}}
+				},
+				"vcenter": {"vms": {"total": 55}}
+			}`)
+			Expect(st.Inventory().Save(ctx, data)).To(Succeed())
+		}
+
+		ids := func(clusters []models.ClusterMetrics) []string {
+			out := make([]string, 0, len(clusters))
+			for _, c := range clusters {
+				out = append(out, c.ID)
+			}
+			return out
+		}
+
+		// Given nothing has been collected
+		// When we list the clusters
+		// Then it should return a not-found error
+		It("should return not found when no inventory exists", func() {
+			// Act
+			_, err := srv.Clusters(ctx, services.ClusterListParams{})
+
+			// Assert
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+
+		// Given a stored inventory with three clusters
+		// When we list the clusters without filters
+		// Then every cluster should be returned with its totals
+		It("should return every cluster without filters", func() {
+			// Arrange
+			saveClusters()
+
+			// Act
+			clusters, err := srv.Clusters(ctx, services.ClusterListParams{})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(clusters)).To(Equal([]string{"domain-c1", "domain-c2", "domain-c3"}))
+			Expect(clusters[0].TotalVMs).To(Equal(40))
+			Expect(clusters[0].TotalHosts).To(Equal(4))
+			Expect(*clusters[0].CPUOverCommitment).To(Equal(4.5))
+			Expect(clusters[2].CPUOverCommitment).To(BeNil())
+		})
+
+		ratio := func(v float64) *float64 { return &v }
+
+		// Given clusters with synthetic overcommitment ratios
+		// When we filter on minimum CPU and/or memory overcommitment
+		// Then only the clusters reaching every minimum should be returned,
+		// and clusters without a ratio never match
+		DescribeTable("should filter by minimum overcommitment",
+			func(minCPU, minMemory *float64, expected []string) {
+				// Arrange
+				saveClusters()
+
+				// Act
+				clusters, err := srv.Clusters(ctx, services.ClusterListParams{
+					MinCPUOverCommitment:    minCPU,
+					MinMemoryOverCommitment: minMemory,
+				})
+
+				// Assert
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ids(clusters)).To(Equal(expected))
+			},
+			Entry("CPU", ratio(4.0), nil, []string{"domain-c1"}),
+			Entry("CPU equal to the ratio", ratio(1.5), nil, []string{"domain-c1", "domain-c2"}),
+			Entry("CPU at zero drops clusters without host data", ratio(0.0), nil, []string{"domain-c1", "domain-c2"}),
+			Entry("memory", nil, ratio(1.0), []string{"domain-c2"}),
+			Entry("CPU and memory", ratio(4.0), ratio(1.0), []string{}),
+			Entry("CPU above every cluster", ratio(10.0), nil, []string{}),
+		)
+	})
+
+	Context("Revalidate", func() {
+		// Given nothing has been collected
+		// When we revalidate
+		// Then it should return a not-found error
+		It("should return not found when no inventory exists", func() {
+			// Act
+			_, err := srv.Revalidate(ctx)
+
+			// Assert
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+
+		// Given a collected inventory and policies that now raise a concern
+		// When we revalidate
+		// Then every VM should get the concern, the inventory should be rebuilt
+		// and queued once for the console
+		It("should recompute the concerns and queue the rebuilt inventory", func() {
+			// Arrange
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			Expect(st.Inventory().Save(ctx, []byte(`{}`))).To(Succeed())
+			var vmCount int
+			Expect(db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vinfo`).Scan(&vmCount)).To(Succeed())
+
+			validator.Concerns = []duckdb_models.Concern{
+				{Id: "policy.new", Label: "New concern", Category: "Warning", Assessment: "Check it"},
+			}
+			events := services.NewEventService(st)
+			srv.WithEventService(events)
+
+			// Act
+			count, err := srv.Revalidate(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(vmCount))
+
+			inv, err := srv.GetInventory(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(inv.Data)).To(ContainSubstring("New concern"))
+
+			queued, err := events.Events(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queued).To(HaveLen(1))
+			Expect(queued[0].Kind).To(Equal(models.InventoryUpdateEvent))
+			Expect(queued[0].Data).To(Equal(inv.Data))
+		})
+
+		// Given collected VMs and custom disk size, CPU and memory tiers
+		// When we revalidate
+		// Then the rebuilt inventory should bucket the VMs with the custom
+		// tiers, for the vCenter and each cluster
+		It("should bucket the rebuilt inventory with the configured tiers", func() {
+			// Arrange
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			Expect(st.Inventory().Save(ctx, []byte(`{}`))).To(Succeed())
+
+			// Fixture disks total 50 to 1000 MiB per VM; 0.0001 TB is about
+			// 105 MiB and 0.0009 TB about 944 MiB. VMs have 1 to 8 vCPUs and
+			// 2 to 16 GB of memory.
+			tiers, err := services.ParseTiers(config.Agent{
+				DiskSizeTiers: []string{"small=0.0001", "medium=0.0009", "large"},
+				CPUTiers:      []int{2, 4},
+				MemoryTiers:   []int{4, 8},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			srv.WithTiers(tiers)
+
+			// Act
+			_, err = srv.Revalidate(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+
+			stored, err := srv.GetInventory(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			var inv v1alpha1.Inventory
+			Expect(json.Unmarshal(stored.Data, &inv)).To(Succeed())
+
+			Expect(*inv.Vcenter.Vms.DiskSizeTier).To(Equal(map[string]v1alpha1.DiskSizeTierSummary{
+				"small":  {VmCount: 4, TotalSizeTB: 0},
+				"medium": {VmCount: 4, TotalSizeTB: 0},
+				"large":  {VmCount: 2, TotalSizeTB: 0},
+			}))
+			Expect(*inv.Vcenter.Vms.DistributionByCpuTier).To(Equal(map[string]int{"0-2": 6, "3-4": 2, "4+": 2}))
+			Expect(*inv.Vcenter.Vms.DistributionByMemoryTier).To(Equal(map[string]int{"0-4": 6, "5-8": 2, "8+": 2}))
+
+			Expect(inv.Clusters).To(HaveLen(3))
+			for id, cluster := range inv.Clusters {
+				Expect(cluster.Vms.DiskSizeTier).NotTo(BeNil(), id)
+				count := 0
+				for label, summary := range *cluster.Vms.DiskSizeTier {
+					Expect(label).To(BeElementOf("small", "medium", "large"), id)
+					count += summary.VmCount
+				}
+				Expect(count).To(Equal(cluster.Vms.Total), id)
+
+				count = 0
+				for key, n := range *cluster.Vms.DistributionByCpuTier {
+					Expect(key).To(BeElementOf("0-2", "3-4", "4+"), id)
+					count += n
+				}
+				Expect(count).To(Equal(cluster.Vms.Total), id)
+			}
+		})
+
+		// Given policies raising an Info and a Warning concern for every VM
+		// and Info mapped to critical
+		// When we revalidate
+		// Then the rebuilt inventory should count the Info concern as a
+		// not-migratable reason, for the vCenter and each cluster
+		It("should count the rebuilt inventory's concerns with the configured categories", func() {
+			// Arrange
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			Expect(st.Inventory().Save(ctx, []byte(`{}`))).To(Succeed())
+			var vmCount int
+			Expect(db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vinfo`).Scan(&vmCount)).To(Succeed())
+
+			validator.Concerns = []duckdb_models.Concern{
+				{Id: "policy.info", Label: "Info concern", Category: "Info", Assessment: "Note it"},
+				{Id: "policy.warn", Label: "Warning concern", Category: "Warning", Assessment: "Check it"},
+			}
+			categories, err := services.ParseConcernCategories(config.Agent{
+				ConcernCategories: map[string]string{"Info": "critical"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			srv.WithConcernCategories(categories)
+
+			// Act
+			_, err = srv.Revalidate(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+
+			stored, err := srv.GetInventory(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			var inv v1alpha1.Inventory
+			Expect(json.Unmarshal(stored.Data, &inv)).To(Succeed())
+
+			infoID, warnID := "policy.info", "policy.warn"
+			Expect(inv.Vcenter.Vms.NotMigratableReasons).To(ContainElement(v1alpha1.MigrationIssue{
+				Id: &infoID, Label: "Info concern", Assessment: "Note it", Count: vmCount,
+			}))
+			Expect(inv.Vcenter.Vms.MigrationWarnings).To(ContainElement(v1alpha1.MigrationIssue{
+				Id: &warnID, Label: "Warning concern", Assessment: "Check it", Count: vmCount,
+			}))
+			Expect(inv.Vcenter.Vms.TotalMigratable).To(BeZero())
+			Expect(*inv.Vcenter.Vms.TotalMigratableWithWarnings).To(Equal(vmCount))
+
+			Expect(inv.Clusters).NotTo(BeEmpty())
+			for id, cluster := range inv.Clusters {
+				Expect(cluster.Vms.TotalMigratable).To(BeZero(), id)
+				Expect(cluster.Vms.NotMigratableReasons).To(ContainElement(v1alpha1.MigrationIssue{
+					Id: &infoID, Label: "Info concern", Assessment: "Note it", Count: cluster.Vms.Total,
+				}), id)
+			}
+		})
+
+		// Given an unknown bucket in the concern category mapping
+		// When we parse it
+		// Then the error should name the flag
+		It("should name the flag of an invalid concern category mapping", func() {
+			// Act
+			_, err := services.ParseConcernCategories(config.Agent{ConcernCategories: map[string]string{"Info": "notice"}})
+
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("concern-categories")))
+		})
+
+		// Given invalid tier bounds in the configuration
+		// When we parse them
+		// Then the error should name the flag
+		It("should name the flag of invalid tiers", func() {
+			// Act
+			_, err := services.ParseTiers(config.Agent{MemoryTiers: []int{16, 8}})
+
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("memory-tiers")))
+		})
+	})
 })
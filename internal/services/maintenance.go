@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+// MaintenanceService tracks the maintenance (read-only) flag. While enabled,
+// handlers reject operations that mutate state such as collection, inspection
+// and inventory pushes. The flag is persisted so it survives restarts.
+type MaintenanceService struct {
+	store   *store.Store
+	enabled atomic.Bool
+}
+
+// NewMaintenanceService loads the persisted maintenance flag. A missing
+// configuration row means maintenance is off.
+func NewMaintenanceService(st *store.Store) (*MaintenanceService, error) {
+	m := &MaintenanceService{store: st}
+
+	cfg, err := st.Configuration().Get(context.Background())
+	switch {
+	case err == nil:
+		m.enabled.Store(cfg.Maintenance)
+	case errors.IsResourceNotFoundError(err):
+	default:
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Enabled reports whether maintenance mode is on.
+func (m *MaintenanceService) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// SetEnabled persists the maintenance flag and applies it.
+func (m *MaintenanceService) SetEnabled(ctx context.Context, enabled bool) error {
+	if err := m.store.Configuration().SaveMaintenance(ctx, enabled); err != nil {
+		return err
+	}
+	m.enabled.Store(enabled)
+
+	zap.S().Named("maintenance_service").Infow("maintenance mode changed", "enabled", enabled)
+	return nil
+}
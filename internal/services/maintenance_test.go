@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+var _ = Describe("MaintenanceService", func() {
+	var (
+		ctx context.Context
+		db  *sql.DB
+		st  *store.Store
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = migrations.Run(ctx, db)
+		Expect(err).NotTo(HaveOccurred())
+
+		st = store.NewStore(db, test.NewMockValidator())
+	})
+
+	AfterEach(func() {
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	// Given an empty store
+	// When we create the service
+	// Then maintenance mode should be off
+	It("should start disabled when nothing is persisted", func() {
+		// Act
+		srv, err := services.NewMaintenanceService(st)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Enabled()).To(BeFalse())
+	})
+
+	// Given maintenance mode was enabled
+	// When the service is recreated on the same store (agent restart)
+	// Then maintenance mode should still be on
+	It("should persist the flag across restarts", func() {
+		// Arrange
+		srv, err := services.NewMaintenanceService(st)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.SetEnabled(ctx, true)).To(Succeed())
+
+		// Act
+		restarted, err := services.NewMaintenanceService(st)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted.Enabled()).To(BeTrue())
+	})
+
+	// Given maintenance mode is enabled and the agent mode is connected
+	// When maintenance mode is disabled
+	// Then the agent mode should be left untouched
+	It("should not change the agent mode", func() {
+		// Arrange
+		Expect(st.Configuration().Save(ctx, &models.Configuration{AgentMode: models.AgentModeConnected})).To(Succeed())
+		srv, err := services.NewMaintenanceService(st)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.SetEnabled(ctx, true)).To(Succeed())
+
+		// Act
+		err = srv.SetEnabled(ctx, false)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Enabled()).To(BeFalse())
+		cfg, err := st.Configuration().Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.AgentMode).To(Equal(models.AgentModeConnected))
+	})
+})
@@ -3,10 +3,16 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/config"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
 	"github.com/kubev2v/assisted-migration-agent/pkg/console"
+	"github.com/kubev2v/assisted-migration-agent/pkg/vmware"
 )
 
 const (
@@ -29,6 +35,11 @@ type ServiceManager struct {
 	vm          *VMService
 	group       *GroupService
 	rightsizing *RightsizingService
+	maintenance *MaintenanceService
+	storage     *StorageService
+	scheduler   *CollectionScheduler
+	broker      *StatusBroker
+	sessions    *vmware.SessionCache
 }
 
 type ServiceManagerOption func(*ServiceManager)
@@ -70,17 +81,64 @@ func (m *ServiceManager) Initialize() error {
 		return errors.New("console client is required")
 	}
 
-	m.inventory = NewInventoryService(m.store)
-	m.event = NewEventService(m.store)
+	maintenance, err := NewMaintenanceService(m.store)
+	if err != nil {
+		return err
+	}
+	m.maintenance = maintenance
 
-	factory := newCollectorWorkFactory(m.store, m.event, m.cfg.Agent.DataFolder, m.cfg.Agent.OpaPoliciesFolder)
-	m.collector = NewCollectorService(m.inventory, factory.Build)
+	tiers, err := ParseTiers(m.cfg.Agent)
+	if err != nil {
+		return err
+	}
+	categories, err := ParseConcernCategories(m.cfg.Agent)
+	if err != nil {
+		return err
+	}
+
+	m.event = NewEventService(m.store)
+	m.inventory = NewInventoryService(m.store).
+		WithEventService(m.event).
+		WithTiers(tiers).
+		WithConcernCategories(categories)
+	m.broker = NewStatusBroker()
+
+	// Collection and inspection share vCenter sessions so back-to-back runs
+	// don't log in again.
+	m.sessions = vmware.NewSessionCache(m.cfg.Agent.VCenterSessionTTL)
+
+	factory := newCollectorWorkFactory(m.store, m.event, m.cfg.Agent.DataFolder, m.cfg.Agent.OpaPoliciesFolder).
+		withSessionCache(m.sessions).
+		withInventoryRetention(m.cfg.Agent.InventoryRetention).
+		withCollectionConcurrency(m.cfg.Agent.CollectionConcurrency).
+		withTiers(tiers).
+		withConcernCategories(categories)
+	m.collector = NewCollectorService(m.inventory, factory.Build).
+		WithStatusBroker(m.broker).
+		WithStateStore(m.store.CollectorState())
+	if m.cfg.Agent.CollectionHistoryEnabled && m.cfg.Agent.DataFolder != "" {
+		m.collector.WithHistory(NewCollectionHistory(m.cfg.Agent.DataFolder, m.store))
+	}
+	if m.cfg.Agent.CredentialsSecretPath != "" {
+		secret, err := os.ReadFile(m.cfg.Agent.CredentialsSecretPath)
+		if err != nil {
+			return fmt.Errorf("reading credentials secret: %w", err)
+		}
+		if len(strings.TrimSpace(string(secret))) == 0 {
+			return errors.New("credentials secret file is empty")
+		}
+		m.collector.WithCredentialsStore(m.store.Credentials().WithSecret(strings.TrimSpace(string(secret))))
+	}
 
-	m.inspector = NewInspectorService(m.store, maxVMsPerCycle, m.cfg.Agent.DataFolder)
+	m.inspector = NewInspectorService(m.store, maxVMsPerCycle, m.cfg.Agent.DataFolder).
+		WithStatusBroker(m.broker).
+		WithSessionCache(m.sessions).
+		WithWorkers(m.cfg.Agent.InspectionWorkers)
 
 	m.forecaster = NewForecasterService(m.store, maxPairsPerRun)
 
 	m.vddk = NewVddkService(m.cfg.Agent.DataFolder, m.store)
+	m.storage = NewStorageService(m.cfg.Agent.DataFolder, m.store)
 
 	consoleSrv, err := NewConsoleService(
 		m.cfg.Agent,
@@ -94,12 +152,33 @@ func (m *ServiceManager) Initialize() error {
 		_ = m.inspector.Stop()
 		return err
 	}
-	m.console = consoleSrv
+	m.console = consoleSrv.WithStatusBroker(m.broker)
 
-	m.vm = NewVMService(m.store)
+	m.vm = NewVMService(m.store).
+		WithSuppressedConcerns(m.cfg.Agent.SuppressedConcerns).
+		WithConcernAssessments(m.cfg.Agent.ConcernAssessments)
 	m.group = NewGroupService(m.store)
 	m.rightsizing = NewRightsizingService(m.store)
 
+	// Before the scheduler, so a resumed collection makes its first tick skip.
+	if _, err := m.collector.Resume(context.Background()); err != nil {
+		zap.S().Named("service_manager").Warnw("failed to resume interrupted collection", "error", err)
+	}
+
+	if m.cfg.Agent.CollectionSchedule != "" {
+		schedule, err := ParseCollectionSchedule(m.cfg.Agent.CollectionSchedule)
+		if err != nil {
+			m.console.Stop()
+			m.collector.Stop()
+			_ = m.inspector.Stop()
+			return err
+		}
+		m.scheduler = NewCollectionScheduler(m.collector, schedule).
+			WithMaintenance(m.maintenance).
+			WithInspector(m.inspector)
+		m.scheduler.Start()
+	}
+
 	return nil
 }
 
@@ -119,6 +198,10 @@ func (m *ServiceManager) VddkService() *VddkService {
 	return m.vddk
 }
 
+func (m *ServiceManager) StorageService() *StorageService {
+	return m.storage
+}
+
 func (m *ServiceManager) EventService() *EventService {
 	return m.event
 }
@@ -143,10 +226,22 @@ func (m *ServiceManager) ForecasterService() *ForecasterService {
 	return m.forecaster
 }
 
+func (m *ServiceManager) MaintenanceService() *MaintenanceService {
+	return m.maintenance
+}
+
+func (m *ServiceManager) StatusBroker() *StatusBroker {
+	return m.broker
+}
+
 func (m *ServiceManager) Stop(ctx context.Context) {
+	if m.scheduler != nil {
+		m.scheduler.Stop()
+	}
 	m.console.Stop()
-	m.collector.Stop()
+	m.collector.shutdown()
 	_ = m.inspector.Stop()
 	m.rightsizing.Stop()
 	_ = m.forecaster.Stop()
+	m.sessions.Close()
 }
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	duckdb_models "github.com/kubev2v/migration-planner/pkg/duckdb_parser/models"
+	"github.com/kubev2v/migration-planner/pkg/opa"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"go.uber.org/zap"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+// concernsPackage is the package the validator reads concerns from.
+var concernsPackage = ast.MustParseRef("data.io.konveyor.forklift.vmware")
+
+// PolicyService holds the OPA validator used to compute VM concerns.
+// It implements duckdb_parser.Validator so it can be handed to the store,
+// and lets the validator be rebuilt from the policies folder at runtime.
+type PolicyService struct {
+	mu        sync.RWMutex
+	dir       string
+	validator *opa.Validator
+	catalog   []models.ConcernDefinition
+}
+
+// NewPolicyService loads the policies from dir. It fails if the folder cannot
+// be read, holds no policies, or the policies do not compile.
+func NewPolicyService(dir string) (*PolicyService, error) {
+	s := &PolicyService{dir: dir}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate evaluates the currently loaded policies against the VM.
+func (s *PolicyService) Validate(ctx context.Context, vm duckdb_models.VM) ([]duckdb_models.Concern, error) {
+	s.mu.RLock()
+	v := s.validator
+	s.mu.RUnlock()
+
+	return v.Validate(ctx, vm)
+}
+
+// Catalog returns the concerns the currently loaded policies can raise,
+// sorted by ID.
+func (s *PolicyService) Catalog() []models.ConcernDefinition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.catalog)
+}
+
+// Reload re-reads and compiles the policies folder and returns the number of
+// policies loaded. On error the previously loaded policies stay in use.
+func (s *PolicyService) Reload() (int, error) {
+	policies, err := opa.NewPolicyReader().ReadPolicies(s.dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read policies: %w", err)
+	}
+
+	v, err := opa.NewValidator(policies)
+	if err != nil {
+		return 0, err
+	}
+
+	catalog, err := concernCatalog(policies)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	s.validator = v
+	s.catalog = catalog
+	s.mu.Unlock()
+
+	zap.S().Named("policy_service").Infow("policies loaded", "dir", s.dir, "count", len(policies), "concerns", len(catalog))
+
+	return len(policies), nil
+}
+
+// concernCatalog lists the concern objects built by the concerns rules of the
+// policies. A field the policy formats with sprintf is reported as its format
+// string; any other computed field is left empty. When several rules raise
+// the same ID, the first one in file name order wins.
+func concernCatalog(policies map[string]string) ([]models.ConcernDefinition, error) {
+	byID := make(map[string]models.ConcernDefinition)
+	for _, filename := range slices.Sorted(maps.Keys(policies)) {
+		module, err := ast.ParseModuleWithOpts(filename, policies[filename], ast.ParserOptions{
+			RegoVersion: ast.RegoV1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy %s: %w", filename, err)
+		}
+		if !module.Package.Path.Equal(concernsPackage) {
+			continue
+		}
+
+		for _, rule := range module.Rules {
+			if ref := rule.Head.Ref(); len(ref) != 1 || !ref[0].Equal(ast.VarTerm("concerns")) {
+				continue
+			}
+			ast.WalkTerms(rule, func(t *ast.Term) bool {
+				obj, ok := t.Value.(ast.Object)
+				if !ok {
+					return false
+				}
+				id := policyString(obj.Get(ast.StringTerm("id")))
+				if id == "" {
+					return false
+				}
+				if _, found := byID[id]; !found {
+					byID[id] = models.ConcernDefinition{
+						ID:         id,
+						Category:   policyString(obj.Get(ast.StringTerm("category"))),
+						Label:      policyString(obj.Get(ast.StringTerm("label"))),
+						Assessment: policyString(obj.Get(ast.StringTerm("assessment"))),
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	return slices.SortedFunc(maps.Values(byID), func(a, b models.ConcernDefinition) int {
+		return cmp.Compare(a.ID, b.ID)
+	}), nil
+}
+
+// policyString returns the value of a string term, or the format string of a
+// sprintf call.
+func policyString(t *ast.Term) string {
+	if t == nil {
+		return ""
+	}
+	switch v := t.Value.(type) {
+	case ast.String:
+		return string(v)
+	case ast.Call:
+		if len(v) > 1 && v[0].Equal(ast.RefTerm(ast.VarTerm("sprintf"))) {
+			return policyString(v[1])
+		}
+	}
+	return ""
+}
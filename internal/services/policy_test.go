@@ -0,0 +1,196 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	duckdb_models "github.com/kubev2v/migration-planner/pkg/duckdb_parser/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+)
+
+const namePolicy = `package io.konveyor.forklift.vmware
+
+concerns contains flag if {
+	input.name == "flagged"
+	flag := {"id": "test.name", "category": "Warning", "label": "Flagged by name", "assessment": "name"}
+}
+`
+
+const memoryPolicy = `package io.konveyor.forklift.vmware
+
+concerns contains flag if {
+	input.memoryMB > 1024
+	flag := {"id": "test.memory", "category": "Critical", "label": "Too much memory", "assessment": "memory"}
+}
+`
+
+const diskPolicy = `package io.konveyor.forklift.vmware
+
+import rego.v1
+
+invalid_disks contains idx if {
+	some idx
+	input.disks[idx].capacity <= 0
+}
+
+concerns contains flag if {
+	invalid_disks[idx]
+	disk := input.disks[idx]
+	flag := {
+		"id": "test.disk",
+		"category": "Critical",
+		"label": sprintf("Disk '%v' has an invalid capacity", [disk.file]),
+		"assessment": concat(" ", ["capacity", "of", disk.file]),
+	}
+}
+
+concerns contains flag if {
+	input.guestId == "other"
+	flag := {"id": "test.name", "category": "Information", "label": "Duplicate", "assessment": "ignored"}
+}
+
+helper := {"id": "test.helper", "category": "Warning", "label": "Not a concern", "assessment": "helper"}
+`
+
+const otherPackagePolicy = `package io.konveyor.forklift.ova
+
+concerns contains flag if {
+	flag := {"id": "test.ova", "category": "Warning", "label": "Not a vmware concern", "assessment": "ova"}
+}
+`
+
+var _ = Describe("PolicyService", func() {
+	var (
+		ctx context.Context
+		dir string
+	)
+
+	writePolicy := func(name, content string) {
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)).To(Succeed())
+	}
+
+	concernIDs := func(concerns []duckdb_models.Concern) []string {
+		ids := make([]string, 0, len(concerns))
+		for _, c := range concerns {
+			ids = append(ids, c.Id)
+		}
+		return ids
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		dir = GinkgoT().TempDir()
+		writePolicy("name.rego", namePolicy)
+	})
+
+	// Given a folder with one policy
+	// When we create the service
+	// Then it should validate VMs against that policy
+	It("should load policies on creation", func() {
+		// Act
+		srv, err := services.NewPolicyService(dir)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		concerns, err := srv.Validate(ctx, duckdb_models.VM{Name: "flagged", MemoryMB: 4096})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(concernIDs(concerns)).To(ConsistOf("test.name"))
+	})
+
+	// Given a folder without policies
+	// When we create the service
+	// Then it should fail
+	It("should fail when the folder has no policies", func() {
+		// Act
+		_, err := services.NewPolicyService(GinkgoT().TempDir())
+
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+
+	// Given a loaded service and a policy added to the folder
+	// When we reload
+	// Then the new policy should be counted and applied
+	It("should pick up a changed policy set on reload", func() {
+		// Arrange
+		srv, err := services.NewPolicyService(dir)
+		Expect(err).NotTo(HaveOccurred())
+		writePolicy("memory.rego", memoryPolicy)
+
+		// Act
+		count, err := srv.Reload()
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(2))
+		concerns, err := srv.Validate(ctx, duckdb_models.VM{Name: "flagged", MemoryMB: 4096})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(concernIDs(concerns)).To(ConsistOf("test.name", "test.memory"))
+	})
+
+	// Given a loaded service and a broken policy added to the folder
+	// When we reload
+	// Then it should fail and keep using the previous policies
+	It("should keep the previous policies when reload fails", func() {
+		// Arrange
+		srv, err := services.NewPolicyService(dir)
+		Expect(err).NotTo(HaveOccurred())
+		writePolicy("broken.rego", "package io.konveyor.forklift.vmware\n\nconcerns contains {")
+
+		// Act
+		_, err = srv.Reload()
+
+		// Assert
+		Expect(err).To(HaveOccurred())
+		concerns, err := srv.Validate(ctx, duckdb_models.VM{Name: "flagged"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(concernIDs(concerns)).To(ConsistOf("test.name"))
+	})
+
+	Describe("Catalog", func() {
+		// Given policies raising constant and formatted concerns, an ID raised
+		// again by a later file, a helper rule and a concern outside the vmware package
+		// When we read the catalog
+		// Then it should list each vmware concern once, as first defined, sorted by ID
+		It("should list the concerns the policies can raise", func() {
+			// Arrange
+			writePolicy("storage.rego", diskPolicy)
+			writePolicy("ova.rego", otherPackagePolicy)
+
+			// Act
+			srv, err := services.NewPolicyService(dir)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(srv.Catalog()).To(Equal([]models.ConcernDefinition{
+				{ID: "test.disk", Category: "Critical", Label: "Disk '%v' has an invalid capacity"},
+				{ID: "test.name", Category: "Warning", Label: "Flagged by name", Assessment: "name"},
+			}))
+		})
+
+		// Given a loaded service and a policy added to the folder
+		// When we reload
+		// Then the catalog should include the new concern
+		It("should follow the policies on reload", func() {
+			// Arrange
+			srv, err := services.NewPolicyService(dir)
+			Expect(err).NotTo(HaveOccurred())
+			writePolicy("memory.rego", memoryPolicy)
+
+			// Act
+			_, err = srv.Reload()
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(srv.Catalog()).To(HaveLen(2))
+			Expect(srv.Catalog()[0]).To(Equal(models.ConcernDefinition{
+				ID: "test.memory", Category: "Critical", Label: "Too much memory", Assessment: "memory",
+			}))
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+// statusBrokerBuffer is how many events a subscriber may fall behind before
+// further events are dropped for it.
+const statusBrokerBuffer = 16
+
+// StatusBroker fans out service state changes to subscribers such as the
+// GET /events stream. Publish never blocks: a subscriber that falls behind
+// misses events rather than stalling the publishing service.
+type StatusBroker struct {
+	mu   sync.Mutex
+	subs map[chan models.StatusEvent]struct{}
+}
+
+func NewStatusBroker() *StatusBroker {
+	return &StatusBroker{subs: make(map[chan models.StatusEvent]struct{})}
+}
+
+// Subscribe returns a channel receiving every event published from now on,
+// and a function that unsubscribes and closes the channel. The function is
+// safe to call more than once.
+func (b *StatusBroker) Subscribe() (<-chan models.StatusEvent, func()) {
+	ch := make(chan models.StatusEvent, statusBrokerBuffer)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, ch)
+			close(ch)
+		})
+	}
+}
+
+// Publish sends event to every subscriber with room in its buffer.
+func (b *StatusBroker) Publish(event models.StatusEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+)
+
+var _ = Describe("StatusBroker", func() {
+	var broker *services.StatusBroker
+
+	BeforeEach(func() {
+		broker = services.NewStatusBroker()
+	})
+
+	// Given two subscribers
+	// When an event is published
+	// Then both should receive it
+	It("should deliver events to every subscriber", func() {
+		// Arrange
+		first, unsubscribeFirst := broker.Subscribe()
+		defer unsubscribeFirst()
+		second, unsubscribeSecond := broker.Subscribe()
+		defer unsubscribeSecond()
+		event := models.StatusEvent{
+			Source: models.StatusEventCollector,
+			Status: models.CollectorStatus{State: models.CollectorStateCollecting},
+		}
+
+		// Act
+		broker.Publish(event)
+
+		// Assert
+		Expect(first).To(Receive(Equal(event)))
+		Expect(second).To(Receive(Equal(event)))
+	})
+
+	// Given a subscriber that unsubscribed
+	// When an event is published
+	// Then its channel should be closed and receive nothing
+	It("should close the channel on unsubscribe", func() {
+		// Arrange
+		events, unsubscribe := broker.Subscribe()
+
+		// Act
+		unsubscribe()
+		unsubscribe()
+		broker.Publish(models.StatusEvent{Source: models.StatusEventInspector})
+
+		// Assert
+		Expect(events).To(BeClosed())
+	})
+
+	// Given a subscriber that does not read
+	// When more events are published than it buffers
+	// Then Publish should not block and the extra events are dropped
+	It("should drop events for a slow subscriber", func() {
+		// Arrange
+		events, unsubscribe := broker.Subscribe()
+		defer unsubscribe()
+
+		// Act
+		for range 100 {
+			broker.Publish(models.StatusEvent{Source: models.StatusEventAgent})
+		}
+
+		// Assert
+		Expect(len(events)).To(Equal(cap(events)))
+	})
+})
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+// StorageService reports how much of the data folder is used by the DuckDB
+// file and the uploaded VDDK, and how much room is left on its filesystem.
+// It also compacts the database and hands out its file on demand.
+type StorageService struct {
+	dataFolder string
+	store      *store.Store
+}
+
+func NewStorageService(dataFolder string, st *store.Store) *StorageService {
+	return &StorageService{dataFolder: dataFolder, store: st}
+}
+
+// Compact reclaims space freed in the database file. Returns a
+// ResourceNotFoundError when the agent runs without a data folder, since an
+// in-memory database has nothing to compact.
+func (s *StorageService) Compact(ctx context.Context) error {
+	if s.dataFolder == "" {
+		return srvErrors.NewDataFolderNotSetError()
+	}
+	return s.store.Compact(ctx)
+}
+
+// OpenDatabase checkpoints the database, so the file holds every committed
+// write, and opens the file for reading. The caller closes it. Returns a
+// ResourceNotFoundError when the agent runs without a data folder, since an
+// in-memory database has no file.
+func (s *StorageService) OpenDatabase(ctx context.Context) (*os.File, error) {
+	if s.dataFolder == "" {
+		return nil, srvErrors.NewDataFolderNotSetError()
+	}
+	if err := s.store.Checkpoint(); err != nil {
+		return nil, fmt.Errorf("checkpoint: %w", err)
+	}
+	return os.Open(filepath.Join(s.dataFolder, store.DatabaseFile))
+}
+
+// Usage measures the data folder. Returns a ResourceNotFoundError when the
+// agent runs without a data folder (in-memory database).
+func (s *StorageService) Usage(ctx context.Context) (*models.StorageUsage, error) {
+	if s.dataFolder == "" {
+		return nil, srvErrors.NewDataFolderNotSetError()
+	}
+
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(s.dataFolder, &st); err != nil {
+		return nil, fmt.Errorf("statfs %s: %w", s.dataFolder, err)
+	}
+
+	usage := &models.StorageUsage{
+		Path:       s.dataFolder,
+		TotalBytes: st.Blocks * uint64(st.Bsize),
+		FreeBytes:  st.Bavail * uint64(st.Bsize),
+	}
+
+	dbPath := filepath.Join(s.dataFolder, store.DatabaseFile)
+	for _, f := range []string{dbPath, dbPath + ".wal"} {
+		size, err := fileSize(f)
+		if err != nil {
+			return nil, err
+		}
+		usage.DatabaseBytes += size
+	}
+
+	vddkSize, err := dirSize(ctx, filepath.Join(s.dataFolder, vddkFolder))
+	if err != nil {
+		return nil, err
+	}
+	usage.VddkBytes = vddkSize
+
+	return usage, nil
+}
+
+// fileSize returns the size of path, or zero if it does not exist.
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// dirSize sums the sizes of the regular files under root, or zero if root does
+// not exist.
+func dirSize(ctx context.Context, root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	return total, err
+}
@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/services"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+var _ = Describe("StorageService", func() {
+	var (
+		ctx        context.Context
+		dataFolder string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		dataFolder = GinkgoT().TempDir()
+	})
+
+	writeFile := func(path string, size int) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, make([]byte, size), 0o644)).To(Succeed())
+	}
+
+	// Given a data folder with a database, its WAL and a nested VDDK tree
+	// When we measure storage usage
+	// Then the database and VDDK sizes should match the files on disk
+	It("should report database and vddk sizes", func() {
+		// Arrange
+		writeFile(filepath.Join(dataFolder, store.DatabaseFile), 1024)
+		writeFile(filepath.Join(dataFolder, store.DatabaseFile+".wal"), 512)
+		writeFile(filepath.Join(dataFolder, "vddk", "lib", "libvixDiskLib.so"), 2048)
+		writeFile(filepath.Join(dataFolder, "vddk", "bin", "vmware-vdiskmanager"), 100)
+		writeFile(filepath.Join(dataFolder, "other.txt"), 4096)
+
+		srv := services.NewStorageService(dataFolder, nil)
+
+		// Act
+		usage, err := srv.Usage(ctx)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(usage.Path).To(Equal(dataFolder))
+		Expect(usage.DatabaseBytes).To(Equal(int64(1536)))
+		Expect(usage.VddkBytes).To(Equal(int64(2148)))
+		Expect(usage.TotalBytes).To(BeNumerically(">", 0))
+		Expect(usage.FreeBytes).To(BeNumerically("<=", usage.TotalBytes))
+	})
+
+	// Given an empty data folder
+	// When we measure storage usage
+	// Then the database and VDDK sizes should be zero
+	It("should report zero for missing files", func() {
+		// Arrange
+		srv := services.NewStorageService(dataFolder, nil)
+
+		// Act
+		usage, err := srv.Usage(ctx)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(usage.DatabaseBytes).To(BeZero())
+		Expect(usage.VddkBytes).To(BeZero())
+	})
+
+	// Given an agent running without a data folder
+	// When we measure storage usage
+	// Then it should return a resource not found error
+	It("should return not found without a data folder", func() {
+		// Arrange
+		srv := services.NewStorageService("", nil)
+
+		// Act
+		_, err := srv.Usage(ctx)
+
+		// Assert
+		Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+	})
+
+	// Given an agent running without a data folder
+	// When we compact the database
+	// Then it should return a resource not found error
+	It("should refuse to compact without a data folder", func() {
+		// Arrange
+		srv := services.NewStorageService("", nil)
+
+		// Act
+		err := srv.Compact(ctx)
+
+		// Assert
+		Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+	})
+})
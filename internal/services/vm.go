@@ -2,19 +2,50 @@ package services
 
 import (
 	"context"
+	"slices"
 
 	sq "github.com/Masterminds/squirrel"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
 )
 
+// defaultStreamBatchSize is the number of VMs Stream reads at a time.
+const defaultStreamBatchSize = 500
+
 type VMService struct {
-	store *store.Store
+	store              *store.Store
+	suppressedConcerns []string
+	concernAssessments map[string]string
+	streamBatchSize    uint64
 }
 
 func NewVMService(st *store.Store) *VMService {
-	return &VMService{store: st}
+	return &VMService{store: st, streamBatchSize: defaultStreamBatchSize}
+}
+
+// WithStreamBatchSize sets the number of VMs Stream reads at a time.
+func (s *VMService) WithStreamBatchSize(n int) *VMService {
+	if n > 0 {
+		s.streamBatchSize = uint64(n)
+	}
+	return s
+}
+
+// WithSuppressedConcerns sets the concern IDs left out of issue counts,
+// severity breakdowns and migratability in List, Count, OsDistribution and
+// GroupBy, unless the caller sets VMListParams.IncludeSuppressed.
+func (s *VMService) WithSuppressedConcerns(ids []string) *VMService {
+	s.suppressedConcerns = ids
+	return s
+}
+
+// WithConcernAssessments sets text, by concern ID, that Get and GetMany append
+// to the description of the VM issues with that ID.
+func (s *VMService) WithConcernAssessments(extra map[string]string) *VMService {
+	s.concernAssessments = extra
+	return s
 }
 
 type SortField struct {
@@ -23,10 +54,17 @@ type SortField struct {
 }
 
 type VMListParams struct {
-	Expression string
-	Sort       []SortField
-	Limit      uint64
-	Offset     uint64
+	Expression        string
+	HasCritical       bool               // only VMs with at least one Critical concern
+	IssueLabel        string             // only VMs with a concern whose label matches this regex
+	NICType           string             // only VMs with a NIC whose adapter type matches this regex
+	NICCountMin       *int               // only VMs with at least this many NICs
+	NICCountMax       *int               // only VMs with at most this many NICs
+	Readiness         []models.Readiness // only VMs with one of these readiness categories
+	IncludeSuppressed bool               // also count the concerns set with WithSuppressedConcerns
+	Sort              []SortField
+	Limit             uint64
+	Offset            uint64
 }
 
 func (s *VMService) Get(ctx context.Context, id string) (*models.VM, error) {
@@ -34,6 +72,7 @@ func (s *VMService) Get(ctx context.Context, id string) (*models.VM, error) {
 	if err != nil {
 		return nil, err
 	}
+	s.enrichIssues(vm)
 
 	results, err := s.store.Inspection().ListResults(ctx, id)
 	if err != nil {
@@ -48,22 +87,66 @@ func (s *VMService) Get(ctx context.Context, id string) (*models.VM, error) {
 	return vm, nil
 }
 
-func (s *VMService) List(ctx context.Context, params VMListParams) ([]models.VirtualMachineSummary, int, error) {
-	filters, opts := s.buildListOptions(params)
+// GetMany returns details for the VMs in ids, in the order requested, and the
+// IDs that matched no VM.
+func (s *VMService) GetMany(ctx context.Context, ids []string) ([]models.VM, []string, error) {
+	vms, err := s.store.VM().GetMany(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if len(params.Sort) == 0 {
-		opts = append(opts, store.WithDefaultSort())
+	found := make(map[string]struct{}, len(vms))
+	foundIDs := make([]string, 0, len(vms))
+	for i := range vms {
+		s.enrichIssues(&vms[i])
+		found[vms[i].ID] = struct{}{}
+		foundIDs = append(foundIDs, vms[i].ID)
+	}
+
+	notFound := []string{}
+	for _, id := range ids {
+		if _, ok := found[id]; !ok && !slices.Contains(notFound, id) {
+			notFound = append(notFound, id)
+		}
 	}
 
-	vms, err := s.store.VM().List(ctx, filters, opts...)
+	if len(vms) == 0 {
+		return vms, notFound, nil
+	}
+
+	results, err := s.store.Inspection().ListResultsByVM(ctx, foundIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range vms {
+		if r := results[vms[i].ID]; len(r) > 0 {
+			vms[i].InspectionConcerns = r[0].Concerns
+		}
+	}
+
+	return vms, notFound, nil
+}
+
+// enrichIssues appends the text set with WithConcernAssessments to the
+// description of the issues of vm.
+func (s *VMService) enrichIssues(vm *models.VM) {
+	for i, issue := range vm.Issues {
+		vm.Issues[i].Description = collectorV1.EnrichAssessment(issue.Description, s.concernAssessments[issue.ID])
+	}
+}
+
+// List returns a page of the VMs matching params and the total number of
+// matches. VMs are always ordered by ID last (see buildListOptions), so pages
+// neither overlap nor skip VMs, with or without params.Sort.
+func (s *VMService) List(ctx context.Context, params VMListParams) ([]models.VirtualMachineSummary, int, error) {
+	filters, opts := s.buildListOptions(params)
+
+	vms, err := s.vmStore(params).List(ctx, filters, opts...)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	countFilters, _ := s.buildListOptions(VMListParams{
-		Expression: params.Expression,
-	})
-	total, err := s.store.VM().Count(ctx, countFilters...)
+	total, err := s.Count(ctx, params)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -71,13 +154,92 @@ func (s *VMService) List(ctx context.Context, params VMListParams) ([]models.Vir
 	return vms, total, nil
 }
 
-func (s *VMService) buildListOptions(params VMListParams) ([]sq.Sqlizer, []store.ListOption) {
-	var filters []sq.Sqlizer
-	var opts []store.ListOption
+// Stream calls fn with every VM matching the filters in params, in the order
+// of params.Sort, reading them streamBatchSize at a time so the whole list is
+// never held in memory. Pagination in params is ignored; VMs are ordered by ID
+// last, so batches neither overlap nor skip VMs with equal sort keys.
+func (s *VMService) Stream(ctx context.Context, params VMListParams, fn func([]models.VirtualMachineSummary) error) error {
+	params.Limit, params.Offset = 0, 0
+	filters, opts := s.buildListOptions(params)
 
-	if params.Expression != "" {
-		filters = append(filters, store.ByFilter(params.Expression))
+	vmStore := s.vmStore(params)
+	for offset := uint64(0); ; offset += s.streamBatchSize {
+		batchOpts := append(slices.Clone(opts), store.WithLimit(s.streamBatchSize), store.WithOffset(offset))
+		vms, err := vmStore.List(ctx, filters, batchOpts...)
+		if err != nil {
+			return err
+		}
+		if len(vms) > 0 {
+			if err := fn(vms); err != nil {
+				return err
+			}
+		}
+		if uint64(len(vms)) < s.streamBatchSize {
+			return nil
+		}
+	}
+}
+
+// Explain returns the SQL and arguments List would run for params, with the
+// same filters, sort and pagination, without running it.
+func (s *VMService) Explain(params VMListParams) (string, []any, error) {
+	filters, opts := s.buildListOptions(params)
+
+	return s.vmStore(params).Explain(filters, opts...)
+}
+
+// Count returns the number of VMs matching the filters in params without
+// fetching any rows. Sort and pagination are ignored.
+func (s *VMService) Count(ctx context.Context, params VMListParams) (int, error) {
+	filters := s.buildFilters(params)
+	return s.vmStore(params).Count(ctx, filters...)
+}
+
+// OsDistribution returns the number of VMs per guest OS among the VMs
+// matching the filters in params. Sort and pagination are ignored.
+func (s *VMService) OsDistribution(ctx context.Context, params VMListParams) (map[string]int, error) {
+	filters := s.buildFilters(params)
+	return s.vmStore(params).OsDistribution(ctx, filters...)
+}
+
+// GroupBy returns the number of VMs per value of field among the VMs matching
+// the filters in params. Sort and pagination are ignored. Only the fields of
+// store.GroupFields are accepted; any other is a ValidationError.
+func (s *VMService) GroupBy(ctx context.Context, field string, params VMListParams) (map[string]int, error) {
+	filters := s.buildFilters(params)
+	return s.vmStore(params).GroupBy(ctx, field, filters...)
+}
+
+// CreateView saves a named GET /vms query. The caller validates the
+// expression and sort fields.
+func (s *VMService) CreateView(ctx context.Context, view models.VMView) (*models.VMView, error) {
+	return s.store.Views().Create(ctx, view)
+}
+
+// ListViews returns all saved views ordered by name.
+func (s *VMService) ListViews(ctx context.Context) ([]models.VMView, error) {
+	return s.store.Views().List(ctx)
+}
+
+// GetView returns a saved view by name.
+func (s *VMService) GetView(ctx context.Context, name string) (*models.VMView, error) {
+	return s.store.Views().Get(ctx, name)
+}
+
+// vmStore returns the VM store, hiding the suppressed concerns unless params
+// asks for them.
+func (s *VMService) vmStore(params VMListParams) *store.VMStore {
+	if params.IncludeSuppressed || len(s.suppressedConcerns) == 0 {
+		return s.store.VM()
 	}
+	return s.store.VM().WithoutConcerns(s.suppressedConcerns)
+}
+
+// buildListOptions turns params into store filters and list options. The
+// options always order by VM ID, alone or after params.Sort as a tiebreaker,
+// so LIMIT/OFFSET pages are stable.
+func (s *VMService) buildListOptions(params VMListParams) ([]sq.Sqlizer, []store.ListOption) {
+	var opts []store.ListOption
 
 	if len(params.Sort) > 0 {
 		sortParams := make([]store.SortParam, len(params.Sort))
@@ -85,6 +247,8 @@ func (s *VMService) buildListOptions(params VMListParams) ([]sq.Sqlizer, []store
 			sortParams[i] = store.SortParam{Field: s.Field, Desc: s.Desc}
 		}
 		opts = append(opts, store.WithSort(sortParams))
+	} else {
+		opts = append(opts, store.WithDefaultSort())
 	}
 
 	if params.Limit > 0 {
@@ -94,5 +258,37 @@ func (s *VMService) buildListOptions(params VMListParams) ([]sq.Sqlizer, []store
 		opts = append(opts, store.WithOffset(params.Offset))
 	}
 
-	return filters, opts
+	return s.buildFilters(params), opts
+}
+
+// buildFilters turns the filters in params into store filters, leaving out
+// sort and pagination.
+func (s *VMService) buildFilters(params VMListParams) []sq.Sqlizer {
+	var filters []sq.Sqlizer
+
+	if params.Expression != "" {
+		filters = append(filters, store.ByFilter(params.Expression))
+	}
+
+	if params.HasCritical {
+		filters = append(filters, store.ByCriticalConcerns())
+	}
+
+	if params.IssueLabel != "" {
+		filters = append(filters, store.ByConcernLabel(params.IssueLabel))
+	}
+
+	if params.NICType != "" {
+		filters = append(filters, store.ByNICType(params.NICType))
+	}
+
+	if params.NICCountMin != nil || params.NICCountMax != nil {
+		filters = append(filters, store.ByNICCount(params.NICCountMin, params.NICCountMax))
+	}
+
+	if len(params.Readiness) > 0 {
+		filters = append(filters, store.ByReadiness(params.Readiness))
+	}
+
+	return filters
 }
@@ -3,12 +3,14 @@ package services_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/services"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
 	"github.com/kubev2v/assisted-migration-agent/test"
@@ -180,5 +182,315 @@ var _ = Describe("VMService", func() {
 			Expect(total).To(Equal(10))
 			Expect(vms).To(BeEmpty())
 		})
+
+		// Given only vm-007 has a Critical concern
+		// When we list with HasCritical
+		// Then it should return only vm-007
+		It("should return only VMs with critical concerns", func() {
+			// Arrange
+			params := services.VMListParams{HasCritical: true}
+
+			// Act
+			vms, total, err := srv.List(ctx, params)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(1))
+			Expect(vms).To(HaveLen(1))
+			Expect(vms[0].ID).To(Equal("vm-007"))
+			Expect(vms[0].CriticalCount).To(Equal(1))
+		})
+
+		// Given vm-007 is the only VM with a Critical concern and lives in staging
+		// When we combine HasCritical with a cluster expression
+		// Then both conditions should apply
+		It("should combine HasCritical with an expression", func() {
+			// Act
+			staging, stagingTotal, err := srv.List(ctx, services.VMListParams{HasCritical: true, Expression: "cluster = 'staging'"})
+			Expect(err).NotTo(HaveOccurred())
+			production, productionTotal, err := srv.List(ctx, services.VMListParams{HasCritical: true, Expression: "cluster = 'production'"})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(stagingTotal).To(Equal(1))
+			Expect(staging).To(HaveLen(1))
+			Expect(staging[0].ID).To(Equal("vm-007"))
+			Expect(productionTotal).To(Equal(0))
+			Expect(production).To(BeEmpty())
+		})
+	})
+
+	Context("List pagination", func() {
+		// paginate lists every page of pageSize VMs for params, returning the
+		// IDs of each page.
+		paginate := func(params services.VMListParams, pageSize uint64) [][]string {
+			var pages [][]string
+			for offset := uint64(0); ; offset += pageSize {
+				params.Limit, params.Offset = pageSize, offset
+				vms, total, err := srv.List(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(total).To(Equal(10))
+				if len(vms) == 0 {
+					return pages
+				}
+				page := make([]string, 0, len(vms))
+				for _, vm := range vms {
+					page = append(page, vm.ID)
+				}
+				pages = append(pages, page)
+			}
+		}
+
+		// Given 10 VMs and a sort that leaves many ties, or no sort at all
+		// When we page through them twice, 3 at a time
+		// Then both runs should return the same pages, covering every VM
+		// exactly once
+		DescribeTable("should return stable, complete, non-overlapping pages",
+			func(sort []services.SortField) {
+				// Act
+				first := paginate(services.VMListParams{Sort: sort}, 3)
+				second := paginate(services.VMListParams{Sort: sort}, 3)
+
+				// Assert
+				Expect(second).To(Equal(first))
+				Expect(first).To(HaveLen(4))
+				var ids []string
+				for _, page := range first {
+					ids = append(ids, page...)
+				}
+				Expect(ids).To(HaveLen(10))
+				Expect(ids).To(ConsistOf("vm-001", "vm-002", "vm-003", "vm-004", "vm-005", "vm-006", "vm-007", "vm-008", "vm-009", "vm-010"))
+			},
+			Entry("without a sort", nil),
+			Entry("sorted by cluster", []services.SortField{{Field: "cluster"}}),
+			Entry("sorted by power state, descending", []services.SortField{{Field: "vCenterState", Desc: true}}),
+		)
+
+		// Given VMs sharing a cluster
+		// When we list them sorted by cluster
+		// Then VMs of the same cluster should be ordered by ID
+		It("should order VMs with equal sort keys by ID", func() {
+			// Act
+			vms, _, err := srv.List(ctx, services.VMListParams{Sort: []services.SortField{{Field: "cluster"}}})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			ids := make([]string, 0, len(vms))
+			for _, vm := range vms {
+				ids = append(ids, vm.ID)
+			}
+			Expect(ids).To(Equal([]string{
+				"vm-008", "vm-009", "vm-010", // development
+				"vm-001", "vm-002", "vm-003", "vm-004", // production
+				"vm-005", "vm-006", "vm-007", // staging
+			}))
+		})
+	})
+
+	Context("Stream", func() {
+		stream := func(params services.VMListParams) ([]models.VirtualMachineSummary, []int) {
+			var vms []models.VirtualMachineSummary
+			var batches []int
+			err := srv.WithStreamBatchSize(3).Stream(ctx, params, func(batch []models.VirtualMachineSummary) error {
+				vms = append(vms, batch...)
+				batches = append(batches, len(batch))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return vms, batches
+		}
+
+		// Given 10 VMs, several with the same memory, and a batch size of 3
+		// When we stream them sorted by memory, with pagination set
+		// Then every VM should be passed once, in batches, sorted by memory
+		// then ID, ignoring the pagination
+		It("should pass every VM once, in sort order", func() {
+			// Act
+			vms, batches := stream(services.VMListParams{
+				Sort:  []services.SortField{{Field: "memory", Desc: true}},
+				Limit: 2,
+			})
+
+			// Assert
+			Expect(batches).To(Equal([]int{3, 3, 3, 1}))
+			ids := make([]string, 0, len(vms))
+			for i, vm := range vms {
+				ids = append(ids, vm.ID)
+				if i > 0 {
+					prev := vms[i-1]
+					Expect(vm.Memory).To(BeNumerically("<=", prev.Memory))
+					if vm.Memory == prev.Memory {
+						Expect(vm.ID > prev.ID).To(BeTrue(), vm.ID)
+					}
+				}
+			}
+			Expect(ids).To(ConsistOf("vm-001", "vm-002", "vm-003", "vm-004", "vm-005", "vm-006", "vm-007", "vm-008", "vm-009", "vm-010"))
+		})
+
+		// Given a filter matching a full batch
+		// When we stream the matching VMs
+		// Then only they should be passed, without an empty last batch
+		It("should apply the filters", func() {
+			// Act
+			vms, batches := stream(services.VMListParams{Expression: "cluster = 'staging'"})
+
+			// Assert
+			Expect(batches).To(Equal([]int{3}))
+			Expect(vms).To(HaveLen(3))
+			for _, vm := range vms {
+				Expect(vm.Cluster).To(Equal("staging"))
+			}
+		})
+
+		// Given fn failing on the first batch
+		// When we stream
+		// Then the error should be returned and no more batches read
+		It("should stop at the first error of fn", func() {
+			// Arrange
+			calls := 0
+			failure := errors.New("client gone")
+
+			// Act
+			err := srv.WithStreamBatchSize(3).Stream(ctx, services.VMListParams{}, func([]models.VirtualMachineSummary) error {
+				calls++
+				return failure
+			})
+
+			// Assert
+			Expect(err).To(MatchError(failure))
+			Expect(calls).To(Equal(1))
+		})
+	})
+
+	Context("Count", func() {
+		// Given 10 VMs in the store
+		// When we count with pagination set
+		// Then it should return the total of all matching VMs
+		It("should ignore pagination", func() {
+			// Act
+			total, err := srv.Count(ctx, services.VMListParams{Limit: 3, Offset: 3})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(10))
+		})
+
+		// Given VMs in both "production" and "staging" clusters
+		// When we count with filters
+		// Then it should match the total reported by List
+		It("should apply expression and HasCritical filters", func() {
+			// Act
+			production, err := srv.Count(ctx, services.VMListParams{Expression: "cluster = 'production'"})
+			Expect(err).NotTo(HaveOccurred())
+			critical, err := srv.Count(ctx, services.VMListParams{HasCritical: true})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(production).To(Equal(4))
+			Expect(critical).To(Equal(1))
+		})
+	})
+
+	Context("Suppressed concerns", func() {
+		BeforeEach(func() {
+			// vm-007's only Critical concern and one of vm-003's warnings
+			srv = srv.WithSuppressedConcerns([]string{"concern-005", "concern-001"})
+		})
+
+		// Given suppressed concerns on vm-003 and vm-007
+		// When we list VMs
+		// Then their issue counts and severity breakdowns should leave them out
+		It("should leave suppressed concerns out of counts", func() {
+			// Act
+			vms, _, err := srv.List(ctx, services.VMListParams{Expression: "id in ['vm-003', 'vm-007']"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms).To(HaveLen(2))
+			byID := map[string]int{}
+			for i, vm := range vms {
+				byID[vm.ID] = i
+			}
+			Expect(vms[byID["vm-003"]].IssueCount).To(Equal(1))
+			Expect(vms[byID["vm-003"]].WarningCount).To(Equal(1))
+			Expect(vms[byID["vm-007"]].IssueCount).To(Equal(2))
+			Expect(vms[byID["vm-007"]].CriticalCount).To(Equal(0))
+			Expect(vms[byID["vm-007"]].IsMigratable).To(BeTrue())
+		})
+
+		// Given vm-007's only Critical concern is suppressed
+		// When we list and count VMs with critical concerns
+		// Then none should match unless suppressed concerns are included
+		It("should apply suppression to HasCritical unless IncludeSuppressed is set", func() {
+			// Act
+			vms, total, err := srv.List(ctx, services.VMListParams{HasCritical: true})
+			Expect(err).NotTo(HaveOccurred())
+			included, includedTotal, err := srv.List(ctx, services.VMListParams{HasCritical: true, IncludeSuppressed: true})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(total).To(Equal(0))
+			Expect(vms).To(BeEmpty())
+			Expect(includedTotal).To(Equal(1))
+			Expect(included).To(HaveLen(1))
+			Expect(included[0].CriticalCount).To(Equal(1))
+		})
+
+		// Given suppressed concerns on vm-007
+		// When we get the VM details
+		// Then every concern should still be returned
+		It("should keep suppressed concerns in VM details", func() {
+			// Act
+			vm, err := srv.Get(ctx, "vm-007")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vm.Issues).To(HaveLen(3))
+		})
+	})
+
+	Context("Concern assessments", func() {
+		BeforeEach(func() {
+			srv = srv.WithConcernAssessments(map[string]string{"concern-005": "See https://wiki.example.com/rdm"})
+		})
+
+		// Given extra assessment text for vm-007's RDM concern
+		// When we get the VM details, alone and in a batch
+		// Then that issue's description should carry the text and the others
+		// be left as they are
+		It("should append the configured text to matching issue descriptions", func() {
+			// Act
+			vm, err := srv.Get(ctx, "vm-007")
+			Expect(err).NotTo(HaveOccurred())
+			many, _, err := srv.GetMany(ctx, []string{"vm-007"})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			for _, got := range []models.VM{*vm, many[0]} {
+				Expect(got.Issues).To(HaveLen(3))
+				for _, issue := range got.Issues {
+					if issue.ID == "concern-005" {
+						Expect(issue.Description).To(HavePrefix("Raw Device Mapping (RDM) disk is attached."))
+						Expect(issue.Description).To(HaveSuffix("\n\nSee https://wiki.example.com/rdm"))
+					} else {
+						Expect(issue.Description).NotTo(ContainSubstring("wiki.example.com"))
+					}
+				}
+			}
+		})
+
+		// Given extra assessment text for vm-007's Critical concern
+		// When we list VMs
+		// Then the issue counts should be unchanged
+		It("should not change issue counts", func() {
+			// Act
+			vms, _, err := srv.List(ctx, services.VMListParams{Expression: "id = 'vm-007'"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms).To(HaveLen(1))
+			Expect(vms[0].IssueCount).To(Equal(3))
+			Expect(vms[0].CriticalCount).To(Equal(1))
+		})
 	})
 })
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+// CollectorStateStore keeps the state of the collection in flight, so a
+// collection interrupted by a restart can be told apart from one that never
+// ran.
+type CollectorStateStore struct {
+	db QueryInterceptor
+}
+
+func NewCollectorStateStore(db QueryInterceptor) *CollectorStateStore {
+	return &CollectorStateStore{db: db}
+}
+
+// Save records state as the state of the collection in flight.
+func (s *CollectorStateStore) Save(ctx context.Context, state models.CollectorStateType) error {
+	query, args, err := sq.Insert("collector_state").
+		Columns("id", "state", "updated_at").
+		Values(singleValidId, string(state), sq.Expr("now()")).
+		Suffix("ON CONFLICT (id) DO UPDATE SET state = EXCLUDED.state, updated_at = now()").
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// Get returns the state of the collection in flight, or a
+// ResourceNotFoundError if none is.
+func (s *CollectorStateStore) Get(ctx context.Context) (models.CollectorStateType, error) {
+	query, args, err := sq.Select("state").
+		From("collector_state").
+		Where(sq.Eq{"id": singleValidId}).
+		ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var state string
+	err = s.db.QueryRowContext(ctx, query, args...).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", srvErrors.NewResourceNotFoundError("collector state", "")
+	}
+	if err != nil {
+		return "", err
+	}
+	return models.CollectorStateType(state), nil
+}
+
+// Clear records that no collection is in flight.
+func (s *CollectorStateStore) Clear(ctx context.Context) error {
+	query, args, err := sq.Delete("collector_state").ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,73 @@
+package store_test
+
+import (
+	"context"
+	"database/sql"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+var _ = Describe("CollectorStateStore", func() {
+	var (
+		ctx context.Context
+		s   *store.Store
+		db  *sql.DB
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = migrations.Run(ctx, db)
+		Expect(err).NotTo(HaveOccurred())
+
+		s = store.NewStore(db, test.NewMockValidator())
+	})
+
+	AfterEach(func() {
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	// Given no collection in flight
+	// When the state is read
+	// Then it should return a ResourceNotFoundError
+	It("should return not found when no collection is in flight", func() {
+		// Act
+		_, err := s.CollectorState().Get(ctx)
+
+		// Assert
+		Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+	})
+
+	// Given a collection moving from connecting to collecting
+	// When the state is read, then cleared
+	// Then the latest state should be returned, and none after clearing
+	It("should keep the latest state until cleared", func() {
+		// Arrange
+		Expect(s.CollectorState().Save(ctx, models.CollectorStateConnecting)).To(Succeed())
+		Expect(s.CollectorState().Save(ctx, models.CollectorStateCollecting)).To(Succeed())
+
+		// Act
+		state, err := s.CollectorState().Get(ctx)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(Equal(models.CollectorStateCollecting))
+
+		Expect(s.CollectorState().Clear(ctx)).To(Succeed())
+		_, err = s.CollectorState().Get(ctx)
+		Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+	})
+})
@@ -2,8 +2,13 @@ package store
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -20,7 +25,7 @@ func NewConfigurationStore(db QueryInterceptor) *ConfigurationStore {
 }
 
 func (s *ConfigurationStore) Get(ctx context.Context) (*models.Configuration, error) {
-	query, args, err := sq.Select("agent_mode").
+	query, args, err := sq.Select("agent_mode", "COALESCE(maintenance, false)", "update_interval_ns", "legacy_status_enabled").
 		From("configuration").
 		Where(sq.Eq{"id": 1}).
 		ToSql()
@@ -29,19 +34,35 @@ func (s *ConfigurationStore) Get(ctx context.Context) (*models.Configuration, er
 	}
 
 	row := s.db.QueryRowContext(ctx, query, args...)
-	var agentMode string
-	err = row.Scan(&agentMode)
+	var (
+		agentMode      string
+		maintenance    bool
+		updateInterval sql.NullInt64
+		legacyStatus   sql.NullBool
+	)
+	err = row.Scan(&agentMode, &maintenance, &updateInterval, &legacyStatus)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, srvErrors.NewConfigurationNotFoundError()
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &models.Configuration{
-		AgentMode: models.AgentMode(agentMode),
-	}, nil
+	cfg := &models.Configuration{
+		AgentMode:   models.AgentMode(agentMode),
+		Maintenance: maintenance,
+	}
+	if updateInterval.Valid {
+		interval := time.Duration(updateInterval.Int64)
+		cfg.Console.UpdateInterval = &interval
+	}
+	if legacyStatus.Valid {
+		cfg.Console.LegacyStatusEnabled = &legacyStatus.Bool
+	}
+	return cfg, nil
 }
 
+// Save persists the agent mode. The maintenance flag and console settings are
+// left untouched.
 func (s *ConfigurationStore) Save(ctx context.Context, cfg *models.Configuration) error {
 	query, args, err := sq.Insert("configuration").
 		Columns("id", "agent_mode").
@@ -54,3 +75,82 @@ func (s *ConfigurationStore) Save(ctx context.Context, cfg *models.Configuration
 	_, err = s.db.ExecContext(ctx, query, args...)
 	return err
 }
+
+// SaveMaintenance persists the maintenance flag. The agent mode is left untouched.
+func (s *ConfigurationStore) SaveMaintenance(ctx context.Context, enabled bool) error {
+	query, args, err := sq.Insert("configuration").
+		Columns("id", "maintenance").
+		Values(1, enabled).
+		Suffix("ON CONFLICT (id) DO UPDATE SET maintenance = EXCLUDED.maintenance").
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// SaveConsoleSettings persists the console settings that are set in change and
+// clears the ones it resets. Other settings, the agent mode and the maintenance
+// flag are left untouched.
+func (s *ConfigurationStore) SaveConsoleSettings(ctx context.Context, change models.ConsoleSettingsChange) error {
+	columns := []string{"id"}
+	values := []any{1}
+	var updates []string
+	set := func(column string, value any) {
+		columns = append(columns, column)
+		values = append(values, value)
+		updates = append(updates, column+" = EXCLUDED."+column)
+	}
+	switch {
+	case change.ResetUpdateInterval:
+		set("update_interval_ns", nil)
+	case change.UpdateInterval != nil:
+		set("update_interval_ns", change.UpdateInterval.Nanoseconds())
+	}
+	switch {
+	case change.ResetLegacyStatusEnabled:
+		set("legacy_status_enabled", nil)
+	case change.LegacyStatusEnabled != nil:
+		set("legacy_status_enabled", *change.LegacyStatusEnabled)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	query, args, err := sq.Insert("configuration").
+		Columns(columns...).
+		Values(values...).
+		Suffix("ON CONFLICT (id) DO UPDATE SET " + strings.Join(updates, ", ")).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// RedactionKey returns the agent's redaction key, generating and saving a
+// random one the first time. The key is kept for the lifetime of the
+// database, so a value redacts alike on every push.
+func (s *ConfigurationStore) RedactionKey(ctx context.Context) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating redaction key: %w", err)
+	}
+
+	query, args, err := sq.Insert("configuration").
+		Columns("id", "redaction_key").
+		Values(1, hex.EncodeToString(buf)).
+		Suffix("ON CONFLICT (id) DO UPDATE SET redaction_key = COALESCE(configuration.redaction_key, EXCLUDED.redaction_key) RETURNING redaction_key").
+		ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var key string
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&key); err != nil {
+		return "", err
+	}
+	return key, nil
+}
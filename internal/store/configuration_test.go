@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -132,6 +133,129 @@ var _ = Describe("ConfigurationStore", func() {
 		})
 	})
 
+	Context("SaveMaintenance", func() {
+		// Given a saved agent mode
+		// When we enable maintenance
+		// Then the flag should be stored and the agent mode kept
+		It("should save the maintenance flag without touching the agent mode", func() {
+			// Arrange
+			Expect(s.Configuration().Save(ctx, &models.Configuration{AgentMode: models.AgentModeConnected})).To(Succeed())
+
+			// Act
+			err := s.Configuration().SaveMaintenance(ctx, true)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			retrieved, err := s.Configuration().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrieved.Maintenance).To(BeTrue())
+			Expect(retrieved.AgentMode).To(Equal(models.AgentModeConnected))
+		})
+
+		// Given maintenance enabled on an empty store
+		// When the agent mode is saved afterwards
+		// Then the maintenance flag should be kept
+		It("should keep the maintenance flag when the agent mode changes", func() {
+			// Arrange
+			Expect(s.Configuration().SaveMaintenance(ctx, true)).To(Succeed())
+
+			// Act
+			err := s.Configuration().Save(ctx, &models.Configuration{AgentMode: models.AgentModeConnected})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			retrieved, err := s.Configuration().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrieved.Maintenance).To(BeTrue())
+			Expect(retrieved.AgentMode).To(Equal(models.AgentModeConnected))
+		})
+	})
+
+	Context("SaveConsoleSettings", func() {
+		// Given a saved agent mode and no console settings
+		// When the configuration is read
+		// Then both console settings should be unset
+		It("should leave console settings unset by default", func() {
+			// Arrange
+			Expect(s.Configuration().Save(ctx, &models.Configuration{AgentMode: models.AgentModeConnected})).To(Succeed())
+
+			// Act
+			retrieved, err := s.Configuration().Get(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrieved.Console.UpdateInterval).To(BeNil())
+			Expect(retrieved.Console.LegacyStatusEnabled).To(BeNil())
+		})
+
+		// Given a saved agent mode and update interval
+		// When only the legacy status flag is saved
+		// Then the interval and agent mode should be kept
+		It("should only save the settings that are set", func() {
+			// Arrange
+			interval := 30 * time.Second
+			legacy := false
+			Expect(s.Configuration().Save(ctx, &models.Configuration{AgentMode: models.AgentModeConnected})).To(Succeed())
+			Expect(s.Configuration().SaveConsoleSettings(ctx, models.ConsoleSettingsChange{ConsoleSettings: models.ConsoleSettings{UpdateInterval: &interval}})).To(Succeed())
+
+			// Act
+			err := s.Configuration().SaveConsoleSettings(ctx, models.ConsoleSettingsChange{ConsoleSettings: models.ConsoleSettings{LegacyStatusEnabled: &legacy}})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			retrieved, err := s.Configuration().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrieved.AgentMode).To(Equal(models.AgentModeConnected))
+			Expect(retrieved.Console.UpdateInterval).To(HaveValue(Equal(30 * time.Second)))
+			Expect(retrieved.Console.LegacyStatusEnabled).To(HaveValue(BeFalse()))
+		})
+
+		// Given both console settings saved
+		// When only the update interval is reset
+		// Then the interval should be unset and the legacy status flag kept
+		It("should clear the settings that are reset", func() {
+			// Arrange
+			interval := 30 * time.Second
+			legacy := true
+			Expect(s.Configuration().SaveConsoleSettings(ctx, models.ConsoleSettingsChange{
+				ConsoleSettings: models.ConsoleSettings{UpdateInterval: &interval, LegacyStatusEnabled: &legacy},
+			})).To(Succeed())
+
+			// Act
+			err := s.Configuration().SaveConsoleSettings(ctx, models.ConsoleSettingsChange{ResetUpdateInterval: true})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			retrieved, err := s.Configuration().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrieved.Console.UpdateInterval).To(BeNil())
+			Expect(retrieved.Console.LegacyStatusEnabled).To(HaveValue(BeTrue()))
+		})
+	})
+
+	Context("RedactionKey", func() {
+		// Given a saved agent mode and no redaction key
+		// When the redaction key is read twice
+		// Then a random key should be generated once and returned both times
+		It("should generate the key once and keep it", func() {
+			// Arrange
+			Expect(s.Configuration().Save(ctx, &models.Configuration{AgentMode: models.AgentModeConnected})).To(Succeed())
+
+			// Act
+			first, err := s.Configuration().RedactionKey(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := s.Configuration().RedactionKey(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first).To(HaveLen(64))
+			Expect(second).To(Equal(first))
+			retrieved, err := s.Configuration().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrieved.AgentMode).To(Equal(models.AgentModeConnected))
+		})
+	})
+
 	Context("Concurrent writes", func() {
 		// Given multiple goroutines writing to the same configuration
 		// When all goroutines attempt to save configuration simultaneously
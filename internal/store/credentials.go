@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+// Constants for credentials table
+const (
+	credentialsTable       = "credentials"
+	credentialsColId       = "id"
+	credentialsColURL      = "url"
+	credentialsColUsername = "username"
+	credentialsColPassword = "password"
+	credentialsColInsecure = "insecure"
+
+	// credentialsKeyInfo binds derived keys to this use, so the same secret
+	// used elsewhere does not yield the same key.
+	credentialsKeyInfo = "assisted-migration-agent/credentials"
+)
+
+var errNoCredentialsSecret = errors.New("credentials secret is not configured")
+
+// CredentialsStore persists the last vCenter credentials so the agent can
+// recollect on a schedule, including after a restart. The password is sealed
+// with AES-256-GCM under a key derived from the configured secret; the URL
+// and username are stored in clear and authenticated as associated data.
+type CredentialsStore struct {
+	db  QueryInterceptor
+	key []byte
+}
+
+func NewCredentialsStore(db QueryInterceptor) *CredentialsStore {
+	return &CredentialsStore{db: db}
+}
+
+// WithSecret derives the encryption key from secret. Until a secret is set,
+// Save and Get fail.
+func (s *CredentialsStore) WithSecret(secret string) *CredentialsStore {
+	key, err := hkdf.Key(sha256.New, []byte(secret), nil, credentialsKeyInfo, 32)
+	if err != nil {
+		// Only possible for an out-of-range key length.
+		panic(err)
+	}
+	s.key = key
+	return s
+}
+
+// Save encrypts the password and replaces the stored credentials.
+func (s *CredentialsStore) Save(ctx context.Context, creds models.Credentials) error {
+	aead, err := s.aead()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(creds.Password), credentialsAD(creds))
+
+	query, args, err := sq.Insert(credentialsTable).
+		Columns(credentialsColId, credentialsColURL, credentialsColUsername, credentialsColPassword, credentialsColInsecure).
+		Values(singleValidId, creds.URL, creds.Username, sealed, creds.Insecure).
+		Suffix("ON CONFLICT (id) DO UPDATE SET url = EXCLUDED.url, username = EXCLUDED.username, password = EXCLUDED.password, insecure = EXCLUDED.insecure").
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// Get returns the stored credentials with the password decrypted. Returns a
+// ResourceNotFoundError when none are stored, and an error if the password
+// cannot be decrypted (e.g. the secret changed).
+func (s *CredentialsStore) Get(ctx context.Context) (*models.Credentials, error) {
+	aead, err := s.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := sq.Select(credentialsColURL, credentialsColUsername, credentialsColPassword, credentialsColInsecure).
+		From(credentialsTable).
+		Where(sq.Eq{credentialsColId: singleValidId}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var creds models.Credentials
+	var sealed []byte
+	err = s.db.QueryRowContext(ctx, query, args...).Scan(&creds.URL, &creds.Username, &sealed, &creds.Insecure)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, srvErrors.NewResourceNotFoundError("credentials", "")
+		}
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("decrypting credentials: ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	password, err := aead.Open(nil, nonce, ciphertext, credentialsAD(creds))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting credentials: %w", err)
+	}
+	creds.Password = string(password)
+
+	return &creds, nil
+}
+
+func (s *CredentialsStore) aead() (cipher.AEAD, error) {
+	if s.key == nil {
+		return nil, errNoCredentialsSecret
+	}
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func credentialsAD(creds models.Credentials) []byte {
+	return []byte(creds.URL + "\x00" + creds.Username)
+}
@@ -0,0 +1,143 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+var _ = Describe("CredentialsStore", func() {
+	var (
+		ctx   context.Context
+		s     *store.Store
+		db    *sql.DB
+		creds models.Credentials
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = migrations.Run(ctx, db)
+		Expect(err).NotTo(HaveOccurred())
+
+		s = store.NewStore(db, test.NewMockValidator())
+		creds = models.Credentials{
+			URL:      "https://vcenter.example.com",
+			Username: "administrator@vsphere.local",
+			Password: "s3cr3t-p@ss",
+			Insecure: true,
+		}
+	})
+
+	AfterEach(func() {
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	// Given a store with a secret
+	// When credentials are saved and read back
+	// Then the password should round-trip and the latest save should win
+	It("should round-trip saved credentials", func() {
+		// Arrange
+		s.Credentials().WithSecret("agent-secret")
+		Expect(s.Credentials().Save(ctx, models.Credentials{URL: "https://old", Username: "old", Password: "old"})).To(Succeed())
+
+		// Act
+		Expect(s.Credentials().Save(ctx, creds)).To(Succeed())
+		got, err := s.Credentials().Get(ctx)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*got).To(Equal(creds))
+	})
+
+	// Given saved credentials
+	// When the raw row is read
+	// Then the password should not be stored in clear
+	It("should not persist the password in clear", func() {
+		// Arrange
+		s.Credentials().WithSecret("agent-secret")
+		Expect(s.Credentials().Save(ctx, creds)).To(Succeed())
+
+		// Act
+		var raw []byte
+		err := db.QueryRowContext(ctx, "SELECT password FROM credentials").Scan(&raw)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(raw).NotTo(BeEmpty())
+		Expect(bytes.Contains(raw, []byte(creds.Password))).To(BeFalse())
+	})
+
+	// Given credentials saved under one secret
+	// When they are read with a different secret
+	// Then decryption should fail
+	It("should fail to decrypt with a different secret", func() {
+		// Arrange
+		s.Credentials().WithSecret("agent-secret")
+		Expect(s.Credentials().Save(ctx, creds)).To(Succeed())
+
+		// Act
+		s.Credentials().WithSecret("other-secret")
+		_, err := s.Credentials().Get(ctx)
+
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("decrypting credentials")))
+	})
+
+	// Given credentials whose username was altered in the database
+	// When they are read
+	// Then decryption should fail because the row is authenticated
+	It("should reject a tampered row", func() {
+		// Arrange
+		s.Credentials().WithSecret("agent-secret")
+		Expect(s.Credentials().Save(ctx, creds)).To(Succeed())
+		_, err := db.ExecContext(ctx, "UPDATE credentials SET username = 'attacker'")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Act
+		_, err = s.Credentials().Get(ctx)
+
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+
+	// Given a store with a secret and nothing saved
+	// When credentials are read
+	// Then it should return a resource not found error
+	It("should return not found when nothing is stored", func() {
+		// Arrange
+		s.Credentials().WithSecret("agent-secret")
+
+		// Act
+		_, err := s.Credentials().Get(ctx)
+
+		// Assert
+		Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+	})
+
+	// Given a store without a secret
+	// When credentials are saved
+	// Then it should refuse
+	It("should refuse to save without a secret", func() {
+		// Act
+		err := s.Credentials().Save(ctx, creds)
+
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -53,10 +53,10 @@ func (l *ExtensionLoader) loadExtension(conn *sql.DB, extDir string, ext Extensi
 	if _, err := os.Stat(extPath); err == nil {
 		// Local extension found, install and load from path
 		zap.S().Infof("loading %s extension from local file: %s", ext.Name, extPath)
-		if _, err := conn.Exec(fmt.Sprintf("INSTALL '%s'", extPath)); err != nil {
+		if _, err := conn.Exec("INSTALL " + quoteLiteral(extPath)); err != nil {
 			return fmt.Errorf("installing %s extension from local path: %w", ext.Name, err)
 		}
-		if _, err := conn.Exec(fmt.Sprintf("LOAD '%s'", extPath)); err != nil {
+		if _, err := conn.Exec("LOAD " + quoteLiteral(extPath)); err != nil {
 			return fmt.Errorf("loading %s extension from local path: %w", ext.Name, err)
 		}
 	} else {
@@ -72,6 +72,9 @@ func (l *ExtensionLoader) loadExtension(conn *sql.DB, extDir string, ext Extensi
 	return nil
 }
 
+// DatabaseFile is the name of the DuckDB file inside the agent data folder.
+const DatabaseFile = "agent.duckdb"
+
 // NewDB opens a DuckDB database at the given path.
 // Use ":memory:" for an in-memory database (useful for testing).
 func NewDB(loader *ExtensionLoader, path string) (*sql.DB, error) {
@@ -97,7 +100,7 @@ func NewDB(loader *ExtensionLoader, path string) (*sql.DB, error) {
 	// Configure extension directory to the same folder as the database
 	// This prevents DuckDB from trying to write to ~/.duckdb which may be read-only
 	extDir := filepath.Dir(path)
-	if _, err := conn.Exec(fmt.Sprintf("SET extension_directory = '%s'", extDir)); err != nil {
+	if _, err := conn.Exec("SET extension_directory = " + quoteLiteral(extDir)); err != nil {
 		_ = conn.Close()
 		return nil, fmt.Errorf("setting extension directory: %w", err)
 	}
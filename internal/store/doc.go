@@ -15,9 +15,20 @@
 // inside transactions because DuckDB does not support it there.
 //
 // The database uses DuckDB's WAL mode. Checkpoint() forces a WAL flush to
-// the main file. The sqlite_scanner extension is bundled and loaded at
-// connection time so Parser().IngestSqlite() can read collector output
-// directly without downloading it at runtime. This is required because the
+// the main file. Compact() additionally runs VACUUM ANALYZE before the
+// checkpoint to reclaim space left by deletes; it is a no-op for ":memory:".
+// ClearInventory deletes the parsed inventory tables and the rows keyed on
+// their VMs (inspection results, concerns, group matches) one statement at a
+// time outside a transaction; groups and the inventory snapshot are kept.
+// ReplaceInventory ingests a collection into a staging database file and,
+// only if that succeeds, attaches it and deletes and copies the collected
+// tables in one transaction, carrying over the inspection results of VMs still
+// collected. The tables keyed on vinfo have no foreign keys (migration 026):
+// DuckDB cannot delete a referenced row in the transaction that deleted its
+// references.
+// The sqlite_scanner extension is bundled and loaded at connection time so
+// Parser().IngestSqlite() can read collector output directly without
+// downloading it at runtime. This is required because the
 // agent may be deployed in air-gapped environments with no internet access.
 //
 // # Architecture Overview
@@ -49,6 +60,13 @@
 //	│  schema_migrations      │  Migration version tracking                 │
 //	│  vm_inspection_status   │ Per-VM deep-inspection state / queue        │
 //	│  vm_inspection_concerns │ Per-run inspection concern rows (FK vinfo)  │
+//	│  credentials            │  Last vCenter credentials (password sealed) │
+//	│  inventory_snapshots    │  Timestamps of the last two collections     │
+//	│  inventory_snapshot_vms │  Per-VM CPU, memory, disk of those runs     │
+//	│  vm_views               │  Saved GET /vms filter/sort/pageSize sets   │
+//	│  vsnapshots             │  VM snapshot trees read from vCenter        │
+//	│  vcreation              │  VM creation dates read from vCenter        │
+//	│  collector_state        │  State of the collection in flight          │
 //	└─────────────────────────┴─────────────────────────────────────────────┘
 //
 // Tables created by DUCKDB_PARSER (parser.Init()):
@@ -88,12 +106,16 @@
 //
 //	configuration (
 //	    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
-//	    agent_mode VARCHAR DEFAULT 'disconnected'
+//	    agent_mode VARCHAR DEFAULT 'disconnected',
+//	    ...
+//	    redaction_key VARCHAR
 //	)
 //
 // Methods:
 //   - Get(ctx) → *models.Configuration
 //   - Save(ctx, cfg) → error (uses UPSERT)
+//   - RedactionKey(ctx) → (string, error): the key pushed inventory fields are
+//     HMAC'd with; generated on first call and kept
 //
 // # InventoryStore
 //
@@ -113,6 +135,39 @@
 //   - Get(ctx) → *models.Inventory
 //   - Save(ctx, data []byte) → error (uses UPSERT, updates updated_at)
 //
+// # CredentialsStore
+//
+// Persists the last vCenter credentials in a single-row table so scheduled
+// recollection survives restarts. WithSecret derives an AES-256 key from the
+// configured secret with HKDF-SHA256; until it is called, Save and Get fail.
+// The password is sealed with AES-GCM and stored as nonce || ciphertext. The
+// URL and username are stored in clear but bound to the ciphertext as
+// associated data, so editing them makes Get fail, as does a changed secret.
+//
+// Schema:
+//
+//	credentials (
+//	    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+//	    url VARCHAR NOT NULL,
+//	    username VARCHAR NOT NULL,
+//	    password BLOB NOT NULL
+//	)
+//
+// Methods:
+//   - WithSecret(secret) → *CredentialsStore
+//   - Get(ctx) → *models.Credentials (ResourceNotFoundError when empty)
+//   - Save(ctx, creds) → error (uses UPSERT)
+//
+// # CollectorStateStore
+//
+// Keeps the state of the collection in flight in a single-row table, so a
+// collection cut short by a restart can be resumed or reported as failed.
+//
+// Methods:
+//   - Save(ctx, state) → error (uses UPSERT)
+//   - Get(ctx) → models.CollectorStateType (ResourceNotFoundError when none)
+//   - Clear(ctx) → error
+//
 // # GroupStore
 //
 // Stores named filter expressions (groups) that dynamically match VMs.
@@ -168,8 +223,8 @@
 // and inserts one row per concern. VM list/filter joins the latest run per VM
 // (max inspection_id) as alias `ic` for inspection_concern.* filter fields.
 //
-// Methods (status): Get, List, First, Add, Update, DeleteAll.
-// Methods (concerns): InsertResult, ListResults.
+// Methods (status): Get, List, Add, Update, DeleteAll.
+// Methods (concerns): InsertResult, ListResults, ListResultsByVM.
 //
 // # VMStore
 //
@@ -237,6 +292,16 @@
 //
 //	total, err := store.VM().Count(ctx, store.ByFilter("status = 'poweredOn'"))
 //
+// Count counts DISTINCT VM IDs, so a VM with several disks or NICs matching a
+// filter is counted once, as List returns it once.
+//
+// WithoutConcerns returns a view of the VM store whose List, Count,
+// OsDistribution and GroupBy ignore the given concern IDs. It prefixes the query with a
+// "WITH concerns AS (...)" CTE that shadows the parser's concerns table, so the
+// issue counts, migratability and the filter subquery all read the kept rows:
+//
+//	vms, err := store.VM().WithoutConcerns([]string{"vmware.cbt"}).List(ctx, nil)
+//
 // Filtering:
 //
 //   - ByFilter(expr string)
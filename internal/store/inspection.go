@@ -222,14 +222,25 @@ func (s *InspectionStore) InsertResult(ctx context.Context, vmID string, concern
 }
 
 func (s *InspectionStore) ListResults(ctx context.Context, vmID string) ([]models.VmInspectionResult, error) {
+	results, err := s.ListResultsByVM(ctx, []string{vmID})
+	if err != nil {
+		return nil, err
+	}
+	return results[vmID], nil
+}
+
+// ListResultsByVM returns the inspection results of each VM in vmIDs, newest
+// first, keyed by VM ID. VMs without results are absent from the map.
+func (s *InspectionStore) ListResultsByVM(ctx context.Context, vmIDs []string) (map[string][]models.VmInspectionResult, error) {
 	query, args, err := sq.Select(
+		"c."+vmInspectionConcernsColVMID,
 		"c."+vmInspectionConcernsColInspectionID,
 		"c."+vmInspectionConcernsColCategory,
 		"c."+vmInspectionConcernsColLabel,
 		"c."+vmInspectionConcernsColMsg,
 	).From(vmInspectionConcernsTable+" c").
-		Where(sq.Eq{`c.` + vmInspectionConcernsColVMID: vmID}).
-		OrderBy("c."+vmInspectionConcernsColInspectionID+" DESC", "c.id").
+		Where(sq.Eq{`c.` + vmInspectionConcernsColVMID: vmIDs}).
+		OrderBy("c."+vmInspectionConcernsColVMID, "c."+vmInspectionConcernsColInspectionID+" DESC", "c.id").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("building list inspection results: %w", err)
@@ -240,38 +251,36 @@ func (s *InspectionStore) ListResults(ctx context.Context, vmID string) ([]model
 	}
 	defer func() { _ = rows.Close() }()
 
-	var out []models.VmInspectionResult
+	out := make(map[string][]models.VmInspectionResult)
 	var cur *models.VmInspectionResult
-	var lastID int64 = -1
+	flush := func() {
+		if cur != nil {
+			out[cur.VMID] = append(out[cur.VMID], *cur)
+		}
+	}
 
 	for rows.Next() {
+		var vmID string
 		var inspectionID int64
 		var cat, label, msg sql.NullString
-		if err := rows.Scan(&inspectionID, &cat, &label, &msg); err != nil {
+		if err := rows.Scan(&vmID, &inspectionID, &cat, &label, &msg); err != nil {
 			return nil, fmt.Errorf("scanning vm inspection result row: %w", err)
 		}
-		if inspectionID != lastID {
-			if cur != nil {
-				out = append(out, *cur)
-			}
+		if cur == nil || cur.VMID != vmID || cur.InspectionID != inspectionID {
+			flush()
 			cur = &models.VmInspectionResult{
 				InspectionID: inspectionID,
 				VMID:         vmID,
 				Concerns:     []models.VmInspectionConcern{},
 			}
-			lastID = inspectionID
 		}
-		if cur != nil {
-			cur.Concerns = append(cur.Concerns, models.VmInspectionConcern{
-				Category: cat.String,
-				Label:    label.String,
-				Msg:      msg.String,
-			})
-		}
-	}
-	if cur != nil {
-		out = append(out, *cur)
+		cur.Concerns = append(cur.Concerns, models.VmInspectionConcern{
+			Category: cat.String,
+			Label:    label.String,
+			Msg:      msg.String,
+		})
 	}
+	flush()
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterating vm inspection results: %w", err)
 	}
@@ -1,11 +1,16 @@
 package store
 
 import (
+	"strings"
+
 	sq "github.com/Masterminds/squirrel"
 
 	"github.com/kubev2v/assisted-migration-agent/internal/models"
 )
 
+// likeEscaper escapes the LIKE wildcards and the escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type InspectionFilterFunc func(sq.SelectBuilder) sq.SelectBuilder
 
 type InspectionQueryFilter struct {
@@ -45,6 +50,18 @@ func (f *InspectionQueryFilter) ByStatus(statuses ...models.InspectionState) *In
 	})
 }
 
+// ByErrorContains keeps rows whose error message contains substr. LIKE wildcards
+// in substr are matched literally.
+func (f *InspectionQueryFilter) ByErrorContains(substr string) *InspectionQueryFilter {
+	if substr == "" {
+		return f
+	}
+	pattern := "%" + likeEscaper.Replace(substr) + "%"
+	return f.Add(func(b sq.SelectBuilder) sq.SelectBuilder {
+		return b.Where(sq.Expr(inspectionColError+` LIKE ? ESCAPE '\'`, pattern))
+	})
+}
+
 func (f *InspectionQueryFilter) Limit(limit int) *InspectionQueryFilter {
 	return f.Add(func(b sq.SelectBuilder) sq.SelectBuilder {
 		return b.Limit(uint64(limit))
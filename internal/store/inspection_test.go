@@ -3,6 +3,7 @@ package store_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -104,6 +105,29 @@ var _ = Describe("InspectionStore", func() {
 			Expect(results[1].Concerns[0]).To(Equal(models.VmInspectionConcern{Category: "stale", Label: "first-run", Msg: "from-first"}))
 		})
 
+		It("should group results by VM", func() {
+			_, err := db.ExecContext(ctx, `
+				INSERT INTO vinfo ("VM ID", "VM") VALUES ('vm-inspect-2', 'other')
+			`)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = s.WithTx(ctx, func(txCtx context.Context) error {
+				if err := s.Inspection().InsertResult(txCtx, "vm-inspect-1", []models.VmInspectionConcern{{Category: "disk", Label: "a", Msg: "one"}}); err != nil {
+					return err
+				}
+				return s.Inspection().InsertResult(txCtx, "vm-inspect-2", []models.VmInspectionConcern{{Category: "network", Label: "b", Msg: "two"}})
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			results, err := s.Inspection().ListResultsByVM(ctx, []string{"vm-inspect-1", "vm-inspect-2", "vm-no-result"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			Expect(results["vm-inspect-1"]).To(HaveLen(1))
+			Expect(results["vm-inspect-1"][0].Concerns).To(Equal([]models.VmInspectionConcern{{Category: "disk", Label: "a", Msg: "one"}}))
+			Expect(results["vm-inspect-2"][0].VMID).To(Equal("vm-inspect-2"))
+			Expect(results["vm-inspect-2"][0].Concerns[0].Msg).To(Equal("two"))
+		})
+
 		It("should return an empty list when the VM has no inspection results", func() {
 			_, err := db.ExecContext(ctx, `
 				INSERT INTO vinfo ("VM ID", "VM") VALUES ('vm-no-result', 'other')
@@ -115,4 +139,98 @@ var _ = Describe("InspectionStore", func() {
 			Expect(results).To(BeEmpty())
 		})
 	})
+
+	Context("statuses", func() {
+		var (
+			ctx context.Context
+			s   *store.Store
+			db  *sql.DB
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+
+			var err error
+			db, err = store.NewDB(nil, ":memory:")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = migrations.Run(ctx, db)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = db.ExecContext(ctx, `
+				INSERT INTO vinfo ("VM ID", "VM") VALUES
+					('vm-1', 'one'), ('vm-2', 'two'), ('vm-3', 'three'), ('vm-4', 'four')
+			`)
+			Expect(err).NotTo(HaveOccurred())
+
+			s = store.NewStore(db, test.NewMockValidator())
+
+			for _, id := range []string{"vm-1", "vm-2", "vm-3", "vm-4"} {
+				Expect(s.Inspection().Add(ctx, []string{id}, models.InspectionStatePending)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			if db != nil {
+				_ = db.Close()
+			}
+		})
+
+		// Given VMs that failed with different errors
+		// When we filter by an error substring
+		// Then only the VMs whose error contains it are returned
+		It("should filter statuses by error substring", func() {
+			// Arrange
+			failed := map[string]string{
+				"vm-1": "snapshot failed: disk is locked",
+				"vm-2": "privileges missing: VirtualMachine.Snapshot",
+				"vm-3": "snapshot failed: timeout",
+			}
+			for id, msg := range failed {
+				Expect(s.Inspection().Update(ctx, store.NewInspectionUpdateFilter().ByVmIDs(id),
+					models.InspectionStatus{State: models.InspectionStateError, Error: errors.New(msg)})).To(Succeed())
+			}
+
+			// Act
+			statuses, err := s.Inspection().List(ctx, store.NewInspectionQueryFilter().ByErrorContains("snapshot failed"))
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses).To(HaveKey("vm-1"))
+			Expect(statuses).To(HaveKey("vm-3"))
+			Expect(statuses["vm-1"].Error).To(MatchError("snapshot failed: disk is locked"))
+		})
+
+		// Given errors containing LIKE wildcard characters
+		// When we filter by a substring containing % or _
+		// Then the wildcards are matched literally
+		It("should match LIKE wildcards literally", func() {
+			// Arrange
+			Expect(s.Inspection().Update(ctx, store.NewInspectionUpdateFilter().ByVmIDs("vm-1"),
+				models.InspectionStatus{State: models.InspectionStateError, Error: errors.New("disk 100% full")})).To(Succeed())
+			Expect(s.Inspection().Update(ctx, store.NewInspectionUpdateFilter().ByVmIDs("vm-2"),
+				models.InspectionStatus{State: models.InspectionStateError, Error: errors.New("disk 1000 full")})).To(Succeed())
+
+			// Act
+			percent, err := s.Inspection().List(ctx, store.NewInspectionQueryFilter().ByErrorContains("100% full"))
+			Expect(err).NotTo(HaveOccurred())
+			underscore, err := s.Inspection().List(ctx, store.NewInspectionQueryFilter().ByErrorContains("disk_1"))
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert
+			Expect(percent).To(HaveLen(1))
+			Expect(percent).To(HaveKey("vm-1"))
+			Expect(underscore).To(BeEmpty())
+		})
+
+		// Given VMs without errors
+		// When we filter with an empty substring
+		// Then the filter is not applied
+		It("should not filter on an empty substring", func() {
+			statuses, err := s.Inspection().List(ctx, store.NewInspectionQueryFilter().ByErrorContains(""))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveLen(4))
+		})
+	})
 })
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -19,6 +21,12 @@ func NewInventoryStore(db QueryInterceptor) *InventoryStore {
 	return &InventoryStore{db: db}
 }
 
+// Snapshot generations: the current collection and the one before it.
+const (
+	snapshotCurrent  = 0
+	snapshotPrevious = 1
+)
+
 func (s *InventoryStore) Get(ctx context.Context) (*models.Inventory, error) {
 	query, args, err := sq.Select("data", "created_at", "updated_at").
 		From("inventory").
@@ -52,3 +60,240 @@ func (s *InventoryStore) Save(ctx context.Context, data []byte) error {
 	_, err = s.db.ExecContext(ctx, query, args...)
 	return err
 }
+
+// SaveWarnings replaces the warnings of the last collection with the given
+// ones, keeping their order. Call it in the transaction saving the inventory.
+func (s *InventoryStore) SaveWarnings(ctx context.Context, warnings []string) error {
+	query, args, err := sq.Delete("collection_warnings").ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("clearing collection warnings: %w", err)
+	}
+
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	insert := sq.Insert("collection_warnings").Columns("position", "message")
+	for i, w := range warnings {
+		insert = insert.Values(i, w)
+	}
+	query, args, err = insert.ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("saving collection warnings: %w", err)
+	}
+	return nil
+}
+
+// Warnings returns the warnings of the last collection, in the order they
+// were raised, or an empty list if it had none.
+func (s *InventoryStore) Warnings(ctx context.Context) ([]string, error) {
+	query, args, err := sq.Select("message").
+		From("collection_warnings").
+		OrderBy("position").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	warnings := []string{}
+	for rows.Next() {
+		var w string
+		if err := rows.Scan(&w); err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings, rows.Err()
+}
+
+// AppendHistory records data as the inventory collected at takenAt and prunes
+// the history to the newest collection and the previous ones before it. Call
+// it in the transaction saving the inventory.
+func (s *InventoryStore) AppendHistory(ctx context.Context, data []byte, takenAt time.Time, previous int) error {
+	query, args, err := sq.Insert("inventory_history").
+		Columns("taken_at", "data").
+		Values(takenAt, data).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("recording inventory history: %w", err)
+	}
+
+	kept := sq.Select("taken_at").
+		From("inventory_history").
+		OrderBy("taken_at DESC").
+		Limit(uint64(max(previous, 0) + 1))
+	query, args, err = sq.Delete("inventory_history").
+		Where(sq.Expr("taken_at NOT IN (?)", kept)).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("pruning inventory history: %w", err)
+	}
+	return nil
+}
+
+// History returns when each kept inventory was collected, newest first.
+func (s *InventoryStore) History(ctx context.Context) ([]time.Time, error) {
+	query, args, err := sq.Select("taken_at").
+		From("inventory_history").
+		OrderBy("taken_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	history := []time.Time{}
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		history = append(history, t)
+	}
+	return history, rows.Err()
+}
+
+// GetAt returns the inventory collected at takenAt, or a
+// ResourceNotFoundError if it is not (or no longer) kept.
+func (s *InventoryStore) GetAt(ctx context.Context, takenAt time.Time) (*models.Inventory, error) {
+	query, args, err := sq.Select("data", "taken_at").
+		From("inventory_history").
+		Where(sq.Eq{"taken_at": takenAt}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var inv models.Inventory
+	err = s.db.QueryRowContext(ctx, query, args...).Scan(&inv.Data, &inv.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, srvErrors.NewResourceNotFoundError("inventory snapshot", takenAt.Format(time.RFC3339Nano))
+	}
+	if err != nil {
+		return nil, err
+	}
+	inv.UpdatedAt = inv.CreatedAt
+	return &inv, nil
+}
+
+// RotateSnapshot makes the current per-VM snapshot the previous one and
+// records the parsed inventory (vinfo, vdisk) as the new current snapshot.
+// Call it inside a transaction after a successful ingest.
+func (s *InventoryStore) RotateSnapshot(ctx context.Context) error {
+	stmts := []sq.Sqlizer{
+		sq.Delete("inventory_snapshot_vms").Where(sq.Eq{"generation": snapshotPrevious}),
+		sq.Delete("inventory_snapshots").Where(sq.Eq{"generation": snapshotPrevious}),
+		sq.Update("inventory_snapshot_vms").Set("generation", snapshotPrevious).Where(sq.Eq{"generation": snapshotCurrent}),
+		sq.Update("inventory_snapshots").Set("generation", snapshotPrevious).Where(sq.Eq{"generation": snapshotCurrent}),
+		sq.Insert("inventory_snapshot_vms").
+			Columns("generation", "vm_id", "name", "cpus", "memory_mb", "disk_mb").
+			Select(sq.Select(
+				fmt.Sprint(snapshotCurrent),
+				`v."VM ID"`,
+				`v."VM"`,
+				`COALESCE(v."CPUs", 0)`,
+				`COALESCE(v."Memory", 0)`,
+				`COALESCE(d.total_disk, 0)`,
+			).From("vinfo v").
+				LeftJoin(`(SELECT "VM ID", SUM("Capacity MiB") AS total_disk FROM vdisk GROUP BY "VM ID") d ON v."VM ID" = d."VM ID"`)),
+		sq.Insert("inventory_snapshots").
+			Columns("generation", "taken_at").
+			Values(snapshotCurrent, sq.Expr("now()")),
+	}
+
+	for _, stmt := range stmts {
+		query, args, err := stmt.ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("rotating inventory snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// CurrentSnapshot returns the per-VM snapshot of the last successful
+// collection, or a ResourceNotFoundError if there is none.
+func (s *InventoryStore) CurrentSnapshot(ctx context.Context) (*models.InventorySnapshot, error) {
+	return s.snapshot(ctx, snapshotCurrent)
+}
+
+// PreviousSnapshot returns the per-VM snapshot of the collection before the
+// last one, or a ResourceNotFoundError if fewer than two collections ran.
+func (s *InventoryStore) PreviousSnapshot(ctx context.Context) (*models.InventorySnapshot, error) {
+	return s.snapshot(ctx, snapshotPrevious)
+}
+
+func (s *InventoryStore) snapshot(ctx context.Context, generation int) (*models.InventorySnapshot, error) {
+	query, args, err := sq.Select("taken_at").
+		From("inventory_snapshots").
+		Where(sq.Eq{"generation": generation}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var snap models.InventorySnapshot
+	err = s.db.QueryRowContext(ctx, query, args...).Scan(&snap.TakenAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, srvErrors.NewResourceNotFoundError("inventory snapshot", "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err = sq.Select("vm_id", "name", "cpus", "memory_mb", "disk_mb").
+		From("inventory_snapshot_vms").
+		Where(sq.Eq{"generation": generation}).
+		OrderBy("vm_id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	snap.VMs = []models.VMSnapshot{}
+	for rows.Next() {
+		var vm models.VMSnapshot
+		if err := rows.Scan(&vm.ID, &vm.Name, &vm.CPUs, &vm.MemoryMB, &vm.DiskMB); err != nil {
+			return nil, err
+		}
+		snap.VMs = append(snap.VMs, vm)
+	}
+	return &snap, rows.Err()
+}
@@ -3,10 +3,13 @@ package store_test
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
 	"github.com/kubev2v/assisted-migration-agent/internal/store"
 	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
@@ -122,4 +125,219 @@ var _ = Describe("InventoryStore", func() {
 			Expect(retrieved.UpdatedAt).NotTo(BeZero())
 		})
 	})
+
+	Describe("SaveWarnings", func() {
+		// Given no collection saved yet
+		// When we read the warnings
+		// Then the list should be empty
+		It("should return no warnings before the first collection", func() {
+			// Act
+			warnings, err := s.Inventory().Warnings(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		// Given the warnings of a first collection
+		// When a second collection saves its own
+		// Then only the second collection's warnings should be kept, in order
+		It("should replace the warnings of the previous collection", func() {
+			// Arrange
+			Expect(s.Inventory().SaveWarnings(ctx, []string{"old"})).To(Succeed())
+
+			// Act
+			err := s.Inventory().SaveWarnings(ctx, []string{"second", "first"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			warnings, err := s.Inventory().Warnings(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(Equal([]string{"second", "first"}))
+
+			Expect(s.Inventory().SaveWarnings(ctx, nil)).To(Succeed())
+			warnings, err = s.Inventory().Warnings(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		// Given warnings already saved in a transaction
+		// When the same transaction saves the warnings again
+		// Then the second list should replace the first one
+		It("should replace the warnings saved earlier in the same transaction", func() {
+			// Act
+			err := s.WithTx(ctx, func(txCtx context.Context) error {
+				if err := s.Inventory().SaveWarnings(txCtx, []string{"a", "b"}); err != nil {
+					return err
+				}
+				return s.Inventory().SaveWarnings(txCtx, []string{"c", "d"})
+			})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			warnings, err := s.Inventory().Warnings(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(Equal([]string{"c", "d"}))
+		})
+	})
+
+	Describe("AppendHistory", func() {
+		at := func(minute int) time.Time {
+			return time.Date(2026, 3, 1, 10, minute, 0, 0, time.UTC)
+		}
+
+		// Given no collection recorded yet
+		// When we list the history
+		// Then it should be empty
+		It("should return an empty history before the first collection", func() {
+			// Act
+			history, err := s.Inventory().History(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(BeEmpty())
+		})
+
+		// Given a retention of two previous inventories
+		// When four collections are recorded
+		// Then only the newest three should be kept, newest first
+		It("should prune inventories beyond the retention", func() {
+			// Act
+			for i := range 4 {
+				Expect(s.Inventory().AppendHistory(ctx, []byte(fmt.Sprintf(`{"collection":%d}`, i)), at(i), 2)).To(Succeed())
+			}
+
+			// Assert
+			history, err := s.Inventory().History(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(3))
+			Expect(history[0].Equal(at(3))).To(BeTrue())
+			Expect(history[1].Equal(at(2))).To(BeTrue())
+			Expect(history[2].Equal(at(1))).To(BeTrue())
+
+			_, err = s.Inventory().GetAt(ctx, at(0))
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+
+		// Given no previous inventories retained
+		// When two collections are recorded
+		// Then only the latest should be kept
+		It("should keep only the latest inventory with a retention of zero", func() {
+			// Act
+			Expect(s.Inventory().AppendHistory(ctx, []byte(`{"collection":0}`), at(0), 0)).To(Succeed())
+			Expect(s.Inventory().AppendHistory(ctx, []byte(`{"collection":1}`), at(1), 0)).To(Succeed())
+
+			// Assert
+			history, err := s.Inventory().History(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].Equal(at(1))).To(BeTrue())
+		})
+	})
+
+	Describe("GetAt", func() {
+		// Given two recorded collections
+		// When we fetch the older one by its timestamp
+		// Then its own data should be returned
+		It("should return the inventory collected at the given time", func() {
+			// Arrange
+			first := time.Date(2026, 3, 1, 10, 0, 0, 123456000, time.UTC)
+			second := first.Add(time.Hour)
+			Expect(s.Inventory().AppendHistory(ctx, []byte(`{"collection":1}`), first, 1)).To(Succeed())
+			Expect(s.Inventory().AppendHistory(ctx, []byte(`{"collection":2}`), second, 1)).To(Succeed())
+
+			// Act
+			inv, err := s.Inventory().GetAt(ctx, first)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(inv.Data)).To(Equal(`{"collection":1}`))
+			Expect(inv.CreatedAt.Equal(first)).To(BeTrue())
+		})
+
+		// Given a recorded collection
+		// When we fetch a timestamp no collection has
+		// Then it should return a ResourceNotFoundError
+		It("should return ResourceNotFoundError for an unknown timestamp", func() {
+			// Arrange
+			taken := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+			Expect(s.Inventory().AppendHistory(ctx, []byte(`{}`), taken, 1)).To(Succeed())
+
+			// Act
+			_, err := s.Inventory().GetAt(ctx, taken.Add(time.Second))
+
+			// Assert
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+	})
+
+	Describe("RotateSnapshot", func() {
+		rotate := func() {
+			Expect(s.WithTx(ctx, func(txCtx context.Context) error {
+				return s.Inventory().RotateSnapshot(txCtx)
+			})).To(Succeed())
+		}
+
+		// Given a single collection
+		// When the snapshot is rotated
+		// Then only the current snapshot should exist, with disks summed per VM
+		It("should record the current collection", func() {
+			// Arrange
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+
+			// Act
+			rotate()
+
+			// Assert
+			current, err := s.Inventory().CurrentSnapshot(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(current.TakenAt).NotTo(BeZero())
+			Expect(current.VMs).To(HaveLen(len(test.VMs)))
+			Expect(current.VMs[2]).To(Equal(models.VMSnapshot{
+				ID: "vm-003", Name: "db-server-1", CPUs: 8, MemoryMB: 16384, DiskMB: 1000,
+			}))
+
+			_, err = s.Inventory().PreviousSnapshot(ctx)
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+
+		// Given a second collection where a VM was resized
+		// When the snapshot is rotated again
+		// Then the first collection should become the previous snapshot
+		It("should keep the previous collection", func() {
+			// Arrange
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			rotate()
+			Expect(s.ClearInventory(ctx)).To(Succeed())
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			_, err := db.ExecContext(ctx, `UPDATE vinfo SET "CPUs" = 4 WHERE "VM ID" = 'vm-001'`)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			rotate()
+
+			// Assert
+			previous, err := s.Inventory().PreviousSnapshot(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			current, err := s.Inventory().CurrentSnapshot(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(previous.VMs).To(HaveLen(len(test.VMs)))
+			Expect(previous.VMs[0].CPUs).To(Equal(int32(2)))
+			Expect(current.VMs).To(HaveLen(len(test.VMs)))
+			Expect(current.VMs[0].CPUs).To(Equal(int32(4)))
+			Expect(current.TakenAt).To(BeTemporally(">=", previous.TakenAt))
+		})
+
+		// Given nothing was collected
+		// When the current snapshot is read
+		// Then it should return a ResourceNotFoundError
+		It("should return not found before the first collection", func() {
+			// Act
+			_, err := s.Inventory().CurrentSnapshot(ctx)
+
+			// Assert
+			Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,2 @@
+-- Persist the maintenance (read-only) flag so it survives agent restarts.
+ALTER TABLE configuration ADD COLUMN IF NOT EXISTS maintenance BOOLEAN DEFAULT false;
@@ -0,0 +1,9 @@
+-- Last vCenter credentials for scheduled recollection: single row. The
+-- password is stored as nonce || AES-GCM ciphertext, never in clear.
+CREATE TABLE IF NOT EXISTS credentials (
+    id INTEGER PRIMARY KEY DEFAULT 1,
+    url VARCHAR NOT NULL,
+    username VARCHAR NOT NULL,
+    password BLOB NOT NULL,
+    CHECK (id = 1)
+);
@@ -0,0 +1,17 @@
+-- Per-VM resources of the last two successful collections, used to diff a
+-- recollection against the one before it. Generation 0 is the current
+-- collection and 1 the previous one. There are no keys: rotation deletes and
+-- re-inserts rows in one transaction, which DuckDB rejects on indexed tables.
+CREATE TABLE IF NOT EXISTS inventory_snapshots (
+    generation INTEGER NOT NULL,
+    taken_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS inventory_snapshot_vms (
+    generation INTEGER NOT NULL,
+    vm_id VARCHAR NOT NULL,
+    name VARCHAR NOT NULL,
+    cpus INTEGER NOT NULL,
+    memory_mb INTEGER NOT NULL,
+    disk_mb BIGINT NOT NULL
+);
@@ -0,0 +1,8 @@
+-- Saved GET /vms queries, applied with ?view=<name>.
+CREATE TABLE IF NOT EXISTS vm_views (
+    name VARCHAR PRIMARY KEY,
+    expression VARCHAR NOT NULL DEFAULT '',
+    sort VARCHAR[] DEFAULT [],
+    page_size INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT now()
+);
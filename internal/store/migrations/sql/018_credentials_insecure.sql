@@ -0,0 +1,3 @@
+-- Whether the stored credentials were given with TLS verification disabled,
+-- so a scheduled recollection connects the same way.
+ALTER TABLE credentials ADD COLUMN IF NOT EXISTS insecure BOOLEAN DEFAULT false;
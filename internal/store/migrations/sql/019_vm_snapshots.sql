@@ -0,0 +1,8 @@
+-- VM snapshots read from vCenter at collection time. The forklift model keeps
+-- only the current snapshot, so the trees are collected separately.
+CREATE TABLE IF NOT EXISTS vsnapshots (
+    "VM ID" VARCHAR NOT NULL,
+    "Name" VARCHAR NOT NULL,
+    "Created" TIMESTAMP,
+    FOREIGN KEY ("VM ID") REFERENCES vinfo("VM ID")
+);
@@ -0,0 +1,7 @@
+-- VM creation dates read from vCenter at collection time. The forklift model
+-- does not keep config.createDate, so it is collected separately.
+CREATE TABLE IF NOT EXISTS vcreation (
+    "VM ID" VARCHAR NOT NULL,
+    "Created" TIMESTAMP NOT NULL,
+    FOREIGN KEY ("VM ID") REFERENCES vinfo("VM ID")
+);
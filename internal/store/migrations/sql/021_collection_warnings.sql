@@ -0,0 +1,6 @@
+-- Non-fatal issues hit by the last successful collection (e.g. a VM that
+-- could not be validated), replaced whenever a collection is saved.
+CREATE TABLE IF NOT EXISTS collection_warnings (
+    position INTEGER NOT NULL PRIMARY KEY,
+    message VARCHAR NOT NULL
+);
@@ -0,0 +1,10 @@
+-- Inventories of the last collections, newest kept as the current one and
+-- pruned to the configured retention on every collection. The inventory
+-- already stored seeds the history.
+CREATE TABLE IF NOT EXISTS inventory_history (
+    taken_at TIMESTAMP NOT NULL PRIMARY KEY,
+    data BLOB NOT NULL
+);
+
+INSERT INTO inventory_history (taken_at, data)
+SELECT updated_at, data FROM inventory WHERE id = 1;
@@ -0,0 +1,8 @@
+-- State of the collection in flight, updated as it moves through its phases
+-- and removed when it ends or is stopped. A row still present at startup is a
+-- collection interrupted by a restart.
+CREATE TABLE IF NOT EXISTS collector_state (
+    id INTEGER NOT NULL PRIMARY KEY,
+    state VARCHAR NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -0,0 +1,4 @@
+-- Console settings changed at runtime with PATCH /agent/config. NULL keeps the
+-- value the agent was started with.
+ALTER TABLE configuration ADD COLUMN IF NOT EXISTS update_interval_ns BIGINT;
+ALTER TABLE configuration ADD COLUMN IF NOT EXISTS legacy_status_enabled BOOLEAN;
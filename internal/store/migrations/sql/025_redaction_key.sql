@@ -0,0 +1,4 @@
+-- Per-agent key the inventory fields listed in --push-redacted-fields are
+-- HMAC'd with, generated on first use so redacted values stay stable across
+-- pushes and restarts.
+ALTER TABLE configuration ADD COLUMN IF NOT EXISTS redaction_key VARCHAR;
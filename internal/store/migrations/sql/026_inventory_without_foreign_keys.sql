@@ -0,0 +1,114 @@
+-- Rebuild the tables keyed on vinfo without their foreign keys. DuckDB rejects
+-- deleting a vinfo row in the transaction that deleted the rows referencing
+-- it, so with the keys in place a collection could not replace the inventory
+-- in one transaction (see Store.ReplaceInventory). Ingestion and the swap keep
+-- the tables consistent with vinfo.
+
+CREATE TABLE vcpu_rebuilt (
+    "VM ID" VARCHAR,
+    "Hot Add" BOOLEAN DEFAULT false,
+    "Hot Remove" BOOLEAN DEFAULT false,
+    "Sockets" INTEGER DEFAULT 0,
+    "Cores p/s" INTEGER DEFAULT 0
+);
+INSERT INTO vcpu_rebuilt BY NAME SELECT * FROM vcpu;
+DROP TABLE vcpu;
+ALTER TABLE vcpu_rebuilt RENAME TO vcpu;
+
+CREATE TABLE vmemory_rebuilt (
+    "VM ID" VARCHAR,
+    "Hot Add" BOOLEAN DEFAULT false,
+    "Ballooned" INTEGER DEFAULT 0
+);
+INSERT INTO vmemory_rebuilt BY NAME SELECT * FROM vmemory;
+DROP TABLE vmemory;
+ALTER TABLE vmemory_rebuilt RENAME TO vmemory;
+
+CREATE TABLE vdisk_rebuilt (
+    "VM ID" VARCHAR,
+    "Disk Key" VARCHAR,
+    "Unit #" VARCHAR,
+    "Path" VARCHAR,
+    "Disk Path" VARCHAR,
+    "Capacity MiB" BIGINT DEFAULT 0,
+    "Sharing mode" BOOLEAN DEFAULT false,
+    "Raw" BOOLEAN DEFAULT false,
+    "Shared Bus" VARCHAR,
+    "Disk Mode" VARCHAR,
+    "Disk UUID" VARCHAR,
+    "Thin" BOOLEAN DEFAULT false,
+    "Controller" VARCHAR,
+    "Label" VARCHAR,
+    "SCSI Unit #" VARCHAR
+);
+INSERT INTO vdisk_rebuilt BY NAME SELECT * FROM vdisk;
+DROP TABLE vdisk;
+ALTER TABLE vdisk_rebuilt RENAME TO vdisk;
+
+CREATE TABLE vnetwork_rebuilt (
+    "VM ID" VARCHAR,
+    "Network" VARCHAR,
+    "Mac Address" VARCHAR,
+    "NIC label" VARCHAR,
+    "Adapter" VARCHAR,
+    "Switch" VARCHAR,
+    "Connected" BOOLEAN DEFAULT false,
+    "Starts Connected" BOOLEAN DEFAULT false,
+    "Type" VARCHAR,
+    "IPv4 Address" VARCHAR,
+    "IPv6 Address" VARCHAR,
+    "Cluster" VARCHAR
+);
+INSERT INTO vnetwork_rebuilt BY NAME SELECT * FROM vnetwork;
+DROP TABLE vnetwork;
+ALTER TABLE vnetwork_rebuilt RENAME TO vnetwork;
+
+CREATE TABLE concerns_rebuilt (
+    "VM_ID" VARCHAR,
+    "Concern_ID" VARCHAR,
+    "Label" VARCHAR,
+    "Category" VARCHAR,
+    "Assessment" VARCHAR
+);
+INSERT INTO concerns_rebuilt BY NAME SELECT * FROM concerns;
+DROP TABLE concerns;
+ALTER TABLE concerns_rebuilt RENAME TO concerns;
+
+CREATE TABLE vm_inspection_status_rebuilt (
+    "VM ID" VARCHAR PRIMARY KEY,
+    status VARCHAR NOT NULL,
+    error VARCHAR,
+    sequence INTEGER DEFAULT nextval('vm_inspection_status_seq')
+);
+INSERT INTO vm_inspection_status_rebuilt BY NAME SELECT * FROM vm_inspection_status;
+DROP TABLE vm_inspection_status;
+ALTER TABLE vm_inspection_status_rebuilt RENAME TO vm_inspection_status;
+
+CREATE TABLE vm_inspection_concerns_rebuilt (
+    id INTEGER PRIMARY KEY DEFAULT nextval('vm_inspection_concerns_seq'),
+    "VM ID" VARCHAR NOT NULL,
+    inspection_id INTEGER NOT NULL,
+    category VARCHAR,
+    label VARCHAR,
+    msg VARCHAR
+);
+INSERT INTO vm_inspection_concerns_rebuilt BY NAME SELECT * FROM vm_inspection_concerns;
+DROP TABLE vm_inspection_concerns;
+ALTER TABLE vm_inspection_concerns_rebuilt RENAME TO vm_inspection_concerns;
+
+CREATE TABLE vsnapshots_rebuilt (
+    "VM ID" VARCHAR NOT NULL,
+    "Name" VARCHAR NOT NULL,
+    "Created" TIMESTAMP
+);
+INSERT INTO vsnapshots_rebuilt BY NAME SELECT * FROM vsnapshots;
+DROP TABLE vsnapshots;
+ALTER TABLE vsnapshots_rebuilt RENAME TO vsnapshots;
+
+CREATE TABLE vcreation_rebuilt (
+    "VM ID" VARCHAR NOT NULL,
+    "Created" TIMESTAMP NOT NULL
+);
+INSERT INTO vcreation_rebuilt BY NAME SELECT * FROM vcreation;
+DROP TABLE vcreation;
+ALTER TABLE vcreation_rebuilt RENAME TO vcreation;
@@ -3,6 +3,11 @@ package store
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/kubev2v/migration-planner/pkg/duckdb_parser"
 
@@ -12,6 +17,7 @@ import (
 type Store struct {
 	db            *sql.DB
 	parser        *duckdb_parser.Parser
+	validator     duckdb_parser.Validator
 	configuration *ConfigurationStore
 	inventory     *InventoryStore
 	vm            *VMStore
@@ -21,6 +27,9 @@ type Store struct {
 	outbox        *OutboxStore
 	rightsizing   *RightSizingStore
 	forecast      *ForecastStore
+	credentials   *CredentialsStore
+	collector     *CollectorStateStore
+	view          *ViewStore
 	transactor    *DBTransactor
 }
 
@@ -30,6 +39,7 @@ func NewStore(db *sql.DB, validator duckdb_parser.Validator) *Store {
 	return &Store{
 		db:            db,
 		parser:        parser,
+		validator:     validator,
 		configuration: NewConfigurationStore(qi),
 		inventory:     NewInventoryStore(qi),
 		vm:            NewVMStore(qi, parser),
@@ -39,6 +49,9 @@ func NewStore(db *sql.DB, validator duckdb_parser.Validator) *Store {
 		outbox:        NewOutboxStore(qi),
 		rightsizing:   NewRightSizingStore(qi),
 		forecast:      NewForecastStore(qi),
+		credentials:   NewCredentialsStore(qi),
+		collector:     NewCollectorStateStore(qi),
+		view:          NewViewStore(qi),
 		transactor:    newTransactor(db),
 	}
 }
@@ -59,6 +72,11 @@ func (s *Store) Parser() *duckdb_parser.Parser {
 	return s.parser
 }
 
+// Validator returns the validator ingestion computes the VM concerns with.
+func (s *Store) Validator() duckdb_parser.Validator {
+	return s.validator
+}
+
 func (s *Store) Configuration() *ConfigurationStore {
 	return s.configuration
 }
@@ -95,6 +113,18 @@ func (s *Store) Forecast() *ForecastStore {
 	return s.forecast
 }
 
+func (s *Store) Credentials() *CredentialsStore {
+	return s.credentials
+}
+
+func (s *Store) CollectorState() *CollectorStateStore {
+	return s.collector
+}
+
+func (s *Store) Views() *ViewStore {
+	return s.view
+}
+
 func (s *Store) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
 	return s.transactor.WithTx(ctx, fn)
 }
@@ -105,6 +135,203 @@ func (s *Store) Checkpoint() error {
 	return err
 }
 
+// collectedTables lists the tables rebuilt by a collection, referencing
+// tables before the vinfo rows they point to.
+var collectedTables = []string{
+	"vm_inspection_concerns",
+	"vm_inspection_status",
+	"group_matches",
+	"concerns",
+	"vcpu",
+	"vmemory",
+	"vdisk",
+	"vnetwork",
+	"vhost",
+	"vdatastore",
+	"vhba",
+	"dvport",
+	"dvswitch",
+	"vcluster",
+	"vsnapshots",
+	"vcreation",
+	"vinfo",
+}
+
+// inspectionTables lists the collected tables holding inspection results,
+// which a new collection keeps for the VMs it still finds.
+var inspectionTables = []string{
+	"vm_inspection_concerns",
+	"vm_inspection_status",
+}
+
+// stagingAlias is the name ReplaceInventory attaches the staging database as.
+const stagingAlias = "inventory_staging"
+
+// ClearInventory deletes the parsed inventory and everything keyed on its
+// VMs (inspection results, group matches) so a new collection can be ingested.
+// Groups and the last inventory snapshot are kept. The deletes run one by one
+// outside a transaction because DuckDB rejects deleting a referenced vinfo row
+// in the same transaction as the rows referencing it. Must not be called
+// inside a transaction.
+func (s *Store) ClearInventory(ctx context.Context) error {
+	for _, table := range collectedTables {
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", table)); err != nil {
+			return fmt.Errorf("clearing %s: %w", table, err)
+		}
+	}
+	return s.Checkpoint()
+}
+
+// ReplaceInventory ingests a new inventory into an empty staging database and,
+// only once ingest returns without error, replaces the collected tables with
+// the staged ones. A failed ingest leaves the current inventory, inspection
+// results and group matches untouched.
+//
+// ingest gets a Store over the staging database, with the same schema and
+// validator. The staging database is a file next to this one (in the temp
+// folder for an in-memory store), removed when done. The swap deletes and
+// copies every collected table in one transaction, keeping the inspection
+// results of VMs still present; a copy failure rolls back to the previous
+// inventory. Must not be called inside a transaction.
+func (s *Store) ReplaceInventory(ctx context.Context, ingest func(ctx context.Context, staged *Store) error) error {
+	var dbPath, extDir sql.NullString
+	if err := s.db.QueryRowContext(ctx, "SELECT path FROM duckdb_databases() WHERE database_name = current_database()").Scan(&dbPath); err != nil {
+		return fmt.Errorf("resolving database path: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, "SELECT current_setting('extension_directory')").Scan(&extDir); err != nil {
+		return fmt.Errorf("resolving extension directory: %w", err)
+	}
+
+	parent := os.TempDir()
+	if dbPath.Valid && dbPath.String != "" {
+		parent = filepath.Dir(dbPath.String)
+	}
+	dir, err := os.MkdirTemp(parent, "inventory-staging-")
+	if err != nil {
+		return fmt.Errorf("creating staging folder: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	stagingPath := filepath.Join(dir, DatabaseFile)
+
+	if err := s.stage(ctx, stagingPath, extDir.String, ingest); err != nil {
+		return err
+	}
+
+	return s.swapInventory(ctx, stagingPath)
+}
+
+// stage creates the staging database at path and runs ingest against it. The
+// staging database loads extensions from extDir, like this one, so ingestion
+// finds the bundled sqlite_scanner without downloading it.
+func (s *Store) stage(ctx context.Context, path, extDir string, ingest func(ctx context.Context, staged *Store) error) error {
+	db, err := sql.Open("duckdb", path)
+	if err != nil {
+		return fmt.Errorf("opening staging database: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	db.SetMaxOpenConns(1)
+
+	if extDir != "" {
+		if _, err := db.ExecContext(ctx, "SET extension_directory = "+quoteLiteral(extDir)); err != nil {
+			return fmt.Errorf("setting staging extension directory: %w", err)
+		}
+	}
+
+	staged := NewStore(db, s.validator)
+	if err := staged.Migrate(ctx); err != nil {
+		return fmt.Errorf("creating staging schema: %w", err)
+	}
+
+	if err := ingest(ctx, staged); err != nil {
+		return err
+	}
+
+	return staged.Checkpoint()
+}
+
+// swapInventory replaces the collected tables with those of the staging
+// database at path. The deletes and copies run in one transaction, so readers
+// and a failed or interrupted swap keep the previous inventory. Inspection
+// results of VMs that are still in the staged inventory are carried over.
+func (s *Store) swapInventory(ctx context.Context, path string) error {
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf("ATTACH %s AS %s (READ_ONLY)", quoteLiteral(path), stagingAlias)); err != nil {
+		return fmt.Errorf("attaching staging database: %w", err)
+	}
+	defer func() {
+		_, _ = s.db.ExecContext(context.Background(), "DETACH "+stagingAlias)
+	}()
+
+	err := s.WithTx(ctx, func(txCtx context.Context) error {
+		qi := newQueryInterceptor(s.db)
+		for _, table := range inspectionTables {
+			query := fmt.Sprintf(`CREATE OR REPLACE TEMP TABLE carried_%s AS SELECT * FROM %s WHERE "VM ID" IN (SELECT "VM ID" FROM %s.vinfo)`, table, table, stagingAlias)
+			if _, err := qi.ExecContext(txCtx, query); err != nil {
+				return fmt.Errorf("keeping %s: %w", table, err)
+			}
+		}
+		for _, table := range collectedTables {
+			if _, err := qi.ExecContext(txCtx, fmt.Sprintf("DELETE FROM %s", table)); err != nil {
+				return fmt.Errorf("clearing %s: %w", table, err)
+			}
+		}
+		// Referenced tables go first, the reverse of the delete order.
+		for _, table := range slices.Backward(collectedTables) {
+			source := fmt.Sprintf("%s.%s", stagingAlias, table)
+			if slices.Contains(inspectionTables, table) {
+				source = "carried_" + table
+			}
+			query := fmt.Sprintf("INSERT INTO %s BY NAME SELECT * FROM %s", table, source)
+			if _, err := qi.ExecContext(txCtx, query); err != nil {
+				return fmt.Errorf("copying %s: %w", table, err)
+			}
+		}
+		for _, table := range inspectionTables {
+			if _, err := qi.ExecContext(txCtx, "DROP TABLE carried_"+table); err != nil {
+				return fmt.Errorf("dropping carried %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.Checkpoint()
+}
+
+// quoteLiteral quotes value as an SQL string literal, for statements such as
+// ATTACH and SET that take no parameters.
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// Compact refreshes table statistics and forces a checkpoint so that row
+// groups emptied by deletes are merged and their blocks reused. DuckDB has no
+// VACUUM FULL; the file is not truncated, but space freed by clearing and
+// recollecting inventory is reclaimed for subsequent writes. It is a no-op for
+// an in-memory database. Must not be called inside a transaction.
+func (s *Store) Compact(ctx context.Context) error {
+	var path sql.NullString
+	if err := s.db.QueryRowContext(ctx, "SELECT path FROM duckdb_databases() WHERE database_name = current_database()").Scan(&path); err != nil {
+		return fmt.Errorf("resolving database path: %w", err)
+	}
+	if !path.Valid || path.String == "" {
+		return nil
+	}
+
+	if _, err := s.db.ExecContext(ctx, "VACUUM ANALYZE"); err != nil {
+		return fmt.Errorf("vacuum: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, "FORCE CHECKPOINT"); err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+	return nil
+}
+
 func (s *Store) Close() error {
 	return s.db.Close()
 }
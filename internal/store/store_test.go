@@ -0,0 +1,294 @@
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		ctx context.Context
+		s   *store.Store
+		db  *sql.DB
+	)
+
+	open := func(path string) {
+		var err error
+		db, err = store.NewDB(nil, path)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = migrations.Run(ctx, db)
+		Expect(err).NotTo(HaveOccurred())
+
+		s = store.NewStore(db, test.NewMockValidator())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	Describe("Compact", func() {
+		// Given a file-backed store that was populated and then cleared
+		// When we compact it
+		// Then it should remain queryable, empty and writable
+		It("should leave a cleared store queryable and empty", func() {
+			// Arrange
+			open(filepath.Join(GinkgoT().TempDir(), store.DatabaseFile))
+
+			ids := make([]string, 0, 200)
+			for i := range 200 {
+				id := fmt.Sprintf("vm-%d", i)
+				_, err := db.ExecContext(ctx, `
+					INSERT INTO vinfo ("VM ID", "VM", "Powerstate", "Cluster", "Memory", "Template")
+					VALUES (?, ?, 'poweredOn', 'cluster-a', 4096, false)
+				`, id, id)
+				Expect(err).NotTo(HaveOccurred())
+				ids = append(ids, id)
+			}
+			Expect(s.Inspection().Add(ctx, ids, models.InspectionStatePending)).To(Succeed())
+
+			Expect(s.Inspection().DeleteAll(ctx)).To(Succeed())
+			_, err := db.ExecContext(ctx, `DELETE FROM vinfo`)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			err = s.Compact(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+
+			statuses, err := s.Inspection().List(ctx, store.NewInspectionQueryFilter())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(BeEmpty())
+
+			count, err := s.VM().Count(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+
+			_, err = db.ExecContext(ctx, `
+				INSERT INTO vinfo ("VM ID", "VM", "Powerstate", "Cluster", "Memory", "Template")
+				VALUES ('vm-new', 'vm-new', 'poweredOn', 'cluster-a', 4096, false)
+			`)
+			Expect(err).NotTo(HaveOccurred())
+			count, err = s.VM().Count(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+		})
+
+		// Given an in-memory store
+		// When we compact it
+		// Then it should do nothing and return no error
+		It("should be a no-op for an in-memory store", func() {
+			// Arrange
+			open(":memory:")
+
+			// Act
+			err := s.Compact(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("ClearInventory", func() {
+		// Given a collected inventory with inspection results, groups and a snapshot
+		// When we clear the inventory
+		// Then VM-keyed data should be gone while groups and the snapshot remain
+		It("should clear collected data and keep groups and the snapshot", func() {
+			// Arrange
+			open(":memory:")
+			for _, id := range []string{"vm-1", "vm-2"} {
+				_, err := db.ExecContext(ctx, `
+					INSERT INTO vinfo ("VM ID", "VM", "Powerstate", "Cluster", "Memory", "Template", "Folder")
+					VALUES (?, ?, 'poweredOn', 'cluster-a', 4096, false, 'prod')
+				`, id, id)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(s.Inspection().Add(ctx, []string{"vm-1"}, models.InspectionStatePending)).To(Succeed())
+			group, err := s.Group().Create(ctx, models.Group{Name: "prod", Filter: "folder = 'prod'"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Group().RefreshMatches(ctx)).To(Succeed())
+			Expect(s.Inventory().Save(ctx, []byte(`{"vms":[]}`))).To(Succeed())
+
+			// Act
+			err = s.ClearInventory(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+
+			count, err := s.VM().Count(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+
+			statuses, err := s.Inspection().List(ctx, store.NewInspectionQueryFilter())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(BeEmpty())
+
+			matched, err := s.Group().GetMatchedIDs(ctx, group.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matched).To(BeEmpty())
+
+			_, err = s.Group().Get(ctx, group.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = s.Inventory().Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = db.ExecContext(ctx, `
+				INSERT INTO vinfo ("VM ID", "VM", "Powerstate", "Cluster", "Memory", "Template")
+				VALUES ('vm-1', 'vm-1', 'poweredOn', 'cluster-a', 4096, false)
+			`)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("ReplaceInventory", func() {
+		insertVM := func(db *sql.DB, id string) {
+			_, err := db.ExecContext(ctx, `
+				INSERT INTO vinfo ("VM ID", "VM", "Powerstate", "Cluster", "Memory", "Template", "Folder")
+				VALUES (?, ?, 'poweredOn', 'cluster-a', 4096, false, 'prod')
+			`, id, id)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		vmIDs := func() []string {
+			rows, err := db.QueryContext(ctx, `SELECT "VM ID" FROM vinfo ORDER BY "VM ID"`)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = rows.Close() }()
+			var ids []string
+			for rows.Next() {
+				var id string
+				Expect(rows.Scan(&id)).To(Succeed())
+				ids = append(ids, id)
+			}
+			Expect(rows.Err()).NotTo(HaveOccurred())
+			return ids
+		}
+
+		// Given a collected inventory with inspection results and group matches
+		// When the ingest of a new inventory fails halfway
+		// Then the previous VMs, inspection results and group matches should remain
+		It("should keep the current inventory when the ingest fails", func() {
+			// Arrange
+			open(filepath.Join(GinkgoT().TempDir(), store.DatabaseFile))
+			insertVM(db, "vm-1")
+			Expect(s.Inspection().Add(ctx, []string{"vm-1"}, models.InspectionStatePending)).To(Succeed())
+			group, err := s.Group().Create(ctx, models.Group{Name: "prod", Filter: "folder = 'prod'"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Group().RefreshMatches(ctx)).To(Succeed())
+
+			// Act
+			err = s.ReplaceInventory(ctx, func(ctx context.Context, staged *store.Store) error {
+				insertVM(staged.DB(), "vm-2")
+				return errors.New("schema validation failed")
+			})
+
+			// Assert
+			Expect(err).To(MatchError("schema validation failed"))
+			Expect(vmIDs()).To(Equal([]string{"vm-1"}))
+
+			statuses, err := s.Inspection().List(ctx, store.NewInspectionQueryFilter())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveKey("vm-1"))
+
+			matched, err := s.Group().GetMatchedIDs(ctx, group.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matched).To(ConsistOf("vm-1"))
+		})
+
+		// Given a collected inventory with inspection results
+		// When a new inventory is ingested successfully
+		// Then only the new VMs should remain and the inspection results should be gone
+		It("should replace the inventory with the staged one", func() {
+			// Arrange
+			open(":memory:")
+			insertVM(db, "vm-1")
+			Expect(s.Inspection().Add(ctx, []string{"vm-1"}, models.InspectionStatePending)).To(Succeed())
+
+			// Act
+			err := s.ReplaceInventory(ctx, func(ctx context.Context, staged *store.Store) error {
+				insertVM(staged.DB(), "vm-2")
+				insertVM(staged.DB(), "vm-3")
+				return staged.VM().InsertCreateDates(ctx, map[string]time.Time{"vm-2": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)})
+			})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs()).To(Equal([]string{"vm-2", "vm-3"}))
+
+			statuses, err := s.Inspection().List(ctx, store.NewInspectionQueryFilter())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(BeEmpty())
+
+			var created int
+			Expect(db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vcreation`).Scan(&created)).To(Succeed())
+			Expect(created).To(Equal(1))
+		})
+
+		// Given inspection results for a VM that the new inventory still holds
+		// When a new inventory is ingested successfully
+		// Then that VM's inspection results should be kept
+		It("should keep the inspection results of VMs still collected", func() {
+			// Arrange
+			open(":memory:")
+			insertVM(db, "vm-1")
+			insertVM(db, "vm-2")
+			Expect(s.Inspection().Add(ctx, []string{"vm-1", "vm-2"}, models.InspectionStatePending)).To(Succeed())
+
+			// Act
+			err := s.ReplaceInventory(ctx, func(ctx context.Context, staged *store.Store) error {
+				insertVM(staged.DB(), "vm-2")
+				return nil
+			})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs()).To(Equal([]string{"vm-2"}))
+
+			statuses, err := s.Inspection().List(ctx, store.NewInspectionQueryFilter())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveLen(1))
+			Expect(statuses).To(HaveKey("vm-2"))
+		})
+
+		// Given a database in a folder whose name holds a quote
+		// When a new inventory is ingested successfully
+		// Then the staging database should be attached and swapped in
+		It("should replace the inventory when the folder name holds a quote", func() {
+			// Arrange
+			dir := filepath.Join(GinkgoT().TempDir(), "o'brien")
+			Expect(os.Mkdir(dir, 0o755)).To(Succeed())
+			open(filepath.Join(dir, store.DatabaseFile))
+			insertVM(db, "vm-1")
+
+			// Act
+			err := s.ReplaceInventory(ctx, func(ctx context.Context, staged *store.Store) error {
+				insertVM(staged.DB(), "vm-2")
+				return nil
+			})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs()).To(Equal([]string{"vm-2"}))
+		})
+	})
+})
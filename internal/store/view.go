@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+)
+
+const (
+	viewTable         = "vm_views"
+	viewColName       = "name"
+	viewColExpression = "expression"
+	viewColSort       = "sort"
+	viewColPageSize   = "page_size"
+	viewColCreatedAt  = "created_at"
+)
+
+var viewSelectStm = sq.Select(
+	viewColName,
+	viewColExpression,
+	viewColSort,
+	viewColPageSize,
+	viewColCreatedAt).
+	From(viewTable)
+
+// ViewStore persists saved GET /vms views, keyed by name.
+type ViewStore struct {
+	db QueryInterceptor
+}
+
+func NewViewStore(db QueryInterceptor) *ViewStore {
+	return &ViewStore{db: db}
+}
+
+// Create inserts a new view. Returns a DuplicateResourceError if a view with
+// the same name exists.
+func (s *ViewStore) Create(ctx context.Context, view models.VMView) (*models.VMView, error) {
+	sort := view.Sort
+	if sort == nil {
+		sort = []string{}
+	}
+
+	query, args, err := sq.Insert(viewTable).
+		Columns(viewColName, viewColExpression, viewColSort, viewColPageSize).
+		Values(view.Name, view.Expression, sort, view.PageSize).
+		Suffix(fmt.Sprintf("RETURNING %s, %s, %s, %s, %s",
+			viewColName, viewColExpression, viewColSort, viewColPageSize, viewColCreatedAt)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building create query: %w", err)
+	}
+
+	created, err := scanView(s.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return nil, srvErrors.NewDuplicateResourceError("view", "name", view.Name)
+		}
+		return nil, fmt.Errorf("creating view: %w", err)
+	}
+	return created, nil
+}
+
+// List returns all views ordered by name.
+func (s *ViewStore) List(ctx context.Context) ([]models.VMView, error) {
+	query, args, err := viewSelectStm.OrderBy(viewColName + " ASC").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building list query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("executing list query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	views := []models.VMView{}
+	for rows.Next() {
+		v, err := scanView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning view row: %w", err)
+		}
+		views = append(views, *v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating view rows: %w", err)
+	}
+
+	return views, nil
+}
+
+// Get returns a view by name, or a ResourceNotFoundError.
+func (s *ViewStore) Get(ctx context.Context, name string) (*models.VMView, error) {
+	query, args, err := viewSelectStm.Where(sq.Eq{viewColName: name}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building get query: %w", err)
+	}
+
+	v, err := scanView(s.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, srvErrors.NewResourceNotFoundError("view", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning view: %w", err)
+	}
+	return v, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanView(row rowScanner) (*models.VMView, error) {
+	var v models.VMView
+	var sort StringArray
+	if err := row.Scan(&v.Name, &v.Expression, &sort, &v.PageSize, &v.CreatedAt); err != nil {
+		return nil, err
+	}
+	v.Sort = sort
+	return &v, nil
+}
@@ -0,0 +1,131 @@
+package store_test
+
+import (
+	"context"
+	"database/sql"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	"github.com/kubev2v/assisted-migration-agent/internal/store"
+	"github.com/kubev2v/assisted-migration-agent/internal/store/migrations"
+	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
+	"github.com/kubev2v/assisted-migration-agent/test"
+)
+
+var _ = Describe("ViewStore", func() {
+	var (
+		ctx context.Context
+		s   *store.Store
+		db  *sql.DB
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		db, err = store.NewDB(nil, ":memory:")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = migrations.Run(ctx, db)
+		Expect(err).NotTo(HaveOccurred())
+
+		s = store.NewStore(db, test.NewMockValidator())
+	})
+
+	AfterEach(func() {
+		if db != nil {
+			_ = db.Close()
+		}
+	})
+
+	// Given a view definition
+	// When it is created and read back
+	// Then all fields should round-trip
+	It("should create and get a view", func() {
+		// Arrange
+		view := models.VMView{
+			Name:       "big-prod",
+			Expression: "cluster = 'production'",
+			Sort:       []string{"memory:desc", "name:asc"},
+			PageSize:   50,
+		}
+
+		// Act
+		created, err := s.Views().Create(ctx, view)
+		Expect(err).NotTo(HaveOccurred())
+		got, err := s.Views().Get(ctx, "big-prod")
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.CreatedAt).NotTo(BeZero())
+		Expect(got.Name).To(Equal(view.Name))
+		Expect(got.Expression).To(Equal(view.Expression))
+		Expect(got.Sort).To(Equal(view.Sort))
+		Expect(got.PageSize).To(Equal(50))
+	})
+
+	// Given a view with only a name
+	// When it is read back
+	// Then the unset fields should be empty
+	It("should store a view without filter, sort or page size", func() {
+		// Act
+		_, err := s.Views().Create(ctx, models.VMView{Name: "all"})
+		Expect(err).NotTo(HaveOccurred())
+		got, err := s.Views().Get(ctx, "all")
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got.Expression).To(BeEmpty())
+		Expect(got.Sort).To(BeEmpty())
+		Expect(got.PageSize).To(BeZero())
+	})
+
+	// Given an existing view
+	// When another view with the same name is created
+	// Then it should return a DuplicateResourceError
+	It("should reject a duplicate name", func() {
+		// Arrange
+		_, err := s.Views().Create(ctx, models.VMView{Name: "dup"})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Act
+		_, err = s.Views().Create(ctx, models.VMView{Name: "dup", PageSize: 10})
+
+		// Assert
+		Expect(srvErrors.IsDuplicateResourceError(err)).To(BeTrue())
+	})
+
+	// Given several views
+	// When they are listed
+	// Then they should be ordered by name
+	It("should list views by name", func() {
+		// Arrange
+		for _, name := range []string{"zeta", "alpha", "mid"} {
+			_, err := s.Views().Create(ctx, models.VMView{Name: name})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		// Act
+		views, err := s.Views().List(ctx)
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(views).To(HaveLen(3))
+		Expect(views[0].Name).To(Equal("alpha"))
+		Expect(views[1].Name).To(Equal("mid"))
+		Expect(views[2].Name).To(Equal("zeta"))
+	})
+
+	// Given no views
+	// When a view is read by name
+	// Then it should return a ResourceNotFoundError
+	It("should return not found for an unknown view", func() {
+		// Act
+		_, err := s.Views().Get(ctx, "missing")
+
+		// Assert
+		Expect(srvErrors.IsResourceNotFoundError(err)).To(BeTrue())
+	})
+})
@@ -2,9 +2,13 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/kubev2v/migration-planner/pkg/duckdb_parser"
@@ -16,50 +20,56 @@ import (
 	"github.com/kubev2v/assisted-migration-agent/pkg/filter"
 )
 
+const (
+	vmSnapshotsTable = "vsnapshots"
+	vmCreationTable  = "vcreation"
+)
+
+// The concern raised for VMs with snapshots matches the forklift
+// vmware.snapshot.detected policy, so OPA and the agent never report it twice.
+const (
+	snapshotConcernID         = "vmware.snapshot.detected"
+	snapshotConcernLabel      = "VM snapshot detected"
+	snapshotConcernAssessment = "Online snapshots are not currently supported by OpenShift Virtualization. VM will be migrated with current snapshot."
+)
+
 type VMStore struct {
-	db     QueryInterceptor
-	parser *duckdb_parser.Parser
+	db         QueryInterceptor
+	parser     *duckdb_parser.Parser
+	suppressed []string
 }
 
 func NewVMStore(db QueryInterceptor, parser *duckdb_parser.Parser) *VMStore {
 	return &VMStore{db: db, parser: parser}
 }
 
+// WithoutConcerns returns a view of the store whose List, Count,
+// OsDistribution and GroupBy ignore the concerns with the given IDs: they count neither
+// towards issue counts nor migratability, and filters do not see them. Get and
+// GetMany still return every concern.
+func (s *VMStore) WithoutConcerns(ids []string) *VMStore {
+	view := *s
+	view.suppressed = ids
+	return &view
+}
+
+// withSuppression shadows the parser's concerns table with a copy that leaves
+// out the suppressed concerns, so every join and subquery in builder, including
+// the filter subquery, reads the filtered rows.
+func (s *VMStore) withSuppression(builder sq.SelectBuilder) sq.SelectBuilder {
+	if len(s.suppressed) == 0 {
+		return builder
+	}
+	kept := sq.Select("*").From("concerns").Where(sq.NotEq{`"Concern_ID"`: s.suppressed})
+	return builder.PrefixExpr(sq.ConcatExpr("WITH concerns AS (", kept, ")"))
+}
+
 // FilterOption is a SQL WHERE condition for filtering VMs in the flat filter subquery.
 type FilterOption = sq.Sqlizer
 
 // List returns VM summaries with filters, sorting, and pagination.
 func (s *VMStore) List(ctx context.Context, filters []sq.Sqlizer, opts ...ListOption) ([]models.VirtualMachineSummary, error) {
-	builder := vmOutputQuery.
-		Columns(
-			`u.cpu_p95_pct AS cpu_p95_pct`,
-			`u.mem_p95_pct AS mem_p95_pct`,
-			`u.disk_pct    AS disk_pct`,
-			`u.confidence_pct AS confidence_pct`,
-		).
-		LeftJoin(`rightsizing_vm_utilization u ON u.moid = v."VM ID" AND u.report_id = (` +
-			`SELECT id FROM rightsizing_reports WHERE written_batch_count > 0 ORDER BY created_at DESC LIMIT 1` +
-			`)`)
-
-	// Apply external filters via subquery (filters reference table aliases in vmFilterSubquery)
-	if len(filters) > 0 {
-		subquery := vmFilterSubquery
-		for _, f := range filters {
-			subquery = subquery.Where(f)
-		}
-		subSQL, subArgs, err := subquery.ToSql()
-		if err != nil {
-			return nil, err
-		}
-		builder = builder.Where(sq.Expr(fmt.Sprintf(`v."VM ID" IN (%s)`, subSQL), subArgs...))
-	}
-
-	// Apply options (sort, limit, offset)
-	for _, opt := range opts {
-		builder = opt(builder)
-	}
-
-	query, args, err := builder.ToSql()
+	query, args, err := s.listQuery(filters, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -87,12 +97,15 @@ func (s *VMStore) List(ctx context.Context, filters []sq.Sqlizer, opts ...ListOp
 			&vm.Memory,
 			&vm.DiskSize,
 			&vm.IssueCount,
+			&vm.CriticalCount,
+			&vm.WarningCount,
 			&vm.InspectionStatus.State,
 			&vm.IsTemplate,
 			&vm.IsMigratable,
 			&sqlErr,
 			&inspectionConcernCount,
 			&tags,
+			&vm.SnapshotCount,
 			&vm.UtilizationCpuP95,
 			&vm.UtilizationMemP95,
 			&vm.UtilizationDisk,
@@ -108,13 +121,69 @@ func (s *VMStore) List(ctx context.Context, filters []sq.Sqlizer, opts ...ListOp
 		vm.Tags = tags
 		vms = append(vms, vm)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(vms))
+	for _, vm := range vms {
+		ids = append(ids, vm.ID)
+	}
+	diskTypes, err := s.diskTypesByVM(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vms {
+		vms[i].DiskTypes = diskTypes[vms[i].ID]
+	}
+
+	return vms, nil
+}
+
+// Explain returns the SQL and arguments List would run for the same filters
+// and options, without running it.
+func (s *VMStore) Explain(filters []sq.Sqlizer, opts ...ListOption) (string, []any, error) {
+	return s.listQuery(filters, opts...)
+}
+
+func (s *VMStore) listQuery(filters []sq.Sqlizer, opts ...ListOption) (string, []any, error) {
+	builder := vmOutputQuery.
+		Columns(
+			`u.cpu_p95_pct AS cpu_p95_pct`,
+			`u.mem_p95_pct AS mem_p95_pct`,
+			`u.disk_pct    AS disk_pct`,
+			`u.confidence_pct AS confidence_pct`,
+		).
+		LeftJoin(`rightsizing_vm_utilization u ON u.moid = v."VM ID" AND u.report_id = (` +
+			`SELECT id FROM rightsizing_reports WHERE written_batch_count > 0 ORDER BY created_at DESC LIMIT 1` +
+			`)`)
+
+	// Apply external filters via subquery (filters reference table aliases in vmFilterSubquery)
+	if len(filters) > 0 {
+		subquery := vmFilterSubquery
+		for _, f := range filters {
+			subquery = subquery.Where(f)
+		}
+		subSQL, subArgs, err := subquery.ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		builder = builder.Where(sq.Expr(fmt.Sprintf(`v."VM ID" IN (%s)`, subSQL), subArgs...))
+	}
+
+	// Apply options (sort, limit, offset)
+	for _, opt := range opts {
+		builder = opt(builder)
+	}
 
-	return vms, rows.Err()
+	return s.withSuppression(builder).ToSql()
 }
 
-// Count returns the total number of VMs matching the filters.
+// Count returns the total number of VMs matching the filters. Filters run
+// against the flat join of disks, NICs and concerns, which has one row per
+// combination; only distinct VM IDs are counted, so a VM is counted once.
 func (s *VMStore) Count(ctx context.Context, filters ...sq.Sqlizer) (int, error) {
-	builder := sq.Select("COUNT(*)").From("vinfo v")
+	builder := sq.Select(`COUNT(DISTINCT v."VM ID")`).From("vinfo v")
 
 	if len(filters) > 0 {
 		subquery := vmFilterSubquery
@@ -128,7 +197,7 @@ func (s *VMStore) Count(ctx context.Context, filters ...sq.Sqlizer) (int, error)
 		builder = builder.Where(sq.Expr(fmt.Sprintf(`v."VM ID" IN (%s)`, subSQL), subArgs...))
 	}
 
-	query, args, err := builder.ToSql()
+	query, args, err := s.withSuppression(builder).ToSql()
 	if err != nil {
 		return 0, err
 	}
@@ -138,6 +207,119 @@ func (s *VMStore) Count(ctx context.Context, filters ...sq.Sqlizer) (int, error)
 	return count, err
 }
 
+// vmOsName is the OS a VM is reported under: the guest OS seen by VMware
+// Tools, falling back to the configured one. It matches the keys of the
+// inventory's osInfo, except that VMs with neither are counted as "Unknown OS".
+const vmOsName = `COALESCE(NULLIF(TRIM(v."OS according to the VMware Tools"), ''), NULLIF(TRIM(v."OS according to the configuration file"), ''), 'Unknown OS')`
+
+// OsDistribution returns the number of VMs per guest OS name, restricted to
+// the VMs matching filters.
+func (s *VMStore) OsDistribution(ctx context.Context, filters ...sq.Sqlizer) (map[string]int, error) {
+	builder := sq.Select(vmOsName+" AS os_name", "COUNT(*)").
+		From("vinfo v").
+		GroupBy("os_name")
+
+	if len(filters) > 0 {
+		subquery := vmFilterSubquery
+		for _, f := range filters {
+			subquery = subquery.Where(f)
+		}
+		subSQL, subArgs, err := subquery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		builder = builder.Where(sq.Expr(fmt.Sprintf(`v."VM ID" IN (%s)`, subSQL), subArgs...))
+	}
+
+	query, args, err := s.withSuppression(builder).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("executing os distribution query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	distribution := map[string]int{}
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, fmt.Errorf("scanning os distribution row: %w", err)
+		}
+		distribution[name] = count
+	}
+
+	return distribution, rows.Err()
+}
+
+// vmGroupColumns maps the fields VMs can be grouped by to the value each VM
+// is counted under.
+var vmGroupColumns = map[string]string{
+	"cluster":      `COALESCE(v."Cluster", '')`,
+	"datacenter":   `COALESCE(v."Datacenter", '')`,
+	"host":         `COALESCE(v."Host", '')`,
+	"os":           vmOsName,
+	"vCenterState": `COALESCE(v."Powerstate", '')`,
+}
+
+// GroupFields returns the fields GroupBy accepts, sorted.
+func GroupFields() []string {
+	return slices.Sorted(maps.Keys(vmGroupColumns))
+}
+
+// GroupBy returns the number of VMs per value of field, restricted to the VMs
+// matching filters. VMs without a value are counted under the empty string,
+// except for os, which counts them as "Unknown OS". A field outside
+// GroupFields is a ValidationError.
+func (s *VMStore) GroupBy(ctx context.Context, field string, filters ...sq.Sqlizer) (map[string]int, error) {
+	column, ok := vmGroupColumns[field]
+	if !ok {
+		return nil, srvErrors.NewValidationError(fmt.Sprintf("cannot group VMs by %q: must be one of %s", field, strings.Join(GroupFields(), ", ")))
+	}
+
+	builder := sq.Select(column+" AS group_value", "COUNT(*)").
+		From("vinfo v").
+		GroupBy("group_value")
+
+	if len(filters) > 0 {
+		subquery := vmFilterSubquery
+		for _, f := range filters {
+			subquery = subquery.Where(f)
+		}
+		subSQL, subArgs, err := subquery.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		builder = builder.Where(sq.Expr(fmt.Sprintf(`v."VM ID" IN (%s)`, subSQL), subArgs...))
+	}
+
+	query, args, err := s.withSuppression(builder).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("executing group by query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, fmt.Errorf("scanning group by row: %w", err)
+		}
+		counts[value] = count
+	}
+
+	return counts, rows.Err()
+}
+
 // Get returns full VM details by ID using the parser.
 func (s *VMStore) Get(ctx context.Context, id string) (*models.VM, error) {
 	vms, err := s.parser.VMs(ctx, duckdb_parser.Filters{VmId: id}, duckdb_parser.Options{})
@@ -151,9 +333,406 @@ func (s *VMStore) Get(ctx context.Context, id string) (*models.VM, error) {
 
 	result := fromDB(vms[0])
 
+	if err := s.setDiskDatastores(ctx, vms[0].Disks, result.Disks); err != nil {
+		return nil, err
+	}
+	snapshots, err := s.snapshotsByVM(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	result.Snapshots = snapshots[id]
+
+	diskTypes, err := s.diskTypesByVM(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	result.DiskTypes = diskTypes[id]
+
 	return &result, nil
 }
 
+// GetMany returns full VM details for the given IDs, in the order requested.
+// Unknown and repeated IDs are skipped. Only the requested VMs are read, in
+// one query (see vmsByID).
+func (s *VMStore) GetMany(ctx context.Context, ids []string) ([]models.VM, error) {
+	vms, err := s.vmsByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]duckdb_models.VM, len(vms))
+	for _, vm := range vms {
+		byID[vm.ID] = vm
+	}
+
+	result := make([]models.VM, 0, len(byID))
+	var pdisks []duckdb_models.Disk
+	var disks []models.Disk
+	for _, id := range ids {
+		pvm, ok := byID[id]
+		if !ok {
+			continue
+		}
+		delete(byID, id)
+
+		vm := fromDB(pvm)
+		pdisks = append(pdisks, pvm.Disks...)
+		disks = append(disks, vm.Disks...)
+		result = append(result, vm)
+	}
+
+	// Resolve the datastores of all disks at once, then hand them back.
+	if err := s.setDiskDatastores(ctx, pdisks, disks); err != nil {
+		return nil, err
+	}
+	for i := range result {
+		disks = disks[copy(result[i].Disks, disks):]
+	}
+
+	snapshots, err := s.snapshotsByVM(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		result[i].Snapshots = snapshots[result[i].ID]
+	}
+
+	diskTypes, err := s.diskTypesByVM(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		result[i].DiskTypes = diskTypes[result[i].ID]
+	}
+
+	return result, nil
+}
+
+// vmsByID reads the parser's VM rows for ids. The parser filters on a single
+// VM ID at most, so its VM query is wrapped with a "VM ID" IN (...) filter and
+// the columns of vmRowColumns are selected from it by name.
+func (s *VMStore) vmsByID(ctx context.Context, ids []string) ([]duckdb_models.VM, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	inner, err := duckdb_parser.NewBuilder().VMQuery(duckdb_parser.Filters{}, duckdb_parser.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("building vm query: %w", err)
+	}
+	inner = strings.TrimSuffix(strings.TrimSpace(inner), ";")
+
+	columns := make([]string, len(vmRowColumns))
+	for i, column := range vmRowColumns {
+		columns[i] = `v."` + column + `"`
+	}
+
+	query, args, err := sq.Select(columns...).
+		From("(" + inner + ") AS v").
+		Where(sq.Eq{`v."ID"`: ids}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building vms by id query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying VMs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var vms []duckdb_models.VM
+	for rows.Next() {
+		var vm duckdb_models.VM
+		if err := rows.Scan(
+			&vm.ID,
+			&vm.Name,
+			&vm.Folder,
+			&vm.Host,
+			&vm.UUID,
+			&vm.Firmware,
+			&vm.PowerState,
+			&vm.ConnectionState,
+			&vm.FaultToleranceEnabled,
+			&vm.CpuCount,
+			&vm.MemoryMB,
+			&vm.GuestName,
+			&vm.GuestNameFromVmwareTools,
+			&vm.HostName,
+			&vm.IpAddress,
+			&vm.StorageUsed,
+			&vm.IsTemplate,
+			&vm.ChangeTrackingEnabled,
+			&vm.DiskEnableUuid,
+			&vm.Datacenter,
+			&vm.Cluster,
+			&vm.HWVersion,
+			&vm.TotalDiskCapacityMiB,
+			&vm.ProvisionedMiB,
+			&vm.ResourcePool,
+			&vm.OsDiskComplexity,
+			&vm.CpuHotAddEnabled,
+			&vm.CpuHotRemoveEnabled,
+			&vm.CpuSockets,
+			&vm.CoresPerSocket,
+			&vm.MemoryHotAddEnabled,
+			&vm.BalloonedMemory,
+			&vm.Disks,
+			&vm.NICs,
+			&vm.Networks,
+			&vm.Concerns,
+		); err != nil {
+			return nil, fmt.Errorf("scanning VM row: %w", err)
+		}
+
+		// Like the parser, propagate the VM's CBT setting to each disk.
+		for i := range vm.Disks {
+			vm.Disks[i].ChangeTrackingEnabled = vm.ChangeTrackingEnabled
+		}
+
+		vms = append(vms, vm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating VM rows: %w", err)
+	}
+	return vms, nil
+}
+
+// setDiskDatastores resolves the datastore name and type of each disk from vdatastore.
+// Parser disks reference their datastore by object ID only.
+func (s *VMStore) setDiskDatastores(ctx context.Context, pdisks []duckdb_models.Disk, disks []models.Disk) error {
+	ids := make([]string, 0, len(pdisks))
+	for _, d := range pdisks {
+		if d.Datastore.ID != "" {
+			ids = append(ids, d.Datastore.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sq.Select(`"Object ID"`, `COALESCE("Name", '')`, `COALESCE("Type", '')`).
+		From("vdatastore").
+		Where(sq.Eq{`"Object ID"`: ids}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	type datastore struct{ name, kind string }
+	datastores := make(map[string]datastore)
+	for rows.Next() {
+		var id string
+		var ds datastore
+		if err := rows.Scan(&id, &ds.name, &ds.kind); err != nil {
+			return err
+		}
+		datastores[id] = ds
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, d := range pdisks {
+		if ds, ok := datastores[d.Datastore.ID]; ok {
+			disks[i].Datastore = ds.name
+			disks[i].DiskType = ds.kind
+		}
+	}
+
+	return nil
+}
+
+// snapshotsByVM returns the snapshots of the given VMs, oldest first.
+func (s *VMStore) snapshotsByVM(ctx context.Context, ids []string) (map[string][]models.VirtualMachineSnapshot, error) {
+	query, args, err := sq.Select(`"VM ID"`, `"Name"`, `"Created"`).
+		From(vmSnapshotsTable).
+		Where(sq.Eq{`"VM ID"`: ids}).
+		OrderBy(`"VM ID"`, `"Created"`, `"Name"`).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	snapshots := make(map[string][]models.VirtualMachineSnapshot)
+	for rows.Next() {
+		var snap models.VirtualMachineSnapshot
+		var created sql.NullTime
+		if err := rows.Scan(&snap.VMID, &snap.Name, &created); err != nil {
+			return nil, err
+		}
+		snap.CreatedAt = created.Time
+		snapshots[snap.VMID] = append(snapshots[snap.VMID], snap)
+	}
+
+	return snapshots, rows.Err()
+}
+
+// diskTypesByVM returns the disk capacity of the given VMs in GB per
+// datastore type. It is the inventory's disk type summary (DiskTypeSummary in
+// the parser) kept per VM: disks are matched to their datastore by the name
+// in the disk path, and disks on an unknown or untyped datastore are left out.
+func (s *VMStore) diskTypesByVM(ctx context.Context, ids []string) (map[string]map[string]float64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sq.Select(`d."VM ID"`, `ds."Type"`, `ROUND(SUM(d."Capacity MiB") / 1024.0, 2)`).
+		From("vdisk d").
+		Join(`vdatastore ds ON ds."Name" = regexp_extract(COALESCE(d."Path", d."Disk Path"), '\[([^\]]+)\]', 1)`).
+		Where(sq.Eq{`d."VM ID"`: ids}).
+		Where(`ds."Type" IS NOT NULL AND ds."Type" != ''`).
+		GroupBy(`d."VM ID"`, `ds."Type"`).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	diskTypes := make(map[string]map[string]float64)
+	for rows.Next() {
+		var id, kind string
+		var sizeGB float64
+		if err := rows.Scan(&id, &kind, &sizeGB); err != nil {
+			return nil, err
+		}
+		if diskTypes[id] == nil {
+			diskTypes[id] = make(map[string]float64)
+		}
+		diskTypes[id][kind] = sizeGB
+	}
+
+	return diskTypes, rows.Err()
+}
+
+// InsertSnapshots stores the snapshots read from vCenter for the collected
+// VMs. Snapshots of VMs missing from vinfo, created after the inventory was
+// read, are dropped. Each VM with a snapshot also gets the
+// vmware.snapshot.detected concern, unless the OPA policies already raised it,
+// so the concern does not depend on policies being configured.
+func (s *VMStore) InsertSnapshots(ctx context.Context, snapshots []models.VirtualMachineSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(snapshots))
+	args := make([]any, 0, 3*len(snapshots))
+	for _, snap := range snapshots {
+		values = append(values, "(?, ?, ?::TIMESTAMP)")
+		args = append(args, snap.VMID, snap.Name, snap.CreatedAt.UTC())
+	}
+	query := fmt.Sprintf(
+		`INSERT INTO %s ("VM ID", "Name", "Created") SELECT * FROM (VALUES %s) s(vm_id, name, created) WHERE s.vm_id IN (SELECT "VM ID" FROM vinfo)`,
+		vmSnapshotsTable, strings.Join(values, ", "),
+	)
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("inserting snapshots: %w", err)
+	}
+
+	return s.addSnapshotConcerns(ctx)
+}
+
+// addSnapshotConcerns raises the snapshot concern on every VM with a stored
+// snapshot that does not carry it yet.
+func (s *VMStore) addSnapshotConcerns(ctx context.Context) error {
+	query, args, err := sq.Insert("concerns").
+		Columns(`"VM_ID"`, `"Concern_ID"`, `"Label"`, `"Category"`, `"Assessment"`).
+		Select(sq.Select(`DISTINCT s."VM ID"`).
+			Column("?", snapshotConcernID).
+			Column("?", snapshotConcernLabel).
+			Column("?", "Information").
+			Column("?", snapshotConcernAssessment).
+			From(vmSnapshotsTable+" s").
+			Where(`NOT EXISTS (SELECT 1 FROM concerns c WHERE c."VM_ID" = s."VM ID" AND c."Concern_ID" = ?)`, snapshotConcernID),
+		).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("adding snapshot concerns: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceConcerns replaces the concerns table with the given concerns, keyed
+// by VM ID, then raises the snapshot concern again. It returns the number of
+// concerns stored.
+func (s *VMStore) ReplaceConcerns(ctx context.Context, concerns map[string][]duckdb_models.Concern) (int, error) {
+	builder := duckdb_parser.NewConcernValuesBuilder()
+	for id, c := range concerns {
+		builder.Append(id, c...)
+	}
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM concerns"); err != nil {
+		return 0, fmt.Errorf("deleting concerns: %w", err)
+	}
+	if values := builder.Build(); values != "" {
+		query := fmt.Sprintf(`INSERT INTO concerns ("VM_ID", "Concern_ID", "Label", "Category", "Assessment") VALUES %s`, values)
+		if _, err := s.db.ExecContext(ctx, query); err != nil {
+			return 0, fmt.Errorf("inserting concerns: %w", err)
+		}
+	}
+	if err := s.addSnapshotConcerns(ctx); err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM concerns").Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting concerns: %w", err)
+	}
+	return count, nil
+}
+
+// InsertCreateDates stores the VM creation dates read from vCenter, keyed by
+// VM ID. Dates of VMs missing from vinfo, created after the inventory was
+// read, are dropped.
+func (s *VMStore) InsertCreateDates(ctx context.Context, dates map[string]time.Time) error {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(dates))
+	args := make([]any, 0, 2*len(dates))
+	for id, created := range dates {
+		values = append(values, "(?, ?::TIMESTAMP)")
+		args = append(args, id, created.UTC())
+	}
+	query := fmt.Sprintf(
+		`INSERT INTO %s ("VM ID", "Created") SELECT * FROM (VALUES %s) s(vm_id, created) WHERE s.vm_id IN (SELECT "VM ID" FROM vinfo)`,
+		vmCreationTable, strings.Join(values, ", "),
+	)
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("inserting creation dates: %w", err)
+	}
+
+	return nil
+}
+
 // normalizeCategory validates and normalizes an issue category (case-insensitive).
 func normalizeCategory(category, issueID string) string {
 	// Valid issue categories (lowercase for case-insensitive comparison)
@@ -203,6 +782,7 @@ func fromDB(pvm duckdb_models.VM) models.VM {
 			RDM:      d.RDM,
 			Bus:      d.Bus,
 			Mode:     d.Mode,
+			Thin:     strings.EqualFold(d.Thin, "true"),
 		})
 		totalDiskCapacityMiB += d.Capacity
 	}
@@ -263,6 +843,59 @@ func ByFilter(expr string) sq.Sqlizer {
 	return sqlizer
 }
 
+// ByCriticalConcerns keeps only VMs that have at least one Critical concern.
+func ByCriticalConcerns() sq.Sqlizer {
+	return sq.Expr(`EXISTS (SELECT 1 FROM concerns cr WHERE cr."VM_ID" = v."VM ID" AND cr."Category" = 'Critical')`)
+}
+
+// ByReadiness keeps only VMs whose migration readiness is one of readiness:
+// blocked with a Critical concern, warning with a Warning concern but no
+// Critical one, migratable with neither.
+func ByReadiness(readiness []models.Readiness) sq.Sqlizer {
+	critical := `EXISTS (SELECT 1 FROM concerns rc WHERE rc."VM_ID" = v."VM ID" AND rc."Category" = 'Critical')`
+	warning := `EXISTS (SELECT 1 FROM concerns rw WHERE rw."VM_ID" = v."VM ID" AND rw."Category" = 'Warning')`
+
+	or := sq.Or{}
+	for _, r := range readiness {
+		switch r {
+		case models.ReadinessBlocked:
+			or = append(or, sq.Expr(critical))
+		case models.ReadinessWarning:
+			or = append(or, sq.Expr("NOT "+critical+" AND "+warning))
+		case models.ReadinessMigratable:
+			or = append(or, sq.Expr("NOT "+critical+" AND NOT "+warning))
+		}
+	}
+	return or
+}
+
+// ByConcernLabel keeps only VMs that have a concern whose label matches the
+// regular expression pattern, as the filter DSL's ~ operator matches.
+func ByConcernLabel(pattern string) sq.Sqlizer {
+	return sq.Expr(`EXISTS (SELECT 1 FROM concerns cl WHERE cl."VM_ID" = v."VM ID" AND regexp_matches(cl."Label", ?))`, pattern)
+}
+
+// ByNICType keeps only VMs that have a NIC whose adapter type (vnetwork
+// "Adapter", e.g. e1000 or vmxnet3) matches the regular expression pattern.
+func ByNICType(pattern string) sq.Sqlizer {
+	return sq.Expr(`EXISTS (SELECT 1 FROM vnetwork nt WHERE nt."VM ID" = v."VM ID" AND regexp_matches(nt."Adapter", ?))`, pattern)
+}
+
+// ByNICCount keeps only VMs whose number of distinct NICs (by MAC address) is
+// within [minCount, maxCount]. A nil bound is not checked; VMs without NICs
+// count zero.
+func ByNICCount(minCount, maxCount *int) sq.Sqlizer {
+	count := `(SELECT COUNT(DISTINCT nc."Mac Address") FROM vnetwork nc WHERE nc."VM ID" = v."VM ID")`
+	and := sq.And{}
+	if minCount != nil {
+		and = append(and, sq.Expr(count+" >= ?", *minCount))
+	}
+	if maxCount != nil {
+		and = append(and, sq.Expr(count+" <= ?", *maxCount))
+	}
+	return and
+}
+
 // WithVMIDs filters the output query to only include VMs with the given IDs.
 // This bypasses the filter subquery, using pre-computed group match results.
 func WithVMIDs(ids []string) ListOption {
@@ -295,12 +928,14 @@ func WithDefaultSort() ListOption {
 // WithSort applies multi-field sorting using output aliases.
 func WithSort(sorts []SortParam) ListOption {
 	apiFieldToDBColumn := map[string]string{
-		"name":         "name",
-		"vCenterState": "power_state",
-		"cluster":      "cluster",
-		"diskSize":     "disk_size",
-		"memory":       "memory",
-		"issues":       "issue_count",
+		"name":           "name",
+		"vCenterState":   "power_state",
+		"cluster":        "cluster",
+		"diskSize":       "disk_size",
+		"memory":         "memory",
+		"issues":         "issue_count",
+		"criticalIssues": "critical_count",
+		"warningIssues":  "warning_count",
 	}
 
 	return func(b sq.SelectBuilder) sq.SelectBuilder {
@@ -321,6 +956,99 @@ func WithSort(sorts []SortParam) ListOption {
 	}
 }
 
+// SizesByCluster returns the vCPUs, memory and total disk capacity of every
+// VM, grouped by cluster name. A VM without disks counts with 0 TB, as in the
+// parser's disk size tiers.
+func (s *VMStore) SizesByCluster(ctx context.Context) (map[string][]models.VMSize, error) {
+	query, args, err := sq.Select(
+		`COALESCE(v."Cluster", '')`,
+		`COALESCE(v."CPUs", 0)`,
+		`COALESCE(v."Memory", 0)`,
+		`COALESCE(SUM(d."Capacity MiB"), 0) / 1048576.0`,
+	).
+		From("vinfo v").
+		LeftJoin(`vdisk d ON v."VM ID" = d."VM ID"`).
+		GroupBy(`v."VM ID"`, `v."Cluster"`, `v."CPUs"`, `v."Memory"`).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	sizes := make(map[string][]models.VMSize)
+	for rows.Next() {
+		var cluster string
+		var size models.VMSize
+		if err := rows.Scan(&cluster, &size.CPUs, &size.MemoryMB, &size.DiskTB); err != nil {
+			return nil, err
+		}
+		sizes[cluster] = append(sizes[cluster], size)
+	}
+
+	return sizes, rows.Err()
+}
+
+// ConcernsByCluster returns the concerns of every VM, grouped by cluster name.
+// VMs without concerns are listed too, with none, so each cluster holds all
+// of its VMs.
+func (s *VMStore) ConcernsByCluster(ctx context.Context) (map[string][]models.VMConcerns, error) {
+	query, args, err := sq.Select(
+		`COALESCE(v."Cluster", '')`,
+		`v."VM ID"`,
+		`c."Concern_ID"`,
+		`c."Label"`,
+		`c."Category"`,
+		`c."Assessment"`,
+	).
+		From("vinfo v").
+		LeftJoin(`concerns c ON v."VM ID" = c."VM_ID"`).
+		OrderBy(`v."VM ID"`, `c."Concern_ID"`).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	concerns := make(map[string][]models.VMConcerns)
+	for rows.Next() {
+		var cluster, vmID string
+		var id, label, category, assessment sql.NullString
+		if err := rows.Scan(&cluster, &vmID, &id, &label, &category, &assessment); err != nil {
+			return nil, err
+		}
+		vms := concerns[cluster]
+		if len(vms) == 0 || vms[len(vms)-1].VMID != vmID {
+			vms = append(vms, models.VMConcerns{VMID: vmID})
+		}
+		if id.Valid {
+			vm := &vms[len(vms)-1]
+			vm.Concerns = append(vm.Concerns, models.VMConcern{
+				ID:         id.String,
+				Label:      label.String,
+				Category:   category.String,
+				Assessment: assessment.String,
+			})
+		}
+		concerns[cluster] = vms
+	}
+
+	return concerns, rows.Err()
+}
+
 // GetFolders returns a list of distinct folders from the vinfo table.
 func (s *VMStore) GetFolders(ctx context.Context) ([]models.Folder, error) {
 	builder := sq.Select(
@@ -3,6 +3,7 @@ package store_test
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"sort"
 
 	sq "github.com/Masterminds/squirrel"
@@ -96,6 +97,47 @@ var _ = Describe("VMStore cross-table filters", func() {
 		})
 	})
 
+	Context("datacenter membership", func() {
+		It("should filter by datacenter across all of its clusters", func() {
+			f := store.ByFilter("datacenter = 'DC1'")
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{f}, store.WithDefaultSort())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs(vms)).To(Equal([]string{"vm-001", "vm-002", "vm-003", "vm-004", "vm-005", "vm-006", "vm-007"}))
+		})
+
+		It("should filter by datacenter list", func() {
+			f := store.ByFilter("datacenter in ['DC2', 'DC3']")
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{f}, store.WithDefaultSort())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs(vms)).To(Equal([]string{"vm-008", "vm-009", "vm-010"}))
+		})
+
+		It("should combine datacenter and cluster filters", func() {
+			f := store.ByFilter("datacenter = 'DC1' and cluster != 'production'")
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{f}, store.WithDefaultSort())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs(vms)).To(Equal([]string{"vm-005", "vm-006", "vm-007"}))
+		})
+
+		It("should count VMs by datacenter", func() {
+			count, err := s.VM().Count(ctx, store.ByFilter("datacenter = 'DC2'"))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(3))
+		})
+
+		It("should return empty for an unknown datacenter", func() {
+			f := store.ByFilter("datacenter = 'DC9'")
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{f}, store.WithDefaultSort())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms).To(BeEmpty())
+		})
+	})
+
 	Context("vdisk columns (disk.* prefix)", func() {
 		It("should filter by individual disk capacity", func() {
 			f := store.ByFilter("disk.capacity >= 500")
@@ -178,6 +220,106 @@ var _ = Describe("VMStore cross-table filters", func() {
 		})
 	})
 
+	Context("readiness", func() {
+		// Given vm-007 with a Critical concern, vm-003 and vm-004 with only
+		// Warning ones, and the other VMs without either
+		// When we filter on each readiness with the filter DSL and ByReadiness
+		// Then both should select the same VMs
+		It("should select the VMs of each readiness", func() {
+			buckets := map[models.Readiness][]string{
+				models.ReadinessBlocked:    {"vm-007"},
+				models.ReadinessWarning:    {"vm-003", "vm-004"},
+				models.ReadinessMigratable: {"vm-001", "vm-002", "vm-005", "vm-006", "vm-008", "vm-009", "vm-010"},
+			}
+			for readiness, ids := range buckets {
+				vms, err := s.VM().List(ctx, []sq.Sqlizer{store.ByFilter(fmt.Sprintf("readiness = '%s'", readiness))})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(vmIDs(vms)).To(Equal(ids), string(readiness))
+
+				vms, err = s.VM().List(ctx, []sq.Sqlizer{store.ByReadiness([]models.Readiness{readiness})})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(vmIDs(vms)).To(Equal(ids), string(readiness))
+			}
+		})
+
+		// Given several readiness values
+		// When we filter on them
+		// Then VMs in any of them should match
+		It("should match any of several readiness values", func() {
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{store.ByReadiness([]models.Readiness{models.ReadinessWarning, models.ReadinessBlocked})})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs(vms)).To(Equal([]string{"vm-003", "vm-004", "vm-007"}))
+
+			vms, err = s.VM().List(ctx, []sq.Sqlizer{store.ByFilter("readiness in ['warning', 'blocked']")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs(vms)).To(Equal([]string{"vm-003", "vm-004", "vm-007"}))
+		})
+
+		// Given vm-007's Critical concern suppressed
+		// When we filter on readiness
+		// Then vm-007 should move to warning, as it still has a Warning concern
+		It("should ignore suppressed concerns", func() {
+			suppressed := s.VM().WithoutConcerns([]string{"concern-005"})
+
+			vms, err := suppressed.List(ctx, []sq.Sqlizer{store.ByReadiness([]models.Readiness{models.ReadinessWarning})})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs(vms)).To(Equal([]string{"vm-003", "vm-004", "vm-007"}))
+
+			count, err := suppressed.Count(ctx, store.ByFilter("readiness = 'blocked'"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+	})
+
+	Context("issue severity counts", func() {
+		It("should split issue count into critical and warning counts", func() {
+			vms, err := s.VM().List(ctx, nil, store.WithDefaultSort())
+			Expect(err).NotTo(HaveOccurred())
+
+			byID := make(map[string]models.VirtualMachineSummary, len(vms))
+			for _, vm := range vms {
+				byID[vm.ID] = vm
+			}
+
+			// vm-003: 2 warnings
+			Expect(byID["vm-003"].IssueCount).To(Equal(2))
+			Expect(byID["vm-003"].CriticalCount).To(Equal(0))
+			Expect(byID["vm-003"].WarningCount).To(Equal(2))
+			// vm-007: 1 critical, 1 warning, 1 information
+			Expect(byID["vm-007"].IssueCount).To(Equal(3))
+			Expect(byID["vm-007"].CriticalCount).To(Equal(1))
+			Expect(byID["vm-007"].WarningCount).To(Equal(1))
+			// vm-001: no concerns
+			Expect(byID["vm-001"].IssueCount).To(Equal(0))
+			Expect(byID["vm-001"].CriticalCount).To(Equal(0))
+			Expect(byID["vm-001"].WarningCount).To(Equal(0))
+		})
+
+		It("should filter by critical count", func() {
+			f := store.ByFilter("critical_count >= 1")
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{f}, store.WithDefaultSort())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs(vms)).To(Equal([]string{"vm-007"}))
+		})
+
+		It("should filter by warning count", func() {
+			f := store.ByFilter("warning_count >= 1")
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{f}, store.WithDefaultSort())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmIDs(vms)).To(Equal([]string{"vm-003", "vm-004", "vm-007"}))
+		})
+
+		It("should include VMs without concerns when filtering on zero critical issues", func() {
+			f := store.ByFilter("critical_count = 0")
+			count, err := s.VM().Count(ctx, f)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(9))
+		})
+	})
+
 	Context("vcpu columns (cpu.* prefix)", func() {
 		It("should filter by cores per socket", func() {
 			f := store.ByFilter("cpu.cores_per_socket >= 4")
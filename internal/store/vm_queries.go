@@ -2,6 +2,51 @@ package store
 
 import sq "github.com/Masterminds/squirrel"
 
+// vmSnapshotCounts joins the number of snapshots of each VM as snap.snapshot_count.
+const vmSnapshotCounts = `(SELECT "VM ID", COUNT(*) AS snapshot_count FROM vsnapshots GROUP BY "VM ID") snap ON v."VM ID" = snap."VM ID"`
+
+// vmRowColumns are the columns of the parser's VM query that vmsByID reads, by
+// name and in the order they are scanned. Selecting them by name turns a column
+// renamed or moved by the parser into a query error instead of a misread row.
+var vmRowColumns = []string{
+	"ID",
+	"Name",
+	"Folder",
+	"Host",
+	"UUID",
+	"Firmware",
+	"PowerState",
+	"ConnectionState",
+	"FaultToleranceEnabled",
+	"CpuCount",
+	"MemoryMB",
+	"GuestName",
+	"GuestNameFromVmwareTools",
+	"HostName",
+	"IpAddress",
+	"StorageUsed",
+	"IsTemplate",
+	"ChangeTrackingEnabled",
+	"DiskEnableUuid",
+	"Datacenter",
+	"Cluster",
+	"HWVersion",
+	"TotalDiskCapacityMiB",
+	"ProvisionedMiB",
+	"ResourcePool",
+	"OsDiskComplexity",
+	"CpuHotAddEnabled",
+	"CpuHotRemoveEnabled",
+	"CpuSockets",
+	"CoresPerSocket",
+	"MemoryHotAddEnabled",
+	"BalloonedMemory",
+	"Disks",
+	"NICs",
+	"Networks",
+	"Concerns",
+}
+
 // vmOutputQuery is the base aggregated output query that produces one row per VM.
 // Filters should be applied via Where clauses on the VM ID.
 var vmOutputQuery = sq.Select(
@@ -13,17 +58,22 @@ var vmOutputQuery = sq.Select(
 	`v."Memory" AS memory`,
 	`COALESCE(d.total_disk, 0) AS disk_size`,
 	`COALESCE(c.issues_count, 0) AS issue_count`,
+	`COALESCE(crit.critical_count, 0) AS critical_count`,
+	`COALESCE(warn.warning_count, 0) AS warning_count`,
 	`COALESCE(i.status, 'not_started') AS status`,
 	`v."Template" as template`,
 	`COALESCE(crit.critical_count, 0) = 0 AS migratable`,
 	`COALESCE(i.error, '') AS error`,
 	`COALESCE((SELECT COUNT(*)::BIGINT FROM vm_inspection_concerns ic WHERE ic."VM ID" = v."VM ID" AND ic.inspection_id = (SELECT MAX(inspection_id) FROM vm_inspection_concerns imx WHERE imx."VM ID" = v."VM ID")), 0) AS inspection_concern_count`,
 	`COALESCE(t.tags, [])::VARCHAR[] AS tags`,
+	`COALESCE(snap.snapshot_count, 0) AS snapshot_count`,
 ).From("vinfo v").
 	LeftJoin(`(SELECT "VM_ID", COUNT(*) AS issues_count FROM concerns GROUP BY "VM_ID") c ON v."VM ID" = c."VM_ID"`).
 	LeftJoin(`(SELECT "VM_ID", COUNT(*) AS critical_count FROM concerns WHERE "Category" = 'Critical' GROUP BY "VM_ID") crit ON v."VM ID" = crit."VM_ID"`).
+	LeftJoin(`(SELECT "VM_ID", COUNT(*) AS warning_count FROM concerns WHERE "Category" = 'Warning' GROUP BY "VM_ID") warn ON v."VM ID" = warn."VM_ID"`).
 	LeftJoin(`(SELECT "VM ID", SUM("Capacity MiB") AS total_disk FROM vdisk GROUP BY "VM ID") d ON v."VM ID" = d."VM ID"`).
 	LeftJoin(`vm_inspection_status i ON v."VM ID" = i."VM ID"`).
+	LeftJoin(vmSnapshotCounts).
 	LeftJoin(`(
 		SELECT u.vm_id, list_distinct(flatten(list(g.tags))) AS tags
 		FROM group_matches gm
@@ -46,6 +96,9 @@ var vmFilterSubquery = sq.Select(`DISTINCT v."VM ID"`).
 	LeftJoin(`vnetwork net ON v."VM ID" = net."VM ID"`).
 	LeftJoin(`(SELECT "VM_ID", COUNT(*) AS issues_count FROM concerns GROUP BY "VM_ID") cc ON v."VM ID" = cc."VM_ID"`).
 	LeftJoin(`(SELECT "VM_ID", COUNT(*) AS critical_count FROM concerns WHERE "Category" = 'Critical' GROUP BY "VM_ID") crit ON v."VM ID" = crit."VM_ID"`).
+	LeftJoin(`(SELECT "VM_ID", COUNT(*) AS warning_count FROM concerns WHERE "Category" = 'Warning' GROUP BY "VM_ID") warn ON v."VM ID" = warn."VM_ID"`).
 	LeftJoin(`(SELECT "VM ID", SUM("Capacity MiB") AS total_disk FROM vdisk GROUP BY "VM ID") d ON v."VM ID" = d."VM ID"`).
+	LeftJoin(vmSnapshotCounts).
+	LeftJoin(`vcreation vcr ON v."VM ID" = vcr."VM ID"`).
 	LeftJoin(`vdatastore ds ON ds."Name" = regexp_extract(COALESCE(dk."Path", dk."Disk Path"), '\[([^\]]+)\]', 1)`).
 	LeftJoin(`vm_inspection_concerns ic ON v."VM ID" = ic."VM ID" AND ic.inspection_id = (SELECT MAX(inspection_id) FROM vm_inspection_concerns imx WHERE imx."VM ID" = v."VM ID")`)
@@ -3,8 +3,10 @@ package store_test
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	duckdb_models "github.com/kubev2v/migration-planner/pkg/duckdb_parser/models"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -67,6 +69,15 @@ var _ = Describe("VMStore", func() {
 		Expect(err).NotTo(HaveOccurred())
 	}
 
+	// Helper to insert a NIC into vnetwork table
+	insertNIC := func(vmID, network, mac string) {
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO vnetwork ("VM ID", "Network", "Mac Address")
+			VALUES (?, ?, ?)
+		`, vmID, network, mac)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
 	// Helper to insert concerns for a VM
 	insertConcern := func(vmID, concernID, label, category string) {
 		_, err := db.ExecContext(ctx, `
@@ -328,6 +339,52 @@ var _ = Describe("VMStore", func() {
 				Expect(vms).To(HaveLen(5))
 				Expect(vms[0].IssueCount).To(Equal(2)) // vm-3 has 2 issues
 			})
+
+			// Given VMs with and without critical concerns
+			// When we sort by critical issues descending, then name ascending
+			// Then VMs with critical issues come first, ties ordered by name
+			It("should sort by critical issues then name", func() {
+				// Arrange
+				insertConcern("vm-2", "concern-c1", "RDM disk detected", "Critical")
+				insertConcern("vm-4", "concern-c2", "Shared disk detected", "Critical")
+
+				// Act
+				vms, err := s.VM().List(ctx, nil, store.WithSort([]store.SortParam{
+					{Field: "criticalIssues", Desc: true},
+					{Field: "name", Desc: false},
+				}))
+
+				// Assert
+				Expect(err).NotTo(HaveOccurred())
+				names := make([]string, 0, len(vms))
+				for _, vm := range vms {
+					names = append(names, vm.Name)
+				}
+				Expect(names).To(Equal([]string{"app-server-1", "web-server-2", "app-server-2", "db-server-1", "web-server-1"}))
+			})
+
+			// Given VMs with the same critical count but different warning counts
+			// When we sort by critical issues, then warning issues, both descending
+			// Then warnings break the tie between VMs with equal critical counts
+			It("should sort by critical issues then warning issues", func() {
+				// Arrange
+				insertConcern("vm-1", "concern-c1", "RDM disk detected", "Critical")
+				insertConcern("vm-5", "concern-c2", "Shared disk detected", "Critical")
+
+				// Act
+				vms, err := s.VM().List(ctx, nil, store.WithSort([]store.SortParam{
+					{Field: "criticalIssues", Desc: true},
+					{Field: "warningIssues", Desc: true},
+				}))
+
+				// Assert
+				Expect(err).NotTo(HaveOccurred())
+				ids := make([]string, 0, len(vms))
+				for _, vm := range vms {
+					ids = append(ids, vm.ID)
+				}
+				Expect(ids).To(Equal([]string{"vm-5", "vm-1", "vm-3", "vm-2", "vm-4"}))
+			})
 		})
 
 		Context("combined filters", func() {
@@ -531,6 +588,167 @@ var _ = Describe("VMStore", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(count).To(Equal(2))
 		})
+
+		// Given a VM with several disks and NICs
+		// When we count with filters matching its disks and NICs
+		// Then it should be counted once, as List returns it once
+		It("should count a VM with several disks and NICs once", func() {
+			// Arrange
+			insertDisk("vm-1", 300)
+			insertDisk("vm-1", 400)
+			insertNIC("vm-1", "prod-net", "00:50:56:00:00:01")
+			insertNIC("vm-1", "prod-net", "00:50:56:00:00:02")
+			insertNIC("vm-2", "prod-net", "00:50:56:00:00:03")
+			filter := store.ByFilter("net.network = 'prod-net' and cluster = 'cluster-a'")
+
+			// Act
+			count, err := s.VM().Count(ctx, filter)
+			Expect(err).NotTo(HaveOccurred())
+			all, err := s.VM().Count(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{filter})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+			Expect(count).To(Equal(len(vms)))
+			Expect(all).To(Equal(3))
+		})
+	})
+
+	Context("WithoutConcerns", func() {
+		BeforeEach(func() {
+			insertVM("vm-1", "vm1", "poweredOn", "cluster-a", 4096)
+			insertVM("vm-2", "vm2", "poweredOn", "cluster-a", 8192)
+
+			insertConcern("vm-1", "known.critical", "Accepted RDM disk", "Critical")
+			insertConcern("vm-1", "known.warning", "Accepted CBT setting", "Warning")
+			insertConcern("vm-1", "other.warning", "Outdated OS", "Warning")
+			insertConcern("vm-2", "known.critical", "Accepted RDM disk", "Critical")
+		})
+
+		suppressed := []string{"known.critical", "known.warning"}
+
+		// Given VMs with suppressed and other concerns
+		// When we list them without the suppressed concerns
+		// Then counts and migratability should ignore the suppressed concerns
+		It("should leave suppressed concerns out of counts", func() {
+			// Act
+			vms, err := s.VM().WithoutConcerns(suppressed).List(ctx, nil, store.WithDefaultSort())
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms).To(HaveLen(2))
+			Expect(vms[0].IssueCount).To(Equal(1))
+			Expect(vms[0].WarningCount).To(Equal(1))
+			Expect(vms[0].CriticalCount).To(Equal(0))
+			Expect(vms[0].IsMigratable).To(BeTrue())
+			Expect(vms[1].IssueCount).To(Equal(0))
+			Expect(vms[1].IsMigratable).To(BeTrue())
+		})
+
+		// Given VMs whose only critical concern is suppressed
+		// When we list and count VMs with critical concerns
+		// Then no VM should match
+		It("should leave suppressed concerns out of filters", func() {
+			// Act
+			vms, err := s.VM().WithoutConcerns(suppressed).List(ctx, []sq.Sqlizer{store.ByCriticalConcerns()})
+			Expect(err).NotTo(HaveOccurred())
+			count, err := s.VM().WithoutConcerns(suppressed).Count(ctx, store.ByCriticalConcerns())
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms).To(BeEmpty())
+			Expect(count).To(Equal(0))
+		})
+
+		// Given VMs whose only RDM concern is suppressed
+		// When we filter by that concern label
+		// Then only the unfiltered store should match them
+		It("should leave suppressed concerns out of label filters", func() {
+			// Act
+			hidden, err := s.VM().WithoutConcerns(suppressed).List(ctx, []sq.Sqlizer{store.ByConcernLabel("(?i)rdm")})
+			Expect(err).NotTo(HaveOccurred())
+			all, err := s.VM().List(ctx, []sq.Sqlizer{store.ByConcernLabel("(?i)rdm")})
+			Expect(err).NotTo(HaveOccurred())
+			outdated, err := s.VM().WithoutConcerns(suppressed).Count(ctx, store.ByConcernLabel("^Outdated"))
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hidden).To(BeEmpty())
+			Expect(all).To(HaveLen(2))
+			Expect(outdated).To(Equal(1))
+		})
+
+		// Given a store view without some concerns
+		// When we use the store itself and get a VM
+		// Then every concern should still be there
+		It("should not affect the store or VM details", func() {
+			// Arrange
+			_ = s.VM().WithoutConcerns(suppressed)
+
+			// Act
+			vms, err := s.VM().List(ctx, nil, store.WithDefaultSort())
+			Expect(err).NotTo(HaveOccurred())
+			vm, err := s.VM().WithoutConcerns(suppressed).Get(ctx, "vm-1")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms[0].IssueCount).To(Equal(3))
+			Expect(vms[0].IsMigratable).To(BeFalse())
+			Expect(vm.Issues).To(HaveLen(3))
+		})
+	})
+
+	Context("GetMany", func() {
+		BeforeEach(func() {
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			Expect(test.InsertVMDatastores(ctx, db)).To(Succeed())
+		})
+
+		// Given several VMs in the database
+		// When we get some of them, with unknown and repeated IDs
+		// Then it should return each known VM once, in request order, with disk datastores
+		It("should return known VMs in request order", func() {
+			// Act
+			vms, err := s.VM().GetMany(ctx, []string{"vm-003", "non-existent", "vm-001", "vm-003"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms).To(HaveLen(2))
+			Expect(vms[0].ID).To(Equal("vm-003"))
+			Expect(vms[0].Disks).To(HaveLen(2))
+			for _, d := range vms[0].Disks {
+				Expect(d.Datastore).To(Equal("datastore1"))
+			}
+			Expect(vms[1].ID).To(Equal("vm-001"))
+		})
+
+		// Given several VMs in the database
+		// When we get one of them with GetMany and with Get
+		// Then both should return the same details
+		It("should return the same details as Get", func() {
+			// Act
+			vms, err := s.VM().GetMany(ctx, []string{"vm-003"})
+			single, singleErr := s.VM().Get(ctx, "vm-003")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(singleErr).NotTo(HaveOccurred())
+			Expect(vms).To(Equal([]models.VM{*single}))
+		})
+
+		// Given only unknown IDs
+		// When we get them
+		// Then it should return an empty list
+		It("should return an empty list for unknown IDs", func() {
+			// Act
+			vms, err := s.VM().GetMany(ctx, []string{"non-existent"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms).To(BeEmpty())
+		})
 	})
 
 	Context("Get", func() {
@@ -586,6 +804,47 @@ var _ = Describe("VMStore", func() {
 			Expect(vm.Issues[1].Label).To(Equal("Outdated VMware Tools"))
 		})
 
+		// Given a VM with a thin and a thick disk on a known datastore
+		// When we get it by ID
+		// Then each disk should carry its provisioning, datastore name, and datastore type
+		It("should return thin provisioning and datastore info for each disk", func() {
+			// Arrange
+			err := test.InsertVMDatastores(ctx, db)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act - vm-003 has a thin disk1 and a thick disk2 on datastore1
+			vm, err := s.VM().Get(ctx, "vm-003")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vm.Disks).To(HaveLen(2))
+			thin := map[string]bool{}
+			for _, d := range vm.Disks {
+				thin[d.File] = d.Thin
+				Expect(d.Datastore).To(Equal("datastore1"))
+				Expect(d.DiskType).To(Equal("VMFS"))
+			}
+			Expect(thin).To(Equal(map[string]bool{
+				"[datastore1] vm-003/disk1.vmdk": true,
+				"[datastore1] vm-003/disk2.vmdk": false,
+			}))
+		})
+
+		// Given a VM whose datastore is missing from vdatastore
+		// When we get it by ID
+		// Then the datastore fields should be left empty
+		It("should leave datastore info empty when the datastore is unknown", func() {
+			// Act
+			vm, err := s.VM().Get(ctx, "vm-003")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			for _, d := range vm.Disks {
+				Expect(d.Datastore).To(BeEmpty())
+				Expect(d.DiskType).To(BeEmpty())
+			}
+		})
+
 		// Given a VM with only warning concerns
 		// When we get it by ID
 		// Then it should have IsMigratable=true
@@ -690,6 +949,44 @@ var _ = Describe("VMStore", func() {
 		})
 	})
 
+	Context("ConcernsByCluster", func() {
+		// Given VMs in two clusters, one VM without concerns and one without a
+		// cluster
+		// When we read the concerns by cluster
+		// Then every VM should be listed under its cluster with its concerns
+		It("should group every VM and its concerns by cluster", func() {
+			// Arrange
+			insertVM("vm-1", "vm1", "poweredOn", "cluster-a", 4096)
+			insertVM("vm-2", "vm2", "poweredOn", "cluster-a", 4096)
+			insertVM("vm-3", "vm3", "poweredOn", "cluster-b", 4096)
+			insertVM("vm-4", "vm4", "poweredOn", "", 4096)
+			insertConcern("vm-1", "c.warn", "Warn", "Warning")
+			insertConcern("vm-1", "c.info", "Info", "Info")
+			insertConcern("vm-3", "c.crit", "Crit", "Critical")
+
+			// Act
+			concerns, err := s.VM().ConcernsByCluster(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(concerns).To(Equal(map[string][]models.VMConcerns{
+				"cluster-a": {
+					{VMID: "vm-1", Concerns: []models.VMConcern{
+						{ID: "c.info", Label: "Info", Category: "Info", Assessment: "Needs attention"},
+						{ID: "c.warn", Label: "Warn", Category: "Warning", Assessment: "Needs attention"},
+					}},
+					{VMID: "vm-2"},
+				},
+				"cluster-b": {
+					{VMID: "vm-3", Concerns: []models.VMConcern{
+						{ID: "c.crit", Label: "Crit", Category: "Critical", Assessment: "Needs attention"},
+					}},
+				},
+				"": {{VMID: "vm-4"}},
+			}))
+		})
+	})
+
 	Context("GetFolders", func() {
 		// Given VMs with different folders
 		// When we call GetFolders
@@ -912,4 +1209,371 @@ var _ = Describe("VMStore", func() {
 			})
 		})
 	})
+
+	Context("Snapshots", func() {
+		t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+		BeforeEach(func() {
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+
+			// vm-003 already carries the OPA snapshot concern
+			insertConcern("vm-003", "vmware.snapshot.detected", "VM snapshot detected", "Information")
+
+			err := s.VM().InsertSnapshots(ctx, []models.VirtualMachineSnapshot{
+				{VMID: "vm-001", Name: "after-upgrade", CreatedAt: t0.Add(time.Hour)},
+				{VMID: "vm-001", Name: "before-upgrade", CreatedAt: t0},
+				{VMID: "vm-003", Name: "nightly", CreatedAt: t0},
+				{VMID: "vm-gone", Name: "orphan", CreatedAt: t0},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		summaries := func(filters ...sq.Sqlizer) map[string]models.VirtualMachineSummary {
+			vms, err := s.VM().List(ctx, filters)
+			Expect(err).NotTo(HaveOccurred())
+			byID := make(map[string]models.VirtualMachineSummary, len(vms))
+			for _, vm := range vms {
+				byID[vm.ID] = vm
+			}
+			return byID
+		}
+
+		// Given VMs with and without snapshots
+		// When we list them
+		// Then each summary should carry its snapshot count
+		It("should expose the snapshot count in the summary", func() {
+			// Act
+			vms := summaries()
+
+			// Assert
+			Expect(vms["vm-001"].SnapshotCount).To(Equal(2))
+			Expect(vms["vm-003"].SnapshotCount).To(Equal(1))
+			Expect(vms["vm-002"].SnapshotCount).To(Equal(0))
+		})
+
+		// Given a snapshot of a VM missing from the inventory
+		// When snapshots are inserted
+		// Then it should be dropped
+		It("should drop snapshots of unknown VMs", func() {
+			// Act
+			var count int
+			err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vsnapshots WHERE "VM ID" = 'vm-gone'`).Scan(&count)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+
+		// Given VMs with and without snapshots
+		// When we filter on snapshot_count > 0
+		// Then only the VMs with snapshots should match
+		It("should filter VMs by snapshot count", func() {
+			// Arrange
+			f := store.ByFilter("snapshot_count > 0")
+			Expect(f).NotTo(BeNil())
+
+			// Act
+			vms := summaries(f)
+			count, err := s.VM().Count(ctx, f)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vms).To(HaveLen(2))
+			Expect(vms).To(HaveKey("vm-001"))
+			Expect(vms).To(HaveKey("vm-003"))
+			Expect(count).To(Equal(2))
+		})
+
+		// Given filters on the snapshot count
+		// When we filter VMs without snapshots
+		// Then the VMs with snapshots should be left out
+		It("should match VMs without snapshots on snapshot_count = 0", func() {
+			// Act
+			vms := summaries(store.ByFilter("snapshot_count = 0"))
+
+			// Assert
+			Expect(vms).NotTo(HaveKey("vm-001"))
+			Expect(vms).NotTo(HaveKey("vm-003"))
+			Expect(vms).To(HaveKey("vm-002"))
+		})
+
+		// Given a VM with snapshots and no snapshot concern
+		// When snapshots are inserted
+		// Then it should get the snapshot concern once, as Information
+		It("should add the snapshot concern when none exists", func() {
+			// Act
+			vm, err := s.VM().Get(ctx, "vm-001")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vm.Issues).To(ContainElement(models.Issue{
+				ID:          "vmware.snapshot.detected",
+				Label:       "VM snapshot detected",
+				Description: "Online snapshots are not currently supported by OpenShift Virtualization. VM will be migrated with current snapshot.",
+				Category:    "Information",
+			}))
+			Expect(vm.IsMigratable).To(BeTrue())
+		})
+
+		// Given a VM whose snapshot concern came from the OPA policies
+		// When snapshots are inserted
+		// Then the concern should not be duplicated
+		It("should not duplicate an existing snapshot concern", func() {
+			// Act
+			var count int
+			err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM concerns WHERE "VM_ID" = 'vm-003' AND "Concern_ID" = 'vmware.snapshot.detected'`).Scan(&count)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+		})
+
+		// Given a VM with snapshots
+		// When we get its details
+		// Then the snapshots should be listed oldest first
+		It("should return the snapshots in the VM details", func() {
+			// Act
+			vm, err := s.VM().Get(ctx, "vm-001")
+			many, manyErr := s.VM().GetMany(ctx, []string{"vm-002", "vm-001"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vm.Snapshots).To(HaveLen(2))
+			Expect(vm.Snapshots[0].Name).To(Equal("before-upgrade"))
+			Expect(vm.Snapshots[0].CreatedAt.Equal(t0)).To(BeTrue())
+			Expect(vm.Snapshots[1].Name).To(Equal("after-upgrade"))
+
+			Expect(manyErr).NotTo(HaveOccurred())
+			Expect(many).To(HaveLen(2))
+			Expect(many[0].Snapshots).To(BeEmpty())
+			Expect(many[1].Snapshots).To(HaveLen(2))
+		})
+
+		// Given stored snapshots
+		// When the inventory is cleared for a new collection
+		// Then the snapshots should be cleared too
+		It("should clear snapshots with the inventory", func() {
+			// Act
+			err := s.ClearInventory(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			var count int
+			Expect(db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vsnapshots`).Scan(&count)).To(Succeed())
+			Expect(count).To(BeZero())
+		})
+	})
+
+	Context("Creation dates", func() {
+		BeforeEach(func() {
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+
+			err := s.VM().InsertCreateDates(ctx, map[string]time.Time{
+				"vm-001":  time.Date(2019, 3, 14, 10, 0, 0, 0, time.UTC),
+				"vm-002":  time.Date(2023, 11, 20, 16, 45, 0, 0, time.UTC),
+				"vm-003":  time.Date(2024, 5, 2, 9, 15, 0, 0, time.UTC),
+				"vm-gone": time.Date(2024, 5, 2, 9, 15, 0, 0, time.UTC),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		ids := func(vms []models.VirtualMachineSummary) []string {
+			out := make([]string, 0, len(vms))
+			for _, vm := range vms {
+				out = append(out, vm.ID)
+			}
+			return out
+		}
+
+		// Given VMs created over several years
+		// When we filter on a creation date threshold
+		// Then only the VMs created before it should match
+		It("should filter VMs created before a date", func() {
+			// Arrange
+			f := store.ByFilter("created < '2024-01-01'")
+			Expect(f).NotTo(BeNil())
+
+			// Act
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{f})
+			count, countErr := s.VM().Count(ctx, f)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(vms)).To(ConsistOf("vm-001", "vm-002"))
+			Expect(countErr).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+		})
+
+		// Given VMs with and without a creation date
+		// When we filter on created after a timestamp
+		// Then VMs without a date should never match
+		It("should skip VMs without a creation date", func() {
+			// Act
+			vms, err := s.VM().List(ctx, []sq.Sqlizer{store.ByFilter("created >= '2023-11-20T16:45:00Z'")})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(vms)).To(ConsistOf("vm-002", "vm-003"))
+		})
+
+		// Given a creation date of a VM missing from the inventory
+		// When creation dates are inserted
+		// Then it should be dropped
+		It("should drop creation dates of unknown VMs", func() {
+			// Act
+			var count int
+			err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vcreation WHERE "VM ID" = 'vm-gone'`).Scan(&count)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+
+		// Given stored creation dates
+		// When the inventory is cleared for a new collection
+		// Then the creation dates should be cleared too
+		It("should clear creation dates with the inventory", func() {
+			// Act
+			err := s.ClearInventory(ctx)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			var count int
+			Expect(db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vcreation`).Scan(&count)).To(Succeed())
+			Expect(count).To(BeZero())
+		})
+	})
+
+	Context("Disk types", func() {
+		BeforeEach(func() {
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+			Expect(test.InsertVMDatastores(ctx, db)).To(Succeed())
+
+			// vm-003 has two 500 MiB disks on the VMFS datastore1; give it a
+			// disk on an NFS datastore and one on a datastore vCenter did not report.
+			_, err := db.ExecContext(ctx, `
+				INSERT INTO vdatastore ("Name", "Object ID", "Type") VALUES ('nfs1', 'datastore-002', 'NFS')
+			`)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.ExecContext(ctx, `
+				INSERT INTO vdisk ("VM ID", "Capacity MiB", "Path") VALUES
+				('vm-003', 1536, '[nfs1] vm-003/disk3.vmdk'),
+				('vm-003', 4096, '[gone] vm-003/disk4.vmdk')
+			`)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		// Given a VM with disks on a VMFS and an NFS datastore
+		// When we list VMs
+		// Then its summary should carry the capacity per datastore type in GB
+		It("should sum the disk capacity per datastore type in the summary", func() {
+			// Act
+			vms, err := s.VM().List(ctx, nil)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			byID := make(map[string]models.VirtualMachineSummary, len(vms))
+			for _, vm := range vms {
+				byID[vm.ID] = vm
+			}
+			Expect(byID["vm-003"].DiskTypes).To(Equal(map[string]float64{"VMFS": 0.98, "NFS": 1.5}))
+			Expect(byID["vm-001"].DiskTypes).To(Equal(map[string]float64{"VMFS": 0.1}))
+		})
+
+		// Given a VM with disks on several datastore types
+		// When we get its details, alone or with other VMs
+		// Then the same breakdown should be returned
+		It("should return the breakdown in the VM details", func() {
+			// Act
+			vm, err := s.VM().Get(ctx, "vm-003")
+			many, manyErr := s.VM().GetMany(ctx, []string{"vm-003", "vm-004"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vm.DiskTypes).To(Equal(map[string]float64{"VMFS": 0.98, "NFS": 1.5}))
+			Expect(manyErr).NotTo(HaveOccurred())
+			Expect(many).To(HaveLen(2))
+			Expect(many[0].DiskTypes).To(Equal(vm.DiskTypes))
+			Expect(many[1].DiskTypes).To(Equal(map[string]float64{"VMFS": 0.98}))
+		})
+
+		// Given a page of VMs without disks on a known datastore
+		// When we list them
+		// Then they should have no breakdown
+		It("should leave the breakdown empty without known datastores", func() {
+			// Arrange
+			_, err := db.ExecContext(ctx, `DELETE FROM vdatastore`)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			vms, err := s.VM().List(ctx, nil)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			for _, vm := range vms {
+				Expect(vm.DiskTypes).To(BeEmpty())
+			}
+		})
+	})
+
+	Context("ReplaceConcerns", func() {
+		BeforeEach(func() {
+			Expect(test.InsertVMs(ctx, db)).To(Succeed())
+
+			insertConcern("vm-002", "policy.stale", "Stale concern", "Critical")
+			Expect(s.VM().InsertSnapshots(ctx, []models.VirtualMachineSnapshot{
+				{VMID: "vm-001", Name: "nightly", CreatedAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)},
+			})).To(Succeed())
+		})
+
+		concernIDs := func(vmID string) []string {
+			rows, err := db.QueryContext(ctx, `SELECT "Concern_ID" FROM concerns WHERE "VM_ID" = ? ORDER BY "Concern_ID"`, vmID)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = rows.Close() }()
+
+			var ids []string
+			for rows.Next() {
+				var id string
+				Expect(rows.Scan(&id)).To(Succeed())
+				ids = append(ids, id)
+			}
+			Expect(rows.Err()).NotTo(HaveOccurred())
+			return ids
+		}
+
+		// Given stored concerns computed by older policies
+		// When the concerns are replaced with new ones for two VMs
+		// Then only those VMs should carry concerns, plus the snapshot concern
+		It("should replace the stored concerns", func() {
+			// Arrange
+			concern := duckdb_models.Concern{Id: "policy.new", Label: "New concern", Category: "Warning", Assessment: "Check it"}
+
+			// Act
+			count, err := s.VM().ReplaceConcerns(ctx, map[string][]duckdb_models.Concern{
+				"vm-001": {concern},
+				"vm-003": {concern},
+			})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(3))
+			Expect(concernIDs("vm-002")).To(BeEmpty())
+			Expect(concernIDs("vm-003")).To(Equal([]string{"policy.new"}))
+			Expect(concernIDs("vm-001")).To(Equal([]string{"policy.new", "vmware.snapshot.detected"}))
+		})
+
+		// Given stored concerns
+		// When the concerns are replaced with none
+		// Then only the snapshot concern should remain
+		It("should keep the snapshot concern when no concerns are given", func() {
+			// Act
+			count, err := s.VM().ReplaceConcerns(ctx, nil)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+			Expect(concernIDs("vm-002")).To(BeEmpty())
+			Expect(concernIDs("vm-001")).To(Equal([]string{"vmware.snapshot.detected"}))
+		})
+	})
 })
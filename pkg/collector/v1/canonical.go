@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+)
+
+// MarshalInventory encodes inv as canonical JSON: object keys sorted at every
+// level, including those produced by custom marshalers, and no insignificant
+// whitespace. Identical inventories always encode to the same bytes.
+func MarshalInventory(inv v1alpha1.Inventory) ([]byte, error) {
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(data)
+}
+
+// Canonicalize re-encodes the JSON document data in canonical form. Numbers
+// are kept as written.
+func Canonicalize(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// InventoryHash returns the hex SHA-256 of the canonical form of the JSON
+// inventory data, so inventories differing only in key order or formatting
+// hash the same.
+func InventoryHash(data []byte) (string, error) {
+	canonical, err := Canonicalize(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
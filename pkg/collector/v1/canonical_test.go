@@ -0,0 +1,86 @@
+package v1_test
+
+import (
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
+)
+
+var _ = Describe("Canonical inventory JSON", func() {
+	newInventory := func() v1alpha1.Inventory {
+		vcenter := v1alpha1.InventoryData{
+			Vms: v1alpha1.VMs{
+				Total:       3,
+				PowerStates: map[string]int{"poweredOn": 2, "poweredOff": 1, "suspended": 0},
+			},
+		}
+		return v1alpha1.Inventory{
+			VcenterId: "vcenter-1",
+			Vcenter:   &vcenter,
+			Clusters: map[string]v1alpha1.InventoryData{
+				"cluster-c": {},
+				"cluster-a": {},
+				"cluster-b": {},
+			},
+		}
+	}
+
+	// Given the same inventory
+	// When we marshal it twice
+	// Then both encodings should be byte-identical
+	It("should marshal the same inventory to identical bytes", func() {
+		// Act
+		first, err := collectorV1.MarshalInventory(newInventory())
+		Expect(err).NotTo(HaveOccurred())
+		second, err := collectorV1.MarshalInventory(newInventory())
+		Expect(err).NotTo(HaveOccurred())
+
+		// Assert
+		Expect(second).To(Equal(first))
+	})
+
+	// Given two encodings of the same data with different key order and spacing
+	// When we hash them
+	// Then the hashes should match
+	It("should hash documents differing only in key order and spacing the same", func() {
+		// Arrange
+		a := []byte(`{"vcenter_id":"vc-1","clusters":{"b":{},"a":{}},"size":1.50}`)
+		b := []byte(`{ "size": 1.50, "clusters": {"a": {}, "b": {}}, "vcenter_id": "vc-1" }`)
+
+		// Act
+		hashA, err := collectorV1.InventoryHash(a)
+		Expect(err).NotTo(HaveOccurred())
+		hashB, err := collectorV1.InventoryHash(b)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Assert
+		Expect(hashA).To(Equal(hashB))
+	})
+
+	// Given two different documents
+	// When we hash them
+	// Then the hashes should differ
+	It("should hash different documents differently", func() {
+		// Act
+		hashA, err := collectorV1.InventoryHash([]byte(`{"vcenter_id":"vc-1"}`))
+		Expect(err).NotTo(HaveOccurred())
+		hashB, err := collectorV1.InventoryHash([]byte(`{"vcenter_id":"vc-2"}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		// Assert
+		Expect(hashA).NotTo(Equal(hashB))
+	})
+
+	// Given invalid JSON
+	// When we hash it
+	// Then an error should be returned
+	It("should fail on invalid JSON", func() {
+		// Act
+		_, err := collectorV1.InventoryHash([]byte(`{`))
+
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+})
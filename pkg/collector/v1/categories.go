@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+// ConcernBucket is where the inventory counts the concerns of a category.
+type ConcernBucket string
+
+const (
+	// BucketCritical lists the concern in notMigratableReasons and counts its
+	// VMs as not migratable.
+	BucketCritical ConcernBucket = "critical"
+	// BucketWarning lists the concern in migrationWarnings and counts its VMs
+	// in totalMigratableWithWarnings.
+	BucketWarning ConcernBucket = "warning"
+	// BucketIgnore leaves the concern out of the inventory.
+	BucketIgnore ConcernBucket = "ignore"
+)
+
+// ConcernBuckets lists every bucket a category can be mapped to.
+var ConcernBuckets = []ConcernBucket{BucketCritical, BucketWarning, BucketIgnore}
+
+// ConcernCategories maps concern categories, such as those OPA policies set,
+// to buckets. A category it does not list keeps the parser's handling:
+// "Critical" and "Warning" go to their buckets and any other is ignored.
+type ConcernCategories map[string]ConcernBucket
+
+// ParseConcernCategories parses category=bucket pairs such as Info=warning.
+// Categories are case-sensitive, as the parser matches them; buckets are
+// trimmed and must be known. No entries yields nil, which keeps the parser's
+// handling.
+func ParseConcernCategories(entries map[string]string) (ConcernCategories, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	categories := make(ConcernCategories, len(entries))
+	for category, value := range entries {
+		category = strings.TrimSpace(category)
+		if category == "" {
+			return nil, fmt.Errorf("concern category %q: missing category", category+"="+value)
+		}
+		bucket := ConcernBucket(strings.ToLower(strings.TrimSpace(value)))
+		if !slices.Contains(ConcernBuckets, bucket) {
+			return nil, fmt.Errorf("concern category %q: unknown bucket %q: must be one of %s", category, value, concernBucketNames())
+		}
+		categories[category] = bucket
+	}
+	return categories, nil
+}
+
+func concernBucketNames() string {
+	names := make([]string, 0, len(ConcernBuckets))
+	for _, b := range ConcernBuckets {
+		names = append(names, string(b))
+	}
+	return strings.Join(names, ", ")
+}
+
+// Bucket returns the bucket the concerns of category are counted in.
+func (c ConcernCategories) Bucket(category string) ConcernBucket {
+	if bucket, ok := c[category]; ok {
+		return bucket
+	}
+	switch category {
+	case "Critical":
+		return BucketCritical
+	case "Warning":
+		return BucketWarning
+	default:
+		return BucketIgnore
+	}
+}
+
+// ApplyConcernCategories recomputes the migration warnings, not-migratable
+// reasons, totalMigratable and totalMigratableWithWarnings of the vCenter-wide
+// aggregate and of each cluster from the per-VM concerns, with their
+// categories mapped by categories. Like the parser, issues are grouped by
+// concern ID and count concerns, a VM with a critical concern is not
+// migratable and one with a warning counts with warnings whether or not it is
+// also critical. Issues are ordered by ID.
+//
+// clusterVMs is keyed by cluster ID, as inv.Clusters is, and holds every VM
+// of the cluster; a cluster missing from it is left as it is. The resource
+// breakdowns keep the parser's Critical and Warning split. inv is not
+// modified.
+func ApplyConcernCategories(inv v1alpha1.Inventory, vcenterVMs []models.VMConcerns, clusterVMs map[string][]models.VMConcerns, categories ConcernCategories) v1alpha1.Inventory {
+	if inv.Vcenter != nil {
+		vcenter := *inv.Vcenter
+		vcenter.Vms = applyConcernCategories(vcenter.Vms, vcenterVMs, categories)
+		inv.Vcenter = &vcenter
+	}
+
+	if inv.Clusters != nil {
+		clusters := make(map[string]v1alpha1.InventoryData, len(inv.Clusters))
+		for id, data := range inv.Clusters {
+			if vms, ok := clusterVMs[id]; ok {
+				data.Vms = applyConcernCategories(data.Vms, vms, categories)
+			}
+			clusters[id] = data
+		}
+		inv.Clusters = clusters
+	}
+	return inv
+}
+
+func applyConcernCategories(data v1alpha1.VMs, vms []models.VMConcerns, categories ConcernCategories) v1alpha1.VMs {
+	warnings := map[string]*v1alpha1.MigrationIssue{}
+	critical := map[string]*v1alpha1.MigrationIssue{}
+	notMigratable, withWarnings := 0, 0
+	for _, vm := range vms {
+		hasCritical, hasWarning := false, false
+		for _, concern := range vm.Concerns {
+			var issues map[string]*v1alpha1.MigrationIssue
+			switch categories.Bucket(concern.Category) {
+			case BucketCritical:
+				issues, hasCritical = critical, true
+			case BucketWarning:
+				issues, hasWarning = warnings, true
+			default:
+				continue
+			}
+			issue, ok := issues[concern.ID]
+			if !ok {
+				id := concern.ID
+				issue = &v1alpha1.MigrationIssue{Id: &id, Label: concern.Label, Assessment: concern.Assessment}
+				issues[concern.ID] = issue
+			}
+			issue.Count++
+		}
+		if hasCritical {
+			notMigratable++
+		}
+		if hasWarning {
+			withWarnings++
+		}
+	}
+
+	data.MigrationWarnings = sortedIssues(warnings)
+	data.NotMigratableReasons = sortedIssues(critical)
+	data.TotalMigratable = len(vms) - notMigratable
+	data.TotalMigratableWithWarnings = &withWarnings
+	return data
+}
+
+func sortedIssues(issues map[string]*v1alpha1.MigrationIssue) []v1alpha1.MigrationIssue {
+	ids := make([]string, 0, len(issues))
+	for id := range issues {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	result := make([]v1alpha1.MigrationIssue, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, *issues[id])
+	}
+	return result
+}
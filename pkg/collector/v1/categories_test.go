@@ -0,0 +1,167 @@
+package v1_test
+
+import (
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
+)
+
+var _ = Describe("ConcernCategories", func() {
+	Context("ParseConcernCategories", func() {
+		// Given category=bucket pairs with spaces and mixed-case buckets
+		// When we parse them
+		// Then categories and buckets should be trimmed and buckets lowered
+		It("should parse the mapping", func() {
+			// Act
+			categories, err := collectorV1.ParseConcernCategories(map[string]string{" Info ": "Warning", "Notice": " ignore"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(categories).To(Equal(collectorV1.ConcernCategories{
+				"Info":   collectorV1.BucketWarning,
+				"Notice": collectorV1.BucketIgnore,
+			}))
+		})
+
+		// Given no pairs
+		// When we parse them
+		// Then the parser's handling should be kept
+		It("should return nil without entries", func() {
+			// Act
+			categories, err := collectorV1.ParseConcernCategories(nil)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(categories).To(BeNil())
+		})
+
+		// Given malformed pairs
+		// When we parse them
+		// Then each should be rejected
+		It("should reject invalid pairs", func() {
+			for _, entries := range []map[string]string{
+				{"": "warning"},      // missing category
+				{"Info": ""},         // missing bucket
+				{"Info": "notice"},   // unknown bucket
+				{"Info": "warnings"}, // unknown bucket
+			} {
+				_, err := collectorV1.ParseConcernCategories(entries)
+				Expect(err).To(HaveOccurred(), "%v", entries)
+			}
+		})
+	})
+
+	Context("Bucket", func() {
+		// Given a mapping for Info and Warning
+		// When we look up categories
+		// Then mapped ones should use the mapping and the others the parser's
+		// handling
+		It("should fall back to the parser's handling", func() {
+			// Arrange
+			categories := collectorV1.ConcernCategories{"Info": collectorV1.BucketWarning, "Warning": collectorV1.BucketIgnore}
+
+			// Act & Assert
+			Expect(categories.Bucket("Info")).To(Equal(collectorV1.BucketWarning))
+			Expect(categories.Bucket("Warning")).To(Equal(collectorV1.BucketIgnore))
+			Expect(categories.Bucket("Critical")).To(Equal(collectorV1.BucketCritical))
+			Expect(categories.Bucket("Notice")).To(Equal(collectorV1.BucketIgnore))
+			Expect(collectorV1.ConcernCategories(nil).Bucket("Warning")).To(Equal(collectorV1.BucketWarning))
+		})
+	})
+
+	Context("ApplyConcernCategories", func() {
+		concern := func(id, category string) models.VMConcern {
+			return models.VMConcern{ID: id, Label: id + " label", Category: category, Assessment: id + " assessment"}
+		}
+		issue := func(id string, count int) v1alpha1.MigrationIssue {
+			return v1alpha1.MigrationIssue{Id: &id, Label: id + " label", Assessment: id + " assessment", Count: count}
+		}
+		// vm-1 has an Info and a Warning concern, vm-2 a Critical and an
+		// Info one, vm-3 two disks with the same Info concern and vm-4 none.
+		vms := []models.VMConcerns{
+			{VMID: "vm-1", Concerns: []models.VMConcern{concern("info.a", "Info"), concern("warn.a", "Warning")}},
+			{VMID: "vm-2", Concerns: []models.VMConcern{concern("crit.a", "Critical"), concern("info.a", "Info")}},
+			{VMID: "vm-3", Concerns: []models.VMConcern{concern("info.b", "Info"), concern("info.b", "Info")}},
+			{VMID: "vm-4"},
+		}
+		parserWarnings := []v1alpha1.MigrationIssue{issue("warn.a", 1)}
+		newInventory := func() v1alpha1.Inventory {
+			withWarnings := 1
+			data := v1alpha1.InventoryData{Vms: v1alpha1.VMs{
+				Total:                       4,
+				TotalMigratable:             3,
+				TotalMigratableWithWarnings: &withWarnings,
+				MigrationWarnings:           parserWarnings,
+				NotMigratableReasons:        []v1alpha1.MigrationIssue{issue("crit.a", 1)},
+			}}
+			return v1alpha1.Inventory{
+				Vcenter:  &data,
+				Clusters: map[string]v1alpha1.InventoryData{"domain-c1": data, "domain-c2": data},
+			}
+		}
+
+		// Given Info concerns mapped to warnings
+		// When we apply the mapping
+		// Then Info issues should be listed with the warnings, counted per
+		// concern, and the VMs with them counted with warnings
+		It("should route a custom category to the warnings", func() {
+			// Act
+			result := collectorV1.ApplyConcernCategories(newInventory(), vms, nil, collectorV1.ConcernCategories{"Info": collectorV1.BucketWarning})
+
+			// Assert
+			Expect(result.Vcenter.Vms.MigrationWarnings).To(Equal([]v1alpha1.MigrationIssue{
+				issue("info.a", 2), issue("info.b", 2), issue("warn.a", 1),
+			}))
+			Expect(result.Vcenter.Vms.NotMigratableReasons).To(Equal([]v1alpha1.MigrationIssue{issue("crit.a", 1)}))
+			Expect(result.Vcenter.Vms.TotalMigratable).To(Equal(3))
+			Expect(*result.Vcenter.Vms.TotalMigratableWithWarnings).To(Equal(3))
+			Expect(result.Vcenter.Vms.Total).To(Equal(4))
+		})
+
+		// Given Info concerns mapped to critical and Warning ones ignored
+		// When we apply the mapping
+		// Then Info issues should make their VMs not migratable and the
+		// warnings should be dropped
+		It("should route categories to critical or drop them", func() {
+			// Act
+			result := collectorV1.ApplyConcernCategories(newInventory(), vms, nil, collectorV1.ConcernCategories{
+				"Info":    collectorV1.BucketCritical,
+				"Warning": collectorV1.BucketIgnore,
+			})
+
+			// Assert
+			Expect(result.Vcenter.Vms.MigrationWarnings).To(BeEmpty())
+			Expect(result.Vcenter.Vms.NotMigratableReasons).To(Equal([]v1alpha1.MigrationIssue{
+				issue("crit.a", 1), issue("info.a", 2), issue("info.b", 2),
+			}))
+			Expect(result.Vcenter.Vms.TotalMigratable).To(Equal(1))
+			Expect(*result.Vcenter.Vms.TotalMigratableWithWarnings).To(Equal(0))
+		})
+
+		// Given per-cluster concerns for one of two clusters
+		// When we apply the mapping
+		// Then the known cluster should be recomputed, the other left as it
+		// is, and the input should not change
+		It("should recompute the clusters it has VMs for", func() {
+			// Arrange
+			inv := newInventory()
+
+			// Act
+			result := collectorV1.ApplyConcernCategories(inv, vms, map[string][]models.VMConcerns{"domain-c1": vms[2:]}, collectorV1.ConcernCategories{"Info": collectorV1.BucketWarning})
+
+			// Assert
+			c1 := result.Clusters["domain-c1"].Vms
+			Expect(c1.MigrationWarnings).To(Equal([]v1alpha1.MigrationIssue{issue("info.b", 2)}))
+			Expect(c1.NotMigratableReasons).To(BeEmpty())
+			Expect(c1.TotalMigratable).To(Equal(2))
+			Expect(*c1.TotalMigratableWithWarnings).To(Equal(1))
+
+			Expect(result.Clusters["domain-c2"]).To(Equal(inv.Clusters["domain-c2"]))
+			Expect(inv.Vcenter.Vms.MigrationWarnings).To(Equal(parserWarnings))
+			Expect(*inv.Clusters["domain-c1"].Vms.TotalMigratableWithWarnings).To(Equal(1))
+		})
+	})
+})
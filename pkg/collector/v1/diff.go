@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"sort"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+// DiffInventories compares the per-VM snapshots of two collections. VMs are
+// matched by ID; a VM present in both is reported only if its CPU count,
+// memory or total disk size changed.
+func DiffInventories(previous, current models.InventorySnapshot) models.InventoryDiff {
+	diff := models.InventoryDiff{
+		PreviousCollectedAt: previous.TakenAt,
+		CurrentCollectedAt:  current.TakenAt,
+		Added:               []string{},
+		Removed:             []string{},
+		Changed:             []models.VMDelta{},
+	}
+
+	before := make(map[string]models.VMSnapshot, len(previous.VMs))
+	for _, vm := range previous.VMs {
+		before[vm.ID] = vm
+	}
+
+	seen := make(map[string]bool, len(current.VMs))
+	for _, vm := range current.VMs {
+		seen[vm.ID] = true
+		old, ok := before[vm.ID]
+		if !ok {
+			diff.Added = append(diff.Added, vm.ID)
+			continue
+		}
+
+		delta := models.VMDelta{
+			ID:       vm.ID,
+			Name:     vm.Name,
+			CPUs:     fieldDelta(int64(old.CPUs), int64(vm.CPUs)),
+			MemoryMB: fieldDelta(int64(old.MemoryMB), int64(vm.MemoryMB)),
+			DiskMB:   fieldDelta(old.DiskMB, vm.DiskMB),
+		}
+		if delta.CPUs != nil || delta.MemoryMB != nil || delta.DiskMB != nil {
+			diff.Changed = append(diff.Changed, delta)
+		}
+	}
+
+	for _, vm := range previous.VMs {
+		if !seen[vm.ID] {
+			diff.Removed = append(diff.Removed, vm.ID)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].ID < diff.Changed[j].ID })
+
+	return diff
+}
+
+func fieldDelta(previous, current int64) *models.FieldDelta {
+	if previous == current {
+		return nil
+	}
+	return &models.FieldDelta{Previous: previous, Current: current}
+}
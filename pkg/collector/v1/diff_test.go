@@ -0,0 +1,101 @@
+package v1_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
+)
+
+var _ = Describe("DiffInventories", func() {
+	var (
+		previousAt = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+		currentAt  = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	)
+
+	// Given two collections where VMs were added, removed and resized
+	// When we diff them
+	// Then each change should be reported once, with only the changed fields
+	It("should report added, removed and changed VMs", func() {
+		// Arrange
+		previous := models.InventorySnapshot{
+			TakenAt: previousAt,
+			VMs: []models.VMSnapshot{
+				{ID: "vm-1", Name: "web", CPUs: 2, MemoryMB: 4096, DiskMB: 20480},
+				{ID: "vm-2", Name: "db", CPUs: 4, MemoryMB: 8192, DiskMB: 102400},
+				{ID: "vm-3", Name: "old", CPUs: 1, MemoryMB: 1024, DiskMB: 10240},
+				{ID: "vm-4", Name: "cache", CPUs: 2, MemoryMB: 2048, DiskMB: 10240},
+			},
+		}
+		current := models.InventorySnapshot{
+			TakenAt: currentAt,
+			VMs: []models.VMSnapshot{
+				{ID: "vm-5", Name: "new", CPUs: 2, MemoryMB: 2048, DiskMB: 10240},
+				{ID: "vm-2", Name: "db", CPUs: 8, MemoryMB: 8192, DiskMB: 204800},
+				{ID: "vm-1", Name: "web", CPUs: 2, MemoryMB: 4096, DiskMB: 20480},
+				{ID: "vm-4", Name: "cache", CPUs: 2, MemoryMB: 4096, DiskMB: 10240},
+			},
+		}
+
+		// Act
+		diff := collectorV1.DiffInventories(previous, current)
+
+		// Assert
+		Expect(diff.PreviousCollectedAt).To(Equal(previousAt))
+		Expect(diff.CurrentCollectedAt).To(Equal(currentAt))
+		Expect(diff.Added).To(Equal([]string{"vm-5"}))
+		Expect(diff.Removed).To(Equal([]string{"vm-3"}))
+		Expect(diff.Changed).To(Equal([]models.VMDelta{
+			{
+				ID:     "vm-2",
+				Name:   "db",
+				CPUs:   &models.FieldDelta{Previous: 4, Current: 8},
+				DiskMB: &models.FieldDelta{Previous: 102400, Current: 204800},
+			},
+			{
+				ID:       "vm-4",
+				Name:     "cache",
+				MemoryMB: &models.FieldDelta{Previous: 2048, Current: 4096},
+			},
+		}))
+	})
+
+	// Given two identical collections
+	// When we diff them
+	// Then the diff should be empty but not nil
+	It("should return empty lists when nothing changed", func() {
+		// Arrange
+		vms := []models.VMSnapshot{{ID: "vm-1", Name: "web", CPUs: 2, MemoryMB: 4096, DiskMB: 20480}}
+
+		// Act
+		diff := collectorV1.DiffInventories(
+			models.InventorySnapshot{TakenAt: previousAt, VMs: vms},
+			models.InventorySnapshot{TakenAt: currentAt, VMs: vms},
+		)
+
+		// Assert
+		Expect(diff.Added).To(BeEmpty())
+		Expect(diff.Added).NotTo(BeNil())
+		Expect(diff.Removed).To(BeEmpty())
+		Expect(diff.Changed).To(BeEmpty())
+	})
+
+	// Given a VM that was renamed without resource changes
+	// When we diff the collections
+	// Then it should not be reported as changed
+	It("should match VMs by ID and ignore renames", func() {
+		// Act
+		diff := collectorV1.DiffInventories(
+			models.InventorySnapshot{VMs: []models.VMSnapshot{{ID: "vm-1", Name: "web", CPUs: 2}}},
+			models.InventorySnapshot{VMs: []models.VMSnapshot{{ID: "vm-1", Name: "frontend", CPUs: 2}}},
+		)
+
+		// Assert
+		Expect(diff.Added).To(BeEmpty())
+		Expect(diff.Removed).To(BeEmpty())
+		Expect(diff.Changed).To(BeEmpty())
+	})
+})
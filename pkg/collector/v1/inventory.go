@@ -0,0 +1,26 @@
+// Package v1 converts inventories to the legacy single-inventory (non-clustered)
+// schema still expected by older backend versions.
+package v1
+
+import (
+	"github.com/google/uuid"
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+)
+
+// UpdateInventory is the legacy manual-upload payload wrapping a flat inventory.
+type UpdateInventory struct {
+	AgentId   uuid.UUID              `json:"agentId"`
+	Inventory v1alpha1.InventoryData `json:"inventory"`
+}
+
+// ToLegacyInventory projects a clustered inventory onto the flat v1 shape.
+// The vCenter-wide aggregate becomes the inventory and the vCenter ID is carried
+// in its vcenter field. Per-cluster data is dropped.
+func ToLegacyInventory(inv v1alpha1.Inventory) v1alpha1.InventoryData {
+	var legacy v1alpha1.InventoryData
+	if inv.Vcenter != nil {
+		legacy = *inv.Vcenter
+	}
+	legacy.Vcenter = &v1alpha1.VCenter{Id: inv.VcenterId}
+	return legacy
+}
@@ -0,0 +1,85 @@
+package v1_test
+
+import (
+	"encoding/json"
+
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
+)
+
+var _ = Describe("ToLegacyInventory", func() {
+	// Given a clustered inventory with a vCenter-wide aggregate
+	// When we project it onto the v1 schema
+	// Then it should return the aggregate with the vCenter ID attached
+	It("should project the vCenter aggregate onto the flat inventory", func() {
+		// Arrange
+		inv := v1alpha1.Inventory{
+			VcenterId: "vc-1",
+			Vcenter: &v1alpha1.InventoryData{
+				Infra: v1alpha1.Infra{
+					TotalHosts: 3,
+					Datastores: []v1alpha1.Datastore{{Type: "VMFS", TotalCapacityGB: 100}},
+					Networks:   []v1alpha1.Network{{Name: "VM Network", Type: "standard"}},
+				},
+				Vms: v1alpha1.VMs{Total: 10, TotalMigratable: 8},
+			},
+			Clusters: map[string]v1alpha1.InventoryData{
+				"cluster-a": {Infra: v1alpha1.Infra{TotalHosts: 1}, Vms: v1alpha1.VMs{Total: 4}},
+				"cluster-b": {Infra: v1alpha1.Infra{TotalHosts: 2}, Vms: v1alpha1.VMs{Total: 6}},
+			},
+		}
+
+		// Act
+		legacy := collectorV1.ToLegacyInventory(inv)
+
+		// Assert
+		Expect(legacy.Vcenter).NotTo(BeNil())
+		Expect(legacy.Vcenter.Id).To(Equal("vc-1"))
+		Expect(legacy.Infra.TotalHosts).To(Equal(3))
+		Expect(legacy.Infra.Datastores).To(HaveLen(1))
+		Expect(legacy.Infra.Networks).To(HaveLen(1))
+		Expect(legacy.Vms.Total).To(Equal(10))
+		Expect(legacy.Vms.TotalMigratable).To(Equal(8))
+	})
+
+	// Given a clustered inventory
+	// When we project it onto the v1 schema and marshal it
+	// Then the JSON should have the legacy top-level keys only
+	It("should marshal to the legacy shape", func() {
+		// Arrange
+		inv := v1alpha1.Inventory{
+			VcenterId: "vc-1",
+			Vcenter:   &v1alpha1.InventoryData{Vms: v1alpha1.VMs{Total: 2}},
+			Clusters:  map[string]v1alpha1.InventoryData{"cluster-a": {}},
+		}
+
+		// Act
+		data, err := json.Marshal(collectorV1.ToLegacyInventory(inv))
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		var raw map[string]any
+		Expect(json.Unmarshal(data, &raw)).To(Succeed())
+		Expect(raw).To(HaveKey("infra"))
+		Expect(raw).To(HaveKey("vms"))
+		Expect(raw).To(HaveKeyWithValue("vcenter", map[string]any{"id": "vc-1"}))
+		Expect(raw).NotTo(HaveKey("clusters"))
+		Expect(raw).NotTo(HaveKey("vcenter_id"))
+	})
+
+	// Given a clustered inventory without a vCenter aggregate
+	// When we project it onto the v1 schema
+	// Then it should return an empty inventory carrying only the vCenter ID
+	It("should return an empty inventory when the vCenter aggregate is missing", func() {
+		// Act
+		legacy := collectorV1.ToLegacyInventory(v1alpha1.Inventory{VcenterId: "vc-2"})
+
+		// Assert
+		Expect(legacy.Vcenter).NotTo(BeNil())
+		Expect(legacy.Vcenter.Id).To(Equal("vc-2"))
+		Expect(legacy.Vms.Total).To(Equal(0))
+	})
+})
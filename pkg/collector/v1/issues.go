@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"slices"
+
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+)
+
+// SuppressIssues returns a copy of inv without the migration warnings and
+// not-migratable reasons whose ID is in ids, in the vCenter aggregate and in
+// every cluster. Issues without an ID are kept. inv is not modified.
+//
+// Total, TotalMigratable and TotalMigratableWithWarnings are deliberately left
+// as collected, so they still count suppressed concerns: the issue lists hold
+// only per-concern counts, which cannot tell how many VMs would change state,
+// and inv may be a history snapshot that the current VM table does not match.
+// The VM list endpoints apply suppression to per-VM migratability instead.
+func SuppressIssues(inv v1alpha1.Inventory, ids []string) v1alpha1.Inventory {
+	if len(ids) == 0 {
+		return inv
+	}
+
+	if inv.Vcenter != nil {
+		vcenter := suppressDataIssues(*inv.Vcenter, ids)
+		inv.Vcenter = &vcenter
+	}
+
+	if inv.Clusters != nil {
+		clusters := make(map[string]v1alpha1.InventoryData, len(inv.Clusters))
+		for name, cluster := range inv.Clusters {
+			clusters[name] = suppressDataIssues(cluster, ids)
+		}
+		inv.Clusters = clusters
+	}
+
+	return inv
+}
+
+func suppressDataIssues(data v1alpha1.InventoryData, ids []string) v1alpha1.InventoryData {
+	data.Vms.MigrationWarnings = withoutIssues(data.Vms.MigrationWarnings, ids)
+	data.Vms.NotMigratableReasons = withoutIssues(data.Vms.NotMigratableReasons, ids)
+	return data
+}
+
+func withoutIssues(issues []v1alpha1.MigrationIssue, ids []string) []v1alpha1.MigrationIssue {
+	if issues == nil {
+		return nil
+	}
+	kept := make([]v1alpha1.MigrationIssue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Id != nil && slices.Contains(ids, *issue.Id) {
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// EnrichIssues returns a copy of inv in which the assessment of every
+// migration warning and not-migratable reason whose ID is a key of extra is
+// followed by the text extra holds for it, in the vCenter aggregate and in
+// every cluster. Counts, labels and the issues themselves are unchanged. inv
+// is not modified.
+func EnrichIssues(inv v1alpha1.Inventory, extra map[string]string) v1alpha1.Inventory {
+	if len(extra) == 0 {
+		return inv
+	}
+
+	if inv.Vcenter != nil {
+		vcenter := enrichDataIssues(*inv.Vcenter, extra)
+		inv.Vcenter = &vcenter
+	}
+
+	if inv.Clusters != nil {
+		clusters := make(map[string]v1alpha1.InventoryData, len(inv.Clusters))
+		for name, cluster := range inv.Clusters {
+			clusters[name] = enrichDataIssues(cluster, extra)
+		}
+		inv.Clusters = clusters
+	}
+
+	return inv
+}
+
+func enrichDataIssues(data v1alpha1.InventoryData, extra map[string]string) v1alpha1.InventoryData {
+	data.Vms.MigrationWarnings = enrichIssues(data.Vms.MigrationWarnings, extra)
+	data.Vms.NotMigratableReasons = enrichIssues(data.Vms.NotMigratableReasons, extra)
+	return data
+}
+
+func enrichIssues(issues []v1alpha1.MigrationIssue, extra map[string]string) []v1alpha1.MigrationIssue {
+	if issues == nil {
+		return nil
+	}
+	enriched := slices.Clone(issues)
+	for i, issue := range enriched {
+		if issue.Id != nil {
+			enriched[i].Assessment = EnrichAssessment(issue.Assessment, extra[*issue.Id])
+		}
+	}
+	return enriched
+}
+
+// EnrichAssessment returns assessment followed by extra, separated by a
+// blank line. An empty extra leaves assessment as it is.
+func EnrichAssessment(assessment, extra string) string {
+	switch {
+	case extra == "":
+		return assessment
+	case assessment == "":
+		return extra
+	default:
+		return assessment + "\n\n" + extra
+	}
+}
@@ -0,0 +1,165 @@
+package v1_test
+
+import (
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
+)
+
+var _ = Describe("SuppressIssues", func() {
+	issueID := func(id string) *string { return &id }
+
+	newData := func() v1alpha1.InventoryData {
+		return v1alpha1.InventoryData{
+			Vms: v1alpha1.VMs{
+				MigrationWarnings: []v1alpha1.MigrationIssue{
+					{Id: issueID("known.warning"), Label: "Accepted", Count: 3},
+					{Id: issueID("other.warning"), Label: "Other", Count: 1},
+					{Label: "No ID", Count: 2},
+				},
+				NotMigratableReasons: []v1alpha1.MigrationIssue{
+					{Id: issueID("known.critical"), Label: "Accepted RDM", Count: 1},
+				},
+			},
+		}
+	}
+
+	// Given an inventory with issues in the vCenter aggregate and a cluster
+	// When we suppress some issue IDs
+	// Then those issues should be gone everywhere and the others kept
+	It("should remove suppressed issues from the vCenter and every cluster", func() {
+		// Arrange
+		vcenter := newData()
+		inv := v1alpha1.Inventory{
+			Vcenter:  &vcenter,
+			Clusters: map[string]v1alpha1.InventoryData{"cluster-a": newData()},
+		}
+
+		// Act
+		result := collectorV1.SuppressIssues(inv, []string{"known.warning", "known.critical"})
+
+		// Assert
+		for _, data := range []v1alpha1.InventoryData{*result.Vcenter, result.Clusters["cluster-a"]} {
+			Expect(data.Vms.MigrationWarnings).To(HaveLen(2))
+			Expect(*data.Vms.MigrationWarnings[0].Id).To(Equal("other.warning"))
+			Expect(data.Vms.MigrationWarnings[1].Id).To(BeNil())
+			Expect(data.Vms.NotMigratableReasons).To(BeEmpty())
+		}
+	})
+
+	// Given an inventory whose totals count VMs with a suppressed concern
+	// When we suppress that concern
+	// Then the VM totals should stay as collected
+	It("should leave the migratable totals unchanged", func() {
+		// Arrange
+		withWarnings := 4
+		vcenter := newData()
+		vcenter.Vms.Total = 10
+		vcenter.Vms.TotalMigratable = 9
+		vcenter.Vms.TotalMigratableWithWarnings = &withWarnings
+		inv := v1alpha1.Inventory{Vcenter: &vcenter}
+
+		// Act
+		result := collectorV1.SuppressIssues(inv, []string{"known.warning", "known.critical"})
+
+		// Assert
+		Expect(result.Vcenter.Vms.NotMigratableReasons).To(BeEmpty())
+		Expect(result.Vcenter.Vms.Total).To(Equal(10))
+		Expect(result.Vcenter.Vms.TotalMigratable).To(Equal(9))
+		Expect(*result.Vcenter.Vms.TotalMigratableWithWarnings).To(Equal(4))
+	})
+
+	// Given an inventory with suppressible issues
+	// When we suppress them
+	// Then the original inventory should be left untouched
+	It("should not modify the input inventory", func() {
+		// Arrange
+		vcenter := newData()
+		inv := v1alpha1.Inventory{
+			Vcenter:  &vcenter,
+			Clusters: map[string]v1alpha1.InventoryData{"cluster-a": newData()},
+		}
+
+		// Act
+		_ = collectorV1.SuppressIssues(inv, []string{"known.warning"})
+
+		// Assert
+		Expect(inv.Vcenter.Vms.MigrationWarnings).To(HaveLen(3))
+		Expect(inv.Clusters["cluster-a"].Vms.MigrationWarnings).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("EnrichIssues", func() {
+	issueID := func(id string) *string { return &id }
+
+	newData := func() v1alpha1.InventoryData {
+		return v1alpha1.InventoryData{
+			Vms: v1alpha1.VMs{
+				Total: 6,
+				MigrationWarnings: []v1alpha1.MigrationIssue{
+					{Id: issueID("vmware.cbt"), Label: "CBT disabled", Assessment: "Enable CBT.", Count: 3},
+					{Id: issueID("other.warning"), Label: "Other", Assessment: "Other.", Count: 1},
+					{Label: "No ID", Assessment: "No ID.", Count: 2},
+				},
+				NotMigratableReasons: []v1alpha1.MigrationIssue{
+					{Id: issueID("vmware.rdm"), Label: "RDM disk", Count: 1},
+				},
+			},
+		}
+	}
+
+	extra := map[string]string{
+		"vmware.cbt": "See https://wiki.example.com/cbt",
+		"vmware.rdm": "Ask the storage team.",
+	}
+
+	// Given an inventory with issues in the vCenter aggregate and a cluster
+	// When we enrich it with text for some concern IDs
+	// Then those assessments should gain the text and counts and labels stay
+	It("should append the configured text to matching assessments everywhere", func() {
+		// Arrange
+		vcenter := newData()
+		inv := v1alpha1.Inventory{
+			Vcenter:  &vcenter,
+			Clusters: map[string]v1alpha1.InventoryData{"cluster-a": newData()},
+		}
+
+		// Act
+		result := collectorV1.EnrichIssues(inv, extra)
+
+		// Assert
+		for _, data := range []v1alpha1.InventoryData{*result.Vcenter, result.Clusters["cluster-a"]} {
+			Expect(data.Vms.Total).To(Equal(6))
+			Expect(data.Vms.MigrationWarnings).To(HaveLen(3))
+			Expect(data.Vms.MigrationWarnings[0].Assessment).To(Equal("Enable CBT.\n\nSee https://wiki.example.com/cbt"))
+			Expect(data.Vms.MigrationWarnings[0].Label).To(Equal("CBT disabled"))
+			Expect(data.Vms.MigrationWarnings[0].Count).To(Equal(3))
+			Expect(data.Vms.MigrationWarnings[1].Assessment).To(Equal("Other."))
+			Expect(data.Vms.MigrationWarnings[2].Assessment).To(Equal("No ID."))
+			Expect(data.Vms.NotMigratableReasons).To(HaveLen(1))
+			Expect(data.Vms.NotMigratableReasons[0].Assessment).To(Equal("Ask the storage team."))
+			Expect(data.Vms.NotMigratableReasons[0].Count).To(Equal(1))
+		}
+	})
+
+	// Given an inventory with issues to enrich
+	// When we enrich it
+	// Then the original inventory should be left untouched
+	It("should not modify the input inventory", func() {
+		// Arrange
+		vcenter := newData()
+		inv := v1alpha1.Inventory{
+			Vcenter:  &vcenter,
+			Clusters: map[string]v1alpha1.InventoryData{"cluster-a": newData()},
+		}
+
+		// Act
+		_ = collectorV1.EnrichIssues(inv, extra)
+
+		// Assert
+		Expect(inv.Vcenter.Vms.MigrationWarnings[0].Assessment).To(Equal("Enable CBT."))
+		Expect(inv.Clusters["cluster-a"].Vms.MigrationWarnings[0].Assessment).To(Equal("Enable CBT."))
+	})
+})
@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+// Metrics extracts the top-level totals from the vCenter-wide aggregate of
+// an inventory. An inventory without one has all totals at zero.
+func Metrics(inv v1alpha1.Inventory) models.InventoryMetrics {
+	if inv.Vcenter == nil {
+		return models.InventoryMetrics{}
+	}
+	return dataMetrics(*inv.Vcenter)
+}
+
+// ClusterMetrics extracts the totals of every cluster of an inventory, in
+// cluster ID order.
+func ClusterMetrics(inv v1alpha1.Inventory) []models.ClusterMetrics {
+	clusters := make([]models.ClusterMetrics, 0, len(inv.Clusters))
+	for _, id := range slices.Sorted(maps.Keys(inv.Clusters)) {
+		metrics := dataMetrics(inv.Clusters[id])
+		clusters = append(clusters, models.ClusterMetrics{
+			ID:                   id,
+			TotalVMs:             metrics.TotalVMs,
+			PoweredOnVMs:         metrics.PoweredOnVMs,
+			TotalVCPUs:           metrics.TotalVCPUs,
+			TotalRAMGB:           metrics.TotalRAMGB,
+			TotalDiskTB:          metrics.TotalDiskTB,
+			TotalHosts:           metrics.TotalHosts,
+			CPUOverCommitment:    metrics.CPUOverCommitment,
+			MemoryOverCommitment: metrics.MemoryOverCommitment,
+		})
+	}
+	return clusters
+}
+
+func dataMetrics(data v1alpha1.InventoryData) models.InventoryMetrics {
+	vms := data.Vms
+	infra := data.Infra
+	metrics := models.InventoryMetrics{
+		TotalVMs:             vms.Total,
+		PoweredOnVMs:         vms.PowerStates["poweredOn"],
+		TotalVCPUs:           vms.CpuCores.Total,
+		TotalRAMGB:           vms.RamGB.Total,
+		TotalDiskTB:          float64(vms.DiskGB.Total) / 1024,
+		TotalHosts:           infra.TotalHosts,
+		CPUOverCommitment:    infra.CpuOverCommitment,
+		MemoryOverCommitment: infra.MemoryOverCommitment,
+	}
+	if infra.TotalDatacenters != nil {
+		metrics.TotalDatacenters = *infra.TotalDatacenters
+	}
+	return metrics
+}
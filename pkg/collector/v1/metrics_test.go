@@ -0,0 +1,115 @@
+package v1_test
+
+import (
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
+)
+
+var _ = Describe("Metrics", func() {
+	// Given an inventory with a vCenter-wide aggregate and per-cluster data
+	// When we extract its metrics
+	// Then only the vCenter totals should be used, with disk converted to TB
+	It("should take the totals from the vCenter aggregate", func() {
+		// Arrange
+		datacenters := 3
+		memory := 1.25
+		inv := v1alpha1.Inventory{
+			Clusters: map[string]v1alpha1.InventoryData{
+				"c1": {Vms: v1alpha1.VMs{Total: 1}, Infra: v1alpha1.Infra{TotalHosts: 1}},
+			},
+			Vcenter: &v1alpha1.InventoryData{
+				Vms: v1alpha1.VMs{
+					Total:       10,
+					PowerStates: map[string]int{"poweredOn": 7, "poweredOff": 2, "suspended": 1},
+					CpuCores:    v1alpha1.VMResourceBreakdown{Total: 40},
+					RamGB:       v1alpha1.VMResourceBreakdown{Total: 160},
+					DiskGB:      v1alpha1.VMResourceBreakdown{Total: 2560},
+				},
+				Infra: v1alpha1.Infra{
+					TotalHosts:           4,
+					TotalDatacenters:     &datacenters,
+					MemoryOverCommitment: &memory,
+				},
+			},
+		}
+
+		// Act
+		metrics := collectorV1.Metrics(inv)
+
+		// Assert
+		Expect(metrics).To(Equal(models.InventoryMetrics{
+			TotalVMs:             10,
+			PoweredOnVMs:         7,
+			TotalVCPUs:           40,
+			TotalRAMGB:           160,
+			TotalDiskTB:          2.5,
+			TotalHosts:           4,
+			TotalDatacenters:     3,
+			MemoryOverCommitment: &memory,
+		}))
+	})
+
+	// Given an inventory without a vCenter-wide aggregate
+	// When we extract its metrics
+	// Then all totals should be zero
+	It("should return zero totals without a vCenter aggregate", func() {
+		// Act
+		metrics := collectorV1.Metrics(v1alpha1.Inventory{VcenterId: "vc-1"})
+
+		// Assert
+		Expect(metrics).To(Equal(models.InventoryMetrics{}))
+	})
+
+	Context("ClusterMetrics", func() {
+		// Given an inventory with two clusters, one without host data
+		// When we extract the cluster metrics
+		// Then each cluster should be listed in ID order with its own totals
+		It("should list the totals of every cluster", func() {
+			// Arrange
+			cpu, memory := 3.5, 1.2
+			inv := v1alpha1.Inventory{
+				Clusters: map[string]v1alpha1.InventoryData{
+					"domain-c2": {Vms: v1alpha1.VMs{Total: 2}},
+					"domain-c1": {
+						Vms: v1alpha1.VMs{
+							Total:       5,
+							PowerStates: map[string]int{"poweredOn": 4},
+							CpuCores:    v1alpha1.VMResourceBreakdown{Total: 20},
+							RamGB:       v1alpha1.VMResourceBreakdown{Total: 64},
+							DiskGB:      v1alpha1.VMResourceBreakdown{Total: 512},
+						},
+						Infra: v1alpha1.Infra{
+							TotalHosts:           2,
+							CpuOverCommitment:    &cpu,
+							MemoryOverCommitment: &memory,
+						},
+					},
+				},
+				Vcenter: &v1alpha1.InventoryData{Vms: v1alpha1.VMs{Total: 7}},
+			}
+
+			// Act
+			clusters := collectorV1.ClusterMetrics(inv)
+
+			// Assert
+			Expect(clusters).To(Equal([]models.ClusterMetrics{
+				{
+					ID:                   "domain-c1",
+					TotalVMs:             5,
+					PoweredOnVMs:         4,
+					TotalVCPUs:           20,
+					TotalRAMGB:           64,
+					TotalDiskTB:          0.5,
+					TotalHosts:           2,
+					CPUOverCommitment:    &cpu,
+					MemoryOverCommitment: &memory,
+				},
+				{ID: "domain-c2", TotalVMs: 2},
+			}))
+		})
+	})
+})
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+)
+
+// RedactField names a group of identifying inventory fields that Redact
+// obfuscates.
+type RedactField string
+
+const (
+	// RedactClusterNames obfuscates the cluster names keying clusters.
+	RedactClusterNames RedactField = "cluster-names"
+	// RedactNetworkNames obfuscates network and distributed switch names.
+	RedactNetworkNames RedactField = "network-names"
+	// RedactDatastoreIDs obfuscates datastore disk IDs.
+	RedactDatastoreIDs RedactField = "datastore-ids"
+	// RedactHostIDs obfuscates host IDs, including those datastores refer to.
+	RedactHostIDs RedactField = "host-ids"
+	// RedactVCenterID obfuscates the vCenter ID.
+	RedactVCenterID RedactField = "vcenter-id"
+)
+
+// RedactFields lists every field group Redact knows, in documentation order.
+var RedactFields = []RedactField{
+	RedactClusterNames,
+	RedactNetworkNames,
+	RedactDatastoreIDs,
+	RedactHostIDs,
+	RedactVCenterID,
+}
+
+// ParseRedactFields parses field group names such as "cluster-names". Names
+// are trimmed and must be known; repeats are dropped. No entries yields nil,
+// which redacts nothing.
+func ParseRedactFields(entries []string) ([]RedactField, error) {
+	var fields []RedactField
+	for _, entry := range entries {
+		field := RedactField(strings.TrimSpace(entry))
+		if !slices.Contains(RedactFields, field) {
+			return nil, fmt.Errorf("unknown field %q: must be one of %s", entry, redactFieldNames())
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+func redactFieldNames() string {
+	names := make([]string, 0, len(RedactFields))
+	for _, f := range RedactFields {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
+
+// Redact returns a copy of inv with the values of fields replaced by
+// RedactValue under key. The same value always redacts to the same string
+// with the same key, so distinct clusters, networks and hosts stay distinct
+// and counts stay right. inv is not modified.
+func Redact(inv v1alpha1.Inventory, fields []RedactField, key []byte) v1alpha1.Inventory {
+	if len(fields) == 0 {
+		return inv
+	}
+
+	out := inv
+	if slices.Contains(fields, RedactVCenterID) {
+		out.VcenterId = RedactValue(key, inv.VcenterId)
+	}
+	if inv.Vcenter != nil {
+		data := redactData(*inv.Vcenter, fields, key)
+		out.Vcenter = &data
+	}
+	if inv.Clusters != nil {
+		out.Clusters = make(map[string]v1alpha1.InventoryData, len(inv.Clusters))
+		for name, data := range inv.Clusters {
+			if slices.Contains(fields, RedactClusterNames) {
+				name = RedactValue(key, name)
+			}
+			out.Clusters[name] = redactData(data, fields, key)
+		}
+	}
+	return out
+}
+
+// redactData returns a copy of data with the values of fields redacted.
+func redactData(data v1alpha1.InventoryData, fields []RedactField, key []byte) v1alpha1.InventoryData {
+	if data.Vcenter != nil && slices.Contains(fields, RedactVCenterID) {
+		data.Vcenter = &v1alpha1.VCenter{Id: RedactValue(key, data.Vcenter.Id)}
+	}
+
+	if slices.Contains(fields, RedactNetworkNames) {
+		data.Infra.Networks = slices.Clone(data.Infra.Networks)
+		for i, n := range data.Infra.Networks {
+			data.Infra.Networks[i].Name = RedactValue(key, n.Name)
+			if n.Dvswitch != nil {
+				dvswitch := RedactValue(key, *n.Dvswitch)
+				data.Infra.Networks[i].Dvswitch = &dvswitch
+			}
+		}
+	}
+
+	redactDatastores := slices.Contains(fields, RedactDatastoreIDs)
+	redactHosts := slices.Contains(fields, RedactHostIDs)
+	if redactDatastores || redactHosts {
+		data.Infra.Datastores = slices.Clone(data.Infra.Datastores)
+		for i, d := range data.Infra.Datastores {
+			if redactDatastores {
+				data.Infra.Datastores[i].DiskId = RedactValue(key, d.DiskId)
+			}
+			if redactHosts && d.HostId != nil {
+				hostID := RedactValue(key, *d.HostId)
+				data.Infra.Datastores[i].HostId = &hostID
+			}
+		}
+	}
+
+	if redactHosts && data.Infra.Hosts != nil {
+		hosts := slices.Clone(*data.Infra.Hosts)
+		for i, h := range hosts {
+			if h.Id != nil {
+				id := RedactValue(key, *h.Id)
+				hosts[i].Id = &id
+			}
+		}
+		data.Infra.Hosts = &hosts
+	}
+
+	return data
+}
+
+// RedactValue returns the obfuscated form of value: "redacted-" followed by
+// the first 32 hex digits of its HMAC-SHA256 under key. Without the key, a
+// redacted value cannot be matched against guessed names. An empty value
+// stays empty.
+func RedactValue(key []byte, value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return "redacted-" + hex.EncodeToString(mac.Sum(nil))[:32]
+}
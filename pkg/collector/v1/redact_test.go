@@ -0,0 +1,116 @@
+package v1_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
+)
+
+var _ = Describe("Redact", func() {
+	var (
+		inv v1alpha1.Inventory
+		key = []byte("agent-redaction-key")
+	)
+
+	BeforeEach(func() {
+		hostID := "host-10"
+		dvswitch := "dvs-prod"
+		inv = v1alpha1.Inventory{
+			VcenterId: "vc-1",
+			Vcenter:   &v1alpha1.InventoryData{Vcenter: &v1alpha1.VCenter{Id: "vc-1"}},
+			Clusters: map[string]v1alpha1.InventoryData{
+				"production": {
+					Infra: v1alpha1.Infra{
+						Networks:   []v1alpha1.Network{{Name: "vlan-finance", Dvswitch: &dvswitch}},
+						Datastores: []v1alpha1.Datastore{{DiskId: "naa.600a", HostId: &hostID}},
+						Hosts:      &[]v1alpha1.Host{{Id: &hostID, Model: "R740"}},
+					},
+					Vms: v1alpha1.VMs{Total: 4},
+				},
+			},
+		}
+	})
+
+	// Given an inventory with identifying names and IDs
+	// When every field group is redacted
+	// Then each value should be replaced by its RedactValue and the rest kept
+	It("should obfuscate every requested field", func() {
+		// Act
+		out := collectorV1.Redact(inv, collectorV1.RedactFields, key)
+
+		// Assert
+		Expect(out.VcenterId).To(Equal(collectorV1.RedactValue(key, "vc-1")))
+		Expect(out.Vcenter.Vcenter.Id).To(Equal(collectorV1.RedactValue(key, "vc-1")))
+		Expect(out.Clusters).NotTo(HaveKey("production"))
+		cluster, ok := out.Clusters[collectorV1.RedactValue(key, "production")]
+		Expect(ok).To(BeTrue())
+		Expect(cluster.Infra.Networks[0].Name).To(Equal(collectorV1.RedactValue(key, "vlan-finance")))
+		Expect(*cluster.Infra.Networks[0].Dvswitch).To(Equal(collectorV1.RedactValue(key, "dvs-prod")))
+		Expect(cluster.Infra.Datastores[0].DiskId).To(Equal(collectorV1.RedactValue(key, "naa.600a")))
+		Expect(*cluster.Infra.Datastores[0].HostId).To(Equal(collectorV1.RedactValue(key, "host-10")))
+		Expect(*(*cluster.Infra.Hosts)[0].Id).To(Equal(collectorV1.RedactValue(key, "host-10")))
+		Expect((*cluster.Infra.Hosts)[0].Model).To(Equal("R740"))
+		Expect(cluster.Vms.Total).To(Equal(4))
+	})
+
+	// Given an inventory
+	// When only network names are redacted
+	// Then the other fields and the input inventory should be left untouched
+	It("should only redact the requested fields and not modify the input", func() {
+		// Act
+		out := collectorV1.Redact(inv, []collectorV1.RedactField{collectorV1.RedactNetworkNames}, key)
+
+		// Assert
+		Expect(out.VcenterId).To(Equal("vc-1"))
+		Expect(out.Clusters).To(HaveKey("production"))
+		Expect(out.Clusters["production"].Infra.Networks[0].Name).To(Equal(collectorV1.RedactValue(key, "vlan-finance")))
+		Expect(out.Clusters["production"].Infra.Datastores[0].DiskId).To(Equal("naa.600a"))
+		Expect(inv.Clusters["production"].Infra.Networks[0].Name).To(Equal("vlan-finance"))
+		Expect(*inv.Clusters["production"].Infra.Networks[0].Dvswitch).To(Equal("dvs-prod"))
+	})
+
+	It("should redact equal values alike and keep empty values empty", func() {
+		Expect(collectorV1.RedactValue(key, "production")).To(Equal(collectorV1.RedactValue(key, "production")))
+		Expect(collectorV1.RedactValue(key, "production")).NotTo(Equal(collectorV1.RedactValue(key, "staging")))
+		Expect(collectorV1.RedactValue(key, "production")).To(HavePrefix("redacted-"))
+		Expect(collectorV1.RedactValue(key, "")).To(BeEmpty())
+	})
+
+	// Given a value that could be guessed, such as a cluster name
+	// When it is redacted
+	// Then the result should depend on the key and not be its plain SHA-256
+	It("should key the hash so a guessed value cannot be matched", func() {
+		// Act
+		redacted := collectorV1.RedactValue(key, "production")
+
+		// Assert
+		sum := sha256.Sum256([]byte("production"))
+		Expect(strings.TrimPrefix(redacted, "redacted-")).NotTo(HavePrefix(hex.EncodeToString(sum[:])[:16]))
+		Expect(redacted).NotTo(Equal(collectorV1.RedactValue([]byte("other-key"), "production")))
+	})
+
+	Context("ParseRedactFields", func() {
+		It("should parse known fields, trimming spaces and dropping repeats", func() {
+			fields, err := collectorV1.ParseRedactFields([]string{"cluster-names", " host-ids ", "cluster-names"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fields).To(Equal([]collectorV1.RedactField{collectorV1.RedactClusterNames, collectorV1.RedactHostIDs}))
+		})
+
+		It("should reject an unknown field", func() {
+			_, err := collectorV1.ParseRedactFields([]string{"vm-names"})
+
+			Expect(err).To(MatchError(ContainSubstring(`unknown field "vm-names"`)))
+		})
+
+		It("should return nil for no entries", func() {
+			Expect(collectorV1.ParseRedactFields(nil)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+// DiskSizeTier is one bucket of the inventory's diskSizeTier distribution.
+// A VM falls into the first tier whose MaxTB is above its total disk
+// capacity. The last tier has no bound (MaxTB 0).
+type DiskSizeTier struct {
+	Label string
+	MaxTB float64
+}
+
+// ParseDiskSizeTiers parses tiers written as "label=maxTB", smallest first,
+// with a bare label for the last, unbounded tier:
+//
+//	Easy=1, Medium=5, Hard=20, White Glove
+//
+// Labels must be unique and the bounds increasing. No entries yields nil,
+// which keeps the parser's buckets (0-100GiB up to 20+TiB).
+func ParseDiskSizeTiers(entries []string) ([]DiskSizeTier, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	tiers := make([]DiskSizeTier, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		last := i == len(entries)-1
+		label, bound, hasBound := strings.Cut(entry, "=")
+		label = strings.TrimSpace(label)
+		if label == "" {
+			return nil, fmt.Errorf("disk size tier %q: missing label", entry)
+		}
+		if seen[label] {
+			return nil, fmt.Errorf("disk size tier %q: duplicate label", label)
+		}
+		seen[label] = true
+
+		if last {
+			if hasBound {
+				return nil, fmt.Errorf("disk size tier %q: the last tier is unbounded and takes no threshold", label)
+			}
+			tiers = append(tiers, DiskSizeTier{Label: label})
+			break
+		}
+		if !hasBound {
+			return nil, fmt.Errorf("disk size tier %q: missing threshold in TB (label=maxTB)", label)
+		}
+		maxTB, err := strconv.ParseFloat(strings.TrimSpace(bound), 64)
+		if err != nil || maxTB <= 0 || math.IsInf(maxTB, 0) {
+			return nil, fmt.Errorf("disk size tier %q: threshold %q must be a positive number of TB", label, bound)
+		}
+		if i > 0 && maxTB <= tiers[i-1].MaxTB {
+			return nil, fmt.Errorf("disk size tier %q: threshold %v must be above the previous one (%v)", label, maxTB, tiers[i-1].MaxTB)
+		}
+		tiers = append(tiers, DiskSizeTier{Label: label, MaxTB: maxTB})
+	}
+	return tiers, nil
+}
+
+// DiskSizeTiers buckets VMs by total disk capacity in TB. Like the parser,
+// only tiers with VMs are listed and sizes are rounded to two decimals.
+func DiskSizeTiers(diskTB []float64, tiers []DiskSizeTier) map[string]v1alpha1.DiskSizeTierSummary {
+	sums := make(map[string]float64)
+	result := make(map[string]v1alpha1.DiskSizeTierSummary)
+	for _, size := range diskTB {
+		label := tierOf(size, tiers)
+		summary := result[label]
+		summary.VmCount++
+		sums[label] += size
+		summary.TotalSizeTB = math.Round(sums[label]*100) / 100
+		result[label] = summary
+	}
+	return result
+}
+
+func tierOf(sizeTB float64, tiers []DiskSizeTier) string {
+	for _, tier := range tiers[:len(tiers)-1] {
+		if sizeTB < tier.MaxTB {
+			return tier.Label
+		}
+	}
+	return tiers[len(tiers)-1].Label
+}
+
+// ParseTierBounds checks the upper bounds of CPU or memory tier buckets:
+// positive and increasing. As in the parser, each bound is inclusive and a
+// last bucket takes the values above the highest one.
+func ParseTierBounds(bounds []int) ([]int, error) {
+	for i, bound := range bounds {
+		if bound <= 0 {
+			return nil, fmt.Errorf("tier bound %d must be positive", bound)
+		}
+		if i > 0 && bound <= bounds[i-1] {
+			return nil, fmt.Errorf("tier bound %d must be above the previous one (%d)", bound, bounds[i-1])
+		}
+	}
+	if len(bounds) == 0 {
+		return nil, nil
+	}
+	return bounds, nil
+}
+
+// TierDistribution counts values per bucket, keyed the way the parser keys
+// distributionByCpuTier and distributionByMemoryTier: bounds 4, 8 give
+// "0-4", "5-8" and "8+". Only buckets with values are listed.
+func TierDistribution(values []float64, bounds []int) map[string]int {
+	result := make(map[string]int)
+	for _, v := range values {
+		result[tierKey(v, bounds)]++
+	}
+	return result
+}
+
+func tierKey(v float64, bounds []int) string {
+	lower := 0
+	for _, bound := range bounds {
+		if v <= float64(bound) {
+			return fmt.Sprintf("%d-%d", lower, bound)
+		}
+		lower = bound + 1
+	}
+	return fmt.Sprintf("%d+", bounds[len(bounds)-1])
+}
+
+// Tiers replaces the parser's fixed buckets. A nil field keeps the parser's
+// distribution.
+type Tiers struct {
+	DiskSize []DiskSizeTier
+	CPU      []int // vCPU bounds of distributionByCpuTier
+	MemoryGB []int // memory bounds, in GB, of distributionByMemoryTier
+}
+
+// IsZero reports whether no buckets are replaced.
+func (t Tiers) IsZero() bool {
+	return t.DiskSize == nil && t.CPU == nil && t.MemoryGB == nil
+}
+
+// ApplyTiers replaces the configured distributions of the vCenter-wide
+// aggregate and of each cluster with ones computed from the per-VM sizes.
+// clusterVMs is keyed by cluster ID, as inv.Clusters is; a cluster missing
+// from it is left as it is.
+func ApplyTiers(inv v1alpha1.Inventory, vcenterVMs []models.VMSize, clusterVMs map[string][]models.VMSize, tiers Tiers) v1alpha1.Inventory {
+	if inv.Vcenter != nil {
+		vcenter := *inv.Vcenter
+		vcenter.Vms = applyTiers(vcenter.Vms, vcenterVMs, tiers)
+		inv.Vcenter = &vcenter
+	}
+
+	clusters := make(map[string]v1alpha1.InventoryData, len(inv.Clusters))
+	for id, data := range inv.Clusters {
+		if vms, ok := clusterVMs[id]; ok {
+			data.Vms = applyTiers(data.Vms, vms, tiers)
+		}
+		clusters[id] = data
+	}
+	inv.Clusters = clusters
+	return inv
+}
+
+func applyTiers(data v1alpha1.VMs, vms []models.VMSize, tiers Tiers) v1alpha1.VMs {
+	disk := make([]float64, 0, len(vms))
+	cpus := make([]float64, 0, len(vms))
+	memory := make([]float64, 0, len(vms))
+	for _, vm := range vms {
+		disk = append(disk, vm.DiskTB)
+		cpus = append(cpus, float64(vm.CPUs))
+		memory = append(memory, float64(vm.MemoryMB)/1024)
+	}
+
+	if tiers.DiskSize != nil {
+		distribution := DiskSizeTiers(disk, tiers.DiskSize)
+		data.DiskSizeTier = &distribution
+	}
+	if tiers.CPU != nil {
+		distribution := TierDistribution(cpus, tiers.CPU)
+		data.DistributionByCpuTier = &distribution
+	}
+	if tiers.MemoryGB != nil {
+		distribution := TierDistribution(memory, tiers.MemoryGB)
+		data.DistributionByMemoryTier = &distribution
+	}
+	return data
+}
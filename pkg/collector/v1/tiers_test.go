@@ -0,0 +1,177 @@
+package v1_test
+
+import (
+	"github.com/kubev2v/migration-planner/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
+)
+
+var _ = Describe("Tiers", func() {
+	tShirts := []collectorV1.DiskSizeTier{
+		{Label: "Easy", MaxTB: 1},
+		{Label: "Medium", MaxTB: 5},
+		{Label: "Hard", MaxTB: 20},
+		{Label: "White Glove"},
+	}
+
+	Context("ParseDiskSizeTiers", func() {
+		// Given tiers written as label=maxTB with a bare last label
+		// When we parse them
+		// Then labels and bounds should be kept in order, spaces trimmed
+		It("should parse the tiers", func() {
+			// Act
+			tiers, err := collectorV1.ParseDiskSizeTiers([]string{"Easy=1", " Medium = 5", "Hard=20", "White Glove"})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tiers).To(Equal(tShirts))
+		})
+
+		// Given no tiers
+		// When we parse them
+		// Then the parser's buckets should be kept
+		It("should return nil without entries", func() {
+			// Act
+			tiers, err := collectorV1.ParseDiskSizeTiers(nil)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tiers).To(BeNil())
+		})
+
+		// Given malformed tiers
+		// When we parse them
+		// Then each should be rejected
+		It("should reject invalid tiers", func() {
+			for _, entries := range [][]string{
+				{"Easy=1", "Hard=20"},          // last tier bounded
+				{"Easy", "Hard"},               // missing threshold
+				{"=1", "Hard"},                 // missing label
+				{"Easy=1", "Easy"},             // duplicate label
+				{"Easy=five", "Hard"},          // not a number
+				{"Easy=0", "Hard"},             // not positive
+				{"Easy=5", "Medium=5", "Hard"}, // not increasing
+				{"Easy=5", "Medium=1", "Hard"}, // decreasing
+			} {
+				_, err := collectorV1.ParseDiskSizeTiers(entries)
+				Expect(err).To(HaveOccurred(), "%v", entries)
+			}
+		})
+	})
+
+	Context("DiskSizeTiers", func() {
+		// Given VMs on both sides of each threshold
+		// When we bucket them
+		// Then a VM at a bound should go to the next tier, and empty tiers
+		// should be left out
+		It("should put each VM in the first tier above its size", func() {
+			// Act
+			distribution := collectorV1.DiskSizeTiers([]float64{0, 0.5, 1, 4.999, 25, 100}, tShirts)
+
+			// Assert
+			Expect(distribution).To(Equal(map[string]v1alpha1.DiskSizeTierSummary{
+				"Easy":        {VmCount: 2, TotalSizeTB: 0.5},
+				"Medium":      {VmCount: 2, TotalSizeTB: 6},
+				"White Glove": {VmCount: 2, TotalSizeTB: 125},
+			}))
+		})
+	})
+
+	Context("ParseTierBounds", func() {
+		// Given increasing positive bounds, or none
+		// When we parse them
+		// Then they should be kept as they are, and none should give nil
+		It("should accept increasing bounds", func() {
+			// Act
+			bounds, err := collectorV1.ParseTierBounds([]int{2, 4, 8})
+			empty, emptyErr := collectorV1.ParseTierBounds(nil)
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bounds).To(Equal([]int{2, 4, 8}))
+			Expect(emptyErr).NotTo(HaveOccurred())
+			Expect(empty).To(BeNil())
+		})
+
+		// Given bounds that are not positive or not increasing
+		// When we parse them
+		// Then each should be rejected
+		It("should reject invalid bounds", func() {
+			for _, bounds := range [][]int{{0, 4}, {-2}, {4, 4}, {8, 4}} {
+				_, err := collectorV1.ParseTierBounds(bounds)
+				Expect(err).To(HaveOccurred(), "%v", bounds)
+			}
+		})
+	})
+
+	Context("TierDistribution", func() {
+		// Given values on both sides of each bound
+		// When we bucket them
+		// Then a value at a bound should stay in its bucket, keys should
+		// follow the parser's format, and empty buckets should be left out
+		It("should key the buckets like the parser", func() {
+			// Act
+			distribution := collectorV1.TierDistribution([]float64{1, 2, 3, 8, 9, 64}, []int{2, 4, 8})
+
+			// Assert
+			Expect(distribution).To(Equal(map[string]int{"0-2": 2, "3-4": 1, "5-8": 1, "8+": 2}))
+		})
+	})
+
+	Context("ApplyTiers", func() {
+		parserTiers := map[string]v1alpha1.DiskSizeTierSummary{"0-100GiB": {VmCount: 1}}
+		parserCPU := map[string]int{"0-4": 1}
+		newInventory := func() v1alpha1.Inventory {
+			vms := v1alpha1.VMs{DiskSizeTier: &parserTiers, DistributionByCpuTier: &parserCPU, DistributionByMemoryTier: &parserCPU}
+			return v1alpha1.Inventory{
+				Vcenter:  &v1alpha1.InventoryData{Vms: vms},
+				Clusters: map[string]v1alpha1.InventoryData{"domain-c1": {Vms: vms}, "domain-c2": {Vms: vms}},
+			}
+		}
+		small := models.VMSize{CPUs: 2, MemoryMB: 4096, DiskTB: 0.5}
+		big := models.VMSize{CPUs: 16, MemoryMB: 65536, DiskTB: 30}
+
+		// Given an inventory with the parser's buckets
+		// When we apply custom disk size tiers
+		// Then the vCenter and known clusters should use them, a cluster
+		// without sizes should be left as it is, and the input should not
+		// change
+		It("should replace the vCenter and cluster disk size distributions", func() {
+			// Arrange
+			inv := newInventory()
+
+			// Act
+			result := collectorV1.ApplyTiers(inv, []models.VMSize{small, big}, map[string][]models.VMSize{"domain-c1": {big}}, collectorV1.Tiers{DiskSize: tShirts})
+
+			// Assert
+			Expect(*result.Vcenter.Vms.DiskSizeTier).To(Equal(map[string]v1alpha1.DiskSizeTierSummary{
+				"Easy":        {VmCount: 1, TotalSizeTB: 0.5},
+				"White Glove": {VmCount: 1, TotalSizeTB: 30},
+			}))
+			Expect(*result.Clusters["domain-c1"].Vms.DiskSizeTier).To(Equal(map[string]v1alpha1.DiskSizeTierSummary{
+				"White Glove": {VmCount: 1, TotalSizeTB: 30},
+			}))
+			Expect(*result.Clusters["domain-c2"].Vms.DiskSizeTier).To(Equal(parserTiers))
+			Expect(*inv.Clusters["domain-c1"].Vms.DiskSizeTier).To(Equal(parserTiers))
+			Expect(*result.Vcenter.Vms.DistributionByCpuTier).To(Equal(parserCPU))
+		})
+
+		// Given custom CPU and memory bounds only
+		// When we apply them
+		// Then those distributions should be replaced, memory counted in GB,
+		// and the disk size one kept
+		It("should replace the CPU and memory distributions", func() {
+			// Act
+			result := collectorV1.ApplyTiers(newInventory(), []models.VMSize{small, big}, nil, collectorV1.Tiers{CPU: []int{2, 8}, MemoryGB: []int{4, 32}})
+
+			// Assert
+			Expect(*result.Vcenter.Vms.DistributionByCpuTier).To(Equal(map[string]int{"0-2": 1, "8+": 1}))
+			Expect(*result.Vcenter.Vms.DistributionByMemoryTier).To(Equal(map[string]int{"0-4": 1, "32+": 1}))
+			Expect(*result.Vcenter.Vms.DiskSizeTier).To(Equal(parserTiers))
+			Expect(*result.Clusters["domain-c1"].Vms.DistributionByCpuTier).To(Equal(parserCPU))
+		})
+	})
+})
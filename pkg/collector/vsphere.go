@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"time"
 
 	"github.com/kubev2v/assisted-migration-agent/pkg/vmware"
@@ -114,7 +115,7 @@ func createSecret(creds *models.Credentials) *core.Secret {
 		Data: map[string][]byte{
 			"user":               []byte(creds.Username),
 			"password":           []byte(creds.Password),
-			"insecureSkipVerify": []byte("true"),
+			"insecureSkipVerify": []byte(strconv.FormatBool(creds.Insecure)),
 		},
 	}
 }
@@ -2,42 +2,145 @@ package console
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	externalRef0 "github.com/kubev2v/migration-planner/api/v1alpha1"
 	apiAgent "github.com/kubev2v/migration-planner/api/v1alpha1/agent"
 	agentClient "github.com/kubev2v/migration-planner/pkg/client"
 
+	collectorV1 "github.com/kubev2v/assisted-migration-agent/pkg/collector/v1"
 	serviceErrs "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
+// IdempotencyKeyHeader carries the key the console can dedupe inventory
+// pushes by.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 type Client struct {
 	baseURL    string
+	host       string
 	httpClient *agentClient.Client
+	transport  *http.Transport
 	jwt        string
 }
 
-func NewConsoleClient(baseURL string, jwt string) (*Client, error) {
-	httpClient, err := agentClient.NewClient(baseURL, agentClient.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
-		if jwt == "" {
+// ClientOption configures a console Client.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	transport *http.Transport
+	userAgent string
+}
+
+// WithDialContext replaces the dialer used to reach the console. Mainly useful
+// in tests to simulate name resolution failures.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ClientOption {
+	return func(o *clientOptions) {
+		o.transport.DialContext = dial
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every console request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// UserAgent formats the User-Agent that identifies an agent to the console,
+// so backend logs can be attributed to a specific agent and version.
+func UserAgent(version, agentID string) string {
+	return fmt.Sprintf("assisted-migration-agent/%s (agent-id=%s)", version, agentID)
+}
+
+func NewConsoleClient(baseURL string, jwt string, opts ...ClientOption) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse console url: %w", err)
+	}
+
+	// Own transport so idle connections can be dropped after a DNS failure
+	// without touching http.DefaultTransport.
+	o := &clientOptions{transport: http.DefaultTransport.(*http.Transport).Clone()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	transport := o.transport
+
+	httpClient, err := agentClient.NewClient(baseURL,
+		agentClient.WithHTTPClient(&http.Client{Transport: transport}),
+		agentClient.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+			if o.userAgent != "" {
+				req.Header.Set("User-Agent", o.userAgent)
+			}
+			if jwt == "" {
+				return nil
+			}
+			req.Header.Add("X-Agent-Token", jwt)
 			return nil
-		}
-		req.Header.Add("X-Agent-Token", jwt)
-		return nil
-	}))
+		}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize console client: %w", err)
 	}
 	return &Client{
 		baseURL:    baseURL,
+		host:       u.Hostname(),
 		httpClient: httpClient,
+		transport:  transport,
 		jwt:        jwt,
 	}, nil
 }
 
+// IdempotencyKey returns the key of an inventory push by agentID: the hex
+// SHA-256 of the agent ID and the InventoryHash of data. Every retry of the
+// same inventory carries the same key, so the console can drop a push it
+// already applied although the agent saw it time out.
+func IdempotencyKey(agentID uuid.UUID, data []byte) (string, error) {
+	hash, err := collectorV1.InventoryHash(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(agentID.String() + ":" + hash))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// transportError classifies errors returned before any HTTP response. A DNS
+// lookup failure is reported as ConsoleUnreachableError (transient) and idle
+// connections are dropped so the next attempt resolves the host again.
+func (c *Client) transportError(err error) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		c.transport.CloseIdleConnections()
+		return serviceErrs.NewConsoleUnreachableError(c.host, err)
+	}
+	return err
+}
+
+// parseRetryAfter reads a Retry-After header, given either as delay seconds or
+// as an HTTP date, relative to now. Missing, malformed and past values yield 0.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
 // UpdateAgentStatus sends agent status to console.redhat.com
 // PUT /api/v1/agents/{id}/status
 func (c *Client) UpdateAgentStatus(ctx context.Context, agentID uuid.UUID, sourceID uuid.UUID, version, status, statusInfo string) error {
@@ -51,7 +154,7 @@ func (c *Client) UpdateAgentStatus(ctx context.Context, agentID uuid.UUID, sourc
 
 	resp, err := c.httpClient.UpdateAgentStatus(ctx, agentID, body)
 	if err != nil {
-		return err
+		return c.transportError(err)
 	}
 	if resp != nil {
 		defer func() {
@@ -62,6 +165,8 @@ func (c *Client) UpdateAgentStatus(ctx context.Context, agentID uuid.UUID, sourc
 	switch {
 	case resp.StatusCode >= 200 && resp.StatusCode < 300:
 		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		return serviceErrs.NewConsoleBackpressureError(resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
 	case resp.StatusCode >= 400 && resp.StatusCode < 500:
 		return serviceErrs.NewConsoleClientError(resp.StatusCode, resp.Status)
 	default:
@@ -71,7 +176,8 @@ func (c *Client) UpdateAgentStatus(ctx context.Context, agentID uuid.UUID, sourc
 
 // UpdateSourceStatus sends source inventory to console.redhat.com
 // PUT /api/v1/sources/{id}/status
-func (c *Client) UpdateSourceStatus(ctx context.Context, sourceID, agentID uuid.UUID, data []byte) error {
+// A non-empty idempotencyKey is sent in the Idempotency-Key header.
+func (c *Client) UpdateSourceStatus(ctx context.Context, sourceID, agentID uuid.UUID, data []byte, idempotencyKey string) error {
 	inv := externalRef0.Inventory{}
 	if err := json.Unmarshal(data, &inv); err != nil {
 		return fmt.Errorf("failed to unmarshal inventory: %w", err)
@@ -82,9 +188,14 @@ func (c *Client) UpdateSourceStatus(ctx context.Context, sourceID, agentID uuid.
 		Inventory: inv,
 	}
 
-	resp, err := c.httpClient.UpdateSourceInventory(ctx, sourceID, body)
+	resp, err := c.httpClient.UpdateSourceInventory(ctx, sourceID, body, func(ctx context.Context, req *http.Request) error {
+		if idempotencyKey != "" {
+			req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return c.transportError(err)
 	}
 	if resp != nil {
 		defer func() {
@@ -95,6 +206,8 @@ func (c *Client) UpdateSourceStatus(ctx context.Context, sourceID, agentID uuid.
 	switch {
 	case resp.StatusCode >= 200 && resp.StatusCode < 300:
 		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		return serviceErrs.NewConsoleBackpressureError(resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
 	case resp.StatusCode >= 400 && resp.StatusCode < 500:
 		return serviceErrs.NewConsoleClientError(resp.StatusCode, resp.Status)
 	default:
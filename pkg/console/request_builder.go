@@ -2,6 +2,7 @@ package console
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 
@@ -33,8 +34,14 @@ func NewRequestBuilder(client *Client, sourceID, agentID uuid.UUID) *RequestBuil
 func (b *RequestBuilder) Build(event models.Event) (func(ctx context.Context) error, error) {
 	switch event.Kind {
 	case models.InventoryUpdateEvent:
+		// The key only depends on the agent and the inventory, so a retry of
+		// the event, rebuilt on the next tick, sends the same one.
+		key, err := IdempotencyKey(b.agentID, event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute idempotency key: %w", err)
+		}
 		return func(ctx context.Context) error {
-			return b.client.UpdateSourceStatus(ctx, b.sourceID, b.agentID, event.Data)
+			return b.client.UpdateSourceStatus(ctx, b.sourceID, b.agentID, event.Data, key)
 		}, nil
 	default:
 		return nil, errors.NewUnknownEventKindError(string(event.Kind))
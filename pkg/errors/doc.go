@@ -12,8 +12,19 @@
 //	│ OperationInProgressError │ 409   │ Already running                      │
 //	│ InvalidStateError        │ 500    │ Invalid state for operation         │
 //	│ ModeConflictError        │ 409    │ Mode change blocked by fatal error  │
+//	│ ModeChangeInProgress     │ 409    │ Another mode change is running      │
+//	│ AgentNotConnectedError   │ 409    │ Operation requires connected mode   │
+//	│ MaintenanceModeError     │ 503    │ Mutation blocked by maintenance     │
+//	│ RequestTimeoutError      │ 503    │ Request ran past its timeout        │
 //	│ VCenterError             │ 500    │ vCenter connection/auth failure     │
+//	│ CredentialsError         │ 400    │ vCenter rejected the login          │
+//	│ ValidationError          │ 400    │ Invalid input, optionally per field │
+//	│ CollectionFailedError    │ 409    │ No inventory, collection failed     │
 //	│ ConsoleClientError       │ 4xx    │ HTTP error from console.redhat.com  │
+//	│ SourceNotRegisteredError │ -      │ Console answered 404/410 for source │
+//	│ ConsoleUnreachableError  │ -      │ Console host failed to resolve      │
+//	│ ConsoleBackpressureError │ -      │ Console answered 429/503            │
+//	│ InventoryTooLargeError   │ -      │ Inventory over the push size limit  │
 //	└──────────────────────────┴────────┴─────────────────────────────────────┘
 //
 // # ResourceNotFoundError
@@ -24,6 +35,7 @@
 //   - NewResourceNotFoundError(kind string) - Generic resource not found
 //   - NewInventoryNotFoundError() - Inventory not collected yet
 //   - NewConfigurationNotFoundError() - Configuration not found
+//   - NewDataFolderNotSetError() - Agent runs without a data folder (in-memory database)
 //
 // Usage:
 //
@@ -72,24 +84,144 @@
 //	    c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 //	}
 //
+// # ModeChangeInProgressError
+//
+// Indicates a mode change was rejected because another one is still being
+// applied. Concurrent SetMode calls are not queued; the caller can retry.
+//
+// Constructor:
+//   - NewModeChangeInProgressError()
+//
+// Usage:
+//
+//	if errors.IsModeChangeInProgressError(err) {
+//	    c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+//	}
+//
+// # AgentNotConnectedError
+//
+// Indicates an operation that needs the console loop, such as forcing an
+// inventory push, was requested while the agent is disconnected.
+//
+// Constructor:
+//   - NewAgentNotConnectedError()
+//
+// Usage:
+//
+//	if errors.IsAgentNotConnectedError(err) {
+//	    c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+//	}
+//
+// # MaintenanceModeError
+//
+// Indicates a mutating operation (collection, inspection, inventory push) was
+// rejected because the agent is in maintenance mode. Read endpoints keep working.
+//
+// Constructor:
+//   - NewMaintenanceModeError()
+//
+// Usage:
+//
+//	if errors.IsMaintenanceModeError(err) {
+//	    c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+//	}
+//
+// # RequestTimeoutError
+//
+// Indicates an API request ran past the server's per-request timeout. The
+// timeout middleware sets it as the cause of the request context, so handlers
+// can tell a timed out request from any other cancellation.
+//
+// Constructor:
+//   - NewRequestTimeoutError(timeout time.Duration)
+//
+// Usage:
+//
+//	if errors.IsRequestTimeoutError(context.Cause(c.Request.Context())) {
+//	    c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+//	}
+//
 // # VCenterError
 //
 // Wraps errors from vCenter connections with user-friendly messages.
-// Automatically detects login failures and credential issues.
+// Automatically detects login failures, credential issues, untrusted
+// certificates and denied privileges.
 //
 // Constructor:
 //   - NewVCenterError(err error) - Wraps and interprets the underlying error
 //
 // Error detection:
+//   - x509 verification failure → "vCenter certificate not trusted: ..."
 //   - "Login failure" or "incorrect password" → "invalid credentials"
+//   - NoPermission fault → wraps a PermissionError, "vCenter permission denied: ..."
 //   - Other errors → Original error message
 //
 // Usage:
 //
+//	if errors.IsVCenterCertificateError(err) {
+//	    // Suggest retrying with insecure TLS
+//	}
 //	if errors.IsVCenterError(err) {
 //	    // Handle vCenter-specific error
 //	}
 //
+// # PermissionError
+//
+// Wrapped by a VCenterError when vCenter accepted the login but answered a
+// call with a NoPermission fault, as a read-only account does when it lacks a
+// privilege mid-collection. Privilege holds the missing privilege ID when the
+// fault names one. Check it before IsVCenterError, which also matches.
+//
+// Constructor:
+//   - NewPermissionError(privilege string)
+//
+// Usage:
+//
+//	if errors.IsPermissionError(err) {
+//	    c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+//	}
+//
+// # CredentialsError
+//
+// Indicates vCenter answered but rejected the username or password. Used where
+// a bad login must be told apart from an unreachable vCenter, which is
+// reported as a VCenterError.
+//
+// Constructor:
+//   - NewCredentialsError()
+//
+// Usage:
+//
+//	if errors.IsCredentialsError(err) {
+//	    c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+//	}
+//
+// # ValidationError
+//
+// Indicates invalid request input. A field error carries Fields, a map from
+// the JSON field name to what is wrong with it, which handlers return as
+// {"code": "VALIDATION", "fields": {...}} next to the message.
+//
+// Constructors:
+//   - NewValidationError(msg string)
+//   - NewFieldValidationError(fields map[string]string) - Message lists the fields
+//
+// Usage:
+//
+//	if ve := errors.GetValidationError(err); ve != nil && ve.Fields != nil {
+//	    // Report ve.Fields per input
+//	}
+//
+// # CollectionFailedError
+//
+// Returned by GET /inventory when no inventory is stored and the collector is
+// in the error state, so clients can tell a failed collection from one that
+// never ran (ResourceNotFoundError). The collection error is available through
+// errors.Unwrap.
+//
+// Constructor:
+//   - NewCollectionFailedError(err error)
+//
 // # ConsoleClientError
 //
 // Wraps HTTP 4xx errors from the console.redhat.com API.
@@ -108,6 +240,59 @@
 //	    // Fatal error - console service should stop
 //	}
 //
+// # SourceNotRegisteredError
+//
+// Returned by the console service when the console answers 404 or 410 to an
+// inventory push: the configured source ID is unknown to the console or the
+// source was deleted. The message tells the operator to create the source or
+// fix --source-id. It wraps the ConsoleClientError, so IsConsoleClientError
+// also matches and the console service stops as for any other 4xx. GET /agent
+// reports it with error_code SOURCE_NOT_REGISTERED.
+//
+// Constructor:
+//   - NewSourceNotRegisteredError(sourceID string, err error)
+//
+// # ConsoleUnreachableError
+//
+// Wraps DNS lookup failures ("no such host") for the console host. These are
+// transient: the console service keeps retrying with backoff, and the client
+// drops idle connections so the host is resolved again on the next attempt.
+// The underlying *net.DNSError is available through errors.As.
+//
+// Constructor:
+//   - NewConsoleUnreachableError(host string, err error)
+//
+// Usage:
+//
+//	if errors.IsConsoleUnreachableError(err) {
+//	    // Transient - retry later
+//	}
+//
+// # ConsoleBackpressureError
+//
+// Reports a 429 Too Many Requests or 503 Service Unavailable from the console.
+// It is transient, like ConsoleUnreachableError. RetryAfter holds the
+// Retry-After header (seconds or HTTP date) and the console service waits at
+// least that long before its next attempt.
+//
+// Constructor:
+//   - NewConsoleBackpressureError(statusCode int, retryAfter time.Duration)
+//
+// Usage:
+//
+//	if errors.IsConsoleBackpressureError(err) {
+//	    wait = max(wait, errors.ConsoleRetryAfter(err))
+//	}
+//
+// # InventoryTooLargeError
+//
+// Set as the console status error when an inventory is larger than
+// Agent.MaxInventoryBytes. The push is skipped instead of failing with a 413
+// on every attempt; the error stays until an inventory is pushed.
+//
+// Constructor:
+//   - NewInventoryTooLargeError(size, limit int)
+//
 // # Type Checking Pattern
 //
 // All error types provide Is* helper functions that use errors.As
@@ -132,7 +317,7 @@
 //	    c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 //	case errors.IsOperationInProgressError(err):
 //	    c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-//	case errors.IsModeConflictError(err):
+//	case errors.IsModeConflictError(err), errors.IsModeChangeInProgressError(err):
 //	    c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 //	default:
 //	    c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -1,9 +1,16 @@
 package errors
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 // ServiceAlreadyStartedError indicates that a work service or pool has already been started.
@@ -44,6 +51,10 @@ func NewVddkNotFoundError() *ResourceNotFoundError {
 	return NewResourceNotFoundError("vddk", "")
 }
 
+func NewDataFolderNotSetError() *ResourceNotFoundError {
+	return NewResourceNotFoundError("data folder", "")
+}
+
 func (e *ResourceNotFoundError) Error() string {
 	if e.ID != "" {
 		return fmt.Sprintf("%s '%s' not found", e.Kind, e.ID)
@@ -149,31 +160,196 @@ func IsModeConflictError(err error) bool {
 	return errors.As(err, &e)
 }
 
+// ModeChangeInProgressError indicates a mode change was requested while another
+// one is still being applied.
+type ModeChangeInProgressError struct{}
+
+func NewModeChangeInProgressError() *ModeChangeInProgressError {
+	return &ModeChangeInProgressError{}
+}
+
+func (e *ModeChangeInProgressError) Error() string {
+	return "mode change already in progress"
+}
+
+func IsModeChangeInProgressError(err error) bool {
+	var e *ModeChangeInProgressError
+	return errors.As(err, &e)
+}
+
+// AgentNotConnectedError indicates an operation that requires the agent to be
+// in connected mode with a running console loop.
+type AgentNotConnectedError struct{}
+
+func NewAgentNotConnectedError() *AgentNotConnectedError {
+	return &AgentNotConnectedError{}
+}
+
+func (e *AgentNotConnectedError) Error() string {
+	return "agent is not connected to the console"
+}
+
+func IsAgentNotConnectedError(err error) bool {
+	var e *AgentNotConnectedError
+	return errors.As(err, &e)
+}
+
+// MaintenanceModeError indicates a mutating operation was rejected because the
+// agent is in maintenance mode.
+type MaintenanceModeError struct{}
+
+func NewMaintenanceModeError() *MaintenanceModeError {
+	return &MaintenanceModeError{}
+}
+
+func (e *MaintenanceModeError) Error() string {
+	return "agent is in maintenance mode"
+}
+
+func IsMaintenanceModeError(err error) bool {
+	var e *MaintenanceModeError
+	return errors.As(err, &e)
+}
+
 func NewVCenterError(err error) *VCenterError {
 	vErr := &VCenterError{msg: "unknown error"}
-	if strings.Contains(err.Error(), "Login failure") ||
-		(strings.Contains(err.Error(), "incorrect") && strings.Contains(err.Error(), "password")) {
+	switch {
+	case isCertificateError(err):
+		vErr.msg = fmt.Sprintf("vCenter certificate not trusted: %v", err)
+		vErr.certificate = true
+	case strings.Contains(err.Error(), "Login failure") ||
+		(strings.Contains(err.Error(), "incorrect") && strings.Contains(err.Error(), "password")):
 		vErr.msg = "invalid credentials"
-	} else {
+	case isPermissionError(err):
+		pErr := NewPermissionError(missingPrivilege(err))
+		vErr.msg = pErr.Error()
+		vErr.err = pErr
+	default:
 		vErr.msg = err.Error()
 	}
 	return vErr
 }
 
-// VCenterError indicates the provided credentials are invalid.
+// VCenterError indicates vCenter could not be reached, rejected the
+// credentials, presented a certificate that failed verification or denied the
+// account a privilege. The last case wraps a PermissionError.
 type VCenterError struct {
-	msg string
+	msg         string
+	certificate bool
+	err         error
 }
 
 func (e *VCenterError) Error() string {
 	return e.msg
 }
 
+func (e *VCenterError) Unwrap() error {
+	return e.err
+}
+
+// CertificateNotTrusted reports whether vCenter failed TLS certificate
+// verification. The caller can retry with insecure TLS.
+func (e *VCenterError) CertificateNotTrusted() bool {
+	return e.certificate
+}
+
 func IsVCenterError(err error) bool {
 	var e *VCenterError
 	return errors.As(err, &e)
 }
 
+// IsVCenterCertificateError reports whether err is a VCenterError caused by an
+// untrusted vCenter certificate.
+func IsVCenterCertificateError(err error) bool {
+	var e *VCenterError
+	return errors.As(err, &e) && e.certificate
+}
+
+// isCertificateError detects x509 verification failures. The message check
+// covers errors that were flattened to a string on the way up, as govmomi does
+// for some SOAP faults.
+func isCertificateError(err error) bool {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		invalid          x509.CertificateInvalidError
+		hostname         x509.HostnameError
+		verification     *tls.CertificateVerificationError
+	)
+	if errors.As(err, &unknownAuthority) || errors.As(err, &invalid) ||
+		errors.As(err, &hostname) || errors.As(err, &verification) {
+		return true
+	}
+	return strings.Contains(err.Error(), "x509: ")
+}
+
+// isPermissionError detects vCenter NoPermission faults. The message check
+// covers faults flattened to a string on the way up.
+func isPermissionError(err error) bool {
+	var noPermission *types.NoPermission
+	if _, ok := fault.As(err, &noPermission); ok {
+		return true
+	}
+	return strings.Contains(err.Error(), "NoPermission") ||
+		strings.Contains(err.Error(), "Permission to perform this operation was denied")
+}
+
+// missingPrivilege returns the privilege named by the NoPermission fault in
+// err, or "" when the fault does not name one or was flattened to a string.
+func missingPrivilege(err error) string {
+	var noPermission *types.NoPermission
+	if _, ok := fault.As(err, &noPermission); !ok {
+		return ""
+	}
+	if noPermission.PrivilegeId != "" {
+		return noPermission.PrivilegeId
+	}
+	for _, entity := range noPermission.MissingPrivileges {
+		if len(entity.PrivilegeIds) > 0 {
+			return entity.PrivilegeIds[0]
+		}
+	}
+	return ""
+}
+
+// PermissionError indicates vCenter accepted the login but denied the account
+// an operation, typically a read-only account missing a privilege. Privilege
+// is empty when vCenter did not name it.
+type PermissionError struct {
+	Privilege string
+}
+
+func NewPermissionError(privilege string) *PermissionError {
+	return &PermissionError{Privilege: privilege}
+}
+
+func (e *PermissionError) Error() string {
+	if e.Privilege != "" {
+		return fmt.Sprintf("vCenter permission denied: missing privilege %s", e.Privilege)
+	}
+	return "vCenter permission denied"
+}
+
+func IsPermissionError(err error) bool {
+	var e *PermissionError
+	return errors.As(err, &e)
+}
+
+// CredentialsError indicates vCenter was reached but rejected the login.
+type CredentialsError struct{}
+
+func NewCredentialsError() *CredentialsError {
+	return &CredentialsError{}
+}
+
+func (e *CredentialsError) Error() string {
+	return "invalid credentials"
+}
+
+func IsCredentialsError(err error) bool {
+	var e *CredentialsError
+	return errors.As(err, &e)
+}
+
 // ConsoleClientError wraps HTTP 4xx errors from the console client.
 type ConsoleClientError struct {
 	StatusCode int
@@ -193,6 +369,142 @@ func IsConsoleClientError(err error) bool {
 	return errors.As(err, &e)
 }
 
+// ConsoleClientStatusCode returns the StatusCode of the ConsoleClientError in
+// err's chain, or zero if there is none.
+func ConsoleClientStatusCode(err error) int {
+	var e *ConsoleClientError
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// SourceNotRegisteredError indicates the console answered 404 or 410 for the
+// agent's source: the source ID is unknown to the console or was deleted. It
+// wraps the ConsoleClientError, so the console loop stops as for any 4xx.
+type SourceNotRegisteredError struct {
+	SourceID string
+	err      error
+}
+
+func NewSourceNotRegisteredError(sourceID string, err error) *SourceNotRegisteredError {
+	return &SourceNotRegisteredError{SourceID: sourceID, err: err}
+}
+
+func (e *SourceNotRegisteredError) Error() string {
+	return fmt.Sprintf("source %s is not registered with the console (%v): create the source in the console or set --source-id to an existing one, then restart the agent", e.SourceID, e.err)
+}
+
+func (e *SourceNotRegisteredError) Unwrap() error {
+	return e.err
+}
+
+func IsSourceNotRegisteredError(err error) bool {
+	var e *SourceNotRegisteredError
+	return errors.As(err, &e)
+}
+
+// ConsoleUnreachableError indicates the console host could not be resolved.
+// Unlike ConsoleClientError it is transient: the console loop keeps retrying.
+type ConsoleUnreachableError struct {
+	Host string
+	err  error
+}
+
+func NewConsoleUnreachableError(host string, err error) *ConsoleUnreachableError {
+	return &ConsoleUnreachableError{Host: host, err: err}
+}
+
+func (e *ConsoleUnreachableError) Error() string {
+	return fmt.Sprintf("console host %s unreachable: %v", e.Host, e.err)
+}
+
+func (e *ConsoleUnreachableError) Unwrap() error {
+	return e.err
+}
+
+func IsConsoleUnreachableError(err error) bool {
+	var e *ConsoleUnreachableError
+	return errors.As(err, &e)
+}
+
+// ConsoleBackpressureError indicates the console answered 429 or 503. It is
+// transient; RetryAfter is the wait the console asked for through its
+// Retry-After header, or zero when it sent none.
+type ConsoleBackpressureError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func NewConsoleBackpressureError(statusCode int, retryAfter time.Duration) *ConsoleBackpressureError {
+	return &ConsoleBackpressureError{StatusCode: statusCode, RetryAfter: retryAfter}
+}
+
+func (e *ConsoleBackpressureError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("console busy (%d), retry after %s", e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("console busy (%d)", e.StatusCode)
+}
+
+func IsConsoleBackpressureError(err error) bool {
+	var e *ConsoleBackpressureError
+	return errors.As(err, &e)
+}
+
+// ConsoleRetryAfter returns the RetryAfter of the ConsoleBackpressureError in
+// err's chain, or zero if there is none.
+func ConsoleRetryAfter(err error) time.Duration {
+	var e *ConsoleBackpressureError
+	if errors.As(err, &e) {
+		return e.RetryAfter
+	}
+	return 0
+}
+
+// CollectionFailedError indicates there is no inventory because the last
+// collection failed. It wraps the collection error.
+type CollectionFailedError struct {
+	err error
+}
+
+func NewCollectionFailedError(err error) *CollectionFailedError {
+	return &CollectionFailedError{err: err}
+}
+
+func (e *CollectionFailedError) Error() string {
+	return fmt.Sprintf("inventory not available, collection failed: %v", e.err)
+}
+
+func (e *CollectionFailedError) Unwrap() error {
+	return e.err
+}
+
+func IsCollectionFailedError(err error) bool {
+	var e *CollectionFailedError
+	return errors.As(err, &e)
+}
+
+// InventoryTooLargeError indicates an inventory was not pushed to the console
+// because it is larger than the configured limit.
+type InventoryTooLargeError struct {
+	Size  int
+	Limit int
+}
+
+func NewInventoryTooLargeError(size, limit int) *InventoryTooLargeError {
+	return &InventoryTooLargeError{Size: size, Limit: limit}
+}
+
+func (e *InventoryTooLargeError) Error() string {
+	return fmt.Sprintf("inventory not pushed: %d bytes exceeds the %d byte limit", e.Size, e.Limit)
+}
+
+func IsInventoryTooLargeError(err error) bool {
+	var e *InventoryTooLargeError
+	return errors.As(err, &e)
+}
+
 // InspectorNotRunningError indicates that inspector not currently running
 type InspectorNotRunningError struct{}
 
@@ -291,15 +603,33 @@ func NewForecasterInProgressError() *OperationInProgressError {
 	return NewOperationInProgressError("forecast")
 }
 
-// ValidationError indicates invalid input.
+// ValidationError indicates invalid input. Fields, when set, maps each
+// invalid request field to what is wrong with it.
 type ValidationError struct {
 	Message string
+	Fields  map[string]string
 }
 
 func NewValidationError(msg string) *ValidationError {
 	return &ValidationError{Message: msg}
 }
 
+// NewFieldValidationError returns a ValidationError for the given field to
+// message map. Its message lists the fields in name order.
+func NewFieldValidationError(fields map[string]string) *ValidationError {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, name+" "+fields[name])
+	}
+	return &ValidationError{Message: strings.Join(msgs, "; "), Fields: fields}
+}
+
 func (e *ValidationError) Error() string {
 	return e.Message
 }
@@ -309,6 +639,15 @@ func IsValidationError(err error) bool {
 	return errors.As(err, &e)
 }
 
+// GetValidationError returns the ValidationError in err's chain, or nil.
+func GetValidationError(err error) *ValidationError {
+	var e *ValidationError
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
 // CredentialsNotSetError indicates that required credentials were not set
 type CredentialsNotSetError struct{}
 
@@ -342,3 +681,21 @@ func IsUnknownEventKindError(err error) bool {
 	var e *UnknownEventKindError
 	return errors.As(err, &e)
 }
+
+// RequestTimeoutError indicates an API request ran past the server's per-request timeout.
+type RequestTimeoutError struct {
+	Timeout time.Duration
+}
+
+func NewRequestTimeoutError(timeout time.Duration) *RequestTimeoutError {
+	return &RequestTimeoutError{Timeout: timeout}
+}
+
+func (e *RequestTimeoutError) Error() string {
+	return fmt.Sprintf("request timed out after %s", e.Timeout)
+}
+
+func IsRequestTimeoutError(err error) bool {
+	var e *RequestTimeoutError
+	return errors.As(err, &e)
+}
@@ -1,12 +1,20 @@
 package errors_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+
 	srvErrors "github.com/kubev2v/assisted-migration-agent/pkg/errors"
 )
 
@@ -320,6 +328,60 @@ var _ = Describe("Errors", func() {
 			Expect(err.Error()).To(Equal("connection refused"))
 		})
 
+		// Given an x509 unknown authority error wrapped by the HTTP client
+		// When NewVCenterError wraps it
+		// Then it should be reported as an untrusted certificate
+		It("should detect an untrusted certificate", func() {
+			// Arrange
+			cause := &url.Error{Op: "Post", URL: "https://vc/sdk", Err: &tls.CertificateVerificationError{
+				Err: x509.UnknownAuthorityError{},
+			}}
+
+			// Act
+			err := srvErrors.NewVCenterError(cause)
+
+			// Assert
+			Expect(err.Error()).To(HavePrefix("vCenter certificate not trusted"))
+			Expect(err.CertificateNotTrusted()).To(BeTrue())
+			Expect(srvErrors.IsVCenterCertificateError(fmt.Errorf("collect: %w", err))).To(BeTrue())
+			Expect(srvErrors.IsVCenterError(err)).To(BeTrue())
+		})
+
+		// Given a hostname mismatch error
+		// When NewVCenterError wraps it
+		// Then it should be reported as an untrusted certificate
+		It("should detect a hostname mismatch", func() {
+			// Act
+			err := srvErrors.NewVCenterError(x509.HostnameError{Certificate: &x509.Certificate{}, Host: "vc"})
+
+			// Assert
+			Expect(err.CertificateNotTrusted()).To(BeTrue())
+		})
+
+		// Given a certificate error flattened to a string
+		// When NewVCenterError wraps it
+		// Then it should still be reported as an untrusted certificate
+		It("should detect a certificate error from its message", func() {
+			// Act
+			err := srvErrors.NewVCenterError(errors.New("Post \"https://vc/sdk\": tls: failed to verify certificate: x509: certificate has expired"))
+
+			// Assert
+			Expect(err.CertificateNotTrusted()).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("certificate has expired"))
+		})
+
+		// Given a non-certificate vCenter error
+		// When checked with IsVCenterCertificateError
+		// Then it should return false
+		It("should not report other failures as certificate errors", func() {
+			// Arrange
+			err := srvErrors.NewVCenterError(errors.New("connection refused"))
+
+			// Act & Assert
+			Expect(err.CertificateNotTrusted()).To(BeFalse())
+			Expect(srvErrors.IsVCenterCertificateError(err)).To(BeFalse())
+		})
+
 		// Given a VCenterError
 		// When checked with IsVCenterError
 		// Then it should return true
@@ -349,6 +411,68 @@ var _ = Describe("Errors", func() {
 			// Act & Assert
 			Expect(srvErrors.IsVCenterError(errors.New("nope"))).To(BeFalse())
 		})
+
+		// Given a NoPermission fault naming the privilege
+		// When NewVCenterError wraps it
+		// Then it should be a PermissionError carrying the privilege
+		It("should detect a missing privilege", func() {
+			// Arrange
+			cause := fmt.Errorf("list datastores: %w", soap.WrapVimFault(&types.NoPermission{PrivilegeId: "System.Read"}))
+
+			// Act
+			err := srvErrors.NewVCenterError(cause)
+
+			// Assert
+			Expect(err.Error()).To(Equal("vCenter permission denied: missing privilege System.Read"))
+			Expect(srvErrors.IsVCenterError(err)).To(BeTrue())
+			Expect(srvErrors.IsPermissionError(fmt.Errorf("collect: %w", err))).To(BeTrue())
+			var pErr *srvErrors.PermissionError
+			Expect(errors.As(err, &pErr)).To(BeTrue())
+			Expect(pErr.Privilege).To(Equal("System.Read"))
+		})
+
+		// Given a NoPermission fault listing the missing privileges per entity
+		// When NewVCenterError wraps it
+		// Then the first listed privilege should be named
+		It("should name the privilege from the missing privileges list", func() {
+			// Arrange
+			cause := soap.WrapVimFault(&types.NoPermission{
+				MissingPrivileges: []types.NoPermissionEntityPrivileges{
+					{PrivilegeIds: []string{"Datastore.Browse", "Datastore.FileManagement"}},
+				},
+			})
+
+			// Act
+			err := srvErrors.NewVCenterError(cause)
+
+			// Assert
+			var pErr *srvErrors.PermissionError
+			Expect(errors.As(err, &pErr)).To(BeTrue())
+			Expect(pErr.Privilege).To(Equal("Datastore.Browse"))
+		})
+
+		// Given a permission fault flattened to a string
+		// When NewVCenterError wraps it
+		// Then it should still be a PermissionError, without a privilege
+		It("should detect a permission error from its message", func() {
+			// Act
+			err := srvErrors.NewVCenterError(errors.New("ServerFaultCode: Permission to perform this operation was denied."))
+
+			// Assert
+			Expect(err.Error()).To(Equal("vCenter permission denied"))
+			Expect(srvErrors.IsPermissionError(err)).To(BeTrue())
+		})
+
+		// Given a vCenter error that is not about permissions
+		// When checked with IsPermissionError
+		// Then it should return false
+		It("should not report other failures as permission errors", func() {
+			// Arrange
+			err := srvErrors.NewVCenterError(errors.New("connection refused"))
+
+			// Act & Assert
+			Expect(srvErrors.IsPermissionError(err)).To(BeFalse())
+		})
 	})
 
 	Context("ConsoleClientError", func() {
@@ -394,6 +518,80 @@ var _ = Describe("Errors", func() {
 		})
 	})
 
+	Context("SourceNotRegisteredError", func() {
+		// Given a 404 from the console wrapped as SourceNotRegisteredError
+		// When checked against both error kinds
+		// Then it should match both, so the console loop still stops on it
+		It("should be detected as both a source and a console client error", func() {
+			// Arrange
+			err := fmt.Errorf("push: %w", srvErrors.NewSourceNotRegisteredError("source-1", srvErrors.NewConsoleClientError(404, "404 Not Found")))
+
+			// Act & Assert
+			Expect(srvErrors.IsSourceNotRegisteredError(err)).To(BeTrue())
+			Expect(srvErrors.IsConsoleClientError(err)).To(BeTrue())
+			Expect(srvErrors.ConsoleClientStatusCode(err)).To(Equal(404))
+			Expect(err.Error()).To(ContainSubstring("create the source in the console"))
+		})
+
+		// Given a plain console client error
+		// When checked with IsSourceNotRegisteredError
+		// Then it should return false
+		It("should not match other console client errors", func() {
+			// Act & Assert
+			Expect(srvErrors.IsSourceNotRegisteredError(srvErrors.NewConsoleClientError(404, "404 Not Found"))).To(BeFalse())
+			Expect(srvErrors.ConsoleClientStatusCode(errors.New("nope"))).To(BeZero())
+		})
+	})
+
+	Context("ConsoleUnreachableError", func() {
+		// Given a DNS failure wrapped as ConsoleUnreachableError
+		// When checked with IsConsoleUnreachableError and errors.As
+		// Then it should match and expose the underlying DNS error
+		It("should be detected and unwrap to the DNS error", func() {
+			// Arrange
+			dnsErr := &net.DNSError{Err: "no such host", Name: "console.example.com", IsNotFound: true}
+			wrapped := fmt.Errorf("dispatch: %w", srvErrors.NewConsoleUnreachableError("console.example.com", dnsErr))
+
+			// Act & Assert
+			Expect(srvErrors.IsConsoleUnreachableError(wrapped)).To(BeTrue())
+			Expect(srvErrors.IsConsoleClientError(wrapped)).To(BeFalse())
+			var target *net.DNSError
+			Expect(errors.As(wrapped, &target)).To(BeTrue())
+			Expect(wrapped.Error()).To(ContainSubstring("console host console.example.com unreachable"))
+		})
+	})
+
+	Context("ConsoleBackpressureError", func() {
+		// Given a wrapped ConsoleBackpressureError with a Retry-After
+		// When checked with IsConsoleBackpressureError and ConsoleRetryAfter
+		// Then it should match and expose the wait, and not be a client error
+		It("should be detected through wrapping and carry the wait", func() {
+			// Arrange
+			wrapped := fmt.Errorf("dispatch: %w", srvErrors.NewConsoleBackpressureError(429, 2*time.Second))
+
+			// Act & Assert
+			Expect(srvErrors.IsConsoleBackpressureError(wrapped)).To(BeTrue())
+			Expect(srvErrors.IsConsoleClientError(wrapped)).To(BeFalse())
+			Expect(srvErrors.ConsoleRetryAfter(wrapped)).To(Equal(2 * time.Second))
+			Expect(srvErrors.ConsoleRetryAfter(errors.New("other"))).To(BeZero())
+		})
+	})
+
+	Context("CredentialsError", func() {
+		// Given a wrapped CredentialsError
+		// When checked with IsCredentialsError and IsVCenterError
+		// Then only IsCredentialsError should match
+		It("should be detected through wrapping and differ from VCenterError", func() {
+			// Arrange
+			wrapped := fmt.Errorf("login: %w", srvErrors.NewCredentialsError())
+
+			// Act & Assert
+			Expect(srvErrors.IsCredentialsError(wrapped)).To(BeTrue())
+			Expect(srvErrors.IsVCenterError(wrapped)).To(BeFalse())
+			Expect(wrapped.Error()).To(Equal("login: invalid credentials"))
+		})
+	})
+
 	Context("InspectorNotRunningError", func() {
 		// Given an InspectorNotRunningError
 		// When Error() is called
@@ -454,6 +652,56 @@ var _ = Describe("Errors", func() {
 		})
 	})
 
+	Context("RequestTimeoutError", func() {
+		It("should format the message with the timeout", func() {
+			err := srvErrors.NewRequestTimeoutError(30 * time.Second)
+			Expect(err.Error()).To(Equal("request timed out after 30s"))
+		})
+
+		It("should be detected when wrapped", func() {
+			wrapped := fmt.Errorf("list vms: %w", srvErrors.NewRequestTimeoutError(time.Second))
+			Expect(srvErrors.IsRequestTimeoutError(wrapped)).To(BeTrue())
+		})
+
+		It("should not match unrelated errors", func() {
+			Expect(srvErrors.IsRequestTimeoutError(errors.New("nope"))).To(BeFalse())
+		})
+	})
+
+	Context("ValidationError", func() {
+		// Given per-field messages
+		// When a field validation error is created
+		// Then the message should list the fields in name order
+		It("should format the fields in a stable order", func() {
+			// Act
+			err := srvErrors.NewFieldValidationError(map[string]string{"url": "is required", "password": "is required"})
+
+			// Assert
+			Expect(err.Error()).To(Equal("password is required; url is required"))
+			Expect(err.Fields).To(HaveLen(2))
+		})
+
+		// Given a wrapped field validation error
+		// When extracted with GetValidationError
+		// Then the fields should be available
+		It("should be extracted when wrapped", func() {
+			// Arrange
+			wrapped := fmt.Errorf("bind: %w", srvErrors.NewFieldValidationError(map[string]string{"url": "is required"}))
+
+			// Act
+			ve := srvErrors.GetValidationError(wrapped)
+
+			// Assert
+			Expect(srvErrors.IsValidationError(wrapped)).To(BeTrue())
+			Expect(ve).NotTo(BeNil())
+			Expect(ve.Fields).To(HaveKeyWithValue("url", "is required"))
+		})
+
+		It("should return nil for unrelated errors", func() {
+			Expect(srvErrors.GetValidationError(errors.New("nope"))).To(BeNil())
+		})
+	})
+
 	Context("cross-type isolation", func() {
 		// Given errors of different types
 		// When each Is* function checks the wrong type
@@ -19,6 +19,8 @@ func (q QuantityUnit) String() string {
 		return "Gb"
 	case TbQuantityUnit:
 		return "Tb"
+	case PercentQuantityUnit:
+		return "%"
 	case NoQuantityUnit:
 		return "noUnit"
 	default:
@@ -32,6 +34,8 @@ const (
 	MbQuantityUnit // this is the baseline. In db, we store as Mb
 	GbQuantityUnit
 	TbQuantityUnit
+	// PercentQuantityUnit is a percentage, compared as a ratio (150% is 1.5).
+	PercentQuantityUnit
 )
 
 // Expression is the abstract syntax tree for any expression.
@@ -113,7 +117,10 @@ func newQuantityExpression(val string) *quantityExpression {
 	qe := &quantityExpression{Unit: NoQuantityUnit}
 
 	numStr := val
-	if len(val) >= 3 {
+	if strings.HasSuffix(val, "%") {
+		qe.Unit = PercentQuantityUnit
+		numStr = val[:len(val)-1]
+	} else if len(val) >= 3 {
 		suffix := strings.ToLower(val[len(val)-2:])
 		switch suffix {
 		case "kb":
@@ -9,8 +9,9 @@
 //	expression  : term ( "or" term )* ;
 //	term        : factor ( "and" factor )* ;
 //	factor      : equality | "(" expression ")" ;
-//	equality    : IDENTIFIER ( "=" | "!=" | "<" | "<=" | ">" | ">=" ) value
-//	            | IDENTIFIER ( "~" | "!~" ) REGEX_LITERAL
+//	equality    : IDENTIFIER ( "=" | "!=" | "<" | "<=" | ">" | ">=" ) ( value | IDENTIFIER )
+//	            | IDENTIFIER ( "~" | "!~" | "~*" | "!~*" ) REGEX_LITERAL
+//	            | IDENTIFIER ( "like" | "contains" | "not" "contains" ) STRING
 //	            | IDENTIFIER "in" "[" STRING ( "," STRING )* "]"
 //	            | IDENTIFIER "not" "in" "[" STRING ( "," STRING )* "]" ;
 //	value       : STRING | QUANTITY | BOOLEAN ;
@@ -19,7 +20,7 @@
 //	REGEX_LITERAL : '/' ( '\\/' | . )*? '/' ;
 //	STRING        : "'" (.*?) "'" | '"' (.*?) '"' ;
 //	BOOLEAN       : "true" | "false" ;
-//	QUANTITY      : [0-9]+(\.[0-9]+)? ( 'KB' | 'MB' | 'GB' | 'TB' )? ;
+//	QUANTITY      : [0-9]+(\.[0-9]+)? ( 'KB' | 'MB' | 'GB' | 'TB' | '%' )? ;
 //
 // # Operators
 //
@@ -31,6 +32,11 @@
 //	<=   Less than or equal
 //	~      Regex match (uses regexp_matches)
 //	!~     Regex not match
+//	~*     Case-insensitive regex match (regexp_matches with the 'i' option)
+//	!~*    Case-insensitive regex not match
+//	like   Substring match (SQL LIKE '%…%')
+//	contains     Literal substring match; % and _ are escaped
+//	not contains Literal substring exclusion
 //	in     Membership test (SQL IN clause)
 //	not in Exclusion test (SQL NOT IN clause)
 //	and    Logical AND (higher precedence than OR)
@@ -60,12 +66,35 @@
 //	memory > 1024KB     // 1 MB
 //	count = 100         // plain number (no conversion)
 //
+// Percentages: Numbers followed directly by '%' are divided by 100, so they
+// compare against columns holding ratios (1.0 = 100%). '%' is only accepted
+// right after a number; there is no modulo operator.
+//
+//	cpu_overcommit > 150%    // 1.5
+//	utilization <= 80%       // 0.8
+//	ratio >= 12.5%           // 0.125
+//
 // Regex: AWK-style patterns between forward slashes.
 //
 //	name ~ /^prod-.*/           // starts with "prod-"
 //	name ~ /web|api/            // contains "web" or "api"
 //	name !~ /test/              // does not contain "test"
 //	path ~ /a\/b/               // escaped slash matches "a/b"
+//	os_config ~* /windows/      // matches "Windows", "WINDOWS", ...
+//
+// Fields: Another identifier compares two columns of the same row. Both
+// sides are resolved by the MapFunc, quoted or not, so unknown fields are
+// rejected, and both must have the same field type.
+//
+//	storage_used > provisioned      // (v."In Use MiB" > v."Provisioned MiB")
+//	disk.capacity >= datastore.free
+//	cluster = net.cluster
+//
+// Substrings: contains matches a string literally and case-sensitively; the
+// LIKE wildcards % and _ in the value have no special meaning.
+//
+//	name contains 'prod'        // name LIKE '%prod%' ESCAPE '\'
+//	name not contains '50%'     // name NOT LIKE '%50\%%' ESCAPE '\'
 //
 // Lists: Comma-separated strings in square brackets for IN/NOT IN operators.
 //
@@ -82,6 +111,17 @@
 //	vm.host.datacenter = 'DC1'
 //	config.nested.value > 100
 //
+// Names containing spaces or other characters can be quoted with backticks.
+// A quoted segment may follow a dotted prefix and must end the identifier:
+//
+//	`DNS Name` = 'vm01.example.com'
+//	disk.`Disk Path` ~ /DS1/
+//
+// The default MapFunc resolves quoted identifiers to the raw column of the
+// table named by the prefix (vinfo when there is none). Only columns behind a
+// known identifier are accepted, with that identifier's type. Square brackets
+// are reserved for lists and cannot be used for quoting.
+//
 // # Operator Precedence
 //
 // AND binds tighter than OR. Use parentheses to override:
@@ -148,7 +188,36 @@
 //	powerstate (alias: status), connection_state, ft_state, cpus, memory,
 //	os_config, os_tools, dns_name, ip_address, storage_used, template,
 //	cbt, enable_uuid, datacenter, cluster, hw_version, total_disk_capacity,
-//	provisioned, resource_pool
+//	provisioned, resource_pool, issues_count, critical_count, warning_count,
+//	snapshot_count, readiness
+//
+// readiness is the VM's migration readiness, from the categories of its
+// concerns: 'blocked' with a Critical concern, 'warning' with a Warning one
+// but no Critical one, 'migratable' otherwise:
+//
+//	readiness in ['warning', 'blocked']
+//
+// snapshot_count is the number of snapshots the VM had in vCenter at
+// collection time, so VMs with snapshots are selected with:
+//
+//	snapshot_count > 0
+//
+// vcreation (vcr) — date field:
+//
+//	created
+//
+// created is the VM creation date read from vCenter. Date fields compare with
+// ISO 8601 strings, either a day or a timestamp (UTC unless a zone is given),
+// and accept only =, !=, <, <=, > and >=. VMs without a creation date never
+// match:
+//
+//	created < '2024-01-01'
+//	created >= '2024-01-01T08:30:00+02:00'
+//
+// datacenter is the VM's own datacenter, so every cluster of a datacenter
+// can be selected without listing the cluster names:
+//
+//	datacenter = 'DC1' and cluster != 'production'
 //
 // vdisk (dk) — disk.* prefix:
 //
@@ -230,6 +299,7 @@
 //	name ~ /^prod-/
 //	disk.path ~ /datastore1/
 //	concern.label ~ /RDM/
+//	os_config ~* /red hat/
 //
 // IN/NOT IN:
 //
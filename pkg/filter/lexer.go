@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -36,12 +37,31 @@ func (l *lexer) Scan() (int, Token, string) {
 	ch := l.ch
 	l.next()
 
+	// backtick-quoted identifiers, e.g. `DNS Name`
+	if ch == '`' {
+		name, err := l.scanQuotedIdentifier()
+		if err != nil {
+			return pos, illegal, err.Error()
+		}
+		return pos, identifier, "`" + name + "`"
+	}
+
 	// keywords and identifiers
 	if isIdentifierStart(ch) {
 		start := l.tokenStart()
 		for isIdentifierStart(l.ch) || isDot(l.ch) {
 			if isDot(l.ch) {
 				l.next()
+				// a quoted segment ends the identifier, e.g. disk.`Disk Path`
+				if l.ch == '`' {
+					prefix := string(l.src[start:l.tokenEnd()])
+					l.next()
+					name, err := l.scanQuotedIdentifier()
+					if err != nil {
+						return pos, illegal, err.Error()
+					}
+					return pos, identifier, prefix + "`" + name + "`"
+				}
 				if !isIdentifierStart(l.ch) {
 					return pos, illegal, "identifier cannot end with a dot or have consecutive dots"
 				}
@@ -63,6 +83,8 @@ func (l *lexer) Scan() (int, Token, string) {
 			val = name
 		case "like":
 			tok = like2
+		case "contains":
+			tok = contains
 		default:
 			tok = identifier
 			val = name
@@ -89,6 +111,8 @@ func (l *lexer) Scan() (int, Token, string) {
 				return pos, illegal, "quantity unit is malformed"
 			}
 			l.next()
+		} else if l.ch == '%' {
+			l.next()
 		}
 		val = string(l.src[start:l.tokenEnd()])
 		tok = quantity
@@ -110,6 +134,10 @@ func (l *lexer) Scan() (int, Token, string) {
 		tok = equal
 	case '~':
 		tok = like
+		if l.ch == '*' {
+			tok = iLike
+			l.next()
+		}
 	case '!':
 		switch l.ch {
 		case '=':
@@ -118,6 +146,10 @@ func (l *lexer) Scan() (int, Token, string) {
 		case '~':
 			tok = notLike
 			l.next()
+			if l.ch == '*' {
+				tok = notILike
+				l.next()
+			}
 		default:
 			tok = illegal
 		}
@@ -200,6 +232,24 @@ func (l *lexer) next() {
 	l.offset++
 }
 
+// scanQuotedIdentifier reads a backtick-quoted identifier. The opening backtick
+// has already been consumed; the closing one is consumed here.
+func (l *lexer) scanQuotedIdentifier() (string, error) {
+	chars := make([]byte, 0, 32)
+	for l.ch != '`' {
+		if l.ch == 0 {
+			return "", errors.New("unclosed quoted identifier")
+		}
+		chars = append(chars, l.ch)
+		l.next()
+	}
+	l.next()
+	if strings.TrimSpace(string(chars)) == "" {
+		return "", errors.New("empty quoted identifier")
+	}
+	return string(chars), nil
+}
+
 func isIdentifierStart(ch byte) bool {
 	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_'
 }
@@ -29,6 +29,9 @@ var _ = Describe("Lexer", func() {
 			// Regex operators
 			{input: "~", output: "like eol"},
 			{input: "!~", output: "notLike eol"},
+			{input: "~*", output: "iLike eol"},
+			{input: "!~*", output: "notILike eol"},
+			{input: "~ *", output: "like illegal eol"},
 
 			// All operators together
 			{input: "= != < <= > >= ~ !~", output: "equal notEqual less lte greater gte like notLike eol"},
@@ -68,6 +71,11 @@ var _ = Describe("Lexer", func() {
 			{input: "not in", output: "not in eol"},
 			{input: "status not in ['a', 'b']", output: "identifier not in [ stringLit , stringLit ] eol"},
 
+			// ===== CONTAINS OPERATOR =====
+			{input: "contains", output: "contains eol"},
+			{input: "CONTAINS", output: "contains eol"},
+			{input: "name not contains 'prod'", output: "identifier not contains stringLit eol"},
+
 			// ===== STRINGS =====
 			// Single quoted strings
 			{input: "'test'", output: "stringLit eol"},
@@ -124,6 +132,13 @@ var _ = Describe("Lexer", func() {
 			{input: "100.25MB", output: "quantity eol"},
 			{input: "0.5TB", output: "quantity eol"},
 
+			// Percentages
+			{input: "150%", output: "quantity eol"},
+			{input: "12.5%", output: "quantity eol"},
+			{input: "150 %", output: "quantity illegal eol"},
+			{input: "5GB%", output: "quantity illegal eol"},
+			{input: "150%%", output: "quantity illegal eol"},
+
 			// Without units (plain numbers)
 			{input: "100", output: "quantity eol"},
 			{input: "0", output: "quantity eol"},
@@ -252,6 +267,14 @@ var _ = Describe("Lexer", func() {
 				input:  "vm.name !~ /test/",
 				output: "identifier notLike regexLit eol",
 			},
+			{
+				input:  "os ~* /windows/",
+				output: "identifier iLike regexLit eol",
+			},
+			{
+				input:  "os!~*/linux/",
+				output: "identifier notILike regexLit eol",
+			},
 			{
 				input:  "name ~ /pattern/ and status = 'active'",
 				output: "identifier like regexLit and identifier equal stringLit eol",
@@ -267,6 +290,23 @@ var _ = Describe("Lexer", func() {
 				output: "identifier equal boolean and identifier equal boolean eol",
 			},
 
+			// Quoted identifiers
+			{
+				input:  "`DNS Name` = 'vm.local'",
+				output: "identifier equal stringLit eol",
+			},
+			{
+				input:  "disk.`Disk Path` ~ /DS1/",
+				output: "identifier like regexLit eol",
+			},
+			{
+				input:  "`Cluster` in ['a', 'b']",
+				output: "identifier in [ stringLit , stringLit ] eol",
+			},
+			{input: "`DNS Name", output: "illegal eol"},
+			{input: "`  ` = 'x'", output: "illegal equal stringLit eol"},
+			{input: "disk.`Disk Path", output: "illegal eol"},
+
 			// Mixed types
 			{
 				input:  "name ~ /prod/ and enabled = true and count > '10'",
@@ -294,6 +334,29 @@ var _ = Describe("Lexer", func() {
 		}
 	})
 
+	Context("quoted identifiers", func() {
+		It("should keep spaces and case inside backticks", func() {
+			l := newLexer([]byte("`DNS Name`"))
+			_, tok, val := l.Scan()
+			Expect(tok).To(Equal(identifier))
+			Expect(val).To(Equal("`DNS Name`"))
+		})
+
+		It("should keep the prefix of a dotted quoted identifier", func() {
+			l := newLexer([]byte("disk.`Disk Path`"))
+			_, tok, val := l.Scan()
+			Expect(tok).To(Equal(identifier))
+			Expect(val).To(Equal("disk.`Disk Path`"))
+		})
+
+		It("should report an unclosed quoted identifier", func() {
+			l := newLexer([]byte("`DNS Name"))
+			_, tok, val := l.Scan()
+			Expect(tok).To(Equal(illegal))
+			Expect(val).To(Equal("unclosed quoted identifier"))
+		})
+	})
+
 	Context("escaped string values", func() {
 		It("should unescape single quotes", func() {
 			l := newLexer([]byte(`'it\'s'`))
@@ -120,7 +120,9 @@ func (p *parser) factor() Expression {
 
 // equality parses a comparison expression.
 //
-// IDENTIFIER ( "=" | "!=" | "<" | "<=" | ">" | ">=" | "~" | "!~" ) value
+// IDENTIFIER ( "=" | "!=" | "<" | "<=" | ">" | ">=" ) ( value | IDENTIFIER )
+// IDENTIFIER ( "~" | "!~" | "~*" | "!~*" ) REGEX_LITERAL
+// IDENTIFIER ( "like" | "contains" | "not" "contains" ) STRING
 // IDENTIFIER "in" "[" STRING ( "," STRING )* "]"
 func (p *parser) equality() Expression {
 	p.expect(identifier)
@@ -135,6 +137,11 @@ func (p *parser) equality() Expression {
 	}
 	if p.tok == not {
 		p.next()
+		if p.tok == contains {
+			p.next()
+			p.expect(stringLit)
+			return &binaryExpression{Left: left, Op: notContains, Right: p.value()}
+		}
 		p.expect(in)
 		p.next()
 		values := p.list()
@@ -146,11 +153,11 @@ func (p *parser) equality() Expression {
 	case equal, notEqual, greater, gte, less, lte:
 		op = p.tok
 		p.next()
-	case like, notLike:
+	case like, notLike, iLike, notILike:
 		op = p.tok
 		p.next()
 		p.expect(regexLit)
-	case like2:
+	case like2, contains:
 		op = p.tok
 		p.next()
 		p.expect(stringLit)
@@ -195,11 +202,13 @@ func (p *parser) list() []string {
 	return values
 }
 
-// value parses a value (string, quantity, boolean, or regex).
+// value parses a value (string, quantity, boolean, regex, or another field).
 func (p *parser) value() Expression {
 	var expr Expression
 
 	switch p.tok {
+	case identifier:
+		expr = &varExpression{Name: p.val}
 	case stringLit:
 		expr = &stringExpression{Value: p.val}
 	case quantity:
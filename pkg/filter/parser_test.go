@@ -33,6 +33,12 @@ var _ = Describe("Parser", func() {
 			{input: "name ~ /^prod-.*/", output: "(name like /^prod-.*/)"},
 			{input: "name !~ /test/", output: "(name notLike /test/)"},
 			{input: "name ~ /a\\/b/", output: "(name like /a/b/)"},
+			{input: "name ~* /pattern/", output: "(name iLike /pattern/)"},
+			{input: "name !~* /pattern/", output: "(name notILike /pattern/)"},
+
+			// ===== FIELD VALUES =====
+			{input: "memory > disk", output: "(memory greater disk)"},
+			{input: "disk.capacity >= datastore.free", output: "(disk.capacity gte datastore.free)"},
 
 			// ===== BOOLEAN VALUES =====
 			{input: "enabled = true", output: "(enabled equal true)"},
@@ -55,6 +61,8 @@ var _ = Describe("Parser", func() {
 			{input: "disk = 1TB", output: "(disk equal 1.00Tb)"},
 			{input: "memory > 1.5GB", output: "(memory greater 1.50Gb)"},
 			{input: "memory > 100.25MB", output: "(memory greater 100.25Mb)"},
+			{input: "cpu_overcommit > 150%", output: "(cpu_overcommit greater 150.00%)"},
+			{input: "utilization <= 12.5%", output: "(utilization lte 12.50%)"},
 
 			// Without units (plain numbers)
 			{input: "count > 100", output: "(count greater 100.00)"},
@@ -171,6 +179,12 @@ var _ = Describe("Parser", func() {
 			{input: "name like 'test'", output: `(name like2 "test")`},
 			{input: "name like 'prod-db'", output: `(name like2 "prod-db")`},
 			{input: "name like 'test' and active = true", output: `((name like2 "test") and (active equal true))`},
+
+			// ===== CONTAINS / NOT CONTAINS OPERATORS =====
+			{input: "name contains 'prod'", output: `(name contains "prod")`},
+			{input: "name CONTAINS '50%'", output: `(name contains "50%")`},
+			{input: "name not contains 'test'", output: `(name notContains "test")`},
+			{input: "name not contains 'a' and name contains 'b'", output: `((name notContains "a") and (name contains "b"))`},
 		}
 
 		for _, test := range tests {
@@ -196,6 +210,12 @@ var _ = Describe("Parser", func() {
 			"name ~ 'string'",
 			"name !~ 'string'",
 			"name like /pattern/",
+			"name ~ other",
+			"name like other",
+			"name contains /pattern/",
+			"name contains other",
+			"name not contains",
+			"name contains 5GB",
 		}
 
 		for _, input := range inputs {
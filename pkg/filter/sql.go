@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 )
@@ -13,177 +14,200 @@ import (
 // The function should return an error for unknown identifiers.
 type MapFunc func(name string) (string, FieldType, error)
 
-var defaultMapFn MapFunc = func(name string) (string, FieldType, error) {
-	switch strings.ToLower(name) {
+// readinessColumn derives a VM's migration readiness from its concerns:
+// 'blocked' with a Critical one, 'warning' with a Warning one, 'migratable'
+// otherwise.
+const readinessColumn = `(CASE` +
+	` WHEN EXISTS (SELECT 1 FROM concerns rc WHERE rc."VM_ID" = v."VM ID" AND rc."Category" = 'Critical') THEN 'blocked'` +
+	` WHEN EXISTS (SELECT 1 FROM concerns rw WHERE rw."VM_ID" = v."VM ID" AND rw."Category" = 'Warning') THEN 'warning'` +
+	` ELSE 'migratable' END)`
+
+// field is the SQL column a filter identifier resolves to and its type.
+type field struct {
+	column string
+	typ    FieldType
+}
+
+// defaultFields maps the identifiers of the VM filter to their columns. It is
+// the allowlist for both plain and quoted identifiers.
+var defaultFields = map[string]field{
 	// vinfo (v) — string fields
-	case "id":
-		return `v."VM ID"`, StringField, nil
-	case "name":
-		return `v."VM"`, StringField, nil
-	case "folder_id":
-		return `v."Folder ID"`, StringField, nil
-	case "folder":
-		return `v."Folder"`, StringField, nil
-	case "host":
-		return `v."Host"`, StringField, nil
-	case "smbios_uuid":
-		return `v."SMBIOS UUID"`, StringField, nil
-	case "vm_uuid":
-		return `v."VM UUID"`, StringField, nil
-	case "firmware":
-		return `v."Firmware"`, StringField, nil
-	case "powerstate", "status":
-		return `v."Powerstate"`, StringField, nil
-	case "connection_state":
-		return `v."Connection state"`, StringField, nil
-	case "ft_state":
-		return `v."FT State"`, StringField, nil
-	case "os_config":
-		return `v."OS according to the configuration file"`, StringField, nil
-	case "os_tools":
-		return `v."OS according to the VMware Tools"`, StringField, nil
-	case "dns_name":
-		return `v."DNS Name"`, StringField, nil
-	case "ip_address":
-		return `v."Primary IP Address"`, StringField, nil
-	case "hw_version":
-		return `v."HW version"`, StringField, nil
-	case "resource_pool":
-		return `v."Resource pool"`, StringField, nil
-	case "datacenter":
-		return `v."Datacenter"`, StringField, nil
-	case "cluster":
-		return `v."Cluster"`, StringField, nil
+	"id":               {`v."VM ID"`, StringField},
+	"name":             {`v."VM"`, StringField},
+	"folder_id":        {`v."Folder ID"`, StringField},
+	"folder":           {`v."Folder"`, StringField},
+	"host":             {`v."Host"`, StringField},
+	"smbios_uuid":      {`v."SMBIOS UUID"`, StringField},
+	"vm_uuid":          {`v."VM UUID"`, StringField},
+	"firmware":         {`v."Firmware"`, StringField},
+	"powerstate":       {`v."Powerstate"`, StringField},
+	"status":           {`v."Powerstate"`, StringField},
+	"connection_state": {`v."Connection state"`, StringField},
+	"ft_state":         {`v."FT State"`, StringField},
+	"os_config":        {`v."OS according to the configuration file"`, StringField},
+	"os_tools":         {`v."OS according to the VMware Tools"`, StringField},
+	"dns_name":         {`v."DNS Name"`, StringField},
+	"ip_address":       {`v."Primary IP Address"`, StringField},
+	"hw_version":       {`v."HW version"`, StringField},
+	"resource_pool":    {`v."Resource pool"`, StringField},
+	"datacenter":       {`v."Datacenter"`, StringField},
+	"cluster":          {`v."Cluster"`, StringField},
 
 	// vinfo (v) — numeric fields
-	case "cpus":
-		return `v."CPUs"`, NumericField, nil
-	case "memory":
-		return `v."Memory"`, NumericField, nil
-	case "storage_used":
-		return `v."In Use MiB"`, NumericField, nil
-	case "total_disk_capacity":
-		return `d.total_disk`, NumericField, nil
-	case "provisioned":
-		return `v."Provisioned MiB"`, NumericField, nil
-	case "issues_count":
-		return `cc."issues_count"`, NumericField, nil
+	"cpus":                {`v."CPUs"`, NumericField},
+	"memory":              {`v."Memory"`, NumericField},
+	"storage_used":        {`v."In Use MiB"`, NumericField},
+	"total_disk_capacity": {`d.total_disk`, NumericField},
+	"provisioned":         {`v."Provisioned MiB"`, NumericField},
+	"issues_count":        {`cc."issues_count"`, NumericField},
+	"critical_count":      {`COALESCE(crit.critical_count, 0)`, NumericField},
+	"warning_count":       {`COALESCE(warn.warning_count, 0)`, NumericField},
+	"snapshot_count":      {`COALESCE(snap.snapshot_count, 0)`, NumericField},
 
 	// vinfo (v) — boolean fields
-	case "template":
-		return `v."Template"`, BooleanField, nil
-	case "cbt":
-		return `v."CBT"`, BooleanField, nil
-	case "enable_uuid":
-		return `v."EnableUUID"`, BooleanField, nil
-	case "migratable":
-		return `(COALESCE(crit.critical_count, 0) = 0)`, BooleanField, nil
+	"template":    {`v."Template"`, BooleanField},
+	"cbt":         {`v."CBT"`, BooleanField},
+	"enable_uuid": {`v."EnableUUID"`, BooleanField},
+	"migratable":  {`(COALESCE(crit.critical_count, 0) = 0)`, BooleanField},
+
+	// computed from the concerns' categories
+	"readiness": {readinessColumn, StringField},
+
+	// vcreation (vcr) — date fields
+	"created": {`vcr."Created"`, DateField},
 
 	// vdisk (dk) — disk.* prefix
-	case "disk.path":
-		return `dk."Disk Path"`, StringField, nil
-	case "disk.sharing":
-		return `dk."Sharing mode"`, StringField, nil
-	case "disk.shared_bus":
-		return `dk."Shared Bus"`, StringField, nil
-	case "disk.mode":
-		return `dk."Disk Mode"`, StringField, nil
-	case "disk.controller":
-		return `dk."Controller"`, StringField, nil
-	case "disk.label":
-		return `dk."Label"`, StringField, nil
-	case "disk.key":
-		return `dk."Disk Key"`, NumericField, nil
-	case "disk.capacity":
-		return `dk."Capacity MiB"`, NumericField, nil
-	case "disk.raw":
-		return `dk."Raw"`, BooleanField, nil
-	case "disk.thin":
-		return `dk."Thin"`, BooleanField, nil
+	"disk.path":       {`dk."Disk Path"`, StringField},
+	"disk.sharing":    {`dk."Sharing mode"`, StringField},
+	"disk.shared_bus": {`dk."Shared Bus"`, StringField},
+	"disk.mode":       {`dk."Disk Mode"`, StringField},
+	"disk.controller": {`dk."Controller"`, StringField},
+	"disk.label":      {`dk."Label"`, StringField},
+	"disk.key":        {`dk."Disk Key"`, NumericField},
+	"disk.capacity":   {`dk."Capacity MiB"`, NumericField},
+	"disk.raw":        {`dk."Raw"`, BooleanField},
+	"disk.thin":       {`dk."Thin"`, BooleanField},
 
 	// concerns (c) — concern.* prefix
-	case "concern.label":
-		return `c."Label"`, StringField, nil
-	case "concern.category":
-		return `c."Category"`, StringField, nil
-	case "concern.assessment":
-		return `c."Assessment"`, StringField, nil
+	"concern.label":      {`c."Label"`, StringField},
+	"concern.category":   {`c."Category"`, StringField},
+	"concern.assessment": {`c."Assessment"`, StringField},
 
 	// vm_inspection_status (i) — inspection.* prefix
-	case "inspection.status":
-		return `i.status`, StringField, nil
-	case "inspection.error":
-		return `i.error`, StringField, nil
+	"inspection.status": {`i.status`, StringField},
+	"inspection.error":  {`i.error`, StringField},
 
 	// vm_inspection_concerns (ic) — inspection_concern.* prefix
-	case "inspection_concern.label":
-		return `ic.label`, StringField, nil
-	case "inspection_concern.category":
-		return `ic.category`, StringField, nil
-	case "inspection_concern.msg":
-		return `ic.msg`, StringField, nil
+	"inspection_concern.label":    {`ic.label`, StringField},
+	"inspection_concern.category": {`ic.category`, StringField},
+	"inspection_concern.msg":      {`ic.msg`, StringField},
 
 	// vcpu (cpu) — cpu.* prefix
-	case "cpu.sockets":
-		return `cpu."Sockets"`, NumericField, nil
-	case "cpu.cores_per_socket":
-		return `cpu."Cores p/s"`, NumericField, nil
-	case "cpu.hot_add":
-		return `cpu."Hot Add"`, BooleanField, nil
-	case "cpu.hot_remove":
-		return `cpu."Hot Remove"`, BooleanField, nil
+	"cpu.sockets":          {`cpu."Sockets"`, NumericField},
+	"cpu.cores_per_socket": {`cpu."Cores p/s"`, NumericField},
+	"cpu.hot_add":          {`cpu."Hot Add"`, BooleanField},
+	"cpu.hot_remove":       {`cpu."Hot Remove"`, BooleanField},
 
 	// vmemory (mem) — mem.* prefix
-	case "mem.ballooned":
-		return `mem."Ballooned"`, NumericField, nil
-	case "mem.hot_add":
-		return `mem."Hot Add"`, BooleanField, nil
+	"mem.ballooned": {`mem."Ballooned"`, NumericField},
+	"mem.hot_add":   {`mem."Hot Add"`, BooleanField},
 
 	// vnetwork (net) — net.* prefix
-	case "net.network":
-		return `net."Network"`, StringField, nil
-	case "net.mac":
-		return `net."Mac Address"`, StringField, nil
-	case "net.nic_label":
-		return `net."NIC label"`, StringField, nil
-	case "net.adapter":
-		return `net."Adapter"`, StringField, nil
-	case "net.switch":
-		return `net."Switch"`, StringField, nil
-	case "net.type":
-		return `net."Type"`, StringField, nil
-	case "net.ipv4":
-		return `net."IPv4 Address"`, StringField, nil
-	case "net.ipv6":
-		return `net."IPv6 Address"`, StringField, nil
-	case "net.cluster":
-		return `net."Cluster"`, StringField, nil
-	case "net.connected":
-		return `net."Connected"`, BooleanField, nil
-	case "net.starts_connected":
-		return `net."Starts Connected"`, BooleanField, nil
+	"net.network":          {`net."Network"`, StringField},
+	"net.mac":              {`net."Mac Address"`, StringField},
+	"net.nic_label":        {`net."NIC label"`, StringField},
+	"net.adapter":          {`net."Adapter"`, StringField},
+	"net.switch":           {`net."Switch"`, StringField},
+	"net.type":             {`net."Type"`, StringField},
+	"net.ipv4":             {`net."IPv4 Address"`, StringField},
+	"net.ipv6":             {`net."IPv6 Address"`, StringField},
+	"net.cluster":          {`net."Cluster"`, StringField},
+	"net.connected":        {`net."Connected"`, BooleanField},
+	"net.starts_connected": {`net."Starts Connected"`, BooleanField},
 
 	// vdatastore (ds) — datastore.* prefix
-	case "datastore.name":
-		return `ds."Name"`, StringField, nil
-	case "datastore.address":
-		return `ds."Address"`, StringField, nil
-	case "datastore.object_id":
-		return `ds."Object ID"`, StringField, nil
-	case "datastore.mha":
-		return `ds."MHA"`, StringField, nil
-	case "datastore.type":
-		return `ds."Type"`, StringField, nil
-	case "datastore.hosts":
-		return `ds."Hosts"`, NumericField, nil
-	case "datastore.free":
-		return `ds."Free MiB"`, NumericField, nil
-	case "datastore.capacity":
-		return `ds."Capacity MiB"`, NumericField, nil
+	"datastore.name":      {`ds."Name"`, StringField},
+	"datastore.address":   {`ds."Address"`, StringField},
+	"datastore.object_id": {`ds."Object ID"`, StringField},
+	"datastore.mha":       {`ds."MHA"`, StringField},
+	"datastore.type":      {`ds."Type"`, StringField},
+	"datastore.hosts":     {`ds."Hosts"`, NumericField},
+	"datastore.free":      {`ds."Free MiB"`, NumericField},
+	"datastore.capacity":  {`ds."Capacity MiB"`, NumericField},
+}
 
-	default:
+// quotedFields indexes the plain columns of defaultFields by table alias and
+// raw column name (the dk and Disk Path of disk.`Disk Path`), for resolving
+// quoted identifiers.
+var quotedFields = func() map[string]field {
+	fields := make(map[string]field, len(defaultFields))
+	for _, f := range defaultFields {
+		alias, column, ok := strings.Cut(f.column, ".")
+		if !ok || strings.ContainsAny(alias, "( ") {
+			continue
+		}
+		fields[alias+"."+strings.Trim(column, `"`)] = f
+	}
+	return fields
+}()
+
+var defaultMapFn MapFunc = func(name string) (string, FieldType, error) {
+	if f, ok, err := quotedField(name); ok || err != nil {
+		return f.column, f.typ, err
+	}
+
+	f, ok := defaultFields[strings.ToLower(name)]
+	if !ok {
 		return "", 0, fmt.Errorf("unknown filter field: %s", name)
 	}
+	return f.column, f.typ, nil
+}
+
+// quotedTableAliases maps the prefix of a quoted identifier (the "disk" in
+// disk.`Disk Path`) to the table alias used by the VM filter query.
+// Unprefixed quoted identifiers refer to vinfo.
+var quotedTableAliases = map[string]string{
+	"":                   "v",
+	"disk":               "dk",
+	"concern":            "c",
+	"inspection":         "i",
+	"inspection_concern": "ic",
+	"cpu":                "cpu",
+	"mem":                "mem",
+	"net":                "net",
+	"datastore":          "ds",
+}
+
+// quotedField resolves a backtick-quoted identifier such as `DNS Name` or
+// disk.`Disk Path` to the known field of that raw column (v."DNS Name",
+// dk."Disk Path"). Columns missing from defaultFields are rejected like
+// unknown plain identifiers. ok is false when name is not quoted.
+func quotedField(name string) (f field, ok bool, err error) {
+	i := strings.IndexByte(name, '`')
+	if i < 0 || !strings.HasSuffix(name, "`") || i == len(name)-1 {
+		return field{}, false, nil
+	}
+
+	prefix := strings.TrimSuffix(strings.ToLower(name[:i]), ".")
+	alias, found := quotedTableAliases[prefix]
+	if !found {
+		return field{}, true, fmt.Errorf("unknown filter field: %s", name)
+	}
+
+	f, found = quotedFields[alias+"."+name[i+1:len(name)-1]]
+	if !found {
+		return field{}, true, fmt.Errorf("unknown filter field: %s", name)
+	}
+	return f, true, nil
+}
+
+// fieldName normalizes an identifier before it is resolved by a MapFunc.
+// Plain identifiers are case-insensitive; quoted segments keep their case.
+func fieldName(name string) string {
+	if i := strings.IndexByte(name, '`'); i >= 0 {
+		return strings.ToLower(name[:i]) + name[i:]
+	}
+	return strings.ToLower(name)
 }
 
 var groupMapFn MapFunc = func(name string) (string, FieldType, error) {
@@ -204,12 +228,24 @@ func toSql(expr Expression, mf MapFunc) (sq.Sqlizer, error) {
 	case *binaryExpression:
 		if e.Op != and && e.Op != or {
 			if v, ok := e.Left.(*varExpression); ok {
-				_, fieldType, err := mf(strings.ToLower(v.Name))
+				_, fieldType, err := mf(fieldName(v.Name))
 				if err != nil {
 					return nil, err
 				}
-				if err := checkValueType(fieldType, e.Right); err != nil {
+				if rv, ok := e.Right.(*varExpression); ok {
+					// Column-to-column comparison: the right side goes through
+					// the same MapFunc, so only known fields can be referenced.
+					_, rightType, err := mf(fieldName(rv.Name))
+					if err != nil {
+						return nil, err
+					}
+					if fieldType != AnyField && rightType != AnyField && fieldType != rightType {
+						return nil, fmt.Errorf("field %q is %s, but field %q is %s", v.Name, fieldType, rv.Name, rightType)
+					}
+				} else if err := checkValueType(fieldType, e.Right); err != nil {
 					return nil, fmt.Errorf("field %q is %s, but got %s value", v.Name, fieldType, e.Right.Type())
+				} else if fieldType == DateField {
+					return dateComparison(v.Name, e.Op, mf, e.Right.(*stringExpression))
 				}
 			}
 		}
@@ -240,6 +276,10 @@ func toSql(expr Expression, mf MapFunc) (sq.Sqlizer, error) {
 			return sq.Expr(fmt.Sprintf("regexp_matches(%s, %s)", leftSQL, rightSQL), args...), nil
 		case notLike:
 			return sq.Expr(fmt.Sprintf("NOT regexp_matches(%s, %s)", leftSQL, rightSQL), args...), nil
+		case iLike:
+			return sq.Expr(fmt.Sprintf("regexp_matches(%s, %s, 'i')", leftSQL, rightSQL), args...), nil
+		case notILike:
+			return sq.Expr(fmt.Sprintf("NOT regexp_matches(%s, %s, 'i')", leftSQL, rightSQL), args...), nil
 		case and:
 			return sq.And{left, right}, nil
 		case or:
@@ -247,11 +287,14 @@ func toSql(expr Expression, mf MapFunc) (sq.Sqlizer, error) {
 		case like2:
 			pattern := fmt.Sprintf("%%%v%%", rightArgs[0])
 			return sq.Expr(fmt.Sprintf("(%s %s ?)", leftSQL, e.Op.Sql()), append(leftArgs, pattern)...), nil
+		case contains, notContains:
+			pattern := "%" + escapeLike(fmt.Sprint(rightArgs[0])) + "%"
+			return sq.Expr(fmt.Sprintf(`(%s %s ? ESCAPE '\')`, leftSQL, e.Op.Sql()), append(leftArgs, pattern)...), nil
 		default:
 			return sq.Expr(fmt.Sprintf("(%s %s %s)", leftSQL, e.Op.Sql(), rightSQL), args...), nil
 		}
 	case *varExpression:
-		col, _, err := mf(strings.ToLower(e.Name))
+		col, _, err := mf(fieldName(e.Name))
 		if err != nil {
 			return nil, err
 		}
@@ -276,12 +319,14 @@ func toSql(expr Expression, mf MapFunc) (sq.Sqlizer, error) {
 			valueInMb = e.Value * 1024
 		case TbQuantityUnit:
 			valueInMb = e.Value * 1024 * 1024
+		case PercentQuantityUnit:
+			valueInMb = e.Value / 100
 		default:
 			valueInMb = e.Value
 		}
 		return sq.Expr("?", valueInMb), nil
 	case *inExpression:
-		col, ft, err := mf(strings.ToLower(e.Left.(*varExpression).Name))
+		col, ft, err := mf(fieldName(e.Left.(*varExpression).Name))
 		if err != nil {
 			return nil, err
 		}
@@ -297,6 +342,14 @@ func toSql(expr Expression, mf MapFunc) (sq.Sqlizer, error) {
 	}
 }
 
+// likeEscaper escapes the LIKE wildcards % and _, and the escape character
+// itself, so contains matches the value literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
 // FieldType describes the expected value type for a filter field.
 type FieldType int
 
@@ -306,6 +359,8 @@ const (
 	StringField
 	NumericField
 	BooleanField
+	// DateField is a timestamp column, compared with ISO 8601 date strings.
+	DateField
 )
 
 func (f FieldType) String() string {
@@ -318,6 +373,8 @@ func (f FieldType) String() string {
 		return "numeric"
 	case BooleanField:
 		return "boolean"
+	case DateField:
+		return "date"
 	default:
 		return "unknown"
 	}
@@ -340,6 +397,48 @@ func checkValueType(ft FieldType, value Expression) error {
 		if _, ok := value.(*booleanExpression); ok {
 			return nil
 		}
+	case DateField:
+		if s, ok := value.(*stringExpression); ok {
+			_, err := parseDate(s.Value)
+			return err
+		}
 	}
 	return errors.New("type mismatched")
 }
+
+// dateLayouts are the ISO 8601 forms accepted for date fields, from a plain
+// day to a full timestamp with a zone. Values without a zone are UTC.
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not an ISO 8601 date", s)
+}
+
+// dateComparison compares a date field with a date string. Only the ordering
+// operators apply to dates; a VM without a date never matches.
+func dateComparison(name string, op Token, mf MapFunc, value *stringExpression) (sq.Sqlizer, error) {
+	switch op {
+	case equal, notEqual, greater, gte, less, lte:
+	default:
+		return nil, fmt.Errorf("field %q is date, but %s only applies to string fields", name, op.Sql())
+	}
+
+	col, _, err := mf(fieldName(name))
+	if err != nil {
+		return nil, err
+	}
+	t, err := parseDate(value.Value)
+	if err != nil {
+		return nil, err
+	}
+	return sq.Expr(fmt.Sprintf("(%s %s ?)", col, op.Sql()), t), nil
+}
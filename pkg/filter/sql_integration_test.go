@@ -155,6 +155,38 @@ var _ = Describe("Filter Integration with DuckDB", func() {
 		})
 	})
 
+	Context("String case-insensitive regex (~* and !~*)", func() {
+		BeforeEach(func() {
+			_, err := db.Exec(`ALTER TABLE vms ADD COLUMN "os" VARCHAR`)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = db.Exec(`UPDATE vms SET "os" = CASE
+				WHEN "name" LIKE 'vm-web%' THEN 'Microsoft Windows Server 2019'
+				WHEN "name" LIKE 'vm-db%' THEN 'Red Hat Enterprise Linux 9'
+				WHEN "name" = 'vm-legacy' THEN 'WINDOWS XP'
+				ELSE 'Ubuntu Linux'
+			END`)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should match mixed-case values with a lowercase pattern", func() {
+			names, err := queryVMs("os ~* /windows/")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-legacy", "vm-web-01", "vm-web-02"}))
+		})
+
+		It("should stay case-sensitive with ~", func() {
+			names, err := queryVMs("os ~ /windows/")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(BeEmpty())
+		})
+
+		It("should exclude mixed-case values with !~*", func() {
+			names, err := queryVMs("os !~* /windows|red hat/")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-analytics", "vm-cache-01", "vm-test", "vm-worker-01", "vm-worker-02"}))
+		})
+	})
+
 	// ============================================================
 	// BOOLEAN COLUMN TESTS (active)
 	// ============================================================
@@ -749,6 +781,60 @@ var _ = Describe("Filter Integration with DuckDB", func() {
 		})
 	})
 
+	Context("CONTAINS operator", func() {
+		BeforeEach(func() {
+			_, err := db.Exec(`INSERT INTO vms VALUES
+				('vm-50%-cpu',  true,  2, 1024, 10240),
+				('vm_under',    true,  2, 1024, 10240),
+				('vmXunder',    true,  2, 1024, 10240),
+				('vm\backup',  false, 2, 1024, 10240)
+			`)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should find VMs whose name contains a substring", func() {
+			names, err := queryVMs("name contains 'web'")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-web-01", "vm-web-02"}))
+		})
+
+		It("should be case-sensitive", func() {
+			names, err := queryVMs("name contains 'WEB'")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(BeEmpty())
+		})
+
+		It("should match % literally", func() {
+			names, err := queryVMs("name contains '%'")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-50%-cpu"}))
+		})
+
+		It("should match _ literally", func() {
+			names, err := queryVMs("name contains 'm_u'")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm_under"}))
+		})
+
+		It("should match a backslash literally", func() {
+			names, err := queryVMs(`name contains '\b'`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{`vm\backup`}))
+		})
+
+		It("should exclude VMs with not contains", func() {
+			names, err := queryVMs("name not contains 'vm-' and name not contains 'under'")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{`vm\backup`}))
+		})
+
+		It("should combine contains with other filters", func() {
+			names, err := queryVMs("name contains 'db' and memory >= 32GB")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-db-01"}))
+		})
+	})
+
 	// ============================================================
 	// ALL OPERATOR COMBINATIONS
 	// ============================================================
@@ -1218,6 +1304,161 @@ var _ = Describe("Filter Integration with DuckDB", func() {
 		})
 	})
 
+	// ============================================================
+	// COLUMN VS COLUMN TESTS
+	// ============================================================
+
+	Context("Column comparison", func() {
+		BeforeEach(func() {
+			// vm-analytics gets less disk than memory so memory > disk matches one row
+			_, err := db.Exec(`UPDATE vms SET "disk" = 1024 WHERE "name" = 'vm-analytics'`)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should compare two numeric columns", func() {
+			names, err := queryVMs("memory > disk")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-analytics"}))
+		})
+
+		It("should return the complement with the inverse operator", func() {
+			names, err := queryVMs("disk >= memory")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(HaveLen(9))
+			Expect(names).NotTo(ContainElement("vm-analytics"))
+		})
+
+		It("should combine a column comparison with a literal", func() {
+			names, err := queryVMs("memory < disk and cpus >= 8")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-db-01", "vm-db-02"}))
+		})
+
+		It("should match a column compared with itself", func() {
+			names, err := queryVMs("cpus = cpus")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(HaveLen(10))
+		})
+	})
+
+	Context("Percentage values on a ratio column", func() {
+		BeforeEach(func() {
+			// Overcommitment stored as a ratio of allocated to available
+			// capacity: 1.0 is 100%.
+			_, err := db.Exec(`ALTER TABLE vms ADD COLUMN "cpu_overcommit" DOUBLE DEFAULT 1.0`)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = db.Exec(`UPDATE vms SET "cpu_overcommit" = CASE "name"
+				WHEN 'vm-db-01' THEN 2.0
+				WHEN 'vm-analytics' THEN 1.5
+				WHEN 'vm-web-02' THEN 1.25
+				WHEN 'vm-worker-02' THEN 0.5
+				ELSE 1.0 END`)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should compare a percentage against the ratio", func() {
+			names, err := queryVMs("cpu_overcommit > 150%")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-db-01"}))
+		})
+
+		It("should include the boundary with >=", func() {
+			names, err := queryVMs("cpu_overcommit >= 150%")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-analytics", "vm-db-01"}))
+		})
+
+		It("should accept fractional percentages", func() {
+			names, err := queryVMs("cpu_overcommit = 125.0%")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-web-02"}))
+		})
+
+		It("should combine a percentage with other quantities", func() {
+			names, err := queryVMs("cpu_overcommit < 100% or (cpu_overcommit > 100% and memory > 16GB)")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-analytics", "vm-db-01", "vm-worker-02"}))
+		})
+	})
+
+	Context("Date values on a timestamp column", func() {
+		dateMapper := func(name string) (string, FieldType, error) {
+			if name == "created" {
+				return `"created"`, DateField, nil
+			}
+			return testMapper(name)
+		}
+
+		queryByDate := func(filterExpr string) ([]string, error) {
+			sqlizer, err := Parse([]byte(filterExpr), dateMapper)
+			if err != nil {
+				return nil, err
+			}
+			query, args, err := sq.Select(`"name"`).From("vms").Where(sqlizer).OrderBy(`"name"`).ToSql()
+			if err != nil {
+				return nil, err
+			}
+			rows, err := db.Query(query, args...)
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = rows.Close() }()
+
+			var names []string
+			for rows.Next() {
+				var name string
+				if err := rows.Scan(&name); err != nil {
+					return nil, err
+				}
+				names = append(names, name)
+			}
+			return names, rows.Err()
+		}
+
+		BeforeEach(func() {
+			// vCenter only records a creation date for VMs created on
+			// vSphere 6.7 or later, so the legacy VM has none.
+			_, err := db.Exec(`ALTER TABLE vms ADD COLUMN "created" TIMESTAMP`)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = db.Exec(`UPDATE vms SET "created" = CASE "name"
+				WHEN 'vm-db-01' THEN TIMESTAMP '2019-03-14 10:00:00'
+				WHEN 'vm-db-02' THEN TIMESTAMP '2021-06-01 00:00:00'
+				WHEN 'vm-web-01' THEN TIMESTAMP '2023-11-20 16:45:00'
+				WHEN 'vm-legacy' THEN NULL
+				ELSE TIMESTAMP '2024-05-02 09:15:00' END`)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should select VMs created before a day", func() {
+			names, err := queryByDate("created < '2022-01-01'")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-db-01", "vm-db-02"}))
+		})
+
+		It("should include the boundary with >= and skip VMs without a date", func() {
+			names, err := queryByDate("created >= '2021-06-01' and created < '2024-01-01'")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-db-02", "vm-web-01"}))
+		})
+
+		It("should compare against a full timestamp", func() {
+			names, err := queryByDate("created > '2023-11-20T16:00:00Z' and created < '2023-11-20T17:00:00Z'")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-web-01"}))
+		})
+
+		It("should combine a date with other fields", func() {
+			names, err := queryByDate("created < '2024-01-01' and memory > 16GB")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"vm-db-01"}))
+		})
+
+		It("should reject a string that is not a date", func() {
+			_, err := queryByDate("created < 'yesterday'")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Context("SQL Injection Prevention - Verify Data Integrity After All Tests", func() {
 		It("should have all original data intact", func() {
 			// This test verifies that none of the injection attempts modified data
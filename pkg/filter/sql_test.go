@@ -3,6 +3,7 @@ package filter
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -88,6 +89,12 @@ var _ = Describe("SQL Generation", func() {
 			// ===== LESS THAN OR EQUAL =====
 			{input: "count <= '10'", output: `("count" <= '10')`},
 			{input: "rank <= '100'", output: `("rank" <= '100')`},
+
+			// ===== FIELD VS FIELD =====
+			{input: "memory > disk", output: `("memory" > "disk")`},
+			{input: "used >= capacity", output: `("used" >= "capacity")`},
+			{input: "host = cluster", output: `("host" = "cluster")`},
+			{input: "a != b and c > 4", output: `(("a" != "b") AND ("c" > 4.00))`},
 		}
 
 		for _, test := range tests {
@@ -125,6 +132,11 @@ var _ = Describe("SQL Generation", func() {
 			{input: "name !~ /excluded/", output: `NOT regexp_matches("name", 'excluded')`},
 			{input: "name !~ /[0-9]+/", output: `NOT regexp_matches("name", '[0-9]+')`},
 
+			// ===== CASE-INSENSITIVE (regex match with the 'i' option) =====
+			{input: "name ~* /windows/", output: `regexp_matches("name", 'windows', 'i')`},
+			{input: "name !~* /^test-/", output: `NOT regexp_matches("name", '^test-', 'i')`},
+			{input: "vm.os ~* /red hat|rhel/", output: `regexp_matches("vm.os", 'red hat|rhel', 'i')`},
+
 			// ===== REGEX WITH ESCAPED SLASHES =====
 			{input: "path ~ /a\\/b/", output: `regexp_matches("path", 'a/b')`},
 			{input: "url ~ /https:\\/\\//", output: `regexp_matches("url", 'https://')`},
@@ -253,6 +265,13 @@ var _ = Describe("SQL Generation", func() {
 			{input: "disk = 10TB", output: `("disk" = 10485760.00)`},
 			{input: "storage > 0.5TB", output: `("storage" > 524288.00)`},
 
+			// ===== PERCENTAGES (divide by 100) =====
+			{input: "cpu_overcommit > 150%", output: `("cpu_overcommit" > 1.50)`},
+			{input: "utilization <= 80%", output: `("utilization" <= 0.80)`},
+			{input: "utilization >= 0%", output: `("utilization" >= 0.00)`},
+			{input: "ratio = 2.5%", output: `("ratio" = 0.03)`},
+			{input: "cpu_overcommit > 150% and memory > 8GB", output: `(("cpu_overcommit" > 1.50) AND ("memory" > 8192.00))`},
+
 			// ===== PLAIN NUMBERS (no unit, no conversion) =====
 			{input: "count > 100", output: `("count" > 100.00)`},
 			{input: "count >= 50", output: `("count" >= 50.00)`},
@@ -679,6 +698,51 @@ var _ = Describe("SQL Generation", func() {
 		})
 	})
 
+	Context("CONTAINS operator", func() {
+		type testCase struct {
+			input  string
+			output string
+		}
+
+		tests := []testCase{
+			{input: "name contains 'prod'", output: `("name" LIKE '%prod%' ESCAPE '\')`},
+			{input: "name not contains 'prod'", output: `("name" NOT LIKE '%prod%' ESCAPE '\')`},
+			{input: "name contains '50%'", output: `("name" LIKE '%50\%%' ESCAPE '\')`},
+			{input: "name contains 'web_01'", output: `("name" LIKE '%web\_01%' ESCAPE '\')`},
+			{input: `name contains 'a\b'`, output: `("name" LIKE '%a\\b%' ESCAPE '\')`},
+		}
+
+		for _, test := range tests {
+			test := test
+			It("should generate SQL for: "+test.input, func() {
+				expr, err := parse([]byte(test.input))
+				Expect(err).ToNot(HaveOccurred())
+				sql, err := toSqlString(expr, sqlTestMapper)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sql).To(Equal(test.output))
+			})
+		}
+
+		It("should properly parameterize the contains value", func() {
+			expr, err := parse([]byte("name not contains 'test'"))
+			Expect(err).ToNot(HaveOccurred())
+			sqlizer, err := toSql(expr, sqlTestMapper)
+			Expect(err).ToNot(HaveOccurred())
+			sql, args, err := sqlizer.ToSql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sql).To(Equal(`("name" NOT LIKE ? ESCAPE '\')`))
+			Expect(args).To(Equal([]interface{}{"%test%"}))
+		})
+
+		It("should combine contains with AND", func() {
+			expr, err := parse([]byte("name contains 'prod' and name not contains 'test'"))
+			Expect(err).ToNot(HaveOccurred())
+			sql, err := toSqlString(expr, sqlTestMapper)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sql).To(Equal(`(("name" LIKE '%prod%' ESCAPE '\') AND ("name" NOT LIKE '%test%' ESCAPE '\'))`))
+		})
+	})
+
 	Context("IN operator", func() {
 		It("should generate SQL for single value IN", func() {
 			expr, err := parse([]byte("status in ['active']"))
@@ -884,6 +948,68 @@ var _ = Describe("SQL Generation", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("unknown filter field"))
 		})
+
+		It("should map a quoted identifier to the raw vinfo column", func() {
+			col, _, err := defaultMapFn("`DNS Name`")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(col).To(Equal(`v."DNS Name"`))
+		})
+
+		It("should map a prefixed quoted identifier to the raw column of that table", func() {
+			col, _, err := defaultMapFn("disk.`Disk Path`")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(col).To(Equal(`dk."Disk Path"`))
+		})
+
+		It("should give a quoted identifier the type of its known field", func() {
+			_, fieldType, err := defaultMapFn("disk.`Capacity MiB`")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fieldType).To(Equal(NumericField))
+		})
+
+		It("should return error for a quoted identifier naming an unknown column", func() {
+			_, _, err := defaultMapFn("`Annotation`")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown filter field"))
+		})
+
+		It("should return error for a quoted identifier holding a double quote", func() {
+			_, _, err := defaultMapFn("`a\"b`")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown filter field"))
+		})
+
+		It("should return error for a quoted identifier with an unknown prefix", func() {
+			_, _, err := defaultMapFn("bogus.`Disk Path`")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown filter field"))
+		})
+	})
+
+	Context("Quoted identifiers", func() {
+		It("should generate SQL with the raw column name", func() {
+			expr, err := parse([]byte("`DNS Name` = 'vm.local' and disk.`Disk Path` ~ /DS1/"))
+			Expect(err).ToNot(HaveOccurred())
+			sql, err := toSqlString(expr, defaultMapFn)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sql).To(Equal(`((v."DNS Name" = 'vm.local') AND regexp_matches(dk."Disk Path", 'DS1'))`))
+		})
+
+		It("should keep the case of the quoted segment while lowering the prefix", func() {
+			expr, err := parse([]byte("DISK.`Disk Path` = 'x'"))
+			Expect(err).ToNot(HaveOccurred())
+			sql, err := toSqlString(expr, defaultMapFn)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sql).To(Equal(`(dk."Disk Path" = 'x')`))
+		})
+
+		It("should support quoted identifiers in IN lists", func() {
+			expr, err := parse([]byte("`Cluster` in ['a', 'b']"))
+			Expect(err).ToNot(HaveOccurred())
+			sql, err := toSqlString(expr, defaultMapFn)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sql).To(Equal(`v."Cluster" IN ('a','b')`))
+		})
 	})
 
 	Context("groupMapFn field mappings", func() {
@@ -1058,6 +1184,13 @@ var _ = Describe("SQL Generation", func() {
 				{"any skips validation with quantity", "x > 8GB", map[string]FieldType{"x": AnyField}},
 				{"any skips validation with regex", "x ~ /p/", map[string]FieldType{"x": AnyField}},
 				{"any skips validation with in", "x in ['a']", map[string]FieldType{"x": AnyField}},
+				{"numeric > numeric field", "memory > disk", map[string]FieldType{"memory": NumericField, "disk": NumericField}},
+				{"string = string field", "host = cluster", map[string]FieldType{"host": StringField, "cluster": StringField}},
+				{"any = typed field", "x = cpus", map[string]FieldType{"x": AnyField, "cpus": NumericField}},
+				{"date < day", "created < '2024-01-01'", map[string]FieldType{"created": DateField}},
+				{"date >= timestamp", "created >= '2024-01-01T08:30:00'", map[string]FieldType{"created": DateField}},
+				{"date = timestamp with zone", "created = '2024-01-01T08:30:00+02:00'", map[string]FieldType{"created": DateField}},
+				{"date > date field", "created > updated", map[string]FieldType{"created": DateField, "updated": DateField}},
 			}
 
 			for _, test := range tests {
@@ -1090,6 +1223,16 @@ var _ = Describe("SQL Generation", func() {
 				{"boolean field with regex", "active ~ /true/", map[string]FieldType{"active": BooleanField}, `field "active" is boolean, but got regex value`},
 				{"numeric field with in", "cpus in ['1']", map[string]FieldType{"cpus": NumericField}, `field "cpus" is numeric, but in/not in requires a string field`},
 				{"boolean field with not in", "active not in ['x']", map[string]FieldType{"active": BooleanField}, `field "active" is boolean, but in/not in requires a string field`},
+				{"numeric field with string field", "memory > name", map[string]FieldType{"memory": NumericField, "name": StringField}, `field "memory" is numeric, but field "name" is string`},
+				{"boolean field with numeric field", "active = cpus", map[string]FieldType{"active": BooleanField, "cpus": NumericField}, `field "active" is boolean, but field "cpus" is numeric`},
+				{"unknown field on the right", "memory > secret", map[string]FieldType{"memory": NumericField}, `unknown field: secret`},
+				{"date field with a non-date string", "created < 'last year'", map[string]FieldType{"created": DateField}, `field "created" is date, but got string value`},
+				{"date field with numeric", "created > 2024", map[string]FieldType{"created": DateField}, `field "created" is date, but got numeric value`},
+				{"date field with regex", "created ~ /2024/", map[string]FieldType{"created": DateField}, `field "created" is date, but got regex value`},
+				{"date field with like", "created like '2024-01-01'", map[string]FieldType{"created": DateField}, `field "created" is date, but LIKE only applies to string fields`},
+				{"numeric field with contains", "cpus contains '4'", map[string]FieldType{"cpus": NumericField}, `field "cpus" is numeric, but got string value`},
+				{"date field with not contains", "created not contains '2024-01-01'", map[string]FieldType{"created": DateField}, `field "created" is date, but NOT LIKE only applies to string fields`},
+				{"date field with string field", "created > name", map[string]FieldType{"created": DateField, "name": StringField}, `field "created" is date, but field "name" is string`},
 			}
 
 			for _, test := range tests {
@@ -1173,6 +1316,56 @@ var _ = Describe("SQL Generation", func() {
 				_, err := ParseWithDefaultMap([]byte("memory > 8GB and template = true and name = 'test'"))
 				Expect(err).ToNot(HaveOccurred())
 			})
+
+			It("should compare created with a date as a timestamp", func() {
+				sqlizer, err := ParseWithDefaultMap([]byte("created < '2024-01-01'"))
+				Expect(err).ToNot(HaveOccurred())
+				sql, args, err := sqlizer.ToSql()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sql).To(Equal(`(vcr."Created" < ?)`))
+				Expect(args).To(Equal([]any{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}))
+			})
+
+			It("should convert a zoned timestamp to UTC", func() {
+				sqlizer, err := ParseWithDefaultMap([]byte("created >= '2024-01-01T08:30:00+02:00'"))
+				Expect(err).ToNot(HaveOccurred())
+				_, args, err := sqlizer.ToSql()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(args).To(Equal([]any{time.Date(2024, 1, 1, 6, 30, 0, 0, time.UTC)}))
+			})
+
+			It("should compare two known columns", func() {
+				sqlizer, err := ParseWithDefaultMap([]byte("storage_used > provisioned"))
+				Expect(err).ToNot(HaveOccurred())
+				sql, args, err := sqlizer.ToSql()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sql).To(Equal(`(v."In Use MiB" > v."Provisioned MiB")`))
+				Expect(args).To(BeEmpty())
+			})
+
+			It("should reject an unknown column on the right side", func() {
+				_, err := ParseWithDefaultMap([]byte("memory > secret_column"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unknown filter field: secret_column"))
+			})
+
+			It("should reject a quoted unknown column on the right side", func() {
+				_, err := ParseWithDefaultMap([]byte("memory > `Secret Column`"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unknown filter field: `Secret Column`"))
+			})
+
+			It("should reject a quoted unknown column on the left side", func() {
+				_, err := ParseWithDefaultMap([]byte("`Secret Column` > memory"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unknown filter field: `Secret Column`"))
+			})
+
+			It("should check the types of quoted columns", func() {
+				_, err := ParseWithDefaultMap([]byte("`DNS Name` > `Memory`"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("is string, but field"))
+			})
 		})
 	})
 })
@@ -28,6 +28,10 @@ const (
 	lSquareBracket
 	rSquareBracket
 	like2
+	iLike
+	notILike
+	contains
+	notContains
 )
 
 var tokenNames = map[Token]string{
@@ -56,6 +60,10 @@ var tokenNames = map[Token]string{
 	lSquareBracket: "[",
 	rSquareBracket: "]",
 	like2:          "like2",
+	iLike:          "iLike",
+	notILike:       "notILike",
+	contains:       "contains",
+	notContains:    "notContains",
 }
 
 func (t Token) String() string {
@@ -63,18 +71,22 @@ func (t Token) String() string {
 }
 
 var tokenSql = map[Token]string{
-	and:      "AND",
-	or:       "OR",
-	in:       "IN",
-	equal:    "=",
-	gte:      ">=",
-	greater:  ">",
-	lte:      "<=",
-	less:     "<",
-	notEqual: "!=",
-	like:     "",    // translated to regexp_matches(...)
-	notLike:  "NOT", // translated to NOT regexp_matches(...)
-	like2:    "LIKE",
+	and:         "AND",
+	or:          "OR",
+	in:          "IN",
+	equal:       "=",
+	gte:         ">=",
+	greater:     ">",
+	lte:         "<=",
+	less:        "<",
+	notEqual:    "!=",
+	like:        "",    // translated to regexp_matches(...)
+	notLike:     "NOT", // translated to NOT regexp_matches(...)
+	like2:       "LIKE",
+	iLike:       "",         // translated to regexp_matches(..., 'i')
+	notILike:    "NOT",      // translated to NOT regexp_matches(..., 'i')
+	contains:    "LIKE",     // value escaped and wrapped in %...%
+	notContains: "NOT LIKE", // value escaped and wrapped in %...%
 }
 
 func (t Token) Sql() string {
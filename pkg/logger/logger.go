@@ -5,30 +5,24 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// level is shared by every logger built by Init, so changing it through Level
+// takes effect on the running process.
+var level = zap.NewAtomicLevel()
+
 // Init initializes and configures a zap logger based on the provided configuration.
 // It sets up the appropriate log level and format according to the config settings.
 func Init(format string, logLevel string) *zap.Logger {
 	lvl := zapcore.InfoLevel
-	level, err := zapcore.ParseLevel(logLevel)
+	parsed, err := zapcore.ParseLevel(logLevel)
 	if err == nil {
-		lvl = level
+		lvl = parsed
 	}
+	level.SetLevel(lvl)
 
 	loggerCfg := &zap.Config{
-		Level:    zap.NewAtomicLevelAt(lvl),
-		Encoding: format,
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        "time",
-			LevelKey:       "severity",
-			NameKey:        "logger",
-			CallerKey:      "caller",
-			MessageKey:     "message",
-			StacktraceKey:  "stacktrace",
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeTime:     zapcore.RFC3339TimeEncoder,
-			EncodeLevel:    zapcore.LowercaseLevelEncoder,
-			EncodeDuration: zapcore.MillisDurationEncoder, EncodeCaller: zapcore.ShortCallerEncoder,
-		},
+		Level:            level,
+		Encoding:         format,
+		EncoderConfig:    encoderConfig(),
 		OutputPaths:      []string{"stdout"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
@@ -40,3 +34,24 @@ func Init(format string, logLevel string) *zap.Logger {
 
 	return plain
 }
+
+// Level returns the level of the loggers built by Init. Calling SetLevel on it
+// changes their verbosity at runtime.
+func Level() zap.AtomicLevel {
+	return level
+}
+
+func encoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "severity",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.RFC3339TimeEncoder,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeDuration: zapcore.MillisDurationEncoder, EncodeCaller: zapcore.ShortCallerEncoder,
+	}
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Ring keeps the last lines logged by a set of named loggers in memory, so
+// they can be served without access to the process output. Use Core to attach
+// it to a logger, usually next to the regular output with zapcore.NewTee.
+type Ring struct {
+	mu    sync.Mutex
+	lines []string
+	next  int // index of the oldest line once the ring is full
+	size  int
+	names []string
+}
+
+// NewRing returns a Ring holding up to size lines from the loggers named in
+// names or nested under them (e.g. "inventory" also takes "inventory.parser").
+func NewRing(size int, names ...string) *Ring {
+	return &Ring{
+		lines: make([]string, 0, size),
+		size:  size,
+		names: names,
+	}
+}
+
+// Lines returns up to n of the most recent lines, oldest first.
+func (r *Ring) Lines(n int) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ordered := append(slices.Clone(r.lines[r.next:]), r.lines[:r.next]...)
+	if n < len(ordered) {
+		ordered = ordered[len(ordered)-n:]
+	}
+	return ordered
+}
+
+// Core returns a core writing the entries of the ring's loggers at info level
+// and above to the ring, in console format.
+func (r *Ring) Core() zapcore.Core {
+	return &ringCore{
+		ring:    r,
+		encoder: zapcore.NewConsoleEncoder(encoderConfig()),
+	}
+}
+
+func (r *Ring) accepts(name string) bool {
+	for _, n := range r.names {
+		if name == n || strings.HasPrefix(name, n+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Ring) add(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size <= 0 {
+		return
+	}
+	if len(r.lines) < r.size {
+		r.lines = append(r.lines, line)
+		return
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % r.size
+}
+
+type ringCore struct {
+	ring    *Ring
+	encoder zapcore.Encoder
+}
+
+func (c *ringCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.InfoLevel
+}
+
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, f := range fields {
+		f.AddTo(encoder)
+	}
+	return &ringCore{ring: c.ring, encoder: encoder}
+}
+
+func (c *ringCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) && c.ring.accepts(entry.LoggerName) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *ringCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	c.ring.add(strings.TrimSuffix(buf.String(), "\n"))
+	return nil
+}
+
+func (c *ringCore) Sync() error {
+	return nil
+}
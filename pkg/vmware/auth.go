@@ -159,9 +159,9 @@ func VerifyCredentials(ctx context.Context, creds *models.Credentials, resourceN
 	verifyCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	vimClient, err := vim25.NewClient(verifyCtx, soap.NewClient(u, true))
+	vimClient, err := vim25.NewClient(verifyCtx, soap.NewClient(u, creds.Insecure))
 	if err != nil {
-		return err
+		return srvErrors.NewVCenterError(err)
 	}
 
 	client := &govmomi.Client{
@@ -182,3 +182,59 @@ func VerifyCredentials(ctx context.Context, creds *models.Credentials, resourceN
 	zap.S().Named(resourceName).Info("vCenter credentials verified successfully")
 	return nil
 }
+
+// TestConnection logs in to vCenter, reads its product information and logs
+// out. It returns a ValidationError for a malformed URL, a CredentialsError
+// when vCenter rejects the login and a VCenterError when vCenter cannot be
+// reached or fails otherwise. The vCenter certificate is verified unless
+// creds.Insecure is set.
+func TestConnection(ctx context.Context, creds *models.Credentials) (*models.VCenterInfo, error) {
+	u, err := url.ParseRequestURI(creds.URL)
+	if err != nil {
+		return nil, srvErrors.NewValidationError(fmt.Sprintf("invalid vCenter URL: %v", err))
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/sdk"
+	}
+	u.User = url.UserPassword(creds.Username, creds.Password)
+
+	testCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	vimClient, err := vim25.NewClient(testCtx, soap.NewClient(u, creds.Insecure))
+	if err != nil {
+		return nil, srvErrors.NewVCenterError(err)
+	}
+	defer vimClient.CloseIdleConnections()
+
+	sm := session.NewManager(vimClient)
+	if err := sm.Login(testCtx, u.User); err != nil {
+		if isInvalidLogin(err) {
+			return nil, srvErrors.NewCredentialsError()
+		}
+		return nil, srvErrors.NewVCenterError(err)
+	}
+	defer func() {
+		logoutCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = sm.Logout(logoutCtx)
+	}()
+
+	about := vimClient.ServiceContent.About
+	return &models.VCenterInfo{
+		Product:    about.FullName,
+		Version:    about.Version,
+		Build:      about.Build,
+		APIVersion: about.ApiVersion,
+	}, nil
+}
+
+// isInvalidLogin reports whether err is vCenter rejecting the username or
+// password rather than a transport or server failure.
+func isInvalidLogin(err error) bool {
+	if !soap.IsSoapFault(err) {
+		return false
+	}
+	_, ok := soap.ToSoapFault(err).VimFault().(types.InvalidLogin)
+	return ok
+}
@@ -0,0 +1,232 @@
+package vmware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+const (
+	sessionLoginTimeout  = 30 * time.Second
+	sessionCheckTimeout  = 10 * time.Second
+	sessionLogoutTimeout = 10 * time.Second
+)
+
+// SessionCache shares logged-in vCenter sessions between operations that run
+// back to back (collection, inspection), keyed by vCenter URL and username.
+//
+// A session stays cached for ttl after its last user releases it and is then
+// logged out. A cached session is only handed out when it was opened with the
+// same password, is still active on vCenter, and was not opened with insecure
+// TLS for a caller that verifies the certificate; otherwise it is evicted and
+// a new one logged in. Logins and session checks run without the cache lock,
+// so a slow vCenter does not hold up callers for another one.
+//
+// A nil *SessionCache, or one with a non-positive ttl, caches nothing: every
+// Acquire logs in and its release logs out.
+type SessionCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*cachedSession
+}
+
+type cachedSession struct {
+	client   *govmomi.Client
+	password string
+	insecure bool
+	refs     int
+	timer    *time.Timer
+}
+
+// NewSessionCache returns an empty cache keeping idle sessions for ttl.
+func NewSessionCache(ttl time.Duration) *SessionCache {
+	return &SessionCache{
+		ttl:      ttl,
+		sessions: make(map[string]*cachedSession),
+	}
+}
+
+// Acquire returns a logged-in client for creds, reusing a cached session when
+// possible. The caller must call release once done with the client and must
+// not use the client afterwards. Login errors are returned as from
+// NewVsphereClient.
+func (s *SessionCache) Acquire(ctx context.Context, creds models.Credentials) (*govmomi.Client, func(), error) {
+	if s == nil {
+		client, err := login(ctx, creds)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, func() { logout(client) }, nil
+	}
+
+	key := sessionKey(creds)
+	if e := s.pin(key); e != nil {
+		// The session check and the login below talk to vCenter, so they run
+		// without the lock; the pin keeps e from being logged out meanwhile.
+		if e.reusable(ctx, creds) {
+			return e.client, s.releaser(key, e), nil
+		}
+		s.unpinStale(key, e)
+	}
+
+	client, err := login(ctx, creds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e := &cachedSession{
+		client:   client,
+		password: creds.Password,
+		insecure: creds.Insecure,
+		refs:     1,
+	}
+	if s.ttl <= 0 {
+		return client, s.releaser(key, e), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Another Acquire may have logged in for the same key meanwhile. Keep its
+	// session when it fits creds and drop ours, so only one stays cached.
+	if cur, ok := s.sessions[key]; ok {
+		if cur.password == creds.Password && (!cur.insecure || creds.Insecure) {
+			cur.refs++
+			cur.stopTimer()
+			go logout(client)
+			return cur.client, s.releaser(key, cur), nil
+		}
+		s.evictLocked(key, cur)
+	}
+	s.sessions[key] = e
+	return client, s.releaser(key, e), nil
+}
+
+// pin returns the session cached for key, if any, with one more reference and
+// its expiry stopped.
+func (s *SessionCache) pin(key string) *cachedSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.sessions[key]
+	if !ok {
+		return nil
+	}
+	e.refs++
+	e.stopTimer()
+	return e
+}
+
+// unpinStale drops the reference taken by pin on a session found unusable and
+// evicts it, unless another caller already replaced it.
+func (s *SessionCache) unpinStale(key string, e *cachedSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.refs--
+	if s.sessions[key] == e {
+		s.evictLocked(key, e)
+		return
+	}
+	if e.refs == 0 {
+		go logout(e.client)
+	}
+}
+
+// Close logs out every idle session. Sessions still in use are logged out
+// when released.
+func (s *SessionCache) Close() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, e := range s.sessions {
+		s.evictLocked(key, e)
+	}
+}
+
+func (s *SessionCache) releaser(key string, e *cachedSession) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { s.release(key, e) })
+	}
+}
+
+func (s *SessionCache) release(key string, e *cachedSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.refs--
+	if e.refs > 0 {
+		return
+	}
+
+	// Evicted while in use, or never cached.
+	if s.sessions[key] != e {
+		go logout(e.client)
+		return
+	}
+
+	e.timer = time.AfterFunc(s.ttl, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.sessions[key] == e && e.refs == 0 {
+			s.evictLocked(key, e)
+		}
+	})
+}
+
+// evictLocked drops e from the cache and logs it out unless it is still in
+// use, in which case the last release logs it out.
+func (s *SessionCache) evictLocked(key string, e *cachedSession) {
+	delete(s.sessions, key)
+	e.stopTimer()
+	if e.refs == 0 {
+		go logout(e.client)
+	}
+}
+
+func (e *cachedSession) stopTimer() {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+}
+
+func (e *cachedSession) reusable(ctx context.Context, creds models.Credentials) bool {
+	if e.password != creds.Password || (e.insecure && !creds.Insecure) {
+		return false
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, sessionCheckTimeout)
+	defer cancel()
+
+	us, err := e.client.SessionManager.UserSession(checkCtx)
+	return err == nil && us != nil
+}
+
+func sessionKey(creds models.Credentials) string {
+	return creds.URL + "\x00" + creds.Username
+}
+
+func login(ctx context.Context, creds models.Credentials) (*govmomi.Client, error) {
+	loginCtx, cancel := context.WithTimeout(ctx, sessionLoginTimeout)
+	defer cancel()
+
+	return NewVsphereClient(loginCtx, creds.URL, creds.Username, creds.Password, creds.Insecure)
+}
+
+func logout(client *govmomi.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionLogoutTimeout)
+	defer cancel()
+
+	_ = client.Logout(ctx)
+	client.CloseIdleConnections()
+}
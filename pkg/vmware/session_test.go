@@ -0,0 +1,253 @@
+package vmware
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/mo"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+// newSessionTestServer starts a vcsim accepting user/pass and returns the
+// credentials for it together with a counter of its active sessions. The
+// counter logs in a session of its own, which it leaves out of the count.
+func newSessionTestServer(t *testing.T) (models.Credentials, func() int) {
+	t.Helper()
+
+	model := simulator.VPX()
+	if err := model.Create(); err != nil {
+		t.Fatalf("create vcsim model: %v", err)
+	}
+	model.Service.Listen = &url.URL{User: url.UserPassword("user", "pass")}
+	server := model.Service.NewServer()
+	t.Cleanup(model.Remove)
+	t.Cleanup(server.Close)
+
+	creds := models.Credentials{
+		URL:      server.URL.Scheme + "://" + server.URL.Host + "/sdk",
+		Username: "user",
+		Password: "pass",
+		Insecure: true,
+	}
+
+	observer, err := login(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("observer login: %v", err)
+	}
+	t.Cleanup(func() { logout(observer) })
+
+	count := func() int {
+		var sm mo.SessionManager
+		pc := property.DefaultCollector(observer.Client)
+		if err := pc.RetrieveOne(context.Background(), *observer.ServiceContent.SessionManager, []string{"sessionList"}, &sm); err != nil {
+			t.Fatalf("retrieve session list: %v", err)
+		}
+		return len(sm.SessionList) - 1
+	}
+
+	return creds, count
+}
+
+func waitForSessions(t *testing.T, count func() int, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for count() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d active sessions, want %d", count(), want)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestSessionCacheReusesSessionWithinTTL(t *testing.T) {
+	creds, sessions := newSessionTestServer(t)
+	cache := NewSessionCache(time.Minute)
+	defer cache.Close()
+
+	first, release, err := cache.Acquire(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+	release()
+
+	second, release, err := cache.Acquire(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("second acquire: %v", err)
+	}
+	defer release()
+
+	if first != second {
+		t.Error("second acquire within the TTL logged in again")
+	}
+	if got := sessions(); got != 1 {
+		t.Errorf("got %d active sessions, want 1", got)
+	}
+}
+
+func TestSessionCacheLogsOutExpiredSession(t *testing.T) {
+	creds, sessions := newSessionTestServer(t)
+	cache := NewSessionCache(50 * time.Millisecond)
+	defer cache.Close()
+
+	first, release, err := cache.Acquire(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	release()
+
+	waitForSessions(t, sessions, 0)
+
+	second, release, err := cache.Acquire(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("acquire after expiry: %v", err)
+	}
+	defer release()
+
+	if first == second {
+		t.Error("expired session was reused")
+	}
+}
+
+func TestSessionCacheKeepsSessionInUse(t *testing.T) {
+	creds, sessions := newSessionTestServer(t)
+	cache := NewSessionCache(time.Millisecond)
+
+	_, release, err := cache.Acquire(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	cache.Close()
+	if got := sessions(); got != 1 {
+		t.Fatalf("session in use was logged out: got %d active sessions, want 1", got)
+	}
+
+	release()
+	waitForSessions(t, sessions, 0)
+}
+
+func TestSessionCacheDoesNotReuseAcrossCredentials(t *testing.T) {
+	creds, _ := newSessionTestServer(t)
+	cache := NewSessionCache(time.Minute)
+	defer cache.Close()
+
+	first, release, err := cache.Acquire(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	release()
+
+	wrong := creds
+	wrong.Password = "wrong"
+	if _, _, err := cache.Acquire(context.Background(), wrong); err == nil {
+		t.Fatal("acquire with a wrong password reused the cached session")
+	}
+
+	second, release, err := cache.Acquire(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer release()
+	if first == second {
+		t.Error("session evicted for other credentials was reused")
+	}
+}
+
+func TestSessionCacheWithoutTTLLogsOutOnRelease(t *testing.T) {
+	creds, sessions := newSessionTestServer(t)
+
+	for _, cache := range []*SessionCache{nil, NewSessionCache(0)} {
+		_, release, err := cache.Acquire(context.Background(), creds)
+		if err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+		release()
+		waitForSessions(t, sessions, 0)
+	}
+}
+
+func TestSessionCacheLogsInWithoutHoldingTheLock(t *testing.T) {
+	creds, _ := newSessionTestServer(t)
+	cache := NewSessionCache(time.Minute)
+	defer cache.Close()
+
+	// A vCenter that accepts connections and never answers.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+		}
+	}()
+	hanging := creds
+	hanging.URL = "https://" + listener.Addr().String() + "/sdk"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	stuck := make(chan struct{})
+	go func() {
+		defer close(stuck)
+		_, _, _ = cache.Acquire(ctx, hanging)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	_, release, err := cache.Acquire(context.Background(), creds)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("acquire waited %v for another vCenter's login", elapsed)
+	}
+
+	cancel()
+	<-stuck
+}
+
+func TestSessionCacheKeepsOneSessionForConcurrentLogins(t *testing.T) {
+	creds, sessions := newSessionTestServer(t)
+	cache := NewSessionCache(time.Minute)
+
+	var wg sync.WaitGroup
+	releases := make([]func(), 4)
+	for i := range releases {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, release, err := cache.Acquire(context.Background(), creds)
+			if err != nil {
+				t.Errorf("acquire: %v", err)
+				return
+			}
+			releases[i] = release
+		}()
+	}
+	wg.Wait()
+
+	for _, release := range releases {
+		if release != nil {
+			release()
+		}
+	}
+	waitForSessions(t, sessions, 1)
+
+	cache.Close()
+	waitForSessions(t, sessions, 0)
+}
@@ -0,0 +1,63 @@
+package vmware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/kubev2v/assisted-migration-agent/internal/models"
+)
+
+// VMDetails holds the per-VM data the forklift collector does not keep.
+type VMDetails struct {
+	// Snapshots lists every snapshot of every VM. VMs without snapshots
+	// contribute nothing.
+	Snapshots []models.VirtualMachineSnapshot
+	// CreateDates holds the creation date of every VM, keyed by VM ID. vCenter
+	// only records it for VMs created on vSphere 6.7 or later, so older VMs
+	// are missing from the map.
+	CreateDates map[string]time.Time
+}
+
+// ListVMDetails reads the snapshot tree and the creation date of every VM in
+// the vCenter in one walk of a container view.
+func ListVMDetails(ctx context.Context, c *vim25.Client) (*VMDetails, error) {
+	v, err := view.NewManager(c).CreateContainerView(ctx, c.ServiceContent.RootFolder, []string{"VirtualMachine"}, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container view: %w", err)
+	}
+	defer func() { _ = v.Destroy(ctx) }()
+
+	var vms []mo.VirtualMachine
+	if err := v.Retrieve(ctx, []string{"VirtualMachine"}, []string{"snapshot", "config.createDate"}, &vms); err != nil {
+		return nil, fmt.Errorf("failed to retrieve VM snapshots and creation dates: %w", err)
+	}
+
+	details := &VMDetails{CreateDates: make(map[string]time.Time, len(vms))}
+	var walk func(vmID string, trees []types.VirtualMachineSnapshotTree)
+	walk = func(vmID string, trees []types.VirtualMachineSnapshotTree) {
+		for _, t := range trees {
+			details.Snapshots = append(details.Snapshots, models.VirtualMachineSnapshot{
+				VMID:      vmID,
+				Name:      t.Name,
+				CreatedAt: t.CreateTime,
+			})
+			walk(vmID, t.ChildSnapshotList)
+		}
+	}
+	for _, vm := range vms {
+		if vm.Snapshot != nil {
+			walk(vm.Self.Value, vm.Snapshot.RootSnapshotList)
+		}
+		if vm.Config != nil && vm.Config.CreateDate != nil {
+			details.CreateDates[vm.Self.Value] = *vm.Config.CreateDate
+		}
+	}
+
+	return details, nil
+}
@@ -0,0 +1,100 @@
+package vmware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+)
+
+func newVMDetailsTestClient(t *testing.T) *govmomi.Client {
+	t.Helper()
+
+	model := simulator.VPX()
+	if err := model.Create(); err != nil {
+		t.Fatalf("create vcsim model: %v", err)
+	}
+	server := model.Service.NewServer()
+	t.Cleanup(model.Remove)
+	t.Cleanup(server.Close)
+
+	c, err := govmomi.NewClient(context.Background(), server.URL, true)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	return c
+}
+
+func TestListVMDetailsSnapshots(t *testing.T) {
+	ctx := context.Background()
+	c := newVMDetailsTestClient(t)
+
+	vm, err := find.NewFinder(c.Client).VirtualMachine(ctx, "DC0_H0_VM0")
+	if err != nil {
+		t.Fatalf("find vm: %v", err)
+	}
+	for _, name := range []string{"before-upgrade", "after-upgrade"} {
+		task, err := vm.CreateSnapshot(ctx, name, "", false, false)
+		if err != nil {
+			t.Fatalf("create snapshot %s: %v", name, err)
+		}
+		if err := task.Wait(ctx); err != nil {
+			t.Fatalf("wait snapshot %s: %v", name, err)
+		}
+	}
+
+	details, err := ListVMDetails(ctx, c.Client)
+	if err != nil {
+		t.Fatalf("ListVMDetails: %v", err)
+	}
+
+	snapshots := details.Snapshots
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want 2: %+v", len(snapshots), snapshots)
+	}
+	for i, want := range []string{"before-upgrade", "after-upgrade"} {
+		if snapshots[i].VMID != vm.Reference().Value {
+			t.Errorf("snapshot %d: VM ID %q, want %q", i, snapshots[i].VMID, vm.Reference().Value)
+		}
+		if snapshots[i].Name != want {
+			t.Errorf("snapshot %d: name %q, want %q", i, snapshots[i].Name, want)
+		}
+		if snapshots[i].CreatedAt.IsZero() {
+			t.Errorf("snapshot %d: creation time not set", i)
+		}
+	}
+}
+
+func TestListVMDetailsCreateDates(t *testing.T) {
+	ctx := context.Background()
+	c := newVMDetailsTestClient(t)
+
+	vms, err := find.NewFinder(c.Client).VirtualMachineList(ctx, "/...")
+	if err != nil {
+		t.Fatalf("find vms: %v", err)
+	}
+
+	details, err := ListVMDetails(ctx, c.Client)
+	if err != nil {
+		t.Fatalf("ListVMDetails: %v", err)
+	}
+
+	if len(details.CreateDates) != len(vms) {
+		t.Fatalf("got %d creation dates, want %d", len(details.CreateDates), len(vms))
+	}
+	for _, vm := range vms {
+		created, ok := details.CreateDates[vm.Reference().Value]
+		if !ok {
+			t.Errorf("VM %s: creation date missing", vm.Name())
+			continue
+		}
+		if created.IsZero() {
+			t.Errorf("VM %s: creation date not set", vm.Name())
+		}
+	}
+	if len(details.Snapshots) != 0 {
+		t.Errorf("got %d snapshots, want none", len(details.Snapshots))
+	}
+}
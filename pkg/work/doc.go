@@ -80,6 +80,7 @@
 // After Start():
 //   - State() is always valid and returns the current or final status.
 //   - IsRunning() reports whether the goroutine(s) are still active.
+//   - Wait() on Pipeline and Service blocks until the run completes or is stopped.
 //   - Stop() is idempotent and safe to call concurrently.
 //   - After completion or Stop(), result and error persist on the instance.
 //   - For Service and Pool, the instance is never reused. Create a new one for the next run.
@@ -126,6 +126,18 @@ func (p *Pipeline[S, R]) Stop() {
 	}
 }
 
+// Wait blocks until the running pipeline finishes or is stopped. It returns
+// immediately when the pipeline is not running.
+func (p *Pipeline[S, R]) Wait() {
+	p.mu.Lock()
+	done := p.done
+	p.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+}
+
 func (p *Pipeline[S, R]) State() Status[S, R] {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -66,6 +66,18 @@ func (w *Service[S, R]) Stop() {
 	}
 }
 
+// Wait blocks until the pipeline finishes or is stopped. It returns
+// immediately when the service is not running.
+func (w *Service[S, R]) Wait() {
+	w.mu.Lock()
+	p := w.pipeline
+	w.mu.Unlock()
+
+	if p != nil {
+		p.Wait()
+	}
+}
+
 func (w *Service[S, R]) State() Status[S, R] {
 	w.mu.Lock()
 	p := w.pipeline
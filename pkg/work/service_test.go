@@ -88,6 +88,40 @@ var _ = Describe("Service", func() {
 		})
 	})
 
+	Context("Wait", func() {
+		It("should return immediately when not started", func() {
+			srv := work.NewService("pending", wb())
+
+			Expect(func() { srv.Wait() }).NotTo(Panic())
+		})
+
+		It("should block until the pipeline finishes", func() {
+			gate := make(chan struct{})
+			units := []work.WorkUnit[string, int]{
+				unit("blocking", func(_ context.Context, r int) (int, error) {
+					<-gate
+					return r + 1, nil
+				}),
+			}
+
+			srv := work.NewService("pending", wb(units...))
+			Expect(srv.Start()).To(Succeed())
+
+			waited := make(chan struct{})
+			go func() {
+				srv.Wait()
+				close(waited)
+			}()
+			Consistently(waited, 50*time.Millisecond).ShouldNot(BeClosed())
+
+			close(gate)
+
+			Eventually(waited).Should(BeClosed())
+			Expect(srv.IsRunning()).To(BeFalse())
+			Expect(srv.State().Result).To(Equal(1))
+		})
+	})
+
 	Context("State", func() {
 		It("should return initial state before start", func() {
 			units := []work.WorkUnit[string, int]{
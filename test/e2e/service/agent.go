@@ -30,9 +30,10 @@ type AgentStatus struct {
 }
 
 type CollectorStartRequest struct {
-	URL      string `json:"url"`
-	Username string `json:"username"`
-	Password string `json:"password"`
+	URL                string `json:"url"`
+	Username           string `json:"username"`
+	Password           string `json:"password"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
 }
 
 type CollectorStatus struct {
@@ -150,6 +151,8 @@ func (a *AgentSvc) StartCollector(vcenterURL, username, password string) (*Colle
 		URL:      vcenterURL,
 		Username: username,
 		Password: password,
+		// vcsim serves a self-signed certificate.
+		InsecureSkipVerify: true,
 	}
 	data, err := json.Marshal(body)
 	if err != nil {
@@ -34,12 +34,14 @@ type Disk struct {
 	Shared      bool
 	RDM         bool
 	Controller  string
+	Thin        bool
 }
 
 type NIC struct {
 	VMID    string
 	Network string
 	MAC     string
+	Adapter string
 }
 
 type Concern struct {
@@ -64,25 +66,25 @@ var VMs = []VM{
 }
 
 var Disks = []Disk{
-	{"vm-001", 100, "[datastore1] vm-001/disk1.vmdk", "persistent", false, false, "SCSI"},
-	{"vm-002", 100, "[datastore1] vm-002/disk1.vmdk", "persistent", false, false, "SCSI"},
-	{"vm-003", 500, "[datastore1] vm-003/disk1.vmdk", "persistent", false, false, "SCSI"},
-	{"vm-003", 500, "[datastore1] vm-003/disk2.vmdk", "persistent", false, false, "SCSI"},
-	{"vm-004", 1000, "[datastore1] vm-004/disk1.vmdk", "persistent", true, false, "SCSI"},
-	{"vm-005", 200, "[datastore1] vm-005/disk1.vmdk", "persistent", false, false, "SCSI"},
-	{"vm-006", 200, "[datastore1] vm-006/disk1.vmdk", "persistent", false, false, "SCSI"},
-	{"vm-007", 50, "[datastore1] vm-007/disk1.vmdk", "independent_persistent", false, true, "SCSI"},
-	{"vm-008", 150, "[datastore1] vm-008/disk1.vmdk", "persistent", false, false, "NVME"},
-	{"vm-009", 150, "[datastore1] vm-009/disk1.vmdk", "persistent", false, false, "NVME"},
-	{"vm-010", 80, "[datastore1] vm-010/disk1.vmdk", "persistent", false, false, "SCSI"},
+	{"vm-001", 100, "[datastore1] vm-001/disk1.vmdk", "persistent", false, false, "SCSI", true},
+	{"vm-002", 100, "[datastore1] vm-002/disk1.vmdk", "persistent", false, false, "SCSI", false},
+	{"vm-003", 500, "[datastore1] vm-003/disk1.vmdk", "persistent", false, false, "SCSI", true},
+	{"vm-003", 500, "[datastore1] vm-003/disk2.vmdk", "persistent", false, false, "SCSI", false},
+	{"vm-004", 1000, "[datastore1] vm-004/disk1.vmdk", "persistent", true, false, "SCSI", false},
+	{"vm-005", 200, "[datastore1] vm-005/disk1.vmdk", "persistent", false, false, "SCSI", true},
+	{"vm-006", 200, "[datastore1] vm-006/disk1.vmdk", "persistent", false, false, "SCSI", false},
+	{"vm-007", 50, "[datastore1] vm-007/disk1.vmdk", "independent_persistent", false, true, "SCSI", false},
+	{"vm-008", 150, "[datastore1] vm-008/disk1.vmdk", "persistent", false, false, "NVME", false},
+	{"vm-009", 150, "[datastore1] vm-009/disk1.vmdk", "persistent", false, false, "NVME", false},
+	{"vm-010", 80, "[datastore1] vm-010/disk1.vmdk", "persistent", false, false, "SCSI", false},
 }
 
 var NICs = []NIC{
-	{"vm-001", "VM Network", "00:50:56:01:01:01"},
-	{"vm-002", "VM Network", "00:50:56:01:02:01"},
-	{"vm-003", "Production", "00:50:56:01:03:01"},
-	{"vm-003", "Management", "00:50:56:01:03:02"},
-	{"vm-007", "Staging", "00:50:56:01:07:01"},
+	{"vm-001", "VM Network", "00:50:56:01:01:01", "vmxnet3"},
+	{"vm-002", "VM Network", "00:50:56:01:02:01", "e1000"},
+	{"vm-003", "Production", "00:50:56:01:03:01", "vmxnet3"},
+	{"vm-003", "Management", "00:50:56:01:03:02", "e1000e"},
+	{"vm-007", "Staging", "00:50:56:01:07:01", "E1000"},
 }
 
 type Memory struct {
@@ -164,9 +166,9 @@ func InsertVMs(ctx context.Context, db *sql.DB) error {
 
 	for _, disk := range Disks {
 		_, err := db.ExecContext(ctx, `
-			INSERT INTO vdisk ("VM ID", "Capacity MiB", "Path", "Disk Mode", "Sharing mode", "Raw", "Controller")
-			VALUES (?, ?, ?, ?, ?, ?, ?)
-		`, disk.VMID, disk.CapacityMiB, disk.Path, disk.DiskMode, disk.Shared, disk.RDM, disk.Controller)
+			INSERT INTO vdisk ("VM ID", "Capacity MiB", "Path", "Disk Mode", "Sharing mode", "Raw", "Controller", "Thin")
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, disk.VMID, disk.CapacityMiB, disk.Path, disk.DiskMode, disk.Shared, disk.RDM, disk.Controller, disk.Thin)
 		if err != nil {
 			return err
 		}
@@ -174,9 +176,9 @@ func InsertVMs(ctx context.Context, db *sql.DB) error {
 
 	for _, nic := range NICs {
 		_, err := db.ExecContext(ctx, `
-			INSERT INTO vnetwork ("VM ID", "Network", "Mac Address")
-			VALUES (?, ?, ?)
-		`, nic.VMID, nic.Network, nic.MAC)
+			INSERT INTO vnetwork ("VM ID", "Network", "Mac Address", "Adapter")
+			VALUES (?, ?, ?, ?)
+		`, nic.VMID, nic.Network, nic.MAC, nic.Adapter)
 		if err != nil {
 			return err
 		}